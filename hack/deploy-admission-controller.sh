@@ -45,6 +45,8 @@ webhooks:
     - kongconsumers
     - kongplugins
     - kongclusterplugins
+    - tcpingresses
+    - udpingresses
   - apiGroups:
     - ''
     apiVersions:
@@ -53,6 +55,15 @@ webhooks:
     - UPDATE
     resources:
     - secrets
+  - apiGroups:
+    - ''
+    apiVersions:
+    - 'v1'
+    operations:
+    - CREATE
+    - UPDATE
+    resources:
+    - services
   - apiGroups:
     - gateway.networking.k8s.io
     apiVersions:
@@ -63,6 +74,15 @@ webhooks:
     resources:
     - gateways
     - httproutes
+  - apiGroups:
+    - networking.k8s.io
+    apiVersions:
+    - 'v1'
+    operations:
+    - CREATE
+    - UPDATE
+    resources:
+    - ingresses
   clientConfig:
     service:
       namespace: kong
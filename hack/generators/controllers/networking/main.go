@@ -16,14 +16,16 @@ import (
 const (
 	outputFile = "../../internal/controllers/configuration/zz_generated_controllers.go"
 
-	corev1     = "k8s.io/api/core/v1"
-	netv1      = "k8s.io/api/networking/v1"
-	netv1beta1 = "k8s.io/api/networking/v1beta1"
-	extv1beta1 = "k8s.io/api/extensions/v1beta1"
+	corev1      = "k8s.io/api/core/v1"
+	discoveryv1 = "k8s.io/api/discovery/v1"
+	netv1       = "k8s.io/api/networking/v1"
+	netv1beta1  = "k8s.io/api/networking/v1beta1"
+	extv1beta1  = "k8s.io/api/extensions/v1beta1"
 
 	kongv1          = "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
 	kongv1beta1     = "github.com/kong/kubernetes-ingress-controller/v2/api/configuration/v1beta1"
 	knativev1alpha1 = "knative.dev/networking/pkg/apis/networking/v1alpha1"
+	mcsv1alpha1     = "github.com/kong/kubernetes-ingress-controller/v2/internal/apis/multicluster/v1alpha1"
 )
 
 // inputControllersNeeded is a list of the supported Types for the
@@ -59,6 +61,20 @@ var inputControllersNeeded = &typesNeeded{
 		AcceptsIngressClassNameSpec:       false,
 		RBACVerbs:                         []string{"list", "watch"},
 	},
+	typeNeeded{
+		Group:                             "discovery.k8s.io",
+		Version:                           "v1",
+		Kind:                              "EndpointSlice",
+		PackageImportAlias:                "discoveryv1",
+		PackageAlias:                      "DiscoveryV1",
+		Package:                           discoveryv1,
+		Plural:                            "endpointslices",
+		CacheType:                         "EndpointSlice",
+		NeedsStatusPermissions:            false,
+		AcceptsIngressClassNameAnnotation: false,
+		AcceptsIngressClassNameSpec:       false,
+		RBACVerbs:                         []string{"list", "watch"},
+	},
 	typeNeeded{
 		Group:                             "\"\"",
 		Version:                           "v1",
@@ -218,6 +234,76 @@ var inputControllersNeeded = &typesNeeded{
 		AcceptsIngressClassNameSpec:       false,
 		RBACVerbs:                         []string{"get", "list", "watch"},
 	},
+	typeNeeded{
+		Group:                             "configuration.konghq.com",
+		Version:                           "v1beta1",
+		Kind:                              "KongCACertificate",
+		PackageImportAlias:                "kongv1beta1",
+		PackageAlias:                      "KongV1Beta1",
+		Package:                           kongv1beta1,
+		Plural:                            "kongcacertificates",
+		CacheType:                         "KongCACertificate",
+		NeedsStatusPermissions:            true,
+		AcceptsIngressClassNameAnnotation: true,
+		AcceptsIngressClassNameSpec:       false,
+		RBACVerbs:                         []string{"get", "list", "watch"},
+	},
+	typeNeeded{
+		Group:                             "configuration.konghq.com",
+		Version:                           "v1beta1",
+		Kind:                              "KongUpstreamPolicy",
+		PackageImportAlias:                "kongv1beta1",
+		PackageAlias:                      "KongV1Beta1",
+		Package:                           kongv1beta1,
+		Plural:                            "kongupstreampolicies",
+		CacheType:                         "KongUpstreamPolicy",
+		NeedsStatusPermissions:            false,
+		AcceptsIngressClassNameAnnotation: false,
+		AcceptsIngressClassNameSpec:       false,
+		RBACVerbs:                         []string{"get", "list", "watch"},
+	},
+	typeNeeded{
+		Group:                             "configuration.konghq.com",
+		Version:                           "v1beta1",
+		Kind:                              "IngressClassParameters",
+		PackageImportAlias:                "kongv1beta1",
+		PackageAlias:                      "KongV1Beta1",
+		Package:                           kongv1beta1,
+		Plural:                            "ingressclassparameterses",
+		CacheType:                         "IngressClassParametersV1beta1",
+		NeedsStatusPermissions:            false,
+		AcceptsIngressClassNameAnnotation: false,
+		AcceptsIngressClassNameSpec:       false,
+		RBACVerbs:                         []string{"get", "list", "watch"},
+	},
+	typeNeeded{
+		Group:                             "configuration.konghq.com",
+		Version:                           "v1beta1",
+		Kind:                              "KongPluginBundle",
+		PackageImportAlias:                "kongv1beta1",
+		PackageAlias:                      "KongV1Beta1",
+		Package:                           kongv1beta1,
+		Plural:                            "kongpluginbundles",
+		CacheType:                         "KongPluginBundle",
+		NeedsStatusPermissions:            false,
+		AcceptsIngressClassNameAnnotation: false,
+		AcceptsIngressClassNameSpec:       false,
+		RBACVerbs:                         []string{"get", "list", "watch"},
+	},
+	typeNeeded{
+		Group:                             "configuration.konghq.com",
+		Version:                           "v1beta1",
+		Kind:                              "KongVault",
+		PackageImportAlias:                "kongv1beta1",
+		PackageAlias:                      "KongV1Beta1",
+		Package:                           kongv1beta1,
+		Plural:                            "kongvaults",
+		CacheType:                         "KongVault",
+		NeedsStatusPermissions:            false,
+		AcceptsIngressClassNameAnnotation: true,
+		AcceptsIngressClassNameSpec:       false,
+		RBACVerbs:                         []string{"get", "list", "watch"},
+	},
 	typeNeeded{
 		Group:                             "networking.internal.knative.dev",
 		Version:                           "v1alpha1",
@@ -233,6 +319,20 @@ var inputControllersNeeded = &typesNeeded{
 		AcceptsIngressClassNameSpec:       false,
 		RBACVerbs:                         []string{"get", "list", "watch"},
 	},
+	typeNeeded{
+		Group:                             "multicluster.x-k8s.io",
+		Version:                           "v1alpha1",
+		Kind:                              "ServiceImport",
+		PackageImportAlias:                "mcsv1alpha1",
+		PackageAlias:                      "MCSV1Alpha1",
+		Package:                           mcsv1alpha1,
+		Plural:                            "serviceimports",
+		CacheType:                         "ServiceImport",
+		NeedsStatusPermissions:            false,
+		AcceptsIngressClassNameAnnotation: false,
+		AcceptsIngressClassNameSpec:       false,
+		RBACVerbs:                         []string{"get", "list", "watch"},
+	},
 }
 
 var inputRBACPermissionsNeeded = &rbacsNeeded{
@@ -395,7 +495,9 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	extv1beta1 "k8s.io/api/extensions/v1beta1"
 	netv1 "k8s.io/api/networking/v1"
 	netv1beta1 "k8s.io/api/networking/v1beta1"
@@ -404,7 +506,6 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	knativev1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
-	knativeApis "knative.dev/pkg/apis"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -413,8 +514,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	mcsv1alpha1 "github.com/kong/kubernetes-ingress-controller/v2/internal/apis/multicluster/v1alpha1"
 	ctrlutils "github.com/kong/kubernetes-ingress-controller/v2/internal/controllers/utils"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/tracing"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util/kubernetes/object/status"
 	kongv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
@@ -532,9 +635,15 @@ func (r *{{.PackageAlias}}{{.Kind}}Reconciler) listClassless(obj client.Object)
 {{- end}}
 
 // Reconcile processes the watched objects
-func (r *{{.PackageAlias}}{{.Kind}}Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *{{.PackageAlias}}{{.Kind}}Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	log := r.Log.WithValues("{{.PackageAlias}}{{.Kind}}", req.NamespacedName)
 
+	// trace the reconciliation, and link it to the configuration update applying it
+	ctx, span := tracing.Tracer().Start(ctx, "Reconcile {{.PackageAlias}}{{.Kind}}",
+		trace.WithAttributes(tracing.ObjectAttributes("{{.Kind}}", req.NamespacedName)...))
+	defer func() { tracing.EndSpan(span, err) }()
+	r.DataplaneClient.TraceChange(ctx)
+
 	// get the relevant object
 	obj := new({{.PackageImportAlias}}.{{.Kind}})
 	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
@@ -599,29 +708,17 @@ func (r *{{.PackageAlias}}{{.Kind}}Reconciler) Reconcile(ctx context.Context, re
 
 		log.V(util.DebugLevel).Info("found addresses for data-plane updating object status", "namespace", req.Namespace, "name", req.Name)
 {{- if eq .Group "networking.internal.knative.dev"}}
-		var knativeLBIngress []knativev1alpha1.LoadBalancerIngressStatus
-		for _, addr := range addrs {
-			knativeIng := knativev1alpha1.LoadBalancerIngressStatus{
-				IP:     addr.IP,
-				Domain: addr.Hostname,
-			}
-			knativeLBIngress = append(knativeLBIngress, knativeIng)
-		}
-		ingressCondSet := knativeApis.NewLivingConditionSet()
-		if obj.Status.PublicLoadBalancer == nil || len(obj.Status.PublicLoadBalancer.Ingress) != len(addrs) || !reflect.DeepEqual(obj.Status.PublicLoadBalancer.Ingress, knativeLBIngress) {
-			obj.Status.MarkLoadBalancerReady(knativeLBIngress, knativeLBIngress)
-			ingressCondSet.Manage(&obj.Status).MarkTrue(knativev1alpha1.IngressConditionReady)
-			ingressCondSet.Manage(&obj.Status).MarkTrue(knativev1alpha1.IngressConditionNetworkConfigured)
-			obj.Status.ObservedGeneration = obj.Generation
+		return r.updateKnativeIngressStatus(ctx, log, obj, addrs)
+	}
 {{- else}}
 		if len(obj.Status.LoadBalancer.Ingress) != len(addrs) || !reflect.DeepEqual(obj.Status.LoadBalancer.Ingress, addrs) {
 			obj.Status.LoadBalancer.Ingress = addrs
-{{- end}}
-			return ctrl.Result{}, r.Status().Update(ctx, obj)
+			return ctrlutils.StatusUpdateResult(log, r.Status().Update(ctx, obj))
 		} else {
 			log.V(util.DebugLevel).Info("status update not needed", "namespace", req.Namespace, "name", req.Name)
 		}
 	}
+{{- end}}
 {{- end}}
 
 	return ctrl.Result{}, nil
@@ -0,0 +1,25 @@
+package adminapi
+
+import "sync"
+
+// AdminToken is the Kong Enterprise RBAC token sent with every Admin API
+// call, which can be replaced while HTTP clients use it, e.g. when the Secret
+// it's read from rotates. The zero value holds no token.
+type AdminToken struct {
+	lock  sync.RWMutex
+	token string
+}
+
+// Set replaces the token.
+func (t *AdminToken) Set(token string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.token = token
+}
+
+// Get provides the current token.
+func (t *AdminToken) Get() string {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return t.token
+}
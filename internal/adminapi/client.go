@@ -26,6 +26,8 @@ type HTTPClientOpts struct {
 	CACert string
 	// Array of headers added to every Admin API call.
 	Headers []string
+	// Kong Enterprise RBAC token added to every Admin API call, which can be updated while in use.
+	AdminToken *AdminToken
 	// mTLS client certificate file for authentication.
 	TLSClientCertPath string
 	// mTLS client key file for authentication.
@@ -34,6 +36,8 @@ type HTTPClientOpts struct {
 	TLSClientKeyPath string
 	// mTLS client key for authentication.
 	TLSClientKey string
+	// mTLS client certificate and CA certificates which can be updated while in use.
+	TLSCredentials *TLSCredentials
 }
 
 // MakeHTTPClient returns an HTTP client with the specified mTLS/headers configuration.
@@ -133,11 +137,26 @@ func MakeHTTPClient(opts *HTTPClientOpts) (*http.Client, error) {
 		tlsConfig.Certificates = []tls.Certificate{cert}
 	}
 
+	if opts.TLSCredentials != nil {
+		if len(tlsConfig.Certificates) != 0 {
+			return nil, fmt.Errorf("both --kong-admin-tls-secret and a client certificate are set; " +
+				"please remove one or the other")
+		}
+		tlsConfig.GetClientCertificate = opts.TLSCredentials.getClientCertificate
+		if !opts.TLSSkipVerify {
+			// verify the certificate of Kong against the CA certificates current
+			// when connecting rather than against fixed RootCAs.
+			tlsConfig.InsecureSkipVerify = true
+			tlsConfig.VerifyConnection = opts.TLSCredentials.verifyConnection(tlsConfig.RootCAs)
+		}
+	}
+
 	transport := http.DefaultTransport.(*http.Transport).Clone()
 	transport.TLSClientConfig = &tlsConfig
 	return &http.Client{
 		Transport: &HeaderRoundTripper{
 			headers: opts.Headers,
+			token:   opts.AdminToken,
 			rt:      transport,
 		},
 	}, nil
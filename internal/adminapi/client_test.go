@@ -190,7 +190,7 @@ func buildTLS(t *testing.T) (caPEM *bytes.Buffer, certPEM *bytes.Buffer, certPri
 		return nil, nil, nil, err
 	}
 
-	certBytes, err := x509.CreateCertificate(rand.Reader, cert, ca, &certPrivateKey.PublicKey, certPrivateKey)
+	certBytes, err := x509.CreateCertificate(rand.Reader, cert, ca, &certPrivateKey.PublicKey, caPrivateKey)
 	if err != nil {
 		t.Errorf("Fail to generate ingress certificate %s", err.Error())
 		return nil, nil, nil, err
@@ -272,3 +272,27 @@ func validate(t *testing.T,
 
 	return nil
 }
+
+func TestMakeHTTPClientWithAdminToken(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get("Kong-Admin-Token"))
+	}))
+	defer server.Close()
+
+	token := &AdminToken{}
+	httpclient, err := MakeHTTPClient(&HTTPClientOpts{AdminToken: token})
+	require.NoError(t, err)
+	get := func() {
+		response, err := httpclient.Get(server.URL)
+		require.NoError(t, err)
+		require.NoError(t, response.Body.Close())
+	}
+
+	get()
+	token.Set("initial-token")
+	get()
+	token.Set("rotated-token")
+	get()
+	assert.Equal(t, []string{"", "initial-token", "rotated-token"}, received)
+}
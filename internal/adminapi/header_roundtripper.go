@@ -5,10 +5,11 @@ import (
 	"strings"
 )
 
-// HeaderRoundTripper injects Headers into requests
-// made via RT.
+// HeaderRoundTripper injects Headers, and the current admin token if any,
+// into requests made via RT.
 type HeaderRoundTripper struct {
 	headers []string
+	token   *AdminToken
 	rt      http.RoundTripper
 }
 
@@ -26,5 +27,10 @@ func (t *HeaderRoundTripper) RoundTrip(req *http.Request) (*http.Response, error
 			newRequest.Header[split[0]] = append([]string(nil), split[1])
 		}
 	}
+	if t.token != nil {
+		if token := t.token.Get(); token != "" {
+			newRequest.Header.Set("Kong-Admin-Token", token)
+		}
+	}
 	return t.rt.RoundTrip(newRequest)
 }
@@ -0,0 +1,83 @@
+package adminapi
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"sync"
+)
+
+// TLSCredentials are the mTLS client certificate and the CA certificates used
+// with the Kong Admin API which can be replaced while HTTP clients use them,
+// e.g. when the Secret they are loaded from rotates. Connections established
+// after an update use the new credentials. The zero value holds none.
+type TLSCredentials struct {
+	lock       sync.RWMutex
+	clientCert *tls.Certificate
+	caCerts    *x509.CertPool
+}
+
+// Update replaces the credentials with the given PEM-encoded client
+// certificate and key and CA certificates, either of which may be empty. The
+// credentials are left unchanged if any of them is invalid.
+func (c *TLSCredentials) Update(clientCert, clientKey, caCert []byte) error {
+	var cert *tls.Certificate
+	if len(clientCert) != 0 || len(clientKey) != 0 {
+		pair, err := tls.X509KeyPair(clientCert, clientKey)
+		if err != nil {
+			return fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		cert = &pair
+	}
+	var caCerts *x509.CertPool
+	if len(caCert) != 0 {
+		caCerts = x509.NewCertPool()
+		if !caCerts.AppendCertsFromPEM(caCert) {
+			return fmt.Errorf("failed to load CA certificate")
+		}
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.clientCert = cert
+	c.caCerts = caCerts
+	return nil
+}
+
+// getClientCertificate provides the current client certificate, or none.
+func (c *TLSCredentials) getClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.clientCert == nil {
+		return &tls.Certificate{}, nil
+	}
+	return c.clientCert, nil
+}
+
+// verifyConnection returns a function verifying the certificate of the Kong
+// Admin API against the current CA certificates, or against the given roots
+// (the system ones if nil) when the credentials include none.
+func (c *TLSCredentials) verifyConnection(roots *x509.CertPool) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return fmt.Errorf("no certificate presented by the Kong Admin API")
+		}
+		c.lock.RLock()
+		caCerts := c.caCerts
+		c.lock.RUnlock()
+		if caCerts == nil {
+			caCerts = roots
+		}
+
+		opts := x509.VerifyOptions{
+			Roots:         caCerts,
+			DNSName:       cs.ServerName,
+			Intermediates: x509.NewCertPool(),
+		}
+		for _, cert := range cs.PeerCertificates[1:] {
+			opts.Intermediates.AddCert(cert)
+		}
+		_, err := cs.PeerCertificates[0].Verify(opts)
+		return err
+	}
+}
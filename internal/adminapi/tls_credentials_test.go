@@ -0,0 +1,70 @@
+package adminapi
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakeHTTPClientWithTLSCredentials(t *testing.T) {
+	caPEM, certPEM, certPrivateKeyPEM, err := buildTLS(t)
+	require.NoError(t, err)
+	otherCAPEM, otherCertPEM, otherCertPrivateKeyPEM, err := buildTLS(t)
+	require.NoError(t, err)
+
+	serverCert, err := tls.X509KeyPair(certPEM.Bytes(), certPrivateKeyPEM.Bytes())
+	require.NoError(t, err)
+	certPool := x509.NewCertPool()
+	require.True(t, certPool.AppendCertsFromPEM(caPEM.Bytes()))
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{
+		ClientCAs:    certPool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		Certificates: []tls.Certificate{serverCert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	server.Config.SetKeepAlivesEnabled(false) // connect anew on each request
+	server.StartTLS()
+	defer server.Close()
+
+	credentials := &TLSCredentials{}
+	require.NoError(t, credentials.Update(otherCertPEM.Bytes(), otherCertPrivateKeyPEM.Bytes(), otherCAPEM.Bytes()))
+	httpclient, err := MakeHTTPClient(&HTTPClientOpts{TLSCredentials: credentials})
+	require.NoError(t, err)
+
+	get := func() error {
+		response, err := httpclient.Get(server.URL)
+		if err != nil {
+			return err
+		}
+		return response.Body.Close()
+	}
+
+	t.Log("verifying that credentials from another CA are rejected")
+	require.Error(t, get())
+
+	t.Log("verifying that the connection succeeds once the credentials are rotated")
+	require.NoError(t, credentials.Update(certPEM.Bytes(), certPrivateKeyPEM.Bytes(), caPEM.Bytes()))
+	require.NoError(t, get())
+
+	t.Log("verifying that new connections use the rotated credentials")
+	require.NoError(t, credentials.Update(otherCertPEM.Bytes(), otherCertPrivateKeyPEM.Bytes(), caPEM.Bytes()))
+	require.Error(t, get())
+
+	t.Log("verifying that invalid credentials are rejected and the current ones kept")
+	require.Error(t, credentials.Update(certPEM.Bytes(), otherCertPrivateKeyPEM.Bytes(), caPEM.Bytes()))
+	require.Error(t, credentials.Update(nil, nil, []byte("not a certificate")))
+	require.Error(t, get())
+
+	t.Log("verifying that credentials can't be combined with a client certificate")
+	_, err = MakeHTTPClient(&HTTPClientOpts{
+		TLSCredentials: credentials,
+		TLSClientCert:  certPEM.String(),
+		TLSClientKey:   certPrivateKeyPEM.String(),
+	})
+	require.Error(t, err)
+}
@@ -6,16 +6,39 @@ const (
 	ErrTextConsumerExists                     = "consumer already exists"
 	ErrTextConsumerUnretrievable              = "failed to fetch consumer from kong"
 	ErrTextConsumerUsernameEmpty              = "username cannot be empty"
+	ErrTextNamespaceQuotaExceeded             = "namespace %s exceeds its quota of %d %s"
+	ErrTextRoutesUnretrievable                = "failed to list the objects routes are translated from"
 	ErrTextFailedToRetrieveSecret             = "could not retrieve secrets from the kubernets API" //nolint:gosec
 	ErrTextPluginConfigInvalid                = "could not parse plugin configuration"
+	ErrTextPluginConfigPatchInvalid           = "could not patch plugin configuration: %s"
 	ErrTextPluginConfigValidationFailed       = "unable to validate plugin schema"
 	ErrTextPluginConfigViolatesSchema         = "plugin failed schema validation: %s"
 	ErrTextPluginNameEmpty                    = "plugin name cannot be empty"
+	ErrTextPluginSecretNamespaceNotAllowed    = "plugin cannot reference secrets in namespace %s"
 	ErrTextPluginSecretConfigUnretrievable    = "could not load secret plugin configuration"
 	ErrTextPluginUsesBothConfigTypes          = "plugin cannot use both Config and ConfigFrom"
+	ErrTextPluginPatchesConfigFrom            = "plugin cannot use both ConfigFrom and ConfigPatches"
 )
 
 const (
 	ErrTextCantRetrieveGatewayClass    = "gatewayclass for this gateway could not be retrieved"
 	ErrTextInvalidGatewayConfiguration = "gateway metadata and/or spec are invalid"
 )
+
+const (
+	ErrTextIngressClassUnretrievable = "ingressclass for this ingress could not be retrieved"
+	ErrTextIngressesUnretrievable    = "failed to list ingresses"
+	ErrTextIngressPathInvalid        = "invalid path %q: %s"
+	ErrTextIngressPathDuplicate      = "host %q and path %q are already used by ingress %s"
+)
+
+const (
+	ErrTextL4IngressesUnretrievable = "failed to list %s"
+	ErrTextL4IngressRuleInvalid     = "invalid rule %d: %s"
+	ErrTextTCPIngressPortDuplicate  = "port %d with host %q is already used by TCPIngress %s"
+	ErrTextUDPIngressPortDuplicate  = "port %d is already used by UDPIngress %s"
+)
+
+const (
+	ErrTextAnnotationInvalid = "invalid annotation: %s"
+)
@@ -11,6 +11,7 @@ import (
 	"github.com/sirupsen/logrus"
 	admission "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
@@ -18,6 +19,7 @@ import (
 	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	configuration "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+	configurationv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
 )
 
 var (
@@ -185,6 +187,26 @@ var (
 		Version:  gatewayv1alpha2.SchemeGroupVersion.Version,
 		Resource: "httproutes",
 	}
+	serviceGVResource = meta.GroupVersionResource{
+		Group:    corev1.SchemeGroupVersion.Group,
+		Version:  corev1.SchemeGroupVersion.Version,
+		Resource: "services",
+	}
+	ingressGVResource = meta.GroupVersionResource{
+		Group:    netv1.SchemeGroupVersion.Group,
+		Version:  netv1.SchemeGroupVersion.Version,
+		Resource: "ingresses",
+	}
+	tcpIngressGVResource = meta.GroupVersionResource{
+		Group:    configurationv1beta1.SchemeGroupVersion.Group,
+		Version:  configurationv1beta1.SchemeGroupVersion.Version,
+		Resource: "tcpingresses",
+	}
+	udpIngressGVResource = meta.GroupVersionResource{
+		Group:    configurationv1beta1.SchemeGroupVersion.Group,
+		Version:  configurationv1beta1.SchemeGroupVersion.Version,
+		Resource: "udpingresses",
+	}
 )
 
 func (a RequestHandler) handleValidation(ctx context.Context, request admission.AdmissionRequest) (
@@ -306,6 +328,50 @@ func (a RequestHandler) handleValidation(ctx context.Context, request admission.
 		if err != nil {
 			return nil, err
 		}
+	case ingressGVResource:
+		ingress := netv1.Ingress{}
+		deserializer := codecs.UniversalDeserializer()
+		_, _, err = deserializer.Decode(request.Object.Raw, nil, &ingress)
+		if err != nil {
+			return nil, err
+		}
+		ok, message, err = a.Validator.ValidateIngress(ctx, ingress)
+		if err != nil {
+			return nil, err
+		}
+	case serviceGVResource:
+		service := corev1.Service{}
+		deserializer := codecs.UniversalDeserializer()
+		_, _, err = deserializer.Decode(request.Object.Raw, nil, &service)
+		if err != nil {
+			return nil, err
+		}
+		ok, message, err = a.Validator.ValidateService(ctx, service)
+		if err != nil {
+			return nil, err
+		}
+	case tcpIngressGVResource:
+		ingress := configurationv1beta1.TCPIngress{}
+		deserializer := codecs.UniversalDeserializer()
+		_, _, err = deserializer.Decode(request.Object.Raw, nil, &ingress)
+		if err != nil {
+			return nil, err
+		}
+		ok, message, err = a.Validator.ValidateTCPIngress(ctx, ingress)
+		if err != nil {
+			return nil, err
+		}
+	case udpIngressGVResource:
+		ingress := configurationv1beta1.UDPIngress{}
+		deserializer := codecs.UniversalDeserializer()
+		_, _, err = deserializer.Decode(request.Object.Raw, nil, &ingress)
+		if err != nil {
+			return nil, err
+		}
+		ok, message, err = a.Validator.ValidateUDPIngress(ctx, ingress)
+		if err != nil {
+			return nil, err
+		}
 	default:
 		return nil, fmt.Errorf("unknown resource type to validate: %s/%s %s",
 			request.Resource.Group, request.Resource.Version,
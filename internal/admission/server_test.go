@@ -14,10 +14,12 @@ import (
 	"github.com/stretchr/testify/assert"
 	admission "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	configuration "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+	configurationv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
 )
 
 var decoder = codecs.UniversalDeserializer()
@@ -55,6 +57,22 @@ func (v KongFakeValidator) ValidateHTTPRoute(ctx context.Context, gateway gatewa
 	return v.Result, v.Message, v.Error
 }
 
+func (v KongFakeValidator) ValidateIngress(ctx context.Context, ingress netv1.Ingress) (bool, string, error) {
+	return v.Result, v.Message, v.Error
+}
+
+func (v KongFakeValidator) ValidateService(ctx context.Context, service corev1.Service) (bool, string, error) {
+	return v.Result, v.Message, v.Error
+}
+
+func (v KongFakeValidator) ValidateTCPIngress(ctx context.Context, ingress configurationv1beta1.TCPIngress) (bool, string, error) {
+	return v.Result, v.Message, v.Error
+}
+
+func (v KongFakeValidator) ValidateUDPIngress(ctx context.Context, ingress configurationv1beta1.UDPIngress) (bool, string, error) {
+	return v.Result, v.Message, v.Error
+}
+
 func TestServeHTTPBasic(t *testing.T) {
 	assert := assert.New(t)
 	res := httptest.NewRecorder()
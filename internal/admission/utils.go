@@ -44,11 +44,21 @@ func listManagedConsumersReferencingCredentialsSecret(secret corev1.Secret, mana
 // if the caller is building the index to validate updates for specific secrets
 // and those secrets should be excluded from the index because they will be added
 // later, a map of the namespace and name of those secrets can be provided to exclude them.
+//
+// secrets referenced by several consumers are only indexed once, so that they
+// don't violate the unique key constraints with themselves.
 func globalValidationIndexForCredentials(ctx context.Context, managerClient client.Client, consumers []*kongv1.KongConsumer, ignoredSecrets map[string]map[string]struct{}) (credsvalidation.Index, error) {
 	// pull the reference secrets for credentials from each consumer in the list
 	index := make(credsvalidation.Index)
+	indexedSecrets := make(map[client.ObjectKey]struct{})
 	for _, consumer := range consumers {
 		for _, secretName := range consumer.Credentials {
+			key := client.ObjectKey{Namespace: consumer.Namespace, Name: secretName}
+			if _, ok := indexedSecrets[key]; ok {
+				continue
+			}
+			indexedSecrets[key] = struct{}{}
+
 			// if its been requested that this secret be specifically ignored
 			// (e.g. that secret is being updated and will soon have new values)
 			// then don't add it to the index.
@@ -60,10 +70,7 @@ func globalValidationIndexForCredentials(ctx context.Context, managerClient clie
 
 			// grab a copy of the credential secret
 			secret := &corev1.Secret{}
-			if err := managerClient.Get(ctx, client.ObjectKey{
-				Namespace: consumer.Namespace,
-				Name:      secretName,
-			}, secret); err != nil {
+			if err := managerClient.Get(ctx, key, secret); err != nil {
 				if errors.IsNotFound(err) { // ignore missing secrets
 					continue
 				}
@@ -2,23 +2,35 @@ package admission
 
 import (
 	"context"
+	"encoding/json"
+	goerrors "errors"
 	"fmt"
+	"regexp"
+	"regexp/syntax"
 	"strings"
 
+	"github.com/blang/semver/v4"
 	"github.com/kong/go-kong/kong"
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
 	gatewaycontroller "github.com/kong/kubernetes-ingress-controller/v2/internal/controllers/gateway"
+	ctrlutils "github.com/kong/kubernetes-ingress-controller/v2/internal/controllers/utils"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
 	credsvalidation "github.com/kong/kubernetes-ingress-controller/v2/internal/validation/consumers/credentials"
 	gatewayvalidators "github.com/kong/kubernetes-ingress-controller/v2/internal/validation/gateway"
 	kongv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+	kongv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
 )
 
 // KongValidator validates Kong entities.
@@ -29,6 +41,10 @@ type KongValidator interface {
 	ValidateCredential(ctx context.Context, secret corev1.Secret) (bool, string, error)
 	ValidateGateway(ctx context.Context, gateway gatewayv1alpha2.Gateway) (bool, string, error)
 	ValidateHTTPRoute(ctx context.Context, httproute gatewayv1alpha2.HTTPRoute) (bool, string, error)
+	ValidateIngress(ctx context.Context, ingress netv1.Ingress) (bool, string, error)
+	ValidateService(ctx context.Context, service corev1.Service) (bool, string, error)
+	ValidateTCPIngress(ctx context.Context, ingress kongv1beta1.TCPIngress) (bool, string, error)
+	ValidateUDPIngress(ctx context.Context, ingress kongv1beta1.UDPIngress) (bool, string, error)
 }
 
 // KongHTTPValidator implements KongValidator interface to validate Kong
@@ -40,6 +56,26 @@ type KongHTTPValidator struct {
 	SecretGetter  kongstate.SecretGetter
 	ManagerClient client.Client
 
+	// NamespaceQuotas limits the number of consumers, plugins and routes which
+	// can be created in any single namespace.
+	NamespaceQuotas util.NamespaceQuotas
+
+	// CombinedRoutes indicates that the routes of Ingresses are translated with
+	// the CombinedRoutes feature, which determines how many routes they count
+	// for in the route quota.
+	CombinedRoutes bool
+
+	// ClusterPluginSecretNamespaces are the namespaces of the Secrets which
+	// KongClusterPlugins may reference, any namespace if empty.
+	ClusterPluginSecretNamespaces []string
+
+	// KongVersion and RouterFlavor are the version and the router_flavor of
+	// Kong, which determine the paths Kong considers regexes and how it
+	// compiles them. RouterFlavor is empty for versions prior to 3.0.
+	KongVersion  semver.Version
+	RouterFlavor string
+
+	ingressClass        string
 	ingressClassMatcher func(*metav1.ObjectMeta, string, annotations.ClassMatching) bool
 }
 
@@ -61,7 +97,9 @@ func NewKongHTTPValidator(
 		Logger:        logger,
 		SecretGetter:  &managerClientSecretGetter{managerClient: managerClient},
 		ManagerClient: managerClient,
+		KongVersion:   util.GetKongVersion(),
 
+		ingressClass:        ingressClass,
 		ingressClassMatcher: matcher,
 	}
 }
@@ -85,6 +123,11 @@ func (validator KongHTTPValidator) ValidateConsumer(
 		return false, ErrTextConsumerUsernameEmpty, nil
 	}
 
+	// verify that the namespace has room for another consumer
+	if ok, message, err := validator.checkConsumerQuota(ctx, consumer); !ok || err != nil {
+		return ok, message, err
+	}
+
 	// verify that the consumer is not already otherwise present in the data-plane
 	c, err := validator.ConsumerSvc.Get(ctx, &consumer.Username)
 	if err != nil {
@@ -229,6 +272,9 @@ func (validator KongHTTPValidator) ValidatePlugin(
 	if k8sPlugin.PluginName == "" {
 		return false, ErrTextPluginNameEmpty, nil
 	}
+	if ok, message, err := validator.checkPluginQuota(ctx, k8sPlugin); !ok || err != nil {
+		return ok, message, err
+	}
 	var plugin kong.Plugin
 	plugin.Name = kong.String(k8sPlugin.PluginName)
 	var err error
@@ -240,12 +286,22 @@ func (validator KongHTTPValidator) ValidatePlugin(
 		if len(plugin.Config) > 0 {
 			return false, ErrTextPluginUsesBothConfigTypes, nil
 		}
+		if len(k8sPlugin.ConfigPatches) > 0 {
+			return false, ErrTextPluginPatchesConfigFrom, nil
+		}
 		config, err := kongstate.SecretToConfiguration(validator.SecretGetter, (*k8sPlugin.ConfigFrom).SecretValue, k8sPlugin.Namespace)
 		if err != nil {
 			return false, ErrTextPluginSecretConfigUnretrievable, err
 		}
 		plugin.Config = config
 	}
+	if len(k8sPlugin.ConfigPatches) > 0 {
+		patches := kongstate.NamespacedConfigPatches(k8sPlugin.Namespace, k8sPlugin.ConfigPatches)
+		plugin.Config, err = kongstate.ApplyConfigPatches(validator.SecretGetter, plugin.Config, patches)
+		if err != nil {
+			return false, fmt.Sprintf(ErrTextPluginConfigPatchInvalid, err), nil
+		}
+	}
 	if k8sPlugin.RunOn != "" {
 		plugin.RunOn = kong.String(k8sPlugin.RunOn)
 	}
@@ -278,7 +334,40 @@ func (validator KongHTTPValidator) ValidateClusterPlugin(
 		RunOn:       k8sPlugin.RunOn,
 		Protocols:   k8sPlugin.Protocols,
 	}
+	if len(k8sPlugin.ConfigPatches) > 0 {
+		// the patches may reference Secrets in any namespace, so they're
+		// applied here rather than carried over to the derived KongPlugin
+		if k8sPlugin.ConfigFrom != nil {
+			return false, ErrTextPluginPatchesConfigFrom, nil
+		}
+		for _, patch := range k8sPlugin.ConfigPatches {
+			if patch.ValueFrom == nil {
+				continue
+			}
+			namespace := patch.ValueFrom.SecretValue.Namespace
+			if !kongstate.IsClusterPluginSecretNamespaceAllowed(namespace, validator.ClusterPluginSecretNamespaces) {
+				return false, fmt.Sprintf(ErrTextPluginSecretNamespaceNotAllowed, namespace), nil
+			}
+		}
+		config, err := kongstate.RawConfigToConfiguration(k8sPlugin.Config)
+		if err != nil {
+			return false, ErrTextPluginConfigInvalid, err
+		}
+		config, err = kongstate.ApplyConfigPatches(validator.SecretGetter, config, k8sPlugin.ConfigPatches)
+		if err != nil {
+			return false, fmt.Sprintf(ErrTextPluginConfigPatchInvalid, err), nil
+		}
+		raw, err := json.Marshal(config)
+		if err != nil {
+			return false, ErrTextPluginConfigInvalid, err
+		}
+		derived.Config = apiextensionsv1.JSON{Raw: raw}
+	}
 	if k8sPlugin.ConfigFrom != nil {
+		namespace := k8sPlugin.ConfigFrom.SecretValue.Namespace
+		if !kongstate.IsClusterPluginSecretNamespaceAllowed(namespace, validator.ClusterPluginSecretNamespaces) {
+			return false, fmt.Sprintf(ErrTextPluginSecretNamespaceNotAllowed, namespace), nil
+		}
 		ref := kongv1.ConfigSource{
 			SecretValue: kongv1.SecretValueFromSource{
 				Secret: k8sPlugin.ConfigFrom.SecretValue.Secret,
@@ -360,11 +449,104 @@ func (validator KongHTTPValidator) ValidateHTTPRoute(
 		return true, "", nil
 	}
 
+	// verify that the namespace has room for the routes of the HTTPRoute
+	if ok, message, err := validator.checkRouteQuota(ctx, "HTTPRoute", &httproute, parser.HTTPRouteRouteCount(&httproute)); !ok || err != nil {
+		return ok, message, err
+	}
+
 	// now that we know whether or not the HTTPRoute is linked to a managed
 	// Gateway we can run it through full validation.
 	return gatewayvalidators.ValidateHTTPRoute(&httproute, managedGateways...)
 }
 
+// ValidateIngress checks that the timeouts its annotations request are valid
+// and that the paths of the rules of ingress can be translated to Kong routes:
+// that they don't contain "//", that the regexes among them compile with the
+// regex engine of the router of Kong, that no other Ingress of the same class
+// already uses the same host and path, and that its routes fit in the route
+// quota of its namespace.
+func (validator KongHTTPValidator) ValidateIngress(
+	ctx context.Context, ingress netv1.Ingress,
+) (bool, string, error) {
+	classIsDefault, err := validator.ingressClassIsDefault(ctx)
+	if err != nil {
+		return false, ErrTextIngressClassUnretrievable, err
+	}
+	// ignore ingresses that are being managed by another controller
+	if !validator.isManagedIngress(&ingress, classIsDefault) {
+		return true, "", nil
+	}
+
+	if _, err := kongstate.ServiceTimeoutsFromAnnotations(ingress.Annotations); err != nil {
+		return false, fmt.Sprintf(ErrTextAnnotationInvalid, err), nil
+	}
+	if _, err := kongstate.RewriteURIFromAnnotations(ingress.Annotations); err != nil {
+		return false, fmt.Sprintf(ErrTextAnnotationInvalid, err), nil
+	}
+	if _, err := kongstate.CORSConfigurationFromAnnotations(ingress.Annotations); err != nil {
+		return false, fmt.Sprintf(ErrTextAnnotationInvalid, err), nil
+	}
+
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, rulePath := range rule.HTTP.Paths {
+			if strings.Contains(rulePath.Path, "//") {
+				return false, fmt.Sprintf(ErrTextIngressPathInvalid, rulePath.Path, `paths cannot contain "//"`), nil
+			}
+			regexes, err := parser.KongRegexPaths(rulePath.Path, ingressPathType(rulePath), ingress.Annotations, validator.KongVersion)
+			if err != nil {
+				return false, fmt.Sprintf(ErrTextIngressPathInvalid, rulePath.Path, err), nil
+			}
+			for _, regex := range regexes {
+				if err := validator.compileRegexPath(regex); err != nil {
+					return false, fmt.Sprintf(ErrTextIngressPathInvalid, rulePath.Path, err), nil
+				}
+			}
+		}
+	}
+
+	// the ingress itself is skipped, as it may be an update of an existing one
+	ingresses := &netv1.IngressList{}
+	if err := validator.ManagerClient.List(ctx, ingresses); err != nil {
+		return false, ErrTextIngressesUnretrievable, err
+	}
+	used := make(map[ingressHostPath]string)
+	for i := range ingresses.Items {
+		other := &ingresses.Items[i]
+		if (other.Namespace == ingress.Namespace && other.Name == ingress.Name) ||
+			!validator.isManagedIngress(other, classIsDefault) {
+			continue
+		}
+		for _, hostPath := range ingressHostPaths(other) {
+			used[hostPath] = other.Namespace + "/" + other.Name
+		}
+	}
+	for _, hostPath := range ingressHostPaths(&ingress) {
+		if owner, ok := used[hostPath]; ok {
+			return false, fmt.Sprintf(ErrTextIngressPathDuplicate, hostPath.host, hostPath.path, owner), nil
+		}
+		used[hostPath] = ingress.Namespace + "/" + ingress.Name
+	}
+
+	return validator.checkRouteQuota(ctx, "Ingress", &ingress, parser.IngressRouteCount(&ingress, validator.CombinedRoutes))
+}
+
+// ValidateService checks that the timeouts and the number of retries the
+// annotations of service request are valid.
+func (validator KongHTTPValidator) ValidateService(
+	_ context.Context, service corev1.Service,
+) (bool, string, error) {
+	if _, err := kongstate.ServiceTimeoutsFromAnnotations(service.Annotations); err != nil {
+		return false, fmt.Sprintf(ErrTextAnnotationInvalid, err), nil
+	}
+	if _, err := kongstate.ServiceRetriesFromAnnotations(service.Annotations); err != nil {
+		return false, fmt.Sprintf(ErrTextAnnotationInvalid, err), nil
+	}
+	return true, "", nil
+}
+
 // -----------------------------------------------------------------------------
 // KongHTTPValidator - Private Methods
 // -----------------------------------------------------------------------------
@@ -393,6 +575,197 @@ func (validator KongHTTPValidator) listManagedConsumers(ctx context.Context) ([]
 	return managedConsumers, nil
 }
 
+// -----------------------------------------------------------------------------
+// KongHTTPValidator - Ingresses
+// -----------------------------------------------------------------------------
+
+// routerFlavorTraditionalCompatible is the router flavor of Kong which compiles
+// regexes with the Rust regex engine, whose syntax is the one of Go regexes.
+// The other flavors compile them with PCRE.
+const routerFlavorTraditionalCompatible = "traditional_compatible"
+
+// pcreSyntaxErrors are the errors of the Go regex parser which PCRE reports too.
+// Other errors concern syntaxes which only PCRE supports, such as lookarounds.
+var pcreSyntaxErrors = map[syntax.ErrorCode]struct{}{
+	syntax.ErrInvalidCharRange:      {},
+	syntax.ErrMissingBracket:        {},
+	syntax.ErrMissingParen:          {},
+	syntax.ErrMissingRepeatArgument: {},
+	syntax.ErrTrailingBackslash:     {},
+	syntax.ErrUnexpectedParen:       {},
+}
+
+// compileRegexPath checks that the router of Kong can compile the regex of a
+// path.
+func (validator KongHTTPValidator) compileRegexPath(regex string) error {
+	if validator.RouterFlavor == routerFlavorTraditionalCompatible {
+		_, err := regexp.Compile(regex)
+		return err
+	}
+	if _, err := syntax.Parse(regex, syntax.Perl); err != nil {
+		var syntaxErr *syntax.Error
+		if goerrors.As(err, &syntaxErr) {
+			if _, ok := pcreSyntaxErrors[syntaxErr.Code]; ok {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ingressClassIsDefault indicates whether the IngressClass of the controller
+// is the default one, in which case the Ingresses without a class are managed
+// by the controller.
+// ValidateTCPIngress checks that the rules of a TCPIngress can be translated
+// and that none of them listens on a port and host another TCPIngress of the
+// class already uses or exceeds the route quota of its namespace.
+func (validator KongHTTPValidator) ValidateTCPIngress(
+	ctx context.Context, ingress kongv1beta1.TCPIngress,
+) (bool, string, error) {
+	classIsDefault, err := validator.ingressClassIsDefault(ctx)
+	if err != nil {
+		return false, ErrTextIngressClassUnretrievable, err
+	}
+	if !ctrlutils.MatchesIngressClass(&ingress, validator.ingressClass, classIsDefault) {
+		return true, "", nil
+	}
+	for i, rule := range ingress.Spec.Rules {
+		if err := parser.ValidateTCPIngressRule(rule); err != nil {
+			return false, fmt.Sprintf(ErrTextL4IngressRuleInvalid, i, err), nil
+		}
+	}
+
+	// the ingress itself is skipped, as it may be an update of an existing one
+	ingresses := &kongv1beta1.TCPIngressList{}
+	if err := validator.ManagerClient.List(ctx, ingresses); err != nil {
+		return false, fmt.Sprintf(ErrTextL4IngressesUnretrievable, "TCPIngresses"), err
+	}
+	used := make(map[parser.TCPListener]string)
+	for i := range ingresses.Items {
+		other := &ingresses.Items[i]
+		if (other.Namespace == ingress.Namespace && other.Name == ingress.Name) ||
+			!ctrlutils.MatchesIngressClass(other, validator.ingressClass, classIsDefault) {
+			continue
+		}
+		for _, rule := range other.Spec.Rules {
+			used[parser.TCPListenerOf(rule)] = other.Namespace + "/" + other.Name
+		}
+	}
+	for _, rule := range ingress.Spec.Rules {
+		listener := parser.TCPListenerOf(rule)
+		if owner, ok := used[listener]; ok {
+			return false, fmt.Sprintf(ErrTextTCPIngressPortDuplicate, listener.Port, listener.Host, owner), nil
+		}
+		used[listener] = ingress.Namespace + "/" + ingress.Name
+	}
+	return validator.checkRouteQuota(ctx, "TCPIngress", &ingress, parser.TCPIngressRouteCount(&ingress))
+}
+
+// ValidateUDPIngress checks that the rules of a UDPIngress can be translated
+// and that none of them listens on a port another UDPIngress of the class
+// already uses or exceeds the route quota of its namespace.
+func (validator KongHTTPValidator) ValidateUDPIngress(
+	ctx context.Context, ingress kongv1beta1.UDPIngress,
+) (bool, string, error) {
+	classIsDefault, err := validator.ingressClassIsDefault(ctx)
+	if err != nil {
+		return false, ErrTextIngressClassUnretrievable, err
+	}
+	if !ctrlutils.MatchesIngressClass(&ingress, validator.ingressClass, classIsDefault) {
+		return true, "", nil
+	}
+	for i, rule := range ingress.Spec.Rules {
+		if err := parser.ValidateUDPIngressRule(rule); err != nil {
+			return false, fmt.Sprintf(ErrTextL4IngressRuleInvalid, i, err), nil
+		}
+	}
+
+	// the ingress itself is skipped, as it may be an update of an existing one
+	ingresses := &kongv1beta1.UDPIngressList{}
+	if err := validator.ManagerClient.List(ctx, ingresses); err != nil {
+		return false, fmt.Sprintf(ErrTextL4IngressesUnretrievable, "UDPIngresses"), err
+	}
+	used := make(map[int]string)
+	for i := range ingresses.Items {
+		other := &ingresses.Items[i]
+		if (other.Namespace == ingress.Namespace && other.Name == ingress.Name) ||
+			!ctrlutils.MatchesIngressClass(other, validator.ingressClass, classIsDefault) {
+			continue
+		}
+		for _, rule := range other.Spec.Rules {
+			for port := rule.Port; port <= parser.UDPIngressRuleEndPort(rule); port++ {
+				used[port] = other.Namespace + "/" + other.Name
+			}
+		}
+	}
+	for _, rule := range ingress.Spec.Rules {
+		for port := rule.Port; port <= parser.UDPIngressRuleEndPort(rule); port++ {
+			if owner, ok := used[port]; ok {
+				return false, fmt.Sprintf(ErrTextUDPIngressPortDuplicate, port, owner), nil
+			}
+			used[port] = ingress.Namespace + "/" + ingress.Name
+		}
+	}
+	return validator.checkRouteQuota(ctx, "UDPIngress", &ingress, parser.UDPIngressRouteCount(&ingress))
+}
+
+func (validator KongHTTPValidator) ingressClassIsDefault(ctx context.Context) (bool, error) {
+	class := &netv1.IngressClass{}
+	if err := validator.ManagerClient.Get(ctx, client.ObjectKey{Name: validator.ingressClass}, class); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return ctrlutils.IsDefaultIngressClass(class), nil
+}
+
+// isManagedIngress indicates whether an Ingress is managed by the controller,
+// considering its class annotation first, then its class name.
+func (validator KongHTTPValidator) isManagedIngress(ingress *netv1.Ingress, classIsDefault bool) bool {
+	if ingress.Annotations[annotations.IngressClassKey] != "" {
+		return validator.ingressClassMatcher(&ingress.ObjectMeta, annotations.IngressClassKey, annotations.ExactClassMatch)
+	}
+	if ingress.Spec.IngressClassName != nil {
+		return *ingress.Spec.IngressClassName == validator.ingressClass
+	}
+	return classIsDefault
+}
+
+// ingressHostPath is a combination of host, path and path type which only one
+// Ingress rule of a class can use.
+type ingressHostPath struct {
+	host     string
+	path     string
+	pathType netv1.PathType
+}
+
+func ingressHostPaths(ingress *netv1.Ingress) []ingressHostPath {
+	var hostPaths []ingressHostPath
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, rulePath := range rule.HTTP.Paths {
+			hostPaths = append(hostPaths, ingressHostPath{
+				host:     rule.Host,
+				path:     rulePath.Path,
+				pathType: ingressPathType(rulePath),
+			})
+		}
+	}
+	return hostPaths
+}
+
+// ingressPathType provides the type of an Ingress path, which defaults to
+// ImplementationSpecific.
+func ingressPathType(path netv1.HTTPIngressPath) netv1.PathType {
+	if path.PathType == nil {
+		return netv1.PathTypeImplementationSpecific
+	}
+	return *path.PathType
+}
+
 // -----------------------------------------------------------------------------
 // Private - Manager Client Secret Getter
 // -----------------------------------------------------------------------------
@@ -408,3 +781,150 @@ func (m *managerClientSecretGetter) GetSecret(namespace, name string) (*corev1.S
 		Name:      name,
 	}, secret)
 }
+
+// -----------------------------------------------------------------------------
+// KongHTTPValidator - Namespace Quotas
+// -----------------------------------------------------------------------------
+
+// checkConsumerQuota verifies that creating the consumer doesn't exceed the
+// consumer quota of its namespace. Consumers managed by other controllers don't
+// count towards the quota.
+func (validator KongHTTPValidator) checkConsumerQuota(ctx context.Context, consumer kongv1.KongConsumer) (bool, string, error) {
+	max := validator.NamespaceQuotas.MaxConsumers
+	if max <= 0 {
+		return true, "", nil
+	}
+	consumers := &kongv1.KongConsumerList{}
+	if err := validator.ManagerClient.List(ctx, consumers, client.InNamespace(consumer.Namespace)); err != nil {
+		return false, ErrTextConsumerUnretrievable, err
+	}
+	names := make([]string, 0, len(consumers.Items))
+	for i := range consumers.Items {
+		if validator.ingressClassMatcher(&consumers.Items[i].ObjectMeta, annotations.IngressClassKey, annotations.ExactClassMatch) {
+			names = append(names, consumers.Items[i].Name)
+		}
+	}
+	if exceedsNamespaceQuota(names, consumer.Name, max) {
+		return false, fmt.Sprintf(ErrTextNamespaceQuotaExceeded, consumer.Namespace, max, "consumers"), nil
+	}
+	return true, "", nil
+}
+
+// checkPluginQuota verifies that creating the plugin doesn't exceed the plugin
+// quota of its namespace. As each KongPlugin produces at least one Kong plugin
+// when it's used, the number of KongPlugins is limited by the quota too.
+func (validator KongHTTPValidator) checkPluginQuota(ctx context.Context, plugin kongv1.KongPlugin) (bool, string, error) {
+	max := validator.NamespaceQuotas.MaxPlugins
+	if max <= 0 {
+		return true, "", nil
+	}
+	plugins := &kongv1.KongPluginList{}
+	if err := validator.ManagerClient.List(ctx, plugins, client.InNamespace(plugin.Namespace)); err != nil {
+		return false, ErrTextPluginConfigValidationFailed, err
+	}
+	names := make([]string, 0, len(plugins.Items))
+	for _, p := range plugins.Items {
+		names = append(names, p.Name)
+	}
+	if exceedsNamespaceQuota(names, plugin.Name, max) {
+		return false, fmt.Sprintf(ErrTextNamespaceQuotaExceeded, plugin.Namespace, max, "plugins"), nil
+	}
+	return true, "", nil
+}
+
+// routeSource identifies an object of a namespace Kong routes are translated from.
+type routeSource struct {
+	kind, name string
+}
+
+// checkRouteQuota verifies that the routes an object of the given kind
+// translates to don't exceed the route quota of its namespace, along with the
+// routes of the other Ingresses, HTTPRoutes, TCPIngresses and UDPIngresses of
+// the namespace. Updates which don't add routes are always accepted.
+func (validator KongHTTPValidator) checkRouteQuota(ctx context.Context, kind string, obj client.Object, routes int) (bool, string, error) {
+	max := validator.NamespaceQuotas.MaxRoutes
+	if max <= 0 {
+		return true, "", nil
+	}
+	counts, err := validator.namespaceRouteCounts(ctx, obj.GetNamespace())
+	if err != nil {
+		return false, ErrTextRoutesUnretrievable, err
+	}
+	self := routeSource{kind: kind, name: obj.GetName()}
+	if routes <= counts[self] {
+		return true, "", nil
+	}
+	total := routes
+	for source, count := range counts {
+		if source != self {
+			total += count
+		}
+	}
+	if total > max {
+		return false, fmt.Sprintf(ErrTextNamespaceQuotaExceeded, obj.GetNamespace(), max, "routes"), nil
+	}
+	return true, "", nil
+}
+
+// namespaceRouteCounts provides the number of routes each Ingress, HTTPRoute,
+// TCPIngress and UDPIngress managed by the controller in a namespace
+// translates to. HTTPRoutes aren't counted if their CRD isn't installed.
+func (validator KongHTTPValidator) namespaceRouteCounts(ctx context.Context, namespace string) (map[routeSource]int, error) {
+	classIsDefault, err := validator.ingressClassIsDefault(ctx)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[routeSource]int)
+
+	ingresses := &netv1.IngressList{}
+	if err := validator.ManagerClient.List(ctx, ingresses, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	for i := range ingresses.Items {
+		if ingress := &ingresses.Items[i]; validator.isManagedIngress(ingress, classIsDefault) {
+			counts[routeSource{kind: "Ingress", name: ingress.Name}] = parser.IngressRouteCount(ingress, validator.CombinedRoutes)
+		}
+	}
+
+	httproutes := &gatewayv1alpha2.HTTPRouteList{}
+	if err := validator.ManagerClient.List(ctx, httproutes, client.InNamespace(namespace)); err != nil && !meta.IsNoMatchError(err) {
+		return nil, err
+	}
+	for i := range httproutes.Items {
+		counts[routeSource{kind: "HTTPRoute", name: httproutes.Items[i].Name}] = parser.HTTPRouteRouteCount(&httproutes.Items[i])
+	}
+
+	tcpIngresses := &kongv1beta1.TCPIngressList{}
+	if err := validator.ManagerClient.List(ctx, tcpIngresses, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	for i := range tcpIngresses.Items {
+		if ingress := &tcpIngresses.Items[i]; ctrlutils.MatchesIngressClass(ingress, validator.ingressClass, classIsDefault) {
+			counts[routeSource{kind: "TCPIngress", name: ingress.Name}] = parser.TCPIngressRouteCount(ingress)
+		}
+	}
+
+	udpIngresses := &kongv1beta1.UDPIngressList{}
+	if err := validator.ManagerClient.List(ctx, udpIngresses, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	for i := range udpIngresses.Items {
+		if ingress := &udpIngresses.Items[i]; ctrlutils.MatchesIngressClass(ingress, validator.ingressClass, classIsDefault) {
+			counts[routeSource{kind: "UDPIngress", name: ingress.Name}] = parser.UDPIngressRouteCount(ingress)
+		}
+	}
+
+	return counts, nil
+}
+
+// exceedsNamespaceQuota reports whether adding an object named name to the
+// existing objects of a namespace exceeds the quota. Updates of existing
+// objects never exceed it.
+func exceedsNamespaceQuota(existing []string, name string, max int) bool {
+	for _, existingName := range existing {
+		if existingName == name {
+			return false
+		}
+	}
+	return len(existing) >= max
+}
@@ -5,13 +5,24 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/blang/semver/v4"
 	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
 	configurationv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+	configurationv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
 )
 
 type fakePluginSvc struct {
@@ -123,6 +134,58 @@ func TestKongHTTPValidator_ValidatePlugin(t *testing.T) {
 			wantMessage: ErrTextPluginSecretConfigUnretrievable,
 			wantErr:     true,
 		},
+		{
+			name:      "plugin has both ConfigFrom and ConfigPatches",
+			PluginSvc: &fakePluginSvc{},
+			args: args{
+				plugin: configurationv1.KongPlugin{
+					PluginName: "key-auth",
+					ConfigFrom: &configurationv1.ConfigSource{
+						SecretValue: configurationv1.SecretValueFromSource{
+							Key:    "key-auth-config",
+							Secret: "conf-secret",
+						},
+					},
+					ConfigPatches: []configurationv1.ConfigPatch{{
+						Path:  "/key_names",
+						Value: &apiextensionsv1.JSON{Raw: []byte(`["apikey"]`)},
+					}},
+				},
+			},
+			wantOK:      false,
+			wantMessage: ErrTextPluginPatchesConfigFrom,
+			wantErr:     false,
+		},
+		{
+			name:      "plugin has an invalid config patch",
+			PluginSvc: &fakePluginSvc{},
+			args: args{
+				plugin: configurationv1.KongPlugin{
+					PluginName:    "key-auth",
+					ConfigPatches: []configurationv1.ConfigPatch{{Path: "/key_names"}},
+				},
+			},
+			wantOK: false,
+			wantMessage: fmt.Sprintf(ErrTextPluginConfigPatchInvalid,
+				`config patch "/key_names" must set exactly one of value and valueFrom`),
+			wantErr: false,
+		},
+		{
+			name:      "plugin config is patched",
+			PluginSvc: &fakePluginSvc{valid: true},
+			args: args{
+				plugin: configurationv1.KongPlugin{
+					PluginName: "key-auth",
+					ConfigPatches: []configurationv1.ConfigPatch{{
+						Path:  "/key_names",
+						Value: &apiextensionsv1.JSON{Raw: []byte(`["apikey"]`)},
+					}},
+				},
+			},
+			wantOK:      true,
+			wantMessage: "",
+			wantErr:     false,
+		},
 		{
 			name:      "failed to retrieve validation info",
 			PluginSvc: &fakePluginSvc{valid: false, err: fmt.Errorf("everything broke")},
@@ -162,12 +225,13 @@ func TestKongHTTPValidator_ValidateClusterPlugin(t *testing.T) {
 		plugin configurationv1.KongClusterPlugin
 	}
 	tests := []struct {
-		name        string
-		PluginSvc   kong.AbstractPluginService
-		args        args
-		wantOK      bool
-		wantMessage string
-		wantErr     bool
+		name             string
+		PluginSvc        kong.AbstractPluginService
+		secretNamespaces []string
+		args             args
+		wantOK           bool
+		wantMessage      string
+		wantErr          bool
 	}{
 		{
 			name:      "plugin is valid",
@@ -255,6 +319,26 @@ func TestKongHTTPValidator_ValidateClusterPlugin(t *testing.T) {
 			wantMessage: ErrTextPluginSecretConfigUnretrievable,
 			wantErr:     true,
 		},
+		{
+			name:             "plugin ConfigFrom references a Secret in a namespace which isn't allowed",
+			PluginSvc:        &fakePluginSvc{},
+			secretNamespaces: []string{"kong"},
+			args: args{
+				plugin: configurationv1.KongClusterPlugin{
+					PluginName: "key-auth",
+					ConfigFrom: &configurationv1.NamespacedConfigSource{
+						SecretValue: configurationv1.NamespacedSecretValueFromSource{
+							Key:       "key-auth-config",
+							Secret:    "conf-secret",
+							Namespace: "default",
+						},
+					},
+				},
+			},
+			wantOK:      false,
+			wantMessage: fmt.Sprintf(ErrTextPluginSecretNamespaceNotAllowed, "default"),
+			wantErr:     false,
+		},
 		{
 			name:      "failed to retrieve validation info",
 			PluginSvc: &fakePluginSvc{valid: false, err: fmt.Errorf("everything broke")},
@@ -269,9 +353,10 @@ func TestKongHTTPValidator_ValidateClusterPlugin(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			validator := KongHTTPValidator{
-				SecretGetter:        store,
-				PluginSvc:           tt.PluginSvc,
-				ingressClassMatcher: fakeClassMatcher,
+				SecretGetter:                  store,
+				PluginSvc:                     tt.PluginSvc,
+				ClusterPluginSecretNamespaces: tt.secretNamespaces,
+				ingressClassMatcher:           fakeClassMatcher,
 			}
 			got, got1, err := validator.ValidateClusterPlugin(context.Background(), tt.args.plugin)
 			if (err != nil) != tt.wantErr {
@@ -289,3 +374,605 @@ func TestKongHTTPValidator_ValidateClusterPlugin(t *testing.T) {
 }
 
 func fakeClassMatcher(*metav1.ObjectMeta, string, annotations.ClassMatching) bool { return true }
+
+func TestKongHTTPValidator_ValidatePluginNamespaceQuota(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := configurationv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	managerClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&configurationv1.KongPlugin{ObjectMeta: metav1.ObjectMeta{Namespace: "full", Name: "a"}, PluginName: "foo"},
+		&configurationv1.KongPlugin{ObjectMeta: metav1.ObjectMeta{Namespace: "full", Name: "b"}, PluginName: "foo"},
+		&configurationv1.KongPlugin{ObjectMeta: metav1.ObjectMeta{Namespace: "roomy", Name: "a"}, PluginName: "foo"},
+	).Build()
+	validator := KongHTTPValidator{
+		PluginSvc:       &fakePluginSvc{valid: true},
+		ManagerClient:   managerClient,
+		NamespaceQuotas: util.NamespaceQuotas{MaxPlugins: 2},
+	}
+
+	tests := []struct {
+		name        string
+		plugin      configurationv1.KongPlugin
+		wantOK      bool
+		wantMessage string
+	}{
+		{
+			name:   "namespace below its quota",
+			plugin: configurationv1.KongPlugin{ObjectMeta: metav1.ObjectMeta{Namespace: "roomy", Name: "b"}, PluginName: "foo"},
+			wantOK: true,
+		},
+		{
+			name:        "namespace at its quota",
+			plugin:      configurationv1.KongPlugin{ObjectMeta: metav1.ObjectMeta{Namespace: "full", Name: "c"}, PluginName: "foo"},
+			wantOK:      false,
+			wantMessage: fmt.Sprintf(ErrTextNamespaceQuotaExceeded, "full", 2, "plugins"),
+		},
+		{
+			name:   "update of a plugin in a namespace at its quota",
+			plugin: configurationv1.KongPlugin{ObjectMeta: metav1.ObjectMeta{Namespace: "full", Name: "a"}, PluginName: "foo"},
+			wantOK: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotOK, gotMessage, err := validator.ValidatePlugin(context.Background(), tt.plugin)
+			if err != nil {
+				t.Fatalf("ValidatePlugin() unexpected error: %v", err)
+			}
+			if gotOK != tt.wantOK {
+				t.Errorf("ValidatePlugin() gotOK = %v, want %v", gotOK, tt.wantOK)
+			}
+			if gotMessage != tt.wantMessage {
+				t.Errorf("ValidatePlugin() gotMessage = %v, want %v", gotMessage, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestKongHTTPValidator_ValidateCredential(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, configurationv1.AddToScheme(scheme))
+	keyAuth := func(name, key string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Data: map[string][]byte{
+				"kongCredType": []byte("key-auth"),
+				"key":          []byte(key),
+			},
+		}
+	}
+	consumer := func(name string, credentials ...string) *configurationv1.KongConsumer {
+		return &configurationv1.KongConsumer{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "default",
+				Name:        name,
+				Annotations: map[string]string{annotations.IngressClassKey: annotations.DefaultIngressClass},
+			},
+			Username:    name,
+			Credentials: credentials,
+		}
+	}
+	managerClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		keyAuth("shared", "foo"),
+		keyAuth("other", "bar"),
+		consumer("alice", "shared", "other"),
+		consumer("bob", "shared"),
+	).Build()
+	validator := KongHTTPValidator{
+		ManagerClient:       managerClient,
+		ingressClassMatcher: annotations.IngressClassValidatorFuncFromObjectMeta(annotations.DefaultIngressClass),
+	}
+
+	tests := []struct {
+		name        string
+		secret      *corev1.Secret
+		wantOK      bool
+		wantMessage string
+	}{
+		{
+			name:   "secrets referenced by several consumers don't conflict with themselves",
+			secret: keyAuth("other", "baz"),
+			wantOK: true,
+		},
+		{
+			name:        "duplicate key-auth keys are rejected",
+			secret:      keyAuth("other", "foo"),
+			wantMessage: ErrTextConsumerCredentialValidationFailed,
+		},
+		{
+			name: "invalid values are rejected",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other"},
+				Data: map[string][]byte{
+					"kongCredType":   []byte("jwt"),
+					"algorithm":      []byte("none"),
+					"key":            []byte("key"),
+					"rsa_public_key": []byte("key"),
+					"secret":         []byte("secret"),
+				},
+			},
+			wantMessage: ErrTextConsumerCredentialValidationFailed,
+		},
+		{
+			name:   "secrets which aren't referenced aren't validated",
+			secret: keyAuth("unreferenced", "foo"),
+			wantOK: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotOK, gotMessage, err := validator.ValidateCredential(context.Background(), *tt.secret)
+			assert.Equal(t, tt.wantOK, gotOK)
+			assert.Equal(t, tt.wantMessage, gotMessage)
+			if !tt.wantOK {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestKongHTTPValidator_ValidateIngress(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, netv1.AddToScheme(scheme))
+
+	pathType := netv1.PathTypeImplementationSpecific
+	ingress := func(name, class, host string, paths ...string) *netv1.Ingress {
+		rule := netv1.IngressRule{
+			Host:             host,
+			IngressRuleValue: netv1.IngressRuleValue{HTTP: &netv1.HTTPIngressRuleValue{}},
+		}
+		for _, path := range paths {
+			rule.HTTP.Paths = append(rule.HTTP.Paths, netv1.HTTPIngressPath{Path: path, PathType: &pathType})
+		}
+		return &netv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec: netv1.IngressSpec{
+				IngressClassName: &class,
+				Rules:            []netv1.IngressRule{rule},
+			},
+		}
+	}
+	managerClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		ingress("existing", annotations.DefaultIngressClass, "example.com", "/foo"),
+		ingress("other-class", "other", "example.com", "/bar"),
+	).Build()
+
+	for _, tt := range []struct {
+		name         string
+		ingress      *netv1.Ingress
+		kongVersion  semver.Version
+		routerFlavor string
+		wantOK       bool
+		wantMessage  string
+	}{
+		{
+			name:    "valid paths are accepted",
+			ingress: ingress("new", annotations.DefaultIngressClass, "example.com", "/baz", "/~/qux/[a-z]+"),
+			wantOK:  true,
+		},
+		{
+			name:        `paths containing "//" are rejected`,
+			ingress:     ingress("new", annotations.DefaultIngressClass, "example.com", "/baz//qux"),
+			wantMessage: fmt.Sprintf(ErrTextIngressPathInvalid, "/baz//qux", `paths cannot contain "//"`),
+		},
+		{
+			name:        "invalid regexes are rejected",
+			ingress:     ingress("new", annotations.DefaultIngressClass, "example.com", "/~/baz/[a-z"),
+			wantMessage: fmt.Sprintf(ErrTextIngressPathInvalid, "/~/baz/[a-z", "error parsing regexp: missing closing ]: `[a-z`"),
+		},
+		{
+			name:    "regexes are not validated without the regex prefix",
+			ingress: ingress("new", annotations.DefaultIngressClass, "example.com", "/baz/[a-z"),
+			wantOK:  true,
+		},
+		{
+			name:        "regexes are validated based on their characters before Kong 3.0",
+			ingress:     ingress("new", annotations.DefaultIngressClass, "example.com", "/baz/[a-z"),
+			kongVersion: semver.MustParse("2.8.0"),
+			wantMessage: fmt.Sprintf(ErrTextIngressPathInvalid, "/baz/[a-z", "error parsing regexp: missing closing ]: `[a-z`"),
+		},
+		{
+			name:    "regexes PCRE supports are accepted by the traditional router",
+			ingress: ingress("new", annotations.DefaultIngressClass, "example.com", "/~/baz/(?!qux)"),
+			wantOK:  true,
+		},
+		{
+			name:         "regexes Rust doesn't support are rejected by the traditional_compatible router",
+			ingress:      ingress("new", annotations.DefaultIngressClass, "example.com", "/~/baz/(?!qux)"),
+			routerFlavor: "traditional_compatible",
+			wantMessage:  fmt.Sprintf(ErrTextIngressPathInvalid, "/~/baz/(?!qux)", "error parsing regexp: invalid or unsupported Perl syntax: `(?!`"),
+		},
+		{
+			name:        "hosts and paths used by another ingress of the class are rejected",
+			ingress:     ingress("new", annotations.DefaultIngressClass, "example.com", "/foo"),
+			wantMessage: fmt.Sprintf(ErrTextIngressPathDuplicate, "example.com", "/foo", "default/existing"),
+		},
+		{
+			name:        "hosts and paths used twice by the ingress are rejected",
+			ingress:     ingress("new", annotations.DefaultIngressClass, "example.com", "/baz", "/baz"),
+			wantMessage: fmt.Sprintf(ErrTextIngressPathDuplicate, "example.com", "/baz", "default/new"),
+		},
+		{
+			name:    "updates of an ingress don't conflict with itself",
+			ingress: ingress("existing", annotations.DefaultIngressClass, "example.com", "/foo"),
+			wantOK:  true,
+		},
+		{
+			name:    "hosts and paths used by an ingress of another class are accepted",
+			ingress: ingress("new", annotations.DefaultIngressClass, "example.com", "/bar"),
+			wantOK:  true,
+		},
+		{
+			name: "invalid timeouts are rejected",
+			ingress: func() *netv1.Ingress {
+				ing := ingress("new", annotations.DefaultIngressClass, "example.com", "/baz")
+				ing.Annotations = map[string]string{"konghq.com/read-timeout": "5m"}
+				return ing
+			}(),
+			wantMessage: fmt.Sprintf(ErrTextAnnotationInvalid,
+				`konghq.com/read-timeout annotation "5m" must be a number of milliseconds between 0 and 2147483646`),
+		},
+		{
+			name: "invalid rewrites are rejected",
+			ingress: func() *netv1.Ingress {
+				ing := ingress("new", annotations.DefaultIngressClass, "example.com", "/~/baz/(.*)")
+				ing.Annotations = map[string]string{"konghq.com/rewrite": "api/$1"}
+				return ing
+			}(),
+			wantMessage: fmt.Sprintf(ErrTextAnnotationInvalid,
+				`konghq.com/rewrite annotation "api/$1" must be a path starting with /`),
+		},
+		{
+			name: "invalid CORS annotations are rejected",
+			ingress: func() *netv1.Ingress {
+				ing := ingress("new", annotations.DefaultIngressClass, "example.com", "/baz")
+				ing.Annotations = map[string]string{
+					"konghq.com/cors-allow-origins": "https://example.com",
+					"konghq.com/cors-max-age":       "1h",
+				}
+				return ing
+			}(),
+			wantMessage: fmt.Sprintf(ErrTextAnnotationInvalid,
+				`konghq.com/cors-max-age annotation "1h" must be a number of seconds`),
+		},
+		{
+			name:    "ingresses of another class are not validated",
+			ingress: ingress("new", "other", "example.com", "/baz//qux"),
+			wantOK:  true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := NewKongHTTPValidator(nil, nil, nil, managerClient, annotations.DefaultIngressClass)
+			validator.KongVersion = parser.MinRegexPathPrefixKongVersion
+			if tt.kongVersion.Major != 0 {
+				validator.KongVersion = tt.kongVersion
+			}
+			validator.RouterFlavor = tt.routerFlavor
+
+			ok, message, err := validator.ValidateIngress(context.Background(), *tt.ingress)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantMessage, message)
+		})
+	}
+}
+
+func TestKongHTTPValidator_ValidateService(t *testing.T) {
+	validator := KongHTTPValidator{}
+	service := func(anns map[string]string) corev1.Service {
+		return corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "svc", Annotations: anns}}
+	}
+
+	ok, message, err := validator.ValidateService(context.Background(), service(map[string]string{
+		"konghq.com/connect-timeout": "5000",
+		"konghq.com/read-timeout":    "300000",
+		"konghq.com/write-timeout":   "0",
+	}))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Empty(t, message)
+
+	ok, message, err = validator.ValidateService(context.Background(), service(map[string]string{
+		"konghq.com/write-timeout": "-1",
+	}))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, fmt.Sprintf(ErrTextAnnotationInvalid,
+		`konghq.com/write-timeout annotation "-1" must be a number of milliseconds between 0 and 2147483646`), message)
+
+	ok, message, err = validator.ValidateService(context.Background(), service(map[string]string{
+		"konghq.com/retries": "many",
+	}))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, fmt.Sprintf(ErrTextAnnotationInvalid,
+		`konghq.com/retries annotation "many" must be a number between 0 and 32767`), message)
+}
+
+func TestKongHTTPValidator_ValidateTCPIngress(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, netv1.AddToScheme(scheme))
+	require.NoError(t, configurationv1beta1.AddToScheme(scheme))
+
+	ingress := func(name, class string, rules ...configurationv1beta1.IngressRule) *configurationv1beta1.TCPIngress {
+		return &configurationv1beta1.TCPIngress{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "default",
+				Name:        name,
+				Annotations: map[string]string{annotations.IngressClassKey: class},
+			},
+			Spec: configurationv1beta1.TCPIngressSpec{Rules: rules},
+		}
+	}
+	rule := func(port int, host string) configurationv1beta1.IngressRule {
+		return configurationv1beta1.IngressRule{
+			Port:    port,
+			Host:    host,
+			Backend: configurationv1beta1.IngressBackend{ServiceName: "db", ServicePort: 5432},
+		}
+	}
+	managerClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		ingress("existing", annotations.DefaultIngressClass, rule(9000, "db.example.com")),
+		ingress("other-class", "other", rule(9001, "")),
+	).Build()
+
+	for _, tt := range []struct {
+		name        string
+		ingress     *configurationv1beta1.TCPIngress
+		wantOK      bool
+		wantMessage string
+	}{
+		{
+			name:    "valid rules are accepted",
+			ingress: ingress("new", annotations.DefaultIngressClass, rule(9000, "cache.example.com"), rule(9002, "")),
+			wantOK:  true,
+		},
+		{
+			name:        "invalid ports are rejected",
+			ingress:     ingress("new", annotations.DefaultIngressClass, rule(0, "")),
+			wantMessage: fmt.Sprintf(ErrTextL4IngressRuleInvalid, 0, "invalid port: 0"),
+		},
+		{
+			name: "empty service names are rejected",
+			ingress: ingress("new", annotations.DefaultIngressClass, configurationv1beta1.IngressRule{
+				Port:    9002,
+				Backend: configurationv1beta1.IngressBackend{ServicePort: 5432},
+			}),
+			wantMessage: fmt.Sprintf(ErrTextL4IngressRuleInvalid, 0, "empty serviceName"),
+		},
+		{
+			name:        "ports and hosts used by another TCPIngress of the class are rejected",
+			ingress:     ingress("new", annotations.DefaultIngressClass, rule(9002, ""), rule(9000, "DB.example.com")),
+			wantMessage: fmt.Sprintf(ErrTextTCPIngressPortDuplicate, 9000, "db.example.com", "default/existing"),
+		},
+		{
+			name:        "ports and hosts used twice by the TCPIngress are rejected",
+			ingress:     ingress("new", annotations.DefaultIngressClass, rule(9002, ""), rule(9002, "")),
+			wantMessage: fmt.Sprintf(ErrTextTCPIngressPortDuplicate, 9002, "", "default/new"),
+		},
+		{
+			name:    "updates of a TCPIngress don't conflict with itself",
+			ingress: ingress("existing", annotations.DefaultIngressClass, rule(9000, "db.example.com")),
+			wantOK:  true,
+		},
+		{
+			name:    "ports used by a TCPIngress of another class are accepted",
+			ingress: ingress("new", annotations.DefaultIngressClass, rule(9001, "")),
+			wantOK:  true,
+		},
+		{
+			name:    "TCPIngresses of another class are not validated",
+			ingress: ingress("new", "other", rule(0, "")),
+			wantOK:  true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := NewKongHTTPValidator(nil, nil, nil, managerClient, annotations.DefaultIngressClass)
+			ok, message, err := validator.ValidateTCPIngress(context.Background(), *tt.ingress)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantMessage, message)
+		})
+	}
+}
+
+func TestKongHTTPValidator_ValidateUDPIngress(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, netv1.AddToScheme(scheme))
+	require.NoError(t, configurationv1beta1.AddToScheme(scheme))
+
+	ingress := func(name string, rules ...configurationv1beta1.UDPIngressRule) *configurationv1beta1.UDPIngress {
+		return &configurationv1beta1.UDPIngress{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "default",
+				Name:        name,
+				Annotations: map[string]string{annotations.IngressClassKey: annotations.DefaultIngressClass},
+			},
+			Spec: configurationv1beta1.UDPIngressSpec{Rules: rules},
+		}
+	}
+	rule := func(port, endPort int) configurationv1beta1.UDPIngressRule {
+		return configurationv1beta1.UDPIngressRule{
+			Port:    port,
+			EndPort: endPort,
+			Backend: configurationv1beta1.IngressBackend{ServiceName: "dns", ServicePort: 53},
+		}
+	}
+	managerClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		ingress("existing", rule(10000, 10009)),
+	).Build()
+
+	for _, tt := range []struct {
+		name        string
+		ingress     *configurationv1beta1.UDPIngress
+		wantOK      bool
+		wantMessage string
+	}{
+		{
+			name:    "valid rules are accepted",
+			ingress: ingress("new", rule(9000, 0), rule(10010, 10019)),
+			wantOK:  true,
+		},
+		{
+			name:        "ranges ending before they start are rejected",
+			ingress:     ingress("new", rule(9000, 8999)),
+			wantMessage: fmt.Sprintf(ErrTextL4IngressRuleInvalid, 0, "invalid endPort: 8999"),
+		},
+		{
+			name:    "ranges of the maximum number of ports are accepted",
+			ingress: ingress("new", rule(1, 1000)),
+			wantOK:  true,
+		},
+		{
+			name:    "ranges exceeding the maximum number of ports are rejected",
+			ingress: ingress("new", rule(1, 1001)),
+			wantMessage: fmt.Sprintf(ErrTextL4IngressRuleInvalid, 0,
+				"the range of ports from 1 to 1001 exceeds the maximum of 1000 ports"),
+		},
+		{
+			name:        "ports in the range of another UDPIngress are rejected",
+			ingress:     ingress("new", rule(9995, 10000)),
+			wantMessage: fmt.Sprintf(ErrTextUDPIngressPortDuplicate, 10000, "default/existing"),
+		},
+		{
+			name:        "overlapping ranges of the UDPIngress are rejected",
+			ingress:     ingress("new", rule(9000, 9005), rule(9005, 0)),
+			wantMessage: fmt.Sprintf(ErrTextUDPIngressPortDuplicate, 9005, "default/new"),
+		},
+		{
+			name:    "updates of a UDPIngress don't conflict with itself",
+			ingress: ingress("existing", rule(10000, 10019)),
+			wantOK:  true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := NewKongHTTPValidator(nil, nil, nil, managerClient, annotations.DefaultIngressClass)
+			ok, message, err := validator.ValidateUDPIngress(context.Background(), *tt.ingress)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantMessage, message)
+		})
+	}
+}
+
+func TestKongHTTPValidator_ValidateRouteNamespaceQuota(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, netv1.AddToScheme(scheme))
+	require.NoError(t, configurationv1beta1.AddToScheme(scheme))
+	require.NoError(t, gatewayv1alpha2.AddToScheme(scheme))
+
+	classAnnotations := map[string]string{annotations.IngressClassKey: annotations.DefaultIngressClass}
+	pathType := netv1.PathTypePrefix
+	ingress := func(namespace, name string, paths ...string) *netv1.Ingress {
+		rule := netv1.IngressRule{
+			Host:             namespace + ".example.com",
+			IngressRuleValue: netv1.IngressRuleValue{HTTP: &netv1.HTTPIngressRuleValue{}},
+		}
+		for _, path := range paths {
+			rule.HTTP.Paths = append(rule.HTTP.Paths, netv1.HTTPIngressPath{
+				Path:     path,
+				PathType: &pathType,
+				Backend: netv1.IngressBackend{Service: &netv1.IngressServiceBackend{
+					Name: "web",
+					Port: netv1.ServiceBackendPort{Number: 80},
+				}},
+			})
+		}
+		return &netv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Annotations: classAnnotations},
+			Spec:       netv1.IngressSpec{Rules: []netv1.IngressRule{rule}},
+		}
+	}
+	udpIngress := func(name string, port, endPort int) *configurationv1beta1.UDPIngress {
+		return &configurationv1beta1.UDPIngress{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "full", Name: name, Annotations: classAnnotations},
+			Spec: configurationv1beta1.UDPIngressSpec{Rules: []configurationv1beta1.UDPIngressRule{{
+				Port:    port,
+				EndPort: endPort,
+				Backend: configurationv1beta1.IngressBackend{ServiceName: "dns", ServicePort: 53},
+			}}},
+		}
+	}
+	managerClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		ingress("full", "web", "/a", "/b"),
+		udpIngress("dns", 9000, 0),
+		&gatewayv1alpha2.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "roomy", Name: "api"},
+			Spec: gatewayv1alpha2.HTTPRouteSpec{
+				Hostnames: []gatewayv1alpha2.Hostname{"api.example.com"},
+				Rules:     []gatewayv1alpha2.HTTPRouteRule{{}},
+			},
+		},
+	).Build()
+	validator := NewKongHTTPValidator(nil, nil, nil, managerClient, annotations.DefaultIngressClass)
+	validator.NamespaceQuotas = util.NamespaceQuotas{MaxRoutes: 3}
+	quotaExceeded := func(namespace string) string {
+		return fmt.Sprintf(ErrTextNamespaceQuotaExceeded, namespace, 3, "routes")
+	}
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		name        string
+		validate    func() (bool, string, error)
+		wantOK      bool
+		wantMessage string
+	}{
+		{
+			name: "Ingresses fitting in the quota along with the HTTPRoutes of the namespace are accepted",
+			validate: func() (bool, string, error) {
+				return validator.ValidateIngress(ctx, *ingress("roomy", "web", "/a", "/b"))
+			},
+			wantOK: true,
+		},
+		{
+			name: "Ingresses exceeding the quota along with the HTTPRoutes of the namespace are rejected",
+			validate: func() (bool, string, error) {
+				return validator.ValidateIngress(ctx, *ingress("roomy", "web", "/a", "/b", "/c"))
+			},
+			wantMessage: quotaExceeded("roomy"),
+		},
+		{
+			name: "TCPIngresses exceeding the quota are rejected",
+			validate: func() (bool, string, error) {
+				return validator.ValidateTCPIngress(ctx, configurationv1beta1.TCPIngress{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "full", Name: "db", Annotations: classAnnotations},
+					Spec: configurationv1beta1.TCPIngressSpec{Rules: []configurationv1beta1.IngressRule{{
+						Port:    5432,
+						Backend: configurationv1beta1.IngressBackend{ServiceName: "db", ServicePort: 5432},
+					}}},
+				})
+			},
+			wantMessage: quotaExceeded("full"),
+		},
+		{
+			name: "UDPIngresses counting a route per port of their ranges are rejected",
+			validate: func() (bool, string, error) {
+				return validator.ValidateUDPIngress(ctx, *udpIngress("dns", 9000, 9001))
+			},
+			wantMessage: quotaExceeded("full"),
+		},
+		{
+			name: "updates which don't add routes are accepted",
+			validate: func() (bool, string, error) {
+				return validator.ValidateIngress(ctx, *ingress("full", "web", "/a", "/c"))
+			},
+			wantOK: true,
+		},
+		{
+			name: "updates adding routes beyond the quota are rejected",
+			validate: func() (bool, string, error) {
+				return validator.ValidateIngress(ctx, *ingress("full", "web", "/a", "/b", "/c"))
+			},
+			wantMessage: quotaExceeded("full"),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, message, err := tt.validate()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantMessage, message)
+		})
+	}
+}
@@ -54,6 +54,49 @@ const (
 	RequestBuffering     = "/request-buffering"
 	ResponseBuffering    = "/response-buffering"
 	HostAliasesKey       = "/host-aliases"
+	GRPCWebKey           = "/grpc-web"
+	RegexPrefixKey       = "/regex-prefix"
+	LegacyRegexPathKey   = "/legacy-regex-path"
+	TLSVerifyKey         = "/tls-verify"
+	TLSVerifyDepthKey    = "/tls-verify-depth"
+	ConnectTimeoutKey    = "/connect-timeout"
+	ReadTimeoutKey       = "/read-timeout"
+	WriteTimeoutKey      = "/write-timeout"
+	RetriesKey           = "/retries"
+	CACertificatesKey    = "/ca-certificates"
+	UpstreamPolicyKey    = "/upstream-policy"
+	DrainPolicyKey       = "/drain-policy"
+	APIVersionHeaderKey  = "/api-version-header"
+	HeadersKey           = "/headers"
+	RewriteKey           = "/rewrite"
+	UpstreamNameKey      = "/upstream-name"
+	CatchAllPluginsKey   = "/catch-all-plugins"
+	DisabledKey          = "/disabled"
+	ConsumerGroupsKey    = "/consumer-groups"
+
+	CORSAllowOriginsKey     = "/cors-allow-origins"
+	CORSAllowMethodsKey     = "/cors-allow-methods"
+	CORSAllowHeadersKey     = "/cors-allow-headers"
+	CORSExposeHeadersKey    = "/cors-expose-headers"
+	CORSAllowCredentialsKey = "/cors-allow-credentials"
+	CORSMaxAgeKey           = "/cors-max-age"
+
+	CertManagerIssuerKey        = "/cert-manager-issuer"
+	CertManagerClusterIssuerKey = "/cert-manager-cluster-issuer"
+
+	UpstreamHashOnKey             = "/upstream-hash-on"
+	UpstreamHashOnHeaderKey       = "/upstream-hash-on-header"
+	UpstreamHashOnCookieKey       = "/upstream-hash-on-cookie"
+	UpstreamHashOnCookiePathKey   = "/upstream-hash-on-cookie-path"
+	UpstreamHashFallbackKey       = "/upstream-hash-fallback"
+	UpstreamHashFallbackHeaderKey = "/upstream-hash-fallback-header"
+
+	HealthchecksActivePathKey               = "/healthchecks-active-path"
+	HealthchecksActiveIntervalKey           = "/healthchecks-active-interval"
+	HealthchecksActiveHealthySuccessesKey   = "/healthchecks-active-healthy-successes"
+	HealthchecksActiveUnhealthyFailuresKey  = "/healthchecks-active-unhealthy-failures"
+	HealthchecksPassiveUnhealthyFailuresKey = "/healthchecks-passive-unhealthy-failures"
+	HealthchecksPassiveUnhealthyTimeoutsKey = "/healthchecks-passive-unhealthy-timeouts"
 
 	// GatewayUnmanagedAnnotation is an annotation used on a Gateway resource to
 	// indicate that the Gateway should be reconciled according to unmanaged
@@ -142,10 +185,18 @@ func ExtractProtocolName(anns map[string]string) string {
 	return anns[AnnotationPrefix+ProtocolKey]
 }
 
-// ExtractProtocolNames extracts the protocols supplied in the annotation
+// ExtractProtocolNames extracts the protocols supplied in the annotation,
+// lowercased and stripped of surrounding whitespace, if any.
 func ExtractProtocolNames(anns map[string]string) []string {
-	val := anns[AnnotationPrefix+ProtocolsKey]
-	return strings.Split(val, ",")
+	val := strings.TrimSpace(anns[AnnotationPrefix+ProtocolsKey])
+	if val == "" {
+		return nil
+	}
+	protocols := strings.Split(val, ",")
+	for i, protocol := range protocols {
+		protocols[i] = strings.ToLower(strings.TrimSpace(protocol))
+	}
+	return protocols
 }
 
 // ExtractClientCertificate extracts the secret name containing the
@@ -243,6 +294,287 @@ func ExtractHostAliases(anns map[string]string) ([]string, bool) {
 	return strings.Split(val, ","), true
 }
 
+// ExtractGRPCWeb extracts the boolean annotation indicating whether or not
+// a route should handle gRPC-Web requests.
+func ExtractGRPCWeb(anns map[string]string) (string, bool) {
+	s, ok := anns[AnnotationPrefix+GRPCWebKey]
+	return s, ok
+}
+
+// ExtractRegexPrefix extracts the prefix which marks paths as regex paths.
+func ExtractRegexPrefix(anns map[string]string) (string, bool) {
+	s, ok := anns[AnnotationPrefix+RegexPrefixKey]
+	return s, ok
+}
+
+// ExtractLegacyRegexPath extracts the value of the annotation which requests
+// regex paths to be detected the way Kong versions prior to 3.0 did.
+func ExtractLegacyRegexPath(anns map[string]string) string {
+	return anns[AnnotationPrefix+LegacyRegexPathKey]
+}
+
+// ExtractTLSVerify extracts the boolean annotation indicating whether the
+// TLS certificate of the upstream server should be verified.
+func ExtractTLSVerify(anns map[string]string) (string, bool) {
+	s, ok := anns[AnnotationPrefix+TLSVerifyKey]
+	return s, ok
+}
+
+// ExtractTLSVerifyDepth extracts the maximum depth of the certificate chain
+// allowed when verifying the TLS certificate of the upstream server.
+func ExtractTLSVerifyDepth(anns map[string]string) (string, bool) {
+	s, ok := anns[AnnotationPrefix+TLSVerifyDepthKey]
+	return s, ok
+}
+
+// ExtractConnectTimeout extracts the timeout, in milliseconds, for
+// establishing a connection to the upstream server.
+func ExtractConnectTimeout(anns map[string]string) (string, bool) {
+	s, ok := anns[AnnotationPrefix+ConnectTimeoutKey]
+	return s, ok
+}
+
+// ExtractReadTimeout extracts the timeout, in milliseconds, between two
+// successive read operations when receiving a response from the upstream server.
+func ExtractReadTimeout(anns map[string]string) (string, bool) {
+	s, ok := anns[AnnotationPrefix+ReadTimeoutKey]
+	return s, ok
+}
+
+// ExtractWriteTimeout extracts the timeout, in milliseconds, between two
+// successive write operations when sending a request to the upstream server.
+func ExtractWriteTimeout(anns map[string]string) (string, bool) {
+	s, ok := anns[AnnotationPrefix+WriteTimeoutKey]
+	return s, ok
+}
+
+// ExtractRetries extracts the number of times Kong retries proxying a request
+// to the upstream server after a failure.
+func ExtractRetries(anns map[string]string) (string, bool) {
+	s, ok := anns[AnnotationPrefix+RetriesKey]
+	return s, ok
+}
+
+// ExtractAPIVersionHeader extracts the header (and the versions it must
+// match, e.g. "X-API-Version=v2") routes should match API versions with.
+func ExtractAPIVersionHeader(anns map[string]string) (string, bool) {
+	s, ok := anns[AnnotationPrefix+APIVersionHeaderKey]
+	return s, ok
+}
+
+// ExtractHeaders extracts the values of the headers.* annotations (e.g.
+// "konghq.com/headers.x-api-version": "2"), keyed by the header routes must
+// match them in.
+func ExtractHeaders(anns map[string]string) map[string]string {
+	prefix := AnnotationPrefix + HeadersKey + "."
+	var headers map[string]string
+	for key, value := range anns {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if headers == nil {
+			headers = make(map[string]string)
+		}
+		headers[strings.TrimPrefix(key, prefix)] = value
+	}
+	return headers
+}
+
+// ExtractRewrite extracts the path requests are proxied to the upstream
+// with, in place of the path they match.
+func ExtractRewrite(anns map[string]string) (string, bool) {
+	s, ok := anns[AnnotationPrefix+RewriteKey]
+	return s, ok
+}
+
+// ExtractCORSAllowOrigins extracts the comma-separated origins allowed to
+// make cross-origin requests, which enables CORS when set.
+func ExtractCORSAllowOrigins(anns map[string]string) (string, bool) {
+	s, ok := anns[AnnotationPrefix+CORSAllowOriginsKey]
+	return s, ok
+}
+
+// ExtractCORSAllowMethods extracts the comma-separated methods allowed in
+// cross-origin requests.
+func ExtractCORSAllowMethods(anns map[string]string) (string, bool) {
+	s, ok := anns[AnnotationPrefix+CORSAllowMethodsKey]
+	return s, ok
+}
+
+// ExtractCORSAllowHeaders extracts the comma-separated headers allowed in
+// cross-origin requests.
+func ExtractCORSAllowHeaders(anns map[string]string) (string, bool) {
+	s, ok := anns[AnnotationPrefix+CORSAllowHeadersKey]
+	return s, ok
+}
+
+// ExtractCORSExposeHeaders extracts the comma-separated response headers
+// exposed to cross-origin requests.
+func ExtractCORSExposeHeaders(anns map[string]string) (string, bool) {
+	s, ok := anns[AnnotationPrefix+CORSExposeHeadersKey]
+	return s, ok
+}
+
+// ExtractCORSAllowCredentials extracts whether cross-origin requests may
+// include credentials, "true" or "false".
+func ExtractCORSAllowCredentials(anns map[string]string) (string, bool) {
+	s, ok := anns[AnnotationPrefix+CORSAllowCredentialsKey]
+	return s, ok
+}
+
+// ExtractCORSMaxAge extracts the number of seconds browsers may cache the
+// result of preflight requests for.
+func ExtractCORSMaxAge(anns map[string]string) (string, bool) {
+	s, ok := anns[AnnotationPrefix+CORSMaxAgeKey]
+	return s, ok
+}
+
+// ExtractUpstreamName extracts the name of the Kong upstream, managed outside
+// of Kubernetes, the routes of an Ingress proxy to instead of its backends.
+func ExtractUpstreamName(anns map[string]string) string {
+	return anns[AnnotationPrefix+UpstreamNameKey]
+}
+
+// ExtractCatchAllPlugins extracts the comma-separated names of the KongPlugins
+// attached to the routes matching the requests no path of the hosts of an
+// Ingress matches.
+func ExtractCatchAllPlugins(anns map[string]string) string {
+	return anns[AnnotationPrefix+CatchAllPluginsKey]
+}
+
+// ExtractCACertificates extracts the names of the Secrets (or KongCACertificates)
+// containing the CA certificates used to verify the TLS certificate of the
+// upstream server.
+func ExtractCACertificates(anns map[string]string) []string {
+	var secretNames []string
+	for _, name := range strings.Split(anns[AnnotationPrefix+CACertificatesKey], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			secretNames = append(secretNames, name)
+		}
+	}
+	return secretNames
+}
+
+// ExtractUpstreamHashOn extracts what the upstream hashes on to pick targets
+// (none, consumer, ip, header or cookie).
+func ExtractUpstreamHashOn(anns map[string]string) string {
+	return anns[AnnotationPrefix+UpstreamHashOnKey]
+}
+
+// ExtractUpstreamHashOnHeader extracts the name of the header the upstream
+// hashes on.
+func ExtractUpstreamHashOnHeader(anns map[string]string) string {
+	return anns[AnnotationPrefix+UpstreamHashOnHeaderKey]
+}
+
+// ExtractUpstreamHashOnCookie extracts the name of the cookie the upstream
+// hashes on.
+func ExtractUpstreamHashOnCookie(anns map[string]string) string {
+	return anns[AnnotationPrefix+UpstreamHashOnCookieKey]
+}
+
+// ExtractUpstreamHashOnCookiePath extracts the path of the cookie the upstream
+// sets when hashing on a cookie.
+func ExtractUpstreamHashOnCookiePath(anns map[string]string) string {
+	return anns[AnnotationPrefix+UpstreamHashOnCookiePathKey]
+}
+
+// ExtractUpstreamHashFallback extracts what the upstream hashes on when the
+// primary input is missing (none, consumer, ip or header).
+func ExtractUpstreamHashFallback(anns map[string]string) string {
+	return anns[AnnotationPrefix+UpstreamHashFallbackKey]
+}
+
+// ExtractUpstreamHashFallbackHeader extracts the name of the header the
+// upstream hashes on when the primary input is missing.
+func ExtractUpstreamHashFallbackHeader(anns map[string]string) string {
+	return anns[AnnotationPrefix+UpstreamHashFallbackHeaderKey]
+}
+
+// ExtractHealthchecksActivePath extracts the path active health checks probe.
+func ExtractHealthchecksActivePath(anns map[string]string) string {
+	return anns[AnnotationPrefix+HealthchecksActivePathKey]
+}
+
+// ExtractHealthchecksActiveInterval extracts the interval (in seconds) between
+// active health check probes.
+func ExtractHealthchecksActiveInterval(anns map[string]string) (string, bool) {
+	s, ok := anns[AnnotationPrefix+HealthchecksActiveIntervalKey]
+	return s, ok
+}
+
+// ExtractHealthchecksActiveHealthySuccesses extracts the number of successful
+// active probes after which a target is considered healthy.
+func ExtractHealthchecksActiveHealthySuccesses(anns map[string]string) (string, bool) {
+	s, ok := anns[AnnotationPrefix+HealthchecksActiveHealthySuccessesKey]
+	return s, ok
+}
+
+// ExtractHealthchecksActiveUnhealthyFailures extracts the number of failed
+// active probes after which a target is considered unhealthy.
+func ExtractHealthchecksActiveUnhealthyFailures(anns map[string]string) (string, bool) {
+	s, ok := anns[AnnotationPrefix+HealthchecksActiveUnhealthyFailuresKey]
+	return s, ok
+}
+
+// ExtractHealthchecksPassiveUnhealthyFailures extracts the number of failed
+// proxied requests after which a target is considered unhealthy.
+func ExtractHealthchecksPassiveUnhealthyFailures(anns map[string]string) (string, bool) {
+	s, ok := anns[AnnotationPrefix+HealthchecksPassiveUnhealthyFailuresKey]
+	return s, ok
+}
+
+// ExtractHealthchecksPassiveUnhealthyTimeouts extracts the number of timed out
+// proxied requests after which a target is considered unhealthy.
+func ExtractHealthchecksPassiveUnhealthyTimeouts(anns map[string]string) (string, bool) {
+	s, ok := anns[AnnotationPrefix+HealthchecksPassiveUnhealthyTimeoutsKey]
+	return s, ok
+}
+
+// ExtractUpstreamPolicyName extracts the name of the KongUpstreamPolicy object
+// that configures the load balancing of the Upstream of a Service.
+func ExtractUpstreamPolicyName(anns map[string]string) string {
+	return anns[AnnotationPrefix+UpstreamPolicyKey]
+}
+
+// ExtractDrainPolicy extracts how terminating endpoints of a Service are
+// targeted.
+func ExtractDrainPolicy(anns map[string]string) string {
+	return anns[AnnotationPrefix+DrainPolicyKey]
+}
+
+// ExtractDisabled extracts the disabled annotation value, the boolean string
+// "true" putting the Kong services or routes of an object in maintenance mode.
+func ExtractDisabled(anns map[string]string) string {
+	return anns[AnnotationPrefix+DisabledKey]
+}
+
+// ExtractConsumerGroups extracts the names of the consumer groups listed by
+// the consumer-groups annotation. On a KongConsumer, they are the groups the
+// consumer belongs to, in order of precedence; on a KongClusterPlugin, the
+// groups whose consumers the plugin is attached to.
+func ExtractConsumerGroups(anns map[string]string) []string {
+	var groups []string
+	for _, group := range strings.Split(anns[AnnotationPrefix+ConsumerGroupsKey], ",") {
+		if group = strings.TrimSpace(group); group != "" {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// ExtractCertManagerIssuer extracts the name of the cert-manager Issuer, in
+// the namespace of the object, issuing the certificate for its hosts.
+func ExtractCertManagerIssuer(anns map[string]string) string {
+	return anns[AnnotationPrefix+CertManagerIssuerKey]
+}
+
+// ExtractCertManagerClusterIssuer extracts the name of the cert-manager
+// ClusterIssuer issuing the certificate for the hosts of an object.
+func ExtractCertManagerClusterIssuer(anns map[string]string) string {
+	return anns[AnnotationPrefix+CertManagerClusterIssuerKey]
+}
+
 // ExtractUnmanagedGatewayMode extracts the value of the unmanaged gateway
 // mode annotation.
 func ExtractUnmanagedGatewayMode(anns map[string]string) (string, bool) {
@@ -230,6 +230,22 @@ func TestExtractProtocolNames(t *testing.T) {
 			},
 			want: []string{"foo", "bar"},
 		},
+		{
+			name: "whitespace and case",
+			args: args{
+				anns: map[string]string{
+					"konghq.com/protocols": " HTTPS , grpcs",
+				},
+			},
+			want: []string{"https", "grpcs"},
+		},
+		{
+			name: "empty",
+			args: args{
+				anns: map[string]string{},
+			},
+			want: nil,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -684,3 +700,122 @@ func TestExtractHostAliases(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractGRPCWeb(t *testing.T) {
+	type args struct {
+		anns map[string]string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "empty",
+			want: "",
+		},
+		{
+			name: "non-empty",
+			args: args{
+				anns: map[string]string{
+					"konghq.com/grpc-web": "true",
+				},
+			},
+			want: "true",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ExtractGRPCWeb(tt.args.anns)
+			if tt.want == "" {
+				assert.False(t, ok)
+			} else {
+				assert.True(t, ok)
+			}
+			if got != tt.want {
+				t.Errorf("ExtractGRPCWeb() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractRegexPrefix(t *testing.T) {
+	type args struct {
+		anns map[string]string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "empty",
+			want: "",
+		},
+		{
+			name: "non-empty",
+			args: args{
+				anns: map[string]string{
+					"konghq.com/regex-prefix": "/regex",
+				},
+			},
+			want: "/regex",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ExtractRegexPrefix(tt.args.anns)
+			if tt.want == "" {
+				assert.False(t, ok)
+			} else {
+				assert.True(t, ok)
+			}
+			if got != tt.want {
+				t.Errorf("ExtractRegexPrefix() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractCACertificates(t *testing.T) {
+	assert.Nil(t, ExtractCACertificates(map[string]string{}))
+	assert.Equal(t, []string{"ca-1", "ca-2"}, ExtractCACertificates(map[string]string{
+		"konghq.com/ca-certificates": "ca-1, ca-2,",
+	}))
+}
+
+func TestExtractAPIVersionHeader(t *testing.T) {
+	_, ok := ExtractAPIVersionHeader(map[string]string{})
+	assert.False(t, ok)
+	got, ok := ExtractAPIVersionHeader(map[string]string{
+		"konghq.com/api-version-header": "X-API-Version=v2",
+	})
+	assert.True(t, ok)
+	assert.Equal(t, "X-API-Version=v2", got)
+}
+
+func TestExtractHeaders(t *testing.T) {
+	assert.Nil(t, ExtractHeaders(map[string]string{"konghq.com/api-version-header": "X-API-Version=v2"}))
+	assert.Equal(t, map[string]string{"x-api-version": "2", "x-tenant": "acme, globex"}, ExtractHeaders(map[string]string{
+		"konghq.com/headers.x-api-version": "2",
+		"konghq.com/headers.x-tenant":      "acme, globex",
+		"konghq.com/headers":               "ignored",
+	}))
+}
+
+func TestExtractRewrite(t *testing.T) {
+	_, ok := ExtractRewrite(map[string]string{})
+	assert.False(t, ok)
+	got, ok := ExtractRewrite(map[string]string{
+		"konghq.com/rewrite": "/api/$1",
+	})
+	assert.True(t, ok)
+	assert.Equal(t, "/api/$1", got)
+}
+
+func TestExtractConsumerGroups(t *testing.T) {
+	assert.Nil(t, ExtractConsumerGroups(map[string]string{}))
+	assert.Equal(t, []string{"gold", "beta"}, ExtractConsumerGroups(map[string]string{
+		"konghq.com/consumer-groups": " gold,beta, ",
+	}))
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2022 Kong, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LabelServiceName is the label of the EndpointSlices imported for a
+// ServiceImport, holding the name of the ServiceImport.
+const LabelServiceName = "multicluster.kubernetes.io/service-name"
+
+func init() {
+	SchemeBuilder.Register(&ServiceImport{}, &ServiceImportList{})
+}
+
+//+kubebuilder:object:root=true
+
+// ServiceImportList contains a list of ServiceImport
+type ServiceImportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ServiceImport `json:"items"`
+}
+
+//+kubebuilder:object:root=true
+
+// ServiceImport describes a service imported from the clusters in a ClusterSet.
+type ServiceImport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ServiceImportSpec `json:"spec,omitempty"`
+}
+
+// ServiceImportType designates the type of a ServiceImport.
+type ServiceImportType string
+
+const (
+	// ClusterSetIP services are only accessible via the ClusterSet IP.
+	ClusterSetIP ServiceImportType = "ClusterSetIP"
+	// Headless services allow backend pods to be addressed directly.
+	Headless ServiceImportType = "Headless"
+)
+
+// ServiceImportSpec describes an imported service and the information
+// necessary to consume it.
+type ServiceImportSpec struct {
+	Ports []ServicePort `json:"ports"`
+
+	// IPs are the ClusterSet IPs of a ClusterSetIP ServiceImport.
+	IPs []string `json:"ips,omitempty"`
+
+	// Type defines the type of this service.
+	Type ServiceImportType `json:"type"`
+}
+
+// ServicePort represents the port on which the service is exposed.
+type ServicePort struct {
+	Name        string          `json:"name,omitempty"`
+	Protocol    corev1.Protocol `json:"protocol,omitempty"`
+	AppProtocol *string         `json:"appProtocol,omitempty"`
+	Port        int32           `json:"port"`
+}
@@ -7,7 +7,7 @@ import (
 )
 
 func Run(c *manager.Config) error {
-	ctx, err := SetupSignalHandler(c)
+	ctx, terminating, err := SetupSignalHandler(c)
 	if err != nil {
 		return fmt.Errorf("failed to setup signal handler: %w", err)
 	}
@@ -16,5 +16,5 @@ func Run(c *manager.Config) error {
 	if err != nil {
 		return fmt.Errorf("failed to start diagnostics server: %w", err)
 	}
-	return manager.Run(ctx, c, diag.ConfigDumps)
+	return manager.Run(ctx, c, diag.ConfigDumps, diag.FeatureReports, terminating)
 }
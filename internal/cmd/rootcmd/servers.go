@@ -36,11 +36,13 @@ func StartDiagnosticsServer(ctx context.Context, port int, c *manager.Config) (d
 		Logger:           logger,
 		ProfilingEnabled: c.EnableProfiling,
 		ConfigLock:       &sync.RWMutex{},
+		FeatureReports:   make(chan util.FeatureReport, DiagnosticConfigBufferDepth),
 	}
 	if c.EnableConfigDumps {
 		s.ConfigDumps = util.ConfigDumpDiagnostic{
 			DumpsIncludeSensitive: c.DumpSensitiveConfig,
 			Configs:               make(chan util.ConfigDump, DiagnosticConfigBufferDepth),
+			TranslationFailures:   make(chan []util.TranslationFailureReport, DiagnosticConfigBufferDepth),
 		}
 	}
 	go func() {
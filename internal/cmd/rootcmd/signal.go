@@ -22,27 +22,30 @@ var shutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
 // SetupSignalHandler registers for SIGTERM and SIGINT. A context is returned
 // which is canceled on one of these signals. If a second signal is not caught, the program
 // will delay for the configured period of time before terminating. If a second signal is caught,
-// the program is terminated with exit code 1.
-func SetupSignalHandler(cfg *manager.Config) (context.Context, error) {
+// the program is terminated with exit code 1. The returned channel is closed as soon as the
+// first signal is caught, before the delay.
+func SetupSignalHandler(cfg *manager.Config) (context.Context, <-chan struct{}, error) {
 
 	// This will prevent multiple signal handlers from being created
 	if ok := mutex.TryLock(); !ok {
-		return nil, errors.New("signal handler can only be setup once")
+		return nil, nil, errors.New("signal handler can only be setup once")
 	}
 
 	deprecatedLogger, err := util.MakeLogger(cfg.LogLevel, cfg.LogFormat)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	logger := logrusr.New(deprecatedLogger)
 
 	ctx, cancel := context.WithCancel(context.Background())
+	terminating := make(chan struct{})
 
 	c := make(chan os.Signal, 2)
 	signal.Notify(c, shutdownSignals...)
 	go func() {
 		<-c
 		logger.Info("Signal received, shutting down", "timeout", fmt.Sprint(cfg.TermDelay))
+		close(terminating)
 
 		select {
 		case <-time.After(cfg.TermDelay):
@@ -57,5 +60,5 @@ func SetupSignalHandler(cfg *manager.Config) (context.Context, error) {
 		os.Exit(1) // second signal. Exit directly.
 	}()
 
-	return ctx, nil
+	return ctx, terminating, nil
 }
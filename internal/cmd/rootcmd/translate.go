@@ -0,0 +1,73 @@
+package rootcmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kong/deck/file"
+	"github.com/spf13/cobra"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/manager"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+)
+
+var (
+	translateCfg    manager.Config
+	translateOutput string
+)
+
+func init() {
+	flagSet := translateCfg.FlagSet()
+	for _, name := range []string{"ingress-class", "feature-gates", "kong-admin-filter-tag", "log-level", "log-format"} {
+		translateCmd.Flags().AddFlag(flagSet.Lookup(name))
+	}
+	translateCmd.Flags().StringVarP(&translateOutput, "output", "o", "yaml", `Format of the configuration. Allowed values are yaml and json.`)
+	rootCmd.AddCommand(translateCmd)
+}
+
+var translateCmd = &cobra.Command{
+	Use:   "translate [FILE...]",
+	Short: "Translate Kubernetes manifests into Kong declarative configuration",
+	Long: `Translate reads Kubernetes objects from YAML or JSON manifests and prints the
+Kong declarative configuration the controller would apply for them, without
+contacting a cluster or a Kong Admin API. Manifests are read from the given
+files, or from the standard input if there are none or the file is "-". It
+fails if some objects couldn't be translated, so that it can validate changes
+in CI.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var format file.Format
+		switch strings.ToLower(translateOutput) {
+		case "yaml":
+			format = file.YAML
+		case "json":
+			format = file.JSON
+		default:
+			return fmt.Errorf("invalid --output %q: allowed values are yaml and json", translateOutput)
+		}
+		logger, err := util.MakeLogger(translateCfg.LogLevel, translateCfg.LogFormat)
+		if err != nil {
+			return fmt.Errorf("failed to make logger: %w", err)
+		}
+
+		if len(args) == 0 {
+			args = []string{"-"}
+		}
+		manifests := make([]io.Reader, 0, len(args))
+		for _, name := range args {
+			if name == "-" {
+				manifests = append(manifests, cmd.InOrStdin())
+				continue
+			}
+			f, err := os.Open(name)
+			if err != nil {
+				return fmt.Errorf("failed to read manifests: %w", err)
+			}
+			defer f.Close()
+			manifests = append(manifests, f)
+		}
+		return manager.Translate(&translateCfg, logger, cmd.OutOrStdout(), format, manifests...)
+	},
+	SilenceUsage: true,
+}
@@ -0,0 +1,189 @@
+package configuration
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	ctrlutils "github.com/kong/kubernetes-ingress-controller/v2/internal/controllers/utils"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+)
+
+// -----------------------------------------------------------------------------
+// cert-manager - Certificate Reconcilers
+// -----------------------------------------------------------------------------
+
+// CertManagerIngressReconciler maintains a cert-manager Certificate for the
+// hosts of each Ingress annotated with konghq.com/cert-manager-issuer or
+// konghq.com/cert-manager-cluster-issuer that none of its TLS sections cover.
+// The parser serves the Secret the Certificate is issued to for those hosts,
+// so that hosts added to an Ingress get a certificate without writing one.
+type CertManagerIngressReconciler struct {
+	client.Client
+
+	Log              logr.Logger
+	Scheme           *runtime.Scheme
+	IngressClassName string
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CertManagerIngressReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c, err := controller.New("CertManagerIngress", mgr, controller.Options{
+		Reconciler: r,
+		LogConstructor: func(_ *reconcile.Request) logr.Logger {
+			return r.Log
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err := c.Watch(
+		&source.Kind{Type: newCertManagerCertificate()},
+		&handler.EnqueueRequestForOwner{OwnerType: &netv1.Ingress{}, IsController: true},
+	); err != nil {
+		return err
+	}
+	return c.Watch(
+		&source.Kind{Type: &netv1.Ingress{}},
+		&handler.EnqueueRequestForObject{},
+		ctrlutils.GeneratePredicateFuncsForIngressClassFilter(r.IngressClassName),
+	)
+}
+
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile processes the watched objects
+func (r *CertManagerIngressReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ingress := new(netv1.Ingress)
+	if err := r.Get(ctx, req.NamespacedName, ingress); err != nil {
+		// the Certificate of a deleted Ingress is garbage collected
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	log := r.Log.WithValues("CertManagerIngress", req.NamespacedName)
+	return ctrl.Result{}, reconcileCertManagerCertificate(ctx, r.Client, r.Scheme, log, ingress)
+}
+
+// CertManagerHTTPRouteReconciler maintains a cert-manager Certificate for the
+// hostnames of each HTTPRoute annotated with konghq.com/cert-manager-issuer or
+// konghq.com/cert-manager-cluster-issuer.
+type CertManagerHTTPRouteReconciler struct {
+	client.Client
+
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CertManagerHTTPRouteReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c, err := controller.New("CertManagerHTTPRoute", mgr, controller.Options{
+		Reconciler: r,
+		LogConstructor: func(_ *reconcile.Request) logr.Logger {
+			return r.Log
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err := c.Watch(
+		&source.Kind{Type: newCertManagerCertificate()},
+		&handler.EnqueueRequestForOwner{OwnerType: &gatewayv1alpha2.HTTPRoute{}, IsController: true},
+	); err != nil {
+		return err
+	}
+	return c.Watch(
+		&source.Kind{Type: &gatewayv1alpha2.HTTPRoute{}},
+		&handler.EnqueueRequestForObject{},
+	)
+}
+
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile processes the watched objects
+func (r *CertManagerHTTPRouteReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	httproute := new(gatewayv1alpha2.HTTPRoute)
+	if err := r.Get(ctx, req.NamespacedName, httproute); err != nil {
+		// the Certificate of a deleted HTTPRoute is garbage collected
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	log := r.Log.WithValues("CertManagerHTTPRoute", req.NamespacedName)
+	return ctrl.Result{}, reconcileCertManagerCertificate(ctx, r.Client, r.Scheme, log, httproute)
+}
+
+// newCertManagerCertificate provides an empty cert-manager Certificate, which
+// is handled as an unstructured object to not depend on the cert-manager API.
+func newCertManagerCertificate() *unstructured.Unstructured {
+	cert := &unstructured.Unstructured{}
+	cert.SetGroupVersionKind(util.CertManagerCertificateGVK)
+	return cert
+}
+
+// reconcileCertManagerCertificate creates or updates the Certificate for the
+// hosts of an object requesting one, which the object owns. It deletes the
+// Certificate once the object no longer requests it or has no hosts lacking
+// TLS.
+func reconcileCertManagerCertificate(
+	ctx context.Context,
+	c client.Client,
+	scheme *runtime.Scheme,
+	log logr.Logger,
+	owner client.Object,
+) error {
+	cert := newCertManagerCertificate()
+	cert.SetNamespace(owner.GetNamespace())
+	cert.SetName(util.CertManagerSecretName(owner))
+
+	issuer, ok := util.CertManagerIssuerFromAnnotations(owner.GetAnnotations())
+	hosts := util.CertManagerHosts(owner)
+	if !ok || len(hosts) == 0 {
+		if err := c.Get(ctx, client.ObjectKeyFromObject(cert), cert); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		// a Certificate with the same name which the object doesn't own isn't ours to delete
+		if !metav1.IsControlledBy(cert, owner) {
+			return nil
+		}
+		log.V(util.DebugLevel).Info("deleting the cert-manager Certificate no longer requested", "certificate", cert.GetName())
+		return client.IgnoreNotFound(c.Delete(ctx, cert))
+	}
+
+	dnsNames := make([]interface{}, 0, len(hosts))
+	for _, host := range hosts {
+		dnsNames = append(dnsNames, host)
+	}
+	result, err := controllerutil.CreateOrUpdate(ctx, c, cert, func() error {
+		// fails for Certificates controlled by other objects, which are left alone
+		if err := controllerutil.SetControllerReference(owner, cert, scheme); err != nil {
+			return err
+		}
+		return unstructured.SetNestedField(cert.Object, map[string]interface{}{
+			"secretName": cert.GetName(),
+			"dnsNames":   dnsNames,
+			"issuerRef": map[string]interface{}{
+				"name":  issuer.Name,
+				"kind":  issuer.Kind,
+				"group": util.CertManagerCertificateGVK.Group,
+			},
+		}, "spec")
+	})
+	if err != nil {
+		return err
+	}
+	if result != controllerutil.OperationResultNone {
+		log.V(util.DebugLevel).Info("cert-manager Certificate "+string(result), "certificate", cert.GetName(), "hosts", hosts)
+	}
+	return nil
+}
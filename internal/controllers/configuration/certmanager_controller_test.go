@@ -0,0 +1,76 @@
+package configuration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCertManagerIngressReconciler(t *testing.T) {
+	ctx := context.Background()
+	ingress := &netv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        "foo",
+			UID:         "uid",
+			Annotations: map[string]string{"konghq.com/cert-manager-cluster-issuer": "letsencrypt"},
+		},
+		Spec: netv1.IngressSpec{
+			TLS: []netv1.IngressTLS{{Hosts: []string{"secure.example.com"}, SecretName: "secure"}},
+			Rules: []netv1.IngressRule{
+				{Host: "secure.example.com"},
+				{Host: "example.com"},
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(ingress).Build()
+	r := &CertManagerIngressReconciler{Client: c, Log: logr.Discard(), Scheme: scheme.Scheme}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "foo"}}
+	key := client.ObjectKey{Namespace: "default", Name: "kong-ingress-foo"}
+
+	t.Log("creating the Certificate for the hosts lacking TLS")
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	cert := newCertManagerCertificate()
+	require.NoError(t, c.Get(ctx, key, cert))
+	assert.True(t, metav1.IsControlledBy(cert, ingress))
+	spec, _, err := unstructured.NestedMap(cert.Object, "spec")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"secretName": "kong-ingress-foo",
+		"dnsNames":   []interface{}{"example.com"},
+		"issuerRef": map[string]interface{}{
+			"name":  "letsencrypt",
+			"kind":  "ClusterIssuer",
+			"group": "cert-manager.io",
+		},
+	}, spec)
+
+	t.Log("updating the Certificate when hosts are added")
+	ingress.Spec.Rules = append(ingress.Spec.Rules, netv1.IngressRule{Host: "new.example.com"})
+	require.NoError(t, c.Update(ctx, ingress))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, key, cert))
+	dnsNames, _, err := unstructured.NestedStringSlice(cert.Object, "spec", "dnsNames")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example.com", "new.example.com"}, dnsNames)
+
+	t.Log("deleting the Certificate when it's no longer requested")
+	ingress.Annotations = nil
+	require.NoError(t, c.Update(ctx, ingress))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Error(t, c.Get(ctx, key, newCertManagerCertificate()))
+}
@@ -0,0 +1,84 @@
+package configuration
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	knativev1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	ctrlutils "github.com/kong/kubernetes-ingress-controller/v2/internal/controllers/utils"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+)
+
+// -----------------------------------------------------------------------------
+// Knative Ingress - Status Updates
+// -----------------------------------------------------------------------------
+
+// knativeProbeInterval is how long to wait before probing Kong again for the
+// routes of a Knative Ingress which aren't live yet.
+const knativeProbeInterval = time.Second
+
+// updateKnativeIngressStatus reports the status of a Knative Ingress which has
+// been configured in the data-plane. Knative gates rollouts on the Ingress
+// being Ready for its current generation, so it's only marked Ready once the
+// configuration for its current generation has been applied and its routes
+// are confirmed live in Kong, and is marked not ready meanwhile.
+func (r *Knativev1alpha1IngressReconciler) updateKnativeIngressStatus(
+	ctx context.Context,
+	log logr.Logger,
+	obj *knativev1alpha1.Ingress,
+	addrs []corev1.LoadBalancerIngress,
+) (ctrl.Result, error) {
+	var live bool
+	if r.DataplaneClient.KubernetesObjectGenerationIsConfigured(obj) {
+		var err error
+		live, err = r.DataplaneClient.KongRoutesExist(ctx, parser.KnativeIngressRouteNames(obj)...)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	var result ctrl.Result
+	if !live {
+		log.V(util.DebugLevel).Info("routes not yet live in the data-plane", "namespace", obj.Namespace, "name", obj.Name, "generation", obj.Generation)
+		result.RequeueAfter = knativeProbeInterval // probe again until the routes are live
+	}
+
+	status := knativeIngressStatus(obj, addrs, live)
+	if equality.Semantic.DeepEqual(status, obj.Status) {
+		log.V(util.DebugLevel).Info("status update not needed", "namespace", obj.Namespace, "name", obj.Name)
+		return result, nil
+	}
+	obj.Status = status
+	if statusResult, err := ctrlutils.StatusUpdateResult(log, r.Status().Update(ctx, obj)); err != nil || statusResult.Requeue {
+		return statusResult, err
+	}
+	return result, nil
+}
+
+// knativeIngressStatus provides the status of a Knative Ingress for its
+// current generation, which is Ready only if its routes are live.
+func knativeIngressStatus(obj *knativev1alpha1.Ingress, addrs []corev1.LoadBalancerIngress, live bool) knativev1alpha1.IngressStatus {
+	status := *obj.Status.DeepCopy()
+	status.InitializeConditions()
+	status.MarkNetworkConfigured()
+	if live {
+		var lbIngress []knativev1alpha1.LoadBalancerIngressStatus
+		for _, addr := range addrs {
+			lbIngress = append(lbIngress, knativev1alpha1.LoadBalancerIngressStatus{
+				IP:     addr.IP,
+				Domain: addr.Hostname,
+			})
+		}
+		status.MarkLoadBalancerReady(lbIngress, lbIngress)
+	} else {
+		status.MarkLoadBalancerNotReady()
+	}
+	status.ObservedGeneration = obj.Generation
+	return status
+}
@@ -0,0 +1,39 @@
+package configuration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	knativev1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
+)
+
+func TestKnativeIngressStatus(t *testing.T) {
+	addrs := []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}, {Hostname: "kong.example.com"}}
+	obj := &knativev1alpha1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", Generation: 1}}
+
+	t.Log("verifying that an Ingress whose routes aren't live isn't ready")
+	status := knativeIngressStatus(obj, addrs, false)
+	assert.Equal(t, int64(1), status.ObservedGeneration)
+	assert.True(t, status.GetCondition(knativev1alpha1.IngressConditionNetworkConfigured).IsTrue())
+	assert.True(t, status.GetCondition(knativev1alpha1.IngressConditionLoadBalancerReady).IsUnknown())
+	assert.False(t, status.GetCondition(knativev1alpha1.IngressConditionReady).IsTrue())
+	assert.Nil(t, status.PublicLoadBalancer)
+
+	t.Log("verifying that an Ingress whose routes are live is ready and reports the addresses")
+	obj.Status = knativeIngressStatus(obj, addrs, true)
+	assert.True(t, obj.Status.GetCondition(knativev1alpha1.IngressConditionReady).IsTrue())
+	expectedLBs := []knativev1alpha1.LoadBalancerIngressStatus{{IP: "10.0.0.1"}, {Domain: "kong.example.com"}}
+	assert.Equal(t, expectedLBs, obj.Status.PublicLoadBalancer.Ingress)
+	assert.Equal(t, expectedLBs, obj.Status.PrivateLoadBalancer.Ingress)
+
+	t.Log("verifying that the status doesn't change while the routes stay live")
+	assert.Equal(t, obj.Status, knativeIngressStatus(obj, addrs, true))
+
+	t.Log("verifying that a new generation isn't ready until its routes are live")
+	obj.Generation = 2
+	status = knativeIngressStatus(obj, addrs, false)
+	assert.Equal(t, int64(2), status.ObservedGeneration)
+	assert.False(t, status.GetCondition(knativev1alpha1.IngressConditionReady).IsTrue())
+}
@@ -0,0 +1,111 @@
+package configuration
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+)
+
+// -----------------------------------------------------------------------------
+// Publish Service - Status Reconciler
+// -----------------------------------------------------------------------------
+
+// PublishServiceStatusReconciler watches the Service fronting the data-plane
+// (--publish-service) and, whenever the addresses it provides change, requeues
+// status updates for all the Ingress-like objects (Ingress, TCPIngress,
+// UDPIngress, KnativeIngress) that are currently configured in the data-plane.
+// The reconcilers of those objects then write the new addresses to their
+// status.loadBalancer.ingress, which would otherwise only be refreshed when the
+// objects themselves change.
+type PublishServiceStatusReconciler struct {
+	client.Client
+
+	Log             logr.Logger
+	Scheme          *runtime.Scheme
+	DataplaneClient *dataplane.KongClient
+
+	DataplaneAddressFinder *dataplane.AddressFinder
+	// PublishService is the Service fronting the data-plane, in "namespace/name" format.
+	PublishService string
+
+	lock          sync.Mutex
+	lastAddresses []corev1.LoadBalancerIngress
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *PublishServiceStatusReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c, err := controller.New("PublishServiceStatus", mgr, controller.Options{
+		Reconciler: r,
+		LogConstructor: func(_ *reconcile.Request) logr.Logger {
+			return r.Log
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return c.Watch(
+		&source.Kind{Type: &corev1.Service{}},
+		&handler.EnqueueRequestForObject{},
+		predicate.NewPredicateFuncs(r.isPublishService),
+	)
+}
+
+// isPublishService is a watch predicate that filters out events for objects
+// that aren't the Service referenced by --publish-service.
+func (r *PublishServiceStatusReconciler) isPublishService(obj client.Object) bool {
+	return fmt.Sprintf("%s/%s", obj.GetNamespace(), obj.GetName()) == r.PublishService
+}
+
+//+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch
+
+// Reconcile processes the watched objects
+func (r *PublishServiceStatusReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("PublishService", req.NamespacedName)
+
+	if req.NamespacedName.String() != r.PublishService {
+		return ctrl.Result{}, nil
+	}
+
+	addrs, err := r.DataplaneAddressFinder.GetLoadBalancerAddresses()
+	if err != nil {
+		// the Service will be reconciled again when it gets updated with addresses
+		log.V(util.DebugLevel).Info("data-plane addresses not available yet", "reason", err.Error())
+		return ctrl.Result{}, nil
+	}
+
+	if !r.updateAddresses(addrs) {
+		log.V(util.DebugLevel).Info("data-plane addresses unchanged, status updates not needed")
+		return ctrl.Result{}, nil
+	}
+
+	log.V(util.InfoLevel).Info("data-plane addresses changed, updating the status of configured objects", "addresses", addrs)
+	r.DataplaneClient.RequeueKubernetesObjectReport()
+	return ctrl.Result{}, nil
+}
+
+// updateAddresses records the most recently seen data-plane addresses,
+// reporting whether they differ from the previously recorded ones.
+func (r *PublishServiceStatusReconciler) updateAddresses(addrs []corev1.LoadBalancerIngress) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if reflect.DeepEqual(r.lastAddresses, addrs) {
+		return false
+	}
+	r.lastAddresses = addrs
+	return true
+}
@@ -0,0 +1,365 @@
+package configuration
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	ctrlutils "github.com/kong/kubernetes-ingress-controller/v2/internal/controllers/utils"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+	kongv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
+)
+
+// -----------------------------------------------------------------------------
+// Stream Ports - Provisioning Reconciler
+// -----------------------------------------------------------------------------
+
+const (
+	// streamServicePortPrefix prefixes the names of the ports the
+	// StreamPortsReconciler manages on the publish service, which it removes
+	// once no TCPIngress or UDPIngress claims them anymore.
+	streamServicePortPrefix = "kic-"
+
+	// streamListenEnv is the environment variable of the Kong proxy container
+	// configuring its stream listeners.
+	streamListenEnv = "KONG_STREAM_LISTEN"
+)
+
+// StreamPortsReconciler opens the ports the TCPIngresses and UDPIngresses
+// claim on the Service fronting the data-plane (--publish-service) and, if
+// configured, on the stream listeners of the Kong Deployment, so that exposing
+// a new L4 port only takes creating the TCPIngress or UDPIngress. Ports are
+// added to the KONG_STREAM_LISTEN environment variable of the proxy container
+// but never removed from it, as changing it rolls the Deployment out. The
+// Deployment isn't watched: listeners removed from it are only added back
+// once a TCPIngress, UDPIngress or the Service changes.
+type StreamPortsReconciler struct {
+	client.Client
+
+	Log              logr.Logger
+	Scheme           *runtime.Scheme
+	IngressClassName string
+
+	// PublishService is the Service fronting the data-plane, in "namespace/name" format.
+	PublishService string
+	// Deployment is the Kong Deployment whose stream listeners are opened, in
+	// "namespace/name" format, none if empty.
+	Deployment string
+	// Container is the name of the Kong proxy container of the Deployment.
+	Container string
+	// APIReader reads the Deployment, which isn't cached.
+	APIReader client.Reader
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *StreamPortsReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c, err := controller.New("StreamPorts", mgr, controller.Options{
+		Reconciler: r,
+		LogConstructor: func(_ *reconcile.Request) logr.Logger {
+			return r.Log
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err := c.Watch(
+		&source.Kind{Type: &corev1.Service{}},
+		&handler.EnqueueRequestForObject{},
+		predicate.NewPredicateFuncs(r.isPublishService),
+	); err != nil {
+		return err
+	}
+	preds := ctrlutils.GeneratePredicateFuncsForIngressClassFilter(r.IngressClassName)
+	if err := c.Watch(
+		&source.Kind{Type: &kongv1beta1.TCPIngress{}},
+		handler.EnqueueRequestsFromMapFunc(r.enqueuePublishService),
+		preds,
+	); err != nil {
+		return err
+	}
+	return c.Watch(
+		&source.Kind{Type: &kongv1beta1.UDPIngress{}},
+		handler.EnqueueRequestsFromMapFunc(r.enqueuePublishService),
+		preds,
+	)
+}
+
+// isPublishService is a watch predicate that filters out events for objects
+// that aren't the Service referenced by --publish-service.
+func (r *StreamPortsReconciler) isPublishService(obj client.Object) bool {
+	return fmt.Sprintf("%s/%s", obj.GetNamespace(), obj.GetName()) == r.PublishService
+}
+
+// enqueuePublishService reconciles the publish service whenever the ports the
+// TCPIngresses and UDPIngresses claim may have changed.
+func (r *StreamPortsReconciler) enqueuePublishService(_ client.Object) []reconcile.Request {
+	namespace, name, _ := strings.Cut(r.PublishService, "/")
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}}
+}
+
+//+kubebuilder:rbac:groups=configuration.konghq.com,resources=tcpingresses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=configuration.konghq.com,resources=udpingresses,verbs=get;list;watch
+
+// Reconcile processes the watched objects
+func (r *StreamPortsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("StreamPorts", req.NamespacedName)
+	if req.NamespacedName.String() != r.PublishService {
+		return ctrl.Result{}, nil
+	}
+
+	ports, err := r.claimedStreamPorts(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	service := new(corev1.Service)
+	if err := r.Get(ctx, req.NamespacedName, service); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.V(util.InfoLevel).Info("publish service not found, its stream ports will be opened once it's created")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if servicePorts := streamServicePorts(service.Spec.Ports, ports); !equality.Semantic.DeepEqual(servicePorts, service.Spec.Ports) {
+		patched := service.DeepCopy()
+		patched.Spec.Ports = servicePorts
+		if err := r.Patch(ctx, patched, client.MergeFromWithOptions(service, client.MergeFromWithOptimisticLock{})); err != nil {
+			return ctrl.Result{}, err
+		}
+		log.V(util.InfoLevel).Info("updated the stream ports of the publish service", "ports", len(ports))
+	}
+
+	if r.Deployment == "" {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{}, r.reconcileStreamListen(ctx, log, ports)
+}
+
+// reconcileStreamListen adds the stream listeners missing for the given ports
+// to the Kong proxy container of the Deployment.
+func (r *StreamPortsReconciler) reconcileStreamListen(ctx context.Context, log logr.Logger, ports []streamPort) error {
+	namespace, name, _ := strings.Cut(r.Deployment, "/")
+	deployment := new(appsv1.Deployment)
+	if err := r.APIReader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, deployment); err != nil {
+		return err
+	}
+	patched := deployment.DeepCopy()
+	var container *corev1.Container
+	for i := range patched.Spec.Template.Spec.Containers {
+		if patched.Spec.Template.Spec.Containers[i].Name == r.Container {
+			container = &patched.Spec.Template.Spec.Containers[i]
+		}
+	}
+	if container == nil {
+		return fmt.Errorf("deployment %s has no container %q", r.Deployment, r.Container)
+	}
+
+	envIndex := -1
+	for i, env := range container.Env {
+		if env.Name == streamListenEnv {
+			envIndex = i
+		}
+	}
+	var current string
+	if envIndex >= 0 {
+		if container.Env[envIndex].ValueFrom != nil {
+			return fmt.Errorf("%s of container %q of deployment %s isn't a plain value", streamListenEnv, r.Container, r.Deployment)
+		}
+		current = container.Env[envIndex].Value
+	}
+	listen, added := streamListen(current, ports)
+	if added == 0 {
+		return nil
+	}
+	if envIndex >= 0 {
+		container.Env[envIndex].Value = listen
+	} else {
+		container.Env = append(container.Env, corev1.EnvVar{Name: streamListenEnv, Value: listen})
+	}
+	if err := r.Patch(ctx, patched, client.MergeFromWithOptions(deployment, client.MergeFromWithOptimisticLock{})); err != nil {
+		return err
+	}
+	log.V(util.InfoLevel).Info("added stream listeners to the Kong Deployment", "deployment", r.Deployment, "listeners", added)
+	return nil
+}
+
+// streamPort is a port TCPIngresses or UDPIngresses claim.
+type streamPort struct {
+	port     int
+	protocol corev1.Protocol
+	// tls indicates whether connections to the port are TLS, which Kong
+	// terminates to route them by SNI.
+	tls bool
+}
+
+// claimedStreamPorts lists the ports which the valid rules of the
+// TCPIngresses and UDPIngresses of the ingress class claim, sorted by port
+// and protocol.
+func (r *StreamPortsReconciler) claimedStreamPorts(ctx context.Context) ([]streamPort, error) {
+	class := new(netv1.IngressClass)
+	if err := r.Get(ctx, types.NamespacedName{Name: r.IngressClassName}, class); err != nil {
+		r.Log.V(util.DebugLevel).Info("could not retrieve IngressClass", "ingressclass", r.IngressClassName)
+	}
+	isDefault := ctrlutils.IsDefaultIngressClass(class)
+
+	tcpIngresses := new(kongv1beta1.TCPIngressList)
+	if err := r.List(ctx, tcpIngresses); err != nil {
+		return nil, err
+	}
+	udpIngresses := new(kongv1beta1.UDPIngressList)
+	if err := r.List(ctx, udpIngresses); err != nil {
+		return nil, err
+	}
+
+	claimed := make(map[streamPort]bool)
+	tcpPorts := make(map[int]bool)
+	for i := range tcpIngresses.Items {
+		ingress := &tcpIngresses.Items[i]
+		if !ctrlutils.MatchesIngressClass(ingress, r.IngressClassName, isDefault) {
+			continue
+		}
+		for _, rule := range ingress.Spec.Rules {
+			if parser.ValidateTCPIngressRule(rule) != nil {
+				continue
+			}
+			tcpPorts[rule.Port] = tcpPorts[rule.Port] || rule.Host != ""
+		}
+	}
+	for port, tls := range tcpPorts {
+		claimed[streamPort{port: port, protocol: corev1.ProtocolTCP, tls: tls}] = true
+	}
+	for i := range udpIngresses.Items {
+		ingress := &udpIngresses.Items[i]
+		if !ctrlutils.MatchesIngressClass(ingress, r.IngressClassName, isDefault) {
+			continue
+		}
+		for _, rule := range ingress.Spec.Rules {
+			if parser.ValidateUDPIngressRule(rule) != nil {
+				continue
+			}
+			for port := rule.Port; port <= parser.UDPIngressRuleEndPort(rule); port++ {
+				claimed[streamPort{port: port, protocol: corev1.ProtocolUDP}] = true
+			}
+		}
+	}
+
+	ports := make([]streamPort, 0, len(claimed))
+	for port := range claimed {
+		ports = append(ports, port)
+	}
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].port != ports[j].port {
+			return ports[i].port < ports[j].port
+		}
+		return ports[i].protocol < ports[j].protocol
+	})
+	return ports, nil
+}
+
+// streamServicePortName names the Service port managed for a stream port.
+func streamServicePortName(port streamPort) string {
+	return fmt.Sprintf("%s%s-%d", streamServicePortPrefix, strings.ToLower(string(port.protocol)), port.port)
+}
+
+// streamServicePorts provides the ports of the publish service exposing the
+// given stream ports: the ports it already exposes are kept, the missing
+// ones are added and the ones previously added for ports which aren't
+// claimed anymore are removed.
+func streamServicePorts(current []corev1.ServicePort, ports []streamPort) []corev1.ServicePort {
+	wanted := make(map[string]bool, len(ports))
+	for _, port := range ports {
+		wanted[streamServicePortName(port)] = true
+	}
+
+	exposed := make(map[streamPort]bool)
+	servicePorts := make([]corev1.ServicePort, 0, len(current)+len(ports))
+	for _, servicePort := range current {
+		if strings.HasPrefix(servicePort.Name, streamServicePortPrefix) && !wanted[servicePort.Name] {
+			continue
+		}
+		protocol := servicePort.Protocol
+		if protocol == "" {
+			protocol = corev1.ProtocolTCP
+		}
+		exposed[streamPort{port: int(servicePort.Port), protocol: protocol}] = true
+		servicePorts = append(servicePorts, servicePort)
+	}
+	for _, port := range ports {
+		if exposed[streamPort{port: port.port, protocol: port.protocol}] {
+			continue
+		}
+		servicePorts = append(servicePorts, corev1.ServicePort{
+			Name:       streamServicePortName(port),
+			Protocol:   port.protocol,
+			Port:       int32(port.port),
+			TargetPort: intstr.FromInt(port.port),
+		})
+	}
+	return servicePorts
+}
+
+// streamListen adds the listeners missing for the given ports to the value
+// of KONG_STREAM_LISTEN, returning the new value and how many were added.
+func streamListen(current string, ports []streamPort) (string, int) {
+	var listeners []string
+	listening := make(map[streamPort]bool)
+	if current = strings.TrimSpace(current); current != "" && current != "off" {
+		for _, listener := range strings.Split(current, ",") {
+			listener = strings.TrimSpace(listener)
+			listeners = append(listeners, listener)
+			fields := strings.Fields(listener)
+			if len(fields) == 0 {
+				continue
+			}
+			address := fields[0]
+			port, err := strconv.Atoi(address[strings.LastIndex(address, ":")+1:])
+			if err != nil {
+				continue
+			}
+			protocol := corev1.ProtocolTCP
+			for _, flag := range fields[1:] {
+				if flag == "udp" {
+					protocol = corev1.ProtocolUDP
+				}
+			}
+			listening[streamPort{port: port, protocol: protocol}] = true
+		}
+	}
+
+	var added int
+	for _, port := range ports {
+		if listening[streamPort{port: port.port, protocol: port.protocol}] {
+			continue
+		}
+		listener := "0.0.0.0:" + strconv.Itoa(port.port)
+		switch {
+		case port.protocol == corev1.ProtocolUDP:
+			listener += " udp reuseport"
+		case port.tls:
+			listener += " ssl"
+		}
+		listeners = append(listeners, listener)
+		added++
+	}
+	return strings.Join(listeners, ", "), added
+}
@@ -0,0 +1,125 @@
+package configuration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
+	kongv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
+)
+
+func TestStreamPortsReconciler(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, kongv1beta1.AddToScheme(scheme))
+
+	classAnnotations := map[string]string{annotations.IngressClassKey: annotations.DefaultIngressClass}
+	backend := kongv1beta1.IngressBackend{ServiceName: "svc", ServicePort: 80}
+	tcpIngress := &kongv1beta1.TCPIngress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "tcp", Annotations: classAnnotations},
+		Spec: kongv1beta1.TCPIngressSpec{Rules: []kongv1beta1.IngressRule{
+			{Port: 8000, Backend: backend},
+			{Port: 9443, Host: "example.com", Backend: backend},
+			{Port: 9001}, // invalid rules aren't translated
+		}},
+	}
+	udpIngress := &kongv1beta1.UDPIngress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "udp", Annotations: classAnnotations},
+		Spec: kongv1beta1.UDPIngressSpec{Rules: []kongv1beta1.UDPIngressRule{
+			{Port: 53, EndPort: 54, Backend: backend},
+		}},
+	}
+	otherClass := &kongv1beta1.TCPIngress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other", Annotations: map[string]string{
+			annotations.IngressClassKey: "other",
+		}},
+		Spec: kongv1beta1.TCPIngressSpec{Rules: []kongv1beta1.IngressRule{{Port: 7000, Backend: backend}}},
+	}
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kong", Name: "kong-proxy"},
+		Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{
+			{Name: "proxy", Port: 80, TargetPort: intstr.FromInt(8000)},
+			{Name: "kic-tcp-9000", Protocol: corev1.ProtocolTCP, Port: 9000, TargetPort: intstr.FromInt(9000)},
+			{Name: "tcp", Port: 8000, TargetPort: intstr.FromInt(8000)},
+		}},
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kong", Name: "kong"},
+		Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name: "proxy",
+			Env:  []corev1.EnvVar{{Name: "KONG_STREAM_LISTEN", Value: "0.0.0.0:8000, 0.0.0.0:53 udp reuseport"}},
+		}}}}},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(tcpIngress, udpIngress, otherClass, service, deployment).Build()
+	r := &StreamPortsReconciler{
+		Client:           c,
+		Log:              logr.Discard(),
+		Scheme:           scheme,
+		IngressClassName: annotations.DefaultIngressClass,
+		PublishService:   "kong/kong-proxy",
+		Deployment:       "kong/kong",
+		Container:        "proxy",
+		APIReader:        c,
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "kong", Name: "kong-proxy"}}
+
+	t.Log("opening the claimed ports on the publish service and the Deployment")
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, req.NamespacedName, service))
+	assert.Equal(t, []corev1.ServicePort{
+		{Name: "proxy", Port: 80, TargetPort: intstr.FromInt(8000)},
+		{Name: "tcp", Port: 8000, TargetPort: intstr.FromInt(8000)},
+		{Name: "kic-udp-53", Protocol: corev1.ProtocolUDP, Port: 53, TargetPort: intstr.FromInt(53)},
+		{Name: "kic-udp-54", Protocol: corev1.ProtocolUDP, Port: 54, TargetPort: intstr.FromInt(54)},
+		{Name: "kic-tcp-9443", Protocol: corev1.ProtocolTCP, Port: 9443, TargetPort: intstr.FromInt(9443)},
+	}, service.Spec.Ports, "the ports which aren't claimed anymore are removed")
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "kong", Name: "kong"}, deployment))
+	assert.Equal(t, "0.0.0.0:8000, 0.0.0.0:53 udp reuseport, 0.0.0.0:54 udp reuseport, 0.0.0.0:9443 ssl",
+		deployment.Spec.Template.Spec.Containers[0].Env[0].Value)
+
+	t.Log("leaving the publish service and the Deployment alone once they're up to date")
+	resourceVersions := []string{service.ResourceVersion, deployment.ResourceVersion}
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, req.NamespacedName, service))
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "kong", Name: "kong"}, deployment))
+	assert.Equal(t, resourceVersions, []string{service.ResourceVersion, deployment.ResourceVersion})
+}
+
+func TestStreamListen(t *testing.T) {
+	ports := []streamPort{
+		{port: 53, protocol: corev1.ProtocolUDP},
+		{port: 9000, protocol: corev1.ProtocolTCP},
+	}
+	for _, tt := range []struct {
+		current string
+		want    string
+		added   int
+	}{
+		{current: "", want: "0.0.0.0:53 udp reuseport, 0.0.0.0:9000", added: 2},
+		{current: "off", want: "0.0.0.0:53 udp reuseport, 0.0.0.0:9000", added: 2},
+		{current: "0.0.0.0:9000 proxy_protocol", want: "0.0.0.0:9000 proxy_protocol, 0.0.0.0:53 udp reuseport", added: 1},
+		{current: "[::]:53 udp,127.0.0.1:9000", want: "[::]:53 udp, 127.0.0.1:9000", added: 0},
+		{current: "0.0.0.0:53, 0.0.0.0:9000", want: "0.0.0.0:53, 0.0.0.0:9000, 0.0.0.0:53 udp reuseport", added: 1},
+	} {
+		got, added := streamListen(tt.current, ports)
+		assert.Equal(t, tt.want, got, tt.current)
+		assert.Equal(t, tt.added, added, tt.current)
+	}
+}
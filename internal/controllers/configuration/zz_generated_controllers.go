@@ -24,7 +24,9 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	extv1beta1 "k8s.io/api/extensions/v1beta1"
 	netv1 "k8s.io/api/networking/v1"
 	netv1beta1 "k8s.io/api/networking/v1beta1"
@@ -33,7 +35,6 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	knativev1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
-	knativeApis "knative.dev/pkg/apis"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -42,8 +43,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	mcsv1alpha1 "github.com/kong/kubernetes-ingress-controller/v2/internal/apis/multicluster/v1alpha1"
 	ctrlutils "github.com/kong/kubernetes-ingress-controller/v2/internal/controllers/utils"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/tracing"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util/kubernetes/object/status"
 	kongv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
@@ -84,9 +87,15 @@ func (r *CoreV1ServiceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 //+kubebuilder:rbac:groups="",resources=services/status,verbs=get;update;patch
 
 // Reconcile processes the watched objects
-func (r *CoreV1ServiceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *CoreV1ServiceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	log := r.Log.WithValues("CoreV1Service", req.NamespacedName)
 
+	// trace the reconciliation, and link it to the configuration update applying it
+	ctx, span := tracing.Tracer().Start(ctx, "Reconcile CoreV1Service",
+		trace.WithAttributes(tracing.ObjectAttributes("Service", req.NamespacedName)...))
+	defer func() { tracing.EndSpan(span, err) }()
+	r.DataplaneClient.TraceChange(ctx)
+
 	// get the relevant object
 	obj := new(corev1.Service)
 	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
@@ -157,9 +166,15 @@ func (r *CoreV1EndpointsReconciler) SetupWithManager(mgr ctrl.Manager) error {
 //+kubebuilder:rbac:groups="",resources=endpoints/status,verbs=get;update;patch
 
 // Reconcile processes the watched objects
-func (r *CoreV1EndpointsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *CoreV1EndpointsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	log := r.Log.WithValues("CoreV1Endpoints", req.NamespacedName)
 
+	// trace the reconciliation, and link it to the configuration update applying it
+	ctx, span := tracing.Tracer().Start(ctx, "Reconcile CoreV1Endpoints",
+		trace.WithAttributes(tracing.ObjectAttributes("Endpoints", req.NamespacedName)...))
+	defer func() { tracing.EndSpan(span, err) }()
+	r.DataplaneClient.TraceChange(ctx)
+
 	// get the relevant object
 	obj := new(corev1.Endpoints)
 	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
@@ -196,6 +211,84 @@ func (r *CoreV1EndpointsReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	return ctrl.Result{}, nil
 }
 
+// -----------------------------------------------------------------------------
+// DiscoveryV1 EndpointSlice - Reconciler
+// -----------------------------------------------------------------------------
+
+// DiscoveryV1EndpointSliceReconciler reconciles EndpointSlice resources
+type DiscoveryV1EndpointSliceReconciler struct {
+	client.Client
+
+	Log             logr.Logger
+	Scheme          *runtime.Scheme
+	DataplaneClient *dataplane.KongClient
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DiscoveryV1EndpointSliceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c, err := controller.New("DiscoveryV1EndpointSlice", mgr, controller.Options{
+		Reconciler: r,
+		LogConstructor: func(_ *reconcile.Request) logr.Logger {
+			return r.Log
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return c.Watch(
+		&source.Kind{Type: &discoveryv1.EndpointSlice{}},
+		&handler.EnqueueRequestForObject{},
+	)
+}
+
+//+kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=list;watch
+
+// Reconcile processes the watched objects
+func (r *DiscoveryV1EndpointSliceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	log := r.Log.WithValues("DiscoveryV1EndpointSlice", req.NamespacedName)
+
+	// trace the reconciliation, and link it to the configuration update applying it
+	ctx, span := tracing.Tracer().Start(ctx, "Reconcile DiscoveryV1EndpointSlice",
+		trace.WithAttributes(tracing.ObjectAttributes("EndpointSlice", req.NamespacedName)...))
+	defer func() { tracing.EndSpan(span, err) }()
+	r.DataplaneClient.TraceChange(ctx)
+
+	// get the relevant object
+	obj := new(discoveryv1.EndpointSlice)
+	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
+		if errors.IsNotFound(err) {
+			obj.Namespace = req.Namespace
+			obj.Name = req.Name
+			return ctrl.Result{}, r.DataplaneClient.DeleteObject(obj)
+		}
+		return ctrl.Result{}, err
+	}
+	log.V(util.DebugLevel).Info("reconciling resource", "namespace", req.Namespace, "name", req.Name)
+
+	// clean the object up if it's being deleted
+	if !obj.DeletionTimestamp.IsZero() && time.Now().After(obj.DeletionTimestamp.Time) {
+		log.V(util.DebugLevel).Info("resource is being deleted, its configuration will be removed", "type", "EndpointSlice", "namespace", req.Namespace, "name", req.Name)
+		objectExistsInCache, err := r.DataplaneClient.ObjectExists(obj)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if objectExistsInCache {
+			if err := r.DataplaneClient.DeleteObject(obj); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{Requeue: true}, nil // wait until the object is no longer present in the cache
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// update the kong Admin API with the changes
+	if err := r.DataplaneClient.UpdateObject(obj); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
 // -----------------------------------------------------------------------------
 // CoreV1 Secret - Reconciler
 // -----------------------------------------------------------------------------
@@ -230,9 +323,15 @@ func (r *CoreV1SecretReconciler) SetupWithManager(mgr ctrl.Manager) error {
 //+kubebuilder:rbac:groups="",resources=secrets/status,verbs=get;update;patch
 
 // Reconcile processes the watched objects
-func (r *CoreV1SecretReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *CoreV1SecretReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	log := r.Log.WithValues("CoreV1Secret", req.NamespacedName)
 
+	// trace the reconciliation, and link it to the configuration update applying it
+	ctx, span := tracing.Tracer().Start(ctx, "Reconcile CoreV1Secret",
+		trace.WithAttributes(tracing.ObjectAttributes("Secret", req.NamespacedName)...))
+	defer func() { tracing.EndSpan(span, err) }()
+	r.DataplaneClient.TraceChange(ctx)
+
 	// get the relevant object
 	obj := new(corev1.Secret)
 	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
@@ -354,9 +453,15 @@ func (r *NetV1IngressReconciler) listClassless(obj client.Object) []reconcile.Re
 //+kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses/status,verbs=get;update;patch
 
 // Reconcile processes the watched objects
-func (r *NetV1IngressReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *NetV1IngressReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	log := r.Log.WithValues("NetV1Ingress", req.NamespacedName)
 
+	// trace the reconciliation, and link it to the configuration update applying it
+	ctx, span := tracing.Tracer().Start(ctx, "Reconcile NetV1Ingress",
+		trace.WithAttributes(tracing.ObjectAttributes("Ingress", req.NamespacedName)...))
+	defer func() { tracing.EndSpan(span, err) }()
+	r.DataplaneClient.TraceChange(ctx)
+
 	// get the relevant object
 	obj := new(netv1.Ingress)
 	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
@@ -420,7 +525,7 @@ func (r *NetV1IngressReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		log.V(util.DebugLevel).Info("found addresses for data-plane updating object status", "namespace", req.Namespace, "name", req.Name)
 		if len(obj.Status.LoadBalancer.Ingress) != len(addrs) || !reflect.DeepEqual(obj.Status.LoadBalancer.Ingress, addrs) {
 			obj.Status.LoadBalancer.Ingress = addrs
-			return ctrl.Result{}, r.Status().Update(ctx, obj)
+			return ctrlutils.StatusUpdateResult(log, r.Status().Update(ctx, obj))
 		} else {
 			log.V(util.DebugLevel).Info("status update not needed", "namespace", req.Namespace, "name", req.Name)
 		}
@@ -462,9 +567,15 @@ func (r *NetV1IngressClassReconciler) SetupWithManager(mgr ctrl.Manager) error {
 //+kubebuilder:rbac:groups=networking.k8s.io,resources=ingressclasses,verbs=get;list;watch
 
 // Reconcile processes the watched objects
-func (r *NetV1IngressClassReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *NetV1IngressClassReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	log := r.Log.WithValues("NetV1IngressClass", req.NamespacedName)
 
+	// trace the reconciliation, and link it to the configuration update applying it
+	ctx, span := tracing.Tracer().Start(ctx, "Reconcile NetV1IngressClass",
+		trace.WithAttributes(tracing.ObjectAttributes("IngressClass", req.NamespacedName)...))
+	defer func() { tracing.EndSpan(span, err) }()
+	r.DataplaneClient.TraceChange(ctx)
+
 	// get the relevant object
 	obj := new(netv1.IngressClass)
 	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
@@ -586,9 +697,15 @@ func (r *NetV1Beta1IngressReconciler) listClassless(obj client.Object) []reconci
 //+kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses/status,verbs=get;update;patch
 
 // Reconcile processes the watched objects
-func (r *NetV1Beta1IngressReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *NetV1Beta1IngressReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	log := r.Log.WithValues("NetV1Beta1Ingress", req.NamespacedName)
 
+	// trace the reconciliation, and link it to the configuration update applying it
+	ctx, span := tracing.Tracer().Start(ctx, "Reconcile NetV1Beta1Ingress",
+		trace.WithAttributes(tracing.ObjectAttributes("Ingress", req.NamespacedName)...))
+	defer func() { tracing.EndSpan(span, err) }()
+	r.DataplaneClient.TraceChange(ctx)
+
 	// get the relevant object
 	obj := new(netv1beta1.Ingress)
 	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
@@ -652,7 +769,7 @@ func (r *NetV1Beta1IngressReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		log.V(util.DebugLevel).Info("found addresses for data-plane updating object status", "namespace", req.Namespace, "name", req.Name)
 		if len(obj.Status.LoadBalancer.Ingress) != len(addrs) || !reflect.DeepEqual(obj.Status.LoadBalancer.Ingress, addrs) {
 			obj.Status.LoadBalancer.Ingress = addrs
-			return ctrl.Result{}, r.Status().Update(ctx, obj)
+			return ctrlutils.StatusUpdateResult(log, r.Status().Update(ctx, obj))
 		} else {
 			log.V(util.DebugLevel).Info("status update not needed", "namespace", req.Namespace, "name", req.Name)
 		}
@@ -746,9 +863,15 @@ func (r *ExtV1Beta1IngressReconciler) listClassless(obj client.Object) []reconci
 //+kubebuilder:rbac:groups=extensions,resources=ingresses/status,verbs=get;update;patch
 
 // Reconcile processes the watched objects
-func (r *ExtV1Beta1IngressReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *ExtV1Beta1IngressReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	log := r.Log.WithValues("ExtV1Beta1Ingress", req.NamespacedName)
 
+	// trace the reconciliation, and link it to the configuration update applying it
+	ctx, span := tracing.Tracer().Start(ctx, "Reconcile ExtV1Beta1Ingress",
+		trace.WithAttributes(tracing.ObjectAttributes("Ingress", req.NamespacedName)...))
+	defer func() { tracing.EndSpan(span, err) }()
+	r.DataplaneClient.TraceChange(ctx)
+
 	// get the relevant object
 	obj := new(extv1beta1.Ingress)
 	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
@@ -812,7 +935,7 @@ func (r *ExtV1Beta1IngressReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		log.V(util.DebugLevel).Info("found addresses for data-plane updating object status", "namespace", req.Namespace, "name", req.Name)
 		if len(obj.Status.LoadBalancer.Ingress) != len(addrs) || !reflect.DeepEqual(obj.Status.LoadBalancer.Ingress, addrs) {
 			obj.Status.LoadBalancer.Ingress = addrs
-			return ctrl.Result{}, r.Status().Update(ctx, obj)
+			return ctrlutils.StatusUpdateResult(log, r.Status().Update(ctx, obj))
 		} else {
 			log.V(util.DebugLevel).Info("status update not needed", "namespace", req.Namespace, "name", req.Name)
 		}
@@ -855,9 +978,15 @@ func (r *KongV1KongIngressReconciler) SetupWithManager(mgr ctrl.Manager) error {
 //+kubebuilder:rbac:groups=configuration.konghq.com,resources=kongingresses/status,verbs=get;update;patch
 
 // Reconcile processes the watched objects
-func (r *KongV1KongIngressReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *KongV1KongIngressReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	log := r.Log.WithValues("KongV1KongIngress", req.NamespacedName)
 
+	// trace the reconciliation, and link it to the configuration update applying it
+	ctx, span := tracing.Tracer().Start(ctx, "Reconcile KongV1KongIngress",
+		trace.WithAttributes(tracing.ObjectAttributes("KongIngress", req.NamespacedName)...))
+	defer func() { tracing.EndSpan(span, err) }()
+	r.DataplaneClient.TraceChange(ctx)
+
 	// get the relevant object
 	obj := new(kongv1.KongIngress)
 	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
@@ -928,9 +1057,15 @@ func (r *KongV1KongPluginReconciler) SetupWithManager(mgr ctrl.Manager) error {
 //+kubebuilder:rbac:groups=configuration.konghq.com,resources=kongplugins/status,verbs=get;update;patch
 
 // Reconcile processes the watched objects
-func (r *KongV1KongPluginReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *KongV1KongPluginReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	log := r.Log.WithValues("KongV1KongPlugin", req.NamespacedName)
 
+	// trace the reconciliation, and link it to the configuration update applying it
+	ctx, span := tracing.Tracer().Start(ctx, "Reconcile KongV1KongPlugin",
+		trace.WithAttributes(tracing.ObjectAttributes("KongPlugin", req.NamespacedName)...))
+	defer func() { tracing.EndSpan(span, err) }()
+	r.DataplaneClient.TraceChange(ctx)
+
 	// get the relevant object
 	obj := new(kongv1.KongPlugin)
 	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
@@ -1036,9 +1171,15 @@ func (r *KongV1KongClusterPluginReconciler) listClassless(obj client.Object) []r
 //+kubebuilder:rbac:groups=configuration.konghq.com,resources=kongclusterplugins/status,verbs=get;update;patch
 
 // Reconcile processes the watched objects
-func (r *KongV1KongClusterPluginReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *KongV1KongClusterPluginReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	log := r.Log.WithValues("KongV1KongClusterPlugin", req.NamespacedName)
 
+	// trace the reconciliation, and link it to the configuration update applying it
+	ctx, span := tracing.Tracer().Start(ctx, "Reconcile KongV1KongClusterPlugin",
+		trace.WithAttributes(tracing.ObjectAttributes("KongClusterPlugin", req.NamespacedName)...))
+	defer func() { tracing.EndSpan(span, err) }()
+	r.DataplaneClient.TraceChange(ctx)
+
 	// get the relevant object
 	obj := new(kongv1.KongClusterPlugin)
 	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
@@ -1158,9 +1299,15 @@ func (r *KongV1KongConsumerReconciler) listClassless(obj client.Object) []reconc
 //+kubebuilder:rbac:groups=configuration.konghq.com,resources=kongconsumers/status,verbs=get;update;patch
 
 // Reconcile processes the watched objects
-func (r *KongV1KongConsumerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *KongV1KongConsumerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	log := r.Log.WithValues("KongV1KongConsumer", req.NamespacedName)
 
+	// trace the reconciliation, and link it to the configuration update applying it
+	ctx, span := tracing.Tracer().Start(ctx, "Reconcile KongV1KongConsumer",
+		trace.WithAttributes(tracing.ObjectAttributes("KongConsumer", req.NamespacedName)...))
+	defer func() { tracing.EndSpan(span, err) }()
+	r.DataplaneClient.TraceChange(ctx)
+
 	// get the relevant object
 	obj := new(kongv1.KongConsumer)
 	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
@@ -1296,9 +1443,15 @@ func (r *KongV1Beta1TCPIngressReconciler) listClassless(obj client.Object) []rec
 //+kubebuilder:rbac:groups=configuration.konghq.com,resources=tcpingresses/status,verbs=get;update;patch
 
 // Reconcile processes the watched objects
-func (r *KongV1Beta1TCPIngressReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *KongV1Beta1TCPIngressReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	log := r.Log.WithValues("KongV1Beta1TCPIngress", req.NamespacedName)
 
+	// trace the reconciliation, and link it to the configuration update applying it
+	ctx, span := tracing.Tracer().Start(ctx, "Reconcile KongV1Beta1TCPIngress",
+		trace.WithAttributes(tracing.ObjectAttributes("TCPIngress", req.NamespacedName)...))
+	defer func() { tracing.EndSpan(span, err) }()
+	r.DataplaneClient.TraceChange(ctx)
+
 	// get the relevant object
 	obj := new(kongv1beta1.TCPIngress)
 	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
@@ -1362,7 +1515,7 @@ func (r *KongV1Beta1TCPIngressReconciler) Reconcile(ctx context.Context, req ctr
 		log.V(util.DebugLevel).Info("found addresses for data-plane updating object status", "namespace", req.Namespace, "name", req.Name)
 		if len(obj.Status.LoadBalancer.Ingress) != len(addrs) || !reflect.DeepEqual(obj.Status.LoadBalancer.Ingress, addrs) {
 			obj.Status.LoadBalancer.Ingress = addrs
-			return ctrl.Result{}, r.Status().Update(ctx, obj)
+			return ctrlutils.StatusUpdateResult(log, r.Status().Update(ctx, obj))
 		} else {
 			log.V(util.DebugLevel).Info("status update not needed", "namespace", req.Namespace, "name", req.Name)
 		}
@@ -1456,9 +1609,15 @@ func (r *KongV1Beta1UDPIngressReconciler) listClassless(obj client.Object) []rec
 //+kubebuilder:rbac:groups=configuration.konghq.com,resources=udpingresses/status,verbs=get;update;patch
 
 // Reconcile processes the watched objects
-func (r *KongV1Beta1UDPIngressReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *KongV1Beta1UDPIngressReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	log := r.Log.WithValues("KongV1Beta1UDPIngress", req.NamespacedName)
 
+	// trace the reconciliation, and link it to the configuration update applying it
+	ctx, span := tracing.Tracer().Start(ctx, "Reconcile KongV1Beta1UDPIngress",
+		trace.WithAttributes(tracing.ObjectAttributes("UDPIngress", req.NamespacedName)...))
+	defer func() { tracing.EndSpan(span, err) }()
+	r.DataplaneClient.TraceChange(ctx)
+
 	// get the relevant object
 	obj := new(kongv1beta1.UDPIngress)
 	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
@@ -1522,7 +1681,7 @@ func (r *KongV1Beta1UDPIngressReconciler) Reconcile(ctx context.Context, req ctr
 		log.V(util.DebugLevel).Info("found addresses for data-plane updating object status", "namespace", req.Namespace, "name", req.Name)
 		if len(obj.Status.LoadBalancer.Ingress) != len(addrs) || !reflect.DeepEqual(obj.Status.LoadBalancer.Ingress, addrs) {
 			obj.Status.LoadBalancer.Ingress = addrs
-			return ctrl.Result{}, r.Status().Update(ctx, obj)
+			return ctrlutils.StatusUpdateResult(log, r.Status().Update(ctx, obj))
 		} else {
 			log.V(util.DebugLevel).Info("status update not needed", "namespace", req.Namespace, "name", req.Name)
 		}
@@ -1532,27 +1691,24 @@ func (r *KongV1Beta1UDPIngressReconciler) Reconcile(ctx context.Context, req ctr
 }
 
 // -----------------------------------------------------------------------------
-// Knativev1alpha1 Ingress - Reconciler
+// KongV1Beta1 KongCACertificate - Reconciler
 // -----------------------------------------------------------------------------
 
-// Knativev1alpha1IngressReconciler reconciles Ingress resources
-type Knativev1alpha1IngressReconciler struct {
+// KongV1Beta1KongCACertificateReconciler reconciles KongCACertificate resources
+type KongV1Beta1KongCACertificateReconciler struct {
 	client.Client
 
 	Log             logr.Logger
 	Scheme          *runtime.Scheme
 	DataplaneClient *dataplane.KongClient
 
-	DataplaneAddressFinder *dataplane.AddressFinder
-	StatusQueue            *status.Queue
-
 	IngressClassName string
 	DisableIngressClassLookups bool
 }
 
 // SetupWithManager sets up the controller with the Manager.
-func (r *Knativev1alpha1IngressReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	c, err := controller.New("Knativev1alpha1Ingress", mgr, controller.Options{
+func (r *KongV1Beta1KongCACertificateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c, err := controller.New("KongV1Beta1KongCACertificate", mgr, controller.Options{
 		Reconciler: r,
 		LogConstructor: func(_ *reconcile.Request) logr.Logger {
 			return r.Log
@@ -1561,19 +1717,6 @@ func (r *Knativev1alpha1IngressReconciler) SetupWithManager(mgr ctrl.Manager) er
 	if err != nil {
 		return err
 	}
-	// if configured, start the status updater controller
-	if r.StatusQueue != nil {
-		if err := c.Watch(
-			&source.Channel{Source: r.StatusQueue.Subscribe(schema.GroupVersionKind{
-				Group:   "networking.internal.knative.dev",
-				Version: "v1alpha1",
-				Kind:    "Ingress",
-			})},
-			&handler.EnqueueRequestForObject{},
-		); err != nil {
-			return err
-		}
-	}
 	if !r.DisableIngressClassLookups {
 		err = c.Watch(
 			&source.Kind{Type: &netv1.IngressClass{}},
@@ -1586,16 +1729,16 @@ func (r *Knativev1alpha1IngressReconciler) SetupWithManager(mgr ctrl.Manager) er
 	}
 	preds := ctrlutils.GeneratePredicateFuncsForIngressClassFilter(r.IngressClassName)
 	return c.Watch(
-		&source.Kind{Type: &knativev1alpha1.Ingress{}},
+		&source.Kind{Type: &kongv1beta1.KongCACertificate{}},
 		&handler.EnqueueRequestForObject{},
 		preds,
 	)
 }
 // listClassless finds and reconciles all objects without ingress class information
-func (r *Knativev1alpha1IngressReconciler) listClassless(obj client.Object) []reconcile.Request {
-	resourceList := &knativev1alpha1.IngressList{}
+func (r *KongV1Beta1KongCACertificateReconciler) listClassless(obj client.Object) []reconcile.Request {
+	resourceList := &kongv1beta1.KongCACertificateList{}
 	if err := r.Client.List(context.Background(), resourceList); err != nil {
-		r.Log.Error(err, "failed to list classless ingresses")
+		r.Log.Error(err, "failed to list classless kongcacertificates")
 		return nil
 	}
 	var recs []reconcile.Request
@@ -1612,15 +1755,21 @@ func (r *Knativev1alpha1IngressReconciler) listClassless(obj client.Object) []re
 	return recs
 }
 
-//+kubebuilder:rbac:groups=networking.internal.knative.dev,resources=ingresses,verbs=get;list;watch
-//+kubebuilder:rbac:groups=networking.internal.knative.dev,resources=ingresses/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=configuration.konghq.com,resources=kongcacertificates,verbs=get;list;watch
+//+kubebuilder:rbac:groups=configuration.konghq.com,resources=kongcacertificates/status,verbs=get;update;patch
 
 // Reconcile processes the watched objects
-func (r *Knativev1alpha1IngressReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	log := r.Log.WithValues("Knativev1alpha1Ingress", req.NamespacedName)
+func (r *KongV1Beta1KongCACertificateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	log := r.Log.WithValues("KongV1Beta1KongCACertificate", req.NamespacedName)
+
+	// trace the reconciliation, and link it to the configuration update applying it
+	ctx, span := tracing.Tracer().Start(ctx, "Reconcile KongV1Beta1KongCACertificate",
+		trace.WithAttributes(tracing.ObjectAttributes("KongCACertificate", req.NamespacedName)...))
+	defer func() { tracing.EndSpan(span, err) }()
+	r.DataplaneClient.TraceChange(ctx)
 
 	// get the relevant object
-	obj := new(knativev1alpha1.Ingress)
+	obj := new(kongv1beta1.KongCACertificate)
 	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
 		if errors.IsNotFound(err) {
 			obj.Namespace = req.Namespace
@@ -1633,7 +1782,7 @@ func (r *Knativev1alpha1IngressReconciler) Reconcile(ctx context.Context, req ct
 
 	// clean the object up if it's being deleted
 	if !obj.DeletionTimestamp.IsZero() && time.Now().After(obj.DeletionTimestamp.Time) {
-		log.V(util.DebugLevel).Info("resource is being deleted, its configuration will be removed", "type", "Ingress", "namespace", req.Namespace, "name", req.Name)
+		log.V(util.DebugLevel).Info("resource is being deleted, its configuration will be removed", "type", "KongCACertificate", "namespace", req.Namespace, "name", req.Name)
 		objectExistsInCache, err := r.DataplaneClient.ObjectExists(obj)
 		if err != nil {
 			return ctrl.Result{}, err
@@ -1665,39 +1814,605 @@ func (r *Knativev1alpha1IngressReconciler) Reconcile(ctx context.Context, req ct
 	if err := r.DataplaneClient.UpdateObject(obj); err != nil {
 		return ctrl.Result{}, err
 	}
-	// if status updates are enabled report the status for the object
-	if r.DataplaneClient.AreKubernetesObjectReportsEnabled() {
-		log.V(util.DebugLevel).Info("determining whether data-plane configuration has succeeded", "namespace", req.Namespace, "name", req.Name)
-		if !r.DataplaneClient.KubernetesObjectIsConfigured(obj) {
-			log.V(util.DebugLevel).Info("resource not yet configured in the data-plane", "namespace", req.Namespace, "name", req.Name)
-			return ctrl.Result{Requeue: true}, nil // requeue until the object has been properly configured
+
+	return ctrl.Result{}, nil
+}
+
+// -----------------------------------------------------------------------------
+// KongV1Beta1 KongUpstreamPolicy - Reconciler
+// -----------------------------------------------------------------------------
+
+// KongV1Beta1KongUpstreamPolicyReconciler reconciles KongUpstreamPolicy resources
+type KongV1Beta1KongUpstreamPolicyReconciler struct {
+	client.Client
+
+	Log             logr.Logger
+	Scheme          *runtime.Scheme
+	DataplaneClient *dataplane.KongClient
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *KongV1Beta1KongUpstreamPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c, err := controller.New("KongV1Beta1KongUpstreamPolicy", mgr, controller.Options{
+		Reconciler: r,
+		LogConstructor: func(_ *reconcile.Request) logr.Logger {
+			return r.Log
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return c.Watch(
+		&source.Kind{Type: &kongv1beta1.KongUpstreamPolicy{}},
+		&handler.EnqueueRequestForObject{},
+	)
+}
+
+//+kubebuilder:rbac:groups=configuration.konghq.com,resources=kongupstreampolicies,verbs=get;list;watch
+
+// Reconcile processes the watched objects
+func (r *KongV1Beta1KongUpstreamPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	log := r.Log.WithValues("KongV1Beta1KongUpstreamPolicy", req.NamespacedName)
+
+	// trace the reconciliation, and link it to the configuration update applying it
+	ctx, span := tracing.Tracer().Start(ctx, "Reconcile KongV1Beta1KongUpstreamPolicy",
+		trace.WithAttributes(tracing.ObjectAttributes("KongUpstreamPolicy", req.NamespacedName)...))
+	defer func() { tracing.EndSpan(span, err) }()
+	r.DataplaneClient.TraceChange(ctx)
+
+	// get the relevant object
+	obj := new(kongv1beta1.KongUpstreamPolicy)
+	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
+		if errors.IsNotFound(err) {
+			obj.Namespace = req.Namespace
+			obj.Name = req.Name
+			return ctrl.Result{}, r.DataplaneClient.DeleteObject(obj)
 		}
+		return ctrl.Result{}, err
+	}
+	log.V(util.DebugLevel).Info("reconciling resource", "namespace", req.Namespace, "name", req.Name)
 
-		log.V(util.DebugLevel).Info("determining gateway addresses for object status updates", "namespace", req.Namespace, "name", req.Name)
-		addrs, err := r.DataplaneAddressFinder.GetLoadBalancerAddresses()
+	// clean the object up if it's being deleted
+	if !obj.DeletionTimestamp.IsZero() && time.Now().After(obj.DeletionTimestamp.Time) {
+		log.V(util.DebugLevel).Info("resource is being deleted, its configuration will be removed", "type", "KongUpstreamPolicy", "namespace", req.Namespace, "name", req.Name)
+		objectExistsInCache, err := r.DataplaneClient.ObjectExists(obj)
 		if err != nil {
 			return ctrl.Result{}, err
 		}
+		if objectExistsInCache {
+			if err := r.DataplaneClient.DeleteObject(obj); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{Requeue: true}, nil // wait until the object is no longer present in the cache
+		}
+		return ctrl.Result{}, nil
+	}
 
-		log.V(util.DebugLevel).Info("found addresses for data-plane updating object status", "namespace", req.Namespace, "name", req.Name)
-		var knativeLBIngress []knativev1alpha1.LoadBalancerIngressStatus
-		for _, addr := range addrs {
-			knativeIng := knativev1alpha1.LoadBalancerIngressStatus{
-				IP:     addr.IP,
-				Domain: addr.Hostname,
+	// update the kong Admin API with the changes
+	if err := r.DataplaneClient.UpdateObject(obj); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// -----------------------------------------------------------------------------
+// KongV1Beta1 IngressClassParameters - Reconciler
+// -----------------------------------------------------------------------------
+
+// KongV1Beta1IngressClassParametersReconciler reconciles IngressClassParameters resources
+type KongV1Beta1IngressClassParametersReconciler struct {
+	client.Client
+
+	Log             logr.Logger
+	Scheme          *runtime.Scheme
+	DataplaneClient *dataplane.KongClient
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *KongV1Beta1IngressClassParametersReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c, err := controller.New("KongV1Beta1IngressClassParameters", mgr, controller.Options{
+		Reconciler: r,
+		LogConstructor: func(_ *reconcile.Request) logr.Logger {
+			return r.Log
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return c.Watch(
+		&source.Kind{Type: &kongv1beta1.IngressClassParameters{}},
+		&handler.EnqueueRequestForObject{},
+	)
+}
+
+//+kubebuilder:rbac:groups=configuration.konghq.com,resources=ingressclassparameterses,verbs=get;list;watch
+
+// Reconcile processes the watched objects
+func (r *KongV1Beta1IngressClassParametersReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	log := r.Log.WithValues("KongV1Beta1IngressClassParameters", req.NamespacedName)
+
+	// trace the reconciliation, and link it to the configuration update applying it
+	ctx, span := tracing.Tracer().Start(ctx, "Reconcile KongV1Beta1IngressClassParameters",
+		trace.WithAttributes(tracing.ObjectAttributes("IngressClassParameters", req.NamespacedName)...))
+	defer func() { tracing.EndSpan(span, err) }()
+	r.DataplaneClient.TraceChange(ctx)
+
+	// get the relevant object
+	obj := new(kongv1beta1.IngressClassParameters)
+	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
+		if errors.IsNotFound(err) {
+			obj.Namespace = req.Namespace
+			obj.Name = req.Name
+			return ctrl.Result{}, r.DataplaneClient.DeleteObject(obj)
+		}
+		return ctrl.Result{}, err
+	}
+	log.V(util.DebugLevel).Info("reconciling resource", "namespace", req.Namespace, "name", req.Name)
+
+	// clean the object up if it's being deleted
+	if !obj.DeletionTimestamp.IsZero() && time.Now().After(obj.DeletionTimestamp.Time) {
+		log.V(util.DebugLevel).Info("resource is being deleted, its configuration will be removed", "type", "IngressClassParameters", "namespace", req.Namespace, "name", req.Name)
+		objectExistsInCache, err := r.DataplaneClient.ObjectExists(obj)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if objectExistsInCache {
+			if err := r.DataplaneClient.DeleteObject(obj); err != nil {
+				return ctrl.Result{}, err
 			}
-			knativeLBIngress = append(knativeLBIngress, knativeIng)
-		}
-		ingressCondSet := knativeApis.NewLivingConditionSet()
-		if obj.Status.PublicLoadBalancer == nil || len(obj.Status.PublicLoadBalancer.Ingress) != len(addrs) || !reflect.DeepEqual(obj.Status.PublicLoadBalancer.Ingress, knativeLBIngress) {
-			obj.Status.MarkLoadBalancerReady(knativeLBIngress, knativeLBIngress)
-			ingressCondSet.Manage(&obj.Status).MarkTrue(knativev1alpha1.IngressConditionReady)
-			ingressCondSet.Manage(&obj.Status).MarkTrue(knativev1alpha1.IngressConditionNetworkConfigured)
-			obj.Status.ObservedGeneration = obj.Generation
-			return ctrl.Result{}, r.Status().Update(ctx, obj)
-		} else {
-			log.V(util.DebugLevel).Info("status update not needed", "namespace", req.Namespace, "name", req.Name)
+			return ctrl.Result{Requeue: true}, nil // wait until the object is no longer present in the cache
 		}
+		return ctrl.Result{}, nil
+	}
+
+	// update the kong Admin API with the changes
+	if err := r.DataplaneClient.UpdateObject(obj); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// -----------------------------------------------------------------------------
+// KongV1Beta1 KongPluginBundle - Reconciler
+// -----------------------------------------------------------------------------
+
+// KongV1Beta1KongPluginBundleReconciler reconciles KongPluginBundle resources
+type KongV1Beta1KongPluginBundleReconciler struct {
+	client.Client
+
+	Log             logr.Logger
+	Scheme          *runtime.Scheme
+	DataplaneClient *dataplane.KongClient
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *KongV1Beta1KongPluginBundleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c, err := controller.New("KongV1Beta1KongPluginBundle", mgr, controller.Options{
+		Reconciler: r,
+		LogConstructor: func(_ *reconcile.Request) logr.Logger {
+			return r.Log
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return c.Watch(
+		&source.Kind{Type: &kongv1beta1.KongPluginBundle{}},
+		&handler.EnqueueRequestForObject{},
+	)
+}
+
+//+kubebuilder:rbac:groups=configuration.konghq.com,resources=kongpluginbundles,verbs=get;list;watch
+
+// Reconcile processes the watched objects
+func (r *KongV1Beta1KongPluginBundleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	log := r.Log.WithValues("KongV1Beta1KongPluginBundle", req.NamespacedName)
+
+	// trace the reconciliation, and link it to the configuration update applying it
+	ctx, span := tracing.Tracer().Start(ctx, "Reconcile KongV1Beta1KongPluginBundle",
+		trace.WithAttributes(tracing.ObjectAttributes("KongPluginBundle", req.NamespacedName)...))
+	defer func() { tracing.EndSpan(span, err) }()
+	r.DataplaneClient.TraceChange(ctx)
+
+	// get the relevant object
+	obj := new(kongv1beta1.KongPluginBundle)
+	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
+		if errors.IsNotFound(err) {
+			obj.Namespace = req.Namespace
+			obj.Name = req.Name
+			return ctrl.Result{}, r.DataplaneClient.DeleteObject(obj)
+		}
+		return ctrl.Result{}, err
+	}
+	log.V(util.DebugLevel).Info("reconciling resource", "namespace", req.Namespace, "name", req.Name)
+
+	// clean the object up if it's being deleted
+	if !obj.DeletionTimestamp.IsZero() && time.Now().After(obj.DeletionTimestamp.Time) {
+		log.V(util.DebugLevel).Info("resource is being deleted, its configuration will be removed", "type", "KongPluginBundle", "namespace", req.Namespace, "name", req.Name)
+		objectExistsInCache, err := r.DataplaneClient.ObjectExists(obj)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if objectExistsInCache {
+			if err := r.DataplaneClient.DeleteObject(obj); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{Requeue: true}, nil // wait until the object is no longer present in the cache
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// update the kong Admin API with the changes
+	if err := r.DataplaneClient.UpdateObject(obj); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// -----------------------------------------------------------------------------
+// KongV1Beta1 KongVault - Reconciler
+// -----------------------------------------------------------------------------
+
+// KongV1Beta1KongVaultReconciler reconciles KongVault resources
+type KongV1Beta1KongVaultReconciler struct {
+	client.Client
+
+	Log             logr.Logger
+	Scheme          *runtime.Scheme
+	DataplaneClient *dataplane.KongClient
+
+	IngressClassName string
+	DisableIngressClassLookups bool
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *KongV1Beta1KongVaultReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c, err := controller.New("KongV1Beta1KongVault", mgr, controller.Options{
+		Reconciler: r,
+		LogConstructor: func(_ *reconcile.Request) logr.Logger {
+			return r.Log
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if !r.DisableIngressClassLookups {
+		err = c.Watch(
+			&source.Kind{Type: &netv1.IngressClass{}},
+			handler.EnqueueRequestsFromMapFunc(r.listClassless),
+			predicate.NewPredicateFuncs(ctrlutils.IsDefaultIngressClass),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	preds := ctrlutils.GeneratePredicateFuncsForIngressClassFilter(r.IngressClassName)
+	return c.Watch(
+		&source.Kind{Type: &kongv1beta1.KongVault{}},
+		&handler.EnqueueRequestForObject{},
+		preds,
+	)
+}
+// listClassless finds and reconciles all objects without ingress class information
+func (r *KongV1Beta1KongVaultReconciler) listClassless(obj client.Object) []reconcile.Request {
+	resourceList := &kongv1beta1.KongVaultList{}
+	if err := r.Client.List(context.Background(), resourceList); err != nil {
+		r.Log.Error(err, "failed to list classless kongvaults")
+		return nil
+	}
+	var recs []reconcile.Request
+	for _, resource := range resourceList.Items {
+		if ctrlutils.IsIngressClassEmpty(&resource) {
+			recs = append(recs, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: resource.Namespace,
+					Name:      resource.Name,
+				},
+			})
+		}
+	}
+	return recs
+}
+
+//+kubebuilder:rbac:groups=configuration.konghq.com,resources=kongvaults,verbs=get;list;watch
+
+// Reconcile processes the watched objects
+func (r *KongV1Beta1KongVaultReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	log := r.Log.WithValues("KongV1Beta1KongVault", req.NamespacedName)
+
+	// trace the reconciliation, and link it to the configuration update applying it
+	ctx, span := tracing.Tracer().Start(ctx, "Reconcile KongV1Beta1KongVault",
+		trace.WithAttributes(tracing.ObjectAttributes("KongVault", req.NamespacedName)...))
+	defer func() { tracing.EndSpan(span, err) }()
+	r.DataplaneClient.TraceChange(ctx)
+
+	// get the relevant object
+	obj := new(kongv1beta1.KongVault)
+	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
+		if errors.IsNotFound(err) {
+			obj.Namespace = req.Namespace
+			obj.Name = req.Name
+			return ctrl.Result{}, r.DataplaneClient.DeleteObject(obj)
+		}
+		return ctrl.Result{}, err
+	}
+	log.V(util.DebugLevel).Info("reconciling resource", "namespace", req.Namespace, "name", req.Name)
+
+	// clean the object up if it's being deleted
+	if !obj.DeletionTimestamp.IsZero() && time.Now().After(obj.DeletionTimestamp.Time) {
+		log.V(util.DebugLevel).Info("resource is being deleted, its configuration will be removed", "type", "KongVault", "namespace", req.Namespace, "name", req.Name)
+		objectExistsInCache, err := r.DataplaneClient.ObjectExists(obj)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if objectExistsInCache {
+			if err := r.DataplaneClient.DeleteObject(obj); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{Requeue: true}, nil // wait until the object is no longer present in the cache
+		}
+		return ctrl.Result{}, nil
+	}
+
+	class := new(netv1.IngressClass)
+	if err := r.Get(ctx, types.NamespacedName{Name: r.IngressClassName}, class); err != nil {
+		// we log this without taking action to support legacy configurations that only set ingressClassName or
+		// used the class annotation and did not create a corresponding IngressClass. We only need this to determine
+		// if the IngressClass is default or to configure default settings, and can assume no/no additional defaults
+		// if none exists.
+		log.V(util.DebugLevel).Info("could not retrieve IngressClass", "ingressclass", r.IngressClassName)
+	}
+	// if the object is not configured with our ingress.class, then we need to ensure it's removed from the cache
+	if !ctrlutils.MatchesIngressClass(obj, r.IngressClassName, ctrlutils.IsDefaultIngressClass(class)) {
+		log.V(util.DebugLevel).Info("object missing ingress class, ensuring it's removed from configuration", "namespace", req.Namespace, "name", req.Name)
+		return ctrl.Result{}, r.DataplaneClient.DeleteObject(obj)
+	}
+
+	// update the kong Admin API with the changes
+	if err := r.DataplaneClient.UpdateObject(obj); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// -----------------------------------------------------------------------------
+// Knativev1alpha1 Ingress - Reconciler
+// -----------------------------------------------------------------------------
+
+// Knativev1alpha1IngressReconciler reconciles Ingress resources
+type Knativev1alpha1IngressReconciler struct {
+	client.Client
+
+	Log             logr.Logger
+	Scheme          *runtime.Scheme
+	DataplaneClient *dataplane.KongClient
+
+	DataplaneAddressFinder *dataplane.AddressFinder
+	StatusQueue            *status.Queue
+
+	IngressClassName string
+	DisableIngressClassLookups bool
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Knativev1alpha1IngressReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c, err := controller.New("Knativev1alpha1Ingress", mgr, controller.Options{
+		Reconciler: r,
+		LogConstructor: func(_ *reconcile.Request) logr.Logger {
+			return r.Log
+		},
+	})
+	if err != nil {
+		return err
+	}
+	// if configured, start the status updater controller
+	if r.StatusQueue != nil {
+		if err := c.Watch(
+			&source.Channel{Source: r.StatusQueue.Subscribe(schema.GroupVersionKind{
+				Group:   "networking.internal.knative.dev",
+				Version: "v1alpha1",
+				Kind:    "Ingress",
+			})},
+			&handler.EnqueueRequestForObject{},
+		); err != nil {
+			return err
+		}
+	}
+	if !r.DisableIngressClassLookups {
+		err = c.Watch(
+			&source.Kind{Type: &netv1.IngressClass{}},
+			handler.EnqueueRequestsFromMapFunc(r.listClassless),
+			predicate.NewPredicateFuncs(ctrlutils.IsDefaultIngressClass),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	preds := ctrlutils.GeneratePredicateFuncsForIngressClassFilter(r.IngressClassName)
+	return c.Watch(
+		&source.Kind{Type: &knativev1alpha1.Ingress{}},
+		&handler.EnqueueRequestForObject{},
+		preds,
+	)
+}
+// listClassless finds and reconciles all objects without ingress class information
+func (r *Knativev1alpha1IngressReconciler) listClassless(obj client.Object) []reconcile.Request {
+	resourceList := &knativev1alpha1.IngressList{}
+	if err := r.Client.List(context.Background(), resourceList); err != nil {
+		r.Log.Error(err, "failed to list classless ingresses")
+		return nil
+	}
+	var recs []reconcile.Request
+	for _, resource := range resourceList.Items {
+		if ctrlutils.IsIngressClassEmpty(&resource) {
+			recs = append(recs, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: resource.Namespace,
+					Name:      resource.Name,
+				},
+			})
+		}
+	}
+	return recs
+}
+
+//+kubebuilder:rbac:groups=networking.internal.knative.dev,resources=ingresses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=networking.internal.knative.dev,resources=ingresses/status,verbs=get;update;patch
+
+// Reconcile processes the watched objects
+func (r *Knativev1alpha1IngressReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	log := r.Log.WithValues("Knativev1alpha1Ingress", req.NamespacedName)
+
+	// trace the reconciliation, and link it to the configuration update applying it
+	ctx, span := tracing.Tracer().Start(ctx, "Reconcile Knativev1alpha1Ingress",
+		trace.WithAttributes(tracing.ObjectAttributes("Ingress", req.NamespacedName)...))
+	defer func() { tracing.EndSpan(span, err) }()
+	r.DataplaneClient.TraceChange(ctx)
+
+	// get the relevant object
+	obj := new(knativev1alpha1.Ingress)
+	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
+		if errors.IsNotFound(err) {
+			obj.Namespace = req.Namespace
+			obj.Name = req.Name
+			return ctrl.Result{}, r.DataplaneClient.DeleteObject(obj)
+		}
+		return ctrl.Result{}, err
+	}
+	log.V(util.DebugLevel).Info("reconciling resource", "namespace", req.Namespace, "name", req.Name)
+
+	// clean the object up if it's being deleted
+	if !obj.DeletionTimestamp.IsZero() && time.Now().After(obj.DeletionTimestamp.Time) {
+		log.V(util.DebugLevel).Info("resource is being deleted, its configuration will be removed", "type", "Ingress", "namespace", req.Namespace, "name", req.Name)
+		objectExistsInCache, err := r.DataplaneClient.ObjectExists(obj)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if objectExistsInCache {
+			if err := r.DataplaneClient.DeleteObject(obj); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{Requeue: true}, nil // wait until the object is no longer present in the cache
+		}
+		return ctrl.Result{}, nil
+	}
+
+	class := new(netv1.IngressClass)
+	if err := r.Get(ctx, types.NamespacedName{Name: r.IngressClassName}, class); err != nil {
+		// we log this without taking action to support legacy configurations that only set ingressClassName or
+		// used the class annotation and did not create a corresponding IngressClass. We only need this to determine
+		// if the IngressClass is default or to configure default settings, and can assume no/no additional defaults
+		// if none exists.
+		log.V(util.DebugLevel).Info("could not retrieve IngressClass", "ingressclass", r.IngressClassName)
+	}
+	// if the object is not configured with our ingress.class, then we need to ensure it's removed from the cache
+	if !ctrlutils.MatchesIngressClass(obj, r.IngressClassName, ctrlutils.IsDefaultIngressClass(class)) {
+		log.V(util.DebugLevel).Info("object missing ingress class, ensuring it's removed from configuration", "namespace", req.Namespace, "name", req.Name)
+		return ctrl.Result{}, r.DataplaneClient.DeleteObject(obj)
+	}
+
+	// update the kong Admin API with the changes
+	if err := r.DataplaneClient.UpdateObject(obj); err != nil {
+		return ctrl.Result{}, err
+	}
+	// if status updates are enabled report the status for the object
+	if r.DataplaneClient.AreKubernetesObjectReportsEnabled() {
+		log.V(util.DebugLevel).Info("determining whether data-plane configuration has succeeded", "namespace", req.Namespace, "name", req.Name)
+		if !r.DataplaneClient.KubernetesObjectIsConfigured(obj) {
+			log.V(util.DebugLevel).Info("resource not yet configured in the data-plane", "namespace", req.Namespace, "name", req.Name)
+			return ctrl.Result{Requeue: true}, nil // requeue until the object has been properly configured
+		}
+
+		log.V(util.DebugLevel).Info("determining gateway addresses for object status updates", "namespace", req.Namespace, "name", req.Name)
+		addrs, err := r.DataplaneAddressFinder.GetLoadBalancerAddresses()
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		log.V(util.DebugLevel).Info("found addresses for data-plane updating object status", "namespace", req.Namespace, "name", req.Name)
+		return r.updateKnativeIngressStatus(ctx, log, obj, addrs)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// -----------------------------------------------------------------------------
+// MCSV1Alpha1 ServiceImport - Reconciler
+// -----------------------------------------------------------------------------
+
+// MCSV1Alpha1ServiceImportReconciler reconciles ServiceImport resources
+type MCSV1Alpha1ServiceImportReconciler struct {
+	client.Client
+
+	Log             logr.Logger
+	Scheme          *runtime.Scheme
+	DataplaneClient *dataplane.KongClient
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *MCSV1Alpha1ServiceImportReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c, err := controller.New("MCSV1Alpha1ServiceImport", mgr, controller.Options{
+		Reconciler: r,
+		LogConstructor: func(_ *reconcile.Request) logr.Logger {
+			return r.Log
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return c.Watch(
+		&source.Kind{Type: &mcsv1alpha1.ServiceImport{}},
+		&handler.EnqueueRequestForObject{},
+	)
+}
+
+//+kubebuilder:rbac:groups=multicluster.x-k8s.io,resources=serviceimports,verbs=get;list;watch
+
+// Reconcile processes the watched objects
+func (r *MCSV1Alpha1ServiceImportReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	log := r.Log.WithValues("MCSV1Alpha1ServiceImport", req.NamespacedName)
+
+	// trace the reconciliation, and link it to the configuration update applying it
+	ctx, span := tracing.Tracer().Start(ctx, "Reconcile MCSV1Alpha1ServiceImport",
+		trace.WithAttributes(tracing.ObjectAttributes("ServiceImport", req.NamespacedName)...))
+	defer func() { tracing.EndSpan(span, err) }()
+	r.DataplaneClient.TraceChange(ctx)
+
+	// get the relevant object
+	obj := new(mcsv1alpha1.ServiceImport)
+	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
+		if errors.IsNotFound(err) {
+			obj.Namespace = req.Namespace
+			obj.Name = req.Name
+			return ctrl.Result{}, r.DataplaneClient.DeleteObject(obj)
+		}
+		return ctrl.Result{}, err
+	}
+	log.V(util.DebugLevel).Info("reconciling resource", "namespace", req.Namespace, "name", req.Name)
+
+	// clean the object up if it's being deleted
+	if !obj.DeletionTimestamp.IsZero() && time.Now().After(obj.DeletionTimestamp.Time) {
+		log.V(util.DebugLevel).Info("resource is being deleted, its configuration will be removed", "type", "ServiceImport", "namespace", req.Namespace, "name", req.Name)
+		objectExistsInCache, err := r.DataplaneClient.ObjectExists(obj)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if objectExistsInCache {
+			if err := r.DataplaneClient.DeleteObject(obj); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{Requeue: true}, nil // wait until the object is no longer present in the cache
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// update the kong Admin API with the changes
+	if err := r.DataplaneClient.UpdateObject(obj); err != nil {
+		return ctrl.Result{}, err
 	}
 
 	return ctrl.Result{}, nil
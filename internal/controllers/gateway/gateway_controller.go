@@ -9,6 +9,7 @@ import (
 	"github.com/kong/go-kong/kong"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -22,6 +23,7 @@ import (
 	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
+	ctrlutils "github.com/kong/kubernetes-ingress-controller/v2/internal/controllers/utils"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane"
 )
 
@@ -105,6 +107,26 @@ func (r *GatewayReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return err
 	}
 
+	// if a route attached to a gateway is updated, trigger reconciliation of the
+	// gateways it references so that the number of routes attached to each of their
+	// listeners is kept up to date. Routes whose CRDs are not installed are skipped.
+	for resource, route := range map[string]client.Object{
+		"httproutes": &gatewayv1alpha2.HTTPRoute{},
+		"tcproutes":  &gatewayv1alpha2.TCPRoute{},
+		"udproutes":  &gatewayv1alpha2.UDPRoute{},
+		"tlsroutes":  &gatewayv1alpha2.TLSRoute{},
+	} {
+		if !ctrlutils.CRDExists(mgr.GetClient(), gatewayv1alpha2.SchemeGroupVersion.WithResource(resource)) {
+			continue
+		}
+		if err := c.Watch(
+			&source.Kind{Type: route},
+			handler.EnqueueRequestsFromMapFunc(listGatewaysForRoute),
+		); err != nil {
+			return err
+		}
+	}
+
 	// start the required gatewayclass controller as well
 	gwcCTRL := &GatewayClassReconciler{
 		Client: r.Client,
@@ -186,6 +208,35 @@ func (r *GatewayReconciler) listGatewaysForService(svc client.Object) (recs []re
 	return
 }
 
+// listGatewaysForRoute is a watch predicate which finds all the gateway objects referenced
+// by a route to enqueue them for reconciliation, so that their listener statuses reflect
+// the routes attached to them.
+func listGatewaysForRoute(route client.Object) (recs []reconcile.Request) {
+	parentRefs, err := parentRefsForRoute(route)
+	if err != nil {
+		return nil
+	}
+	for _, parentRef := range parentRefs {
+		if parentRef.Group != nil && *parentRef.Group != gatewayV1alpha2Group {
+			continue
+		}
+		if parentRef.Kind != nil && *parentRef.Kind != "Gateway" {
+			continue
+		}
+		namespace := route.GetNamespace()
+		if parentRef.Namespace != nil {
+			namespace = string(*parentRef.Namespace)
+		}
+		recs = append(recs, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: namespace,
+				Name:      string(parentRef.Name),
+			},
+		})
+	}
+	return
+}
+
 // isGatewayService is a watch predicate that filters out events for objects that aren't
 // the gateway service referenced by --publish-service.
 func (r *GatewayReconciler) isGatewayService(obj client.Object) bool {
@@ -349,7 +400,17 @@ func (r *GatewayReconciler) reconcileUnmanagedGateway(ctx context.Context, log l
 	// a single set of shared listens. We lack knowledge of whether this is compatible with user intent, and it may
 	// be incompatible with the spec, so we should consider evaluating cross-Gateway compatibility and raising error
 	// conditions in the event of a problem
-	listenerStatuses := getListenerStatus(gateway, kongListeners)
+	debug(log, gateway, "counting the routes attached to the gateway listeners")
+	routes, err := r.listRoutes(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	namespaces, err := r.listNamespacesForSelectors(ctx, gateway)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	listenerToAttached := getAttachedRoutesForListeners(gateway, routes, namespaces)
+	listenerStatuses := getListenerStatus(gateway, kongListeners, listenerToAttached)
 
 	// once specification matches the reference Service, all that's left to do is ensure that the
 	// Gateway status reflects the spec. As the status is simply a mirror of the Service, this is
@@ -377,24 +438,78 @@ func (r *GatewayReconciler) reconcileUnmanagedGateway(ctx context.Context, log l
 // -----------------------------------------------------------------------------
 
 var (
-	// supportedKinds indicates which gateway kinds are supported by this implementation
-	supportedKinds = []gatewayv1alpha2.Kind{
-		gatewayv1alpha2.Kind("HTTPRoute"),
+	// routeKindsByProtocol indicates which route kinds are supported by this implementation
+	// for listeners of each protocol.
+	routeKindsByProtocol = map[gatewayv1alpha2.ProtocolType][]gatewayv1alpha2.Kind{
+		gatewayv1alpha2.HTTPProtocolType:  {gatewayv1alpha2.Kind("HTTPRoute")},
+		gatewayv1alpha2.HTTPSProtocolType: {gatewayv1alpha2.Kind("HTTPRoute")},
+		gatewayv1alpha2.TCPProtocolType:   {gatewayv1alpha2.Kind("TCPRoute")},
+		gatewayv1alpha2.UDPProtocolType:   {gatewayv1alpha2.Kind("UDPRoute")},
+		gatewayv1alpha2.TLSProtocolType:   {gatewayv1alpha2.Kind("TLSRoute")},
 	}
-
-	// supportedRouteGroupKinds indicates the full kinds with GVK that are supported by this implementation
-	supportedRouteGroupKinds []gatewayv1alpha2.RouteGroupKind
 )
 
-func init() {
-	// gather the supported RouteGroupKinds for the Gateway listeners
-	group := gatewayv1alpha2.Group(gatewayv1alpha2.GroupName)
-	for _, supportedKind := range supportedKinds {
-		supportedRouteGroupKinds = append(supportedRouteGroupKinds, gatewayv1alpha2.RouteGroupKind{
-			Group: &group,
-			Kind:  supportedKind,
-		})
+// listRoutes provides all the Gateway API routes of the kinds supported by this implementation.
+// Route kinds whose CRDs are not installed in the cluster are skipped.
+func (r *GatewayReconciler) listRoutes(ctx context.Context) ([]client.Object, error) {
+	var routes []client.Object
+
+	httpRoutes := &gatewayv1alpha2.HTTPRouteList{}
+	if err := r.Client.List(ctx, httpRoutes); err != nil && !meta.IsNoMatchError(err) {
+		return nil, fmt.Errorf("failed to list httproutes: %w", err)
+	}
+	for i := range httpRoutes.Items {
+		routes = append(routes, &httpRoutes.Items[i])
+	}
+
+	tcpRoutes := &gatewayv1alpha2.TCPRouteList{}
+	if err := r.Client.List(ctx, tcpRoutes); err != nil && !meta.IsNoMatchError(err) {
+		return nil, fmt.Errorf("failed to list tcproutes: %w", err)
 	}
+	for i := range tcpRoutes.Items {
+		routes = append(routes, &tcpRoutes.Items[i])
+	}
+
+	udpRoutes := &gatewayv1alpha2.UDPRouteList{}
+	if err := r.Client.List(ctx, udpRoutes); err != nil && !meta.IsNoMatchError(err) {
+		return nil, fmt.Errorf("failed to list udproutes: %w", err)
+	}
+	for i := range udpRoutes.Items {
+		routes = append(routes, &udpRoutes.Items[i])
+	}
+
+	tlsRoutes := &gatewayv1alpha2.TLSRouteList{}
+	if err := r.Client.List(ctx, tlsRoutes); err != nil && !meta.IsNoMatchError(err) {
+		return nil, fmt.Errorf("failed to list tlsroutes: %w", err)
+	}
+	for i := range tlsRoutes.Items {
+		routes = append(routes, &tlsRoutes.Items[i])
+	}
+
+	return routes, nil
+}
+
+// listNamespacesForSelectors provides the namespaces in the cluster, indexed by name, if any
+// of the gateway's listeners only allows routes from namespaces matching a label selector.
+// Otherwise the namespace labels are not needed and nothing is listed.
+func (r *GatewayReconciler) listNamespacesForSelectors(ctx context.Context, gateway *gatewayv1alpha2.Gateway) (map[string]corev1.Namespace, error) {
+	for _, listener := range gateway.Spec.Listeners {
+		if listener.AllowedRoutes == nil || listener.AllowedRoutes.Namespaces == nil ||
+			listener.AllowedRoutes.Namespaces.From == nil ||
+			*listener.AllowedRoutes.Namespaces.From != gatewayv1alpha2.NamespacesFromSelector {
+			continue
+		}
+		namespaces := &corev1.NamespaceList{}
+		if err := r.Client.List(ctx, namespaces); err != nil {
+			return nil, fmt.Errorf("failed to list namespaces: %w", err)
+		}
+		byName := make(map[string]corev1.Namespace, len(namespaces.Items))
+		for _, namespace := range namespaces.Items {
+			byName[namespace.Name] = namespace
+		}
+		return byName, nil
+	}
+	return nil, nil
 }
 
 // determineServiceForGateway provides the "publish service" (aka the proxy Service) object which
@@ -555,14 +670,18 @@ func (r *GatewayReconciler) determineListenersFromDataPlane(ctx context.Context,
 
 // updateAddressesAndListenersStatus updates a unmanaged gateway's status with new addresses and listeners.
 // If the addresses and listeners provided are the same as what exists, it is assumed that reconciliation is complete and a Ready condition is posted.
+// Listener statuses are updated whenever they change (e.g. when routes are attached to or detached from a listener).
 func (r *GatewayReconciler) updateAddressesAndListenersStatus(
 	ctx context.Context,
 	gateway *gatewayv1alpha2.Gateway,
 	listenerStatuses []gatewayv1alpha2.ListenerStatus,
 ) (bool, error) {
+	if isGatewayReady(gateway) && areListenerStatusesEqual(gateway.Status.Listeners, listenerStatuses) {
+		return false, nil
+	}
+	gateway.Status.Listeners = listenerStatuses
+	gateway.Status.Addresses = gateway.Spec.Addresses
 	if !isGatewayReady(gateway) {
-		gateway.Status.Listeners = listenerStatuses
-		gateway.Status.Addresses = gateway.Spec.Addresses
 		gateway.Status.Conditions = append(gateway.Status.Conditions, metav1.Condition{
 			Type:               string(gatewayv1alpha2.GatewayConditionReady),
 			Status:             metav1.ConditionTrue,
@@ -571,9 +690,8 @@ func (r *GatewayReconciler) updateAddressesAndListenersStatus(
 			Reason:             string(gatewayv1alpha2.GatewayReasonReady),
 			Message:            "addresses and listeners for the Gateway resource were successfully updated",
 		})
-		return true, r.Status().Update(ctx, pruneGatewayStatusConds(gateway))
 	}
-	return false, nil
+	return true, r.Status().Update(ctx, pruneGatewayStatusConds(gateway))
 }
 
 // areAllowedRoutesConsistentByProtocol returns an error if a set of listeners includes multiple listeners for the same
@@ -2,10 +2,13 @@ package gateway
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
@@ -405,3 +408,175 @@ func Test_areAllowedRoutesConsistentByProtocol(t *testing.T) {
 		assert.Equal(t, input.expected, areAllowedRoutesConsistentByProtocol(input.l), input.message)
 	}
 }
+
+func Test_getListenerSupportedRouteKinds(t *testing.T) {
+	otherGroup := gatewayv1alpha2.Group("example.com")
+
+	inputs := []struct {
+		message           string
+		listener          gatewayv1alpha2.Listener
+		expectedKinds     []gatewayv1alpha2.Kind
+		expectedSupported bool
+	}{
+		{
+			message:           "defaults to the kinds supported for the protocol",
+			listener:          gatewayv1alpha2.Listener{Protocol: gatewayv1alpha2.HTTPSProtocolType},
+			expectedKinds:     []gatewayv1alpha2.Kind{"HTTPRoute"},
+			expectedSupported: true,
+		},
+		{
+			message: "allowed kinds matching the protocol are supported",
+			listener: gatewayv1alpha2.Listener{
+				Protocol: gatewayv1alpha2.TCPProtocolType,
+				AllowedRoutes: &gatewayv1alpha2.AllowedRoutes{
+					Kinds: []gatewayv1alpha2.RouteGroupKind{{Kind: "TCPRoute"}},
+				},
+			},
+			expectedKinds:     []gatewayv1alpha2.Kind{"TCPRoute"},
+			expectedSupported: true,
+		},
+		{
+			message: "allowed kinds not matching the protocol are filtered out",
+			listener: gatewayv1alpha2.Listener{
+				Protocol: gatewayv1alpha2.UDPProtocolType,
+				AllowedRoutes: &gatewayv1alpha2.AllowedRoutes{
+					Kinds: []gatewayv1alpha2.RouteGroupKind{{Kind: "UDPRoute"}, {Kind: "HTTPRoute"}},
+				},
+			},
+			expectedKinds:     []gatewayv1alpha2.Kind{"UDPRoute"},
+			expectedSupported: false,
+		},
+		{
+			message: "allowed kinds from other groups are filtered out",
+			listener: gatewayv1alpha2.Listener{
+				Protocol: gatewayv1alpha2.HTTPProtocolType,
+				AllowedRoutes: &gatewayv1alpha2.AllowedRoutes{
+					Kinds: []gatewayv1alpha2.RouteGroupKind{{Group: &otherGroup, Kind: "HTTPRoute"}},
+				},
+			},
+			expectedKinds:     []gatewayv1alpha2.Kind{},
+			expectedSupported: false,
+		},
+	}
+	for _, input := range inputs {
+		kinds, supported := getListenerSupportedRouteKinds(input.listener)
+		assert.Equal(t, input.expectedSupported, supported, input.message)
+		actualKinds := make([]gatewayv1alpha2.Kind, 0, len(kinds))
+		for _, kind := range kinds {
+			assert.Equal(t, gatewayV1alpha2Group, *kind.Group, input.message)
+			actualKinds = append(actualKinds, kind.Kind)
+		}
+		assert.Equal(t, input.expectedKinds, actualKinds, input.message)
+	}
+}
+
+func Test_getAttachedRoutesForListeners(t *testing.T) {
+	same := gatewayv1alpha2.NamespacesFromSame
+	selector := gatewayv1alpha2.NamespacesFromSelector
+	sectionName := gatewayv1alpha2.SectionName("tcp")
+	otherNamespace := gatewayv1alpha2.Namespace("other")
+
+	gateway := &gatewayv1alpha2.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kong"},
+		Spec: gatewayv1alpha2.GatewaySpec{
+			Listeners: []gatewayv1alpha2.Listener{
+				{Name: "http", Protocol: gatewayv1alpha2.HTTPProtocolType, Port: 80},
+				{
+					Name:     "https",
+					Protocol: gatewayv1alpha2.HTTPSProtocolType,
+					Port:     443,
+					AllowedRoutes: &gatewayv1alpha2.AllowedRoutes{
+						Namespaces: &gatewayv1alpha2.RouteNamespaces{From: &same},
+					},
+				},
+				{
+					Name:     "tcp",
+					Protocol: gatewayv1alpha2.TCPProtocolType,
+					Port:     8888,
+					AllowedRoutes: &gatewayv1alpha2.AllowedRoutes{
+						Namespaces: &gatewayv1alpha2.RouteNamespaces{
+							From:     &selector,
+							Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"tcp": "allowed"}},
+						},
+					},
+				},
+			},
+		},
+	}
+	namespaces := map[string]corev1.Namespace{
+		"default": {ObjectMeta: metav1.ObjectMeta{Name: "default", Labels: map[string]string{"tcp": "allowed"}}},
+		"other":   {ObjectMeta: metav1.ObjectMeta{Name: "other"}},
+	}
+
+	routes := []client.Object{
+		// attached to both HTTP listeners
+		&gatewayv1alpha2.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "same-namespace"},
+			Spec: gatewayv1alpha2.HTTPRouteSpec{CommonRouteSpec: gatewayv1alpha2.CommonRouteSpec{
+				ParentRefs: []gatewayv1alpha2.ParentReference{{Name: "kong"}},
+			}},
+		},
+		// only attached to the listener allowing all namespaces
+		&gatewayv1alpha2.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "other-namespace"},
+			Spec: gatewayv1alpha2.HTTPRouteSpec{CommonRouteSpec: gatewayv1alpha2.CommonRouteSpec{
+				ParentRefs: []gatewayv1alpha2.ParentReference{{Name: "kong", Namespace: (*gatewayv1alpha2.Namespace)(&gateway.Namespace)}},
+			}},
+		},
+		// references a different gateway
+		&gatewayv1alpha2.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "other-gateway"},
+			Spec: gatewayv1alpha2.HTTPRouteSpec{CommonRouteSpec: gatewayv1alpha2.CommonRouteSpec{
+				ParentRefs: []gatewayv1alpha2.ParentReference{{Name: "kong", Namespace: &otherNamespace}},
+			}},
+		},
+		// attached to the TCP listener by section name
+		&gatewayv1alpha2.TCPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "tcp"},
+			Spec: gatewayv1alpha2.TCPRouteSpec{CommonRouteSpec: gatewayv1alpha2.CommonRouteSpec{
+				ParentRefs: []gatewayv1alpha2.ParentReference{{Name: "kong", SectionName: &sectionName}},
+			}},
+		},
+		// not attached as the namespace doesn't match the TCP listener selector
+		&gatewayv1alpha2.TCPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "tcp"},
+			Spec: gatewayv1alpha2.TCPRouteSpec{CommonRouteSpec: gatewayv1alpha2.CommonRouteSpec{
+				ParentRefs: []gatewayv1alpha2.ParentReference{{Name: "kong", Namespace: (*gatewayv1alpha2.Namespace)(&gateway.Namespace)}},
+			}},
+		},
+		// not attached as no listener supports UDPRoutes
+		&gatewayv1alpha2.UDPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "udp"},
+			Spec: gatewayv1alpha2.UDPRouteSpec{CommonRouteSpec: gatewayv1alpha2.CommonRouteSpec{
+				ParentRefs: []gatewayv1alpha2.ParentReference{{Name: "kong"}},
+			}},
+		},
+	}
+
+	assert.Equal(t, listenerAttachedMap{
+		"http":  2,
+		"https": 1,
+		"tcp":   1,
+	}, getAttachedRoutesForListeners(gateway, routes, namespaces))
+}
+
+func Test_areListenerStatusesEqual(t *testing.T) {
+	status := func(attached int32, transitionTime metav1.Time) []gatewayv1alpha2.ListenerStatus {
+		return []gatewayv1alpha2.ListenerStatus{{
+			Name:           "http",
+			AttachedRoutes: attached,
+			Conditions: []metav1.Condition{{
+				Type:               string(gatewayv1alpha2.ListenerConditionReady),
+				Status:             metav1.ConditionTrue,
+				Reason:             string(gatewayv1alpha2.ListenerReasonReady),
+				LastTransitionTime: transitionTime,
+			}},
+		}}
+	}
+	before := metav1.NewTime(metav1.Now().Add(-time.Hour))
+	now := metav1.Now()
+
+	assert.True(t, areListenerStatusesEqual(status(1, before), status(1, now)), "transition times are ignored")
+	assert.False(t, areListenerStatusesEqual(status(1, now), status(2, now)), "attached routes are compared")
+	assert.False(t, areListenerStatusesEqual(status(1, now), nil), "listeners are compared")
+}
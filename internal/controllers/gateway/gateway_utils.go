@@ -6,7 +6,9 @@ import (
 	"strings"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -143,32 +145,212 @@ func buildKongPortMap(listens []gatewayv1alpha2.Listener) protocolPortMap {
 }
 
 // initializeListenerMaps takes a Gateway and builds indices used in status updates and conflict detection. It returns
-// empty maps from port to protocol to listener name and from port to hostnames, and a populated map from listener name
-// to attached route count from their status
+// empty maps from port to protocol to listener name and from port to hostnames.
 func initializeListenerMaps(gateway *gatewayv1alpha2.Gateway) (
 	portProtocolMap,
 	portHostnameMap,
-	listenerAttachedMap,
 ) {
 	portToProtocol := make(portProtocolMap, len(gateway.Status.Listeners))
 	portToHostname := make(portHostnameMap, len(gateway.Status.Listeners))
-	listenerToAttached := make(listenerAttachedMap, len(gateway.Status.Listeners))
 
-	existingStatuses := make(map[gatewayv1alpha2.SectionName]gatewayv1alpha2.ListenerStatus,
-		len(gateway.Status.Listeners))
-	for _, listenerStatus := range gateway.Status.Listeners {
-		existingStatuses[listenerStatus.Name] = listenerStatus
+	for _, listener := range gateway.Spec.Listeners {
+		portToHostname[listener.Port] = make(map[gatewayv1alpha2.Hostname]bool)
+	}
+	return portToProtocol, portToHostname
+}
+
+// getListenerSupportedRouteKinds provides the route kinds a listener supports: the kinds supported by this
+// implementation for the listener protocol, filtered by the kinds requested in the listener's AllowedRoutes (if any).
+// It also reports whether all the requested kinds are supported.
+func getListenerSupportedRouteKinds(listener gatewayv1alpha2.Listener) ([]gatewayv1alpha2.RouteGroupKind, bool) {
+	protocolKinds := routeKindsByProtocol[listener.Protocol]
+
+	if listener.AllowedRoutes == nil || len(listener.AllowedRoutes.Kinds) == 0 {
+		supportedKinds := make([]gatewayv1alpha2.RouteGroupKind, 0, len(protocolKinds))
+		for _, kind := range protocolKinds {
+			group := gatewayV1alpha2Group
+			supportedKinds = append(supportedKinds, gatewayv1alpha2.RouteGroupKind{
+				Group: &group,
+				Kind:  kind,
+			})
+		}
+		return supportedKinds, true
+	}
+
+	supportedKinds := make([]gatewayv1alpha2.RouteGroupKind, 0, len(listener.AllowedRoutes.Kinds))
+	allSupported := true
+	for _, requested := range listener.AllowedRoutes.Kinds {
+		group := gatewayV1alpha2Group
+		if requested.Group != nil && *requested.Group != "" {
+			group = *requested.Group
+		}
+		supported := false
+		if group == gatewayV1alpha2Group {
+			for _, kind := range protocolKinds {
+				if requested.Kind == kind {
+					supported = true
+					break
+				}
+			}
+		}
+		if !supported {
+			allSupported = false
+			continue
+		}
+		supportedKinds = append(supportedKinds, gatewayv1alpha2.RouteGroupKind{
+			Group: &group,
+			Kind:  requested.Kind,
+		})
+	}
+	return supportedKinds, allSupported
+}
+
+// routeKind provides the kind of a Gateway APIs route object, which may come from the cache
+// without its type information.
+func routeKind(route client.Object) gatewayv1alpha2.Kind {
+	switch route.(type) {
+	case *gatewayv1alpha2.HTTPRoute:
+		return gatewayv1alpha2.Kind("HTTPRoute")
+	case *gatewayv1alpha2.TCPRoute:
+		return gatewayv1alpha2.Kind("TCPRoute")
+	case *gatewayv1alpha2.UDPRoute:
+		return gatewayv1alpha2.Kind("UDPRoute")
+	case *gatewayv1alpha2.TLSRoute:
+		return gatewayv1alpha2.Kind("TLSRoute")
+	default:
+		return ""
 	}
+}
 
+// isRouteNamespaceAllowed reports whether a listener allows routes from the given namespace. Listeners without
+// AllowedRoutes allow routes from any namespace. The namespaces map (indexed by name) is only consulted for listeners
+// which select namespaces by labels.
+func isRouteNamespaceAllowed(
+	gateway *gatewayv1alpha2.Gateway,
+	listener gatewayv1alpha2.Listener,
+	routeNamespace string,
+	namespaces map[string]corev1.Namespace,
+) bool {
+	if listener.AllowedRoutes == nil || listener.AllowedRoutes.Namespaces == nil ||
+		listener.AllowedRoutes.Namespaces.From == nil {
+		return true
+	}
+	switch *listener.AllowedRoutes.Namespaces.From {
+	case gatewayv1alpha2.NamespacesFromAll:
+		return true
+	case gatewayv1alpha2.NamespacesFromSame:
+		return routeNamespace == gateway.Namespace
+	case gatewayv1alpha2.NamespacesFromSelector:
+		selector, err := metav1.LabelSelectorAsSelector(listener.AllowedRoutes.Namespaces.Selector)
+		if err != nil {
+			return false
+		}
+		namespace, ok := namespaces[routeNamespace]
+		if !ok {
+			return false
+		}
+		return selector.Matches(labels.Set(namespace.Labels))
+	default:
+		return false
+	}
+}
+
+// getAttachedRoutesForListeners counts the routes attached to each of the gateway's listeners. A route is attached to
+// a listener if one of its parentRefs references the gateway (and the listener, if a section name or port is given),
+// and the listener supports the route's kind and allows routes from the route's namespace.
+func getAttachedRoutesForListeners(
+	gateway *gatewayv1alpha2.Gateway,
+	routes []client.Object,
+	namespaces map[string]corev1.Namespace,
+) listenerAttachedMap {
+	listenerToAttached := make(listenerAttachedMap, len(gateway.Spec.Listeners))
+	listenerToKinds := make(map[gatewayv1alpha2.SectionName][]gatewayv1alpha2.RouteGroupKind, len(gateway.Spec.Listeners))
 	for _, listener := range gateway.Spec.Listeners {
-		portToHostname[listener.Port] = make(map[gatewayv1alpha2.Hostname]bool)
-		if existingStatus, ok := existingStatuses[listener.Name]; ok {
-			listenerToAttached[listener.Name] = existingStatus.AttachedRoutes
-		} else {
-			listenerToAttached[listener.Name] = 0
+		listenerToAttached[listener.Name] = 0
+		listenerToKinds[listener.Name], _ = getListenerSupportedRouteKinds(listener)
+	}
+
+	for _, route := range routes {
+		parentRefs, err := parentRefsForRoute(route)
+		if err != nil {
+			continue
+		}
+		kind := routeKind(route)
+
+		// a route is counted at most once per listener, even if it references it through multiple parentRefs
+		attached := make(map[gatewayv1alpha2.SectionName]bool, len(gateway.Spec.Listeners))
+		for _, parentRef := range parentRefs {
+			if !isParentRefForGateway(parentRef, route.GetNamespace(), gateway) {
+				continue
+			}
+			for _, listener := range gateway.Spec.Listeners {
+				if parentRef.SectionName != nil && *parentRef.SectionName != listener.Name {
+					continue
+				}
+				if parentRef.Port != nil && *parentRef.Port != listener.Port {
+					continue
+				}
+				if !isRouteKindSupported(listenerToKinds[listener.Name], kind) {
+					continue
+				}
+				if !isRouteNamespaceAllowed(gateway, listener, route.GetNamespace(), namespaces) {
+					continue
+				}
+				attached[listener.Name] = true
+			}
+		}
+		for name := range attached {
+			listenerToAttached[name]++
+		}
+	}
+	return listenerToAttached
+}
+
+// isParentRefForGateway reports whether a route parentRef references the given gateway.
+func isParentRefForGateway(parentRef gatewayv1alpha2.ParentReference, routeNamespace string, gateway *gatewayv1alpha2.Gateway) bool {
+	if parentRef.Group != nil && *parentRef.Group != gatewayV1alpha2Group {
+		return false
+	}
+	if parentRef.Kind != nil && *parentRef.Kind != "Gateway" {
+		return false
+	}
+	namespace := routeNamespace
+	if parentRef.Namespace != nil {
+		namespace = string(*parentRef.Namespace)
+	}
+	return namespace == gateway.Namespace && string(parentRef.Name) == gateway.Name
+}
+
+func isRouteKindSupported(supportedKinds []gatewayv1alpha2.RouteGroupKind, kind gatewayv1alpha2.Kind) bool {
+	for _, supported := range supportedKinds {
+		if supported.Kind == kind {
+			return true
 		}
 	}
-	return portToProtocol, portToHostname, listenerToAttached
+	return false
+}
+
+// areListenerStatusesEqual compares two sets of listener statuses, ignoring the transition time of their conditions
+// as those are regenerated on every reconciliation.
+func areListenerStatusesEqual(a, b []gatewayv1alpha2.ListenerStatus) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name || a[i].AttachedRoutes != b[i].AttachedRoutes ||
+			!reflect.DeepEqual(a[i].SupportedKinds, b[i].SupportedKinds) ||
+			len(a[i].Conditions) != len(b[i].Conditions) {
+			return false
+		}
+		for j := range a[i].Conditions {
+			condA, condB := a[i].Conditions[j], b[i].Conditions[j]
+			if condA.Type != condB.Type || condA.Status != condB.Status || condA.Reason != condB.Reason ||
+				condA.Message != condB.Message || condA.ObservedGeneration != condB.ObservedGeneration {
+				return false
+			}
+		}
+	}
+	return true
 }
 
 func canSharePort(requested gatewayv1alpha2.ProtocolType, existing gatewayv1alpha2.ProtocolType) bool {
@@ -203,9 +385,10 @@ func canSharePort(requested gatewayv1alpha2.ProtocolType, existing gatewayv1alph
 func getListenerStatus(
 	gateway *gatewayv1alpha2.Gateway,
 	kongListens []gatewayv1alpha2.Listener,
+	listenerToAttached listenerAttachedMap,
 ) []gatewayv1alpha2.ListenerStatus {
 	statuses := make(map[gatewayv1alpha2.SectionName]gatewayv1alpha2.ListenerStatus, len(gateway.Spec.Listeners))
-	portToProtocol, portToHostname, listenerToAttached := initializeListenerMaps(gateway)
+	portToProtocol, portToHostname := initializeListenerMaps(gateway)
 	kongProtocolsToPort := buildKongPortMap(kongListens)
 	conflictedPorts := make(map[gatewayv1alpha2.PortNumber]bool, len(gateway.Spec.Listeners))
 	conflictedHostnames := make(map[gatewayv1alpha2.PortNumber]map[gatewayv1alpha2.Hostname]bool, len(gateway.Spec.Listeners))
@@ -217,13 +400,24 @@ func getListenerStatus(
 		if listener.Hostname != nil {
 			hostname = *listener.Hostname
 		}
+		supportedKinds, allKindsSupported := getListenerSupportedRouteKinds(listener)
 		status := gatewayv1alpha2.ListenerStatus{
 			Name:           listener.Name,
 			Conditions:     []metav1.Condition{},
-			SupportedKinds: supportedRouteGroupKinds,
-			// this has been populated by initializeListenerMaps()
+			SupportedKinds: supportedKinds,
+			// this has been populated by getAttachedRoutesForListeners()
 			AttachedRoutes: listenerToAttached[listener.Name],
 		}
+		if !allKindsSupported {
+			status.Conditions = append(status.Conditions, metav1.Condition{
+				Type:               string(gatewayv1alpha2.ListenerConditionResolvedRefs),
+				Status:             metav1.ConditionFalse,
+				ObservedGeneration: gateway.Generation,
+				LastTransitionTime: metav1.Now(),
+				Reason:             string(gatewayv1alpha2.ListenerReasonInvalidRouteKinds),
+				Message:            "the listener allows route kinds which are not supported for its protocol",
+			})
+		}
 		// TODO this only handles some Listener conditions and reasons as needed to check cross-listener compatibility
 		// and unattachability due to missing Kong configuration. There are others available and it may be appropriate
 		// for us to add them https://github.com/Kong/kubernetes-ingress-controller/issues/2558
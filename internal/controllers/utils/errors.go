@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// StatusUpdateResult provides the result of a reconciliation depending on the
+// class of the error of its status update: conflicts are requeued right away as
+// the object changed in the meantime, while updates refused because of the
+// permissions of the controller aren't retried until the object changes again,
+// as retrying them can't succeed until the permissions are fixed. Other errors
+// are retried with the default backoff.
+func StatusUpdateResult(log logr.Logger, err error) (ctrl.Result, error) {
+	switch {
+	case err == nil, apierrors.IsNotFound(err):
+		return ctrl.Result{}, nil
+	case apierrors.IsConflict(err):
+		return ctrl.Result{Requeue: true}, nil
+	case apierrors.IsForbidden(err), apierrors.IsUnauthorized(err):
+		log.Error(err, "the controller isn't allowed to update the status, not retrying until the object changes")
+		return ctrl.Result{}, nil
+	default:
+		return ctrl.Result{}, err
+	}
+}
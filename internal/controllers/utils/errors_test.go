@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestStatusUpdateResult(t *testing.T) {
+	gr := schema.GroupResource{Group: "networking.k8s.io", Resource: "ingresses"}
+	for _, tt := range []struct {
+		name       string
+		err        error
+		wantResult ctrl.Result
+		wantErr    bool
+	}{
+		{
+			name: "success",
+		},
+		{
+			name: "deleted object",
+			err:  apierrors.NewNotFound(gr, "foo"),
+		},
+		{
+			name:       "conflict",
+			err:        apierrors.NewConflict(gr, "foo", errors.New("the object has been modified")),
+			wantResult: ctrl.Result{Requeue: true},
+		},
+		{
+			name: "forbidden",
+			err:  apierrors.NewForbidden(gr, "foo", errors.New("RBAC denied")),
+		},
+		{
+			name: "unauthorized",
+			err:  apierrors.NewUnauthorized("invalid token"),
+		},
+		{
+			name:    "other error",
+			err:     apierrors.NewServiceUnavailable("try again"),
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := StatusUpdateResult(logr.Discard(), tt.err)
+			assert.Equal(t, tt.wantResult, result)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
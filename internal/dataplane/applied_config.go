@@ -0,0 +1,88 @@
+package dataplane
+
+import (
+	"context"
+	"encoding/hex"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// -----------------------------------------------------------------------------
+// Dataplane Client - Applied Configuration Records
+// -----------------------------------------------------------------------------
+
+const (
+	// AppliedConfigHashKey is the key of the checksum of the applied configuration
+	// in the applied configuration ConfigMap.
+	AppliedConfigHashKey = "config-hash"
+	// AppliedConfigTimestampKey is the key of the time the configuration was
+	// applied at (in RFC 3339 format) in the applied configuration ConfigMap.
+	AppliedConfigTimestampKey = "applied-at"
+	// AppliedConfigControllerVersionKey is the key of the version of the controller
+	// which applied the configuration in the applied configuration ConfigMap.
+	AppliedConfigControllerVersionKey = "controller-version"
+)
+
+// AppliedConfigurationRecorder records which configuration the data-plane is
+// serving after each successful update.
+type AppliedConfigurationRecorder interface {
+	RecordAppliedConfiguration(ctx context.Context, configSHA []byte, appliedAt time.Time) error
+}
+
+// ConfigMapAppliedConfigurationRecorder records the applied configuration in a
+// ConfigMap, so that external tooling (and humans) can verify which generation
+// of the configuration the data-plane is serving. The ConfigMap is created if
+// it doesn't exist yet.
+type ConfigMapAppliedConfigurationRecorder struct {
+	// Client is used to read and write the ConfigMap. It should not be backed
+	// by a cache, so that ConfigMaps don't need to be watched.
+	Client client.Client
+
+	// ConfigMap is the namespace and name of the ConfigMap.
+	ConfigMap k8stypes.NamespacedName
+
+	// ControllerVersion is the version of the controller recorded along with
+	// the configuration.
+	ControllerVersion string
+}
+
+// RecordAppliedConfiguration writes the checksum of the applied configuration,
+// the time it was applied at and the controller version to the ConfigMap.
+func (r *ConfigMapAppliedConfigurationRecorder) RecordAppliedConfiguration(
+	ctx context.Context,
+	configSHA []byte,
+	appliedAt time.Time,
+) error {
+	data := map[string]string{
+		AppliedConfigHashKey:              hex.EncodeToString(configSHA),
+		AppliedConfigTimestampKey:         appliedAt.UTC().Format(time.RFC3339),
+		AppliedConfigControllerVersionKey: r.ControllerVersion,
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := r.Client.Get(ctx, r.ConfigMap, configMap); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		return r.Client.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      r.ConfigMap.Name,
+				Namespace: r.ConfigMap.Namespace,
+			},
+			Data: data,
+		})
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = make(map[string]string, len(data))
+	}
+	for k, v := range data {
+		configMap.Data[k] = v
+	}
+	return r.Client.Update(ctx, configMap)
+}
@@ -0,0 +1,55 @@
+package dataplane
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestConfigMapAppliedConfigurationRecorder(t *testing.T) {
+	ctx := context.Background()
+	nsn := k8stypes.NamespacedName{Namespace: "kong", Name: "applied-config"}
+	appliedAt := time.Date(2022, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("the ConfigMap is created if it doesn't exist", func(t *testing.T) {
+		k8sClient := fake.NewClientBuilder().Build()
+		recorder := &ConfigMapAppliedConfigurationRecorder{Client: k8sClient, ConfigMap: nsn, ControllerVersion: "2.5.0"}
+		require.NoError(t, recorder.RecordAppliedConfiguration(ctx, []byte{0xca, 0xfe}, appliedAt))
+
+		configMap := &corev1.ConfigMap{}
+		require.NoError(t, k8sClient.Get(ctx, nsn, configMap))
+		assert.Equal(t, map[string]string{
+			AppliedConfigHashKey:              "cafe",
+			AppliedConfigTimestampKey:         "2022-06-01T12:00:00Z",
+			AppliedConfigControllerVersionKey: "2.5.0",
+		}, configMap.Data)
+	})
+
+	t.Run("an existing ConfigMap is updated keeping other keys", func(t *testing.T) {
+		k8sClient := fake.NewClientBuilder().WithObjects(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: nsn.Namespace, Name: nsn.Name},
+			Data: map[string]string{
+				AppliedConfigHashKey: "beef",
+				"owner":              "platform-team",
+			},
+		}).Build()
+		recorder := &ConfigMapAppliedConfigurationRecorder{Client: k8sClient, ConfigMap: nsn, ControllerVersion: "2.5.0"}
+		require.NoError(t, recorder.RecordAppliedConfiguration(ctx, []byte{0xca, 0xfe}, appliedAt))
+
+		configMap := &corev1.ConfigMap{}
+		require.NoError(t, k8sClient.Get(ctx, nsn, configMap))
+		assert.Equal(t, map[string]string{
+			AppliedConfigHashKey:              "cafe",
+			AppliedConfigTimestampKey:         "2022-06-01T12:00:00Z",
+			AppliedConfigControllerVersionKey: "2.5.0",
+			"owner":                           "platform-team",
+		}, configMap.Data)
+	})
+}
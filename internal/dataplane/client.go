@@ -2,6 +2,7 @@ package dataplane
 
 import (
 	"context"
+	"time"
 )
 
 // -----------------------------------------------------------------------------
@@ -15,6 +16,27 @@ const (
 	// NOTE: the current default is based on observed latency in a CI environment using
 	// the GKE cloud provider with the Kong Admin API.
 	DefaultTimeoutSeconds float32 = 30.0
+
+	// DefaultTranslationTimeout indicates the time.Duration allowed for the
+	// translation of Kubernetes objects into Kong configuration before the
+	// result is discarded and the last good configuration is kept in place.
+	DefaultTranslationTimeout = time.Second * 30
+)
+
+const (
+	// KongConfigurationTranslationFailedEventReason is the reason of Events
+	// emitted for Kubernetes objects which couldn't be (fully) translated into
+	// Kong configuration.
+	KongConfigurationTranslationFailedEventReason = "KongConfigurationTranslationFailed"
+
+	// KongConfigurationApplyFailedEventReason is the reason of Events emitted
+	// for Kubernetes objects whose configuration was rejected by the data-plane.
+	KongConfigurationApplyFailedEventReason = "KongConfigurationApplyFailed"
+
+	// KongConfigurationAppliedEventReason is the reason of Events emitted, if
+	// enabled, for the changes to Kubernetes objects propagated to the
+	// data-plane.
+	KongConfigurationAppliedEventReason = "KongConfigurationApplied"
 )
 
 // -----------------------------------------------------------------------------
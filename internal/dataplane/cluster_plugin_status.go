@@ -0,0 +1,158 @@
+package dataplane
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/sendconfig"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
+	configurationv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+)
+
+// -----------------------------------------------------------------------------
+// Dataplane Client - KongClusterPlugin Status
+// -----------------------------------------------------------------------------
+
+// KongClusterPluginStatusUpdater updates the status of the global
+// KongClusterPlugins after each update of the data-plane configuration.
+type KongClusterPluginStatusUpdater interface {
+	UpdateKongClusterPluginStatus(ctx context.Context, clusterPlugin *configurationv1.KongClusterPlugin) error
+}
+
+// ClientKongClusterPluginStatusUpdater updates the status subresource of
+// KongClusterPlugins through a Kubernetes client.
+type ClientKongClusterPluginStatusUpdater struct {
+	Client client.Client
+}
+
+// UpdateKongClusterPluginStatus writes the status of the KongClusterPlugin.
+func (u *ClientKongClusterPluginStatusUpdater) UpdateKongClusterPluginStatus(
+	ctx context.Context,
+	clusterPlugin *configurationv1.KongClusterPlugin,
+) error {
+	return u.Client.Status().Update(ctx, clusterPlugin)
+}
+
+// updateClusterPluginStatuses reports whether the global KongClusterPlugins
+// are applied to the data-plane in their status, given the translation
+// failures of the update and the error applying its configuration, if any.
+// Only statuses which changed are written. Failures are only logged, as the
+// statuses will be computed again on the next update.
+func (c *KongClient) updateClusterPluginStatuses(
+	ctx context.Context,
+	storer store.Storer,
+	state *kongstate.KongState,
+	failures []parser.TranslationFailure,
+	applyErr error,
+) {
+	updater := c.KongClusterPluginStatusUpdater()
+	if updater == nil {
+		return
+	}
+	clusterPlugins, err := storer.ListGlobalKongClusterPlugins()
+	if err != nil {
+		c.logger.WithError(err).Error("failed to list global KongClusterPlugins")
+		return
+	}
+	for _, clusterPlugin := range clusterPlugins {
+		status := kongClusterPluginStatus(clusterPlugin, state, failures, applyErr)
+		if equality.Semantic.DeepEqual(clusterPlugin.Status, status) {
+			continue
+		}
+		clusterPlugin = clusterPlugin.DeepCopy()
+		clusterPlugin.Status = status
+		timedCtx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+		err := updater.UpdateKongClusterPluginStatus(timedCtx, clusterPlugin)
+		cancel()
+		if err != nil {
+			c.logger.WithField("kongclusterplugin_name", clusterPlugin.Name).WithError(err).
+				Error("failed to update the status of KongClusterPlugin")
+		}
+	}
+}
+
+// kongClusterPluginStatus computes the status of a global KongClusterPlugin
+// from the translated state, the translation failures and the error applying
+// the configuration to the data-plane, if any.
+func kongClusterPluginStatus(
+	clusterPlugin *configurationv1.KongClusterPlugin,
+	state *kongstate.KongState,
+	failures []parser.TranslationFailure,
+	applyErr error,
+) configurationv1.KongClusterPluginStatus {
+	status := configurationv1.KongClusterPluginStatus{
+		Conditions: append([]metav1.Condition(nil), clusterPlugin.Status.Conditions...),
+	}
+	condition := metav1.Condition{
+		Type:               configurationv1.KongClusterPluginConditionApplied,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: clusterPlugin.Generation,
+	}
+
+	var messages []string
+	for _, failure := range failures {
+		if obj, ok := failure.Object.(*configurationv1.KongClusterPlugin); ok && obj.Name == clusterPlugin.Name {
+			messages = append(messages, failure.Message)
+		}
+	}
+
+	switch {
+	case len(messages) > 0:
+		condition.Reason = configurationv1.KongClusterPluginReasonInvalid
+		condition.Message = strings.Join(messages, "; ")
+	case !hasGlobalPlugin(state, clusterPlugin.Name):
+		condition.Reason = configurationv1.KongClusterPluginReasonNotAttached
+		condition.Message = "the plugin is not part of the data-plane configuration"
+	case applyErr != nil:
+		var rejectedErr sendconfig.ConfigRejectedError
+		if errors.As(applyErr, &rejectedErr) {
+			for _, entityErr := range rejectedErr.EntityErrors {
+				if entityErr.Type == "plugin" && entityErr.Name == clusterPlugin.PluginName {
+					messages = append(messages, entityErr.Errors...)
+				}
+			}
+		}
+		if len(messages) > 0 {
+			condition.Reason = configurationv1.KongClusterPluginReasonInvalid
+			condition.Message = fmt.Sprintf("the data-plane rejected the configuration of plugin %s: %s",
+				clusterPlugin.PluginName, strings.Join(messages, "; "))
+		} else {
+			condition.Reason = configurationv1.KongClusterPluginReasonConfigurationRejected
+			condition.Message = fmt.Sprintf("the data-plane rejected the configuration: %v", applyErr)
+		}
+	default:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = configurationv1.KongClusterPluginReasonApplied
+		condition.Message = "the plugin is applied globally"
+		status.Global = true
+		status.Services = int32(len(state.Services))
+		for _, service := range state.Services {
+			status.Routes += int32(len(service.Routes))
+		}
+		status.Consumers = int32(len(state.Consumers))
+	}
+
+	meta.SetStatusCondition(&status.Conditions, condition)
+	return status
+}
+
+// hasGlobalPlugin indicates whether the state contains the global plugin
+// translated from the named KongClusterPlugin.
+func hasGlobalPlugin(state *kongstate.KongState, clusterPluginName string) bool {
+	for _, plugin := range state.Plugins {
+		if plugin.K8sName == clusterPluginName && plugin.K8sNamespace == "" &&
+			plugin.Service == nil && plugin.Route == nil && plugin.Consumer == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package dataplane
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/sendconfig"
+	configurationv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+)
+
+func TestKongClusterPluginStatus(t *testing.T) {
+	clusterPlugin := &configurationv1.KongClusterPlugin{
+		ObjectMeta: metav1.ObjectMeta{Name: "rate-limiting", Generation: 3},
+		PluginName: "rate-limiting",
+	}
+	state := &kongstate.KongState{
+		Services: []kongstate.Service{
+			{Routes: []kongstate.Route{{}, {}}},
+			{Routes: []kongstate.Route{{}}},
+		},
+		Consumers: []kongstate.Consumer{{}},
+		Plugins: []kongstate.Plugin{
+			{
+				Plugin:       kong.Plugin{Name: kong.String("rate-limiting"), Route: &kong.Route{ID: kong.String("r")}},
+				K8sNamespace: "default",
+				K8sName:      "rate-limiting",
+			},
+			{
+				Plugin:  kong.Plugin{Name: kong.String("rate-limiting")},
+				K8sName: "rate-limiting",
+			},
+		},
+	}
+	appliedCondition := func(status configurationv1.KongClusterPluginStatus) *metav1.Condition {
+		return meta.FindStatusCondition(status.Conditions, configurationv1.KongClusterPluginConditionApplied)
+	}
+
+	t.Run("applied global plugins report what they're attached to", func(t *testing.T) {
+		status := kongClusterPluginStatus(clusterPlugin, state, nil, nil)
+		assert.True(t, status.Global)
+		assert.Equal(t, int32(2), status.Services)
+		assert.Equal(t, int32(3), status.Routes)
+		assert.Equal(t, int32(1), status.Consumers)
+		condition := appliedCondition(status)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+		assert.Equal(t, configurationv1.KongClusterPluginReasonApplied, condition.Reason)
+		assert.Equal(t, int64(3), condition.ObservedGeneration)
+	})
+
+	t.Run("translation failures are reported", func(t *testing.T) {
+		failures := []parser.TranslationFailure{
+			{Object: &configurationv1.KongClusterPlugin{ObjectMeta: metav1.ObjectMeta{Name: "rate-limiting"}}, Message: "invalid config"},
+			{Object: &configurationv1.KongClusterPlugin{ObjectMeta: metav1.ObjectMeta{Name: "other"}}, Message: "other failure"},
+		}
+		status := kongClusterPluginStatus(clusterPlugin, state, failures, nil)
+		assert.False(t, status.Global)
+		condition := appliedCondition(status)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, configurationv1.KongClusterPluginReasonInvalid, condition.Reason)
+		assert.Equal(t, "invalid config", condition.Message)
+	})
+
+	t.Run("plugins missing from the configuration aren't attached", func(t *testing.T) {
+		status := kongClusterPluginStatus(clusterPlugin, &kongstate.KongState{Plugins: state.Plugins[:1]}, nil, nil)
+		condition := appliedCondition(status)
+		require.NotNil(t, condition)
+		assert.Equal(t, configurationv1.KongClusterPluginReasonNotAttached, condition.Reason)
+	})
+
+	t.Run("schema errors reported by the data-plane are surfaced", func(t *testing.T) {
+		applyErr := sendconfig.ConfigRejectedError{EntityErrors: []sendconfig.EntityError{
+			{Type: "plugin", Name: "rate-limiting", Errors: []string{"config.minute: expected a number"}},
+			{Type: "service", Name: "rate-limiting", Errors: []string{"host: required field missing"}},
+		}}
+		status := kongClusterPluginStatus(clusterPlugin, state, nil, applyErr)
+		condition := appliedCondition(status)
+		require.NotNil(t, condition)
+		assert.Equal(t, configurationv1.KongClusterPluginReasonInvalid, condition.Reason)
+		assert.Equal(t, "the data-plane rejected the configuration of plugin rate-limiting: config.minute: expected a number",
+			condition.Message)
+	})
+
+	t.Run("other errors applying the configuration are reported", func(t *testing.T) {
+		status := kongClusterPluginStatus(clusterPlugin, state, nil, errors.New("connection refused"))
+		condition := appliedCondition(status)
+		require.NotNil(t, condition)
+		assert.Equal(t, configurationv1.KongClusterPluginReasonConfigurationRejected, condition.Reason)
+		assert.Equal(t, "the data-plane rejected the configuration: connection refused", condition.Message)
+	})
+
+	t.Run("the transition time is kept while the condition doesn't change", func(t *testing.T) {
+		previous := kongClusterPluginStatus(clusterPlugin, state, nil, nil)
+		appliedCondition(previous).LastTransitionTime = metav1.Unix(0, 0)
+		withStatus := clusterPlugin.DeepCopy()
+		withStatus.Status = previous
+		status := kongClusterPluginStatus(withStatus, state, nil, nil)
+		assert.Equal(t, previous, status)
+	})
+}
+
+func TestClientKongClusterPluginStatusUpdater(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, configurationv1.AddToScheme(scheme))
+	clusterPlugin := &configurationv1.KongClusterPlugin{
+		ObjectMeta: metav1.ObjectMeta{Name: "rate-limiting"},
+		PluginName: "rate-limiting",
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(clusterPlugin).Build()
+
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(clusterPlugin), clusterPlugin))
+	clusterPlugin.Status = configurationv1.KongClusterPluginStatus{Global: true, Routes: 2}
+	updater := &ClientKongClusterPluginStatusUpdater{Client: k8sClient}
+	require.NoError(t, updater.UpdateKongClusterPluginStatus(ctx, clusterPlugin))
+
+	updated := &configurationv1.KongClusterPlugin{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(clusterPlugin), updated))
+	assert.Equal(t, clusterPlugin.Status, updated.Status)
+}
@@ -0,0 +1,61 @@
+package dataplane
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// -----------------------------------------------------------------------------
+// Dataplane Client - Configuration Exports
+// -----------------------------------------------------------------------------
+
+// ConfigExportKey is the key of the exported configuration in the
+// configuration export ConfigMap.
+const ConfigExportKey = "kong.yaml"
+
+// ConfigExporter exports the configuration applied to the data-plane, in decK
+// YAML format with credentials redacted, so that it can be snapshotted (e.g.
+// by GitOps tooling).
+type ConfigExporter interface {
+	ExportConfig(ctx context.Context, config []byte) error
+}
+
+// ConfigMapConfigExporter exports the configuration to a ConfigMap, which is
+// created if it doesn't exist yet. As ConfigMaps are limited to 1 MiB, very
+// large configurations can't be exported.
+type ConfigMapConfigExporter struct {
+	// Client is used to read and write the ConfigMap. It should not be backed
+	// by a cache, so that ConfigMaps don't need to be watched.
+	Client client.Client
+
+	// ConfigMap is the namespace and name of the ConfigMap.
+	ConfigMap k8stypes.NamespacedName
+}
+
+// ExportConfig writes the configuration to the ConfigMap.
+func (e *ConfigMapConfigExporter) ExportConfig(ctx context.Context, config []byte) error {
+	configMap := &corev1.ConfigMap{}
+	if err := e.Client.Get(ctx, e.ConfigMap, configMap); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		return e.Client.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      e.ConfigMap.Name,
+				Namespace: e.ConfigMap.Namespace,
+			},
+			Data: map[string]string{ConfigExportKey: string(config)},
+		})
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = make(map[string]string, 1)
+	}
+	configMap.Data[ConfigExportKey] = string(config)
+	return e.Client.Update(ctx, configMap)
+}
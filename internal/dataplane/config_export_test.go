@@ -0,0 +1,80 @@
+package dataplane
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestConfigMapConfigExporter(t *testing.T) {
+	ctx := context.Background()
+	nsn := k8stypes.NamespacedName{Namespace: "kong", Name: "kong-config"}
+
+	t.Run("the ConfigMap is created if it doesn't exist", func(t *testing.T) {
+		k8sClient := fake.NewClientBuilder().Build()
+		exporter := &ConfigMapConfigExporter{Client: k8sClient, ConfigMap: nsn}
+		require.NoError(t, exporter.ExportConfig(ctx, []byte("_format_version: \"1.1\"\n")))
+
+		configMap := &corev1.ConfigMap{}
+		require.NoError(t, k8sClient.Get(ctx, nsn, configMap))
+		assert.Equal(t, map[string]string{ConfigExportKey: "_format_version: \"1.1\"\n"}, configMap.Data)
+	})
+
+	t.Run("an existing ConfigMap is updated keeping other keys", func(t *testing.T) {
+		k8sClient := fake.NewClientBuilder().WithObjects(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: nsn.Namespace, Name: nsn.Name},
+			Data: map[string]string{
+				ConfigExportKey: "old",
+				"owner":         "platform-team",
+			},
+		}).Build()
+		exporter := &ConfigMapConfigExporter{Client: k8sClient, ConfigMap: nsn}
+		require.NoError(t, exporter.ExportConfig(ctx, []byte("new")))
+
+		configMap := &corev1.ConfigMap{}
+		require.NoError(t, k8sClient.Get(ctx, nsn, configMap))
+		assert.Equal(t, map[string]string{ConfigExportKey: "new", "owner": "platform-team"}, configMap.Data)
+	})
+}
+
+type fakeConfigExporter struct {
+	exports []string
+}
+
+func (e *fakeConfigExporter) ExportConfig(_ context.Context, config []byte) error {
+	e.exports = append(e.exports, string(config))
+	return nil
+}
+
+func TestKongClientExportConfig(t *testing.T) {
+	ctx := context.Background()
+	exporter := &fakeConfigExporter{}
+	c := &KongClient{logger: logrus.New(), requestTimeout: time.Second}
+	c.SetConfigExporter(exporter, time.Hour)
+
+	c.exportedConfig = []byte("first")
+	c.exportConfig(ctx, []byte{1})
+	assert.Equal(t, []string{"first"}, exporter.exports)
+
+	t.Log("verifying that unchanged configurations aren't exported again")
+	c.lastConfigExport = time.Time{}
+	c.exportConfig(ctx, []byte{1})
+	assert.Len(t, exporter.exports, 1)
+
+	t.Log("verifying that changed configurations are only exported once the interval elapsed")
+	c.lastConfigExport = time.Now()
+	c.exportedConfig = []byte("second")
+	c.exportConfig(ctx, []byte{2})
+	assert.Len(t, exporter.exports, 1)
+	c.lastConfigExport = time.Now().Add(-time.Hour)
+	c.exportConfig(ctx, []byte{2})
+	assert.Equal(t, []string{"first", "second"}, exporter.exports)
+}
@@ -0,0 +1,156 @@
+package dataplane
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/sendconfig"
+)
+
+// -----------------------------------------------------------------------------
+// Dataplane Client - Configuration Snapshots
+// -----------------------------------------------------------------------------
+
+// ConfigSnapshotKey is the key of the gzipped configuration in the
+// configuration snapshot Secret.
+const ConfigSnapshotKey = "config.json.gz"
+
+// maxSecretSize is the maximum total size of the data of a Secret.
+const maxSecretSize = 1 << 20
+
+var (
+	// ErrConfigSnapshotTooLarge is returned when the configuration snapshot
+	// doesn't fit in a Secret, even gzipped.
+	ErrConfigSnapshotTooLarge = errors.New("configuration snapshot too large")
+
+	// ErrNotConfigSnapshotWriter is returned when another instance of the
+	// controller stores the configuration snapshot.
+	ErrNotConfigSnapshotWriter = errors.New("not the configuration snapshot writer")
+)
+
+// ConfigSnapshotStore persists the last configuration successfully applied to
+// a DB-less data-plane, so that it can be pushed to a freshly started Kong
+// before the controller has synced its caches and built a configuration.
+type ConfigSnapshotStore interface {
+	StoreConfigSnapshot(ctx context.Context, config []byte) error
+	// LoadConfigSnapshot provides the stored configuration, nil if there's none.
+	LoadConfigSnapshot(ctx context.Context) ([]byte, error)
+}
+
+// SecretConfigSnapshotStore stores the configuration snapshot gzipped in a
+// Secret, as the configuration includes credentials and TLS keys. The Secret
+// is created if it doesn't exist yet.
+type SecretConfigSnapshotStore struct {
+	// Client is used to read and write the Secret. It should not be backed
+	// by a cache, so that the snapshot can be read before caches are synced.
+	Client client.Client
+
+	// Secret is the namespace and name of the Secret.
+	Secret k8stypes.NamespacedName
+
+	// IsWriter, if set, indicates whether this instance of the controller
+	// stores the snapshot, as the instances sharing the Secret would
+	// otherwise race to update it.
+	IsWriter func() bool
+}
+
+// StoreConfigSnapshot writes the configuration to the Secret. It returns
+// ErrNotConfigSnapshotWriter if another instance of the controller stores the
+// snapshot, and ErrConfigSnapshotTooLarge if the Secret can't hold it.
+func (s *SecretConfigSnapshotStore) StoreConfigSnapshot(ctx context.Context, config []byte) error {
+	if s.IsWriter != nil && !s.IsWriter() {
+		return ErrNotConfigSnapshotWriter
+	}
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(config); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	secret := &corev1.Secret{}
+	if err := s.Client.Get(ctx, s.Secret, secret); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		if buf.Len() > maxSecretSize {
+			return fmt.Errorf("%w: %d bytes gzipped", ErrConfigSnapshotTooLarge, buf.Len())
+		}
+		return s.Client.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      s.Secret.Name,
+				Namespace: s.Secret.Namespace,
+			},
+			Data: map[string][]byte{ConfigSnapshotKey: buf.Bytes()},
+		})
+	}
+
+	size := buf.Len()
+	for key, value := range secret.Data {
+		if key != ConfigSnapshotKey {
+			size += len(value)
+		}
+	}
+	if size > maxSecretSize {
+		return fmt.Errorf("%w: %d bytes gzipped, along with the other keys of the Secret", ErrConfigSnapshotTooLarge, size)
+	}
+
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte, 1)
+	}
+	secret.Data[ConfigSnapshotKey] = buf.Bytes()
+	return s.Client.Update(ctx, secret)
+}
+
+// LoadConfigSnapshot reads the configuration from the Secret.
+func (s *SecretConfigSnapshotStore) LoadConfigSnapshot(ctx context.Context) ([]byte, error) {
+	secret := &corev1.Secret{}
+	if err := s.Client.Get(ctx, s.Secret, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	compressed, ok := secret.Data[ConfigSnapshotKey]
+	if !ok {
+		return nil, nil
+	}
+	r, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration snapshot: %w", err)
+	}
+	defer r.Close()
+	config, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration snapshot: %w", err)
+	}
+	return config, nil
+}
+
+// SeedConfigFromSnapshot pushes the stored configuration snapshot, if any, to
+// a DB-less Kong which has no configuration yet, reporting whether it did.
+// Kong instances already serving a configuration are left alone, so that a
+// restart of the controller alone doesn't roll them back.
+func SeedConfigFromSnapshot(ctx context.Context, kongConfig *sendconfig.Kong, snapshots ConfigSnapshotStore) (bool, error) {
+	config, err := snapshots.LoadConfigSnapshot(ctx)
+	if err != nil {
+		return false, fmt.Errorf("loading the configuration snapshot: %w", err)
+	}
+	if config == nil {
+		return false, nil
+	}
+	return sendconfig.SeedInMemoryConfig(ctx, kongConfig, config)
+}
@@ -0,0 +1,151 @@
+package dataplane
+
+import (
+	"context"
+	"crypto/rand"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/sendconfig"
+)
+
+func TestSecretConfigSnapshotStore(t *testing.T) {
+	ctx := context.Background()
+	nsn := k8stypes.NamespacedName{Namespace: "kong", Name: "config-snapshot"}
+
+	t.Run("no snapshot is loaded when the Secret doesn't exist", func(t *testing.T) {
+		store := &SecretConfigSnapshotStore{Client: fake.NewClientBuilder().Build(), Secret: nsn}
+		config, err := store.LoadConfigSnapshot(ctx)
+		require.NoError(t, err)
+		assert.Nil(t, config)
+	})
+
+	t.Run("the stored snapshot is loaded", func(t *testing.T) {
+		store := &SecretConfigSnapshotStore{Client: fake.NewClientBuilder().Build(), Secret: nsn}
+		require.NoError(t, store.StoreConfigSnapshot(ctx, []byte(`{"_format_version":"1.1"}`)))
+		require.NoError(t, store.StoreConfigSnapshot(ctx, []byte(`{"_format_version":"3.0"}`)))
+		config, err := store.LoadConfigSnapshot(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, `{"_format_version":"3.0"}`, string(config))
+	})
+
+	t.Run("an existing Secret is updated keeping other keys", func(t *testing.T) {
+		k8sClient := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: nsn.Namespace, Name: nsn.Name},
+			Data:       map[string][]byte{"owner": []byte("platform-team")},
+		}).Build()
+		store := &SecretConfigSnapshotStore{Client: k8sClient, Secret: nsn}
+		require.NoError(t, store.StoreConfigSnapshot(ctx, []byte(`{}`)))
+
+		secret := &corev1.Secret{}
+		require.NoError(t, k8sClient.Get(ctx, nsn, secret))
+		assert.Equal(t, []byte("platform-team"), secret.Data["owner"])
+		assert.Contains(t, secret.Data, ConfigSnapshotKey)
+	})
+
+	t.Run("snapshots which don't fit in the Secret are skipped", func(t *testing.T) {
+		k8sClient := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: nsn.Namespace, Name: nsn.Name},
+			Data:       map[string][]byte{"padding": make([]byte, maxSecretSize)},
+		}).Build()
+		store := &SecretConfigSnapshotStore{Client: k8sClient, Secret: nsn}
+		require.ErrorIs(t, store.StoreConfigSnapshot(ctx, []byte(`{}`)), ErrConfigSnapshotTooLarge)
+
+		incompressible := make([]byte, maxSecretSize+1)
+		_, err := rand.Read(incompressible)
+		require.NoError(t, err)
+		store = &SecretConfigSnapshotStore{Client: fake.NewClientBuilder().Build(), Secret: nsn}
+		require.ErrorIs(t, store.StoreConfigSnapshot(ctx, incompressible), ErrConfigSnapshotTooLarge)
+		config, err := store.LoadConfigSnapshot(ctx)
+		require.NoError(t, err)
+		assert.Nil(t, config)
+	})
+
+	t.Run("only the writer stores the snapshot", func(t *testing.T) {
+		isWriter := false
+		store := &SecretConfigSnapshotStore{
+			Client:   fake.NewClientBuilder().Build(),
+			Secret:   nsn,
+			IsWriter: func() bool { return isWriter },
+		}
+		require.ErrorIs(t, store.StoreConfigSnapshot(ctx, []byte(`{}`)), ErrNotConfigSnapshotWriter)
+		config, err := store.LoadConfigSnapshot(ctx)
+		require.NoError(t, err)
+		assert.Nil(t, config)
+
+		isWriter = true
+		require.NoError(t, store.StoreConfigSnapshot(ctx, []byte(`{}`)))
+		config, err = store.LoadConfigSnapshot(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, `{}`, string(config))
+	})
+}
+
+func TestSeedConfigFromSnapshot(t *testing.T) {
+	ctx := context.Background()
+	nsn := k8stypes.NamespacedName{Namespace: "kong", Name: "config-snapshot"}
+
+	for _, tt := range []struct {
+		name       string
+		configHash string
+		snapshot   []byte
+		wantSeeded bool
+	}{
+		{
+			name:       "Kong without configuration is seeded",
+			configHash: "00000000000000000000000000000000",
+			snapshot:   []byte(`{"_format_version":"3.0"}`),
+			wantSeeded: true,
+		},
+		{
+			name:       "configured Kong is left alone",
+			configHash: "0123456789abcdef0123456789abcdef",
+			snapshot:   []byte(`{"_format_version":"3.0"}`),
+		},
+		{
+			name:       "nothing is seeded without a snapshot",
+			configHash: "00000000000000000000000000000000",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var posted []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/status":
+					_, _ = w.Write([]byte(`{"configuration_hash":"` + tt.configHash + `"}`))
+				case "/config":
+					posted, _ = io.ReadAll(r.Body)
+					w.WriteHeader(http.StatusCreated)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+			kongClient, err := kong.NewClient(kong.String(server.URL), server.Client())
+			require.NoError(t, err)
+
+			store := &SecretConfigSnapshotStore{Client: fake.NewClientBuilder().Build(), Secret: nsn}
+			if tt.snapshot != nil {
+				require.NoError(t, store.StoreConfigSnapshot(ctx, tt.snapshot))
+			}
+			seeded, err := SeedConfigFromSnapshot(ctx, &sendconfig.Kong{URL: server.URL, Client: kongClient}, store)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSeeded, seeded)
+			if tt.wantSeeded {
+				assert.Equal(t, tt.snapshot, posted)
+			} else {
+				assert.Nil(t, posted)
+			}
+		})
+	}
+}
@@ -11,10 +11,11 @@ import (
 	"github.com/tidwall/gjson"
 )
 
-// GenerateSHA generates a SHA256 checksum of the (targetContent, customEntities) tuple, with the purpose of change
-// detection.
+// GenerateSHA generates a SHA256 checksum of the (targetContent, customEntities, pluginOrderings) tuple, with the
+// purpose of change detection.
 func GenerateSHA(targetContent *file.Content,
-	customEntities []byte) ([]byte, error) {
+	customEntities []byte,
+	pluginOrderings PluginOrderings) ([]byte, error) {
 
 	var buffer bytes.Buffer
 
@@ -28,6 +29,14 @@ func GenerateSHA(targetContent *file.Content,
 		buffer.Write(customEntities)
 	}
 
+	if len(pluginOrderings) > 0 {
+		jsonOrderings, err := json.Marshal(pluginOrderings)
+		if err != nil {
+			return nil, fmt.Errorf("marshaling plugin orderings to JSON: %w", err)
+		}
+		buffer.Write(jsonOrderings)
+	}
+
 	shaSum := sha256.Sum256(buffer.Bytes())
 	return shaSum[:], nil
 }
@@ -0,0 +1,260 @@
+package deckgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kong/deck/file"
+)
+
+// Entity types reported in a ConfigDiffSummary.
+const (
+	EntityTypeService       = "service"
+	EntityTypeRoute         = "route"
+	EntityTypePlugin        = "plugin"
+	EntityTypeUpstream      = "upstream"
+	EntityTypeTarget        = "target"
+	EntityTypeCertificate   = "certificate"
+	EntityTypeCACertificate = "ca_certificate"
+	EntityTypeConsumer      = "consumer"
+	EntityTypeCredential    = "credential"
+)
+
+// EntityChanges counts the entities of a single type which were created,
+// updated or deleted between two configurations.
+type EntityChanges struct {
+	Created int
+	Updated int
+	Deleted int
+}
+
+// ConfigDiffSummary maps entity types to the changes found for them between
+// two configurations. Entity types without any changes are omitted.
+type ConfigDiffSummary map[string]EntityChanges
+
+// IsEmpty indicates whether the summary contains no changes at all.
+func (s ConfigDiffSummary) IsEmpty() bool {
+	return len(s) == 0
+}
+
+// String provides a stable, human readable representation of the summary
+// which is suitable for logging, e.g. "route: +1 ~0 -2, service: +0 ~1 -0".
+func (s ConfigDiffSummary) String() string {
+	if s.IsEmpty() {
+		return "no changes"
+	}
+	entityTypes := make([]string, 0, len(s))
+	for entityType := range s {
+		entityTypes = append(entityTypes, entityType)
+	}
+	sort.Strings(entityTypes)
+
+	parts := make([]string, 0, len(entityTypes))
+	for _, entityType := range entityTypes {
+		changes := s[entityType]
+		parts = append(parts, fmt.Sprintf("%s: +%d ~%d -%d",
+			entityType, changes.Created, changes.Updated, changes.Deleted))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// SummarizeConfigDiff compares the previously applied configuration with the
+// target configuration and counts, per entity type, how many entities will be
+// created, updated and deleted. A nil previous configuration is treated as an
+// empty one, so every entity of the target is reported as created.
+func SummarizeConfigDiff(previous, target *file.Content) ConfigDiffSummary {
+	summary := ConfigDiffSummary{}
+	for _, diff := range DiffConfigs(previous, target) {
+		changes := summary[diff.Type]
+		switch diff.Operation {
+		case OperationCreate:
+			changes.Created++
+		case OperationUpdate:
+			changes.Updated++
+		case OperationDelete:
+			changes.Deleted++
+		}
+		summary[diff.Type] = changes
+	}
+	return summary
+}
+
+// Operations reported in an EntityDiff.
+const (
+	OperationCreate = "create"
+	OperationUpdate = "update"
+	OperationDelete = "delete"
+)
+
+// EntityDiff describes an entity which differs between two configurations.
+type EntityDiff struct {
+	// Type is the type of the entity, e.g. route.
+	Type string `json:"type"`
+	// ID identifies the entity among the entities of its type.
+	ID string `json:"id"`
+	// Operation is how the entity changes from the previous configuration to
+	// the target one.
+	Operation string `json:"operation"`
+	// Previous is the entity in the previous configuration, without its
+	// nested entities.
+	Previous json.RawMessage `json:"previous,omitempty"`
+	// Target is the entity in the target configuration, without its nested
+	// entities.
+	Target json.RawMessage `json:"target,omitempty"`
+}
+
+// DiffConfigs lists the entities which differ between the previous and the
+// target configurations, sorted by type and identity. A nil configuration is
+// treated as an empty one.
+func DiffConfigs(previous, target *file.Content) []EntityDiff {
+	previousEntities := flattenContent(previous)
+	targetEntities := flattenContent(target)
+
+	var diffs []EntityDiff
+	for key, targetValue := range targetEntities {
+		previousValue, ok := previousEntities[key]
+		switch {
+		case !ok:
+			diffs = append(diffs, newEntityDiff(key, OperationCreate, "", targetValue))
+		case previousValue != targetValue:
+			diffs = append(diffs, newEntityDiff(key, OperationUpdate, previousValue, targetValue))
+		}
+	}
+	for key, previousValue := range previousEntities {
+		if _, ok := targetEntities[key]; !ok {
+			diffs = append(diffs, newEntityDiff(key, OperationDelete, previousValue, ""))
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		if diffs[i].Type != diffs[j].Type {
+			return diffs[i].Type < diffs[j].Type
+		}
+		return diffs[i].ID < diffs[j].ID
+	})
+	return diffs
+}
+
+func newEntityDiff(key entityKey, operation, previous, target string) EntityDiff {
+	return EntityDiff{
+		Type:      key.entityType,
+		ID:        key.id,
+		Operation: operation,
+		Previous:  rawEntity(previous),
+		Target:    rawEntity(target),
+	}
+}
+
+// rawEntity provides a serialized entity as raw JSON, which is omitted when
+// the entity is missing or fully described by its identity (credentials).
+func rawEntity(entity string) json.RawMessage {
+	if entity == "" || entity == "null" {
+		return nil
+	}
+	return json.RawMessage(entity)
+}
+
+// entityKey uniquely identifies an entity in a configuration.
+type entityKey struct {
+	entityType string
+	id         string
+}
+
+// flattenContent indexes every entity in the configuration (including nested
+// ones like routes and targets) by its identity, storing a serialized form of
+// the entity itself (without its nested entities) for change detection.
+func flattenContent(content *file.Content) map[entityKey]string {
+	entities := map[entityKey]string{}
+	if content == nil {
+		return entities
+	}
+	add := func(entityType, id string, entity interface{}) {
+		entities[entityKey{entityType: entityType, id: id}] = serializeEntity(entity)
+	}
+
+	for _, s := range content.Services {
+		serviceName := stringOrEmpty(s.Name)
+		add(EntityTypeService, serviceName, s.Service)
+		for _, p := range s.Plugins {
+			add(EntityTypePlugin, "service:"+serviceName+"/"+stringOrEmpty(p.Name), p.Plugin)
+		}
+		for _, r := range s.Routes {
+			routeName := stringOrEmpty(r.Name)
+			add(EntityTypeRoute, routeName, r.Route)
+			for _, p := range r.Plugins {
+				add(EntityTypePlugin, "route:"+routeName+"/"+stringOrEmpty(p.Name), p.Plugin)
+			}
+		}
+	}
+	for _, r := range content.Routes {
+		add(EntityTypeRoute, stringOrEmpty(r.Name), r.Route)
+	}
+	for _, p := range content.Plugins {
+		add(EntityTypePlugin, "global:"+PluginString(p), p.Plugin)
+	}
+	for _, u := range content.Upstreams {
+		upstreamName := stringOrEmpty(u.Name)
+		add(EntityTypeUpstream, upstreamName, u.Upstream)
+		for _, t := range u.Targets {
+			add(EntityTypeTarget, upstreamName+"/"+stringOrEmpty(t.Target.Target), t.Target)
+		}
+	}
+	for _, c := range content.Certificates {
+		id := stringOrEmpty(c.ID)
+		if id == "" {
+			id = stringOrEmpty(c.Cert)
+		}
+		add(EntityTypeCertificate, id, c)
+	}
+	for _, c := range content.CACertificates {
+		add(EntityTypeCACertificate, stringOrEmpty(c.Cert), c.CACertificate)
+	}
+	for _, c := range content.Consumers {
+		username := stringOrEmpty(c.Username)
+		add(EntityTypeConsumer, username, c.Consumer)
+		for _, p := range c.Plugins {
+			add(EntityTypePlugin, "consumer:"+username+"/"+stringOrEmpty(p.Name), p.Plugin)
+		}
+		// credentials don't have a stable name, so they're identified by their
+		// contents: a modified credential is reported as deleted and created.
+		for _, credentials := range []interface{}{
+			c.KeyAuths, c.HMACAuths, c.JWTAuths, c.BasicAuths, c.Oauth2Creds, c.ACLGroups, c.MTLSAuths,
+		} {
+			for _, credential := range flattenCredentials(credentials) {
+				add(EntityTypeCredential, username+"/"+credential, nil)
+			}
+		}
+	}
+	return entities
+}
+
+// flattenCredentials serializes each credential in a list of credentials of
+// any type.
+func flattenCredentials(credentials interface{}) []string {
+	var raw []json.RawMessage
+	if err := json.Unmarshal([]byte(serializeEntity(credentials)), &raw); err != nil {
+		return nil
+	}
+	result := make([]string, 0, len(raw))
+	for _, credential := range raw {
+		result = append(result, string(credential))
+	}
+	return result
+}
+
+func serializeEntity(entity interface{}) string {
+	b, err := json.Marshal(entity)
+	if err != nil {
+		// entities are plain data structures which always serialize
+		return ""
+	}
+	return string(b)
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,143 @@
+package deckgen
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/kong/deck/file"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarizeConfigDiff(t *testing.T) {
+	previous := &file.Content{
+		Services: []file.FService{
+			{
+				Service: kong.Service{Name: kong.String("svc-a"), Host: kong.String("a.default.80.svc")},
+				Routes: []*file.FRoute{
+					{Route: kong.Route{Name: kong.String("route-a"), Paths: kong.StringSlice("/a")}},
+					{Route: kong.Route{Name: kong.String("route-b"), Paths: kong.StringSlice("/b")}},
+				},
+			},
+		},
+		Upstreams: []file.FUpstream{
+			{
+				Upstream: kong.Upstream{Name: kong.String("a.default.80.svc")},
+				Targets: []*file.FTarget{
+					{Target: kong.Target{Target: kong.String("10.0.0.1:80")}},
+				},
+			},
+		},
+		Consumers: []file.FConsumer{
+			{
+				Consumer: kong.Consumer{Username: kong.String("alice")},
+				KeyAuths: []*kong.KeyAuth{{Key: kong.String("secret")}},
+			},
+		},
+	}
+
+	target := &file.Content{
+		Services: []file.FService{
+			{
+				Service: kong.Service{Name: kong.String("svc-a"), Host: kong.String("a.default.80.svc")},
+				Routes: []*file.FRoute{
+					{Route: kong.Route{Name: kong.String("route-a"), Paths: kong.StringSlice("/a", "/aa")}},
+				},
+				Plugins: []*file.FPlugin{
+					{Plugin: kong.Plugin{Name: kong.String("cors")}},
+				},
+			},
+		},
+		Upstreams: []file.FUpstream{
+			{
+				Upstream: kong.Upstream{Name: kong.String("a.default.80.svc")},
+				Targets: []*file.FTarget{
+					{Target: kong.Target{Target: kong.String("10.0.0.1:80")}},
+					{Target: kong.Target{Target: kong.String("10.0.0.2:80")}},
+				},
+			},
+		},
+		Consumers: []file.FConsumer{
+			{
+				Consumer: kong.Consumer{Username: kong.String("alice")},
+				KeyAuths: []*kong.KeyAuth{{Key: kong.String("new-secret")}},
+			},
+		},
+	}
+
+	t.Log("verifying that all entities are reported as created when there's no previous configuration")
+	assert.Equal(t, ConfigDiffSummary{
+		EntityTypeService:    {Created: 1},
+		EntityTypeRoute:      {Created: 2},
+		EntityTypeUpstream:   {Created: 1},
+		EntityTypeTarget:     {Created: 1},
+		EntityTypeConsumer:   {Created: 1},
+		EntityTypeCredential: {Created: 1},
+	}, SummarizeConfigDiff(nil, previous))
+
+	t.Log("verifying that changes between two configurations are counted per entity type")
+	summary := SummarizeConfigDiff(previous, target)
+	assert.Equal(t, ConfigDiffSummary{
+		EntityTypeRoute:      {Updated: 1, Deleted: 1},
+		EntityTypePlugin:     {Created: 1},
+		EntityTypeTarget:     {Created: 1},
+		EntityTypeCredential: {Created: 1, Deleted: 1},
+	}, summary)
+	assert.Equal(t, "credential: +1 ~0 -1, plugin: +1 ~0 -0, route: +0 ~1 -1, target: +1 ~0 -0", summary.String())
+
+	t.Log("verifying that identical configurations produce an empty summary")
+	summary = SummarizeConfigDiff(target, target)
+	assert.True(t, summary.IsEmpty())
+	assert.Equal(t, "no changes", summary.String())
+}
+
+func TestDiffConfigs(t *testing.T) {
+	previous := &file.Content{
+		Services: []file.FService{
+			{
+				Service: kong.Service{Name: kong.String("svc-a")},
+				Routes: []*file.FRoute{
+					{Route: kong.Route{Name: kong.String("route-a"), Paths: kong.StringSlice("/a")}},
+					{Route: kong.Route{Name: kong.String("route-b"), Paths: kong.StringSlice("/b")}},
+				},
+			},
+		},
+	}
+	target := &file.Content{
+		Services: []file.FService{
+			{
+				Service: kong.Service{Name: kong.String("svc-a")},
+				Routes: []*file.FRoute{
+					{Route: kong.Route{Name: kong.String("route-a"), Paths: kong.StringSlice("/aa")}},
+				},
+				Plugins: []*file.FPlugin{
+					{Plugin: kong.Plugin{Name: kong.String("cors")}},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, []EntityDiff{
+		{
+			Type:      EntityTypePlugin,
+			ID:        "service:svc-a/cors",
+			Operation: OperationCreate,
+			Target:    json.RawMessage(`{"name":"cors"}`),
+		},
+		{
+			Type:      EntityTypeRoute,
+			ID:        "route-a",
+			Operation: OperationUpdate,
+			Previous:  json.RawMessage(`{"name":"route-a","paths":["/a"]}`),
+			Target:    json.RawMessage(`{"name":"route-a","paths":["/aa"]}`),
+		},
+		{
+			Type:      EntityTypeRoute,
+			ID:        "route-b",
+			Operation: OperationDelete,
+			Previous:  json.RawMessage(`{"name":"route-b","paths":["/b"]}`),
+		},
+	}, DiffConfigs(previous, target))
+
+	assert.Empty(t, DiffConfigs(target, target))
+}
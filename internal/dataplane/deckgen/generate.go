@@ -2,6 +2,7 @@ package deckgen
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
@@ -15,6 +16,8 @@ import (
 )
 
 // ToDeckContent generates a decK configuration from `k8sState` and auxiliary parameters.
+// The configurations of plugins are filled with the defaults of their schemas,
+// unless `schemas` is nil.
 func ToDeckContent(
 	ctx context.Context,
 	log logrus.FieldLogger,
@@ -28,6 +31,7 @@ func ToDeckContent(
 
 	for _, s := range k8sState.Services {
 		service := file.FService{Service: s.Service}
+		service.Tags = withTags(s.Service.Tags, s.SourceTags())
 		for _, p := range s.Plugins {
 			plugin := file.FPlugin{
 				Plugin: *p.DeepCopy(),
@@ -44,6 +48,7 @@ func ToDeckContent(
 
 		for _, r := range s.Routes {
 			route := file.FRoute{Route: r.Route}
+			route.Tags = withTags(r.Route.Tags, r.SourceTags())
 			fillRoute(&route.Route)
 
 			for _, p := range r.Plugins {
@@ -88,6 +93,7 @@ func ToDeckContent(
 	for _, u := range k8sState.Upstreams {
 		fillUpstream(&u.Upstream)
 		upstream := file.FUpstream{Upstream: u.Upstream}
+		upstream.Tags = withTags(u.Upstream.Tags, u.Service.SourceTags())
 		for _, t := range u.Targets {
 			target := file.FTarget{Target: t.Target}
 			upstream.Targets = append(upstream.Targets, &target)
@@ -119,6 +125,7 @@ func ToDeckContent(
 
 	for _, c := range k8sState.Consumers {
 		consumer := file.FConsumer{Consumer: c.Consumer}
+		consumer.Tags = withTags(c.Consumer.Tags, c.SourceTags())
 
 		// if a consumer with no username is provided deck wont be able to process it, but we shouldn't
 		// fail the rest of the deckgen either or this will result in one bad consumer being capable of
@@ -167,6 +174,33 @@ func ToDeckContent(
 	return &content
 }
 
+// ToCustomEntities generates the entities of `k8sState` which decK doesn't
+// support, to be sent along with the decK configuration to a DB-less Kong. It
+// returns nil if there are no such entities.
+func ToCustomEntities(k8sState *kongstate.KongState) ([]byte, error) {
+	if len(k8sState.Vaults) == 0 {
+		return nil, nil
+	}
+	customEntities, err := json.Marshal(map[string]interface{}{
+		"vaults": k8sState.Vaults,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshaling vaults to JSON: %w", err)
+	}
+	return customEntities, nil
+}
+
+// withTags provides the tags of an entity along with more tags, without
+// modifying the tags of the entity.
+func withTags(tags, more []*string) []*string {
+	if len(more) == 0 {
+		return tags
+	}
+	merged := make([]*string, 0, len(tags)+len(more))
+	merged = append(merged, tags...)
+	return append(merged, more...)
+}
+
 func fillRoute(route *kong.Route) {
 	if route.HTTPSRedirectStatusCode == nil {
 		route.HTTPSRedirectStatusCode = kong.Int(426)
@@ -189,18 +223,22 @@ func fillPlugin(ctx context.Context, plugin *file.FPlugin, schemas *util.PluginS
 	if plugin.Name == nil || *plugin.Name == "" {
 		return fmt.Errorf("plugin doesn't have a name")
 	}
-	schema, err := schemas.Schema(ctx, *plugin.Name)
-	if err != nil {
-		return fmt.Errorf("error retrieveing schema for plugin %s: %w", *plugin.Name, err)
-	}
 	if plugin.Config == nil {
 		plugin.Config = make(kong.Configuration)
 	}
-	newConfig, err := FillPluginConfig(schema, plugin.Config)
-	if err != nil {
-		return fmt.Errorf("error filling in default for plugin %s: %w", *plugin.Name, err)
+	// without schemas (e.g. when translating offline), the configuration is
+	// left as is
+	if schemas != nil {
+		schema, err := schemas.Schema(ctx, *plugin.Name)
+		if err != nil {
+			return fmt.Errorf("error retrieveing schema for plugin %s: %w", *plugin.Name, err)
+		}
+		newConfig, err := FillPluginConfig(schema, plugin.Config)
+		if err != nil {
+			return fmt.Errorf("error filling in default for plugin %s: %w", *plugin.Name, err)
+		}
+		plugin.Config = newConfig
 	}
-	plugin.Config = newConfig
 	if plugin.RunOn == nil {
 		plugin.RunOn = kong.String("first")
 	}
@@ -0,0 +1,147 @@
+package deckgen
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+	configurationv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+)
+
+func TestToCustomEntities(t *testing.T) {
+	customEntities, err := ToCustomEntities(&kongstate.KongState{})
+	require.NoError(t, err)
+	assert.Nil(t, customEntities, "states without vaults have no custom entities")
+
+	customEntities, err = ToCustomEntities(&kongstate.KongState{
+		Vaults: []kongstate.Vault{
+			{Name: "aws", Prefix: "aws-eu", Config: map[string]interface{}{"region": "eu-west-1"}},
+			{Name: "env", Prefix: "secrets", Description: "environment"},
+		},
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"vaults":[
+		{"name":"aws","prefix":"aws-eu","config":{"region":"eu-west-1"}},
+		{"name":"env","prefix":"secrets","description":"environment"}
+	]}`, string(customEntities))
+}
+
+func TestToDeckContentIsDeterministic(t *testing.T) {
+	newState := func() *kongstate.KongState {
+		return &kongstate.KongState{
+			Services: []kongstate.Service{
+				{
+					Service: kong.Service{Name: kong.String("default.foo.80"), Host: kong.String("foo.default.80.svc")},
+					Routes: []kongstate.Route{
+						{Route: kong.Route{Name: kong.String("default.foo.00"), Paths: kong.StringSlice("/foo")}},
+						{Route: kong.Route{Name: kong.String("default.foo.01"), Paths: kong.StringSlice("/bar")}},
+					},
+				},
+				{
+					Service: kong.Service{Name: kong.String("default.bar.80"), Host: kong.String("bar.default.80.svc")},
+					Routes: []kongstate.Route{
+						{Route: kong.Route{Name: kong.String("default.bar.00"), Paths: kong.StringSlice("/baz")}},
+					},
+				},
+			},
+			Upstreams: []kongstate.Upstream{
+				{
+					Upstream: kong.Upstream{Name: kong.String("foo.default.80.svc")},
+					Targets: []kongstate.Target{
+						{Target: kong.Target{Target: kong.String("10.0.0.1:80")}},
+						{Target: kong.Target{Target: kong.String("10.0.0.2:80")}},
+					},
+				},
+				{Upstream: kong.Upstream{Name: kong.String("bar.default.80.svc")}},
+			},
+			Certificates: []kongstate.Certificate{
+				{Certificate: kong.Certificate{Cert: kong.String("cert-1"), Key: kong.String("key-1")}},
+				{Certificate: kong.Certificate{Cert: kong.String("cert-2"), Key: kong.String("key-2")}},
+			},
+		}
+	}
+	sha := func(state *kongstate.KongState) []byte {
+		content := ToDeckContent(context.Background(), logrus.New(), state, nil, nil)
+		sha, err := GenerateSHA(content, nil, nil)
+		require.NoError(t, err)
+		return sha
+	}
+
+	state := newState()
+	reordered := newState()
+	reverse := func(n int, swap func(i, j int)) {
+		for i, j := 0, n-1; i < j; i, j = i+1, j-1 {
+			swap(i, j)
+		}
+	}
+	reverse(len(reordered.Services), func(i, j int) {
+		reordered.Services[i], reordered.Services[j] = reordered.Services[j], reordered.Services[i]
+	})
+	for _, service := range reordered.Services {
+		reverse(len(service.Routes), func(i, j int) { service.Routes[i], service.Routes[j] = service.Routes[j], service.Routes[i] })
+	}
+	reverse(len(reordered.Upstreams), func(i, j int) {
+		reordered.Upstreams[i], reordered.Upstreams[j] = reordered.Upstreams[j], reordered.Upstreams[i]
+	})
+	for _, upstream := range reordered.Upstreams {
+		reverse(len(upstream.Targets), func(i, j int) { upstream.Targets[i], upstream.Targets[j] = upstream.Targets[j], upstream.Targets[i] })
+	}
+	reverse(len(reordered.Certificates), func(i, j int) {
+		reordered.Certificates[i], reordered.Certificates[j] = reordered.Certificates[j], reordered.Certificates[i]
+	})
+	assert.Equal(t, sha(state), sha(reordered), "the order of the entities of the state doesn't matter")
+
+	changed := newState()
+	changed.Services[0].Routes[0].Paths = kong.StringSlice("/qux")
+	assert.NotEqual(t, sha(state), sha(changed), "changing an entity changes the checksum")
+}
+
+func TestToDeckContentSourceTags(t *testing.T) {
+	k8sService := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "echo", UID: "service-uid"}}
+	service := kongstate.Service{
+		Service:     kong.Service{Name: kong.String("default.echo.80"), Tags: kong.StringSlice("custom")},
+		K8sServices: map[string]*corev1.Service{"default/echo": k8sService},
+		Routes: []kongstate.Route{{
+			Route: kong.Route{Name: kong.String("default.echo.00")},
+			Ingress: util.K8sObjectInfo{
+				Namespace:        "default",
+				Name:             "echo",
+				UID:              "ingress-uid",
+				GroupVersionKind: networkingv1.SchemeGroupVersion.WithKind("Ingress"),
+			},
+		}},
+	}
+	state := &kongstate.KongState{
+		Services:  []kongstate.Service{service},
+		Upstreams: []kongstate.Upstream{{Upstream: kong.Upstream{Name: kong.String("echo.default.80.svc")}, Service: service}},
+		Consumers: []kongstate.Consumer{{
+			Consumer:        kong.Consumer{Username: kong.String("alice")},
+			K8sKongConsumer: configurationv1.KongConsumer{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "alice"}},
+		}},
+	}
+	serviceTags := kong.StringSlice("k8s-name:echo", "k8s-namespace:default", "k8s-kind:Service", "k8s-version:v1", "k8s-uid:service-uid")
+
+	content := ToDeckContent(context.Background(), logrus.New(), state, nil, nil)
+	require.Len(t, content.Services, 1)
+	assert.Equal(t, append(kong.StringSlice("custom"), serviceTags...), content.Services[0].Tags)
+	assert.Equal(t, kong.StringSlice("custom"), state.Services[0].Tags, "the state isn't modified")
+	require.Len(t, content.Services[0].Routes, 1)
+	assert.Equal(t, kong.StringSlice(
+		"k8s-name:echo", "k8s-namespace:default", "k8s-kind:Ingress", "k8s-group:networking.k8s.io", "k8s-version:v1", "k8s-uid:ingress-uid",
+	), content.Services[0].Routes[0].Tags)
+	require.Len(t, content.Upstreams, 1)
+	assert.Equal(t, serviceTags, content.Upstreams[0].Tags)
+	require.Len(t, content.Consumers, 1)
+	assert.Equal(t, kong.StringSlice(
+		"k8s-name:alice", "k8s-namespace:default", "k8s-kind:KongConsumer", "k8s-group:configuration.konghq.com", "k8s-version:v1",
+	), content.Consumers[0].Tags)
+}
@@ -0,0 +1,67 @@
+package deckgen
+
+import (
+	"strings"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+	configurationv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+)
+
+// PluginOrderings are the orderings of the plugins of a configuration, which
+// decK doesn't support, by plugin. Plugins are identified by their name and
+// the entities they are attached to, as they can only be attached once to a
+// combination of entities.
+type PluginOrderings map[string]configurationv1.PluginOrdering
+
+// ToPluginOrderings generates the orderings of the plugins of `k8sState`, to
+// be applied to the configuration sent to a DB-less Kong. It returns nil if no
+// plugin has an ordering.
+func ToPluginOrderings(k8sState *kongstate.KongState) PluginOrderings {
+	var orderings PluginOrderings
+	for _, plugin := range k8sState.Plugins {
+		if plugin.Ordering == nil || plugin.Name == nil {
+			continue
+		}
+		var consumer, route, service string
+		if plugin.Consumer != nil && plugin.Consumer.ID != nil {
+			consumer = *plugin.Consumer.ID
+		}
+		if plugin.Route != nil && plugin.Route.ID != nil {
+			route = *plugin.Route.ID
+		}
+		if plugin.Service != nil && plugin.Service.ID != nil {
+			service = *plugin.Service.ID
+		}
+		if orderings == nil {
+			orderings = make(PluginOrderings)
+		}
+		orderings[pluginOrderingKey(*plugin.Name, consumer, route, service)] = *plugin.Ordering
+	}
+	return orderings
+}
+
+// Apply sets the ordering of the plugins of a declarative configuration,
+// rendered as a generic JSON object.
+func (o PluginOrderings) Apply(config map[string]interface{}) {
+	plugins, ok := config["plugins"].([]interface{})
+	if !ok {
+		return
+	}
+	for _, p := range plugins {
+		plugin, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := plugin["name"].(string)
+		consumer, _ := plugin["consumer"].(string)
+		route, _ := plugin["route"].(string)
+		service, _ := plugin["service"].(string)
+		if ordering, ok := o[pluginOrderingKey(name, consumer, route, service)]; ok {
+			plugin["ordering"] = ordering
+		}
+	}
+}
+
+func pluginOrderingKey(name, consumer, route, service string) string {
+	return strings.Join([]string{name, consumer, route, service}, "|")
+}
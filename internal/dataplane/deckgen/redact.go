@@ -0,0 +1,126 @@
+package deckgen
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/kong/deck/file"
+	"github.com/kong/go-kong/kong"
+	"github.com/tidwall/gjson"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+)
+
+// RedactedValue replaces sensitive values in redacted configurations.
+const RedactedValue = "REDACTED"
+
+// RedactPluginConfigs redacts the sensitive fields of every plugin configuration
+// in `content` in place. Fields are considered sensitive if the plugin schema
+// marks them as encrypted or referenceable. If the schema of a plugin can't be
+// retrieved, its whole configuration is redacted, as the sensitive fields can't
+// be told apart from the rest.
+func RedactPluginConfigs(ctx context.Context, content *file.Content, schemas *util.PluginSchemaStore) {
+	redact := func(plugin *file.FPlugin) {
+		if plugin.Config == nil {
+			return
+		}
+		if plugin.Name != nil && *plugin.Name != "" {
+			if schema, err := schemas.Schema(ctx, *plugin.Name); err == nil {
+				if config, err := RedactPluginConfig(schema, plugin.Config); err == nil {
+					plugin.Config = config
+					return
+				}
+			}
+		}
+		plugin.Config = redactAll(plugin.Config)
+	}
+
+	for _, s := range content.Services {
+		for _, p := range s.Plugins {
+			redact(p)
+		}
+		for _, r := range s.Routes {
+			for _, p := range r.Plugins {
+				redact(p)
+			}
+		}
+	}
+	for _, r := range content.Routes {
+		for _, p := range r.Plugins {
+			redact(p)
+		}
+	}
+	for i := range content.Plugins {
+		redact(&content.Plugins[i])
+	}
+	for _, c := range content.Consumers {
+		for _, p := range c.Plugins {
+			redact(p)
+		}
+	}
+}
+
+// RedactPluginConfig returns a copy of `config` with the values of fields that
+// `schema` marks as encrypted or referenceable replaced by RedactedValue.
+func RedactPluginConfig(schema map[string]interface{},
+	config kong.Configuration) (kong.Configuration, error) {
+	jsonb, err := json.Marshal(&schema)
+	if err != nil {
+		return nil, err
+	}
+	value := gjson.ParseBytes(jsonb)
+	return redactRecord(value, config), nil
+}
+
+func redactRecord(schema gjson.Result, config kong.Configuration) kong.Configuration {
+	if config == nil {
+		return nil
+	}
+	res := config.DeepCopy()
+	schema.Get("fields").ForEach(func(_, value gjson.Result) bool {
+		value.ForEach(func(key, field gjson.Result) bool {
+			fname := key.String()
+			fvalue, ok := res[fname]
+			if !ok || fvalue == nil {
+				return true
+			}
+			if field.Get("encrypted").Bool() || field.Get("referenceable").Bool() {
+				res[fname] = redactValue(fvalue)
+				return true
+			}
+			if field.Get("type").String() == "record" {
+				if subConfig, ok := fvalue.(map[string]interface{}); ok {
+					res[fname] = map[string]interface{}(redactRecord(field, subConfig))
+				}
+			}
+			return true
+		})
+		return true
+	})
+	return res
+}
+
+// redactValue redacts a single value, keeping the shape of arrays so that
+// the number of configured entries is still visible.
+func redactValue(value interface{}) interface{} {
+	if values, ok := value.([]interface{}); ok {
+		res := make([]interface{}, 0, len(values))
+		for range values {
+			res = append(res, RedactedValue)
+		}
+		return res
+	}
+	return RedactedValue
+}
+
+func redactAll(config kong.Configuration) kong.Configuration {
+	res := make(kong.Configuration, len(config))
+	for k, v := range config {
+		if v == nil {
+			res[k] = nil
+			continue
+		}
+		res[k] = redactValue(v)
+	}
+	return res
+}
@@ -0,0 +1,122 @@
+package deckgen
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kong/deck/file"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+)
+
+// sensitivePluginSchema is a plugin configuration schema in the format returned
+// by the /plugins/schema/{name} endpoint.
+const sensitivePluginSchema = `{
+	"fields": [
+		{ "host": { "type": "string" } },
+		{ "password": { "type": "string", "referenceable": true } },
+		{ "client_secret": { "type": "string", "encrypted": true } },
+		{ "api_keys": { "type": "array", "encrypted": true } },
+		{
+			"auth": {
+				"type": "record",
+				"fields": [
+					{ "user": { "type": "string" } },
+					{ "token": { "type": "string", "referenceable": true } }
+				]
+			}
+		}
+	]
+}`
+
+func TestRedactPluginConfig(t *testing.T) {
+	var schema map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(sensitivePluginSchema), &schema))
+
+	config := kong.Configuration{
+		"host":          "redis.example.com",
+		"password":      "hunter2",
+		"client_secret": "s3cr3t",
+		"api_keys":      []interface{}{"key-1", "key-2"},
+		"auth": map[string]interface{}{
+			"user":  "admin",
+			"token": "t0k3n",
+		},
+	}
+
+	res, err := RedactPluginConfig(schema, config)
+	require.NoError(t, err)
+	assert.Equal(t, kong.Configuration{
+		"host":          "redis.example.com",
+		"password":      RedactedValue,
+		"client_secret": RedactedValue,
+		"api_keys":      []interface{}{RedactedValue, RedactedValue},
+		"auth": map[string]interface{}{
+			"user":  "admin",
+			"token": RedactedValue,
+		},
+	}, res)
+
+	t.Log("verifying that the original configuration is left untouched")
+	assert.Equal(t, "hunter2", config["password"])
+	assert.Equal(t, "t0k3n", config["auth"].(map[string]interface{})["token"])
+}
+
+func TestRedactPluginConfigs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/plugins/schema/sensitive" {
+			_, _ = w.Write([]byte(sensitivePluginSchema))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client, err := kong.NewClient(kong.String(server.URL), server.Client())
+	require.NoError(t, err)
+	schemas := util.NewPluginSchemaStore(client)
+
+	content := &file.Content{
+		Services: []file.FService{{
+			Plugins: []*file.FPlugin{{Plugin: kong.Plugin{
+				Name:   kong.String("sensitive"),
+				Config: kong.Configuration{"host": "redis", "password": "hunter2"},
+			}}},
+			Routes: []*file.FRoute{{
+				Plugins: []*file.FPlugin{{Plugin: kong.Plugin{
+					Name:   kong.String("sensitive"),
+					Config: kong.Configuration{"client_secret": "s3cr3t"},
+				}}},
+			}},
+		}},
+		Plugins: []file.FPlugin{{Plugin: kong.Plugin{
+			Name:   kong.String("unknown"),
+			Config: kong.Configuration{"header": "x-secret", "unset": nil},
+		}}},
+		Consumers: []file.FConsumer{{
+			Plugins: []*file.FPlugin{{Plugin: kong.Plugin{
+				Name:   kong.String("sensitive"),
+				Config: kong.Configuration{"auth": map[string]interface{}{"token": "t0k3n"}},
+			}}},
+		}},
+	}
+
+	RedactPluginConfigs(context.Background(), content, schemas)
+
+	assert.Equal(t, kong.Configuration{"host": "redis", "password": RedactedValue},
+		content.Services[0].Plugins[0].Config)
+	assert.Equal(t, kong.Configuration{"client_secret": RedactedValue},
+		content.Services[0].Routes[0].Plugins[0].Config)
+	assert.Equal(t, kong.Configuration{"auth": map[string]interface{}{"token": RedactedValue}},
+		content.Consumers[0].Plugins[0].Config)
+
+	t.Log("verifying that the configuration of plugins without a known schema is redacted entirely")
+	assert.Equal(t, kong.Configuration{"header": RedactedValue, "unset": nil},
+		content.Plugins[0].Config)
+}
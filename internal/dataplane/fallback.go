@@ -0,0 +1,236 @@
+package dataplane
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/sendconfig"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+	configurationv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+)
+
+// -----------------------------------------------------------------------------
+// Dataplane Client - Kong - Fallback Configuration
+// -----------------------------------------------------------------------------
+
+// brokenObject is a Kubernetes object whose translated configuration was
+// rejected by the data-plane, or which couldn't be translated.
+type brokenObject struct {
+	util.K8sObjectInfo
+
+	// errors are the errors the data-plane reported for the entities
+	// generated from the object, or the problems its translation ran into.
+	errors []string
+}
+
+// brokenObjects is a set of broken Kubernetes objects indexed by their key.
+type brokenObjects map[string]*brokenObject
+
+func (b brokenObjects) add(info util.K8sObjectInfo, entityErr sendconfig.EntityError) {
+	errs := make([]string, 0, len(entityErr.Errors))
+	for _, e := range entityErr.Errors {
+		errs = append(errs, fmt.Sprintf("%s %s: %s", entityErr.Type, entityErr.Name, e))
+	}
+	b.addErrors(info, errs...)
+}
+
+func (b brokenObjects) addErrors(info util.K8sObjectInfo, errs ...string) {
+	key := info.Key()
+	obj, ok := b[key]
+	if !ok {
+		obj = &brokenObject{K8sObjectInfo: info}
+		b[key] = obj
+	}
+	obj.errors = append(obj.errors, errs...)
+}
+
+// merge adds the broken objects of other to the set.
+func (b brokenObjects) merge(other brokenObjects) {
+	for _, obj := range other {
+		b.addErrors(obj.K8sObjectInfo, obj.errors...)
+	}
+}
+
+func (b brokenObjects) has(info util.K8sObjectInfo) bool {
+	_, ok := b[info.Key()]
+	return ok
+}
+
+// sorted provides the broken objects in a stable order.
+func (b brokenObjects) sorted() []*brokenObject {
+	keys := make([]string, 0, len(b))
+	for key := range b {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	objs := make([]*brokenObject, 0, len(keys))
+	for _, key := range keys {
+		objs = append(objs, b[key])
+	}
+	return objs
+}
+
+// object provides a minimal client.Object for the broken object, which
+// includes all the information needed to reference it (e.g. in Events).
+func (o *brokenObject) object() client.Object {
+	return o.ToK8sObject()
+}
+
+// serviceObjectInfo provides the object info for a Kubernetes Service, which
+// may come from the cache without its type information.
+func serviceObjectInfo(svc *corev1.Service) util.K8sObjectInfo {
+	info := util.FromK8sObject(svc)
+	info.GroupVersionKind = corev1.SchemeGroupVersion.WithKind("Service")
+	return info
+}
+
+// consumerObjectInfo provides the object info for a KongConsumer, which may
+// come from the cache without its type information.
+func consumerObjectInfo(consumer *configurationv1.KongConsumer) util.K8sObjectInfo {
+	info := util.FromK8sObject(consumer)
+	info.GroupVersionKind = configurationv1.SchemeGroupVersion.WithKind("KongConsumer")
+	return info
+}
+
+// resolveBrokenObjects maps the entities rejected by the data-plane back to
+// the Kubernetes objects they were translated from. An error is returned if
+// any of the entities can't be mapped, as the configuration can't be fixed by
+// excluding objects in that case.
+func resolveBrokenObjects(state *kongstate.KongState, entityErrors []sendconfig.EntityError) (brokenObjects, error) {
+	broken := brokenObjects{}
+	for _, entityErr := range entityErrors {
+		if !resolveBrokenEntity(state, entityErr, broken) {
+			return nil, fmt.Errorf("could not find the Kubernetes object for rejected %s %q", entityErr.Type, entityErr.Name)
+		}
+	}
+	return broken, nil
+}
+
+// resolveBrokenEntity adds the Kubernetes objects which produced the rejected
+// entity to the broken objects, reporting whether any were found.
+func resolveBrokenEntity(state *kongstate.KongState, entityErr sendconfig.EntityError, broken brokenObjects) bool {
+	if entityErr.Name == "" {
+		return false
+	}
+	found := false
+	switch entityErr.Type {
+	case "route":
+		for _, svc := range state.Services {
+			for _, route := range svc.Routes {
+				if route.Name != nil && *route.Name == entityErr.Name {
+					broken.add(route.Ingress, entityErr)
+					found = true
+				}
+			}
+		}
+	case "service":
+		for _, svc := range state.Services {
+			if svc.Name == nil || *svc.Name != entityErr.Name {
+				continue
+			}
+			for _, k8sService := range svc.K8sServices {
+				broken.add(serviceObjectInfo(k8sService), entityErr)
+				found = true
+			}
+			if svc.Parent != nil {
+				broken.add(util.FromK8sObject(svc.Parent), entityErr)
+				found = true
+			}
+		}
+	case "upstream":
+		for _, upstream := range state.Upstreams {
+			if upstream.Name == nil || *upstream.Name != entityErr.Name {
+				continue
+			}
+			for _, k8sService := range upstream.Service.K8sServices {
+				broken.add(serviceObjectInfo(k8sService), entityErr)
+				found = true
+			}
+		}
+	case "consumer":
+		for _, consumer := range state.Consumers {
+			if consumer.Username != nil && *consumer.Username == entityErr.Name {
+				broken.add(consumerObjectInfo(&consumer.K8sKongConsumer), entityErr)
+				found = true
+			}
+		}
+	}
+	return found
+}
+
+// excludeBrokenObjects returns a copy of the state without any of the
+// configuration which was translated from the broken objects.
+func excludeBrokenObjects(state *kongstate.KongState, broken brokenObjects) *kongstate.KongState {
+	fallback := *state
+	fallback.Services = nil
+	fallback.Upstreams = nil
+	fallback.Consumers = nil
+
+	excludedServiceHosts := map[string]struct{}{}
+	for _, svc := range state.Services {
+		if serviceIsBroken(svc, broken) {
+			if svc.Host != nil {
+				excludedServiceHosts[*svc.Host] = struct{}{}
+			}
+			continue
+		}
+		routes := make([]kongstate.Route, 0, len(svc.Routes))
+		for _, route := range svc.Routes {
+			if !broken.has(route.Ingress) {
+				routes = append(routes, route)
+			}
+		}
+		svc.Routes = routes
+		fallback.Services = append(fallback.Services, svc)
+	}
+
+	for _, upstream := range state.Upstreams {
+		if upstream.Name != nil {
+			if _, excluded := excludedServiceHosts[*upstream.Name]; excluded {
+				continue
+			}
+		}
+		if serviceIsBroken(upstream.Service, broken) {
+			continue
+		}
+		fallback.Upstreams = append(fallback.Upstreams, upstream)
+	}
+
+	for _, consumer := range state.Consumers {
+		if !broken.has(consumerObjectInfo(&consumer.K8sKongConsumer)) {
+			fallback.Consumers = append(fallback.Consumers, consumer)
+		}
+	}
+
+	return &fallback
+}
+
+// serviceIsBroken indicates whether a Kong service was translated from (or
+// for) any of the broken objects.
+func serviceIsBroken(svc kongstate.Service, broken brokenObjects) bool {
+	if svc.Parent != nil && broken.has(util.FromK8sObject(svc.Parent)) {
+		return true
+	}
+	for _, k8sService := range svc.K8sServices {
+		if broken.has(serviceObjectInfo(k8sService)) {
+			return true
+		}
+	}
+	return false
+}
+
+// excludeBrokenObjectsFromReport filters the broken objects out of a report of
+// configured Kubernetes objects, as they're not part of the applied configuration.
+func excludeBrokenObjectsFromReport(report []client.Object, broken brokenObjects) []client.Object {
+	filtered := make([]client.Object, 0, len(report))
+	for _, obj := range report {
+		if !broken.has(util.FromK8sObject(obj)) {
+			filtered = append(filtered, obj)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,89 @@
+package dataplane
+
+import (
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/sendconfig"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+)
+
+func TestFallbackConfiguration(t *testing.T) {
+	ingressGVK := networkingv1.SchemeGroupVersion.WithKind("Ingress")
+	goodIngress := util.K8sObjectInfo{Name: "good", Namespace: "default", GroupVersionKind: ingressGVK}
+	badIngress := util.K8sObjectInfo{Name: "bad", Namespace: "default", GroupVersionKind: ingressGVK}
+	svcFoo := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"}}
+	svcBar := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "default"}}
+
+	state := &kongstate.KongState{
+		Services: []kongstate.Service{
+			{
+				Service: kong.Service{Name: kong.String("default.foo.80"), Host: kong.String("foo.default.80.svc")},
+				Routes: []kongstate.Route{
+					{Route: kong.Route{Name: kong.String("default.good.00")}, Ingress: goodIngress},
+					{Route: kong.Route{Name: kong.String("default.bad.00")}, Ingress: badIngress},
+					{Route: kong.Route{Name: kong.String("default.bad.01")}, Ingress: badIngress},
+				},
+				K8sServices: map[string]*corev1.Service{"default/foo": svcFoo},
+			},
+			{
+				Service: kong.Service{Name: kong.String("default.bar.80"), Host: kong.String("bar.default.80.svc")},
+				Routes: []kongstate.Route{
+					{Route: kong.Route{Name: kong.String("default.good.01")}, Ingress: goodIngress},
+				},
+				K8sServices: map[string]*corev1.Service{"default/bar": svcBar},
+			},
+		},
+		Upstreams: []kongstate.Upstream{
+			{
+				Upstream: kong.Upstream{Name: kong.String("foo.default.80.svc")},
+				Service:  kongstate.Service{K8sServices: map[string]*corev1.Service{"default/foo": svcFoo}},
+			},
+			{
+				Upstream: kong.Upstream{Name: kong.String("bar.default.80.svc")},
+				Service:  kongstate.Service{K8sServices: map[string]*corev1.Service{"default/bar": svcBar}},
+			},
+		},
+	}
+
+	t.Log("verifying that rejected routes are mapped to the objects they were translated from")
+	broken, err := resolveBrokenObjects(state, []sendconfig.EntityError{
+		{Type: "route", Name: "default.bad.01", Errors: []string{"paths: invalid"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, broken, 1)
+	assert.True(t, broken.has(badIngress))
+	assert.Equal(t, []string{"route default.bad.01: paths: invalid"}, broken.sorted()[0].errors)
+
+	t.Log("verifying that all routes of a broken object are excluded from the fallback configuration")
+	fallback := excludeBrokenObjects(state, broken)
+	require.Len(t, fallback.Services, 2)
+	require.Len(t, fallback.Services[0].Routes, 1)
+	assert.Equal(t, "default.good.00", *fallback.Services[0].Routes[0].Name)
+	assert.Len(t, fallback.Upstreams, 2)
+	assert.Len(t, state.Services[0].Routes, 3, "the original state must not be modified")
+
+	t.Log("verifying that rejected upstreams exclude their services")
+	broken, err = resolveBrokenObjects(state, []sendconfig.EntityError{
+		{Type: "upstream", Name: "bar.default.80.svc", Errors: []string{"algorithm: invalid"}},
+	})
+	require.NoError(t, err)
+	fallback = excludeBrokenObjects(state, broken)
+	require.Len(t, fallback.Services, 1)
+	assert.Equal(t, "default.foo.80", *fallback.Services[0].Name)
+	require.Len(t, fallback.Upstreams, 1)
+	assert.Equal(t, "foo.default.80.svc", *fallback.Upstreams[0].Name)
+
+	t.Log("verifying that rejected entities which can't be mapped prevent a fallback")
+	_, err = resolveBrokenObjects(state, []sendconfig.EntityError{
+		{Type: "certificate", Errors: []string{"cert: invalid"}},
+	})
+	assert.Error(t, err)
+}
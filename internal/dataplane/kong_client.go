@@ -2,7 +2,10 @@ package dataplane
 
 import (
 	"context"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -10,13 +13,21 @@ import (
 	"github.com/kong/go-kong/kong"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/deckgen"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/sendconfig"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/introspection"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/metrics"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/tracing"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
 	k8sobj "github.com/kong/kubernetes-ingress-controller/v2/internal/util/kubernetes/object"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util/kubernetes/object/status"
@@ -47,6 +58,128 @@ type KongClient struct {
 	// the newer logic which combines them.
 	enableCombinedServiceRoutes bool
 
+	// enableFallbackConfiguration indicates that when the data-plane rejects a
+	// configuration because of specific entities, the Kubernetes objects those
+	// were translated from should be excluded and the rest of the configuration
+	// applied.
+	enableFallbackConfiguration bool
+
+	// enablePartialSync indicates that the Kubernetes objects which couldn't
+	// be fully translated should be excluded from the configuration rather
+	// than partially configured.
+	enablePartialSync bool
+
+	// orphanedEntitiesGCTags are the filter tags the controller previously
+	// managed its entities with. In DB mode, the entities carrying them which
+	// don't correspond to any cached Kubernetes object are deleted after
+	// updates if set.
+	orphanedEntitiesGCTags []string
+
+	// translationFailuresBudget is the number of Kubernetes objects which may
+	// fail translation before updates stop applying the configuration, no
+	// limit if zero.
+	translationFailuresBudget int
+
+	// updateTrigger, if set, makes the next update happen right away, e.g.
+	// when the contents of a TLS Secret in use change.
+	updateTrigger func()
+
+	// elected is closed once the instance of the controller the client runs in
+	// is elected leader, if set: until then the client only translates the
+	// configuration, without applying it to the data-plane.
+	elected <-chan struct{}
+
+	// enableEndpointSliceTargets indicates that the targets of all Services
+	// should be generated from their EndpointSlices rather than their Endpoints.
+	enableEndpointSliceTargets bool
+
+	// appliedConfigurationRecorder records the configuration the data-plane
+	// is serving after each successful update, if set.
+	appliedConfigurationRecorder AppliedConfigurationRecorder
+
+	// lastRecordedConfigSHA is the checksum of the configuration most recently
+	// recorded by the appliedConfigurationRecorder.
+	lastRecordedConfigSHA []byte
+
+	// configSnapshotStore persists the configuration applied to a DB-less
+	// data-plane after each successful update, if set.
+	configSnapshotStore ConfigSnapshotStore
+
+	// lastSnapshotConfigSHA is the checksum of the configuration most
+	// recently stored by the configSnapshotStore.
+	lastSnapshotConfigSHA []byte
+
+	// configExporter exports the configuration applied to the data-plane
+	// at most once per configExportInterval, if set.
+	configExporter       ConfigExporter
+	configExportInterval time.Duration
+
+	// exportedConfig is the redacted configuration of the last successful
+	// update, rendered for the configExporter.
+	exportedConfig []byte
+
+	// lastExportedConfigSHA is the checksum of the configuration most
+	// recently exported by the configExporter, at lastConfigExport.
+	lastExportedConfigSHA []byte
+	lastConfigExport      time.Time
+
+	// kongClusterPluginStatusUpdater reports whether the global
+	// KongClusterPlugins are applied after each update, if set.
+	kongClusterPluginStatusUpdater KongClusterPluginStatusUpdater
+
+	// l4IngressStatusUpdater reports whether rules of the TCPIngresses and
+	// UDPIngresses are ignored because of port conflicts after each
+	// translation, if set.
+	l4IngressStatusUpdater L4IngressStatusUpdater
+
+	// namespaceQuotas limits the amount of configuration the objects of a
+	// single namespace may produce.
+	namespaceQuotas util.NamespaceQuotas
+
+	// translationCache holds the translation of the Kubernetes objects which
+	// haven't changed since a previous update.
+	translationCache *parser.TranslationCache
+
+	// topologyZone is the zone of the data-plane, which the targets generated
+	// from endpoints are limited to.
+	topologyZone string
+
+	// defaultCertificate is the Secret, as "namespace/name", of the
+	// certificate served for the SNIs no TLS section covers, if set.
+	defaultCertificate string
+
+	// routerFlavor is the router_flavor of the data-plane, empty for Kong
+	// versions prior to 3.0.
+	routerFlavor string
+
+	// featureReports receives the report of the translation features in
+	// effect whenever it changes, if set.
+	featureReports chan<- util.FeatureReport
+
+	// lastFeatureReport is the report of the translation features in effect
+	// most recently logged, nil before the first update.
+	lastFeatureReport *util.FeatureReport
+
+	// clusterPluginSecretNamespaces are the namespaces of the Secrets which
+	// KongClusterPlugins may reference, any namespace if empty.
+	clusterPluginSecretNamespaces []string
+
+	// eventRecorder is used to emit Events for Kubernetes objects which
+	// couldn't be translated or were excluded from the configuration.
+	eventRecorder record.EventRecorder
+
+	// changeLinks are the spans of the changes to Kubernetes objects the next
+	// update applies, which its span is linked to.
+	changeLinks tracing.ChangeLinks
+
+	// propagation tracks when the changes to Kubernetes objects were observed,
+	// to measure how long they take to propagate to the data-plane.
+	propagation propagationTracker
+
+	// enablePropagationEvents indicates that an Event should be emitted for
+	// each change to a Kubernetes object propagated to the data-plane.
+	enablePropagationEvents bool
+
 	// skipCACertificates disables CA certificates, to avoid fighting over configuration in multi-workspace
 	// environments. See https://github.com/Kong/deck/pull/617
 	skipCACertificates bool
@@ -55,6 +188,12 @@ type KongClient struct {
 	// requests to the data-plane to receive a response.
 	requestTimeout time.Duration
 
+	// translationTimeout is the maximum amount of time a single translation
+	// of Kubernetes objects into Kong configuration may take. Translations
+	// exceeding this are discarded so that a partially built configuration is
+	// never sent to the data-plane. A zero value disables the deadline.
+	translationTimeout time.Duration
+
 	// cache is the Kubernetes object cache which is used to list Kubernetes
 	// objects for parsing into Kong objects.
 	cache *store.CacheStores
@@ -68,9 +207,25 @@ type KongClient struct {
 	// lastConfigSHA is a checksum of the last successful update to the data-plane
 	lastConfigSHA []byte
 
+	// lastConfig is the configuration of the last successful update to the
+	// data-plane, used to summarize the changes made by subsequent updates.
+	lastConfig *file.Content
+
+	// lastUpdateTimings is how long the stages of the last successful update
+	// took.
+	lastUpdateTimings UpdateTimings
+
 	// lock is used to ensure threadsafety of the KongClient object
 	lock sync.RWMutex
 
+	// introspectionModel is the model of the configuration of the last
+	// successful update to the data-plane, served by the introspection API.
+	introspectionModel *introspection.Model
+
+	// introspectionModelLock protects the introspectionModel, which is read
+	// while updates are in progress.
+	introspectionModelLock sync.RWMutex
+
 	// diagnostic is the client and configuration for reporting diagnostic
 	// information during data-plane update runtime.
 	diagnostic util.ConfigDumpDiagnostic
@@ -104,6 +259,14 @@ type KongClient struct {
 	// whether a Kubernetes object has corresponding data-plane configuration that
 	// is actively configured (e.g. to know how to set the object status).
 	kubernetesObjectReportsFilter k8sobj.Set
+
+	// kubernetesObjectReport is the list of objects which were included in the
+	// most recent Update(), kept so that their status updates can be requeued.
+	kubernetesObjectReport []client.Object
+
+	// kubernetesObjectReportGenerations are the generations of the objects
+	// which were included in the most recent Update(), by UID.
+	kubernetesObjectReportGenerations map[types.UID]int64
 }
 
 // NewKongClient provides a new KongClient object after connecting to the
@@ -116,6 +279,7 @@ func NewKongClient(
 	skipCACertificates bool,
 	diagnostic util.ConfigDumpDiagnostic,
 	kongConfig sendconfig.Kong,
+	eventRecorder record.EventRecorder,
 ) (*KongClient, error) {
 	// build the client object
 	cache := store.NewCacheStores()
@@ -125,10 +289,13 @@ func NewKongClient(
 		enableReverseSync:  enableReverseSync,
 		skipCACertificates: skipCACertificates,
 		requestTimeout:     timeout,
+		translationTimeout: DefaultTranslationTimeout,
 		diagnostic:         diagnostic,
 		prometheusMetrics:  metrics.NewCtrlFuncMetrics(),
 		cache:              &cache,
 		kongConfig:         kongConfig,
+		eventRecorder:      eventRecorder,
+		translationCache:   parser.NewTranslationCache(),
 	}
 
 	// download the kong root configuration (and validate connectivity to the proxy API)
@@ -182,7 +349,19 @@ func NewKongClient(
 func (c *KongClient) UpdateObject(obj client.Object) error {
 	// we do a deep copy of the object here so that the caller can continue to use
 	// the original object in a threadsafe manner.
-	return c.cache.Add(obj.DeepCopyObject())
+	copied := obj.DeepCopyObject().(client.Object)
+	var rotated bool
+	if secret, ok := copied.(*corev1.Secret); ok {
+		rotated = c.isTLSSecretRotation(secret)
+	}
+	if err := c.cache.Add(copied); err != nil {
+		return err
+	}
+	c.propagation.observe(copied, time.Now())
+	if rotated {
+		c.triggerUpdate()
+	}
+	return nil
 }
 
 // DeleteObject accepts a Kubernetes controller-runtime client.Object and removes it from the configuration cache.
@@ -192,9 +371,17 @@ func (c *KongClient) UpdateObject(obj client.Object) error {
 // under the hood the cache implementation will ignore deletions on objects
 // that are not present in the cache, so in those cases this is a no-op.
 func (c *KongClient) DeleteObject(obj client.Object) error {
+	c.propagation.forget(obj)
 	return c.cache.Delete(obj)
 }
 
+// TraceChange records the span of ctx, if any, as a change to a Kubernetes
+// object which the next update applies, so that the span of the update is
+// linked to it.
+func (c *KongClient) TraceChange(ctx context.Context) {
+	c.changeLinks.Add(ctx)
+}
+
 // ObjectExists indicates whether or not any version of the provided object is already present in the proxy.
 func (c *KongClient) ObjectExists(obj client.Object) (bool, error) {
 	_, exists, err := c.cache.Get(obj)
@@ -216,6 +403,22 @@ func (c *KongClient) RootWithTimeout() (map[string]interface{}, error) {
 	return c.kongConfig.Client.Root(ctx)
 }
 
+// KongRoutesExist probes the Kong Admin API for the routes of the given names,
+// reporting whether all of them are live in the data-plane.
+func (c *KongClient) KongRoutesExist(ctx context.Context, names ...string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+	for _, name := range names {
+		if _, err := c.kongConfig.Client.Routes.Get(ctx, kong.String(name)); err != nil {
+			if kong.IsNotFoundErr(err) {
+				return false, nil
+			}
+			return false, err
+		}
+	}
+	return true, nil
+}
+
 // -----------------------------------------------------------------------------
 // Dataplane Client - Kong - Reporting
 // -----------------------------------------------------------------------------
@@ -248,6 +451,37 @@ func (c *KongClient) KubernetesObjectIsConfigured(obj client.Object) bool {
 	return c.kubernetesObjectReportsFilter.Has(obj)
 }
 
+// KubernetesObjectGenerationIsConfigured reports whether the provided object
+// has active configuration for its current generation successfully applied to
+// the data-plane, i.e. whether its latest spec has been applied rather than a
+// previous one.
+func (c *KongClient) KubernetesObjectGenerationIsConfigured(obj client.Object) bool {
+	c.kubernetesObjectReportLock.RLock()
+	defer c.kubernetesObjectReportLock.RUnlock()
+	generation, ok := c.kubernetesObjectReportGenerations[obj.GetUID()]
+	return ok && generation >= obj.GetGeneration()
+}
+
+// RequeueKubernetesObjectReport queues all the objects configured in the most
+// recent Update() for reconciliation again so that their statuses can be
+// refreshed, e.g. when the addresses the data-plane can be reached on change.
+// If Kubernetes object reports are not enabled this is a no-op.
+func (c *KongClient) RequeueKubernetesObjectReport() {
+	c.kubernetesObjectReportLock.RLock()
+	if !c.kubernetesObjectReportsEnabled {
+		c.kubernetesObjectReportLock.RUnlock()
+		return
+	}
+	// the report is replaced rather than modified by updates, publishing is
+	// done without holding the lock as it blocks while the queue is full
+	queue, report := c.kubernetesObjectStatusQueue, c.kubernetesObjectReport
+	c.kubernetesObjectReportLock.RUnlock()
+
+	for _, obj := range report {
+		queue.Publish(obj)
+	}
+}
+
 // -----------------------------------------------------------------------------
 // Dataplane Client - Kong - Optional Features
 // -----------------------------------------------------------------------------
@@ -272,10 +506,366 @@ func (c *KongClient) AreCombinedServiceRoutesEnabled() bool {
 	return c.enableCombinedServiceRoutes
 }
 
+// SetTranslationTimeout configures the maximum amount of time a translation of
+// Kubernetes objects into Kong configuration may take before its result is
+// discarded. A zero value disables the deadline entirely.
+func (c *KongClient) SetTranslationTimeout(timeout time.Duration) {
+	c.additionalFeaturesLock.Lock()
+	defer c.additionalFeaturesLock.Unlock()
+	c.translationTimeout = timeout
+}
+
+// TranslationTimeout provides the currently configured translation deadline.
+func (c *KongClient) TranslationTimeout() time.Duration {
+	c.additionalFeaturesLock.RLock()
+	defer c.additionalFeaturesLock.RUnlock()
+	return c.translationTimeout
+}
+
+// EnableStandby makes the client build the configuration on each update without
+// applying it to the data-plane until elected is closed, once the instance of
+// the controller it runs in is elected leader. This keeps the cache and the
+// translations of standby instances warm, so that they can take over quickly.
+func (c *KongClient) EnableStandby(elected <-chan struct{}) {
+	c.additionalFeaturesLock.Lock()
+	defer c.additionalFeaturesLock.Unlock()
+	c.elected = elected
+}
+
+// IsStandby indicates whether the client only builds configurations, as the
+// instance of the controller it runs in isn't the leader.
+func (c *KongClient) IsStandby() bool {
+	c.additionalFeaturesLock.RLock()
+	elected := c.elected
+	c.additionalFeaturesLock.RUnlock()
+	if elected == nil {
+		return false
+	}
+	select {
+	case <-elected:
+		return false
+	default:
+		return true
+	}
+}
+
+// EnableFallbackConfiguration turns on the fallback configuration feature: when
+// the data-plane rejects a configuration because of specific entities, the
+// Kubernetes objects those were translated from are excluded and the remaining
+// configuration is applied.
+func (c *KongClient) EnableFallbackConfiguration() {
+	c.additionalFeaturesLock.Lock()
+	defer c.additionalFeaturesLock.Unlock()
+	c.enableFallbackConfiguration = true
+}
+
+// EnablePropagationEvents turns on emitting an Event for each change to a
+// Kubernetes object once it has propagated to the data-plane, reporting how
+// long it took.
+func (c *KongClient) EnablePropagationEvents() {
+	c.additionalFeaturesLock.Lock()
+	defer c.additionalFeaturesLock.Unlock()
+	c.enablePropagationEvents = true
+}
+
+// ArePropagationEventsEnabled determines whether Events are emitted for the
+// changes propagated to the data-plane.
+func (c *KongClient) ArePropagationEventsEnabled() bool {
+	c.additionalFeaturesLock.RLock()
+	defer c.additionalFeaturesLock.RUnlock()
+	return c.enablePropagationEvents
+}
+
+// IsFallbackConfigurationEnabled determines whether the fallback configuration
+// feature has been enabled.
+func (c *KongClient) IsFallbackConfigurationEnabled() bool {
+	c.additionalFeaturesLock.RLock()
+	defer c.additionalFeaturesLock.RUnlock()
+	return c.enableFallbackConfiguration
+}
+
+// EnablePartialSync turns on the partial sync feature: the Kubernetes objects
+// which couldn't be fully translated are excluded from the configuration and
+// the remaining configuration is applied.
+func (c *KongClient) EnablePartialSync() {
+	c.additionalFeaturesLock.Lock()
+	defer c.additionalFeaturesLock.Unlock()
+	c.enablePartialSync = true
+}
+
+// IsPartialSyncEnabled determines whether the partial sync feature has been
+// enabled.
+func (c *KongClient) IsPartialSyncEnabled() bool {
+	c.additionalFeaturesLock.RLock()
+	defer c.additionalFeaturesLock.RUnlock()
+	return c.enablePartialSync
+}
+
+// EnableOrphanedEntitiesGC turns on the deletion of the orphaned entities from
+// the Kong database after updates changing the configuration: the entities
+// carrying all the given filter tags, which the controller previously managed
+// its entities with, but not all the current ones, which aren't part of the
+// configuration and were translated from Kubernetes objects which aren't
+// cached anymore. It has no effect in DB-less mode.
+func (c *KongClient) EnableOrphanedEntitiesGC(previousFilterTags []string) {
+	c.additionalFeaturesLock.Lock()
+	defer c.additionalFeaturesLock.Unlock()
+	c.orphanedEntitiesGCTags = previousFilterTags
+}
+
+// OrphanedEntitiesGCTags returns the previous filter tags of the entities
+// whose deletion has been enabled, if any.
+func (c *KongClient) OrphanedEntitiesGCTags() []string {
+	c.additionalFeaturesLock.RLock()
+	defer c.additionalFeaturesLock.RUnlock()
+	return c.orphanedEntitiesGCTags
+}
+
+// SetTranslationFailuresBudget sets the number of Kubernetes objects which may
+// fail translation before updates stop applying the configuration, keeping
+// the configuration the data-plane serves instead. Zero disables the budget.
+func (c *KongClient) SetTranslationFailuresBudget(budget int) {
+	c.additionalFeaturesLock.Lock()
+	defer c.additionalFeaturesLock.Unlock()
+	c.translationFailuresBudget = budget
+}
+
+// TranslationFailuresBudget provides the number of Kubernetes objects which
+// may fail translation before updates stop applying the configuration.
+func (c *KongClient) TranslationFailuresBudget() int {
+	c.additionalFeaturesLock.RLock()
+	defer c.additionalFeaturesLock.RUnlock()
+	return c.translationFailuresBudget
+}
+
+// SetUpdateTrigger sets the function making the next update happen right away,
+// which is called when the contents of a TLS Secret in use change so that
+// certificate renewals propagate to the data-plane immediately.
+func (c *KongClient) SetUpdateTrigger(trigger func()) {
+	c.additionalFeaturesLock.Lock()
+	defer c.additionalFeaturesLock.Unlock()
+	c.updateTrigger = trigger
+}
+
+// EnableEndpointSliceTargets turns on generating the targets of all Services
+// from their EndpointSlices, so that the conditions of their endpoints are
+// taken into account.
+func (c *KongClient) EnableEndpointSliceTargets() {
+	c.additionalFeaturesLock.Lock()
+	defer c.additionalFeaturesLock.Unlock()
+	c.enableEndpointSliceTargets = true
+}
+
+// AreEndpointSliceTargetsEnabled determines whether the targets of all
+// Services are generated from their EndpointSlices.
+func (c *KongClient) AreEndpointSliceTargetsEnabled() bool {
+	c.additionalFeaturesLock.RLock()
+	defer c.additionalFeaturesLock.RUnlock()
+	return c.enableEndpointSliceTargets
+}
+
+// SetNamespaceQuotas limits the amount of configuration which the Kubernetes
+// objects of any single namespace may produce.
+func (c *KongClient) SetNamespaceQuotas(quotas util.NamespaceQuotas) {
+	c.additionalFeaturesLock.Lock()
+	defer c.additionalFeaturesLock.Unlock()
+	c.namespaceQuotas = quotas
+}
+
+// NamespaceQuotas provides the currently configured namespace quotas.
+func (c *KongClient) NamespaceQuotas() util.NamespaceQuotas {
+	c.additionalFeaturesLock.RLock()
+	defer c.additionalFeaturesLock.RUnlock()
+	return c.namespaceQuotas
+}
+
+// SetTopologyZone sets the zone of the data-plane, which the targets generated
+// from the endpoints of Services are limited to.
+func (c *KongClient) SetTopologyZone(zone string) {
+	c.additionalFeaturesLock.Lock()
+	defer c.additionalFeaturesLock.Unlock()
+	c.topologyZone = zone
+}
+
+// TopologyZone provides the currently configured zone of the data-plane.
+func (c *KongClient) TopologyZone() string {
+	c.additionalFeaturesLock.RLock()
+	defer c.additionalFeaturesLock.RUnlock()
+	return c.topologyZone
+}
+
+// SetDefaultCertificate sets the Secret, given as "namespace/name", of the
+// certificate served for the SNIs no TLS section covers.
+func (c *KongClient) SetDefaultCertificate(secretName string) {
+	c.additionalFeaturesLock.Lock()
+	defer c.additionalFeaturesLock.Unlock()
+	c.defaultCertificate = secretName
+}
+
+// DefaultCertificate provides the Secret of the certificate served for the
+// SNIs no TLS section covers, if set.
+func (c *KongClient) DefaultCertificate() string {
+	c.additionalFeaturesLock.RLock()
+	defer c.additionalFeaturesLock.RUnlock()
+	return c.defaultCertificate
+}
+
+// SetRouterFlavor sets the router_flavor of the data-plane, which is reported
+// along with the translation features in effect.
+func (c *KongClient) SetRouterFlavor(flavor string) {
+	c.additionalFeaturesLock.Lock()
+	defer c.additionalFeaturesLock.Unlock()
+	c.routerFlavor = flavor
+}
+
+// SetFeatureReports makes the client send the report of the translation
+// features in effect to reports whenever it changes, e.g. for the diagnostics
+// server to serve it. Reports are dropped while reports is full.
+func (c *KongClient) SetFeatureReports(reports chan<- util.FeatureReport) {
+	c.additionalFeaturesLock.Lock()
+	defer c.additionalFeaturesLock.Unlock()
+	c.featureReports = reports
+}
+
+// FeatureReport describes the translation features currently in effect.
+func (c *KongClient) FeatureReport() util.FeatureReport {
+	c.additionalFeaturesLock.RLock()
+	defer c.additionalFeaturesLock.RUnlock()
+	return util.FeatureReport{
+		CombinedServiceRoutes:   c.enableCombinedServiceRoutes,
+		RegexPathPrefix:         util.GetKongVersion().GTE(parser.MinRegexPathPrefixKongVersion),
+		EndpointSliceTargets:    c.enableEndpointSliceTargets,
+		FallbackConfiguration:   c.enableFallbackConfiguration,
+		PartialSync:             c.enablePartialSync,
+		KubernetesObjectReports: c.AreKubernetesObjectReportsEnabled(),
+		RouterFlavor:            c.routerFlavor,
+		DBMode:                  c.dbmode,
+	}
+}
+
+// SetClusterPluginSecretNamespaces limits the namespaces of the Secrets which
+// KongClusterPlugins may reference for their configuration. An empty list
+// allows any namespace.
+func (c *KongClient) SetClusterPluginSecretNamespaces(namespaces []string) {
+	c.additionalFeaturesLock.Lock()
+	defer c.additionalFeaturesLock.Unlock()
+	c.clusterPluginSecretNamespaces = namespaces
+}
+
+// ClusterPluginSecretNamespaces provides the namespaces of the Secrets which
+// KongClusterPlugins may reference, empty if any namespace is allowed.
+func (c *KongClient) ClusterPluginSecretNamespaces() []string {
+	c.additionalFeaturesLock.RLock()
+	defer c.additionalFeaturesLock.RUnlock()
+	return c.clusterPluginSecretNamespaces
+}
+
+// SetAppliedConfigurationRecorder configures a recorder which is told about
+// the configuration the data-plane is serving after each successful update.
+func (c *KongClient) SetAppliedConfigurationRecorder(recorder AppliedConfigurationRecorder) {
+	c.additionalFeaturesLock.Lock()
+	defer c.additionalFeaturesLock.Unlock()
+	c.appliedConfigurationRecorder = recorder
+}
+
+// AppliedConfigurationRecorder provides the currently configured recorder of
+// applied configurations, if any.
+func (c *KongClient) AppliedConfigurationRecorder() AppliedConfigurationRecorder {
+	c.additionalFeaturesLock.RLock()
+	defer c.additionalFeaturesLock.RUnlock()
+	return c.appliedConfigurationRecorder
+}
+
+// SetConfigSnapshotStore configures a store which persists the configuration
+// applied to a DB-less data-plane after each successful update.
+func (c *KongClient) SetConfigSnapshotStore(store ConfigSnapshotStore) {
+	c.additionalFeaturesLock.Lock()
+	defer c.additionalFeaturesLock.Unlock()
+	c.configSnapshotStore = store
+}
+
+// ConfigSnapshotStore provides the currently configured store of
+// configuration snapshots, if any.
+func (c *KongClient) ConfigSnapshotStore() ConfigSnapshotStore {
+	c.additionalFeaturesLock.RLock()
+	defer c.additionalFeaturesLock.RUnlock()
+	return c.configSnapshotStore
+}
+
+// SetConfigExporter configures an exporter which is passed the configuration
+// applied to the data-plane after successful updates which changed it, at most
+// once per interval.
+func (c *KongClient) SetConfigExporter(exporter ConfigExporter, interval time.Duration) {
+	c.additionalFeaturesLock.Lock()
+	defer c.additionalFeaturesLock.Unlock()
+	c.configExporter = exporter
+	c.configExportInterval = interval
+}
+
+// ConfigExporter provides the currently configured configuration exporter, if
+// any, and the minimum interval between exports.
+func (c *KongClient) ConfigExporter() (ConfigExporter, time.Duration) {
+	c.additionalFeaturesLock.RLock()
+	defer c.additionalFeaturesLock.RUnlock()
+	return c.configExporter, c.configExportInterval
+}
+
+// SetKongClusterPluginStatusUpdater configures an updater which reports in the
+// status of the global KongClusterPlugins whether they're applied after each
+// update.
+func (c *KongClient) SetKongClusterPluginStatusUpdater(updater KongClusterPluginStatusUpdater) {
+	c.additionalFeaturesLock.Lock()
+	defer c.additionalFeaturesLock.Unlock()
+	c.kongClusterPluginStatusUpdater = updater
+}
+
+// KongClusterPluginStatusUpdater provides the currently configured updater of
+// KongClusterPlugin statuses, if any.
+func (c *KongClient) KongClusterPluginStatusUpdater() KongClusterPluginStatusUpdater {
+	c.additionalFeaturesLock.RLock()
+	defer c.additionalFeaturesLock.RUnlock()
+	return c.kongClusterPluginStatusUpdater
+}
+
+// SetL4IngressStatusUpdater configures an updater which reports in the status
+// of the TCPIngresses and UDPIngresses whether rules of theirs are ignored
+// because of port conflicts after each translation.
+func (c *KongClient) SetL4IngressStatusUpdater(updater L4IngressStatusUpdater) {
+	c.additionalFeaturesLock.Lock()
+	defer c.additionalFeaturesLock.Unlock()
+	c.l4IngressStatusUpdater = updater
+}
+
+// L4IngressStatusUpdater provides the currently configured updater of
+// TCPIngress and UDPIngress statuses, if any.
+func (c *KongClient) L4IngressStatusUpdater() L4IngressStatusUpdater {
+	c.additionalFeaturesLock.RLock()
+	defer c.additionalFeaturesLock.RUnlock()
+	return c.l4IngressStatusUpdater
+}
+
 // -----------------------------------------------------------------------------
 // Dataplane Client - Kong - Interface Implementation
 // -----------------------------------------------------------------------------
 
+// UpdateTimings is how long the stages of an update took.
+type UpdateTimings struct {
+	// Translation is how long translating the Kubernetes objects into Kong
+	// configuration took.
+	Translation time.Duration
+
+	// Sync is how long applying the configuration to the data-plane took.
+	Sync time.Duration
+}
+
+// LastUpdateTimings provides how long the stages of the last successful
+// update took, or zero timings if no update succeeded yet.
+func (c *KongClient) LastUpdateTimings() UpdateTimings {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.lastUpdateTimings
+}
+
 // DBMode indicates which database the Kong Gateway is using
 func (c *KongClient) DBMode() string {
 	c.lock.RLock()
@@ -283,46 +873,378 @@ func (c *KongClient) DBMode() string {
 	return c.dbmode
 }
 
+// ForceNextUpdate makes the next Update() push the configuration to the
+// data-plane even if it hasn't changed since the last successful update, e.g.
+// to revert manual changes made to a DB-backed Kong.
+func (c *KongClient) ForceNextUpdate() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.lastConfigSHA = nil
+}
+
 // Update parses the Cache present in the client and converts current
 // Kubernetes state into Kong objects and state, and then ships the
 // resulting configuration to the data-plane (Kong Admin API).
-func (c *KongClient) Update(ctx context.Context) error {
+func (c *KongClient) Update(ctx context.Context) (err error) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
-	// build the kongstate object from the Kubernetes objects in the storer
-	storer := store.New(*c.cache, c.ingressClass, false, false, false, c.logger)
+	ctx, span := tracing.Tracer().Start(ctx, "KongClient.Update", trace.WithLinks(c.changeLinks.Pop()...))
+	defer func() { tracing.EndSpan(span, err) }()
+
+	// all the changes observed so far are part of this update
+	changes := c.propagation.pendingChanges()
+
+	// build the kongstate object from a snapshot of the Kubernetes objects, so
+	// that changes made to them while translating don't apply halfway
+	snapshot := c.cache.Snapshot()
+	storer := store.New(snapshot, c.ingressClass, false, false, false, c.logger)
 
 	// initialize a parser
 	c.logger.Debug("parsing kubernetes objects into data-plane configuration")
+	features := c.FeatureReport()
+	c.reportFeatures(features)
 	p := parser.NewParser(c.logger, storer)
-	if c.AreKubernetesObjectReportsEnabled() {
+	if features.KubernetesObjectReports {
 		p.EnableKubernetesObjectReports()
 	}
-	if c.AreCombinedServiceRoutesEnabled() {
+	if features.CombinedServiceRoutes {
 		p.EnableCombinedServiceRoutes()
 	}
+	if features.EndpointSliceTargets {
+		p.EnableEndpointSliceTargets()
+	}
+	if features.RegexPathPrefix {
+		p.EnableRegexPathPrefix()
+	}
+	p.SetNamespaceQuotas(c.NamespaceQuotas())
+	p.SetTopologyZone(c.TopologyZone())
+	p.SetDefaultCertificate(c.DefaultCertificate())
+	p.SetClusterPluginSecretNamespaces(c.ClusterPluginSecretNamespaces())
+	p.SetTranslationCache(c.translationCache)
 
 	// parse the Kubernetes objects from the storer into Kong configuration
-	kongstate, err := p.Build()
+	translationStart := time.Now()
+	translationCtx, translationSpan := tracing.Tracer().Start(ctx, "parser.Build")
+	kongstate, err := buildWithTimeout(translationCtx, p, c.TranslationTimeout())
+	tracing.EndSpan(translationSpan, err)
+	translationDuration := float64(time.Since(translationStart).Milliseconds())
 	if err != nil {
+		if errors.Is(err, ErrTranslationTimeout) {
+			c.prometheusMetrics.TranslationTimeoutCount.Inc()
+			c.logger.Warnf("%s, keeping the last successfully applied configuration", err)
+		}
 		c.prometheusMetrics.TranslationCount.With(prometheus.Labels{
 			metrics.SuccessKey: metrics.SuccessFalse,
 		}).Inc()
+		c.prometheusMetrics.TranslationDuration.With(prometheus.Labels{
+			metrics.SuccessKey: metrics.SuccessFalse,
+		}).Observe(translationDuration)
 		return err
 	}
 	c.prometheusMetrics.TranslationCount.With(prometheus.Labels{
 		metrics.SuccessKey: metrics.SuccessTrue,
 	}).Inc()
+	c.prometheusMetrics.TranslationDuration.With(prometheus.Labels{
+		metrics.SuccessKey: metrics.SuccessTrue,
+	}).Observe(translationDuration)
 	c.logger.Debug("successfully built data-plane configuration")
+	timings := UpdateTimings{Translation: time.Since(translationStart)}
+
+	// standby instances keep the configuration ready without applying it, the
+	// leader reports on it
+	if c.IsStandby() {
+		p.PopTranslationFailures()
+		p.PopPortConflicts()
+		c.propagation.propagated(changes)
+		c.logger.Debug("not the leader, skipping the configuration update")
+		return nil
+	}
+
+	// let users know about any objects which couldn't be fully translated
+	translationFailures := p.PopTranslationFailures()
+	failedObjects := translationFailedObjects(translationFailures)
+	partialSync := features.PartialSync && len(failedObjects) > 0
+	c.recordTranslationFailureEvents(translationFailures, partialSync)
+	c.reportTranslationFailures(failedObjects, partialSync)
+	portConflicts := p.PopPortConflicts()
+	c.reportPortConflicts(portConflicts)
+	c.updateL4IngressStatuses(ctx, storer, portConflicts)
+	if budget := c.TranslationFailuresBudget(); budget > 0 && len(failedObjects) > budget {
+		err := fmt.Errorf("%d Kubernetes objects couldn't be translated, more than the translation failures budget of %d: "+
+			"keeping the current data-plane configuration", len(failedObjects), budget)
+		c.updateClusterPluginStatuses(ctx, storer, kongstate, translationFailures, err)
+		return err
+	}
+
+	// exclude the objects which couldn't be fully translated if enabled
+	excludedObjects := brokenObjects{}
+	if partialSync {
+		c.logger.Warnf("excluding %d Kubernetes objects which couldn't be translated from the configuration", len(failedObjects))
+		kongstate = excludeBrokenObjects(kongstate, failedObjects)
+		excludedObjects.merge(failedObjects)
+	}
+
+	// generate the deck configuration and apply it to the data-plane
+	syncStart := time.Now()
+	targetConfig, newConfigSHA, err := c.sendConfig(ctx, kongstate)
+	if err != nil {
+		var rejectedErr sendconfig.ConfigRejectedError
+		if !c.IsFallbackConfigurationEnabled() || !errors.As(err, &rejectedErr) {
+			c.updateClusterPluginStatuses(ctx, storer, kongstate, translationFailures, err)
+			return err
+		}
+		var rejectedObjects brokenObjects
+		rejectedObjects, targetConfig, newConfigSHA, err = c.sendFallbackConfig(ctx, kongstate, rejectedErr.EntityErrors, err)
+		if err != nil {
+			c.updateClusterPluginStatuses(ctx, storer, kongstate, translationFailures, err)
+			return err
+		}
+		excludedObjects.merge(rejectedObjects)
+	}
+	timings.Sync = time.Since(syncStart)
+	c.lastUpdateTimings = timings
+	c.updateClusterPluginStatuses(ctx, storer, kongstate, translationFailures, nil)
+
+	// summarize the changes made by this update, if there were any
+	if string(c.lastConfigSHA) != string(newConfigSHA) {
+		c.reportConfigDiff(deckgen.SummarizeConfigDiff(c.lastConfig, targetConfig))
+		c.reportConfigHash(newConfigSHA)
+		c.lastConfig = targetConfig
+
+		appliedState := kongstate
+		if len(excludedObjects) > 0 {
+			appliedState = excludeBrokenObjects(kongstate, excludedObjects)
+		}
+		c.setIntrospectionModel(introspection.NewModel(appliedState))
+		c.renderExportedConfig(ctx, appliedState)
+		c.collectOrphanedEntities(ctx, snapshot, targetConfig)
+	}
+
+	// report on configured Kubernetes objects if enabled
+	if c.AreKubernetesObjectReportsEnabled() {
+		if string(c.lastConfigSHA) != string(newConfigSHA) {
+			report := p.GenerateKubernetesObjectReport()
+			if len(excludedObjects) > 0 {
+				report = excludeBrokenObjectsFromReport(report, excludedObjects)
+			}
+			c.logger.Debugf("triggering report for %d configured Kubernetes objects", len(report))
+			c.triggerKubernetesObjectReport(report...)
+		} else {
+			c.logger.Debug("no configuration change, skipping kubernetes object report")
+		}
+	}
+
+	// update the lastConfigSHA with the new updated checksum
+	c.lastConfigSHA = newConfigSHA
+
+	// record which configuration the data-plane is serving if enabled
+	c.recordAppliedConfiguration(ctx, newConfigSHA)
+
+	// export the configuration the data-plane is serving if enabled
+	c.exportConfig(ctx, newConfigSHA)
+
+	c.reportPropagation(changes, excludedObjects)
+	return nil
+}
+
+// reportPropagation measures how long the changes to Kubernetes objects which
+// an update successfully applied took to propagate to the data-plane, from
+// when they were observed, and emits an Event for each of them if enabled.
+// The changes of broken objects excluded from the configuration are still
+// pending.
+func (c *KongClient) reportPropagation(changes []observedChange, excluded brokenObjects) {
+	if len(excluded) > 0 {
+		broken := make(map[types.UID]bool, len(excluded))
+		for _, obj := range excluded {
+			broken[obj.UID] = true
+		}
+		applied := changes[:0]
+		for _, change := range changes {
+			if !broken[change.obj.GetUID()] {
+				applied = append(applied, change)
+			}
+		}
+		changes = applied
+	}
+
+	now := time.Now()
+	events := c.ArePropagationEventsEnabled()
+	for _, change := range c.propagation.propagated(changes) {
+		duration := now.Sub(change.observedAt)
+		c.prometheusMetrics.ConfigPropagationDuration.With(prometheus.Labels{
+			metrics.KindKey: change.kind(),
+		}).Observe(float64(duration.Milliseconds()))
+		if events {
+			c.eventRecorder.Eventf(change.obj, corev1.EventTypeNormal, KongConfigurationAppliedEventReason,
+				"configuration applied to the data-plane %s after the change was observed", duration.Round(time.Millisecond))
+		}
+	}
+}
+
+// reportFeatures logs and exports the translation features in effect when they
+// differ from those of the previous update, which they do for the first one,
+// so that the effective behavior of the controller is known at a glance.
+func (c *KongClient) reportFeatures(report util.FeatureReport) {
+	if c.lastFeatureReport != nil && *c.lastFeatureReport == report {
+		return
+	}
+	c.lastFeatureReport = &report
+
+	fields := logrus.Fields{"router_flavor": report.RouterFlavor, "db_mode": report.DBMode}
+	for feature, enabled := range report.Features() {
+		fields[feature] = enabled
+		value := 0.0
+		if enabled {
+			value = 1
+		}
+		c.prometheusMetrics.FeatureEnabled.With(prometheus.Labels{metrics.FeatureKey: feature}).Set(value)
+	}
+	c.logger.WithFields(fields).Info("translation features in effect")
+
+	c.additionalFeaturesLock.RLock()
+	reports := c.featureReports
+	c.additionalFeaturesLock.RUnlock()
+	if reports == nil {
+		return
+	}
+	select {
+	case reports <- report:
+	default:
+		c.logger.Error("feature report buffer full, dropping feature report")
+	}
+}
+
+// IntrospectionModel provides the model of the configuration of the last
+// successful update to the data-plane, nil if there was none yet.
+func (c *KongClient) IntrospectionModel() *introspection.Model {
+	c.introspectionModelLock.RLock()
+	defer c.introspectionModelLock.RUnlock()
+	return c.introspectionModel
+}
+
+func (c *KongClient) setIntrospectionModel(model *introspection.Model) {
+	c.introspectionModelLock.Lock()
+	defer c.introspectionModelLock.Unlock()
+	c.introspectionModel = model
+}
+
+// recordAppliedConfiguration passes the checksum of the applied configuration
+// to the applied configuration recorder, if any, unless it was already recorded.
+// Failures are only logged, as the configuration has been applied regardless:
+// recording will be attempted again on the next update.
+func (c *KongClient) recordAppliedConfiguration(ctx context.Context, configSHA []byte) {
+	recorder := c.AppliedConfigurationRecorder()
+	if recorder == nil || string(c.lastRecordedConfigSHA) == string(configSHA) {
+		return
+	}
+	timedCtx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+	if err := recorder.RecordAppliedConfiguration(timedCtx, configSHA, time.Now()); err != nil {
+		c.logger.WithError(err).Error("failed to record the applied configuration")
+		return
+	}
+	c.lastRecordedConfigSHA = configSHA
+}
+
+// collectOrphanedEntities deletes the orphaned entities from the Kong
+// database if enabled, in DB mode, given the snapshot of the Kubernetes
+// objects the applied configuration was translated from. Failures are only
+// logged, the next update changing the configuration will try again.
+func (c *KongClient) collectOrphanedEntities(ctx context.Context, snapshot store.CacheStores, targetConfig *file.Content) {
+	previousFilterTags := c.OrphanedEntitiesGCTags()
+	if c.kongConfig.InMemory || len(previousFilterTags) == 0 {
+		return
+	}
+	uids := snapshot.UIDs()
+	timedCtx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+	deleted, err := sendconfig.CollectOrphanedEntities(timedCtx, c.logger, &c.kongConfig, previousFilterTags, targetConfig,
+		func(uid types.UID) bool {
+			_, ok := uids[uid]
+			return ok
+		},
+	)
+	if err != nil {
+		c.logger.WithError(err).Error("failed to collect orphaned entities")
+		return
+	}
+	if deleted > 0 {
+		c.logger.Infof("deleted %d orphaned entities from the Kong database", deleted)
+	}
+}
+
+// renderExportedConfig renders the configuration translated from state for the
+// configuration exporter, if any, with credentials and sensitive plugin
+// configuration redacted.
+func (c *KongClient) renderExportedConfig(ctx context.Context, state *kongstate.KongState) {
+	if exporter, _ := c.ConfigExporter(); exporter == nil {
+		return
+	}
+	redactedConfig := deckgen.ToDeckContent(ctx,
+		c.logger,
+		state.SanitizedCopy(),
+		c.kongConfig.PluginSchemaStore,
+		c.kongConfig.FilterTags,
+	)
+	deckgen.RedactPluginConfigs(ctx, redactedConfig, c.kongConfig.PluginSchemaStore)
+	config, err := yaml.Marshal(redactedConfig)
+	if err != nil {
+		c.logger.WithError(err).Error("failed to render the exported configuration")
+		return
+	}
+	c.exportedConfig = config
+}
+
+// exportConfig passes the rendered configuration to the configuration
+// exporter, if any, unless it was already exported or the previous export was
+// less than the export interval ago, in which case it's exported on a later
+// update. Failures are only logged, as the configuration has been applied
+// regardless: exporting will be attempted again on the next update.
+func (c *KongClient) exportConfig(ctx context.Context, configSHA []byte) {
+	exporter, interval := c.ConfigExporter()
+	if exporter == nil || c.exportedConfig == nil || string(c.lastExportedConfigSHA) == string(configSHA) ||
+		time.Since(c.lastConfigExport) < interval {
+		return
+	}
+	timedCtx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+	if err := exporter.ExportConfig(timedCtx, c.exportedConfig); err != nil {
+		c.logger.WithError(err).Error("failed to export the configuration")
+		return
+	}
+	c.lastExportedConfigSHA = configSHA
+	c.lastConfigExport = time.Now()
+}
 
+// sendConfig converts the provided KongState into deck configuration and
+// applies it to the data-plane, returning the applied configuration and its
+// checksum.
+func (c *KongClient) sendConfig(ctx context.Context, state *kongstate.KongState) (*file.Content, []byte, error) {
 	// generate the deck configuration to be applied to the admin API
 	c.logger.Debug("converting configuration to deck config")
 	targetConfig := deckgen.ToDeckContent(ctx,
-		c.logger, kongstate,
+		c.logger, state,
 		c.kongConfig.PluginSchemaStore,
 		c.kongConfig.FilterTags,
 	)
+	var customEntities []byte
+	var pluginOrderings deckgen.PluginOrderings
+	if c.kongConfig.InMemory {
+		var err error
+		customEntities, err = deckgen.ToCustomEntities(state)
+		if err != nil {
+			return nil, nil, err
+		}
+		pluginOrderings = deckgen.ToPluginOrderings(state)
+	} else {
+		if len(state.Vaults) > 0 {
+			c.logger.Warn("KongVaults are only applied to Kong in DB-less mode, ignoring them")
+		}
+		if deckgen.ToPluginOrderings(state) != nil {
+			c.logger.Warn("plugin orderings are only applied to Kong in DB-less mode, ignoring them")
+		}
+	}
 
 	// generate diagnostic configuration if enabled
 	// "diagnostic" will be empty if --dump-config is not set
@@ -331,10 +1253,11 @@ func (c *KongClient) Update(ctx context.Context) error {
 		if !c.diagnostic.DumpsIncludeSensitive {
 			redactedConfig := deckgen.ToDeckContent(ctx,
 				c.logger,
-				kongstate.SanitizedCopy(),
+				state.SanitizedCopy(),
 				c.kongConfig.PluginSchemaStore,
 				c.kongConfig.FilterTags,
 			)
+			deckgen.RedactPluginConfigs(ctx, redactedConfig, c.kongConfig.PluginSchemaStore)
 			diagnosticConfig = redactedConfig
 		} else {
 			diagnosticConfig = targetConfig
@@ -353,7 +1276,8 @@ func (c *KongClient) Update(ctx context.Context) error {
 		c.skipCACertificates,
 		targetConfig,
 		c.kongConfig.FilterTags,
-		nil,
+		customEntities,
+		pluginOrderings,
 		c.lastConfigSHA,
 		c.prometheusMetrics,
 	)
@@ -370,39 +1294,174 @@ func (c *KongClient) Update(ctx context.Context) error {
 				c.logger.Error("config diagnostic buffer full, dropping diagnostic config")
 			}
 		}
-		return err
+		return nil, nil, err
 	}
 
 	// ship diagnostics if enabled
 	if c.diagnostic != (util.ConfigDumpDiagnostic{}) {
 		select {
-		case c.diagnostic.Configs <- util.ConfigDump{Failed: false, Config: *diagnosticConfig}:
+		case c.diagnostic.Configs <- util.ConfigDump{Failed: false, Config: *diagnosticConfig, Hash: hex.EncodeToString(newConfigSHA)}:
 			c.logger.Debug("shipping config to diagnostic server")
 		default:
 			c.logger.Error("config diagnostic buffer full, dropping diagnostic config")
 		}
 	}
 
-	// report on configured Kubernetes objects if enabled
-	if c.AreKubernetesObjectReportsEnabled() {
-		if string(c.lastConfigSHA) != string(newConfigSHA) {
-			report := p.GenerateKubernetesObjectReport()
-			c.logger.Debugf("triggering report for %d configured Kubernetes objects", len(report))
-			c.triggerKubernetesObjectReport(report...)
-		} else {
-			c.logger.Debug("no configuration change, skipping kubernetes object report")
+	if c.kongConfig.InMemory {
+		c.storeConfigSnapshot(ctx, newConfigSHA, targetConfig, customEntities, pluginOrderings)
+	}
+
+	return targetConfig, newConfigSHA, nil
+}
+
+// storeConfigSnapshot passes the applied configuration to the configuration
+// snapshot store, if any, unless it was already stored. Failures are only
+// logged, as the configuration has been applied regardless: storing it will
+// be attempted again on the next update.
+func (c *KongClient) storeConfigSnapshot(
+	ctx context.Context,
+	configSHA []byte,
+	targetConfig *file.Content,
+	customEntities []byte,
+	pluginOrderings deckgen.PluginOrderings,
+) {
+	store := c.ConfigSnapshotStore()
+	if store == nil || string(c.lastSnapshotConfigSHA) == string(configSHA) {
+		return
+	}
+	config, err := sendconfig.RenderInMemoryConfig(c.logger, targetConfig, customEntities, pluginOrderings)
+	if err != nil {
+		c.logger.WithError(err).Error("failed to render the configuration snapshot")
+		return
+	}
+	timedCtx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+	if err := store.StoreConfigSnapshot(timedCtx, config); err != nil {
+		switch {
+		case errors.Is(err, ErrNotConfigSnapshotWriter):
+			c.logger.Debug("the configuration snapshot is stored by another instance of the controller")
+		case errors.Is(err, ErrConfigSnapshotTooLarge):
+			// the same configuration would be skipped again
+			c.logger.WithError(err).Error("skipping the configuration snapshot")
+			c.prometheusMetrics.ConfigSnapshotTooLargeCount.Inc()
+			c.lastSnapshotConfigSHA = configSHA
+		default:
+			c.logger.WithError(err).Error("failed to store the configuration snapshot")
 		}
+		return
+	}
+	c.lastSnapshotConfigSHA = configSHA
+}
+
+// sendFallbackConfig is used when the data-plane rejected a configuration
+// because of specific entities: it finds the Kubernetes objects those entities
+// were translated from, emits Events for them and applies the configuration
+// again without them, so that valid objects are still configured. If the
+// fallback configuration can't be built or applied the original error is
+// returned.
+func (c *KongClient) sendFallbackConfig(
+	ctx context.Context,
+	state *kongstate.KongState,
+	entityErrors []sendconfig.EntityError,
+	originalErr error,
+) (brokenObjects, *file.Content, []byte, error) {
+	broken, err := resolveBrokenObjects(state, entityErrors)
+	if err != nil {
+		c.logger.WithError(err).Error("unable to build fallback configuration")
+		return nil, nil, nil, originalErr
 	}
+	c.recordBrokenObjectEvents(broken)
 
-	// update the lastConfigSHA with the new updated checksum
-	c.lastConfigSHA = newConfigSHA
-	return nil
+	c.logger.Warnf("configuration rejected by kong, retrying without %d broken Kubernetes objects", len(broken))
+	targetConfig, newConfigSHA, err := c.sendConfig(ctx, excludeBrokenObjects(state, broken))
+	if err != nil {
+		c.logger.WithError(err).Error("fallback configuration was rejected by kong")
+		return nil, nil, nil, originalErr
+	}
+	return broken, targetConfig, newConfigSHA, nil
+}
+
+// recordTranslationFailureEvents emits a warning Event for each of the
+// problems encountered while translating Kubernetes objects, which tells
+// whether the objects are excluded from the configuration.
+func (c *KongClient) recordTranslationFailureEvents(failures []parser.TranslationFailure, excluded bool) {
+	for _, failure := range failures {
+		message := failure.Message
+		if excluded {
+			message = "invalid configuration excluded from the data-plane: " + message
+		}
+		c.eventRecorder.Event(failure.Object, corev1.EventTypeWarning, KongConfigurationTranslationFailedEventReason, message)
+	}
+}
+
+// recordBrokenObjectEvents emits a warning Event for each of the broken
+// Kubernetes objects, containing the errors reported by the data-plane.
+func (c *KongClient) recordBrokenObjectEvents(broken brokenObjects) {
+	for _, obj := range broken.sorted() {
+		c.logger.WithFields(logrus.Fields{
+			"name":      obj.Name,
+			"namespace": obj.Namespace,
+			"kind":      obj.GroupVersionKind.Kind,
+		}).Errorf("configuration rejected by kong: %s", strings.Join(obj.errors, "; "))
+		c.eventRecorder.Event(obj.object(), corev1.EventTypeWarning, KongConfigurationApplyFailedEventReason,
+			fmt.Sprintf("invalid configuration excluded from the data-plane: %s", strings.Join(obj.errors, "; ")))
+	}
 }
 
 // -----------------------------------------------------------------------------
 // Dataplane Client - Kong - Private
 // -----------------------------------------------------------------------------
 
+// ErrTranslationTimeout is returned when the translation of Kubernetes objects
+// into Kong configuration didn't complete within the configured deadline.
+var ErrTranslationTimeout = errors.New("translation of kubernetes objects into kong configuration timed out")
+
+// kongStateBuilder is implemented by anything which can build a complete
+// KongState from Kubernetes objects (e.g. *parser.Parser).
+type kongStateBuilder interface {
+	BuildWithContext(ctx context.Context) (*kongstate.KongState, error)
+}
+
+// buildWithTimeout runs the provided builder and waits for it to produce a
+// complete KongState for at most the given timeout. If the deadline passes
+// first the build is cancelled and ErrTranslationTimeout is returned, so a
+// partially built state is never used by the caller.
+// A zero timeout waits for the build unconditionally.
+func buildWithTimeout(ctx context.Context, b kongStateBuilder, timeout time.Duration) (*kongstate.KongState, error) {
+	if timeout <= 0 {
+		return b.BuildWithContext(ctx)
+	}
+
+	// the build stops once abandoned rather than keep translating
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type buildResult struct {
+		state *kongstate.KongState
+		err   error
+	}
+
+	// the channel is buffered so that an abandoned build can always deliver
+	// its result and exit without blocking.
+	results := make(chan buildResult, 1)
+	go func() {
+		state, err := b.BuildWithContext(ctx)
+		results <- buildResult{state: state, err: err}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case res := <-results:
+		return res.state, res.err
+	case <-timer.C:
+		return nil, fmt.Errorf("%w after %s", ErrTranslationTimeout, timeout)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
 // triggerKubernetesObjectReport will update the KongClient with a set which
 // enables filtering for which objects are currently applied to the data-plane,
 // as well as updating the c.kubernetesObjectStatusQueue to queue those objects
@@ -411,11 +1470,13 @@ func (c *KongClient) triggerKubernetesObjectReport(objs ...client.Object) {
 	// first a new set of the included objects for the most recent configuration
 	// needs to be generated.
 	set := k8sobj.Set{}
+	generations := make(map[types.UID]int64, len(objs))
 	for _, obj := range objs {
 		set.Insert(obj)
+		generations[obj.GetUID()] = obj.GetGeneration()
 	}
 
-	c.updateKubernetesObjectReportFilter(set)
+	c.updateKubernetesObjectReportFilter(set, objs, generations)
 
 	// after the filter has been updated we signal the status queue so that the
 	// control-plane can update the Kubernetes object statuses for affected objs.
@@ -426,10 +1487,41 @@ func (c *KongClient) triggerKubernetesObjectReport(objs ...client.Object) {
 	}
 }
 
-// updateKubernetesObjectReportFilter overrides the internal object set with
-// a new provided set.
-func (c *KongClient) updateKubernetesObjectReportFilter(set k8sobj.Set) {
+// reportConfigDiff logs a summary of the entities changed by the most recent
+// configuration update and exports the same information as metrics so that
+// traffic anomalies can be correlated with configuration changes.
+func (c *KongClient) reportConfigDiff(summary deckgen.ConfigDiffSummary) {
+	c.logger.WithField("changes", summary.String()).Info("applied configuration changes to kong")
+	for entityType, changes := range summary {
+		for operation, count := range map[string]int{
+			metrics.OperationCreated: changes.Created,
+			metrics.OperationUpdated: changes.Updated,
+			metrics.OperationDeleted: changes.Deleted,
+		} {
+			c.prometheusMetrics.ConfigEntityChangeCount.With(prometheus.Labels{
+				metrics.EntityTypeKey: entityType,
+				metrics.OperationKey:  operation,
+			}).Add(float64(count))
+		}
+	}
+}
+
+// reportConfigHash exports the checksum of the configuration most recently
+// applied to the data-plane as a metric, so that it can be compared across
+// instances of the controller.
+func (c *KongClient) reportConfigHash(configSHA []byte) {
+	c.prometheusMetrics.ConfigHash.Reset()
+	c.prometheusMetrics.ConfigHash.With(prometheus.Labels{
+		metrics.HashKey: hex.EncodeToString(configSHA),
+	}).Set(1)
+}
+
+// updateKubernetesObjectReportFilter overrides the internal object set and
+// report with the newly provided ones.
+func (c *KongClient) updateKubernetesObjectReportFilter(set k8sobj.Set, objs []client.Object, generations map[types.UID]int64) {
 	c.kubernetesObjectReportLock.Lock()
 	defer c.kubernetesObjectReportLock.Unlock()
 	c.kubernetesObjectReportsFilter = set
+	c.kubernetesObjectReport = objs
+	c.kubernetesObjectReportGenerations = generations
 }
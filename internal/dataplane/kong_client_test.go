@@ -0,0 +1,320 @@
+package dataplane
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/sendconfig"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/metrics"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+	k8sobj "github.com/kong/kubernetes-ingress-controller/v2/internal/util/kubernetes/object"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util/kubernetes/object/status"
+	configurationv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
+)
+
+func TestBuildWithTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	t.Log("verifying that a build completing within the deadline returns its result")
+	state, err := buildWithTimeout(ctx, &fakeKongStateBuilder{}, time.Second)
+	require.NoError(t, err)
+	assert.NotNil(t, state)
+
+	t.Log("verifying that build errors are passed through to the caller")
+	buildErr := errors.New("build failed")
+	state, err = buildWithTimeout(ctx, &fakeKongStateBuilder{err: buildErr}, time.Second)
+	assert.ErrorIs(t, err, buildErr)
+	assert.Nil(t, state)
+
+	t.Log("verifying that a build exceeding the deadline is discarded and cancelled")
+	slow := &fakeKongStateBuilder{delay: time.Millisecond * 500, cancelled: make(chan struct{})}
+	state, err = buildWithTimeout(ctx, slow, time.Millisecond*10)
+	assert.ErrorIs(t, err, ErrTranslationTimeout)
+	assert.Nil(t, state)
+	select {
+	case <-slow.cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("the abandoned build wasn't cancelled")
+	}
+
+	t.Log("verifying that a zero timeout disables the deadline")
+	state, err = buildWithTimeout(ctx, &fakeKongStateBuilder{delay: time.Millisecond * 50}, 0)
+	require.NoError(t, err)
+	assert.NotNil(t, state)
+
+	t.Log("verifying that a cancelled context stops waiting for the build")
+	cancelledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	state, err = buildWithTimeout(cancelledCtx, &fakeKongStateBuilder{delay: time.Millisecond * 500}, time.Second)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, state)
+}
+
+// fakeKongStateBuilder fakes a parser so that the translation deadline
+// handling can be tested without any Kubernetes objects.
+type fakeKongStateBuilder struct {
+	delay time.Duration
+	err   error
+	// cancelled, if set, is closed when the build is cancelled
+	cancelled chan struct{}
+}
+
+func (f *fakeKongStateBuilder) BuildWithContext(ctx context.Context) (*kongstate.KongState, error) {
+	select {
+	case <-time.After(f.delay):
+	case <-ctx.Done():
+		if f.cancelled != nil {
+			close(f.cancelled)
+		}
+		return nil, ctx.Err()
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &kongstate.KongState{}, nil
+}
+
+func TestRequeueKubernetesObjectReport(t *testing.T) {
+	c := &KongClient{}
+	queue := status.NewQueue()
+	ingressGVK := netv1.SchemeGroupVersion.WithKind("Ingress")
+	tcpIngressGVK := configurationv1beta1.SchemeGroupVersion.WithKind("TCPIngress")
+
+	ingress := &netv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "ingress", Namespace: "default"}}
+	ingress.SetGroupVersionKind(ingressGVK)
+	tcpIngress := &configurationv1beta1.TCPIngress{ObjectMeta: metav1.ObjectMeta{Name: "tcpingress", Namespace: "default"}}
+	tcpIngress.SetGroupVersionKind(tcpIngressGVK)
+
+	t.Log("verifying that requeueing is a no-op when object reports are disabled")
+	c.RequeueKubernetesObjectReport()
+
+	c.EnableKubernetesObjectReports(queue)
+	c.triggerKubernetesObjectReport(ingress, tcpIngress)
+	ingressEvents, tcpIngressEvents := queue.Subscribe(ingressGVK), queue.Subscribe(tcpIngressGVK)
+	require.Len(t, ingressEvents, 1)
+	require.Len(t, tcpIngressEvents, 1)
+	<-ingressEvents
+	<-tcpIngressEvents
+
+	t.Log("verifying that all the objects from the last report are queued again")
+	c.RequeueKubernetesObjectReport()
+	require.Len(t, ingressEvents, 1)
+	require.Len(t, tcpIngressEvents, 1)
+	assert.Equal(t, ingress, (<-ingressEvents).Object)
+	assert.Equal(t, tcpIngress, (<-tcpIngressEvents).Object)
+}
+
+func TestIsStandby(t *testing.T) {
+	c := &KongClient{}
+	assert.False(t, c.IsStandby(), "clients apply configurations by default")
+
+	elected := make(chan struct{})
+	c.EnableStandby(elected)
+	assert.True(t, c.IsStandby(), "clients only build configurations until elected")
+
+	close(elected)
+	assert.False(t, c.IsStandby(), "clients apply configurations once elected")
+}
+
+func TestReportFeatures(t *testing.T) {
+	reports := make(chan util.FeatureReport, 1)
+	c := &KongClient{
+		logger:            logrus.New(),
+		dbmode:            "off",
+		prometheusMetrics: metrics.NewCtrlFuncMetrics(),
+	}
+	c.SetRouterFlavor("traditional_compatible")
+	c.SetFeatureReports(reports)
+	c.EnableCombinedServiceRoutes()
+
+	report := c.FeatureReport()
+	assert.Equal(t, util.FeatureReport{
+		CombinedServiceRoutes: true,
+		RouterFlavor:          "traditional_compatible",
+		DBMode:                "off",
+	}, report)
+
+	c.reportFeatures(report)
+	require.Len(t, reports, 1, "the features of the first update are reported")
+	assert.Equal(t, report, <-reports)
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.prometheusMetrics.FeatureEnabled.With(prometheus.Labels{
+		metrics.FeatureKey: "combined_service_routes",
+	})))
+	assert.Equal(t, 0.0, testutil.ToFloat64(c.prometheusMetrics.FeatureEnabled.With(prometheus.Labels{
+		metrics.FeatureKey: "endpoint_slice_targets",
+	})))
+
+	c.reportFeatures(c.FeatureReport())
+	assert.Empty(t, reports, "unchanged features aren't reported again")
+
+	c.EnableEndpointSliceTargets()
+	c.reportFeatures(c.FeatureReport())
+	require.Len(t, reports, 1, "changed features are reported")
+	assert.True(t, (<-reports).EndpointSliceTargets)
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.prometheusMetrics.FeatureEnabled.With(prometheus.Labels{
+		metrics.FeatureKey: "endpoint_slice_targets",
+	})))
+}
+
+func TestReportConfigHash(t *testing.T) {
+	c := &KongClient{
+		logger:            logrus.New(),
+		prometheusMetrics: metrics.NewCtrlFuncMetrics(),
+	}
+
+	c.reportConfigHash([]byte{0xab, 0xcd})
+	c.reportConfigHash([]byte{0x12, 0x34})
+	assert.Equal(t, 1, testutil.CollectAndCount(c.prometheusMetrics.ConfigHash), "only the latest checksum is exported")
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.prometheusMetrics.ConfigHash.With(prometheus.Labels{
+		metrics.HashKey: "1234",
+	})))
+}
+
+func TestReportPropagation(t *testing.T) {
+	ingress := func(uid, resourceVersion string) *netv1.Ingress {
+		return &netv1.Ingress{ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "default",
+			Name:            uid,
+			UID:             types.UID(uid),
+			ResourceVersion: resourceVersion,
+		}}
+	}
+	recorder := record.NewFakeRecorder(10)
+	c := &KongClient{
+		logger:            logrus.New(),
+		prometheusMetrics: metrics.NewCtrlFuncMetrics(),
+		eventRecorder:     recorder,
+	}
+	c.EnablePropagationEvents()
+	start := time.Now()
+	c.propagation.observe(ingress("applied", "1"), start.Add(-time.Second))
+	c.propagation.observe(ingress("changed", "1"), start)
+	c.propagation.observe(ingress("broken", "1"), start)
+	changes := c.propagation.pendingChanges()
+	require.Len(t, changes, 3)
+
+	t.Log("changes observed during the update are left for the next one")
+	c.propagation.observe(ingress("changed", "2"), start)
+
+	c.reportPropagation(changes, brokenObjects{"broken": &brokenObject{
+		K8sObjectInfo: util.K8sObjectInfo{UID: "broken"},
+	}})
+	assert.Equal(t, 1, testutil.CollectAndCount(c.prometheusMetrics.ConfigPropagationDuration),
+		"the propagation of the Ingress is measured")
+	require.Len(t, recorder.Events, 1, "only the change which is still the latest one is reported")
+	assert.Contains(t, <-recorder.Events, "Normal KongConfigurationApplied configuration applied to the data-plane 1")
+
+	pending := make(map[string]string)
+	for _, change := range c.propagation.pendingChanges() {
+		pending[change.obj.GetName()] = change.obj.GetResourceVersion()
+	}
+	assert.Equal(t, map[string]string{"changed": "2", "broken": "1"}, pending)
+
+	t.Log("resyncs of propagated versions are not changes")
+	c.propagation.observe(ingress("applied", "1"), time.Now())
+	c.propagation.forget(ingress("broken", "1"))
+	assert.Len(t, c.propagation.pendingChanges(), 1)
+}
+
+func TestKubernetesObjectGenerationIsConfigured(t *testing.T) {
+	c := &KongClient{kubernetesObjectStatusQueue: status.NewQueue()}
+	ingress := &netv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "ingress", Namespace: "default", UID: "1", Generation: 2}}
+	assert.False(t, c.KubernetesObjectGenerationIsConfigured(ingress), "objects not in the last update aren't configured")
+
+	c.triggerKubernetesObjectReport(ingress.DeepCopy())
+	assert.True(t, c.KubernetesObjectGenerationIsConfigured(ingress))
+
+	t.Log("verifying that a newer generation of the object isn't configured until an update includes it")
+	ingress.Generation = 3
+	assert.False(t, c.KubernetesObjectGenerationIsConfigured(ingress))
+}
+
+func TestRequeueKubernetesObjectReportFullQueue(t *testing.T) {
+	c := &KongClient{}
+	c.EnableKubernetesObjectReports(status.NewQueue())
+	// one more object than the queue can hold, so that requeueing blocks
+	objs := make([]client.Object, 8193)
+	for i := range objs {
+		objs[i] = &netv1.Ingress{
+			TypeMeta: metav1.TypeMeta{Kind: "Ingress", APIVersion: netv1.SchemeGroupVersion.String()},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: fmt.Sprintf("ingress-%d", i), Namespace: "default", UID: types.UID(fmt.Sprint(i)),
+			},
+		}
+	}
+	c.updateKubernetesObjectReportFilter(k8sobj.Set{}, objs, nil)
+
+	queued := c.kubernetesObjectStatusQueue.Subscribe(netv1.SchemeGroupVersion.WithKind("Ingress"))
+	requeued := make(chan struct{})
+	go func() {
+		c.RequeueKubernetesObjectReport()
+		close(requeued)
+	}()
+	require.Eventually(t, func() bool { return len(queued) == cap(queued) }, 10*time.Second, time.Millisecond)
+
+	t.Log("verifying that the report can be read and updated while requeueing is blocked")
+	updated := make(chan struct{})
+	go func() {
+		c.KubernetesObjectIsConfigured(objs[0])
+		c.triggerKubernetesObjectReport()
+		close(updated)
+	}()
+	select {
+	case <-updated:
+	case <-time.After(10 * time.Second):
+		t.Fatal("the report is locked while requeueing")
+	}
+
+	for range objs {
+		<-queued
+	}
+	<-requeued
+}
+
+func TestKongRoutesExist(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/routes/default.foo.00":
+			_, _ = w.Write([]byte(`{"id":"2cd9df46-7f4c-4b0b-a43c-52ff4f4e0d4b","name":"default.foo.00"}`))
+		case "/routes/default.foo.01":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not found"}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+	kongClient, err := kong.NewClient(kong.String(server.URL), server.Client())
+	require.NoError(t, err)
+	c := &KongClient{kongConfig: sendconfig.Kong{Client: kongClient}, requestTimeout: time.Second}
+	ctx := context.Background()
+
+	live, err := c.KongRoutesExist(ctx, "default.foo.00")
+	require.NoError(t, err)
+	assert.True(t, live)
+
+	live, err = c.KongRoutesExist(ctx, "default.foo.00", "default.foo.01")
+	require.NoError(t, err)
+	assert.False(t, live, "all the routes must be live")
+
+	_, err = c.KongRoutesExist(ctx, "default.bar.00")
+	assert.Error(t, err, "failures to probe the routes are returned")
+}
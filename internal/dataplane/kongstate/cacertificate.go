@@ -0,0 +1,73 @@
+package kongstate
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/kong/go-kong/kong"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
+	configurationv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
+)
+
+// DefaultKongCACertificateKey is the key of the Secret data a KongCACertificate
+// reads its CA bundle from unless it sets one.
+const DefaultKongCACertificateKey = "ca.crt"
+
+// kongCACertificateIDNamespace is the namespace of the IDs generated for the
+// CA certificates translated from KongCACertificates.
+var kongCACertificateIDNamespace = uuid.MustParse("2a4d1d4f-5e0c-4b5e-9d6a-1f0f6f5c9a3e")
+
+// CACertificatesForKongCACertificate translates a KongCACertificate into a Kong
+// CA certificate for each certificate of the bundle stored in the Secret it
+// references. IDs are derived from the name of the KongCACertificate and the
+// position of the certificate in the bundle, so they stay the same across
+// translations and can be referenced by services.
+func CACertificatesForKongCACertificate(
+	s store.Storer,
+	caCert *configurationv1beta1.KongCACertificate,
+) ([]kong.CACertificate, error) {
+	ref := caCert.Spec.SecretRef
+	secret, err := s.GetSecret(ref.Namespace, ref.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch secret %s/%s: %w", ref.Namespace, ref.Name, err)
+	}
+	key := ref.Key
+	if key == "" {
+		key = DefaultKongCACertificateKey
+	}
+	bundle, ok := secret.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("secret %s/%s has no %q key", ref.Namespace, ref.Name, key)
+	}
+
+	var caCerts []kong.CACertificate
+	for rest := bundle; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		x509Cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate %d of the bundle: %w", len(caCerts), err)
+		}
+		if !x509Cert.IsCA {
+			return nil, fmt.Errorf("certificate %d of the bundle is missing the 'CA' basic constraint", len(caCerts))
+		}
+		id := uuid.NewSHA1(kongCACertificateIDNamespace, []byte(fmt.Sprintf("%s/%d", caCert.Name, len(caCerts))))
+		caCerts = append(caCerts, kong.CACertificate{
+			ID:   kong.String(id.String()),
+			Cert: kong.String(string(pem.EncodeToMemory(block))),
+		})
+	}
+	if len(caCerts) == 0 {
+		return nil, fmt.Errorf("secret %s/%s contains no PEM encoded certificates under the %q key", ref.Namespace, ref.Name, key)
+	}
+	return caCerts, nil
+}
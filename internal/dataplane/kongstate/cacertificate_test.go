@@ -0,0 +1,111 @@
+package kongstate
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
+	configurationv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
+)
+
+func generatePEMCertificate(t *testing.T, isCA bool) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "kong"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestCACertificatesForKongCACertificate(t *testing.T) {
+	ca1 := generatePEMCertificate(t, true)
+	ca2 := generatePEMCertificate(t, true)
+	leaf := generatePEMCertificate(t, false)
+
+	s, err := store.NewFakeStore(store.FakeObjects{
+		Secrets: []*corev1.Secret{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "bundle", Namespace: "default"},
+				Data: map[string][]byte{
+					"ca.crt":  []byte(ca1 + ca2),
+					"custom":  []byte(ca2),
+					"leaf":    []byte(ca1 + leaf),
+					"garbage": []byte("not a certificate"),
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	kongCACert := func(name, secretName, key string) *configurationv1beta1.KongCACertificate {
+		return &configurationv1beta1.KongCACertificate{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: configurationv1beta1.KongCACertificateSpec{
+				SecretRef: configurationv1beta1.KongCACertificateSecretRef{
+					Namespace: "default",
+					Name:      secretName,
+					Key:       key,
+				},
+			},
+		}
+	}
+
+	t.Run("bundle under the default key", func(t *testing.T) {
+		caCerts, err := CACertificatesForKongCACertificate(s, kongCACert("foo", "bundle", ""))
+		require.NoError(t, err)
+		require.Len(t, caCerts, 2)
+		assert.Equal(t, ca1, *caCerts[0].Cert)
+		assert.Equal(t, ca2, *caCerts[1].Cert)
+		assert.NotEqual(t, *caCerts[0].ID, *caCerts[1].ID)
+
+		again, err := CACertificatesForKongCACertificate(s, kongCACert("foo", "bundle", ""))
+		require.NoError(t, err)
+		assert.Equal(t, caCerts, again, "IDs must be stable across translations")
+
+		other, err := CACertificatesForKongCACertificate(s, kongCACert("bar", "bundle", ""))
+		require.NoError(t, err)
+		assert.NotEqual(t, *caCerts[0].ID, *other[0].ID)
+	})
+
+	t.Run("custom key", func(t *testing.T) {
+		caCerts, err := CACertificatesForKongCACertificate(s, kongCACert("foo", "bundle", "custom"))
+		require.NoError(t, err)
+		require.Len(t, caCerts, 1)
+		assert.Equal(t, ca2, *caCerts[0].Cert)
+	})
+
+	for _, tt := range []struct {
+		name       string
+		secretName string
+		key        string
+	}{
+		{name: "missing secret", secretName: "missing"},
+		{name: "missing key", secretName: "bundle", key: "missing"},
+		{name: "no certificates", secretName: "bundle", key: "garbage"},
+		{name: "certificate which isn't a CA", secretName: "bundle", key: "leaf"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CACertificatesForKongCACertificate(s, kongCACert("foo", tt.secretName, tt.key))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -2,17 +2,21 @@ package kongstate
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/blang/semver/v4"
 	"github.com/kong/go-kong/kong"
 	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/validation/consumers/credentials"
+	configurationv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
 )
 
 // KongState holds the configuration that should be applied to Kong.
@@ -23,6 +27,7 @@ type KongState struct {
 	CACertificates []kong.CACertificate
 	Plugins        []Plugin
 	Consumers      []Consumer
+	Vaults         []Vault
 	Version        semver.Version
 }
 
@@ -45,6 +50,13 @@ func (ks *KongState) SanitizedCopy() *KongState {
 			}
 			return
 		}(),
+		Vaults: func() (res []Vault) {
+			for _, v := range ks.Vaults {
+				res = append(res, *v.SanitizedCopy())
+			}
+			return
+		}(),
+		Version: ks.Version,
 	}
 }
 
@@ -116,6 +128,10 @@ func (ks *KongState) FillConsumersAndCredentials(log logrus.FieldLogger, s store
 				log.Error("failed to provision credential: empty secret")
 				continue
 			}
+			if err := ks.validateVaultReferences(credConfig); err != nil {
+				log.WithError(err).Error("failed to provision credential")
+				continue
+			}
 			err = c.SetCredential(credType, credConfig)
 			if err != nil {
 				log.WithError(err).Errorf("failed to provision credential")
@@ -144,8 +160,17 @@ func (ks *KongState) FillOverrides(log logrus.FieldLogger, s store.Storer) {
 			continue
 		}
 
+		// a missing policy only leaves the service with its default connection settings
+		policy, err := getKongUpstreamPolicyForServices(s, ks.Services[i].K8sServices)
+		if err != nil {
+			log.WithError(err).
+				Errorf("failed to fetch KongUpstreamPolicy resource for Services %s",
+					PrettyPrintServiceList(ks.Services[i].K8sServices),
+				)
+		}
+
 		for _, svc := range ks.Services[i].K8sServices {
-			ks.Services[i].override(log, kongIngress, svc)
+			ks.Services[i].override(log, kongIngress, policy, svc)
 		}
 
 		// Routes
@@ -160,6 +185,10 @@ func (ks *KongState) FillOverrides(log logrus.FieldLogger, s store.Storer) {
 
 			ks.Services[i].Routes[j].override(log, kongIngress)
 		}
+		ks.Services[i].removeDisabledRoutes()
+		ks.Services[i].overrideTimeoutsByRoutes()
+
+		ks.Services[i].overrideProtocolForGRPCWeb()
 	}
 
 	// Upstreams
@@ -173,8 +202,17 @@ func (ks *KongState) FillOverrides(log logrus.FieldLogger, s store.Storer) {
 			continue
 		}
 
+		// a missing policy only leaves the upstream with its default load balancing
+		policy, err := getKongUpstreamPolicyForServices(s, ks.Upstreams[i].Service.K8sServices)
+		if err != nil {
+			log.WithError(err).
+				Errorf("failed to fetch KongUpstreamPolicy resource for Services %s",
+					PrettyPrintServiceList(ks.Upstreams[i].Service.K8sServices),
+				)
+		}
+
 		for _, svc := range ks.Upstreams[i].Service.K8sServices {
-			ks.Upstreams[i].override(log, kongIngress, svc)
+			ks.Upstreams[i].override(log, kongIngress, policy, svc)
 		}
 	}
 }
@@ -229,6 +267,9 @@ func (ks *KongState) getPluginRelations() map[string]util.ForeignRelations {
 	}
 	// consumer
 	for _, c := range ks.Consumers {
+		if c.Username == nil {
+			continue
+		}
 		pluginList := annotations.ExtractKongPluginsFromAnnotations(c.K8sKongConsumer.GetAnnotations())
 		for _, pluginName := range pluginList {
 			addConsumerRelation(c.K8sKongConsumer.Namespace, pluginName, *c.Username)
@@ -237,13 +278,191 @@ func (ks *KongState) getPluginRelations() map[string]util.ForeignRelations {
 	return pluginRels
 }
 
-func buildPlugins(log logrus.FieldLogger, s store.Storer, pluginRels map[string]util.ForeignRelations) []Plugin {
+// addSelectorRelations attaches the KongClusterPlugins with a route selector to
+// the routes translated from objects whose labels match it, and those with a
+// service selector to the services translated from Kubernetes Services whose
+// labels match it. The relations are keyed with an empty namespace, which
+// resolves to the KongClusterPlugin. Objects listing the plugin in their
+// plugins annotation already have it attached and are skipped.
+func (ks *KongState) addSelectorRelations(
+	log logrus.FieldLogger,
+	s store.Storer,
+	pluginRels map[string]util.ForeignRelations,
+) {
+	clusterPlugins, err := s.ListKongClusterPlugins()
+	if err != nil {
+		log.WithError(err).Error("failed to list KongClusterPlugins")
+		return
+	}
+	for _, clusterPlugin := range clusterPlugins {
+		pluginKey := ":" + clusterPlugin.Name
+		if clusterPlugin.RouteSelector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(clusterPlugin.RouteSelector)
+			if err != nil {
+				log.WithField("kongclusterplugin_name", clusterPlugin.Name).WithError(err).
+					Error("invalid route selector, the plugin is not attached to any route by label")
+			} else if names := ks.selectRoutes(selector, clusterPlugin.Name); len(names) > 0 {
+				relations := pluginRels[pluginKey]
+				relations.Route = append(relations.Route, names...)
+				pluginRels[pluginKey] = relations
+			}
+		}
+		if clusterPlugin.ServiceSelector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(clusterPlugin.ServiceSelector)
+			if err != nil {
+				log.WithField("kongclusterplugin_name", clusterPlugin.Name).WithError(err).
+					Error("invalid service selector, the plugin is not attached to any service by label")
+			} else if names := ks.selectServices(selector, clusterPlugin.Name); len(names) > 0 {
+				relations := pluginRels[pluginKey]
+				relations.Service = append(relations.Service, names...)
+				pluginRels[pluginKey] = relations
+			}
+		}
+	}
+}
+
+// selectRoutes provides the names of the routes translated from objects whose
+// labels match the selector of the named KongClusterPlugin, except those
+// listing the plugin in their plugins annotation.
+func (ks *KongState) selectRoutes(selector labels.Selector, pluginName string) []string {
+	var names []string
+	for i := range ks.Services {
+		for _, route := range ks.Services[i].Routes {
+			if selector.Matches(labels.Set(route.Ingress.Labels)) &&
+				!annotatedWithPlugin(route.Ingress.Annotations, pluginName) {
+				names = append(names, *route.Name)
+			}
+		}
+	}
+	return names
+}
+
+// selectServices provides the names of the services translated from a
+// Kubernetes Service whose labels match the selector of the named
+// KongClusterPlugin, except those translated from a Service listing the plugin
+// in its plugins annotation.
+func (ks *KongState) selectServices(selector labels.Selector, pluginName string) []string {
+	var names []string
+	for _, service := range ks.Services {
+		selected := false
+		for _, svc := range service.K8sServices {
+			if annotatedWithPlugin(svc.Annotations, pluginName) {
+				selected = false
+				break
+			}
+			selected = selected || selector.Matches(labels.Set(svc.Labels))
+		}
+		if selected {
+			names = append(names, *service.Name)
+		}
+	}
+	return names
+}
+
+// annotatedWithPlugin indicates whether the plugins annotation lists the named
+// plugin.
+func annotatedWithPlugin(anns map[string]string, pluginName string) bool {
+	for _, name := range annotations.ExtractKongPluginsFromAnnotations(anns) {
+		if name == pluginName {
+			return true
+		}
+	}
+	return false
+}
+
+// addConsumerGroupRelations attaches the KongClusterPlugins annotated with
+// consumer groups to the consumers belonging to any of them, e.g. to give each
+// tier of consumers its own rate limits. A consumer only gets one plugin of
+// each kind: the plugins listed in its own plugins annotation override those of
+// its groups, and the groups it lists first override the following ones.
+func (ks *KongState) addConsumerGroupRelations(
+	log logrus.FieldLogger,
+	s store.Storer,
+	pluginRels map[string]util.ForeignRelations,
+) {
+	clusterPlugins, err := s.ListKongClusterPlugins()
+	if err != nil {
+		log.WithError(err).Error("failed to list KongClusterPlugins")
+		return
+	}
+	groupPlugins := make(map[string][]*configurationv1.KongClusterPlugin)
+	for _, clusterPlugin := range clusterPlugins {
+		for _, group := range annotations.ExtractConsumerGroups(clusterPlugin.Annotations) {
+			groupPlugins[group] = append(groupPlugins[group], clusterPlugin)
+		}
+	}
+	if len(groupPlugins) == 0 {
+		return
+	}
+	for _, plugins := range groupPlugins {
+		sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	}
+
+	for _, c := range ks.Consumers {
+		consumerAnns := c.K8sKongConsumer.GetAnnotations()
+		groups := annotations.ExtractConsumerGroups(consumerAnns)
+		if len(groups) == 0 {
+			continue
+		}
+		// plugins refer to consumers by username, the consumers which only
+		// have a custom_id aren't configured
+		if c.Username == nil {
+			log.WithFields(logrus.Fields{
+				"kongconsumer_name":      c.K8sKongConsumer.Name,
+				"kongconsumer_namespace": c.K8sKongConsumer.Namespace,
+			}).Error("consumer groups ignored: the consumer has no username")
+			continue
+		}
+		attached := make(map[string]bool)
+		for _, name := range annotations.ExtractKongPluginsFromAnnotations(consumerAnns) {
+			attached[pluginKind(s, c.K8sKongConsumer.Namespace, name)] = true
+		}
+		for _, group := range groups {
+			for _, clusterPlugin := range groupPlugins[group] {
+				if attached[clusterPlugin.PluginName] {
+					log.WithFields(logrus.Fields{
+						"kongconsumer_name":      c.K8sKongConsumer.Name,
+						"kongconsumer_namespace": c.K8sKongConsumer.Namespace,
+						"kongclusterplugin_name": clusterPlugin.Name,
+						"consumer_group":         group,
+					}).Debugf("%s plugin of the consumer group overridden", clusterPlugin.PluginName)
+					continue
+				}
+				attached[clusterPlugin.PluginName] = true
+				pluginKey := ":" + clusterPlugin.Name
+				relations := pluginRels[pluginKey]
+				relations.Consumer = append(relations.Consumer, *c.Username)
+				pluginRels[pluginKey] = relations
+			}
+		}
+	}
+}
+
+// pluginKind provides the name of the Kong plugin configured by the KongPlugin
+// or KongClusterPlugin a plugins annotation in the namespace refers to, empty
+// if there is none.
+func pluginKind(s store.Storer, namespace, name string) string {
+	if plugin, err := s.GetKongPlugin(namespace, name); err == nil {
+		return plugin.PluginName
+	}
+	if clusterPlugin, err := s.GetKongClusterPlugin(name); err == nil {
+		return clusterPlugin.PluginName
+	}
+	return ""
+}
+
+func buildPlugins(
+	log logrus.FieldLogger,
+	s store.Storer,
+	pluginRels map[string]util.ForeignRelations,
+	secretNamespaces []string,
+) ([]Plugin, []ClusterPluginFailure) {
 	var plugins []Plugin
 
 	for pluginIdentifier, relations := range pluginRels {
 		identifier := strings.Split(pluginIdentifier, ":")
 		namespace, kongPluginName := identifier[0], identifier[1]
-		plugin, err := getPlugin(s, namespace, kongPluginName)
+		plugin, err := getPlugin(s, namespace, kongPluginName, secretNamespaces)
 		if err != nil {
 			log.WithFields(logrus.Fields{
 				"kongplugin_name":      kongPluginName,
@@ -253,37 +472,54 @@ func buildPlugins(log logrus.FieldLogger, s store.Storer, pluginRels map[string]
 		}
 
 		for _, rel := range relations.GetCombinations() {
-			plugin := *plugin.DeepCopy()
+			kongPlugin := *plugin.Plugin.DeepCopy()
 			// ID is populated because that is read by decK and in_memory
 			// translator too
 			if rel.Service != "" {
-				plugin.Service = &kong.Service{ID: kong.String(rel.Service)}
+				kongPlugin.Service = &kong.Service{ID: kong.String(rel.Service)}
 			}
 			if rel.Route != "" {
-				plugin.Route = &kong.Route{ID: kong.String(rel.Route)}
+				kongPlugin.Route = &kong.Route{ID: kong.String(rel.Route)}
 			}
 			if rel.Consumer != "" {
-				plugin.Consumer = &kong.Consumer{ID: kong.String(rel.Consumer)}
+				kongPlugin.Consumer = &kong.Consumer{ID: kong.String(rel.Consumer)}
 			}
-			plugins = append(plugins, Plugin{plugin})
+			plugins = append(plugins, Plugin{
+				Plugin:        kongPlugin,
+				K8sNamespace:  namespace,
+				K8sName:       kongPluginName,
+				ClusterPlugin: plugin.ClusterPlugin,
+				Ordering:      plugin.Ordering,
+			})
 		}
 	}
 
-	globalPlugins, err := globalPlugins(log, s)
+	globalPlugins, failures, err := globalPlugins(log, s, secretNamespaces)
 	if err != nil {
 		log.WithError(err).Error("failed to fetch global plugins")
 	}
 	plugins = append(plugins, globalPlugins...)
 
-	return plugins
+	return plugins, failures
+}
+
+// ClusterPluginFailure describes a problem which prevented a global
+// KongClusterPlugin from being translated into a plugin.
+type ClusterPluginFailure struct {
+	ClusterPlugin *configurationv1.KongClusterPlugin
+	Message       string
 }
 
-func globalPlugins(log logrus.FieldLogger, s store.Storer) ([]Plugin, error) {
+func globalPlugins(
+	log logrus.FieldLogger,
+	s store.Storer,
+	secretNamespaces []string,
+) ([]Plugin, []ClusterPluginFailure, error) {
 	// removed as of 0.10.0
 	// only retrieved now to warn users
 	globalPlugins, err := s.ListGlobalKongPlugins()
 	if err != nil {
-		return nil, fmt.Errorf("error listing global KongPlugins: %w", err)
+		return nil, nil, fmt.Errorf("error listing global KongPlugins: %w", err)
 	}
 	if len(globalPlugins) > 0 {
 		log.Warning("global KongPlugins found. These are no longer applied and",
@@ -291,7 +527,9 @@ func globalPlugins(log logrus.FieldLogger, s store.Storer) ([]Plugin, error) {
 			" Please run \"kubectl get kongplugin -l global=true --all-namespaces\" to list existing plugins")
 	}
 	res := make(map[string]Plugin)
-	var duplicates []string // keep track of duplicate
+	// KongClusterPlugins configuring each plugin, to report duplicates
+	definitions := make(map[string][]*configurationv1.KongClusterPlugin)
+	var failures []ClusterPluginFailure
 	// TODO respect the oldest CRD
 	// Current behavior is to skip creating the plugin but in case
 	// of duplicate plugin definitions, we should respect the oldest one
@@ -300,7 +538,7 @@ func globalPlugins(log logrus.FieldLogger, s store.Storer) ([]Plugin, error) {
 
 	globalClusterPlugins, err := s.ListGlobalKongClusterPlugins()
 	if err != nil {
-		return nil, fmt.Errorf("error listing global KongClusterPlugins: %w", err)
+		return nil, nil, fmt.Errorf("error listing global KongClusterPlugins: %w", err)
 	}
 	for i := 0; i < len(globalClusterPlugins); i++ {
 		k8sPlugin := *globalClusterPlugins[i]
@@ -310,35 +548,197 @@ func globalPlugins(log logrus.FieldLogger, s store.Storer) ([]Plugin, error) {
 			log.WithFields(logrus.Fields{
 				"kongclusterplugin_name": k8sPlugin.Name,
 			}).Errorf("invalid KongClusterPlugin: empty plugin property")
+			failures = append(failures, ClusterPluginFailure{
+				ClusterPlugin: globalClusterPlugins[i],
+				Message:       "invalid KongClusterPlugin: empty plugin property",
+			})
 			continue
 		}
-		if _, ok := res[pluginName]; ok {
+		definitions[pluginName] = append(definitions[pluginName], globalClusterPlugins[i])
+		if len(definitions[pluginName]) > 1 {
 			log.Error("multiple KongPlugin definitions found with"+
 				" 'global' label for '", pluginName,
 				"', the plugin will not be applied")
-			duplicates = append(duplicates, pluginName)
 			continue
 		}
-		if plugin, err := kongPluginFromK8SClusterPlugin(s, k8sPlugin); err == nil {
+		if plugin, err := kongPluginFromK8SClusterPlugin(s, k8sPlugin, secretNamespaces); err == nil {
 			res[pluginName] = Plugin{
-				Plugin: plugin,
+				Plugin:        plugin,
+				K8sName:       k8sPlugin.Name,
+				ClusterPlugin: true,
+				Ordering:      k8sPlugin.Ordering,
 			}
 		} else {
 			log.WithFields(logrus.Fields{
 				"kongclusterplugin_name": k8sPlugin.Name,
 			}).WithError(err).Error("failed to generate configuration from KongClusterPlugin")
+			failures = append(failures, ClusterPluginFailure{
+				ClusterPlugin: globalClusterPlugins[i],
+				Message:       fmt.Sprintf("failed to generate configuration: %v", err),
+			})
 		}
 	}
-	for _, plugin := range duplicates {
-		delete(res, plugin)
+	for _, k8sPlugin := range globalClusterPlugins {
+		pluginName := k8sPlugin.PluginName
+		if len(definitions[pluginName]) < 2 {
+			continue
+		}
+		delete(res, pluginName)
+		failures = append(failures, ClusterPluginFailure{
+			ClusterPlugin: k8sPlugin,
+			Message: fmt.Sprintf("multiple KongClusterPlugins with the 'global' label "+
+				"configure plugin %s, none of them is applied", pluginName),
+		})
 	}
 	var plugins []Plugin
 	for _, p := range res {
 		plugins = append(plugins, p)
 	}
+	return plugins, failures, nil
+}
+
+// bundledPlugins expands the KongPluginBundles into plugins attached to the
+// routes of the namespaces they target. A bundle is only expanded if all its
+// KongClusterPlugins are valid, so that it's never partially applied. Plugins
+// already attached to a route take precedence over bundled ones, and bundles
+// are expanded in order of their names.
+func (ks *KongState) bundledPlugins(
+	log logrus.FieldLogger,
+	s store.Storer,
+	plugins []Plugin,
+	secretNamespaces []string,
+) []Plugin {
+	bundles, err := s.ListKongPluginBundles()
+	if err != nil {
+		log.WithError(err).Error("failed to list KongPluginBundles")
+		return nil
+	}
+
+	// names of the plugins attached to each route
+	routePlugins := make(map[string]map[string]struct{})
+	attach := func(route, plugin string) bool {
+		if _, ok := routePlugins[route][plugin]; ok {
+			return false
+		}
+		if routePlugins[route] == nil {
+			routePlugins[route] = make(map[string]struct{})
+		}
+		routePlugins[route][plugin] = struct{}{}
+		return true
+	}
+	for _, plugin := range plugins {
+		if plugin.Route != nil && plugin.Route.ID != nil && plugin.Name != nil {
+			attach(*plugin.Route.ID, *plugin.Name)
+		}
+	}
+
+	var res []Plugin
+	for _, bundle := range bundles {
+		log := log.WithFields(logrus.Fields{
+			"kongpluginbundle_name":    bundle.Name,
+			"kongpluginbundle_version": bundle.Spec.Version,
+		})
+		bundled, err := getBundledPlugins(s, bundle.Spec.Plugins, secretNamespaces)
+		if err != nil {
+			log.WithError(err).Error("failed to expand KongPluginBundle")
+			continue
+		}
+
+		namespaces := make(map[string]struct{}, len(bundle.Spec.Namespaces))
+		for _, namespace := range bundle.Spec.Namespaces {
+			namespaces[namespace] = struct{}{}
+		}
+		for _, service := range ks.Services {
+			for _, route := range service.Routes {
+				if route.Name == nil {
+					continue
+				}
+				if _, ok := namespaces[route.Ingress.Namespace]; len(namespaces) > 0 && !ok {
+					continue
+				}
+				for i, plugin := range bundled {
+					if !attach(*route.Name, *plugin.Name) {
+						log.WithField("route_name", *route.Name).Debugf(
+							"plugin %s is already attached to the route, skipping bundled one", *plugin.Name)
+						continue
+					}
+					kongPlugin := *plugin.Plugin.DeepCopy()
+					kongPlugin.Route = &kong.Route{ID: kong.String(*route.Name)}
+					res = append(res, Plugin{
+						Plugin:        kongPlugin,
+						K8sName:       bundle.Spec.Plugins[i],
+						ClusterPlugin: true,
+						Ordering:      plugin.Ordering,
+					})
+				}
+			}
+		}
+	}
+	return res
+}
+
+// getBundledPlugins provides the plugins for the KongClusterPlugins of a bundle.
+func getBundledPlugins(s store.Storer, names []string, secretNamespaces []string) ([]Plugin, error) {
+	plugins := make([]Plugin, 0, len(names))
+	for _, name := range names {
+		k8sPlugin, err := s.GetKongClusterPlugin(name)
+		if err != nil {
+			return nil, err
+		}
+		if k8sPlugin.PluginName == "" {
+			return nil, fmt.Errorf("invalid empty 'plugin' property in KongClusterPlugin %v", name)
+		}
+		plugin, err := kongPluginFromK8SClusterPlugin(s, *k8sPlugin, secretNamespaces)
+		if err != nil {
+			return nil, err
+		}
+		plugins = append(plugins, Plugin{Plugin: plugin, Ordering: k8sPlugin.Ordering})
+	}
 	return plugins, nil
 }
 
-func (ks *KongState) FillPlugins(log logrus.FieldLogger, s store.Storer) {
-	ks.Plugins = buildPlugins(log, s, ks.getPluginRelations())
+// FillPlugins translates the KongPlugins and KongClusterPlugins attached to
+// the entities of the state, the global KongClusterPlugins and the
+// KongPluginBundles into plugins. KongClusterPlugins may only reference Secrets
+// in secretNamespaces, or in any namespace if it's empty. It returns the
+// problems which prevented global KongClusterPlugins from being applied.
+func (ks *KongState) FillPlugins(
+	log logrus.FieldLogger,
+	s store.Storer,
+	secretNamespaces []string,
+) []ClusterPluginFailure {
+	pluginRels := ks.getPluginRelations()
+	ks.addSelectorRelations(log, s, pluginRels)
+	ks.addConsumerGroupRelations(log, s, pluginRels)
+	plugins, failures := buildPlugins(log, s, pluginRels, secretNamespaces)
+	ks.Plugins = plugins
+	ks.Plugins = append(ks.Plugins, ks.bundledPlugins(log, s, ks.Plugins, secretNamespaces)...)
+	ks.dropDuplicateGRPCWebPlugins()
+	return failures
+}
+
+// dropDuplicateGRPCWebPlugins detaches the grpc-web plugin attached by the
+// grpc-web annotation from the routes which a grpc-web KongPlugin or
+// KongClusterPlugin is already attached to, as Kong rejects several instances of
+// a plugin on a route. The configured plugin is kept.
+func (ks *KongState) dropDuplicateGRPCWebPlugins() {
+	attached := make(map[string]bool)
+	for _, plugin := range ks.Plugins {
+		if plugin.Name == nil || *plugin.Name != grpcWebPluginName ||
+			plugin.Route == nil || plugin.Route.ID == nil || plugin.Service != nil || plugin.Consumer != nil {
+			continue
+		}
+		attached[*plugin.Route.ID] = true
+	}
+	if len(attached) == 0 {
+		return
+	}
+	for i := range ks.Services {
+		for j := range ks.Services[i].Routes {
+			route := &ks.Services[i].Routes[j]
+			if route.Name != nil && attached[*route.Name] {
+				route.removeGRPCWebPlugin()
+			}
+		}
+	}
 }
@@ -2,19 +2,23 @@ package kongstate
 
 import (
 	"reflect"
+	"sort"
 	"testing"
 
 	"github.com/blang/semver/v4"
 	"github.com/kong/go-kong/kong"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
 	configurationv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+	configurationv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
 )
 
 func TestKongState_SanitizedCopy(t *testing.T) {
@@ -24,7 +28,7 @@ func TestKongState_SanitizedCopy(t *testing.T) {
 		want KongState
 	}{
 		{
-			name: "sanitizes all consumers and certificates and copies all other fields",
+			name: "sanitizes all consumers, certificates and vaults and copies all other fields",
 			in: KongState{
 				Services:       []Service{{Service: kong.Service{ID: kong.String("1")}}},
 				Upstreams:      []Upstream{{Upstream: kong.Upstream{ID: kong.String("1")}}},
@@ -34,6 +38,8 @@ func TestKongState_SanitizedCopy(t *testing.T) {
 				Consumers: []Consumer{{
 					KeyAuths: []*KeyAuth{{kong.KeyAuth{ID: kong.String("1"), Key: kong.String("secret")}}},
 				}},
+				Vaults:  []Vault{{Name: "aws", Prefix: "aws-eu", Config: map[string]interface{}{"region": "eu-west-1"}}},
+				Version: semver.MustParse("3.0.0"),
 			},
 			want: KongState{
 				Services:       []Service{{Service: kong.Service{ID: kong.String("1")}}},
@@ -44,6 +50,8 @@ func TestKongState_SanitizedCopy(t *testing.T) {
 				Consumers: []Consumer{{
 					KeyAuths: []*KeyAuth{{kong.KeyAuth{ID: kong.String("1"), Key: redactedString}}},
 				}},
+				Vaults:  []Vault{{Name: "aws", Prefix: "aws-eu", Config: map[string]interface{}{"region": *redactedString}}},
+				Version: semver.MustParse("3.0.0"),
 			},
 		},
 	} {
@@ -392,3 +400,412 @@ func Test_FillConsumersAndCredentials(t *testing.T) {
 		assert.Equal(t, want.Consumers[0].Oauth2Creds[0].RedirectURIs, state.Consumers[0].Oauth2Creds[0].RedirectURIs)
 	})
 }
+
+func Test_FillPlugins_KongPluginBundles(t *testing.T) {
+	clusterPlugin := func(name, plugin string) *configurationv1.KongClusterPlugin {
+		return &configurationv1.KongClusterPlugin{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: map[string]string{annotations.IngressClassKey: annotations.DefaultIngressClass},
+			},
+			PluginName: plugin,
+		}
+	}
+	s, err := store.NewFakeStore(store.FakeObjects{
+		KongPlugins: []*configurationv1.KongPlugin{
+			{ObjectMeta: metav1.ObjectMeta{Name: "strict-cors", Namespace: "team-a"}, PluginName: "cors"},
+		},
+		KongClusterPlugins: []*configurationv1.KongClusterPlugin{
+			clusterPlugin("edge-cors", "cors"),
+			clusterPlugin("edge-bot-detection", "bot-detection"),
+		},
+		KongPluginBundles: []*configurationv1beta1.KongPluginBundle{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "edge"},
+				Spec: configurationv1beta1.KongPluginBundleSpec{
+					Version:    "v2",
+					Plugins:    []string{"edge-cors", "edge-bot-detection"},
+					Namespaces: []string{"team-a"},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "incomplete"},
+				Spec: configurationv1beta1.KongPluginBundleSpec{
+					Version: "v1",
+					Plugins: []string{"edge-cors", "missing"},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "other-class"},
+				Spec: configurationv1beta1.KongPluginBundleSpec{
+					Version:        "v1",
+					Plugins:        []string{"edge-cors"},
+					IngressClasses: []string{"other"},
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	route := func(namespace, name string, plugins string) Route {
+		return Route{
+			Route: kong.Route{Name: kong.String(name)},
+			Ingress: util.K8sObjectInfo{
+				Namespace:   namespace,
+				Annotations: map[string]string{annotations.AnnotationPrefix + annotations.PluginsKey: plugins},
+			},
+		}
+	}
+	state := KongState{
+		Services: []Service{
+			{
+				Service: kong.Service{Name: kong.String("svc")},
+				Routes: []Route{
+					route("team-a", "team-a.web", ""),
+					route("team-a", "team-a.api", "strict-cors"),
+					route("team-b", "team-b.web", ""),
+				},
+			},
+		},
+	}
+	state.FillPlugins(logrus.New(), s, nil)
+
+	var got []string
+	for _, plugin := range state.Plugins {
+		got = append(got, *plugin.Route.ID+":"+*plugin.Name+":"+plugin.K8sName)
+	}
+	assert.ElementsMatch(t, []string{
+		"team-a.api:cors:strict-cors",
+		"team-a.web:cors:edge-cors",
+		"team-a.web:bot-detection:edge-bot-detection",
+		"team-a.api:bot-detection:edge-bot-detection",
+	}, got)
+}
+
+func Test_FillPlugins_GlobalKongClusterPlugins(t *testing.T) {
+	clusterPlugin := func(name, plugin, config string) *configurationv1.KongClusterPlugin {
+		return &configurationv1.KongClusterPlugin{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Labels:      map[string]string{"global": "true"},
+				Annotations: map[string]string{annotations.IngressClassKey: annotations.DefaultIngressClass},
+			},
+			PluginName: plugin,
+			Config:     apiextensionsv1.JSON{Raw: []byte(config)},
+		}
+	}
+	s, err := store.NewFakeStore(store.FakeObjects{
+		KongClusterPlugins: []*configurationv1.KongClusterPlugin{
+			clusterPlugin("correlation-id", "correlation-id", `{"header_name":"x-request-id"}`),
+			clusterPlugin("cors-a", "cors", ""),
+			clusterPlugin("cors-b", "cors", ""),
+			clusterPlugin("empty", "", ""),
+			clusterPlugin("invalid-config", "key-auth", `[]`),
+		},
+	})
+	require.NoError(t, err)
+
+	var state KongState
+	failures := state.FillPlugins(logrus.New(), s, nil)
+
+	require.Len(t, state.Plugins, 1)
+	assert.Equal(t, "correlation-id", *state.Plugins[0].Name)
+	assert.Equal(t, "correlation-id", state.Plugins[0].K8sName)
+	assert.Empty(t, state.Plugins[0].K8sNamespace)
+
+	failed := make(map[string]string)
+	for _, failure := range failures {
+		failed[failure.ClusterPlugin.Name] = failure.Message
+	}
+	assert.Len(t, failed, 4)
+	assert.Contains(t, failed["cors-a"], "multiple KongClusterPlugins")
+	assert.Contains(t, failed["cors-b"], "multiple KongClusterPlugins")
+	assert.Contains(t, failed["empty"], "empty plugin property")
+	assert.Contains(t, failed["invalid-config"], "failed to generate configuration")
+}
+
+func Test_FillPlugins_PluginOrdering(t *testing.T) {
+	ordering := &configurationv1.PluginOrdering{
+		Before: configurationv1.PluginOrderingPhase{"access": {"key-auth"}},
+	}
+	s, err := store.NewFakeStore(store.FakeObjects{
+		KongPlugins: []*configurationv1.KongPlugin{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "rate-limit", Namespace: "default"},
+				PluginName: "rate-limiting",
+				Ordering:   ordering,
+			},
+		},
+		KongClusterPlugins: []*configurationv1.KongClusterPlugin{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "correlation-id",
+					Labels:      map[string]string{"global": "true"},
+					Annotations: map[string]string{annotations.IngressClassKey: annotations.DefaultIngressClass},
+				},
+				PluginName: "correlation-id",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	state := KongState{
+		Services: []Service{
+			{
+				Service: kong.Service{Name: kong.String("svc")},
+				Routes: []Route{
+					{
+						Route: kong.Route{Name: kong.String("default.web")},
+						Ingress: util.K8sObjectInfo{
+							Namespace:   "default",
+							Annotations: map[string]string{annotations.AnnotationPrefix + annotations.PluginsKey: "rate-limit"},
+						},
+					},
+				},
+			},
+		},
+	}
+	state.FillPlugins(logrus.New(), s, nil)
+
+	orderings := make(map[string]*configurationv1.PluginOrdering)
+	for _, plugin := range state.Plugins {
+		orderings[*plugin.Name] = plugin.Ordering
+	}
+	assert.Equal(t, map[string]*configurationv1.PluginOrdering{
+		"rate-limiting":  ordering,
+		"correlation-id": nil,
+	}, orderings)
+}
+
+func Test_FillPlugins_RouteSelector(t *testing.T) {
+	s, err := store.NewFakeStore(store.FakeObjects{
+		KongClusterPlugins: []*configurationv1.KongClusterPlugin{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "bot-detection",
+					Annotations: map[string]string{annotations.IngressClassKey: annotations.DefaultIngressClass},
+				},
+				PluginName: "bot-detection",
+				RouteSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"tier": "public"},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "invalid-selector",
+					Annotations: map[string]string{annotations.IngressClassKey: annotations.DefaultIngressClass},
+				},
+				PluginName: "key-auth",
+				RouteSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "tier", Operator: "Unknown"},
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	route := func(name string, labels, anns map[string]string) Route {
+		return Route{
+			Route:   kong.Route{Name: kong.String(name)},
+			Ingress: util.K8sObjectInfo{Namespace: "default", Labels: labels, Annotations: anns},
+		}
+	}
+	state := KongState{
+		Services: []Service{
+			{
+				Service: kong.Service{Name: kong.String("svc")},
+				Routes: []Route{
+					route("default.public", map[string]string{"tier": "public"}, nil),
+					route("default.internal", map[string]string{"tier": "internal"}, nil),
+					route("default.annotated", map[string]string{"tier": "public"},
+						map[string]string{annotations.AnnotationPrefix + annotations.PluginsKey: "bot-detection"}),
+				},
+			},
+		},
+	}
+	state.FillPlugins(logrus.New(), s, nil)
+
+	routes := make(map[string]int)
+	for _, plugin := range state.Plugins {
+		assert.Equal(t, "bot-detection", *plugin.Name)
+		assert.Equal(t, "bot-detection", plugin.K8sName)
+		assert.True(t, plugin.ClusterPlugin)
+		require.NotNil(t, plugin.Route)
+		routes[*plugin.Route.ID]++
+	}
+	assert.Equal(t, map[string]int{"default.public": 1, "default.annotated": 1}, routes)
+}
+
+func Test_FillPlugins_GRPCWeb(t *testing.T) {
+	s, err := store.NewFakeStore(store.FakeObjects{
+		KongPlugins: []*configurationv1.KongPlugin{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "grpc-web", Namespace: "default"},
+				PluginName: "grpc-web",
+				Config:     apiextensionsv1.JSON{Raw: []byte(`{"allow_origin_header": "example.com"}`)},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	route := func(name string, anns map[string]string) Route {
+		r := Route{
+			Route:   kong.Route{Name: kong.String(name)},
+			Ingress: util.K8sObjectInfo{Namespace: "default", Annotations: anns},
+		}
+		r.overrideGRPCWeb(logrus.New(), anns)
+		return r
+	}
+	grpcWeb := map[string]string{annotations.AnnotationPrefix + annotations.GRPCWebKey: "true"}
+	state := KongState{
+		Services: []Service{
+			{
+				Service: kong.Service{Name: kong.String("svc")},
+				Routes: []Route{
+					route("default.annotated", grpcWeb),
+					route("default.configured", map[string]string{
+						annotations.AnnotationPrefix + annotations.GRPCWebKey: "true",
+						annotations.AnnotationPrefix + annotations.PluginsKey: "grpc-web",
+					}),
+				},
+			},
+		},
+	}
+	state.FillPlugins(logrus.New(), s, nil)
+
+	t.Log("verifying that the grpc-web annotation attaches the plugin to routes without one")
+	require.Len(t, state.Services[0].Routes[0].Plugins, 1)
+	assert.Equal(t, "grpc-web", *state.Services[0].Routes[0].Plugins[0].Name)
+
+	t.Log("verifying that the grpc-web KongPlugin replaces the one of the annotation")
+	assert.Empty(t, state.Services[0].Routes[1].Plugins)
+	require.Len(t, state.Plugins, 1)
+	assert.Equal(t, "default.configured", *state.Plugins[0].Route.ID)
+	assert.Equal(t, "example.com", state.Plugins[0].Config["allow_origin_header"])
+}
+
+func Test_FillPlugins_ConsumerGroups(t *testing.T) {
+	clusterPlugin := func(name, pluginName, groups string) *configurationv1.KongClusterPlugin {
+		return &configurationv1.KongClusterPlugin{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Annotations: map[string]string{
+					annotations.IngressClassKey:                                  annotations.DefaultIngressClass,
+					annotations.AnnotationPrefix + annotations.ConsumerGroupsKey: groups,
+				},
+			},
+			PluginName: pluginName,
+		}
+	}
+	s, err := store.NewFakeStore(store.FakeObjects{
+		KongClusterPlugins: []*configurationv1.KongClusterPlugin{
+			clusterPlugin("gold-rate-limit", "rate-limiting-advanced", "gold"),
+			clusterPlugin("silver-rate-limit", "rate-limiting-advanced", "silver"),
+			clusterPlugin("tier-header", "request-transformer", "gold, silver"),
+		},
+		KongPlugins: []*configurationv1.KongPlugin{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "custom-rate-limit",
+					Namespace: "default",
+				},
+				PluginName: "rate-limiting-advanced",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	consumer := func(username string, anns map[string]string) Consumer {
+		return Consumer{
+			Consumer: kong.Consumer{Username: kong.String(username)},
+			K8sKongConsumer: configurationv1.KongConsumer{
+				ObjectMeta: metav1.ObjectMeta{Name: username, Namespace: "default", Annotations: anns},
+			},
+		}
+	}
+	groupsKey := annotations.AnnotationPrefix + annotations.ConsumerGroupsKey
+	state := KongState{
+		Consumers: []Consumer{
+			consumer("gold", map[string]string{groupsKey: "gold"}),
+			consumer("silver-then-gold", map[string]string{groupsKey: "silver,gold"}),
+			consumer("custom", map[string]string{
+				groupsKey: "gold",
+				annotations.AnnotationPrefix + annotations.PluginsKey: "custom-rate-limit",
+			}),
+			consumer("unknown-group", map[string]string{groupsKey: "bronze"}),
+			consumer("no-group", nil),
+			{
+				Consumer: kong.Consumer{CustomID: kong.String("custom-id-only")},
+				K8sKongConsumer: configurationv1.KongConsumer{
+					ObjectMeta: metav1.ObjectMeta{Name: "custom-id-only", Namespace: "default", Annotations: map[string]string{
+						groupsKey: "gold",
+						annotations.AnnotationPrefix + annotations.PluginsKey: "custom-rate-limit",
+					}},
+				},
+			},
+		},
+	}
+	require.NotPanics(t, func() { state.FillPlugins(logrus.New(), s, nil) },
+		"consumers which only have a custom_id are skipped")
+
+	consumerPlugins := make(map[string][]string)
+	for _, plugin := range state.Plugins {
+		require.NotNil(t, plugin.Consumer)
+		consumerPlugins[*plugin.Consumer.ID] = append(consumerPlugins[*plugin.Consumer.ID], plugin.K8sName)
+	}
+	for _, plugins := range consumerPlugins {
+		sort.Strings(plugins)
+	}
+	assert.Equal(t, map[string][]string{
+		"gold":             {"gold-rate-limit", "tier-header"},
+		"silver-then-gold": {"silver-rate-limit", "tier-header"},
+		"custom":           {"custom-rate-limit", "tier-header"},
+	}, consumerPlugins)
+}
+
+func Test_FillPlugins_ServiceSelector(t *testing.T) {
+	s, err := store.NewFakeStore(store.FakeObjects{
+		KongClusterPlugins: []*configurationv1.KongClusterPlugin{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "payments-logging",
+					Annotations: map[string]string{annotations.IngressClassKey: annotations.DefaultIngressClass},
+				},
+				PluginName: "http-log",
+				ServiceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"team": "payments"},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	service := func(name string, labels, anns map[string]string) Service {
+		return Service{
+			Service: kong.Service{Name: kong.String(name)},
+			K8sServices: map[string]*corev1.Service{
+				name: {ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels, Annotations: anns}},
+			},
+		}
+	}
+	state := KongState{
+		Services: []Service{
+			service("default.payments.80", map[string]string{"team": "payments"}, nil),
+			service("default.search.80", map[string]string{"team": "search"}, nil),
+			service("default.annotated.80", map[string]string{"team": "payments"},
+				map[string]string{annotations.AnnotationPrefix + annotations.PluginsKey: "payments-logging"}),
+		},
+	}
+	state.FillPlugins(logrus.New(), s, nil)
+
+	services := make(map[string]int)
+	for _, plugin := range state.Plugins {
+		assert.Equal(t, "http-log", *plugin.Name)
+		assert.True(t, plugin.ClusterPlugin)
+		require.NotNil(t, plugin.Service)
+		services[*plugin.Service.ID]++
+	}
+	assert.Equal(t, map[string]int{"default.payments.80": 1, "default.annotated.80": 1}, services)
+}
@@ -0,0 +1,188 @@
+package kongstate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// protocolFamilies groups the protocols Kong can proxy between: a route can
+// only be served by a service of the same family.
+var protocolFamilies = map[string]string{
+	"http":            "http",
+	"https":           "http",
+	"grpc":            "http",
+	"grpcs":           "http",
+	"ws":              "http",
+	"wss":             "http",
+	"tcp":             "stream",
+	"tls":             "stream",
+	"tls_passthrough": "stream",
+	"udp":             "udp",
+}
+
+// defaultServiceProtocol and defaultRouteProtocols are the protocols Kong uses
+// for services and routes which don't set any.
+var (
+	defaultServiceProtocol = "http"
+	defaultRouteProtocols  = []string{"http", "https"}
+)
+
+// ProtocolMismatch describes a route whose protocols can't be served by the
+// protocol of its service.
+type ProtocolMismatch struct {
+	Route   Route
+	Message string
+}
+
+// RemoveProtocolMismatches removes the routes whose protocols can't be served
+// by the protocol of their service (e.g. a udp route on an http service), which
+// Kong would otherwise reject with an error that is hard to trace back to the
+// Kubernetes objects. Protocols unknown to the controller are left for Kong to
+// validate.
+func (ks *KongState) RemoveProtocolMismatches() []ProtocolMismatch {
+	var mismatches []ProtocolMismatch
+	for i, service := range ks.Services {
+		serviceProtocol := defaultServiceProtocol
+		if service.Protocol != nil && *service.Protocol != "" {
+			serviceProtocol = *service.Protocol
+		}
+		serviceFamily, ok := protocolFamilies[serviceProtocol]
+		if !ok {
+			continue
+		}
+
+		routes := make([]Route, 0, len(service.Routes))
+		for _, route := range service.Routes {
+			if invalid := mismatchedProtocols(route, serviceFamily); len(invalid) > 0 {
+				mismatches = append(mismatches, ProtocolMismatch{
+					Route: route,
+					Message: fmt.Sprintf("route %s dropped: protocols %s can't be served by service %s with protocol %s",
+						stringOrEmpty(route.Name), strings.Join(invalid, ","), stringOrEmpty(service.Name), serviceProtocol),
+				})
+				continue
+			}
+			routes = append(routes, route)
+		}
+		ks.Services[i].Routes = routes
+	}
+	return mismatches
+}
+
+// mismatchedProtocols provides the protocols of the route which don't belong to
+// the given protocol family.
+func mismatchedProtocols(route Route, family string) []string {
+	var mismatched []string
+	for _, protocol := range routeProtocols(route) {
+		if routeFamily, ok := protocolFamilies[protocol]; ok && routeFamily != family {
+			mismatched = append(mismatched, protocol)
+		}
+	}
+	return mismatched
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// PluginProtocolMismatch describes a plugin which was not attached to a route
+// or service because none of its protocols is used by them.
+type PluginProtocolMismatch struct {
+	Plugin  Plugin
+	Message string
+}
+
+// RemovePluginProtocolMismatches removes the plugins whose protocols aren't
+// used by the route they are attached to, or by any route of the service they
+// are attached to, which Kong would otherwise reject along with the whole
+// configuration. Only the mismatched attachments are removed.
+func (ks *KongState) RemovePluginProtocolMismatches() []PluginProtocolMismatch {
+	routes := make(map[string]Route)
+	serviceRoutes := make(map[string][]Route)
+	for _, service := range ks.Services {
+		for _, route := range service.Routes {
+			if route.Name != nil {
+				routes[*route.Name] = route
+			}
+			if service.Name != nil {
+				serviceRoutes[*service.Name] = append(serviceRoutes[*service.Name], route)
+			}
+		}
+	}
+
+	var mismatches []PluginProtocolMismatch
+	plugins := make([]Plugin, 0, len(ks.Plugins))
+	for _, plugin := range ks.Plugins {
+		if len(plugin.Protocols) == 0 {
+			plugins = append(plugins, plugin)
+			continue
+		}
+		pluginProtocols := make([]string, 0, len(plugin.Protocols))
+		for _, protocol := range plugin.Protocols {
+			if protocol != nil {
+				pluginProtocols = append(pluginProtocols, *protocol)
+			}
+		}
+
+		switch {
+		case plugin.Route != nil && plugin.Route.ID != nil:
+			route, ok := routes[*plugin.Route.ID]
+			if ok && !sharesProtocol(pluginProtocols, route) {
+				mismatches = append(mismatches, PluginProtocolMismatch{
+					Plugin: plugin,
+					Message: fmt.Sprintf("plugin %s not attached to route %s: protocols %s aren't used by the route",
+						stringOrEmpty(plugin.Name), *plugin.Route.ID, strings.Join(pluginProtocols, ",")),
+				})
+				continue
+			}
+		case plugin.Service != nil && plugin.Service.ID != nil:
+			routes := serviceRoutes[*plugin.Service.ID]
+			matched := len(routes) == 0
+			for _, route := range routes {
+				if sharesProtocol(pluginProtocols, route) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				mismatches = append(mismatches, PluginProtocolMismatch{
+					Plugin: plugin,
+					Message: fmt.Sprintf("plugin %s not attached to service %s: protocols %s aren't used by any of its routes",
+						stringOrEmpty(plugin.Name), *plugin.Service.ID, strings.Join(pluginProtocols, ",")),
+				})
+				continue
+			}
+		}
+		plugins = append(plugins, plugin)
+	}
+	ks.Plugins = plugins
+	return mismatches
+}
+
+// routeProtocols provides the protocols of a route, defaulted as Kong does.
+func routeProtocols(route Route) []string {
+	if len(route.Protocols) == 0 {
+		return defaultRouteProtocols
+	}
+	protocols := make([]string, 0, len(route.Protocols))
+	for _, protocol := range route.Protocols {
+		if protocol != nil {
+			protocols = append(protocols, *protocol)
+		}
+	}
+	return protocols
+}
+
+// sharesProtocol tells whether a route uses any of the given protocols.
+func sharesProtocol(protocols []string, route Route) bool {
+	for _, protocol := range protocols {
+		for _, routeProtocol := range routeProtocols(route) {
+			if protocol == routeProtocol {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package kongstate
+
+import (
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveProtocolMismatches(t *testing.T) {
+	route := func(name string, protocols ...string) Route {
+		return Route{Route: kong.Route{Name: kong.String(name), Protocols: kong.StringSlice(protocols...)}}
+	}
+	service := func(name string, protocol *string, routes ...Route) Service {
+		return Service{Service: kong.Service{Name: kong.String(name), Protocol: protocol}, Routes: routes}
+	}
+
+	ks := KongState{
+		Services: []Service{
+			service("http", kong.String("http"),
+				route("http", "http", "https"),
+				route("grpc", "grpc"),
+				route("default"),
+				route("udp", "udp"),
+			),
+			service("default", nil,
+				route("tcp", "tcp"),
+				route("https", "https"),
+			),
+			service("tcp", kong.String("tcp"),
+				route("tls", "tls"),
+				route("grpc", "grpc"),
+				route("udp", "udp"),
+			),
+			service("udp", kong.String("udp"),
+				route("udp", "udp"),
+				route("default"),
+			),
+			service("unknown", kong.String("unknown"),
+				route("udp", "udp"),
+			),
+		},
+	}
+
+	mismatches := ks.RemoveProtocolMismatches()
+	var messages []string
+	for _, mismatch := range mismatches {
+		messages = append(messages, mismatch.Message)
+	}
+	assert.Equal(t, []string{
+		"route udp dropped: protocols udp can't be served by service http with protocol http",
+		"route tcp dropped: protocols tcp can't be served by service default with protocol http",
+		"route grpc dropped: protocols grpc can't be served by service tcp with protocol tcp",
+		"route udp dropped: protocols udp can't be served by service tcp with protocol tcp",
+		"route default dropped: protocols http,https can't be served by service udp with protocol udp",
+	}, messages)
+
+	routeNames := func(service Service) []string {
+		var names []string
+		for _, route := range service.Routes {
+			names = append(names, *route.Name)
+		}
+		return names
+	}
+	require.Len(t, ks.Services, 5)
+	assert.Equal(t, []string{"http", "grpc", "default"}, routeNames(ks.Services[0]))
+	assert.Equal(t, []string{"https"}, routeNames(ks.Services[1]))
+	assert.Equal(t, []string{"tls"}, routeNames(ks.Services[2]))
+	assert.Equal(t, []string{"udp"}, routeNames(ks.Services[3]))
+	assert.Equal(t, []string{"udp"}, routeNames(ks.Services[4]))
+}
+
+func TestRemovePluginProtocolMismatches(t *testing.T) {
+	route := func(name string, protocols ...string) Route {
+		return Route{Route: kong.Route{Name: kong.String(name), Protocols: kong.StringSlice(protocols...)}}
+	}
+	plugin := func(name string, attach func(*kong.Plugin), protocols ...string) Plugin {
+		p := kong.Plugin{Name: kong.String(name), Protocols: kong.StringSlice(protocols...)}
+		attach(&p)
+		return Plugin{Plugin: p, K8sNamespace: "default", K8sName: name}
+	}
+	onRoute := func(id string) func(*kong.Plugin) {
+		return func(p *kong.Plugin) { p.Route = &kong.Route{ID: kong.String(id)} }
+	}
+	onService := func(id string) func(*kong.Plugin) {
+		return func(p *kong.Plugin) { p.Service = &kong.Service{ID: kong.String(id)} }
+	}
+	global := func(*kong.Plugin) {}
+
+	ks := KongState{
+		Services: []Service{
+			{Service: kong.Service{Name: kong.String("http")}, Routes: []Route{route("default"), route("grpc", "grpc")}},
+			{Service: kong.Service{Name: kong.String("tcp")}, Routes: []Route{route("tcp", "tcp", "tls")}},
+			{Service: kong.Service{Name: kong.String("empty")}},
+		},
+		Plugins: []Plugin{
+			plugin("http-on-default", onRoute("default"), "http"),
+			plugin("http-on-grpc", onRoute("grpc"), "http", "https"),
+			plugin("any-on-grpc", onRoute("grpc")),
+			plugin("http-on-tcp", onService("tcp"), "http"),
+			plugin("grpc-on-http", onService("http"), "grpc"),
+			plugin("http-on-empty", onService("empty"), "http"),
+			plugin("udp-global", global, "udp"),
+		},
+	}
+
+	mismatches := ks.RemovePluginProtocolMismatches()
+	var messages []string
+	for _, mismatch := range mismatches {
+		messages = append(messages, mismatch.Message)
+	}
+	assert.Equal(t, []string{
+		"plugin http-on-grpc not attached to route grpc: protocols http,https aren't used by the route",
+		"plugin http-on-tcp not attached to service tcp: protocols http aren't used by any of its routes",
+	}, messages)
+
+	var names []string
+	for _, p := range ks.Plugins {
+		names = append(names, p.K8sName)
+	}
+	assert.Equal(t, []string{"http-on-default", "any-on-grpc", "grpc-on-http", "http-on-empty", "udp-global"}, names)
+}
@@ -1,6 +1,7 @@
 package kongstate
 
 import (
+	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
@@ -30,6 +31,13 @@ var (
 	// TODO if the Kong core adds support for wildcard SNI route match criteria, this should change
 	validSNIs  = regexp.MustCompile(`^([a-zA-Z0-9]+(-[a-zA-Z0-9]+)*)+(\.([a-zA-Z0-9]+(-[a-zA-Z0-9]+)*))*$`)
 	validHosts = regexp.MustCompile(`^(\*\.)?([a-zA-Z0-9]+(-[a-zA-Z0-9]+)*)+(\.([a-zA-Z0-9]+(-[a-zA-Z0-9]+)*))*?(\.\*)?$`)
+
+	// header names are HTTP tokens, see https://www.rfc-editor.org/rfc/rfc7230#section-3.2.6
+	validHeaderNames = regexp.MustCompile("^[a-zA-Z0-9!#$%&'*+.^_`|~-]+$")
+
+	// rewriteCaptureGroups matches the references to the capture groups of
+	// the regex path of a route in a rewritten path, e.g. $1
+	rewriteCaptureGroups = regexp.MustCompile(`\$([0-9]+)`)
 )
 
 // normalizeProtocols prevents users from mismatching grpc/http
@@ -104,11 +112,16 @@ func (r *Route) overrideStripPath(anns map[string]string) {
 	}
 }
 
-func (r *Route) overrideProtocols(anns map[string]string) {
+func (r *Route) overrideProtocols(log logrus.FieldLogger, anns map[string]string) {
 	protocols := annotations.ExtractProtocolNames(anns)
+	if len(protocols) == 0 {
+		return
+	}
 	var prots []*string
 	for _, prot := range protocols {
 		if !util.ValidateProtocol(prot) {
+			// if any protocol is invalid, discard everything
+			log.WithField("kongroute", r.Name).Errorf("invalid protocol: %v", prot)
 			return
 		}
 		prots = append(prots, kong.String(prot))
@@ -117,7 +130,7 @@ func (r *Route) overrideProtocols(anns map[string]string) {
 	r.Protocols = prots
 }
 
-func (r *Route) overrideHTTPSRedirectCode(anns map[string]string) {
+func (r *Route) overrideHTTPSRedirectCode(log logrus.FieldLogger, anns map[string]string) {
 	if annotations.HasForceSSLRedirectAnnotation(anns) {
 		r.HTTPSRedirectStatusCode = kong.Int(302)
 		r.useSSLProtocol()
@@ -128,18 +141,35 @@ func (r *Route) overrideHTTPSRedirectCode(anns map[string]string) {
 		return
 	}
 	statusCode, err := strconv.Atoi(code)
-	if err != nil {
-		return
-	}
-	if statusCode != 426 &&
+	if err != nil || (statusCode != 426 &&
 		statusCode != 301 &&
 		statusCode != 302 &&
 		statusCode != 307 &&
-		statusCode != 308 {
+		statusCode != 308) {
+		log.WithField("kongroute", r.Name).Errorf("invalid HTTPS redirect status code: %v", code)
 		return
 	}
 
 	r.HTTPSRedirectStatusCode = kong.Int(statusCode)
+	if statusCode != 426 && r.acceptsHTTP() {
+		log.WithField("kongroute", r.Name).Warnf("HTTPS redirect status code %d has no effect: "+
+			"the route accepts http, list https only in the %s%s annotation to redirect",
+			statusCode, annotations.AnnotationPrefix, annotations.ProtocolsKey)
+	}
+}
+
+// acceptsHTTP tells whether the route accepts plain HTTP requests, which Kong
+// routes do when they set no protocols.
+func (r *Route) acceptsHTTP() bool {
+	if len(r.Protocols) == 0 {
+		return true
+	}
+	for _, protocol := range r.Protocols {
+		if protocol != nil && *protocol == "http" {
+			return true
+		}
+	}
+	return false
 }
 
 func (r *Route) overridePreserveHost(anns map[string]string) {
@@ -212,15 +242,133 @@ func (r *Route) overrideSNIs(log logrus.FieldLogger, anns map[string]string) {
 			return
 		}
 	}
+	// Kong rejects SNIs on routes which can't match TLS connections, which
+	// would fail the whole configuration
+	if len(snis) > 0 && !r.matchesTLS() {
+		log.WithField("kongroute", r.Name).Errorf("SNIs require the https or grpcs protocol, ignoring them")
+		return
+	}
 
 	r.SNIs = snis
 }
 
+// matchesTLS tells whether the route matches TLS connections, which it does
+// with the default protocols of Kong routes.
+func (r *Route) matchesTLS() bool {
+	if len(r.Protocols) == 0 {
+		return true
+	}
+	for _, protocol := range r.Protocols {
+		if protocol != nil && (*protocol == "https" || *protocol == "grpcs") {
+			return true
+		}
+	}
+	return false
+}
+
+// overrideHeaders adds the header matches of the headers.* annotations
+// ("konghq.com/headers.<header>: <value>[,<value>...]") to the route,
+// replacing any match of the same headers. Invalid annotations are ignored.
+func (r *Route) overrideHeaders(log logrus.FieldLogger, anns map[string]string) {
+	annotationValues := annotations.ExtractHeaders(anns)
+	if len(annotationValues) == 0 {
+		return
+	}
+	log = log.WithField("kongroute", r.Name)
+
+	matches := make(map[string][]string, len(annotationValues))
+	for header, annotationValue := range annotationValues {
+		if !validHeaderNames.MatchString(header) || strings.EqualFold(header, "host") {
+			log.Errorf("invalid header in annotation: %v", header)
+			continue
+		}
+		var values []string
+		for _, value := range strings.Split(annotationValue, ",") {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				values = nil
+				break
+			}
+			values = append(values, value)
+		}
+		if len(values) == 0 {
+			log.Errorf("invalid values of header %v in annotation: %v", header, annotationValue)
+			continue
+		}
+		matches[header] = values
+	}
+	if len(matches) == 0 {
+		return
+	}
+
+	headers := make(map[string][]string, len(r.Headers)+len(matches))
+	for name, values := range r.Headers {
+		replaced := false
+		for header := range matches {
+			if strings.EqualFold(name, header) {
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			headers[name] = values
+		}
+	}
+	for header, values := range matches {
+		headers[header] = values
+	}
+	r.Headers = headers
+}
+
+// overrideAPIVersionHeader adds the header match of the api-version-header
+// annotation ("<header>=<version>[,<version>...]") to the route, replacing any
+// match of the same header.
+func (r *Route) overrideAPIVersionHeader(log logrus.FieldLogger, anns map[string]string) {
+	annotationValue, ok := annotations.ExtractAPIVersionHeader(anns)
+	if !ok {
+		return
+	}
+	log = log.WithField("kongroute", r.Name)
+
+	header, value, ok := strings.Cut(annotationValue, "=")
+	header = strings.TrimSpace(header)
+	if !ok || !validHeaderNames.MatchString(header) || strings.EqualFold(header, "host") {
+		log.Errorf("invalid API version header: %v", annotationValue)
+		return
+	}
+	var versions []string
+	for _, version := range strings.Split(value, ",") {
+		version = strings.TrimSpace(version)
+		if version == "" {
+			log.Errorf("invalid API version header: %v", annotationValue)
+			return
+		}
+		versions = append(versions, version)
+	}
+
+	headers := make(map[string][]string, len(r.Headers)+1)
+	for name, values := range r.Headers {
+		if !strings.EqualFold(name, header) {
+			headers[name] = values
+		}
+	}
+	headers[header] = versions
+	r.Headers = headers
+}
+
+// isDisabled tells whether the object the route was generated from is in
+// maintenance mode, annotated with konghq.com/disabled: "true". Kong routes
+// can't be disabled, so such routes are detached from their services.
+func (r *Route) isDisabled() bool {
+	disabled, err := strconv.ParseBool(annotations.ExtractDisabled(r.Ingress.Annotations))
+	return err == nil && disabled
+}
+
 // overrideByAnnotation sets Route protocols via annotation
 func (r *Route) overrideByAnnotation(log logrus.FieldLogger) {
-	r.overrideProtocols(r.Ingress.Annotations)
+	r.overrideProtocols(log, r.Ingress.Annotations)
 	r.overrideStripPath(r.Ingress.Annotations)
-	r.overrideHTTPSRedirectCode(r.Ingress.Annotations)
+	r.overrideHTTPSRedirectCode(log, r.Ingress.Annotations)
 	r.overridePreserveHost(r.Ingress.Annotations)
 	r.overrideRegexPriority(r.Ingress.Annotations)
 	r.overrideMethods(log, r.Ingress.Annotations)
@@ -228,6 +376,10 @@ func (r *Route) overrideByAnnotation(log logrus.FieldLogger) {
 	r.overrideRequestBuffering(log, r.Ingress.Annotations)
 	r.overrideResponseBuffering(log, r.Ingress.Annotations)
 	r.overrideHosts(log, r.Ingress.Annotations)
+	r.overrideHeaders(log, r.Ingress.Annotations)
+	r.overrideAPIVersionHeader(log, r.Ingress.Annotations)
+	r.overrideRewrite(log, r.Ingress.Annotations)
+	r.overrideCORS(log, r.Ingress.Annotations)
 }
 
 // override sets Route fields by KongIngress first, then by annotation
@@ -253,6 +405,7 @@ func (r *Route) override(log logrus.FieldLogger, kongIngress *configurationv1.Ko
 	r.overrideByKongIngress(log, kongIngress)
 	r.overrideByAnnotation(log)
 	r.normalizeProtocols()
+	r.overrideGRPCWeb(log, r.Ingress.Annotations)
 	for _, val := range r.Protocols {
 		if *val == "grpc" || *val == "grpcs" {
 			// grpc(s) doesn't accept strip_path
@@ -369,6 +522,57 @@ func (r *Route) overrideResponseBuffering(log logrus.FieldLogger, anns map[strin
 	r.ResponseBuffering = kong.Bool(isEnabled)
 }
 
+// grpcWebPluginName is the name of the Kong plugin which translates gRPC-Web
+// requests from browsers into gRPC requests.
+const grpcWebPluginName = "grpc-web"
+
+// overrideGRPCWeb attaches the grpc-web plugin to the route and makes it
+// accept the HTTP protocols browsers use if the route opted into gRPC-Web.
+func (r *Route) overrideGRPCWeb(log logrus.FieldLogger, anns map[string]string) {
+	annotationValue, ok := annotations.ExtractGRPCWeb(anns)
+	if !ok {
+		// the annotation is not set, quit
+		return
+	}
+
+	isEnabled, err := strconv.ParseBool(strings.ToLower(annotationValue))
+	if err != nil {
+		// the value provided is not a parseable boolean, quit
+		log.WithField("kongroute", r.Name).Errorf("invalid grpc-web value: %s", err)
+		return
+	}
+	if !isEnabled {
+		return
+	}
+
+	// gRPC-Web requests are plain HTTP/1.1 or HTTP/2 requests
+	r.Protocols = kong.StringSlice("http", "https")
+	if !r.hasGRPCWebPlugin() {
+		r.Plugins = append(r.Plugins, kong.Plugin{Name: kong.String(grpcWebPluginName)})
+	}
+}
+
+// hasGRPCWebPlugin indicates whether the grpc-web plugin is attached to the route.
+func (r *Route) hasGRPCWebPlugin() bool {
+	for _, plugin := range r.Plugins {
+		if plugin.Name != nil && *plugin.Name == grpcWebPluginName {
+			return true
+		}
+	}
+	return false
+}
+
+// removeGRPCWebPlugin detaches the grpc-web plugin from the route.
+func (r *Route) removeGRPCWebPlugin() {
+	plugins := r.Plugins[:0]
+	for _, plugin := range r.Plugins {
+		if plugin.Name == nil || *plugin.Name != grpcWebPluginName {
+			plugins = append(plugins, plugin)
+		}
+	}
+	r.Plugins = plugins
+}
+
 // overrideHosts appends Host-Aliases to Hosts
 func (r *Route) overrideHosts(log logrus.FieldLogger, anns map[string]string) {
 	var hosts []*string
@@ -405,3 +609,148 @@ func (r *Route) overrideHosts(log logrus.FieldLogger, anns map[string]string) {
 
 	r.Hosts = hosts
 }
+
+// requestTransformerPluginName is the name of the Kong plugin which rewrites
+// requests before they're proxied to the upstream.
+const requestTransformerPluginName = "request-transformer"
+
+// RewriteURIFromAnnotations translates the path of the konghq.com/rewrite
+// annotation, empty if not set, into the template of the URI the
+// request-transformer plugin proxies requests with. As with ingress-nginx's
+// rewrite-target, $1, $2... refer to the capture groups of the regex path the
+// request matched.
+func RewriteURIFromAnnotations(anns map[string]string) (string, error) {
+	value, ok := annotations.ExtractRewrite(anns)
+	if !ok {
+		return "", nil
+	}
+	if !strings.HasPrefix(value, "/") || strings.ContainsAny(value, " ?#") {
+		return "", fmt.Errorf("%s%s annotation %q must be a path starting with /",
+			annotations.AnnotationPrefix, annotations.RewriteKey, value)
+	}
+	// any other $ would be evaluated by the template engine of the plugin
+	if strings.Contains(rewriteCaptureGroups.ReplaceAllString(value, ""), "$") {
+		return "", fmt.Errorf("%s%s annotation %q may only use $ to refer to capture groups, e.g. $1",
+			annotations.AnnotationPrefix, annotations.RewriteKey, value)
+	}
+	return rewriteCaptureGroups.ReplaceAllString(value, "$$(uri_captures[${1}])"), nil
+}
+
+// overrideRewrite attaches the request-transformer plugin to the route to
+// proxy requests with the path requested by the annotations. An invalid path,
+// which the admission webhook rejects, is ignored.
+func (r *Route) overrideRewrite(log logrus.FieldLogger, anns map[string]string) {
+	uri, err := RewriteURIFromAnnotations(anns)
+	if err != nil {
+		log.WithField("kongroute", r.Name).Error(err)
+		return
+	}
+	if uri == "" {
+		return
+	}
+	r.Plugins = append(r.Plugins, kong.Plugin{
+		Name: kong.String(requestTransformerPluginName),
+		Config: kong.Configuration{
+			"replace": map[string]interface{}{"uri": uri},
+		},
+	})
+}
+
+// corsPluginName is the name of the Kong plugin which handles cross-origin
+// requests.
+const corsPluginName = "cors"
+
+// CORSConfigurationFromAnnotations translates the konghq.com/cors-*
+// annotations into the configuration of the cors plugin, nil unless
+// konghq.com/cors-allow-origins is set.
+func CORSConfigurationFromAnnotations(anns map[string]string) (kong.Configuration, error) {
+	value, ok := annotations.ExtractCORSAllowOrigins(anns)
+	if !ok {
+		return nil, nil
+	}
+	origins := splitAnnotationList(value)
+	if len(origins) == 0 {
+		return nil, fmt.Errorf("%s%s annotation must list at least one origin",
+			annotations.AnnotationPrefix, annotations.CORSAllowOriginsKey)
+	}
+	config := kong.Configuration{"origins": origins}
+
+	if value, ok := annotations.ExtractCORSAllowMethods(anns); ok {
+		methods := splitAnnotationList(strings.ToUpper(value))
+		for _, method := range methods {
+			if !validMethods.MatchString(method) {
+				return nil, fmt.Errorf("%s%s annotation has an invalid method: %q",
+					annotations.AnnotationPrefix, annotations.CORSAllowMethodsKey, method)
+			}
+		}
+		config["methods"] = methods
+	}
+	for _, headers := range []struct {
+		key   string
+		field string
+		value func(map[string]string) (string, bool)
+	}{
+		{annotations.CORSAllowHeadersKey, "headers", annotations.ExtractCORSAllowHeaders},
+		{annotations.CORSExposeHeadersKey, "exposed_headers", annotations.ExtractCORSExposeHeaders},
+	} {
+		value, ok := headers.value(anns)
+		if !ok {
+			continue
+		}
+		names := splitAnnotationList(value)
+		for _, name := range names {
+			if !validHeaderNames.MatchString(name) {
+				return nil, fmt.Errorf("%s%s annotation has an invalid header name: %q",
+					annotations.AnnotationPrefix, headers.key, name)
+			}
+		}
+		config[headers.field] = names
+	}
+	if value, ok := annotations.ExtractCORSAllowCredentials(anns); ok {
+		credentials, err := strconv.ParseBool(strings.ToLower(value))
+		if err != nil {
+			return nil, fmt.Errorf("%s%s annotation %q must be true or false",
+				annotations.AnnotationPrefix, annotations.CORSAllowCredentialsKey, value)
+		}
+		config["credentials"] = credentials
+	}
+	if value, ok := annotations.ExtractCORSMaxAge(anns); ok {
+		maxAge, err := strconv.Atoi(value)
+		if err != nil || maxAge < 0 {
+			return nil, fmt.Errorf("%s%s annotation %q must be a number of seconds",
+				annotations.AnnotationPrefix, annotations.CORSMaxAgeKey, value)
+		}
+		config["max_age"] = maxAge
+	}
+	return config, nil
+}
+
+// splitAnnotationList splits the comma-separated list of an annotation,
+// dropping the whitespace around and the empty items.
+func splitAnnotationList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// overrideCORS attaches the cors plugin to the route with the configuration
+// requested by the annotations. Invalid annotations, which the admission
+// webhook rejects, are ignored.
+func (r *Route) overrideCORS(log logrus.FieldLogger, anns map[string]string) {
+	config, err := CORSConfigurationFromAnnotations(anns)
+	if err != nil {
+		log.WithField("kongroute", r.Name).Error(err)
+		return
+	}
+	if config == nil {
+		return
+	}
+	r.Plugins = append(r.Plugins, kong.Plugin{
+		Name:   kong.String(corsPluginName),
+		Config: config,
+	})
+}
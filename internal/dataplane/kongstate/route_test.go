@@ -301,6 +301,18 @@ func TestOverrideRouteByAnnotation(t *testing.T) {
 	assert.Equal(route.Hosts, kong.StringSlice("foo.com", "bar.com"))
 	assert.Equal(route.Protocols, kong.StringSlice("grpc", "grpcs"))
 
+	route = Route{
+		Ingress: util.K8sObjectInfo{
+			Annotations: map[string]string{
+				"konghq.com/protocols":                  "https",
+				"konghq.com/https-redirect-status-code": "308",
+			},
+		},
+	}
+	route.overrideByAnnotation(logrus.New())
+	assert.Equal(kong.StringSlice("https"), route.Protocols)
+	assert.Equal(kong.Int(308), route.HTTPSRedirectStatusCode)
+
 	assert.NotPanics(func() {
 		var nilRoute *Route
 		nilRoute.override(logrus.New(), nil)
@@ -361,7 +373,7 @@ func TestNormalizeProtocols(t *testing.T) {
 		log.SetOutput(ioutil.Discard)
 
 		var nilUpstream *Upstream
-		nilUpstream.override(log, nil, nil)
+		nilUpstream.override(log, nil, nil, nil)
 	})
 }
 
@@ -618,7 +630,7 @@ func Test_overrideRouteHTTPSRedirectCode(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tt.args.route.overrideHTTPSRedirectCode(tt.args.anns)
+			tt.args.route.overrideHTTPSRedirectCode(logrus.New(), tt.args.anns)
 			if !reflect.DeepEqual(tt.args.route, tt.want) {
 				t.Errorf("overrideRouteHTTPSRedirectCode() got = %v, want %v", tt.args.route, tt.want)
 			}
@@ -754,6 +766,72 @@ func Test_overrideRouteRegexPriority(t *testing.T) {
 	}
 }
 
+func Test_overrideRouteProtocols(t *testing.T) {
+	type args struct {
+		route Route
+		anns  map[string]string
+	}
+	tests := []struct {
+		name string
+		args args
+		want Route
+	}{
+		{name: "basic empty route"},
+		{
+			name: "basic sanity",
+			args: args{
+				anns: map[string]string{
+					"konghq.com/protocols": "https",
+				},
+			},
+			want: Route{
+				Route: kong.Route{
+					Protocols: kong.StringSlice("https"),
+				},
+			},
+		},
+		{
+			name: "whitespace and case",
+			args: args{
+				anns: map[string]string{
+					"konghq.com/protocols": " HTTP, https ",
+				},
+			},
+			want: Route{
+				Route: kong.Route{
+					Protocols: kong.StringSlice("http", "https"),
+				},
+			},
+		},
+		{
+			name: "invalid protocol",
+			args: args{
+				route: Route{
+					Route: kong.Route{
+						Protocols: kong.StringSlice("http", "https"),
+					},
+				},
+				anns: map[string]string{
+					"konghq.com/protocols": "https,ftp",
+				},
+			},
+			want: Route{
+				Route: kong.Route{
+					Protocols: kong.StringSlice("http", "https"),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args.route.overrideProtocols(logrus.New(), tt.args.anns)
+			if !reflect.DeepEqual(tt.args.route, tt.want) {
+				t.Errorf("overrideRouteProtocols() got = %v, want %v", tt.args.route, tt.want)
+			}
+		})
+	}
+}
+
 func Test_overrideRouteMethods(t *testing.T) {
 	type args struct {
 		route Route
@@ -837,6 +915,31 @@ func Test_overrideRouteSNIs(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "https route",
+			args: args{
+				route: Route{Route: kong.Route{Protocols: kong.StringSlice("https")}},
+				anns: map[string]string{
+					"konghq.com/snis": "hrodna.kong.example",
+				},
+			},
+			want: Route{
+				Route: kong.Route{
+					Protocols: kong.StringSlice("https"),
+					SNIs:      kong.StringSlice("hrodna.kong.example"),
+				},
+			},
+		},
+		{
+			name: "http only route, which can't match SNIs",
+			args: args{
+				route: Route{Route: kong.Route{Protocols: kong.StringSlice("http")}},
+				anns: map[string]string{
+					"konghq.com/snis": "hrodna.kong.example",
+				},
+			},
+			want: Route{Route: kong.Route{Protocols: kong.StringSlice("http")}},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1055,3 +1158,301 @@ func Test_overrideHosts(t *testing.T) {
 		})
 	}
 }
+
+func Test_overrideAPIVersionHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string][]string
+		anns    map[string]string
+		want    map[string][]string
+	}{
+		{name: "no annotation"},
+		{
+			name: "single version",
+			anns: map[string]string{"konghq.com/api-version-header": "X-API-Version=v2"},
+			want: map[string][]string{"X-API-Version": {"v2"}},
+		},
+		{
+			name: "several versions",
+			anns: map[string]string{"konghq.com/api-version-header": "X-API-Version = v2, v3"},
+			want: map[string][]string{"X-API-Version": {"v2", "v3"}},
+		},
+		{
+			name:    "other header matches are kept and the same header is replaced",
+			headers: map[string][]string{"x-api-version": {"v1"}, "X-Tenant": {"acme"}},
+			anns:    map[string]string{"konghq.com/api-version-header": "X-API-Version=v2"},
+			want:    map[string][]string{"X-API-Version": {"v2"}, "X-Tenant": {"acme"}},
+		},
+		{
+			name:    "missing version",
+			headers: map[string][]string{"X-Tenant": {"acme"}},
+			anns:    map[string]string{"konghq.com/api-version-header": "X-API-Version"},
+			want:    map[string][]string{"X-Tenant": {"acme"}},
+		},
+		{
+			name: "empty version",
+			anns: map[string]string{"konghq.com/api-version-header": "X-API-Version=v2,"},
+		},
+		{
+			name: "invalid header name",
+			anns: map[string]string{"konghq.com/api-version-header": "X API Version=v2"},
+		},
+		{
+			name: "host header",
+			anns: map[string]string{"konghq.com/api-version-header": "Host=v2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route := Route{Route: kong.Route{Headers: tt.headers}}
+			route.overrideAPIVersionHeader(logrus.New(), tt.anns)
+			assert.Equal(t, tt.want, route.Headers)
+		})
+	}
+}
+
+func Test_overrideHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string][]string
+		anns    map[string]string
+		want    map[string][]string
+	}{
+		{name: "no annotation"},
+		{
+			name: "single header",
+			anns: map[string]string{"konghq.com/headers.x-api-version": "2"},
+			want: map[string][]string{"x-api-version": {"2"}},
+		},
+		{
+			name: "several headers and values",
+			anns: map[string]string{
+				"konghq.com/headers.x-api-version": "2, 3",
+				"konghq.com/headers.x-tenant":      "acme",
+			},
+			want: map[string][]string{"x-api-version": {"2", "3"}, "x-tenant": {"acme"}},
+		},
+		{
+			name:    "other header matches are kept and the same headers are replaced",
+			headers: map[string][]string{"X-API-Version": {"1"}, "X-Region": {"eu"}},
+			anns:    map[string]string{"konghq.com/headers.x-api-version": "2"},
+			want:    map[string][]string{"x-api-version": {"2"}, "X-Region": {"eu"}},
+		},
+		{
+			name:    "invalid annotations are ignored",
+			headers: map[string][]string{"X-Region": {"eu"}},
+			anns: map[string]string{
+				"konghq.com/headers.host":          "example.com",
+				"konghq.com/headers.x-api-version": "2,",
+				"konghq.com/headers.x-tenant":      "acme",
+			},
+			want: map[string][]string{"X-Region": {"eu"}, "x-tenant": {"acme"}},
+		},
+		{
+			name:    "only invalid annotations",
+			headers: map[string][]string{"X-Region": {"eu"}},
+			anns:    map[string]string{"konghq.com/headers.x-tenant": " "},
+			want:    map[string][]string{"X-Region": {"eu"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route := Route{Route: kong.Route{Headers: tt.headers}}
+			route.overrideHeaders(logrus.New(), tt.anns)
+			assert.Equal(t, tt.want, route.Headers)
+		})
+	}
+}
+
+func Test_overrideGRPCWeb(t *testing.T) {
+	grpcWebPlugin := kong.Plugin{Name: kong.String("grpc-web")}
+	tests := []struct {
+		name  string
+		route Route
+		anns  map[string]string
+		want  Route
+	}{
+		{
+			name:  "annotation not set",
+			route: Route{Route: kong.Route{Protocols: kong.StringSlice("grpc", "grpcs")}},
+			want:  Route{Route: kong.Route{Protocols: kong.StringSlice("grpc", "grpcs")}},
+		},
+		{
+			name:  "set to false",
+			route: Route{Route: kong.Route{Protocols: kong.StringSlice("grpc", "grpcs")}},
+			anns: map[string]string{
+				"konghq.com/grpc-web": "false",
+			},
+			want: Route{Route: kong.Route{Protocols: kong.StringSlice("grpc", "grpcs")}},
+		},
+		{
+			name:  "invalid value",
+			route: Route{Route: kong.Route{Protocols: kong.StringSlice("grpc", "grpcs")}},
+			anns: map[string]string{
+				"konghq.com/grpc-web": "yes please",
+			},
+			want: Route{Route: kong.Route{Protocols: kong.StringSlice("grpc", "grpcs")}},
+		},
+		{
+			name:  "set to true attaches the plugin and sets http protocols",
+			route: Route{Route: kong.Route{Protocols: kong.StringSlice("grpc", "grpcs")}},
+			anns: map[string]string{
+				"konghq.com/grpc-web": "True",
+			},
+			want: Route{
+				Route:   kong.Route{Protocols: kong.StringSlice("http", "https")},
+				Plugins: []kong.Plugin{grpcWebPlugin},
+			},
+		},
+		{
+			name: "does not attach the plugin twice",
+			route: Route{
+				Route:   kong.Route{Protocols: kong.StringSlice("https")},
+				Plugins: []kong.Plugin{grpcWebPlugin},
+			},
+			anns: map[string]string{
+				"konghq.com/grpc-web": "true",
+			},
+			want: Route{
+				Route:   kong.Route{Protocols: kong.StringSlice("http", "https")},
+				Plugins: []kong.Plugin{grpcWebPlugin},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.route.overrideGRPCWeb(logrus.New(), tt.anns)
+			assert.Equal(t, tt.want, tt.route)
+		})
+	}
+}
+
+func TestRewriteURIFromAnnotations(t *testing.T) {
+	for _, tt := range []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "/", want: "/"},
+		{value: "/api/v2", want: "/api/v2"},
+		{value: "/api/$1/items/$12", want: "/api/$(uri_captures[1])/items/$(uri_captures[12])"},
+		{value: "api/$1", wantErr: true},
+		{value: "/api?version=2", wantErr: true},
+		{value: "/api/$(uri_captures[1])", wantErr: true},
+		{value: "/api/$", wantErr: true},
+	} {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := RewriteURIFromAnnotations(map[string]string{"konghq.com/rewrite": tt.value})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	got, err := RewriteURIFromAnnotations(map[string]string{})
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func Test_overrideRewrite(t *testing.T) {
+	var route Route
+	route.overrideRewrite(logrus.New(), map[string]string{"konghq.com/rewrite": "api/$1"})
+	assert.Empty(t, route.Plugins)
+
+	route.overrideRewrite(logrus.New(), map[string]string{"konghq.com/rewrite": "/api/$1"})
+	assert.Equal(t, []kong.Plugin{
+		{
+			Name: kong.String("request-transformer"),
+			Config: kong.Configuration{
+				"replace": map[string]interface{}{"uri": "/api/$(uri_captures[1])"},
+			},
+		},
+	}, route.Plugins)
+}
+
+func TestCORSConfigurationFromAnnotations(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		anns    map[string]string
+		want    kong.Configuration
+		wantErr bool
+	}{
+		{name: "not set"},
+		{
+			name: "origins only",
+			anns: map[string]string{"konghq.com/cors-allow-origins": "https://a.example.com, https://b.example.com"},
+			want: kong.Configuration{"origins": []string{"https://a.example.com", "https://b.example.com"}},
+		},
+		{
+			name: "all annotations",
+			anns: map[string]string{
+				"konghq.com/cors-allow-origins":     "*",
+				"konghq.com/cors-allow-methods":     "get, post",
+				"konghq.com/cors-allow-headers":     "Authorization,Content-Type",
+				"konghq.com/cors-expose-headers":    "X-Request-Id",
+				"konghq.com/cors-allow-credentials": "True",
+				"konghq.com/cors-max-age":           "3600",
+			},
+			want: kong.Configuration{
+				"origins":         []string{"*"},
+				"methods":         []string{"GET", "POST"},
+				"headers":         []string{"Authorization", "Content-Type"},
+				"exposed_headers": []string{"X-Request-Id"},
+				"credentials":     true,
+				"max_age":         3600,
+			},
+		},
+		{
+			name:    "empty origins",
+			anns:    map[string]string{"konghq.com/cors-allow-origins": " , "},
+			wantErr: true,
+		},
+		{
+			name:    "invalid method",
+			anns:    map[string]string{"konghq.com/cors-allow-origins": "*", "konghq.com/cors-allow-methods": "GET,-1"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid header",
+			anns:    map[string]string{"konghq.com/cors-allow-origins": "*", "konghq.com/cors-expose-headers": "X Request Id"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid credentials",
+			anns:    map[string]string{"konghq.com/cors-allow-origins": "*", "konghq.com/cors-allow-credentials": "yes"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid max age",
+			anns:    map[string]string{"konghq.com/cors-allow-origins": "*", "konghq.com/cors-max-age": "1h"},
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CORSConfigurationFromAnnotations(tt.anns)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_overrideCORS(t *testing.T) {
+	var route Route
+	route.overrideCORS(logrus.New(), map[string]string{"konghq.com/cors-allow-origins": ""})
+	assert.Empty(t, route.Plugins)
+
+	route.overrideCORS(logrus.New(), map[string]string{"konghq.com/cors-allow-origins": "https://example.com"})
+	assert.Equal(t, []kong.Plugin{
+		{
+			Name:   kong.String("cors"),
+			Config: kong.Configuration{"origins": []string{"https://example.com"}},
+		},
+	}, route.Plugins)
+}
@@ -1,8 +1,11 @@
 package kongstate
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/blang/semver/v4"
 	"github.com/kong/go-kong/kong"
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
@@ -12,6 +15,7 @@ import (
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
 	configurationv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+	configurationv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
 )
 
 // Services is a list of kongstate.Service objects with sorting enabled based
@@ -52,6 +56,39 @@ type Service struct {
 	Backends    []ServiceBackend
 	K8sServices map[string]*corev1.Service
 	Parent      client.Object
+
+	// ExternalUpstream indicates that the service proxies to an upstream
+	// managed outside of Kubernetes, so none is generated for it.
+	ExternalUpstream bool
+}
+
+// DeepCopy provides a copy of the Service which can be modified without
+// affecting the original. The Kubernetes objects the Service refers to are
+// shared with the original, as they're never modified.
+func (s *Service) DeepCopy() *Service {
+	out := &Service{
+		Service:     *s.Service.DeepCopy(),
+		Namespace:   s.Namespace,
+		Backends:    append([]ServiceBackend(nil), s.Backends...),
+		K8sServices: s.K8sServices,
+		Parent:      s.Parent,
+
+		ExternalUpstream: s.ExternalUpstream,
+	}
+	for _, plugin := range s.Plugins {
+		out.Plugins = append(out.Plugins, *plugin.DeepCopy())
+	}
+	for _, route := range s.Routes {
+		routeCopy := Route{
+			Route:   *route.Route.DeepCopy(),
+			Ingress: route.Ingress,
+		}
+		for _, plugin := range route.Plugins {
+			routeCopy.Plugins = append(routeCopy.Plugins, *plugin.DeepCopy())
+		}
+		out.Routes = append(out.Routes, routeCopy)
+	}
+	return out
 }
 
 // overrideByKongIngress sets Service fields by KongIngress
@@ -78,6 +115,15 @@ func (s *Service) overrideByKongIngress(kongIngress *configurationv1.KongIngress
 	if p.WriteTimeout != nil {
 		s.WriteTimeout = kong.Int(*p.WriteTimeout)
 	}
+	if p.TLSVerify != nil {
+		s.TLSVerify = kong.Bool(*p.TLSVerify)
+	}
+	if p.TLSVerifyDepth != nil {
+		s.TLSVerifyDepth = kong.Int(*p.TLSVerifyDepth)
+	}
+	if len(p.CACertificates) > 0 {
+		s.CACertificates = kong.StringSlice(p.CACertificates...)
+	}
 }
 
 func (s *Service) overridePath(anns map[string]string) {
@@ -114,12 +160,213 @@ func (s *Service) overrideByAnnotation(anns map[string]string) {
 	}
 	s.overrideProtocol(anns)
 	s.overridePath(anns)
+	s.overrideTLSVerify(anns)
+	s.overrideTLSVerifyDepth(anns)
+	s.overrideTimeouts(anns)
+	s.overrideRetries(anns)
+}
+
+func (s *Service) overrideTLSVerify(anns map[string]string) {
+	value, ok := annotations.ExtractTLSVerify(anns)
+	if !ok {
+		return
+	}
+	tlsVerify, err := strconv.ParseBool(strings.ToLower(value))
+	if err != nil {
+		return
+	}
+	s.TLSVerify = kong.Bool(tlsVerify)
+}
+
+func (s *Service) overrideTLSVerifyDepth(anns map[string]string) {
+	value, ok := annotations.ExtractTLSVerifyDepth(anns)
+	if !ok {
+		return
+	}
+	depth, err := strconv.Atoi(value)
+	if err != nil || depth < 0 {
+		return
+	}
+	s.TLSVerifyDepth = kong.Int(depth)
+}
+
+// maxServiceTimeout is the largest timeout of a Kong service, in milliseconds.
+const maxServiceTimeout = 2147483646
+
+// ServiceTimeouts are the timeouts of a Kong service, in milliseconds, which
+// the konghq.com/connect-timeout, konghq.com/read-timeout and
+// konghq.com/write-timeout annotations request, nil for those not requested.
+type ServiceTimeouts struct {
+	Connect *int
+	Read    *int
+	Write   *int
+}
+
+// ServiceTimeoutsFromAnnotations parses the timeouts requested by annotations,
+// failing if any of them isn't a number of milliseconds Kong accepts. The
+// valid timeouts are provided regardless.
+func ServiceTimeoutsFromAnnotations(anns map[string]string) (ServiceTimeouts, error) {
+	var timeouts ServiceTimeouts
+	var err error
+	for _, t := range []struct {
+		key     string
+		extract func(map[string]string) (string, bool)
+		timeout **int
+	}{
+		{annotations.ConnectTimeoutKey, annotations.ExtractConnectTimeout, &timeouts.Connect},
+		{annotations.ReadTimeoutKey, annotations.ExtractReadTimeout, &timeouts.Read},
+		{annotations.WriteTimeoutKey, annotations.ExtractWriteTimeout, &timeouts.Write},
+	} {
+		value, ok := t.extract(anns)
+		if !ok {
+			continue
+		}
+		timeout, parseErr := strconv.Atoi(value)
+		if parseErr != nil || timeout < 0 || timeout > maxServiceTimeout {
+			if err == nil {
+				err = fmt.Errorf("%s%s annotation %q must be a number of milliseconds between 0 and %d",
+					annotations.AnnotationPrefix, t.key, value, maxServiceTimeout)
+			}
+			continue
+		}
+		*t.timeout = &timeout
+	}
+	return timeouts, err
+}
+
+// overrideTimeouts sets the timeouts requested by the annotations of the
+// Kubernetes service. Invalid timeouts, which the admission webhook rejects,
+// are ignored.
+func (s *Service) overrideTimeouts(anns map[string]string) {
+	timeouts, _ := ServiceTimeoutsFromAnnotations(anns)
+	if timeouts.Connect != nil {
+		s.ConnectTimeout = kong.Int(*timeouts.Connect)
+	}
+	if timeouts.Read != nil {
+		s.ReadTimeout = kong.Int(*timeouts.Read)
+	}
+	if timeouts.Write != nil {
+		s.WriteTimeout = kong.Int(*timeouts.Write)
+	}
+}
+
+// overrideTimeoutsByRoutes sets the timeouts requested by the annotations of
+// the objects the routes of the service were generated from (e.g. Ingresses),
+// the largest one when they request different ones, so that no object cuts off
+// the requests to the long-running APIs of another. The timeouts requested by
+// the Kubernetes services themselves take precedence.
+func (s *Service) overrideTimeoutsByRoutes() {
+	var fromServices, fromRoutes ServiceTimeouts
+	for _, svc := range s.K8sServices {
+		timeouts, _ := ServiceTimeoutsFromAnnotations(svc.Annotations)
+		fromServices = mergeTimeouts(fromServices, timeouts)
+	}
+	for _, r := range s.Routes {
+		timeouts, _ := ServiceTimeoutsFromAnnotations(r.Ingress.Annotations)
+		fromRoutes = mergeTimeouts(fromRoutes, timeouts)
+	}
+	if fromRoutes.Connect != nil && fromServices.Connect == nil {
+		s.ConnectTimeout = kong.Int(*fromRoutes.Connect)
+	}
+	if fromRoutes.Read != nil && fromServices.Read == nil {
+		s.ReadTimeout = kong.Int(*fromRoutes.Read)
+	}
+	if fromRoutes.Write != nil && fromServices.Write == nil {
+		s.WriteTimeout = kong.Int(*fromRoutes.Write)
+	}
+}
+
+// mergeTimeouts provides the largest of each of the timeouts of a and b.
+func mergeTimeouts(a, b ServiceTimeouts) ServiceTimeouts {
+	largest := func(x, y *int) *int {
+		if x == nil || (y != nil && *y > *x) {
+			return y
+		}
+		return x
+	}
+	return ServiceTimeouts{
+		Connect: largest(a.Connect, b.Connect),
+		Read:    largest(a.Read, b.Read),
+		Write:   largest(a.Write, b.Write),
+	}
+}
+
+// maxServiceRetries is the largest number of retries of a Kong service.
+const maxServiceRetries = 32767
+
+// ServiceRetriesFromAnnotations parses the number of retries requested by the
+// konghq.com/retries annotation, nil if not requested, failing if it isn't
+// one Kong accepts.
+func ServiceRetriesFromAnnotations(anns map[string]string) (*int, error) {
+	value, ok := annotations.ExtractRetries(anns)
+	if !ok {
+		return nil, nil
+	}
+	retries, err := strconv.Atoi(value)
+	if err != nil || retries < 0 || retries > maxServiceRetries {
+		return nil, fmt.Errorf("%s%s annotation %q must be a number between 0 and %d",
+			annotations.AnnotationPrefix, annotations.RetriesKey, value, maxServiceRetries)
+	}
+	return &retries, nil
+}
+
+// overrideRetries sets the number of retries requested by the annotations of
+// the Kubernetes service, e.g. 0 for APIs which aren't idempotent. An invalid
+// number, which the admission webhook rejects, is ignored.
+func (s *Service) overrideRetries(anns map[string]string) {
+	retries, err := ServiceRetriesFromAnnotations(anns)
+	if err != nil || retries == nil {
+		return
+	}
+	s.Retries = kong.Int(*retries)
 }
 
-// override sets Service fields by KongIngress first, then by k8s Service's annotations
+// minServiceEnabledKongVersion is the first Kong version which can disable
+// services.
+var minServiceEnabledKongVersion = semver.MustParse("2.7.0")
+
+// overrideDisabled puts the service in maintenance mode if the Kubernetes
+// service is annotated with konghq.com/disabled: "true". Kong versions which
+// can't disable services get the service without its routes instead.
+func (s *Service) overrideDisabled(anns map[string]string, kongVersion semver.Version) {
+	disabled, err := strconv.ParseBool(annotations.ExtractDisabled(anns))
+	if err != nil || !disabled {
+		return
+	}
+	if kongVersion.LT(minServiceEnabledKongVersion) {
+		s.Routes = nil
+		return
+	}
+	s.Enabled = kong.Bool(false)
+}
+
+// overrideByKongUpstreamPolicy sets Service fields by the connection settings
+// of the KongUpstreamPolicy associated with the Kubernetes service.
+func (s *Service) overrideByKongUpstreamPolicy(policy *configurationv1beta1.KongUpstreamPolicy) {
+	if policy == nil || policy.Spec.Connections == nil {
+		return
+	}
+	c := policy.Spec.Connections
+	if c.ConnectTimeout != nil {
+		s.ConnectTimeout = kong.Int(*c.ConnectTimeout)
+	}
+	if c.ReadTimeout != nil {
+		s.ReadTimeout = kong.Int(*c.ReadTimeout)
+	}
+	if c.WriteTimeout != nil {
+		s.WriteTimeout = kong.Int(*c.WriteTimeout)
+	}
+	if c.Retries != nil {
+		s.Retries = kong.Int(*c.Retries)
+	}
+}
+
+// override sets Service fields by KongIngress first, then by KongUpstreamPolicy
+// and finally by k8s Service's annotations
 func (s *Service) override(
 	log logrus.FieldLogger,
 	kongIngress *configurationv1.KongIngress,
+	policy *configurationv1beta1.KongUpstreamPolicy,
 	svc *corev1.Service,
 ) {
 	if s == nil {
@@ -155,8 +402,10 @@ func (s *Service) override(
 	}
 
 	s.overrideByKongIngress(kongIngress)
+	s.overrideByKongUpstreamPolicy(policy)
 	if svc != nil {
 		s.overrideByAnnotation(svc.Annotations)
+		s.overrideDisabled(svc.Annotations, util.GetKongVersion())
 	}
 
 	if *s.Protocol == "grpc" || *s.Protocol == "grpcs" {
@@ -164,3 +413,39 @@ func (s *Service) override(
 		s.Path = nil
 	}
 }
+
+// removeDisabledRoutes detaches the routes generated from objects in
+// maintenance mode from the service.
+func (s *Service) removeDisabledRoutes() {
+	routes := make([]Route, 0, len(s.Routes))
+	for _, r := range s.Routes {
+		if !r.isDisabled() {
+			routes = append(routes, r)
+		}
+	}
+	s.Routes = routes
+}
+
+// overrideProtocolForGRPCWeb switches the protocol of a service whose routes
+// all handle gRPC-Web to its gRPC equivalent, as the grpc-web plugin proxies
+// the translated requests to a gRPC upstream.
+func (s *Service) overrideProtocolForGRPCWeb() {
+	if s == nil || s.Protocol == nil || len(s.Routes) == 0 {
+		return
+	}
+	for _, r := range s.Routes {
+		if !r.hasGRPCWebPlugin() {
+			return
+		}
+	}
+	switch *s.Protocol {
+	case "http":
+		s.Protocol = kong.String("grpc")
+	case "https":
+		s.Protocol = kong.String("grpcs")
+	default:
+		return
+	}
+	// grpc(s) doesn't accept a path
+	s.Path = nil
+}
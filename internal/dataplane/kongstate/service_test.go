@@ -5,11 +5,17 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/blang/semver/v4"
 	"github.com/kong/go-kong/kong"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
 	configurationv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+	configurationv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
 )
 
 func TestOverrideService(t *testing.T) {
@@ -368,7 +374,7 @@ func TestOverrideService(t *testing.T) {
 
 		k8sServices := testcase.inService.K8sServices
 		for _, svc := range k8sServices {
-			testcase.inService.override(log, &testcase.inKongIngresss, svc)
+			testcase.inService.override(log, &testcase.inKongIngresss, nil, svc)
 			assert.Equal(testcase.inService, testcase.outService)
 		}
 	}
@@ -378,7 +384,7 @@ func TestOverrideService(t *testing.T) {
 		log.SetOutput(ioutil.Discard)
 
 		var nilService *Service
-		nilService.override(log, nil, nil)
+		nilService.override(log, nil, nil, nil)
 	})
 }
 
@@ -444,3 +450,312 @@ func Test_overrideServicePath(t *testing.T) {
 		})
 	}
 }
+
+func Test_overrideServiceProtocolForGRPCWeb(t *testing.T) {
+	grpcWebRoute := Route{Plugins: []kong.Plugin{{Name: kong.String("grpc-web")}}}
+	tests := []struct {
+		name    string
+		service Service
+		want    Service
+	}{
+		{name: "basic empty service"},
+		{
+			name: "service without routes is left untouched",
+			service: Service{
+				Service: kong.Service{Protocol: kong.String("http")},
+			},
+			want: Service{
+				Service: kong.Service{Protocol: kong.String("http")},
+			},
+		},
+		{
+			name: "http service with only gRPC-Web routes uses grpc",
+			service: Service{
+				Service: kong.Service{Protocol: kong.String("http"), Path: kong.String("/")},
+				Routes:  []Route{grpcWebRoute},
+			},
+			want: Service{
+				Service: kong.Service{Protocol: kong.String("grpc")},
+				Routes:  []Route{grpcWebRoute},
+			},
+		},
+		{
+			name: "https service with only gRPC-Web routes uses grpcs",
+			service: Service{
+				Service: kong.Service{Protocol: kong.String("https")},
+				Routes:  []Route{grpcWebRoute},
+			},
+			want: Service{
+				Service: kong.Service{Protocol: kong.String("grpcs")},
+				Routes:  []Route{grpcWebRoute},
+			},
+		},
+		{
+			name: "service shared with plain HTTP routes is left untouched",
+			service: Service{
+				Service: kong.Service{Protocol: kong.String("http")},
+				Routes:  []Route{grpcWebRoute, {}},
+			},
+			want: Service{
+				Service: kong.Service{Protocol: kong.String("http")},
+				Routes:  []Route{grpcWebRoute, {}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.service.overrideProtocolForGRPCWeb()
+			assert.Equal(t, tt.want, tt.service)
+		})
+	}
+}
+
+func Test_overrideServiceTLSVerify(t *testing.T) {
+	t.Run("KongIngress", func(t *testing.T) {
+		s := Service{Service: kong.Service{Name: kong.String("foo")}}
+		s.overrideByKongIngress(&configurationv1.KongIngress{
+			Proxy: &configurationv1.KongIngressService{
+				TLSVerify:      kong.Bool(true),
+				TLSVerifyDepth: kong.Int(2),
+				CACertificates: []string{"ca-1", "ca-2"},
+			},
+		})
+		assert.Equal(t, kong.Service{
+			Name:           kong.String("foo"),
+			TLSVerify:      kong.Bool(true),
+			TLSVerifyDepth: kong.Int(2),
+			CACertificates: kong.StringSlice("ca-1", "ca-2"),
+		}, s.Service)
+	})
+
+	for _, tt := range []struct {
+		name            string
+		anns            map[string]string
+		wantTLSVerify   *bool
+		wantVerifyDepth *int
+	}{
+		{
+			name: "no annotations",
+		},
+		{
+			name: "valid annotations",
+			anns: map[string]string{
+				"konghq.com/tls-verify":       "True",
+				"konghq.com/tls-verify-depth": "3",
+			},
+			wantTLSVerify:   kong.Bool(true),
+			wantVerifyDepth: kong.Int(3),
+		},
+		{
+			name: "invalid annotations are ignored",
+			anns: map[string]string{
+				"konghq.com/tls-verify":       "yes please",
+				"konghq.com/tls-verify-depth": "-1",
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Service{Service: kong.Service{Name: kong.String("foo")}}
+			s.overrideByAnnotation(tt.anns)
+			assert.Equal(t, tt.wantTLSVerify, s.TLSVerify)
+			assert.Equal(t, tt.wantVerifyDepth, s.TLSVerifyDepth)
+		})
+	}
+}
+
+func TestOverrideServiceByKongUpstreamPolicy(t *testing.T) {
+	log := logrus.New()
+	log.SetOutput(ioutil.Discard)
+
+	kongIngress := &configurationv1.KongIngress{
+		Proxy: &configurationv1.KongIngressService{
+			ConnectTimeout: kong.Int(10),
+			Retries:        kong.Int(3),
+		},
+	}
+	policy := &configurationv1beta1.KongUpstreamPolicy{
+		Spec: configurationv1beta1.KongUpstreamPolicySpec{
+			Connections: &configurationv1beta1.KongUpstreamConnections{
+				ConnectTimeout: kong.Int(20),
+				ReadTimeout:    kong.Int(30),
+			},
+		},
+	}
+
+	s := Service{Service: kong.Service{
+		Name:         kong.String("foo"),
+		Protocol:     kong.String("http"),
+		WriteTimeout: kong.Int(60000),
+	}}
+	s.override(log, kongIngress, policy, nil)
+	assert.Equal(t, kong.Service{
+		Name:           kong.String("foo"),
+		Protocol:       kong.String("http"),
+		ConnectTimeout: kong.Int(20),
+		ReadTimeout:    kong.Int(30),
+		WriteTimeout:   kong.Int(60000),
+		Retries:        kong.Int(3),
+	}, s.Service, "the policy overrides the KongIngress, which still sets the fields the policy doesn't")
+
+	s = Service{Service: kong.Service{Name: kong.String("foo"), Protocol: kong.String("http")}}
+	s.override(log, nil, &configurationv1beta1.KongUpstreamPolicy{}, nil)
+	assert.Equal(t, kong.Service{Name: kong.String("foo"), Protocol: kong.String("http")}, s.Service,
+		"a policy without connection settings leaves the service unchanged")
+}
+
+func Test_overrideServiceDisabled(t *testing.T) {
+	routes := []Route{{Route: kong.Route{Name: kong.String("foo")}}}
+	for _, tt := range []struct {
+		name        string
+		anns        map[string]string
+		kongVersion semver.Version
+		wantEnabled *bool
+		wantRoutes  []Route
+	}{
+		{
+			name:        "no annotation",
+			kongVersion: semver.MustParse("2.8.0"),
+			wantRoutes:  routes,
+		},
+		{
+			name:        "disabled",
+			anns:        map[string]string{"konghq.com/disabled": "true"},
+			kongVersion: semver.MustParse("2.8.0"),
+			wantEnabled: kong.Bool(false),
+			wantRoutes:  routes,
+		},
+		{
+			name:        "explicitly enabled",
+			anns:        map[string]string{"konghq.com/disabled": "false"},
+			kongVersion: semver.MustParse("2.8.0"),
+			wantRoutes:  routes,
+		},
+		{
+			name:        "Kong versions which can't disable services lose the routes instead",
+			anns:        map[string]string{"konghq.com/disabled": "true"},
+			kongVersion: semver.MustParse("2.6.0"),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Service{Service: kong.Service{Name: kong.String("foo")}, Routes: routes}
+			s.overrideDisabled(tt.anns, tt.kongVersion)
+			assert.Equal(t, tt.wantEnabled, s.Enabled)
+			assert.Equal(t, tt.wantRoutes, s.Routes)
+		})
+	}
+}
+
+func Test_removeDisabledRoutes(t *testing.T) {
+	route := func(name string, anns map[string]string) Route {
+		return Route{
+			Route:   kong.Route{Name: kong.String(name)},
+			Ingress: util.K8sObjectInfo{Annotations: anns},
+		}
+	}
+	s := Service{Routes: []Route{
+		route("enabled", nil),
+		route("disabled", map[string]string{"konghq.com/disabled": "true"}),
+		route("invalid", map[string]string{"konghq.com/disabled": "maybe"}),
+	}}
+	s.removeDisabledRoutes()
+	assert.Equal(t, []Route{route("enabled", nil), route("invalid", map[string]string{"konghq.com/disabled": "maybe"})}, s.Routes)
+}
+
+func TestServiceTimeoutsFromAnnotations(t *testing.T) {
+	timeouts, err := ServiceTimeoutsFromAnnotations(map[string]string{
+		"konghq.com/connect-timeout": "5000",
+		"konghq.com/read-timeout":    "0",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ServiceTimeouts{Connect: kong.Int(5000), Read: kong.Int(0)}, timeouts)
+
+	for _, value := range []string{"", "5s", "-1", "2147483647"} {
+		timeouts, err := ServiceTimeoutsFromAnnotations(map[string]string{
+			"konghq.com/connect-timeout": "5000",
+			"konghq.com/write-timeout":   value,
+		})
+		assert.Error(t, err, "value %q", value)
+		assert.Equal(t, ServiceTimeouts{Connect: kong.Int(5000)}, timeouts, "the valid timeouts are provided")
+	}
+}
+
+func Test_overrideServiceTimeouts(t *testing.T) {
+	s := Service{Service: kong.Service{
+		ConnectTimeout: kong.Int(60000),
+		ReadTimeout:    kong.Int(60000),
+		WriteTimeout:   kong.Int(60000),
+	}}
+	s.overrideTimeouts(map[string]string{
+		"konghq.com/read-timeout":  "300000",
+		"konghq.com/write-timeout": "invalid",
+	})
+	assert.Equal(t, kong.Int(60000), s.ConnectTimeout)
+	assert.Equal(t, kong.Int(300000), s.ReadTimeout)
+	assert.Equal(t, kong.Int(60000), s.WriteTimeout, "invalid timeouts are ignored")
+}
+
+func Test_overrideTimeoutsByRoutes(t *testing.T) {
+	route := func(anns map[string]string) Route {
+		return Route{Ingress: util.K8sObjectInfo{Annotations: anns}}
+	}
+	s := Service{
+		Service: kong.Service{
+			ConnectTimeout: kong.Int(60000),
+			ReadTimeout:    kong.Int(60000),
+			WriteTimeout:   kong.Int(10000),
+		},
+		K8sServices: map[string]*corev1.Service{
+			"default/svc": {ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+				"konghq.com/write-timeout": "10000",
+			}}},
+		},
+		Routes: []Route{
+			route(map[string]string{"konghq.com/read-timeout": "300000", "konghq.com/write-timeout": "90000"}),
+			route(map[string]string{"konghq.com/read-timeout": "120000"}),
+			route(nil),
+		},
+	}
+	s.overrideTimeoutsByRoutes()
+	assert.Equal(t, kong.Int(60000), s.ConnectTimeout)
+	assert.Equal(t, kong.Int(300000), s.ReadTimeout, "the largest timeout of the routes applies")
+	assert.Equal(t, kong.Int(10000), s.WriteTimeout, "the timeouts of the Kubernetes services take precedence")
+}
+
+func Test_overrideServiceRetries(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		annotations map[string]string
+		want        *int
+	}{
+		{
+			name: "no annotation keeps the retries",
+			want: kong.Int(5),
+		},
+		{
+			name:        "retries can be disabled",
+			annotations: map[string]string{"konghq.com/retries": "0"},
+			want:        kong.Int(0),
+		},
+		{
+			name:        "retries can be increased",
+			annotations: map[string]string{"konghq.com/retries": "10"},
+			want:        kong.Int(10),
+		},
+		{
+			name:        "negative retries are ignored",
+			annotations: map[string]string{"konghq.com/retries": "-1"},
+			want:        kong.Int(5),
+		},
+		{
+			name:        "too many retries are ignored",
+			annotations: map[string]string{"konghq.com/retries": "32768"},
+			want:        kong.Int(5),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Service{Service: kong.Service{Retries: kong.Int(5)}}
+			s.overrideRetries(tt.annotations)
+			assert.Equal(t, tt.want, s.Retries)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package kongstate
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+	configurationv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+)
+
+// SourceTags provides the tags identifying the Kubernetes object the service
+// was translated from: the route object (e.g. HTTPRoute) it was generated for,
+// if any, or else its Kubernetes Service if it has a single one. Upstreams are
+// tagged like their service.
+func (s *Service) SourceTags() []*string {
+	if s.Parent != nil {
+		return util.GenerateTagsForObject(util.FromK8sObject(s.Parent))
+	}
+	if len(s.K8sServices) != 1 {
+		return nil
+	}
+	for _, k8sService := range s.K8sServices {
+		info := util.FromK8sObject(k8sService)
+		if info.GroupVersionKind.Kind == "" {
+			info.GroupVersionKind = corev1.SchemeGroupVersion.WithKind("Service")
+		}
+		return util.GenerateTagsForObject(info)
+	}
+	return nil
+}
+
+// SourceTags provides the tags identifying the Kubernetes object the route was
+// translated from.
+func (r *Route) SourceTags() []*string {
+	return util.GenerateTagsForObject(r.Ingress)
+}
+
+// SourceTags provides the tags identifying the KongConsumer the consumer was
+// translated from.
+func (c *Consumer) SourceTags() []*string {
+	info := util.FromK8sObject(&c.K8sKongConsumer)
+	if info.GroupVersionKind.Kind == "" {
+		info.GroupVersionKind = configurationv1.SchemeGroupVersion.WithKind("KongConsumer")
+	}
+	return util.GenerateTagsForObject(info)
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	"github.com/kong/go-kong/kong"
+
+	configurationv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
 )
 
 type PortMode int
@@ -47,6 +49,10 @@ type ServiceBackend struct {
 	Namespace string
 	PortDef   PortDef
 	Weight    *int32
+
+	// ServiceImport indicates that the backend is a multi-cluster ServiceImport
+	// rather than a Service.
+	ServiceImport bool
 }
 
 type ServiceBackends []ServiceBackend
@@ -78,4 +84,16 @@ func (c *Certificate) SanitizedCopy() *Certificate {
 // Plugin represetns a plugin Object in Kong.
 type Plugin struct {
 	kong.Plugin
+
+	// K8sNamespace is the namespace of the Kubernetes objects the plugin is
+	// configured for, empty for global plugins.
+	K8sNamespace string
+	// K8sName is the name of the KongPlugin (or KongClusterPlugin) the plugin
+	// was translated from.
+	K8sName string
+	// ClusterPlugin indicates that K8sName is the name of a KongClusterPlugin
+	// rather than of a KongPlugin.
+	ClusterPlugin bool
+	// Ordering is the ordering of the plugin, which go-kong doesn't support.
+	Ordering *configurationv1.PluginOrdering
 }
@@ -1,6 +1,8 @@
 package kongstate
 
 import (
+	"strconv"
+
 	"github.com/kong/go-kong/kong"
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
@@ -8,6 +10,7 @@ import (
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
 	configurationv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+	configurationv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
 )
 
 // Upstream is a wrapper around Upstream object in Kong.
@@ -29,13 +32,167 @@ func (u *Upstream) overrideHostHeader(anns map[string]string) {
 	u.HostHeader = kong.String(host)
 }
 
+// overrideHashOn configures consistent hashing of requests across targets
+// (e.g. for sticky sessions) from the upstream-hash-* annotations. Invalid
+// annotations are logged and ignored.
+func (u *Upstream) overrideHashOn(log logrus.FieldLogger, anns map[string]string) {
+	hashOn := annotations.ExtractUpstreamHashOn(anns)
+	if hashOn == "" {
+		return
+	}
+	var hashOnHeader, hashOnCookie string
+	switch hashOn {
+	case "none", "consumer", "ip":
+	case "header":
+		if hashOnHeader = annotations.ExtractUpstreamHashOnHeader(anns); hashOnHeader == "" {
+			log.Errorf("ignoring %s annotation: hashing on a header requires the %s annotation",
+				annotations.AnnotationPrefix+annotations.UpstreamHashOnKey,
+				annotations.AnnotationPrefix+annotations.UpstreamHashOnHeaderKey)
+			return
+		}
+	case "cookie":
+		if hashOnCookie = annotations.ExtractUpstreamHashOnCookie(anns); hashOnCookie == "" {
+			log.Errorf("ignoring %s annotation: hashing on a cookie requires the %s annotation",
+				annotations.AnnotationPrefix+annotations.UpstreamHashOnKey,
+				annotations.AnnotationPrefix+annotations.UpstreamHashOnCookieKey)
+			return
+		}
+	default:
+		log.Errorf("ignoring invalid %s annotation value %q", annotations.AnnotationPrefix+annotations.UpstreamHashOnKey, hashOn)
+		return
+	}
+
+	u.HashOn = kong.String(hashOn)
+	if hashOnHeader != "" {
+		u.HashOnHeader = kong.String(hashOnHeader)
+	}
+	if hashOnCookie != "" {
+		u.HashOnCookie = kong.String(hashOnCookie)
+		if path := annotations.ExtractUpstreamHashOnCookiePath(anns); path != "" {
+			u.HashOnCookiePath = kong.String(path)
+		}
+	}
+	if hashOn != "none" && u.Algorithm == nil {
+		u.Algorithm = kong.String("consistent-hashing")
+	}
+
+	// a cookie is always available as Kong sets it when it's missing, so
+	// there's nothing to fall back to
+	if hashOn == "none" || hashOn == "cookie" {
+		return
+	}
+	hashFallback := annotations.ExtractUpstreamHashFallback(anns)
+	switch hashFallback {
+	case "":
+		return
+	case "none", "consumer", "ip":
+		u.HashFallback = kong.String(hashFallback)
+	case "header":
+		hashFallbackHeader := annotations.ExtractUpstreamHashFallbackHeader(anns)
+		if hashFallbackHeader == "" {
+			log.Errorf("ignoring %s annotation: falling back to a header requires the %s annotation",
+				annotations.AnnotationPrefix+annotations.UpstreamHashFallbackKey,
+				annotations.AnnotationPrefix+annotations.UpstreamHashFallbackHeaderKey)
+			return
+		}
+		u.HashFallback = kong.String(hashFallback)
+		u.HashFallbackHeader = kong.String(hashFallbackHeader)
+	default:
+		log.Errorf("ignoring invalid %s annotation value %q", annotations.AnnotationPrefix+annotations.UpstreamHashFallbackKey, hashFallback)
+	}
+}
+
+// overrideHealthchecks configures active and passive health checks from the
+// healthchecks-* annotations, on top of the health checks set by KongIngress.
+// Annotations which aren't non-negative integers are logged and ignored.
+func (u *Upstream) overrideHealthchecks(log logrus.FieldLogger, anns map[string]string) {
+	count := func(key string, value string, ok bool) *int {
+		if !ok {
+			return nil
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			log.Errorf("ignoring invalid %s annotation value %q: must be a non-negative integer", annotations.AnnotationPrefix+key, value)
+			return nil
+		}
+		return kong.Int(n)
+	}
+	path := annotations.ExtractHealthchecksActivePath(anns)
+	value, ok := annotations.ExtractHealthchecksActiveInterval(anns)
+	interval := count(annotations.HealthchecksActiveIntervalKey, value, ok)
+	value, ok = annotations.ExtractHealthchecksActiveHealthySuccesses(anns)
+	activeSuccesses := count(annotations.HealthchecksActiveHealthySuccessesKey, value, ok)
+	value, ok = annotations.ExtractHealthchecksActiveUnhealthyFailures(anns)
+	activeFailures := count(annotations.HealthchecksActiveUnhealthyFailuresKey, value, ok)
+	value, ok = annotations.ExtractHealthchecksPassiveUnhealthyFailures(anns)
+	passiveFailures := count(annotations.HealthchecksPassiveUnhealthyFailuresKey, value, ok)
+	value, ok = annotations.ExtractHealthchecksPassiveUnhealthyTimeouts(anns)
+	passiveTimeouts := count(annotations.HealthchecksPassiveUnhealthyTimeoutsKey, value, ok)
+
+	active := path != "" || interval != nil || activeSuccesses != nil || activeFailures != nil
+	passive := passiveFailures != nil || passiveTimeouts != nil
+	if !active && !passive {
+		return
+	}
+
+	// the health checks may be shared with other upstreams configured by the
+	// same KongIngress
+	healthchecks := &kong.Healthcheck{}
+	if u.Healthchecks != nil {
+		healthchecks = u.Healthchecks.DeepCopy()
+	}
+	if active {
+		if healthchecks.Active == nil {
+			healthchecks.Active = &kong.ActiveHealthcheck{}
+		}
+		if healthchecks.Active.Healthy == nil {
+			healthchecks.Active.Healthy = &kong.Healthy{}
+		}
+		if healthchecks.Active.Unhealthy == nil {
+			healthchecks.Active.Unhealthy = &kong.Unhealthy{}
+		}
+		if path != "" {
+			healthchecks.Active.HTTPPath = kong.String(path)
+		}
+		if interval != nil {
+			healthchecks.Active.Healthy.Interval = interval
+			healthchecks.Active.Unhealthy.Interval = kong.Int(*interval)
+		}
+		if activeSuccesses != nil {
+			healthchecks.Active.Healthy.Successes = activeSuccesses
+		}
+		if activeFailures != nil {
+			healthchecks.Active.Unhealthy.HTTPFailures = activeFailures
+			healthchecks.Active.Unhealthy.TCPFailures = kong.Int(*activeFailures)
+		}
+	}
+	if passive {
+		if healthchecks.Passive == nil {
+			healthchecks.Passive = &kong.PassiveHealthcheck{}
+		}
+		if healthchecks.Passive.Unhealthy == nil {
+			healthchecks.Passive.Unhealthy = &kong.Unhealthy{}
+		}
+		if passiveFailures != nil {
+			healthchecks.Passive.Unhealthy.HTTPFailures = passiveFailures
+			healthchecks.Passive.Unhealthy.TCPFailures = kong.Int(*passiveFailures)
+		}
+		if passiveTimeouts != nil {
+			healthchecks.Passive.Unhealthy.Timeouts = passiveTimeouts
+		}
+	}
+	u.Healthchecks = healthchecks
+}
+
 // overrideByAnnotation modifies the Kong upstream based on annotations
 // on the Kubernetes service.
-func (u *Upstream) overrideByAnnotation(anns map[string]string) {
+func (u *Upstream) overrideByAnnotation(log logrus.FieldLogger, anns map[string]string) {
 	if u == nil {
 		return
 	}
 	u.overrideHostHeader(anns)
+	u.overrideHashOn(log, anns)
+	u.overrideHealthchecks(log, anns)
 }
 
 // overrideByKongIngress modifies the Kong upstream based on KongIngresses
@@ -82,10 +239,56 @@ func (u *Upstream) overrideByKongIngress(kongIngress *configurationv1.KongIngres
 	// TODO https://github.com/Kong/kubernetes-ingress-controller/issues/2075
 }
 
-// override sets Upstream fields by KongIngress first, then by k8s Service's annotations
+// overrideByKongUpstreamPolicy modifies the Kong upstream based on the
+// KongUpstreamPolicy associated with the Kubernetes service.
+func (u *Upstream) overrideByKongUpstreamPolicy(policy *configurationv1beta1.KongUpstreamPolicy) {
+	if u == nil || policy == nil {
+		return
+	}
+
+	spec := policy.Spec
+	if spec.Algorithm != nil {
+		u.Algorithm = kong.String(*spec.Algorithm)
+	}
+	if spec.Slots != nil {
+		u.Slots = kong.Int(*spec.Slots)
+	}
+	if spec.Healthchecks != nil {
+		u.Healthchecks = spec.Healthchecks.DeepCopy()
+	}
+	if hash := spec.HashOn; hash != nil && hash.Input != nil {
+		u.HashOn = kong.String(*hash.Input)
+		if hash.Header != nil {
+			u.HashOnHeader = kong.String(*hash.Header)
+		}
+		u.overrideHashOnCookie(hash)
+	}
+	if hash := spec.HashOnFallback; hash != nil && hash.Input != nil {
+		u.HashFallback = kong.String(*hash.Input)
+		if hash.Header != nil {
+			u.HashFallbackHeader = kong.String(*hash.Header)
+		}
+		u.overrideHashOnCookie(hash)
+	}
+}
+
+// overrideHashOnCookie sets the hashing cookie, which Kong shares between the
+// primary and the fallback hashing input.
+func (u *Upstream) overrideHashOnCookie(hash *configurationv1beta1.KongUpstreamHash) {
+	if hash.Cookie != nil {
+		u.HashOnCookie = kong.String(*hash.Cookie)
+	}
+	if hash.CookiePath != nil {
+		u.HashOnCookiePath = kong.String(*hash.CookiePath)
+	}
+}
+
+// override sets Upstream fields by KongIngress first, then by KongUpstreamPolicy
+// and finally by k8s Service's annotations
 func (u *Upstream) override(
 	log logrus.FieldLogger,
 	kongIngress *configurationv1.KongIngress,
+	policy *configurationv1beta1.KongUpstreamPolicy,
 	svc *corev1.Service,
 ) {
 	if u == nil {
@@ -119,7 +322,8 @@ func (u *Upstream) override(
 	}
 
 	u.overrideByKongIngress(kongIngress)
+	u.overrideByKongUpstreamPolicy(policy)
 	if svc != nil {
-		u.overrideByAnnotation(svc.Annotations)
+		u.overrideByAnnotation(log, svc.Annotations)
 	}
 }
@@ -11,6 +11,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	configurationv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+	configurationv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
 )
 
 func TestOverrideUpstream(t *testing.T) {
@@ -79,7 +80,7 @@ func TestOverrideUpstream(t *testing.T) {
 		log := logrus.New()
 		log.SetOutput(ioutil.Discard)
 
-		testcase.inUpstream.override(log, testcase.inKongIngresss, testcase.svc)
+		testcase.inUpstream.override(log, testcase.inKongIngresss, nil, testcase.svc)
 		assert.Equal(testcase.inUpstream, testcase.outUpstream)
 	}
 
@@ -88,6 +89,244 @@ func TestOverrideUpstream(t *testing.T) {
 		log.SetOutput(ioutil.Discard)
 
 		var nilUpstream *Upstream
-		nilUpstream.override(log, nil, nil)
+		nilUpstream.override(log, nil, nil, nil)
 	})
 }
+
+func TestOverrideUpstreamHashOn(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		anns map[string]string
+		in   kong.Upstream
+		out  kong.Upstream
+	}{
+		{
+			name: "no annotations",
+			anns: map[string]string{},
+		},
+		{
+			name: "ip with header fallback",
+			anns: map[string]string{
+				"konghq.com/upstream-hash-on":              "ip",
+				"konghq.com/upstream-hash-fallback":        "header",
+				"konghq.com/upstream-hash-fallback-header": "x-user",
+			},
+			out: kong.Upstream{
+				Algorithm:          kong.String("consistent-hashing"),
+				HashOn:             kong.String("ip"),
+				HashFallback:       kong.String("header"),
+				HashFallbackHeader: kong.String("x-user"),
+			},
+		},
+		{
+			name: "header",
+			anns: map[string]string{
+				"konghq.com/upstream-hash-on":        "header",
+				"konghq.com/upstream-hash-on-header": "x-session",
+			},
+			out: kong.Upstream{
+				Algorithm:    kong.String("consistent-hashing"),
+				HashOn:       kong.String("header"),
+				HashOnHeader: kong.String("x-session"),
+			},
+		},
+		{
+			name: "cookie ignores fallback",
+			anns: map[string]string{
+				"konghq.com/upstream-hash-on":             "cookie",
+				"konghq.com/upstream-hash-on-cookie":      "session",
+				"konghq.com/upstream-hash-on-cookie-path": "/app",
+				"konghq.com/upstream-hash-fallback":       "ip",
+			},
+			out: kong.Upstream{
+				Algorithm:        kong.String("consistent-hashing"),
+				HashOn:           kong.String("cookie"),
+				HashOnCookie:     kong.String("session"),
+				HashOnCookiePath: kong.String("/app"),
+			},
+		},
+		{
+			name: "algorithm set by KongIngress is kept",
+			anns: map[string]string{
+				"konghq.com/upstream-hash-on": "consumer",
+			},
+			in: kong.Upstream{
+				Algorithm: kong.String("least-connections"),
+			},
+			out: kong.Upstream{
+				Algorithm: kong.String("least-connections"),
+				HashOn:    kong.String("consumer"),
+			},
+		},
+		{
+			name: "header without a header name is ignored",
+			anns: map[string]string{
+				"konghq.com/upstream-hash-on": "header",
+			},
+		},
+		{
+			name: "cookie without a cookie name is ignored",
+			anns: map[string]string{
+				"konghq.com/upstream-hash-on": "cookie",
+			},
+		},
+		{
+			name: "invalid value is ignored",
+			anns: map[string]string{
+				"konghq.com/upstream-hash-on": "path",
+			},
+		},
+		{
+			name: "invalid fallback is ignored",
+			anns: map[string]string{
+				"konghq.com/upstream-hash-on":       "ip",
+				"konghq.com/upstream-hash-fallback": "cookie",
+			},
+			out: kong.Upstream{
+				Algorithm: kong.String("consistent-hashing"),
+				HashOn:    kong.String("ip"),
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			log := logrus.New()
+			log.SetOutput(ioutil.Discard)
+			u := Upstream{Upstream: tt.in}
+			u.overrideByAnnotation(log, tt.anns)
+			assert.Equal(t, tt.out, u.Upstream)
+		})
+	}
+}
+
+func TestOverrideUpstreamHealthchecks(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		anns map[string]string
+		in   *kong.Healthcheck
+		out  *kong.Healthcheck
+	}{
+		{
+			name: "no annotations",
+			anns: map[string]string{},
+		},
+		{
+			name: "active and passive",
+			anns: map[string]string{
+				"konghq.com/healthchecks-active-path":                "/healthz",
+				"konghq.com/healthchecks-active-interval":            "5",
+				"konghq.com/healthchecks-active-healthy-successes":   "2",
+				"konghq.com/healthchecks-active-unhealthy-failures":  "3",
+				"konghq.com/healthchecks-passive-unhealthy-failures": "4",
+				"konghq.com/healthchecks-passive-unhealthy-timeouts": "1",
+			},
+			out: &kong.Healthcheck{
+				Active: &kong.ActiveHealthcheck{
+					HTTPPath:  kong.String("/healthz"),
+					Healthy:   &kong.Healthy{Interval: kong.Int(5), Successes: kong.Int(2)},
+					Unhealthy: &kong.Unhealthy{Interval: kong.Int(5), HTTPFailures: kong.Int(3), TCPFailures: kong.Int(3)},
+				},
+				Passive: &kong.PassiveHealthcheck{
+					Unhealthy: &kong.Unhealthy{HTTPFailures: kong.Int(4), TCPFailures: kong.Int(4), Timeouts: kong.Int(1)},
+				},
+			},
+		},
+		{
+			name: "merged with KongIngress health checks",
+			anns: map[string]string{
+				"konghq.com/healthchecks-passive-unhealthy-timeouts": "2",
+			},
+			in: &kong.Healthcheck{
+				Active: &kong.ActiveHealthcheck{HTTPPath: kong.String("/status")},
+				Passive: &kong.PassiveHealthcheck{
+					Unhealthy: &kong.Unhealthy{HTTPFailures: kong.Int(5)},
+				},
+			},
+			out: &kong.Healthcheck{
+				Active: &kong.ActiveHealthcheck{HTTPPath: kong.String("/status")},
+				Passive: &kong.PassiveHealthcheck{
+					Unhealthy: &kong.Unhealthy{HTTPFailures: kong.Int(5), Timeouts: kong.Int(2)},
+				},
+			},
+		},
+		{
+			name: "invalid values are ignored",
+			anns: map[string]string{
+				"konghq.com/healthchecks-active-interval":            "often",
+				"konghq.com/healthchecks-passive-unhealthy-failures": "-1",
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			log := logrus.New()
+			log.SetOutput(ioutil.Discard)
+			var in *kong.Healthcheck
+			if tt.in != nil {
+				in = tt.in.DeepCopy()
+			}
+			u := Upstream{Upstream: kong.Upstream{Healthchecks: in}}
+			u.overrideByAnnotation(log, tt.anns)
+			assert.Equal(t, tt.out, u.Healthchecks)
+			if tt.in != nil {
+				assert.Equal(t, tt.in, in, "KongIngress health checks must not be modified")
+			}
+		})
+	}
+}
+
+func TestOverrideUpstreamByKongUpstreamPolicy(t *testing.T) {
+	log := logrus.New()
+	log.SetOutput(ioutil.Discard)
+
+	kongIngress := &configurationv1.KongIngress{
+		Upstream: &configurationv1.KongIngressUpstream{
+			Algorithm: kong.String("round-robin"),
+			Slots:     kong.Int(42),
+		},
+	}
+	policy := &configurationv1beta1.KongUpstreamPolicy{
+		Spec: configurationv1beta1.KongUpstreamPolicySpec{
+			Algorithm: kong.String("consistent-hashing"),
+			HashOn: &configurationv1beta1.KongUpstreamHash{
+				Input:  kong.String("header"),
+				Header: kong.String("x-user"),
+			},
+			HashOnFallback: &configurationv1beta1.KongUpstreamHash{
+				Input:      kong.String("cookie"),
+				Cookie:     kong.String("session"),
+				CookiePath: kong.String("/app"),
+			},
+			Healthchecks: &kong.Healthcheck{
+				Active: &kong.ActiveHealthcheck{HTTPPath: kong.String("/status")},
+			},
+		},
+	}
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				"konghq.com/healthchecks-active-path": "/healthz",
+			},
+		},
+	}
+
+	u := Upstream{Upstream: kong.Upstream{Name: kong.String("foo.com")}}
+	u.override(log, kongIngress, policy, svc)
+	assert.Equal(t, kong.Upstream{
+		Name:             kong.String("foo.com"),
+		Algorithm:        kong.String("consistent-hashing"),
+		Slots:            kong.Int(42),
+		HashOn:           kong.String("header"),
+		HashOnHeader:     kong.String("x-user"),
+		HashFallback:     kong.String("cookie"),
+		HashOnCookie:     kong.String("session"),
+		HashOnCookiePath: kong.String("/app"),
+		Healthchecks: &kong.Healthcheck{
+			Active: &kong.ActiveHealthcheck{
+				HTTPPath:  kong.String("/healthz"),
+				Healthy:   &kong.Healthy{},
+				Unhealthy: &kong.Unhealthy{},
+			},
+		},
+	}, u.Upstream)
+	assert.Equal(t, kong.String("/status"), policy.Spec.Healthchecks.Active.HTTPPath,
+		"KongUpstreamPolicy health checks must not be modified")
+}
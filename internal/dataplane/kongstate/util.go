@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	jsonpatch "github.com/evanphx/json-patch/v5"
 	"github.com/ghodss/yaml"
 	"github.com/kong/go-kong/kong"
 	corev1 "k8s.io/api/core/v1"
@@ -14,6 +15,7 @@ import (
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
 	configurationv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+	configurationv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
 )
 
 func getKongIngressForServices(
@@ -48,6 +50,23 @@ func getKongIngressForServices(
 	return nil, nil
 }
 
+// getKongUpstreamPolicyForServices provides the KongUpstreamPolicy attached to
+// a group of services. As with KongIngress, there can only be one policy for
+// the group, so the first one found is used.
+func getKongUpstreamPolicyForServices(
+	s store.Storer,
+	services map[string]*corev1.Service,
+) (*configurationv1beta1.KongUpstreamPolicy, error) {
+	for _, svc := range services {
+		policyName := annotations.ExtractUpstreamPolicyName(svc.Annotations)
+		if policyName == "" {
+			continue
+		}
+		return s.GetKongUpstreamPolicy(svc.Namespace, policyName)
+	}
+	return nil, nil
+}
+
 func getKongIngressFromObjectMeta(
 	s store.Storer,
 	obj util.K8sObjectInfo,
@@ -79,8 +98,7 @@ func getKongIngressFromObjAnnotations(
 }
 
 // getPlugin constructs a plugins from a KongPlugin resource.
-func getPlugin(s store.Storer, namespace, name string) (kong.Plugin, error) {
-	var plugin kong.Plugin
+func getPlugin(s store.Storer, namespace, name string, secretNamespaces []string) (Plugin, error) {
 	k8sPlugin, err := s.GetKongPlugin(namespace, name)
 	if err != nil {
 		// if no namespaced plugin definition, then
@@ -89,31 +107,32 @@ func getPlugin(s store.Storer, namespace, name string) (kong.Plugin, error) {
 			clusterPlugin, err := s.GetKongClusterPlugin(name)
 			// not found
 			if errors.As(err, &store.ErrNotFound{}) {
-				return plugin, errors.New(
+				return Plugin{}, errors.New(
 					"no KongPlugin or KongClusterPlugin was found")
 			}
 			if err != nil {
-				return plugin, err
+				return Plugin{}, err
 			}
 			if clusterPlugin.PluginName == "" {
-				return plugin, fmt.Errorf("invalid empty 'plugin' property")
+				return Plugin{}, fmt.Errorf("invalid empty 'plugin' property")
 			}
-			plugin, err = kongPluginFromK8SClusterPlugin(s, *clusterPlugin)
-			return plugin, err
+			plugin, err := kongPluginFromK8SClusterPlugin(s, *clusterPlugin, secretNamespaces)
+			return Plugin{Plugin: plugin, Ordering: clusterPlugin.Ordering, ClusterPlugin: true}, err
 		}
 	}
 	// ignore plugins with no name
 	if k8sPlugin.PluginName == "" {
-		return plugin, fmt.Errorf("invalid empty 'plugin' property")
+		return Plugin{}, fmt.Errorf("invalid empty 'plugin' property")
 	}
 
-	plugin, err = kongPluginFromK8SPlugin(s, *k8sPlugin)
-	return plugin, err
+	plugin, err := kongPluginFromK8SPlugin(s, *k8sPlugin)
+	return Plugin{Plugin: plugin, Ordering: k8sPlugin.Ordering}, err
 }
 
 func kongPluginFromK8SClusterPlugin(
 	s store.Storer,
-	k8sPlugin configurationv1.KongClusterPlugin) (kong.Plugin, error) {
+	k8sPlugin configurationv1.KongClusterPlugin,
+	secretNamespaces []string) (kong.Plugin, error) {
 	var config kong.Configuration
 	config, err := RawConfigToConfiguration(k8sPlugin.Config)
 	if err != nil {
@@ -125,7 +144,17 @@ func kongPluginFromK8SClusterPlugin(
 			fmt.Errorf("KongClusterPlugin '/%v' has both "+
 				"Config and ConfigFrom set", k8sPlugin.Name)
 	}
+	if k8sPlugin.ConfigFrom != nil && len(k8sPlugin.ConfigPatches) > 0 {
+		return kong.Plugin{},
+			fmt.Errorf("KongClusterPlugin '/%v' has both "+
+				"ConfigFrom and ConfigPatches set", k8sPlugin.Name)
+	}
 	if k8sPlugin.ConfigFrom != nil {
+		if !IsClusterPluginSecretNamespaceAllowed(k8sPlugin.ConfigFrom.SecretValue.Namespace, secretNamespaces) {
+			return kong.Plugin{},
+				fmt.Errorf("KongClusterPlugin %v may not reference Secrets in namespace %v",
+					k8sPlugin.Name, k8sPlugin.ConfigFrom.SecretValue.Namespace)
+		}
 		var err error
 		config, err = namespacedSecretToConfiguration(
 			s,
@@ -136,6 +165,20 @@ func kongPluginFromK8SClusterPlugin(
 					k8sPlugin.Name, err)
 		}
 	}
+	for _, patch := range k8sPlugin.ConfigPatches {
+		if patch.ValueFrom != nil &&
+			!IsClusterPluginSecretNamespaceAllowed(patch.ValueFrom.SecretValue.Namespace, secretNamespaces) {
+			return kong.Plugin{},
+				fmt.Errorf("KongClusterPlugin %v may not reference Secrets in namespace %v",
+					k8sPlugin.Name, patch.ValueFrom.SecretValue.Namespace)
+		}
+	}
+	config, err = ApplyConfigPatches(s, config, k8sPlugin.ConfigPatches)
+	if err != nil {
+		return kong.Plugin{},
+			fmt.Errorf("error patching config for KongClusterPlugin %v: %w",
+				k8sPlugin.Name, err)
+	}
 	kongPlugin := plugin{
 		Name:   k8sPlugin.PluginName,
 		Config: config,
@@ -176,6 +219,12 @@ func kongPluginFromK8SPlugin(
 				"Config and ConfigFrom set",
 				k8sPlugin.Namespace, k8sPlugin.Name)
 	}
+	if k8sPlugin.ConfigFrom != nil && len(k8sPlugin.ConfigPatches) > 0 {
+		return kong.Plugin{},
+			fmt.Errorf("KongPlugin '%v/%v' has both "+
+				"ConfigFrom and ConfigPatches set",
+				k8sPlugin.Namespace, k8sPlugin.Name)
+	}
 	if k8sPlugin.ConfigFrom != nil {
 		var err error
 		config, err = SecretToConfiguration(s,
@@ -186,6 +235,12 @@ func kongPluginFromK8SPlugin(
 					k8sPlugin.Name, k8sPlugin.Namespace, err)
 		}
 	}
+	config, err = ApplyConfigPatches(s, config, NamespacedConfigPatches(k8sPlugin.Namespace, k8sPlugin.ConfigPatches))
+	if err != nil {
+		return kong.Plugin{},
+			fmt.Errorf("error patching config for KongPlugin '%v/%v': %w",
+				k8sPlugin.Namespace, k8sPlugin.Name, err)
+	}
 	kongPlugin := plugin{
 		Name:   k8sPlugin.PluginName,
 		Config: config,
@@ -219,6 +274,21 @@ func namespacedSecretToConfiguration(
 	return SecretToConfiguration(s, bareReference, reference.Namespace)
 }
 
+// IsClusterPluginSecretNamespaceAllowed indicates whether KongClusterPlugins
+// may reference Secrets in the namespace, given the namespaces they're allowed
+// to reference Secrets in. Any namespace is allowed if none is given.
+func IsClusterPluginSecretNamespaceAllowed(namespace string, secretNamespaces []string) bool {
+	if len(secretNamespaces) == 0 {
+		return true
+	}
+	for _, allowed := range secretNamespaces {
+		if namespace == allowed {
+			return true
+		}
+	}
+	return false
+}
+
 type SecretGetter interface {
 	GetSecret(namespace, name string) (*corev1.Secret, error)
 }
@@ -251,6 +321,97 @@ func SecretToConfiguration(
 	return config, nil
 }
 
+// NamespacedConfigPatches qualifies the Secrets the config patches of a
+// KongPlugin reference with the namespace of the KongPlugin.
+func NamespacedConfigPatches(
+	namespace string,
+	patches []configurationv1.ConfigPatch) []configurationv1.NamespacedConfigPatch {
+	namespaced := make([]configurationv1.NamespacedConfigPatch, 0, len(patches))
+	for _, patch := range patches {
+		p := configurationv1.NamespacedConfigPatch{Path: patch.Path, Value: patch.Value}
+		if patch.ValueFrom != nil {
+			p.ValueFrom = &configurationv1.NamespacedConfigSource{
+				SecretValue: configurationv1.NamespacedSecretValueFromSource{
+					Namespace: namespace,
+					Secret:    patch.ValueFrom.SecretValue.Secret,
+					Key:       patch.ValueFrom.SecretValue.Key,
+				},
+			}
+		}
+		namespaced = append(namespaced, p)
+	}
+	return namespaced
+}
+
+// ApplyConfigPatches sets the fields of the plugin configuration the patches
+// point to, in order, creating missing parent objects. The value of each patch
+// is given either inline or by a Secret key, which must hold JSON.
+func ApplyConfigPatches(
+	s SecretGetter,
+	config kong.Configuration,
+	patches []configurationv1.NamespacedConfigPatch) (kong.Configuration, error) {
+	if len(patches) == 0 {
+		return config, nil
+	}
+	type operation struct {
+		Op    string          `json:"op"`
+		Path  string          `json:"path"`
+		Value json.RawMessage `json:"value"`
+	}
+	operations := make([]operation, 0, len(patches))
+	for _, patch := range patches {
+		if (patch.Value == nil) == (patch.ValueFrom == nil) {
+			return nil, fmt.Errorf("config patch %q must set exactly one of value and valueFrom", patch.Path)
+		}
+		var value []byte
+		if patch.Value != nil {
+			value = patch.Value.Raw
+		} else {
+			ref := patch.ValueFrom.SecretValue
+			secret, err := s.GetSecret(ref.Namespace, ref.Secret)
+			if err != nil {
+				return nil, fmt.Errorf("error fetching config patch secret '%v/%v': %w",
+					ref.Namespace, ref.Secret, err)
+			}
+			var ok bool
+			if value, ok = secret.Data[ref.Key]; !ok {
+				return nil, fmt.Errorf("no key '%v' in secret '%v/%v'", ref.Key, ref.Namespace, ref.Secret)
+			}
+			if !json.Valid(value) {
+				return nil, fmt.Errorf("key '%v' in secret '%v/%v' doesn't contain valid JSON",
+					ref.Key, ref.Namespace, ref.Secret)
+			}
+		}
+		operations = append(operations, operation{Op: "add", Path: patch.Path, Value: value})
+	}
+
+	rawPatch, err := json.Marshal(operations)
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := jsonpatch.DecodePatch(rawPatch)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		config = kong.Configuration{}
+	}
+	doc, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	options := jsonpatch.NewApplyOptions()
+	options.EnsurePathExistsOnAdd = true
+	if doc, err = decoded.ApplyWithOptions(doc, options); err != nil {
+		return nil, err
+	}
+	var patched kong.Configuration
+	if err := json.Unmarshal(doc, &patched); err != nil {
+		return nil, err
+	}
+	return patched, nil
+}
+
 // PrettyPrintServiceList makes a clean printable list of a map of Kubernetes
 // services for the purpose of logging (errors, info, e.t.c.).
 func PrettyPrintServiceList(services map[string]*corev1.Service) string {
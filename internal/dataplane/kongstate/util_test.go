@@ -30,12 +30,15 @@ func TestKongPluginFromK8SClusterPlugin(t *testing.T) {
 				},
 				Data: map[string][]byte{
 					"correlation-id-config": []byte(`{"header_name": "foo"}`),
+					"token":                 []byte(`"s3cr3t"`),
+					"plain-token":           []byte(`s3cr3t`),
 				},
 			},
 		},
 	})
 	type args struct {
-		plugin configurationv1.KongClusterPlugin
+		plugin           configurationv1.KongClusterPlugin
+		secretNamespaces []string
 	}
 	tests := []struct {
 		name    string
@@ -140,10 +143,105 @@ func TestKongPluginFromK8SClusterPlugin(t *testing.T) {
 			want:    kong.Plugin{},
 			wantErr: true,
 		},
+		{
+			name: "secret configuration in an allowed namespace",
+			args: args{
+				plugin: configurationv1.KongClusterPlugin{
+					Protocols:  []configurationv1.KongProtocol{"http"},
+					PluginName: "correlation-id",
+					ConfigFrom: &configurationv1.NamespacedConfigSource{
+						SecretValue: configurationv1.NamespacedSecretValueFromSource{
+							Key:       "correlation-id-config",
+							Secret:    "conf-secret",
+							Namespace: "default",
+						},
+					},
+				},
+				secretNamespaces: []string{"kong", "default"},
+			},
+			want: kong.Plugin{
+				Name: kong.String("correlation-id"),
+				Config: kong.Configuration{
+					"header_name": "foo",
+				},
+				Protocols: kong.StringSlice("http"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "secret configuration in a namespace which isn't allowed",
+			args: args{
+				plugin: configurationv1.KongClusterPlugin{
+					Protocols:  []configurationv1.KongProtocol{"http"},
+					PluginName: "correlation-id",
+					ConfigFrom: &configurationv1.NamespacedConfigSource{
+						SecretValue: configurationv1.NamespacedSecretValueFromSource{
+							Key:       "correlation-id-config",
+							Secret:    "conf-secret",
+							Namespace: "default",
+						},
+					},
+				},
+				secretNamespaces: []string{"kong"},
+			},
+			want:    kong.Plugin{},
+			wantErr: true,
+		},
+		{
+			name: "config patched with a secret value",
+			args: args{
+				plugin: configurationv1.KongClusterPlugin{
+					PluginName: "correlation-id",
+					Config: apiextensionsv1.JSON{
+						Raw: []byte(`{"header_name": "foo"}`),
+					},
+					ConfigPatches: []configurationv1.NamespacedConfigPatch{{
+						Path: "/auth/token",
+						ValueFrom: &configurationv1.NamespacedConfigSource{
+							SecretValue: configurationv1.NamespacedSecretValueFromSource{
+								Key:       "token",
+								Secret:    "conf-secret",
+								Namespace: "default",
+							},
+						},
+					}},
+				},
+				secretNamespaces: []string{"default"},
+			},
+			want: kong.Plugin{
+				Name: kong.String("correlation-id"),
+				Config: kong.Configuration{
+					"header_name": "foo",
+					"auth":        map[string]interface{}{"token": "s3cr3t"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "config patched with a secret value in a namespace which isn't allowed",
+			args: args{
+				plugin: configurationv1.KongClusterPlugin{
+					PluginName: "correlation-id",
+					ConfigPatches: []configurationv1.NamespacedConfigPatch{{
+						Path: "/auth/token",
+						ValueFrom: &configurationv1.NamespacedConfigSource{
+							SecretValue: configurationv1.NamespacedSecretValueFromSource{
+								Key:       "token",
+								Secret:    "conf-secret",
+								Namespace: "default",
+							},
+						},
+					}},
+				},
+				secretNamespaces: []string{"kong"},
+			},
+			want:    kong.Plugin{},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := kongPluginFromK8SClusterPlugin(store, tt.args.plugin)
+			got, err := kongPluginFromK8SClusterPlugin(store, tt.args.plugin, tt.args.secretNamespaces)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("kongPluginFromK8SClusterPlugin error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -164,6 +262,8 @@ func TestKongPluginFromK8SPlugin(t *testing.T) {
 				},
 				Data: map[string][]byte{
 					"correlation-id-config": []byte(`{"header_name": "foo"}`),
+					"token":                 []byte(`"s3cr3t"`),
+					"plain-token":           []byte(`s3cr3t`),
 				},
 			},
 		},
@@ -279,6 +379,102 @@ func TestKongPluginFromK8SPlugin(t *testing.T) {
 			want:    kong.Plugin{},
 			wantErr: true,
 		},
+		{
+			name: "config patched with inline and secret values",
+			args: args{
+				plugin: configurationv1.KongPlugin{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "foo",
+						Namespace: "default",
+					},
+					PluginName: "correlation-id",
+					Config: apiextensionsv1.JSON{
+						Raw: []byte(`{"header_name": "foo"}`),
+					},
+					ConfigPatches: []configurationv1.ConfigPatch{
+						{
+							Path:  "/header_name",
+							Value: &apiextensionsv1.JSON{Raw: []byte(`"bar"`)},
+						},
+						{
+							Path: "/auth/token",
+							ValueFrom: &configurationv1.ConfigSource{
+								SecretValue: configurationv1.SecretValueFromSource{
+									Key:    "token",
+									Secret: "conf-secret",
+								},
+							},
+						},
+					},
+				},
+			},
+			want: kong.Plugin{
+				Name: kong.String("correlation-id"),
+				Config: kong.Configuration{
+					"header_name": "bar",
+					"auth":        map[string]interface{}{"token": "s3cr3t"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "config patched with a secret value which isn't JSON",
+			args: args{
+				plugin: configurationv1.KongPlugin{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "foo",
+						Namespace: "default",
+					},
+					PluginName: "correlation-id",
+					ConfigPatches: []configurationv1.ConfigPatch{{
+						Path: "/auth/token",
+						ValueFrom: &configurationv1.ConfigSource{
+							SecretValue: configurationv1.SecretValueFromSource{
+								Key:    "plain-token",
+								Secret: "conf-secret",
+							},
+						},
+					}},
+				},
+			},
+			want:    kong.Plugin{},
+			wantErr: true,
+		},
+		{
+			name: "config patch without a value",
+			args: args{
+				plugin: configurationv1.KongPlugin{
+					PluginName:    "correlation-id",
+					ConfigPatches: []configurationv1.ConfigPatch{{Path: "/auth/token"}},
+				},
+			},
+			want:    kong.Plugin{},
+			wantErr: true,
+		},
+		{
+			name: "both ConfigFrom and ConfigPatches set",
+			args: args{
+				plugin: configurationv1.KongPlugin{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "foo",
+						Namespace: "default",
+					},
+					PluginName: "correlation-id",
+					ConfigFrom: &configurationv1.ConfigSource{
+						SecretValue: configurationv1.SecretValueFromSource{
+							Key:    "correlation-id-config",
+							Secret: "conf-secret",
+						},
+					},
+					ConfigPatches: []configurationv1.ConfigPatch{{
+						Path:  "/header_name",
+						Value: &apiextensionsv1.JSON{Raw: []byte(`"bar"`)},
+					}},
+				},
+			},
+			want:    kong.Plugin{},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
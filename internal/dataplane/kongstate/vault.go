@@ -0,0 +1,124 @@
+package kongstate
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
+	configurationv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
+)
+
+// builtinVaultPrefixes are the prefixes of the vaults Kong provides without
+// any vault entity: references can use the name of the backend directly.
+var builtinVaultPrefixes = map[string]struct{}{
+	"env": {},
+	"aws": {},
+	"gcp": {},
+	"hcv": {},
+}
+
+// vaultReferenceRegex matches references to secrets stored in a vault
+// ({vault://<prefix>/<secret>}) and captures their prefix.
+var vaultReferenceRegex = regexp.MustCompile(`^\{vault://([^/}]+)/[^}]+\}$`)
+
+// Vault represents a Kong vault, translated from a KongVault.
+type Vault struct {
+	Name        string                 `json:"name"`
+	Prefix      string                 `json:"prefix"`
+	Description string                 `json:"description,omitempty"`
+	Config      map[string]interface{} `json:"config,omitempty"`
+
+	K8sKongVault *configurationv1beta1.KongVault `json:"-"`
+}
+
+// SanitizedCopy returns a shallow copy with the configuration of the vault
+// backend, which can contain credentials, redacted.
+func (v *Vault) SanitizedCopy() *Vault {
+	res := *v
+	if v.Config != nil {
+		res.Config = make(map[string]interface{}, len(v.Config))
+		for k := range v.Config {
+			res.Config[k] = *redactedString
+		}
+	}
+	return &res
+}
+
+// VaultForKongVault translates a KongVault into a Kong vault.
+func VaultForKongVault(kongVault *configurationv1beta1.KongVault) (Vault, error) {
+	vault := Vault{
+		Name:         kongVault.Spec.Backend,
+		Prefix:       kongVault.Spec.Prefix,
+		Description:  kongVault.Spec.Description,
+		K8sKongVault: kongVault,
+	}
+	if len(kongVault.Spec.Config.Raw) > 0 {
+		if err := json.Unmarshal(kongVault.Spec.Config.Raw, &vault.Config); err != nil {
+			return Vault{}, fmt.Errorf("failed to parse the vault configuration: %w", err)
+		}
+	}
+	return vault, nil
+}
+
+// FillVaults translates the KongVaults into Kong vaults. When several
+// KongVaults use the same prefix, only the first one by name is translated.
+func (ks *KongState) FillVaults(log logrus.FieldLogger, s store.Storer) {
+	kongVaults, err := s.ListKongVaults()
+	if err != nil {
+		log.WithError(err).Error("failed to list KongVaults")
+		return
+	}
+
+	prefixes := make(map[string]string, len(kongVaults))
+	for _, kongVault := range kongVaults {
+		log := log.WithField("kongvault_name", kongVault.Name)
+		if owner, ok := prefixes[kongVault.Spec.Prefix]; ok {
+			log.Errorf("vault prefix %q is already used by KongVault %s", kongVault.Spec.Prefix, owner)
+			continue
+		}
+		vault, err := VaultForKongVault(kongVault)
+		if err != nil {
+			log.WithError(err).Error("failed to translate KongVault")
+			continue
+		}
+		prefixes[vault.Prefix] = kongVault.Name
+		ks.Vaults = append(ks.Vaults, vault)
+	}
+}
+
+// validateVaultReferences checks that the values of a credential which
+// reference secrets stored in a vault use either a built-in vault or one of
+// the vaults of the state.
+func (ks *KongState) validateVaultReferences(credConfig map[string]interface{}) error {
+	for k, v := range credConfig {
+		value, ok := v.(string)
+		if !ok {
+			continue
+		}
+		match := vaultReferenceRegex.FindStringSubmatch(value)
+		if match == nil {
+			continue
+		}
+		if !ks.hasVaultPrefix(match[1]) {
+			return fmt.Errorf("%s references unknown vault %q", k, match[1])
+		}
+	}
+	return nil
+}
+
+// hasVaultPrefix indicates whether references with the given prefix can be
+// resolved by Kong.
+func (ks *KongState) hasVaultPrefix(prefix string) bool {
+	if _, ok := builtinVaultPrefixes[prefix]; ok {
+		return true
+	}
+	for _, vault := range ks.Vaults {
+		if vault.Prefix == prefix {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package kongstate
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
+	configurationv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+	configurationv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
+)
+
+func kongVault(name, class, backend, prefix, config string) *configurationv1beta1.KongVault {
+	vault := &configurationv1beta1.KongVault{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: configurationv1beta1.KongVaultSpec{
+			Backend: backend,
+			Prefix:  prefix,
+			Config:  apiextensionsv1.JSON{Raw: []byte(config)},
+		},
+	}
+	if class != "" {
+		vault.Annotations = map[string]string{annotations.IngressClassKey: class}
+	}
+	return vault
+}
+
+func TestFillVaults(t *testing.T) {
+	s, err := store.NewFakeStore(store.FakeObjects{
+		KongVaults: []*configurationv1beta1.KongVault{
+			kongVault("aws-eu", annotations.DefaultIngressClass, "aws", "aws-eu", `{"region":"eu-west-1"}`),
+			kongVault("aws-eu-duplicate", annotations.DefaultIngressClass, "aws", "aws-eu", `{"region":"eu-west-2"}`),
+			kongVault("hcv", annotations.DefaultIngressClass, "hcv", "secrets", ""),
+			kongVault("invalid", annotations.DefaultIngressClass, "env", "invalid", `[]`),
+			kongVault("other-class", "other", "env", "other", ""),
+		},
+	})
+	require.NoError(t, err)
+
+	var state KongState
+	state.FillVaults(logrus.New(), s)
+	require.Len(t, state.Vaults, 2)
+	assert.Equal(t, "aws", state.Vaults[0].Name)
+	assert.Equal(t, "aws-eu", state.Vaults[0].Prefix)
+	assert.Equal(t, map[string]interface{}{"region": "eu-west-1"}, state.Vaults[0].Config)
+	assert.Equal(t, "aws-eu", state.Vaults[0].K8sKongVault.Name)
+	assert.Equal(t, "hcv", state.Vaults[1].Name)
+	assert.Equal(t, "secrets", state.Vaults[1].Prefix)
+	assert.Nil(t, state.Vaults[1].Config)
+}
+
+func Test_FillConsumersAndCredentials_VaultReferences(t *testing.T) {
+	keyAuthSecret := func(name, key string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Data: map[string][]byte{
+				"kongCredType": []byte("key-auth"),
+				"key":          []byte(key),
+			},
+		}
+	}
+	s, err := store.NewFakeStore(store.FakeObjects{
+		Secrets: []*corev1.Secret{
+			keyAuthSecret("custom-vault", "{vault://aws-eu/consumers/foo}"),
+			keyAuthSecret("builtin-vault", "{vault://env/foo-key}"),
+			keyAuthSecret("unknown-vault", "{vault://unknown/foo}"),
+		},
+		KongConsumers: []*configurationv1.KongConsumer{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "foo",
+					Namespace:   "default",
+					Annotations: map[string]string{annotations.IngressClassKey: annotations.DefaultIngressClass},
+				},
+				Username:    "foo",
+				Credentials: []string{"custom-vault", "builtin-vault", "unknown-vault"},
+			},
+		},
+		KongVaults: []*configurationv1beta1.KongVault{
+			kongVault("aws-eu", annotations.DefaultIngressClass, "aws", "aws-eu", ""),
+		},
+	})
+	require.NoError(t, err)
+
+	var state KongState
+	state.FillVaults(logrus.New(), s)
+	state.FillConsumersAndCredentials(logrus.New(), s)
+	require.Len(t, state.Consumers, 1)
+	var keys []string
+	for _, keyAuth := range state.Consumers[0].KeyAuths {
+		keys = append(keys, *keyAuth.Key)
+	}
+	assert.Equal(t, []string{"{vault://aws-eu/consumers/foo}", "{vault://env/foo-key}"}, keys,
+		"credentials referencing unknown vaults are skipped")
+}
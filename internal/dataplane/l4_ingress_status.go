@@ -0,0 +1,131 @@
+package dataplane
+
+import (
+	"context"
+
+	"github.com/kong/go-kong/kong"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
+	configurationv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
+)
+
+// -----------------------------------------------------------------------------
+// Dataplane Client - TCPIngress and UDPIngress Status
+// -----------------------------------------------------------------------------
+
+// L4IngressStatusUpdater updates the status of the TCPIngresses and
+// UDPIngresses after each translation.
+type L4IngressStatusUpdater interface {
+	UpdateL4IngressStatus(ctx context.Context, ingress client.Object) error
+}
+
+// ClientL4IngressStatusUpdater updates the status subresource of
+// TCPIngresses and UDPIngresses through a Kubernetes client.
+type ClientL4IngressStatusUpdater struct {
+	Client client.Client
+}
+
+// UpdateL4IngressStatus writes the status of the TCPIngress or UDPIngress.
+func (u *ClientL4IngressStatusUpdater) UpdateL4IngressStatus(ctx context.Context, ingress client.Object) error {
+	return u.Client.Status().Update(ctx, ingress)
+}
+
+// updateL4IngressStatuses reports in the status of the TCPIngresses and
+// UDPIngresses whether rules of theirs are ignored because of port conflicts
+// and whether the data-plane listens on the ports of their rules. Only
+// statuses which changed are written. Failures are only logged, as the
+// statuses will be computed again on the next update.
+func (c *KongClient) updateL4IngressStatuses(ctx context.Context, storer store.Storer, conflicts []parser.PortConflict) {
+	updater := c.L4IngressStatusUpdater()
+	if updater == nil {
+		return
+	}
+	tcpIngresses, err := storer.ListTCPIngresses()
+	if err != nil {
+		c.logger.WithError(err).Error("failed to list TCPIngresses")
+	}
+	udpIngresses, err := storer.ListUDPIngresses()
+	if err != nil {
+		c.logger.WithError(err).Error("failed to list UDPIngresses")
+	}
+	if len(tcpIngresses) == 0 && len(udpIngresses) == 0 {
+		return
+	}
+
+	messages := portConflictMessages(conflicts)
+	// the stream listeners are left out of the statuses until they can be
+	// retrieved, rather than reporting every port as missing
+	listeners, err := c.streamListeners(ctx)
+	if err != nil {
+		c.logger.WithError(err).Error("failed to retrieve the stream listeners of the data-plane")
+	}
+	conditions := func(ingress client.Object, current []metav1.Condition) []metav1.Condition {
+		conditions := append([]metav1.Condition(nil), current...)
+		meta.SetStatusCondition(&conditions, portConflictCondition(ingress, messages))
+		if listeners != nil {
+			meta.SetStatusCondition(&conditions, streamListenersCondition(ingress, listeners))
+		}
+		return conditions
+	}
+
+	for _, ingress := range tcpIngresses {
+		status := *ingress.Status.DeepCopy()
+		status.Conditions = conditions(ingress, status.Conditions)
+		if equality.Semantic.DeepEqual(ingress.Status, status) {
+			continue
+		}
+		ingress = ingress.DeepCopy()
+		ingress.Status = status
+		c.updateL4IngressStatus(ctx, updater, ingress)
+	}
+	for _, ingress := range udpIngresses {
+		status := *ingress.Status.DeepCopy()
+		status.Conditions = conditions(ingress, status.Conditions)
+		if equality.Semantic.DeepEqual(ingress.Status, status) {
+			continue
+		}
+		ingress = ingress.DeepCopy()
+		ingress.Status = status
+		c.updateL4IngressStatus(ctx, updater, ingress)
+	}
+}
+
+// updateL4IngressStatus writes the status of a TCPIngress or UDPIngress,
+// logging failures.
+func (c *KongClient) updateL4IngressStatus(ctx context.Context, updater L4IngressStatusUpdater, ingress client.Object) {
+	timedCtx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+	if err := updater.UpdateL4IngressStatus(timedCtx, ingress); err != nil {
+		c.logger.WithField("namespace", ingress.GetNamespace()).WithField("name", ingress.GetName()).WithError(err).
+			Errorf("failed to update the status of %s", l4IngressKind(ingress))
+	}
+}
+
+// streamListeners retrieves the stream listeners of the data-plane, which
+// are never nil when retrieved.
+func (c *KongClient) streamListeners(ctx context.Context) ([]kong.StreamListener, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+	_, listeners, err := c.Listeners(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if listeners == nil {
+		listeners = []kong.StreamListener{}
+	}
+	return listeners, nil
+}
+
+// l4IngressKind provides the kind of a TCPIngress or UDPIngress, which objects
+// from the cache lack the type information of.
+func l4IngressKind(ingress client.Object) string {
+	if _, ok := ingress.(*configurationv1beta1.UDPIngress); ok {
+		return "UDPIngress"
+	}
+	return "TCPIngress"
+}
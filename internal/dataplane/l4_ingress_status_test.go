@@ -0,0 +1,115 @@
+package dataplane
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/sendconfig"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
+	configurationv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
+)
+
+type fakeL4IngressStatusUpdater struct {
+	updated []client.Object
+}
+
+func (u *fakeL4IngressStatusUpdater) UpdateL4IngressStatus(_ context.Context, ingress client.Object) error {
+	u.updated = append(u.updated, ingress)
+	return nil
+}
+
+func TestUpdateL4IngressStatuses(t *testing.T) {
+	rootAvailable := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !rootAvailable {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{"configuration":{"stream_listeners":[{"ip":"0.0.0.0","port":9000},` +
+			`{"ip":"0.0.0.0","port":53,"udp":true}]}}`))
+	}))
+	defer server.Close()
+	kongClient, err := kong.NewClient(kong.String(server.URL), server.Client())
+	require.NoError(t, err)
+
+	objectMeta := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "default",
+			UID:         types.UID("uid-" + name),
+			Annotations: map[string]string{annotations.IngressClassKey: annotations.DefaultIngressClass},
+		}
+	}
+	backend := configurationv1beta1.IngressBackend{ServiceName: "svc", ServicePort: 80}
+	winner := &configurationv1beta1.TCPIngress{
+		ObjectMeta: objectMeta("winner"),
+		Spec:       configurationv1beta1.TCPIngressSpec{Rules: []configurationv1beta1.IngressRule{{Port: 9000, Backend: backend}}},
+	}
+	loser := &configurationv1beta1.TCPIngress{
+		ObjectMeta: objectMeta("loser"),
+		Spec:       configurationv1beta1.TCPIngressSpec{Rules: []configurationv1beta1.IngressRule{{Port: 9000, Backend: backend}}},
+	}
+	udpIngress := &configurationv1beta1.UDPIngress{
+		ObjectMeta: objectMeta("dns"),
+		Spec:       configurationv1beta1.UDPIngressSpec{Rules: []configurationv1beta1.UDPIngressRule{{Port: 5353, Backend: backend}}},
+	}
+	conflicts := []parser.PortConflict{{Object: loser, Winner: winner, Message: "rule 0 ignored"}}
+	s, err := store.NewFakeStore(store.FakeObjects{
+		TCPIngresses: []*configurationv1beta1.TCPIngress{winner, loser},
+		UDPIngresses: []*configurationv1beta1.UDPIngress{udpIngress},
+	})
+	require.NoError(t, err)
+	updater := &fakeL4IngressStatusUpdater{}
+	c := &KongClient{
+		logger:                 logrus.New(),
+		kongConfig:             sendconfig.Kong{Client: kongClient},
+		requestTimeout:         time.Second,
+		l4IngressStatusUpdater: updater,
+	}
+	ctx := context.Background()
+
+	t.Log("verifying that the statuses report the conflicts and the missing stream listeners")
+	c.updateL4IngressStatuses(ctx, s, conflicts)
+	require.Len(t, updater.updated, 3)
+	statuses := make(map[string][]metav1.Condition)
+	for _, obj := range updater.updated {
+		switch obj := obj.(type) {
+		case *configurationv1beta1.TCPIngress:
+			statuses[obj.Name] = obj.Status.Conditions
+		case *configurationv1beta1.UDPIngress:
+			statuses[obj.Name] = obj.Status.Conditions
+		}
+	}
+	assert.True(t, meta.IsStatusConditionTrue(statuses["loser"], configurationv1beta1.IngressConditionPortConflict))
+	assert.False(t, meta.IsStatusConditionTrue(statuses["winner"], configurationv1beta1.IngressConditionPortConflict))
+	assert.True(t, meta.IsStatusConditionTrue(statuses["winner"], configurationv1beta1.IngressConditionStreamListenersReady))
+	assert.False(t, meta.IsStatusConditionTrue(statuses["dns"], configurationv1beta1.IngressConditionStreamListenersReady))
+
+	t.Log("verifying that statuses which don't change aren't written")
+	updater.updated = nil
+	winner.Status.Conditions = statuses["winner"]
+	c.updateL4IngressStatuses(ctx, s, conflicts)
+	assert.Len(t, updater.updated, 2)
+
+	t.Log("verifying that the stream listeners are left out of the statuses until they can be retrieved")
+	updater.updated = nil
+	rootAvailable = false
+	loser.Status.Conditions = statuses["loser"]
+	c.updateL4IngressStatuses(ctx, s, conflicts)
+	require.Len(t, updater.updated, 1)
+	assert.Equal(t, "dns", updater.updated[0].GetName())
+}
@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"github.com/kong/go-kong/kong"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+)
+
+// -----------------------------------------------------------------------------
+// Parser - Application Protocols
+// -----------------------------------------------------------------------------
+
+// appProtocols maps the application protocols of Service ports to the protocols
+// of the Kong services proxying to them.
+var appProtocols = map[string]string{
+	"http":              "http",
+	"https":             "https",
+	"grpc":              "grpc",
+	"grpcs":             "grpcs",
+	"ws":                "ws",
+	"wss":               "wss",
+	"kubernetes.io/ws":  "ws",
+	"kubernetes.io/wss": "wss",
+}
+
+// appProtocolRoutes are the protocols of the routes of Kong services with the
+// given protocol, if they differ from the default ones.
+var appProtocolRoutes = map[string][]string{
+	"grpc":  {"grpc", "grpcs"},
+	"grpcs": {"grpc", "grpcs"},
+	"ws":    {"ws", "wss"},
+	"wss":   {"ws", "wss"},
+}
+
+// inferServiceProtocol sets the protocol of an HTTP Kong service from the
+// appProtocol of the ports of its backends, if they all agree on one, and the
+// protocols of its routes accordingly. Routes which don't use the default
+// protocols are left untouched. Nothing is inferred for Kubernetes services
+// whose protocol is configured through the konghq.com/protocol annotation or
+// a KongIngress.
+func inferServiceProtocol(log logrus.FieldLogger, service *kongstate.Service) {
+	if service.Protocol == nil || *service.Protocol != "http" || len(service.Backends) == 0 {
+		return
+	}
+
+	var protocol string
+	for _, backend := range service.Backends {
+		k8sService, ok := service.K8sServices[backend.Name]
+		if !ok {
+			return
+		}
+		if annotations.ExtractProtocolName(k8sService.Annotations) != "" ||
+			annotations.ExtractConfigurationName(k8sService.Annotations) != "" {
+			return
+		}
+		port, err := findPort(k8sService, backend.PortDef)
+		if err != nil || port.AppProtocol == nil {
+			return
+		}
+		backendProtocol, ok := appProtocols[*port.AppProtocol]
+		if !ok {
+			return
+		}
+		if protocol != "" && protocol != backendProtocol {
+			log.WithField("service_name", *service.Name).Warnf(
+				"backends have different application protocols (%s, %s), not inferring the service protocol",
+				protocol, backendProtocol)
+			return
+		}
+		protocol = backendProtocol
+	}
+
+	service.Protocol = kong.String(protocol)
+	routeProtocols, ok := appProtocolRoutes[protocol]
+	if !ok {
+		return
+	}
+	for i := range service.Routes {
+		if hasDefaultRouteProtocols(service.Routes[i]) {
+			service.Routes[i].Protocols = kong.StringSlice(routeProtocols...)
+		}
+	}
+}
+
+// hasDefaultRouteProtocols indicates whether a route uses the default http and
+// https protocols.
+func hasDefaultRouteProtocols(route kongstate.Route) bool {
+	if len(route.Protocols) == 0 {
+		return true
+	}
+	if len(route.Protocols) != 2 {
+		return false
+	}
+	protocols := make(map[string]struct{}, 2)
+	for _, protocol := range route.Protocols {
+		if protocol != nil {
+			protocols[*protocol] = struct{}{}
+		}
+	}
+	_, http := protocols["http"]
+	_, https := protocols["https"]
+	return http && https
+}
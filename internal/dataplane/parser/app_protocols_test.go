@@ -0,0 +1,111 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+)
+
+func TestInferServiceProtocol(t *testing.T) {
+	k8sService := func(name string, appProtocol string, anns map[string]string) *corev1.Service {
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: anns},
+			Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 80}}},
+		}
+		if appProtocol != "" {
+			svc.Spec.Ports[0].AppProtocol = kong.String(appProtocol)
+		}
+		return svc
+	}
+	service := func(protocol string, k8sServices ...*corev1.Service) *kongstate.Service {
+		s := &kongstate.Service{
+			Service: kong.Service{Name: kong.String("default.foo.80"), Protocol: kong.String(protocol)},
+			Routes: []kongstate.Route{
+				{Route: kong.Route{Protocols: kong.StringSlice("http", "https")}},
+				{Route: kong.Route{Protocols: kong.StringSlice("https")}},
+			},
+			K8sServices: make(map[string]*corev1.Service),
+		}
+		for _, svc := range k8sServices {
+			s.Backends = append(s.Backends, kongstate.ServiceBackend{
+				Name:    svc.Name,
+				PortDef: kongstate.PortDef{Mode: kongstate.PortModeByNumber, Number: 80},
+			})
+			s.K8sServices[svc.Name] = svc
+		}
+		return s
+	}
+	routeProtocols := func(s *kongstate.Service) [][]string {
+		var res [][]string
+		for _, route := range s.Routes {
+			var protocols []string
+			for _, protocol := range route.Protocols {
+				protocols = append(protocols, *protocol)
+			}
+			res = append(res, protocols)
+		}
+		return res
+	}
+
+	for _, tt := range []struct {
+		name               string
+		service            *kongstate.Service
+		wantProtocol       string
+		wantRouteProtocols [][]string
+	}{
+		{
+			name:               "grpc services get grpc routes",
+			service:            service("http", k8sService("foo", "grpc", nil)),
+			wantProtocol:       "grpc",
+			wantRouteProtocols: [][]string{{"grpc", "grpcs"}, {"https"}},
+		},
+		{
+			name:               "websocket services get websocket routes",
+			service:            service("http", k8sService("foo", "kubernetes.io/wss", nil)),
+			wantProtocol:       "wss",
+			wantRouteProtocols: [][]string{{"ws", "wss"}, {"https"}},
+		},
+		{
+			name:               "https services keep their routes",
+			service:            service("http", k8sService("foo", "https", nil), k8sService("bar", "https", nil)),
+			wantProtocol:       "https",
+			wantRouteProtocols: [][]string{{"http", "https"}, {"https"}},
+		},
+		{
+			name:               "backends with different application protocols aren't inferred",
+			service:            service("http", k8sService("foo", "grpc", nil), k8sService("bar", "https", nil)),
+			wantProtocol:       "http",
+			wantRouteProtocols: [][]string{{"http", "https"}, {"https"}},
+		},
+		{
+			name:               "unknown application protocols aren't inferred",
+			service:            service("http", k8sService("foo", "example.com/custom", nil)),
+			wantProtocol:       "http",
+			wantRouteProtocols: [][]string{{"http", "https"}, {"https"}},
+		},
+		{
+			name:               "the protocol annotation takes precedence",
+			service:            service("http", k8sService("foo", "grpc", map[string]string{"konghq.com/protocol": "https"})),
+			wantProtocol:       "http",
+			wantRouteProtocols: [][]string{{"http", "https"}, {"https"}},
+		},
+		{
+			name:               "non-http services aren't inferred",
+			service:            service("tcp", k8sService("foo", "grpc", nil)),
+			wantProtocol:       "tcp",
+			wantRouteProtocols: [][]string{{"http", "https"}, {"https"}},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			inferServiceProtocol(logrus.New(), tt.service)
+			assert.Equal(t, tt.wantProtocol, *tt.service.Protocol)
+			assert.Equal(t, tt.wantRouteProtocols, routeProtocols(tt.service))
+		})
+	}
+}
@@ -2,6 +2,8 @@ package parser
 
 import (
 	"fmt"
+	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/kong/go-kong/kong"
@@ -9,15 +11,28 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
 )
 
 type ingressRules struct {
 	SecretNameToSNIs      SecretNameToSNIs
 	ServiceNameToServices map[string]kongstate.Service
+
+	// DefaultCertificateCandidates are the Secrets referenced by Ingress TLS
+	// sections without hosts, one of which is served to clients that don't
+	// send SNI.
+	DefaultCertificateCandidates []defaultCertificateCandidate
+
+	// SNIClaims are the SNIs the TLS sections of Kubernetes objects request
+	// the certificates of Secrets for, which tell which object binds each SNI
+	// to a Secret when several claim it.
+	SNIClaims []sniClaim
 }
 
 func newIngressRules() ingressRules {
@@ -27,6 +42,59 @@ func newIngressRules() ingressRules {
 	}
 }
 
+// routeToExternalUpstreams moves the routes of the Ingresses selecting a Kong
+// upstream managed outside of Kubernetes with the konghq.com/upstream-name
+// annotation to services proxying to that upstream, instead of the services
+// of their backends. Services left without routes are dropped.
+func (ir ingressRules) routeToExternalUpstreams() ingressRules {
+	for key, service := range ir.ServiceNameToServices {
+		if service.ExternalUpstream || len(service.Routes) == 0 {
+			continue
+		}
+		var routes []kongstate.Route
+		for _, route := range service.Routes {
+			upstreamName := annotations.ExtractUpstreamName(route.Ingress.Annotations)
+			if upstreamName == "" {
+				routes = append(routes, route)
+				continue
+			}
+			// Kubernetes names can't contain underscores, so this name can't
+			// collide with the names of services generated for backends
+			serviceName := route.Ingress.Namespace + ".upstream_" + upstreamName
+			externalService, ok := ir.ServiceNameToServices[serviceName]
+			if !ok {
+				externalService = kongstate.Service{
+					Service: kong.Service{
+						Name:           kong.String(serviceName),
+						Host:           kong.String(upstreamName),
+						Port:           kong.Int(DefaultHTTPPort),
+						Protocol:       kong.String("http"),
+						Path:           kong.String("/"),
+						ConnectTimeout: kong.Int(DefaultServiceTimeout),
+						ReadTimeout:    kong.Int(DefaultServiceTimeout),
+						WriteTimeout:   kong.Int(DefaultServiceTimeout),
+						Retries:        kong.Int(DefaultRetries),
+					},
+					Namespace:        route.Ingress.Namespace,
+					ExternalUpstream: true,
+				}
+			}
+			externalService.Routes = append(externalService.Routes, route)
+			ir.ServiceNameToServices[serviceName] = externalService
+		}
+		if len(routes) == len(service.Routes) {
+			continue
+		}
+		if len(routes) == 0 {
+			delete(ir.ServiceNameToServices, key)
+			continue
+		}
+		service.Routes = routes
+		ir.ServiceNameToServices[key] = service
+	}
+	return ir
+}
+
 func mergeIngressRules(objs ...ingressRules) ingressRules {
 	result := newIngressRules()
 
@@ -37,6 +105,8 @@ func mergeIngressRules(objs ...ingressRules) ingressRules {
 		for k, v := range obj.ServiceNameToServices {
 			result.ServiceNameToServices[k] = v
 		}
+		result.DefaultCertificateCandidates = append(result.DefaultCertificateCandidates, obj.DefaultCertificateCandidates...)
+		result.SNIClaims = append(result.SNIClaims, obj.SNIClaims...)
 	}
 	return result
 }
@@ -85,8 +155,17 @@ func (ir *ingressRules) populateServices(log logrus.FieldLogger, s store.Storer)
 					}).Errorf("failed to fetch secret: %v", err)
 				}
 			}
+
+			// extract the CA certificates used to verify the TLS certificate of the upstream
+			if caSecretNames := annotations.ExtractCACertificates(k8sService.Annotations); len(caSecretNames) > 0 {
+				service.CACertificates = getCACertificateIDs(log, s, k8sService.Namespace, caSecretNames)
+			}
 		}
 
+		// the application protocol of the Kubernetes services is known now, so
+		// the protocol of the Kong Service (and its routes) can be inferred.
+		inferServiceProtocol(log, &service)
+
 		// Kubernetes Services have been populated for this Kong Service, so it can
 		// now be cached.
 		ir.ServiceNameToServices[key] = service
@@ -95,6 +174,43 @@ func (ir *ingressRules) populateServices(log logrus.FieldLogger, s store.Storer)
 	return nil
 }
 
+// getCACertificateIDs provides the IDs of the CA certificates stored in the
+// given Secrets, skipping the Secrets which aren't valid CA certificates. Names
+// which don't match a Secret in the namespace are resolved as KongCACertificates.
+func getCACertificateIDs(log logrus.FieldLogger, s store.Storer, namespace string, secretNames []string) []*string {
+	ids := make([]*string, 0, len(secretNames))
+	for _, secretName := range secretNames {
+		log := log.WithFields(logrus.Fields{
+			"secret_name":      secretName,
+			"secret_namespace": namespace,
+		})
+		secret, err := s.GetSecret(namespace, secretName)
+		if err != nil {
+			kongCACert, kerr := s.GetKongCACertificate(secretName)
+			if kerr != nil {
+				log.Errorf("failed to fetch CA certificate secret: %v", err)
+				continue
+			}
+			caCerts, kerr := kongstate.CACertificatesForKongCACertificate(s, kongCACert)
+			if kerr != nil {
+				log.Errorf("invalid KongCACertificate: %v", kerr)
+				continue
+			}
+			for _, caCert := range caCerts {
+				ids = append(ids, caCert.ID)
+			}
+			continue
+		}
+		id, ok := secret.Data["id"]
+		if !ok {
+			log.Errorf("invalid CA certificate: missing 'id' field in data")
+			continue
+		}
+		ids = append(ids, kong.String(string(id)))
+	}
+	return ids
+}
+
 type SecretNameToSNIs map[string][]string
 
 func newSecretNameToSNIs() SecretNameToSNIs {
@@ -128,6 +244,269 @@ func (m SecretNameToSNIs) addFromIngressV1TLS(tlsSections []networkingv1.Ingress
 	}
 }
 
+// addFromCertManager binds the hosts lacking TLS of an Ingress or HTTPRoute
+// requesting a cert-manager certificate to the Secret it's issued to. Until
+// the certificate is issued, the missing Secret is skipped.
+func (m SecretNameToSNIs) addFromCertManager(obj client.Object) {
+	if _, ok := util.CertManagerIssuerFromAnnotations(obj.GetAnnotations()); !ok {
+		return
+	}
+	m.addFromIngressV1TLS([]networkingv1.IngressTLS{{
+		Hosts:      util.CertManagerHosts(obj),
+		SecretName: util.CertManagerSecretName(obj),
+	}}, obj.GetNamespace())
+}
+
+// sniClaim is the request of a Kubernetes object for the certificate of a
+// Secret to be served for an SNI.
+type sniClaim struct {
+	sni        string
+	secretName string
+	owner      client.Object
+}
+
+// sniConflict is an SNI claim ignored because the claim of another object,
+// which takes precedence, binds the SNI to another Secret.
+type sniConflict struct {
+	ignored sniClaim
+	served  sniClaim
+}
+
+// addTLSFromIngressV1beta1 binds the hosts of the TLS sections of owner to
+// their Secrets, and records the claims of owner for them.
+func (ir *ingressRules) addTLSFromIngressV1beta1(tlsSections []networkingv1beta1.IngressTLS, owner client.Object) {
+	var v1 []networkingv1.IngressTLS
+	for _, item := range tlsSections {
+		v1 = append(v1, networkingv1.IngressTLS{Hosts: item.Hosts, SecretName: item.SecretName})
+	}
+	ir.addTLSFromIngressV1(v1, owner)
+}
+
+// addTLSFromIngressV1 binds the hosts of the TLS sections of owner to their
+// Secrets, and records the claims of owner for them.
+func (ir *ingressRules) addTLSFromIngressV1(tlsSections []networkingv1.IngressTLS, owner client.Object) {
+	ir.SecretNameToSNIs.addFromIngressV1TLS(tlsSections, owner.GetNamespace())
+	ir.addSNIClaims(tlsSections, owner)
+}
+
+// addTLSFromCertManager binds the hosts lacking TLS of an Ingress or HTTPRoute
+// requesting a cert-manager certificate to the Secret it's issued to, and
+// records the claims of the object for them.
+func (ir *ingressRules) addTLSFromCertManager(obj client.Object) {
+	if _, ok := util.CertManagerIssuerFromAnnotations(obj.GetAnnotations()); !ok {
+		return
+	}
+	ir.SecretNameToSNIs.addFromCertManager(obj)
+	ir.addSNIClaims([]networkingv1.IngressTLS{{
+		Hosts:      util.CertManagerHosts(obj),
+		SecretName: util.CertManagerSecretName(obj),
+	}}, obj)
+}
+
+func (ir *ingressRules) addSNIClaims(tlsSections []networkingv1.IngressTLS, owner client.Object) {
+	for _, tls := range tlsSections {
+		if tls.SecretName == "" {
+			continue
+		}
+		for _, host := range tls.Hosts {
+			ir.SNIClaims = append(ir.SNIClaims, sniClaim{
+				sni:        host,
+				secretName: owner.GetNamespace() + "/" + tls.SecretName,
+				owner:      owner,
+			})
+		}
+	}
+}
+
+// resolveSNIConflicts binds each SNI claimed for several Secrets to the Secret
+// of the claim which takes precedence, regardless of the order the objects
+// were translated in, and provides the claims ignored as a result. The claim
+// of the oldest object takes precedence, ties are broken by the namespace,
+// name and kind of the objects, then by the order of the TLS sections of the
+// object. Wildcard SNIs don't conflict with the SNIs they match, as Kong
+// prefers exact matches.
+func (ir *ingressRules) resolveSNIConflicts() []sniConflict {
+	claims := make([]sniClaim, len(ir.SNIClaims))
+	copy(claims, ir.SNIClaims)
+	sort.SliceStable(claims, func(i, j int) bool {
+		return sniClaimPrecedes(claims[i], claims[j])
+	})
+
+	served := make(map[string]sniClaim)
+	reported := make(map[string]bool)
+	var conflicts []sniConflict
+	for _, claim := range claims {
+		owner, ok := served[claim.sni]
+		if !ok {
+			served[claim.sni] = claim
+			continue
+		}
+		key := fmt.Sprintf("%s/%s/%s/%s", objectKind(claim.owner), claim.owner.GetNamespace(), claim.owner.GetName(), claim.sni)
+		if owner.secretName == claim.secretName || reported[key] {
+			continue
+		}
+		reported[key] = true
+		conflicts = append(conflicts, sniConflict{ignored: claim, served: owner})
+	}
+
+	for secretName, snis := range ir.SecretNameToSNIs {
+		kept := make([]string, 0, len(snis))
+		for _, sni := range snis {
+			if owner, ok := served[sni]; ok && owner.secretName != secretName {
+				continue
+			}
+			kept = append(kept, sni)
+		}
+		ir.SecretNameToSNIs[secretName] = kept
+	}
+	for sni, owner := range served {
+		var bound bool
+		for _, boundSNI := range ir.SecretNameToSNIs[owner.secretName] {
+			if boundSNI == sni {
+				bound = true
+				break
+			}
+		}
+		if !bound {
+			ir.SecretNameToSNIs[owner.secretName] = append(ir.SecretNameToSNIs[owner.secretName], sni)
+		}
+	}
+	return conflicts
+}
+
+// sniClaimPrecedes determines whether claim a takes precedence over claim b.
+func sniClaimPrecedes(a, b sniClaim) bool {
+	return objectPrecedes(a.owner, b.owner)
+}
+
+// objectPrecedes determines whether object a takes precedence over object b
+// when both claim the same thing: the oldest object wins, ties being broken
+// by namespace, name and kind so that the outcome is deterministic.
+func objectPrecedes(a, b client.Object) bool {
+	aCreated, bCreated := a.GetCreationTimestamp(), b.GetCreationTimestamp()
+	if !aCreated.Equal(&bCreated) {
+		return aCreated.Before(&bCreated)
+	}
+	if a.GetNamespace() != b.GetNamespace() {
+		return a.GetNamespace() < b.GetNamespace()
+	}
+	if a.GetName() != b.GetName() {
+		return a.GetName() < b.GetName()
+	}
+	return objectKind(a) < objectKind(b)
+}
+
+// objectKind provides the kind of obj, which objects from the cache may lack
+// the type information of.
+func objectKind(obj client.Object) string {
+	if kind := obj.GetObjectKind().GroupVersionKind().Kind; kind != "" {
+		return kind
+	}
+	return reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+}
+
+// defaultSNI is the SNI of the certificate Kong serves to clients which don't
+// send SNI, or send one that no other certificate matches.
+const defaultSNI = "*"
+
+// defaultCertificateCandidate is a Secret referenced by an Ingress TLS section
+// without hosts.
+type defaultCertificateCandidate struct {
+	secretName        string
+	ingressNamespace  string
+	ingressName       string
+	creationTimestamp metav1.Time
+}
+
+func (ir *ingressRules) addDefaultCertificateCandidatesFromIngressV1beta1TLS(
+	tlsSections []networkingv1beta1.IngressTLS,
+	ingress metav1.ObjectMeta,
+) {
+	var v1 []networkingv1.IngressTLS
+	for _, item := range tlsSections {
+		v1 = append(v1, networkingv1.IngressTLS{Hosts: item.Hosts, SecretName: item.SecretName})
+	}
+	ir.addDefaultCertificateCandidatesFromIngressV1TLS(v1, ingress)
+}
+
+func (ir *ingressRules) addDefaultCertificateCandidatesFromIngressV1TLS(
+	tlsSections []networkingv1.IngressTLS,
+	ingress metav1.ObjectMeta,
+) {
+	for _, tls := range tlsSections {
+		if len(tls.Hosts) != 0 || tls.SecretName == "" {
+			continue
+		}
+		ir.DefaultCertificateCandidates = append(ir.DefaultCertificateCandidates, defaultCertificateCandidate{
+			secretName:        ingress.Namespace + "/" + tls.SecretName,
+			ingressNamespace:  ingress.Namespace,
+			ingressName:       ingress.Name,
+			creationTimestamp: ingress.CreationTimestamp,
+		})
+	}
+}
+
+// assignDefaultCertificate binds the default SNI to the Secret of a TLS section
+// without hosts, so that the same certificate is served to clients which don't
+// send SNI on every translation. The Secret of the oldest Ingress wins, ties
+// are broken by the Ingress namespace and name, then by the order of its TLS
+// sections. Nothing changes when a TLS section lists the default SNI as a host.
+func (ir *ingressRules) assignDefaultCertificate(log logrus.FieldLogger) {
+	if len(ir.DefaultCertificateCandidates) == 0 {
+		return
+	}
+	for _, snis := range ir.SecretNameToSNIs {
+		for _, sni := range snis {
+			if sni == defaultSNI {
+				return
+			}
+		}
+	}
+
+	candidates := ir.DefaultCertificateCandidates
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if !a.creationTimestamp.Equal(&b.creationTimestamp) {
+			return a.creationTimestamp.Before(&b.creationTimestamp)
+		}
+		if a.ingressNamespace != b.ingressNamespace {
+			return a.ingressNamespace < b.ingressNamespace
+		}
+		return a.ingressName < b.ingressName
+	})
+
+	selected := candidates[0]
+	ir.SecretNameToSNIs[selected.secretName] = append(ir.SecretNameToSNIs[selected.secretName], defaultSNI)
+	for _, candidate := range candidates[1:] {
+		if candidate.secretName == selected.secretName {
+			continue
+		}
+		log.WithFields(logrus.Fields{
+			"ingress_namespace":           candidate.ingressNamespace,
+			"ingress_name":                candidate.ingressName,
+			"secret":                      candidate.secretName,
+			"default_certificate_secret":  selected.secretName,
+			"default_certificate_ingress": selected.ingressNamespace + "/" + selected.ingressName,
+		}).Warn("TLS section without hosts ignored: the default certificate is taken from an older Ingress")
+	}
+}
+
+// assignFallbackDefaultCertificate binds the default SNI to the given Secret,
+// if any, unless a TLS section already provides the default certificate.
+func (ir *ingressRules) assignFallbackDefaultCertificate(secretName string) {
+	if secretName == "" {
+		return
+	}
+	for _, snis := range ir.SecretNameToSNIs {
+		for _, sni := range snis {
+			if sni == defaultSNI {
+				return
+			}
+		}
+	}
+	ir.SecretNameToSNIs[secretName] = append(ir.SecretNameToSNIs[secretName], defaultSNI)
+}
+
 func (m SecretNameToSNIs) filterHosts(hosts []string) []string {
 	hostsToAdd := []string{}
 	seenHosts := map[string]bool{}
@@ -162,7 +541,13 @@ func getK8sServicesForBackends(
 		if backend.Namespace != "" {
 			backendNamespace = backend.Namespace
 		}
-		k8sService, err := storer.GetService(backendNamespace, backend.Name)
+		var k8sService *corev1.Service
+		var err error
+		if backend.ServiceImport {
+			k8sService, err = getServiceForServiceImport(storer, backendNamespace, backend.Name)
+		} else {
+			k8sService, err = storer.GetService(backendNamespace, backend.Name)
+		}
 		if err != nil {
 			log.WithFields(logrus.Fields{
 				"service_name":      backend.PortDef.Name,
@@ -3,16 +3,21 @@ package parser
 import (
 	"bytes"
 	"testing"
+	"time"
 
+	"github.com/kong/go-kong/kong"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	networking "k8s.io/api/networking/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
+	configurationv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
 )
 
 func TestMergeIngressRules(t *testing.T) {
@@ -467,3 +472,212 @@ func Test_doK8sServicesMatchAnnotations(t *testing.T) {
 		})
 	}
 }
+
+func Test_getCACertificateIDs(t *testing.T) {
+	s, err := store.NewFakeStore(store.FakeObjects{
+		Secrets: []*corev1.Secret{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "ca-1", Namespace: "default"},
+				Data:       map[string][]byte{"id": []byte("8214a145-a328-4c56-ab72-2973a56d4eae"), "cert": []byte("cert")},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "no-id", Namespace: "default"},
+				Data:       map[string][]byte{"cert": []byte("cert")},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "ca-2", Namespace: "other"},
+				Data:       map[string][]byte{"id": []byte("f2d4b8d5-8fb4-4ee4-9d6a-9b9f4f4e2d5c"), "cert": []byte("cert")},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "bundle", Namespace: "other"},
+				Data:       map[string][]byte{"ca.crt": []byte(caCert1)},
+			},
+		},
+		KongCACertificates: []*configurationv1beta1.KongCACertificate{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-ca"},
+				Spec: configurationv1beta1.KongCACertificateSpec{
+					SecretRef: configurationv1beta1.KongCACertificateSecretRef{Namespace: "other", Name: "bundle"},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	kongCACert, err := s.GetKongCACertificate("cluster-ca")
+	require.NoError(t, err)
+	clusterCACerts, err := kongstate.CACertificatesForKongCACertificate(s, kongCACert)
+	require.NoError(t, err)
+	require.Len(t, clusterCACerts, 1)
+
+	ids := getCACertificateIDs(logrus.New(), s, "default", []string{"ca-1", "no-id", "ca-2", "missing", "cluster-ca"})
+	assert.Equal(t, []*string{kong.String("8214a145-a328-4c56-ab72-2973a56d4eae"), clusterCACerts[0].ID}, ids)
+}
+
+func Test_assignDefaultCertificate(t *testing.T) {
+	older := metav1.NewTime(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+	newer := metav1.NewTime(time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC))
+	ingress := func(namespace, name string, created metav1.Time) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Namespace: namespace, Name: name, CreationTimestamp: created}
+	}
+	hostless := func(secretNames ...string) []networkingv1.IngressTLS {
+		var tls []networkingv1.IngressTLS
+		for _, secretName := range secretNames {
+			tls = append(tls, networkingv1.IngressTLS{SecretName: secretName})
+		}
+		return tls
+	}
+
+	t.Run("oldest Ingress wins", func(t *testing.T) {
+		ir := newIngressRules()
+		ir.addDefaultCertificateCandidatesFromIngressV1TLS(hostless("newer"), ingress("default", "a", newer))
+		ir.addDefaultCertificateCandidatesFromIngressV1TLS(hostless("older", "older-2"), ingress("default", "b", older))
+		ir.assignDefaultCertificate(logrus.New())
+		assert.Equal(t, SecretNameToSNIs{"default/older": {"*"}}, ir.SecretNameToSNIs)
+	})
+
+	t.Run("ties are broken by namespace and name", func(t *testing.T) {
+		ir := newIngressRules()
+		ir.addDefaultCertificateCandidatesFromIngressV1TLS(hostless("b"), ingress("ns", "b", older))
+		ir.addDefaultCertificateCandidatesFromIngressV1TLS(hostless("a"), ingress("ns", "a", older))
+		ir.addDefaultCertificateCandidatesFromIngressV1TLS(hostless("other"), ingress("other", "a", older))
+		ir.assignDefaultCertificate(logrus.New())
+		assert.Equal(t, SecretNameToSNIs{"ns/a": {"*"}}, ir.SecretNameToSNIs)
+	})
+
+	t.Run("TLS sections with hosts aren't candidates", func(t *testing.T) {
+		ir := newIngressRules()
+		tls := []networkingv1.IngressTLS{{Hosts: []string{"example.com"}, SecretName: "with-hosts"}}
+		ir.SecretNameToSNIs.addFromIngressV1TLS(tls, "default")
+		ir.addDefaultCertificateCandidatesFromIngressV1TLS(tls, ingress("default", "a", older))
+		ir.assignDefaultCertificate(logrus.New())
+		assert.Equal(t, SecretNameToSNIs{"default/with-hosts": {"example.com"}}, ir.SecretNameToSNIs)
+	})
+
+	t.Run("explicit default SNI is kept", func(t *testing.T) {
+		ir := newIngressRules()
+		ir.SecretNameToSNIs.addFromIngressV1TLS([]networkingv1.IngressTLS{{Hosts: []string{"*"}, SecretName: "explicit"}}, "default")
+		ir.addDefaultCertificateCandidatesFromIngressV1TLS(hostless("hostless"), ingress("default", "a", older))
+		ir.assignDefaultCertificate(logrus.New())
+		assert.Equal(t, SecretNameToSNIs{"default/explicit": {"*"}}, ir.SecretNameToSNIs)
+	})
+
+	t.Run("fallback default certificate is used without candidates", func(t *testing.T) {
+		ir := newIngressRules()
+		ir.SecretNameToSNIs.addFromIngressV1TLS([]networkingv1.IngressTLS{{Hosts: []string{"example.com"}, SecretName: "with-hosts"}}, "default")
+		ir.assignDefaultCertificate(logrus.New())
+		ir.assignFallbackDefaultCertificate("kong/default-cert")
+		assert.Equal(t, SecretNameToSNIs{
+			"default/with-hosts": {"example.com"},
+			"kong/default-cert":  {"*"},
+		}, ir.SecretNameToSNIs)
+	})
+
+	t.Run("candidates take precedence over the fallback default certificate", func(t *testing.T) {
+		ir := newIngressRules()
+		ir.addDefaultCertificateCandidatesFromIngressV1TLS(hostless("hostless"), ingress("default", "a", older))
+		ir.assignDefaultCertificate(logrus.New())
+		ir.assignFallbackDefaultCertificate("kong/default-cert")
+		assert.Equal(t, SecretNameToSNIs{"default/hostless": {"*"}}, ir.SecretNameToSNIs)
+	})
+}
+
+func TestAddFromCertManager(t *testing.T) {
+	ingress := func(anns map[string]string) *networkingv1.Ingress {
+		return &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo", Annotations: anns},
+			Spec: networkingv1.IngressSpec{
+				TLS: []networkingv1.IngressTLS{{Hosts: []string{"secure.example.com"}, SecretName: "secure"}},
+				Rules: []networkingv1.IngressRule{
+					{Host: "secure.example.com"},
+					{Host: "b.example.com"},
+					{Host: "a.example.com"},
+				},
+			},
+		}
+	}
+
+	t.Run("hosts lacking TLS are bound to the issued Secret", func(t *testing.T) {
+		m := SecretNameToSNIs{}
+		m.addFromCertManager(ingress(map[string]string{"konghq.com/cert-manager-cluster-issuer": "letsencrypt"}))
+		assert.Equal(t, SecretNameToSNIs{"default/kong-ingress-foo": {"a.example.com", "b.example.com"}}, m)
+	})
+
+	t.Run("objects not requesting a certificate are skipped", func(t *testing.T) {
+		m := SecretNameToSNIs{}
+		m.addFromCertManager(ingress(nil))
+		assert.Empty(t, m)
+	})
+
+	t.Run("HTTPRoute hostnames are bound to the issued Secret", func(t *testing.T) {
+		m := SecretNameToSNIs{}
+		m.addFromCertManager(&gatewayv1alpha2.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "default",
+				Name:        "foo",
+				Annotations: map[string]string{"konghq.com/cert-manager-issuer": "letsencrypt"},
+			},
+			Spec: gatewayv1alpha2.HTTPRouteSpec{Hostnames: []gatewayv1alpha2.Hostname{"example.com"}},
+		})
+		assert.Equal(t, SecretNameToSNIs{"default/kong-httproute-foo": {"example.com"}}, m)
+	})
+}
+
+func Test_resolveSNIConflicts(t *testing.T) {
+	older := metav1.NewTime(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+	newer := metav1.NewTime(time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC))
+	ingress := func(namespace, name string, created metav1.Time) *networkingv1.Ingress {
+		return &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, CreationTimestamp: created}}
+	}
+	tls := func(secretName string, hosts ...string) []networkingv1.IngressTLS {
+		return []networkingv1.IngressTLS{{Hosts: hosts, SecretName: secretName}}
+	}
+
+	t.Run("oldest object wins regardless of the translation order", func(t *testing.T) {
+		newerIngress, olderIngress := ingress("default", "newer", newer), ingress("default", "older", older)
+		ir := newIngressRules()
+		ir.addTLSFromIngressV1(tls("newer-cert", "example.com", "newer.example.com"), newerIngress)
+		ir.addTLSFromIngressV1(tls("older-cert", "example.com"), olderIngress)
+		conflicts := ir.resolveSNIConflicts()
+		assert.Equal(t, SecretNameToSNIs{
+			"default/newer-cert": {"newer.example.com"},
+			"default/older-cert": {"example.com"},
+		}, ir.SecretNameToSNIs)
+		require.Len(t, conflicts, 1)
+		assert.Equal(t, "example.com", conflicts[0].ignored.sni)
+		assert.Equal(t, newerIngress, conflicts[0].ignored.owner)
+		assert.Equal(t, olderIngress, conflicts[0].served.owner)
+		assert.Equal(t, "default/older-cert", conflicts[0].served.secretName)
+	})
+
+	t.Run("ties are broken by namespace and name", func(t *testing.T) {
+		ir := newIngressRules()
+		ir.addTLSFromIngressV1(tls("b-cert", "example.com"), ingress("ns", "b", older))
+		ir.addTLSFromIngressV1(tls("other-cert", "example.com"), ingress("other", "a", older))
+		ir.addTLSFromIngressV1(tls("a-cert", "example.com"), ingress("ns", "a", older))
+		assert.Len(t, ir.resolveSNIConflicts(), 2)
+		assert.Equal(t, SecretNameToSNIs{
+			"ns/a-cert":        {"example.com"},
+			"ns/b-cert":        {},
+			"other/other-cert": {},
+		}, ir.SecretNameToSNIs)
+	})
+
+	t.Run("the same Secret claimed by several objects isn't a conflict", func(t *testing.T) {
+		ir := newIngressRules()
+		ir.addTLSFromIngressV1(tls("cert", "example.com"), ingress("default", "a", newer))
+		ir.addTLSFromIngressV1(tls("cert", "example.com"), ingress("default", "b", older))
+		assert.Empty(t, ir.resolveSNIConflicts())
+		assert.Equal(t, SecretNameToSNIs{"default/cert": {"example.com"}}, ir.SecretNameToSNIs)
+	})
+
+	t.Run("wildcard SNIs don't conflict with the SNIs they match", func(t *testing.T) {
+		ir := newIngressRules()
+		ir.addTLSFromIngressV1(tls("wildcard-cert", "*.example.com"), ingress("default", "a", older))
+		ir.addTLSFromIngressV1(tls("cert", "foo.example.com"), ingress("default", "b", newer))
+		assert.Empty(t, ir.resolveSNIConflicts())
+		assert.Equal(t, SecretNameToSNIs{
+			"default/wildcard-cert": {"*.example.com"},
+			"default/cert":          {"foo.example.com"},
+		}, ir.SecretNameToSNIs)
+	})
+}
@@ -0,0 +1,258 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser/translators"
+	configurationv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
+)
+
+// -----------------------------------------------------------------------------
+// Parser - Namespace Quotas
+// -----------------------------------------------------------------------------
+
+// enforceRouteQuota drops the routes of namespaces which exceed the maximum
+// number of routes. Routes are kept in order of their names so that the same
+// routes are dropped on every translation.
+func (p *Parser) enforceRouteQuota(state *kongstate.KongState) {
+	if p.namespaceQuotas.MaxRoutes <= 0 {
+		return
+	}
+
+	type routeRef struct {
+		service, route int
+		name           string
+	}
+	routesByNamespace := make(map[string][]routeRef)
+	for i, service := range state.Services {
+		for j, route := range service.Routes {
+			name := ""
+			if route.Name != nil {
+				name = *route.Name
+			}
+			ns := route.Ingress.Namespace
+			routesByNamespace[ns] = append(routesByNamespace[ns], routeRef{service: i, route: j, name: name})
+		}
+	}
+
+	dropped := make(map[[2]int]struct{})
+	for ns, refs := range routesByNamespace {
+		if len(refs) <= p.namespaceQuotas.MaxRoutes {
+			continue
+		}
+		sort.Slice(refs, func(i, j int) bool { return refs[i].name < refs[j].name })
+		failed := make(map[string]struct{})
+		for _, ref := range refs[p.namespaceQuotas.MaxRoutes:] {
+			dropped[[2]int{ref.service, ref.route}] = struct{}{}
+			info := state.Services[ref.service].Routes[ref.route].Ingress
+			if _, ok := failed[info.Key()]; ok {
+				continue
+			}
+			failed[info.Key()] = struct{}{}
+			p.registerTranslationFailure(info.ToK8sObject(), fmt.Sprintf(
+				"routes dropped: namespace %s exceeds its quota of %d routes", ns, p.namespaceQuotas.MaxRoutes))
+		}
+	}
+	if len(dropped) == 0 {
+		return
+	}
+
+	for i := range state.Services {
+		routes := make([]kongstate.Route, 0, len(state.Services[i].Routes))
+		for j, route := range state.Services[i].Routes {
+			if _, ok := dropped[[2]int{i, j}]; !ok {
+				routes = append(routes, route)
+			}
+		}
+		state.Services[i].Routes = routes
+	}
+}
+
+// enforceConsumerQuota drops the consumers of namespaces which exceed the
+// maximum number of consumers. Consumers are kept in order of their names.
+func (p *Parser) enforceConsumerQuota(state *kongstate.KongState) {
+	if p.namespaceQuotas.MaxConsumers <= 0 {
+		return
+	}
+
+	consumersByNamespace := make(map[string][]int)
+	for i, consumer := range state.Consumers {
+		ns := consumer.K8sKongConsumer.Namespace
+		consumersByNamespace[ns] = append(consumersByNamespace[ns], i)
+	}
+
+	dropped := make(map[int]struct{})
+	for ns, indexes := range consumersByNamespace {
+		if len(indexes) <= p.namespaceQuotas.MaxConsumers {
+			continue
+		}
+		sort.Slice(indexes, func(i, j int) bool {
+			return state.Consumers[indexes[i]].K8sKongConsumer.Name < state.Consumers[indexes[j]].K8sKongConsumer.Name
+		})
+		for _, i := range indexes[p.namespaceQuotas.MaxConsumers:] {
+			dropped[i] = struct{}{}
+			p.registerTranslationFailure(&state.Consumers[i].K8sKongConsumer, fmt.Sprintf(
+				"consumer dropped: namespace %s exceeds its quota of %d consumers", ns, p.namespaceQuotas.MaxConsumers))
+		}
+	}
+	if len(dropped) == 0 {
+		return
+	}
+
+	consumers := make([]kongstate.Consumer, 0, len(state.Consumers)-len(dropped))
+	for i, consumer := range state.Consumers {
+		if _, ok := dropped[i]; !ok {
+			consumers = append(consumers, consumer)
+		}
+	}
+	state.Consumers = consumers
+}
+
+// enforcePluginQuota drops the plugins configured for the objects of namespaces
+// which exceed the maximum number of plugins. Global plugins don't count towards
+// any namespace. Plugins are kept in order of the objects they're translated
+// from and the entities they're attached to.
+func (p *Parser) enforcePluginQuota(state *kongstate.KongState) {
+	if p.namespaceQuotas.MaxPlugins <= 0 {
+		return
+	}
+
+	pluginsByNamespace := make(map[string][]int)
+	for i, plugin := range state.Plugins {
+		if plugin.K8sNamespace == "" {
+			continue
+		}
+		pluginsByNamespace[plugin.K8sNamespace] = append(pluginsByNamespace[plugin.K8sNamespace], i)
+	}
+
+	dropped := make(map[int]struct{})
+	for ns, indexes := range pluginsByNamespace {
+		if len(indexes) <= p.namespaceQuotas.MaxPlugins {
+			continue
+		}
+		sort.Slice(indexes, func(i, j int) bool {
+			return pluginSortKey(state.Plugins[indexes[i]]) < pluginSortKey(state.Plugins[indexes[j]])
+		})
+		failed := make(map[string]struct{})
+		for _, i := range indexes[p.namespaceQuotas.MaxPlugins:] {
+			dropped[i] = struct{}{}
+			name := state.Plugins[i].K8sName
+			if _, ok := failed[name]; ok {
+				continue
+			}
+			failed[name] = struct{}{}
+			if obj := p.getPluginObject(ns, name); obj != nil {
+				p.registerTranslationFailure(obj, fmt.Sprintf(
+					"plugins dropped: namespace %s exceeds its quota of %d plugins", ns, p.namespaceQuotas.MaxPlugins))
+			}
+		}
+	}
+	if len(dropped) == 0 {
+		return
+	}
+
+	plugins := make([]kongstate.Plugin, 0, len(state.Plugins)-len(dropped))
+	for i, plugin := range state.Plugins {
+		if _, ok := dropped[i]; !ok {
+			plugins = append(plugins, plugin)
+		}
+	}
+	state.Plugins = plugins
+}
+
+// getPluginObject provides the KongPlugin with the given name in the namespace
+// or, if there's none, the KongClusterPlugin with that name.
+func (p *Parser) getPluginObject(namespace, name string) client.Object {
+	if plugin, err := p.storer.GetKongPlugin(namespace, name); err == nil {
+		return plugin
+	}
+	if plugin, err := p.storer.GetKongClusterPlugin(name); err == nil {
+		return plugin
+	}
+	return nil
+}
+
+// pluginSortKey provides a key identifying a plugin by the object it was
+// translated from and the entities it's attached to.
+func pluginSortKey(plugin kongstate.Plugin) string {
+	id := func(entityID *string) string {
+		if entityID == nil {
+			return ""
+		}
+		return *entityID
+	}
+	key := plugin.K8sName
+	if plugin.Service != nil {
+		key += "/" + id(plugin.Service.ID)
+	}
+	if plugin.Route != nil {
+		key += "/" + id(plugin.Route.ID)
+	}
+	if plugin.Consumer != nil {
+		key += "/" + id(plugin.Consumer.ID)
+	}
+	return key
+}
+
+// IngressRouteCount provides the number of Kong routes the rules of an Ingress
+// translate to, for the route quota to be enforced before translation. With
+// combined routes, the paths of a host proxied to the same Service port share
+// a route. The default backend and catch-all routes, which depend on other
+// objects, aren't counted.
+func IngressRouteCount(ingress *networkingv1.Ingress, combinedRoutes bool) int {
+	count := 0
+	if combinedRoutes {
+		for _, service := range translators.TranslateIngress(ingress) {
+			count += len(service.Routes)
+		}
+		return count
+	}
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, rulePath := range rule.HTTP.Paths {
+			if !strings.Contains(rulePath.Path, "//") {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// HTTPRouteRouteCount provides the number of Kong routes the rules of an
+// HTTPRoute translate to.
+func HTTPRouteRouteCount(httproute *gatewayv1alpha2.HTTPRoute) int {
+	count := 0
+	for i, rule := range httproute.Spec.Rules {
+		if routes, err := generateKongRoutesFromHTTPRouteRule(httproute, i, rule); err == nil {
+			count += len(routes)
+		}
+	}
+	return count
+}
+
+// TCPIngressRouteCount provides the number of Kong routes the rules of a
+// TCPIngress translate to.
+func TCPIngressRouteCount(ingress *configurationv1beta1.TCPIngress) int {
+	return len(ingress.Spec.Rules)
+}
+
+// UDPIngressRouteCount provides the number of Kong routes the rules of a
+// UDPIngress translate to: one per port of their ranges.
+func UDPIngressRouteCount(ingress *configurationv1beta1.UDPIngress) int {
+	count := 0
+	for _, rule := range ingress.Spec.Rules {
+		if endPort := UDPIngressRuleEndPort(rule); endPort >= rule.Port {
+			count += endPort - rule.Port + 1
+		}
+	}
+	return count
+}
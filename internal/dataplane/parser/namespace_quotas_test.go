@@ -0,0 +1,176 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+	configurationv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+	configurationv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
+)
+
+func TestNamespaceQuotas(t *testing.T) {
+	s, err := store.NewFakeStore(store.FakeObjects{
+		KongPlugins: []*configurationv1.KongPlugin{
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant-a", Name: "rate-limit"}, PluginName: "rate-limiting"},
+		},
+	})
+	require.NoError(t, err)
+	newParser := func(quotas util.NamespaceQuotas) *Parser {
+		p := NewParser(logrus.New(), s)
+		p.SetNamespaceQuotas(quotas)
+		return p
+	}
+	route := func(namespace, name string) kongstate.Route {
+		return kongstate.Route{
+			Ingress: util.K8sObjectInfo{Namespace: namespace, Name: name},
+			Route:   kong.Route{Name: kong.String(namespace + "." + name)},
+		}
+	}
+	routeNames := func(state *kongstate.KongState) []string {
+		var names []string
+		for _, svc := range state.Services {
+			for _, r := range svc.Routes {
+				names = append(names, *r.Name)
+			}
+		}
+		return names
+	}
+
+	t.Run("routes exceeding the quota of their namespace are dropped", func(t *testing.T) {
+		state := &kongstate.KongState{Services: []kongstate.Service{
+			{Routes: []kongstate.Route{route("tenant-a", "c"), route("tenant-b", "a")}},
+			{Routes: []kongstate.Route{route("tenant-a", "b"), route("tenant-a", "a")}},
+		}}
+		p := newParser(util.NamespaceQuotas{MaxRoutes: 2})
+		p.enforceRouteQuota(state)
+		assert.Equal(t, []string{"tenant-b.a", "tenant-a.b", "tenant-a.a"}, routeNames(state))
+
+		failures := p.PopTranslationFailures()
+		require.Len(t, failures, 1)
+		assert.Equal(t, "c", failures[0].Object.GetName())
+	})
+
+	t.Run("routes are untouched without a quota", func(t *testing.T) {
+		state := &kongstate.KongState{Services: []kongstate.Service{
+			{Routes: []kongstate.Route{route("tenant-a", "c"), route("tenant-a", "b"), route("tenant-a", "a")}},
+		}}
+		p := newParser(util.NamespaceQuotas{})
+		p.enforceRouteQuota(state)
+		assert.Len(t, routeNames(state), 3)
+		assert.Empty(t, p.PopTranslationFailures())
+	})
+
+	t.Run("consumers exceeding the quota of their namespace are dropped", func(t *testing.T) {
+		consumer := func(namespace, name string) kongstate.Consumer {
+			return kongstate.Consumer{
+				Consumer:        kong.Consumer{Username: kong.String(name)},
+				K8sKongConsumer: configurationv1.KongConsumer{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}},
+			}
+		}
+		state := &kongstate.KongState{Consumers: []kongstate.Consumer{
+			consumer("tenant-a", "b"), consumer("tenant-b", "b"), consumer("tenant-a", "a"),
+		}}
+		p := newParser(util.NamespaceQuotas{MaxConsumers: 1})
+		p.enforceConsumerQuota(state)
+		require.Len(t, state.Consumers, 2)
+		assert.Equal(t, "tenant-b", state.Consumers[0].K8sKongConsumer.Namespace)
+		assert.Equal(t, "a", state.Consumers[1].K8sKongConsumer.Name)
+
+		failures := p.PopTranslationFailures()
+		require.Len(t, failures, 1)
+		assert.Equal(t, "b", failures[0].Object.GetName())
+	})
+
+	t.Run("plugins exceeding the quota of their namespace are dropped", func(t *testing.T) {
+		plugin := func(routeID string) kongstate.Plugin {
+			return kongstate.Plugin{
+				Plugin:       kong.Plugin{Name: kong.String("rate-limiting"), Route: &kong.Route{ID: kong.String(routeID)}},
+				K8sNamespace: "tenant-a",
+				K8sName:      "rate-limit",
+			}
+		}
+		global := kongstate.Plugin{Plugin: kong.Plugin{Name: kong.String("prometheus")}}
+		state := &kongstate.KongState{Plugins: []kongstate.Plugin{plugin("b"), global, plugin("a")}}
+		p := newParser(util.NamespaceQuotas{MaxPlugins: 1})
+		p.enforcePluginQuota(state)
+		assert.Equal(t, []kongstate.Plugin{global, plugin("a")}, state.Plugins)
+
+		failures := p.PopTranslationFailures()
+		require.Len(t, failures, 1)
+		assert.Equal(t, "rate-limit", failures[0].Object.GetName())
+	})
+}
+
+func TestRouteCounts(t *testing.T) {
+	t.Run("Ingress", func(t *testing.T) {
+		backend := func(name string) networkingv1.IngressBackend {
+			return networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+				Name: name,
+				Port: networkingv1.ServiceBackendPort{Number: 80},
+			}}
+		}
+		ingress := &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"},
+			Spec: networkingv1.IngressSpec{
+				Rules: []networkingv1.IngressRule{
+					{
+						Host: "example.com",
+						IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{Path: "/a", Backend: backend("a")},
+								{Path: "/b", Backend: backend("a")},
+								{Path: "/c", Backend: backend("c")},
+							},
+						}},
+					},
+					{Host: "no-paths.example.com"},
+				},
+			},
+		}
+		assert.Equal(t, 3, IngressRouteCount(ingress, false), "one route per path")
+		assert.Equal(t, 2, IngressRouteCount(ingress, true), "one route per host and Service port")
+	})
+
+	t.Run("HTTPRoute", func(t *testing.T) {
+		pathPrefix := gatewayv1alpha2.PathMatchPathPrefix
+		match := func(path string) gatewayv1alpha2.HTTPRouteMatch {
+			return gatewayv1alpha2.HTTPRouteMatch{Path: &gatewayv1alpha2.HTTPPathMatch{Type: &pathPrefix, Value: kong.String(path)}}
+		}
+		httproute := &gatewayv1alpha2.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"},
+			Spec: gatewayv1alpha2.HTTPRouteSpec{
+				Hostnames: []gatewayv1alpha2.Hostname{"example.com"},
+				Rules: []gatewayv1alpha2.HTTPRouteRule{
+					{Matches: []gatewayv1alpha2.HTTPRouteMatch{match("/a"), match("/b")}},
+					{},
+				},
+			},
+		}
+		assert.Equal(t, 3, HTTPRouteRouteCount(httproute))
+	})
+
+	t.Run("TCPIngress", func(t *testing.T) {
+		assert.Equal(t, 2, TCPIngressRouteCount(&configurationv1beta1.TCPIngress{
+			Spec: configurationv1beta1.TCPIngressSpec{Rules: []configurationv1beta1.IngressRule{{Port: 9000}, {Port: 9001}}},
+		}))
+	})
+
+	t.Run("UDPIngress", func(t *testing.T) {
+		assert.Equal(t, 11, UDPIngressRouteCount(&configurationv1beta1.UDPIngress{
+			Spec: configurationv1beta1.UDPIngressSpec{Rules: []configurationv1beta1.UDPIngressRule{
+				{Port: 9000},
+				{Port: 10000, EndPort: 10009},
+			}},
+		}), "one route per port of the ranges")
+	})
+}
@@ -2,10 +2,12 @@ package parser
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
+	"net"
 	"reflect"
 	"sort"
 	"strings"
@@ -13,6 +15,7 @@ import (
 	"github.com/kong/go-kong/kong"
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	networking "k8s.io/api/networking/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -38,9 +41,20 @@ type Parser struct {
 	logger                      logrus.FieldLogger
 	storer                      store.Storer
 	configuredKubernetesObjects []client.Object
+	translationFailures         []TranslationFailure
+	portConflicts               []PortConflict
 
 	featureEnabledReportConfiguredKubernetesObjects bool
 	featureEnabledCombinedServiceRoutes             bool
+	featureEnabledRegexPathPrefix                   bool
+	featureEnabledEndpointSliceTargets              bool
+
+	namespaceQuotas               util.NamespaceQuotas
+	topologyZone                  string
+	clusterPluginSecretNamespaces []string
+	defaultCertificate            string
+
+	translationCache *TranslationCache
 }
 
 // NewParser produces a new Parser object provided a logging mechanism
@@ -63,24 +77,44 @@ func NewParser(
 // defined in Kuberentes.
 // It throws an error if there is an error returned from client-go.
 func (p *Parser) Build() (*kongstate.KongState, error) {
+	return p.BuildWithContext(context.Background())
+}
+
+// BuildWithContext is Build, stopping between translation stages with the
+// error of ctx once it's done, so that abandoned translations don't keep
+// running.
+func (p *Parser) BuildWithContext(ctx context.Context) (_ *kongstate.KongState, err error) {
+	if p.translationCache != nil {
+		generation := p.translationCache.begin()
+		defer func() {
+			// a translation which didn't complete doesn't tell which entries
+			// are stale
+			if err == nil {
+				p.translationCache.end(generation)
+			}
+		}()
+	}
+
 	// parse and merge all rules together from all Kubernetes API sources
-	ingressRules := mergeIngressRules(
-		p.ingressRulesFromIngressV1beta1(),
-		p.ingressRulesFromIngressV1(),
-		p.ingressRulesFromTCPIngressV1beta1(),
-		p.ingressRulesFromUDPIngressV1beta1(),
-		p.ingressRulesFromKnativeIngress(),
-		p.ingressRulesFromHTTPRoutes(),
-		p.ingressRulesFromUDPRoutes(),
-		p.ingressRulesFromTCPRoutes(),
-		p.ingressRulesFromTLSRoutes(),
-	)
+	translationContext := TranslationContext{Logger: p.logger, Storer: p.storer, parser: p}
+	var translated []ingressRules
+	for _, translator := range allTranslators() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		translated = append(translated, translator.Translate(translationContext).rules)
+	}
+	ingressRules := mergeIngressRules(translated...)
 
 	// populate any Kubernetes Service objects relevant objects
 	if err := ingressRules.populateServices(p.logger, p.storer); err != nil {
 		return nil, err
 	}
 
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// add the routes and services to the state
 	var result kongstate.KongState
 	for _, service := range ingressRules.ServiceNameToServices {
@@ -88,30 +122,75 @@ func (p *Parser) Build() (*kongstate.KongState, error) {
 	}
 
 	// generate Upstreams and Targets from service defs
-	result.Upstreams = getUpstreams(p.logger, p.storer, ingressRules.ServiceNameToServices)
+	result.Upstreams = getUpstreams(p.logger, p.storer, ingressRules.ServiceNameToServices, p.featureEnabledEndpointSliceTargets, p.topologyZone)
 
 	// merge KongIngress with Routes, Services and Upstream
 	result.FillOverrides(p.logger, p.storer)
 
+	// mark regex paths the way the Kong router expects them
+	if p.featureEnabledRegexPathPrefix {
+		prefixRegexPaths(&result)
+	}
+
+	// drop the routes of namespaces exceeding their quota
+	p.enforceRouteQuota(&result)
+
+	// drop the routes which their services' protocols can't serve
+	for _, mismatch := range result.RemoveProtocolMismatches() {
+		if mismatch.Route.Ingress.Name != "" {
+			p.registerTranslationFailure(mismatch.Route.Ingress.ToK8sObject(), mismatch.Message)
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// generate vaults, which credentials can reference
+	result.FillVaults(p.logger, p.storer)
+
 	// generate consumers and credentials
 	result.FillConsumersAndCredentials(p.logger, p.storer)
+	p.enforceConsumerQuota(&result)
 
 	// process annotation plugins
-	result.FillPlugins(p.logger, p.storer)
+	for _, failure := range result.FillPlugins(p.logger, p.storer, p.clusterPluginSecretNamespaces) {
+		p.registerTranslationFailure(failure.ClusterPlugin, failure.Message)
+	}
+
+	// drop the plugin attachments which their routes' protocols can't trigger
+	for _, mismatch := range result.RemovePluginProtocolMismatches() {
+		if obj := p.pluginObject(mismatch.Plugin); obj != nil {
+			p.registerTranslationFailure(obj, mismatch.Message)
+		}
+	}
+	p.enforcePluginQuota(&result)
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	// generate Certificates and SNIs
+	for _, conflict := range ingressRules.resolveSNIConflicts() {
+		served := conflict.served
+		p.registerTranslationFailure(conflict.ignored.owner, fmt.Sprintf(
+			"TLS host %q ignored: it's served the certificate of Secret %s requested by %s %s/%s, which takes precedence",
+			conflict.ignored.sni, served.secretName, objectKind(served.owner), served.owner.GetNamespace(), served.owner.GetName()))
+	}
+	ingressRules.assignDefaultCertificate(p.logger)
+	ingressRules.assignFallbackDefaultCertificate(p.defaultCertificate)
 	ingressCerts := getCerts(p.logger, p.storer, ingressRules.SecretNameToSNIs)
 	gatewayCerts := getGatewayCerts(p.logger, p.storer)
 	// note that ingress-derived certificates will take precedence over gateway-derived certificates for SNI assignment
 	result.Certificates = mergeCerts(p.logger, ingressCerts, gatewayCerts)
 
 	// populate CA certificates in Kong
-	var err error
 	caCertSecrets, err := p.storer.ListCACerts()
 	if err != nil {
 		return nil, err
 	}
 	result.CACertificates = toCACerts(p.logger, caCertSecrets)
+	result.CACertificates = append(result.CACertificates, p.getKongCACertificates()...)
 
 	return &result, nil
 }
@@ -147,6 +226,73 @@ func (p *Parser) GenerateKubernetesObjectReport() []client.Object {
 	return report
 }
 
+// -----------------------------------------------------------------------------
+// Parser - Public Methods - Translation Failures
+// -----------------------------------------------------------------------------
+
+// TranslationFailure describes a problem which prevented a Kubernetes object
+// (or part of it, e.g. a single rule) from being translated into Kong
+// configuration.
+type TranslationFailure struct {
+	// Object is the Kubernetes object which couldn't be fully translated.
+	Object client.Object
+
+	// Message is a human readable description of the problem.
+	Message string
+}
+
+// PopTranslationFailures provides all the translation failures which have
+// occurred as part of Build() calls so far. The failures are consumed: the
+// parser's internal list will be emptied once this method is called.
+func (p *Parser) PopTranslationFailures() []TranslationFailure {
+	failures := p.translationFailures
+	p.translationFailures = nil
+	return failures
+}
+
+// registerTranslationFailure records a problem translating the provided
+// object so that it can later be reported to users.
+func (p *Parser) registerTranslationFailure(obj client.Object, message string) {
+	p.translationFailures = append(p.translationFailures, TranslationFailure{
+		Object:  obj,
+		Message: message,
+	})
+}
+
+// PortConflict describes a rule of a TCPIngress or UDPIngress which is
+// ignored because an object taking precedence claims the same port.
+type PortConflict struct {
+	// Object is the TCPIngress or UDPIngress whose rule is ignored.
+	Object client.Object
+
+	// Winner is the object the port is routed for instead.
+	Winner client.Object
+
+	// Message is a human readable description of the conflict.
+	Message string
+}
+
+// PopPortConflicts provides all the port conflicts between TCPIngresses or
+// UDPIngresses which have occurred as part of Build() calls so far. The
+// conflicts are consumed: the parser's internal list will be emptied once
+// this method is called.
+func (p *Parser) PopPortConflicts() []PortConflict {
+	conflicts := p.portConflicts
+	p.portConflicts = nil
+	return conflicts
+}
+
+// registerPortConflict records that a rule of obj is ignored because winner
+// claims the same port, and reports it as a translation failure of obj.
+func (p *Parser) registerPortConflict(obj, winner client.Object, message string) {
+	p.portConflicts = append(p.portConflicts, PortConflict{
+		Object:  obj,
+		Winner:  winner,
+		Message: message,
+	})
+	p.registerTranslationFailure(obj, message)
+}
+
 // -----------------------------------------------------------------------------
 // Parser - Public Methods - Other Optional Features
 // -----------------------------------------------------------------------------
@@ -159,6 +305,57 @@ func (p *Parser) EnableCombinedServiceRoutes() {
 	p.featureEnabledCombinedServiceRoutes = true
 }
 
+// SetNamespaceQuotas limits the amount of configuration the Kubernetes objects
+// of a single namespace may produce. Configuration exceeding the quotas is
+// dropped and reported as translation failures.
+func (p *Parser) SetNamespaceQuotas(quotas util.NamespaceQuotas) {
+	p.namespaceQuotas = quotas
+}
+
+// EnableRegexPathPrefix makes the parser prefix regex paths with "~", as
+// required by the router of Kong 3.0 and above, which otherwise treats all
+// paths as plain prefixes.
+func (p *Parser) EnableRegexPathPrefix() {
+	p.featureEnabledRegexPathPrefix = true
+}
+
+// EnableEndpointSliceTargets makes the parser generate the targets of all
+// Services from their EndpointSlices rather than their Endpoints, so that the
+// conditions of their endpoints (e.g. terminating) are taken into account.
+// Services without EndpointSlices still fall back to their Endpoints.
+func (p *Parser) EnableEndpointSliceTargets() {
+	p.featureEnabledEndpointSliceTargets = true
+}
+
+// SetTopologyZone sets the zone of the data-plane. The targets generated from
+// the EndpointSlices of a Service are limited to the endpoints in that zone,
+// or hinted for it, if there are any. The translated configuration is the same
+// for every proxy, which must therefore all run in that zone.
+func (p *Parser) SetTopologyZone(zone string) {
+	p.topologyZone = zone
+}
+
+// SetDefaultCertificate sets the Secret, given as "namespace/name", of the
+// certificate Kong serves for the SNIs no TLS section covers, unless a TLS
+// section sets the default certificate.
+func (p *Parser) SetDefaultCertificate(secretName string) {
+	p.defaultCertificate = secretName
+}
+
+// SetClusterPluginSecretNamespaces limits the namespaces of the Secrets which
+// KongClusterPlugins may reference for their configuration. KongClusterPlugins
+// may reference Secrets in any namespace if none is set.
+func (p *Parser) SetClusterPluginSecretNamespaces(namespaces []string) {
+	p.clusterPluginSecretNamespaces = namespaces
+}
+
+// SetTranslationCache makes the parser reuse the translation of the Kubernetes
+// objects which haven't changed since they were cached, rather than translating
+// them again. The cache should be shared by the parsers of all translations.
+func (p *Parser) SetTranslationCache(cache *TranslationCache) {
+	p.translationCache = cache
+}
+
 // -----------------------------------------------------------------------------
 // Parser - Private Methods
 // -----------------------------------------------------------------------------
@@ -208,6 +405,43 @@ func toCACerts(log logrus.FieldLogger, caCertSecrets []*corev1.Secret) []kong.CA
 	return caCerts
 }
 
+// getKongCACertificates translates the KongCACertificates into Kong CA
+// certificates, reporting those which can't be translated.
+func (p *Parser) getKongCACertificates() []kong.CACertificate {
+	kongCACerts, err := p.storer.ListKongCACertificates()
+	if err != nil {
+		p.logger.WithError(err).Error("failed to list KongCACertificates")
+		return nil
+	}
+
+	var caCerts []kong.CACertificate
+	for _, kongCACert := range kongCACerts {
+		certs, err := kongstate.CACertificatesForKongCACertificate(p.storer, kongCACert)
+		if err != nil {
+			p.registerTranslationFailure(kongCACert, fmt.Sprintf("invalid CA certificate: %v", err))
+			continue
+		}
+		caCerts = append(caCerts, certs...)
+		p.ReportKubernetesObjectUpdate(kongCACert)
+	}
+	return caCerts
+}
+
+// pluginObject provides the KongPlugin or KongClusterPlugin a plugin was
+// translated from, or nil if it can't be retrieved.
+func (p *Parser) pluginObject(plugin kongstate.Plugin) client.Object {
+	if plugin.ClusterPlugin {
+		if obj, err := p.storer.GetKongClusterPlugin(plugin.K8sName); err == nil {
+			return obj
+		}
+		return nil
+	}
+	if obj, err := p.storer.GetKongPlugin(plugin.K8sNamespace, plugin.K8sName); err == nil {
+		return obj
+	}
+	return nil
+}
+
 func knativeIngressToNetworkingTLS(tls []knative.IngressTLS) []networking.IngressTLS {
 	var result []networking.IngressTLS
 
@@ -232,6 +466,23 @@ func tcpIngressToNetworkingTLS(tls []configurationv1beta1.IngressTLS) []networki
 	return result
 }
 
+// tcpIngressRulesToNetworkingTLS provides the TLS sections binding the hosts of
+// TCPIngress rules to the certificates terminating their TLS sessions.
+func tcpIngressRulesToNetworkingTLS(rules []configurationv1beta1.IngressRule) []networking.IngressTLS {
+	var result []networking.IngressTLS
+
+	for _, r := range rules {
+		if r.Host == "" || r.TLSSecretName == "" {
+			continue
+		}
+		result = append(result, networking.IngressTLS{
+			Hosts:      []string{r.Host},
+			SecretName: r.TLSSecretName,
+		})
+	}
+	return result
+}
+
 // findPort finds a port matching the specified definition in a Kubernetes Service.
 func findPort(svc *corev1.Service, wantPort kongstate.PortDef) (*corev1.ServicePort, error) {
 	switch wantPort.Mode {
@@ -249,6 +500,14 @@ func findPort(svc *corev1.Service, wantPort kongstate.PortDef) (*corev1.ServiceP
 				return &port, nil
 			}
 		}
+		// headless Services may have no ports at all, in which case their
+		// endpoints accept traffic on any port.
+		if isHeadlessService(svc) && len(svc.Spec.Ports) == 0 {
+			return &corev1.ServicePort{
+				Port:       wantPort.Number,
+				TargetPort: intstr.FromInt(int(wantPort.Number)),
+			}, nil
+		}
 
 	case kongstate.PortModeByName:
 		if svc.Spec.Type == corev1.ServiceTypeExternalName {
@@ -283,6 +542,8 @@ func getUpstreams(
 	log logrus.FieldLogger,
 	s store.Storer,
 	serviceMap map[string]kongstate.Service,
+	endpointSliceTargets bool,
+	topologyZone string,
 ) []kongstate.Upstream {
 	upstreamDedup := make(map[string]struct{}, len(serviceMap))
 	var empty struct{}
@@ -293,6 +554,11 @@ func getUpstreams(
 		// resolve the host otherwise.
 		name := *service.Host
 
+		// the upstreams of these services are managed outside of Kubernetes
+		if service.ExternalUpstream {
+			continue
+		}
+
 		if _, exists := upstreamDedup[name]; !exists {
 			// populate all the kong targets for the upstream given all the backends
 			var targets []kongstate.Target
@@ -312,12 +578,18 @@ func getUpstreams(
 				}
 
 				// get the new targets for this backend service
-				newTargets := getServiceEndpoints(log, s, k8sService, port)
+				newTargets := getServiceEndpoints(log, s, k8sService, port, endpointSliceTargets)
 
 				if len(newTargets) == 0 {
 					log.WithField("service_name", *service.Name).Errorf("no targets could be found for kubernetes service %s/%s", k8sService.Namespace, k8sService.Name)
 				}
 
+				// targets generated from endpoints are limited to the zone of the
+				// data-plane, so that traffic doesn't needlessly cross zones.
+				if endpointSliceTargets || isHeadlessService(k8sService) {
+					newTargets = filterTopologyTargets(log, s, k8sService, newTargets, topologyZone)
+				}
+
 				// if weights were set for the backend then that weight needs to be
 				// distributed equally among all the targets.
 				if backend.Weight != nil && len(newTargets) != 0 {
@@ -344,6 +616,9 @@ func getUpstreams(
 					}
 				}
 
+				// prefer the targets in the primary zone of the backend service, if any.
+				setZonalFailoverWeights(log, s, k8sService, newTargets)
+
 				// add the new targets to the existing pool of targets for the Upstream.
 				targets = append(targets, newTargets...)
 			}
@@ -621,6 +896,7 @@ func getServiceEndpoints(
 	s store.Storer,
 	svc *corev1.Service,
 	servicePort *corev1.ServicePort,
+	endpointSliceTargets bool,
 ) []kongstate.Target {
 
 	log = log.WithFields(logrus.Fields{
@@ -629,6 +905,28 @@ func getServiceEndpoints(
 		"service_port":      servicePort,
 	})
 
+	// imported services are targeted through their ClusterSet IP or the endpoints
+	// imported for them, never through the Endpoints of the local cluster.
+	if isImportedService(svc) {
+		endpoints := getImportedServiceEndpoints(log, s, svc, servicePort)
+		if len(endpoints) == 0 {
+			log.Warningf("no active endpoints")
+		}
+		return targetsForEndpoints(endpoints)
+	}
+
+	// headless Services have no ClusterIP to rely on, so their pods are targeted
+	// directly based on their EndpointSlices, falling back to their Endpoints.
+	// Other Services are only targeted based on their EndpointSlices if enabled.
+	useEndpointSlices := isHeadlessService(svc) ||
+		(endpointSliceTargets && svc.Spec.Type != corev1.ServiceTypeExternalName)
+	if useEndpointSlices && !annotations.HasServiceUpstreamAnnotation(svc.Annotations) {
+		endpoints := getEndpointSliceEndpoints(log, svc, servicePort, s.GetEndpointSlicesForService)
+		if len(endpoints) > 0 {
+			return targetsForEndpoints(endpoints)
+		}
+	}
+
 	// in theory a Service could have multiple port protocols, we need to ensure we gather
 	// endpoints based on all the protocols the service is configured for. We always check
 	// for TCP as this is the default protocol for service ports.
@@ -747,6 +1045,152 @@ func getEndpoints(
 	return upsServers
 }
 
+// isHeadlessService indicates whether a Service is headless, i.e. it has no
+// ClusterIP load-balancing across its endpoints.
+func isHeadlessService(svc *corev1.Service) bool {
+	return svc.Spec.Type != corev1.ServiceTypeExternalName && svc.Spec.ClusterIP == corev1.ClusterIPNone
+}
+
+const (
+	// drainPolicyDrop stops targeting endpoints as soon as they're terminating.
+	drainPolicyDrop = "drop"
+	// drainPolicyFallback targets terminating endpoints which are still serving
+	// only while the Service has no ready endpoints, e.g. while all its pods are
+	// being replaced at once.
+	drainPolicyFallback = "fallback"
+	// drainPolicyDrain keeps targeting terminating endpoints until they stop
+	// serving, e.g. once they fail their readiness probes during shutdown.
+	drainPolicyDrain = "drain"
+)
+
+// getDrainPolicy provides how the terminating endpoints of a Service are
+// targeted, defaulting to dropping them.
+func getDrainPolicy(log logrus.FieldLogger, s *corev1.Service) string {
+	switch policy := annotations.ExtractDrainPolicy(s.Annotations); policy {
+	case "":
+		return drainPolicyDrop
+	case drainPolicyDrop, drainPolicyFallback, drainPolicyDrain:
+		return policy
+	default:
+		log.Errorf("ignoring invalid %s annotation value %q", annotations.AnnotationPrefix+annotations.DrainPolicyKey, policy)
+		return drainPolicyDrop
+	}
+}
+
+// getEndpointSliceEndpoints returns a list of <endpoint ip>:<port> for a given
+// service/port combination, based on the EndpointSlices of the Service.
+// Only ready endpoints are targeted, unless the Service publishes not ready ones.
+// Terminating endpoints which are still serving are targeted according to the
+// drain policy of the Service.
+func getEndpointSliceEndpoints(
+	log logrus.FieldLogger,
+	s *corev1.Service,
+	port *corev1.ServicePort,
+	getEndpointSlices func(string, string) ([]*discoveryv1.EndpointSlice, error),
+) []util.Endpoint {
+	upsServers := []util.Endpoint{}
+
+	if s == nil || port == nil {
+		return upsServers
+	}
+
+	endpointSlices, err := getEndpointSlices(s.Namespace, s.Name)
+	if err != nil {
+		log.WithError(err).Debug("failed to fetch endpoint slices")
+		return upsServers
+	}
+
+	protocol := port.Protocol
+	if protocol == "" {
+		protocol = corev1.ProtocolTCP
+	}
+
+	drainPolicy := getDrainPolicy(log, s)
+	adus := make(map[string]bool)
+	fallbackServers := []util.Endpoint{}
+	fallbackAdus := make(map[string]bool)
+	for _, endpointSlice := range endpointSlices {
+		if endpointSlice.AddressType == discoveryv1.AddressTypeFQDN {
+			continue
+		}
+
+		var targetPorts []int32
+		if len(endpointSlice.Ports) == 0 {
+			// EndpointSlices without ports accept traffic on any port
+			targetPort := int32(port.TargetPort.IntValue())
+			if targetPort <= 0 {
+				targetPort = port.Port
+			}
+			targetPorts = append(targetPorts, targetPort)
+		}
+		for _, epPort := range endpointSlice.Ports {
+			if epPort.Port == nil || *epPort.Port <= 0 {
+				continue
+			}
+			if epPort.Protocol != nil && *epPort.Protocol != protocol {
+				continue
+			}
+			// port.Name is optional if there is only one port
+			if port.Name != "" && (epPort.Name == nil || *epPort.Name != port.Name) {
+				continue
+			}
+			targetPorts = append(targetPorts, *epPort.Port)
+		}
+
+		for _, endpoint := range endpointSlice.Endpoints {
+			servers, seen := &upsServers, adus
+			if !s.Spec.PublishNotReadyAddresses && !isReadyEndpoint(endpoint.Conditions) {
+				if !isServingTerminatingEndpoint(endpoint.Conditions) {
+					continue
+				}
+				switch drainPolicy {
+				case drainPolicyDrain:
+				case drainPolicyFallback:
+					servers, seen = &fallbackServers, fallbackAdus
+				default:
+					continue
+				}
+			}
+			for _, address := range endpoint.Addresses {
+				for _, targetPort := range targetPorts {
+					ep := fmt.Sprintf("%v:%v", address, targetPort)
+					if _, exists := seen[ep]; exists {
+						continue
+					}
+					*servers = append(*servers, util.Endpoint{
+						Address: address,
+						Port:    fmt.Sprintf("%v", targetPort),
+					})
+					seen[ep] = true
+				}
+			}
+		}
+	}
+
+	if len(upsServers) == 0 && len(fallbackServers) > 0 {
+		log.Info("no ready endpoints, falling back to terminating endpoints")
+		upsServers = fallbackServers
+	}
+
+	log.Debugf("found endpoints in endpoint slices: %v", upsServers)
+	return upsServers
+}
+
+// isReadyEndpoint indicates whether an endpoint is ready to receive traffic.
+// Endpoints with an unknown readiness are considered ready, as advised by the
+// EndpointSlice API.
+func isReadyEndpoint(conditions discoveryv1.EndpointConditions) bool {
+	terminating := conditions.Terminating != nil && *conditions.Terminating
+	return !terminating && (conditions.Ready == nil || *conditions.Ready)
+}
+
+// isServingTerminatingEndpoint indicates whether an endpoint is terminating but
+// still able to serve traffic.
+func isServingTerminatingEndpoint(conditions discoveryv1.EndpointConditions) bool {
+	terminating := conditions.Terminating != nil && *conditions.Terminating
+	return terminating && conditions.Serving != nil && *conditions.Serving
+}
+
 // listProtocols is a helper function to map out all the in-use corev1.Protocols
 // for a service given a corev1.Service object.
 //
@@ -764,13 +1208,211 @@ func listProtocols(svc *corev1.Service) map[corev1.Protocol]bool {
 	return protocols
 }
 
+// failoverRemoteWeight is the weight of the targets outside the primary zone
+// of a Service whose KongUpstreamPolicy configures failover. It's low enough
+// for them to only receive a small share of the traffic, but not 0, which
+// would keep Kong from sending them traffic when healthchecks mark the targets
+// of the primary zone unhealthy.
+const failoverRemoteWeight = 1
+
+// setZonalFailoverWeights lowers the weight of the targets of a Service whose
+// KongUpstreamPolicy configures failover to failoverRemoteWeight if they are
+// outside the primary zone, as long as the primary zone has targets. Targets
+// are only generated for ready endpoints, so all the traffic fails over to the
+// other zones once no endpoint of the primary zone is ready, or once Kong
+// healthchecks mark all the targets of the primary zone unhealthy.
+func setZonalFailoverWeights(
+	log logrus.FieldLogger,
+	s store.Storer,
+	svc *corev1.Service,
+	targets []kongstate.Target,
+) {
+	if len(targets) == 0 {
+		return
+	}
+	primaryZone, ok := failoverPrimaryZone(s, svc)
+	if !ok {
+		return
+	}
+	log = log.WithFields(logrus.Fields{
+		"service_name":      svc.Name,
+		"service_namespace": svc.Namespace,
+		"primary_zone":      primaryZone,
+	})
+
+	zones := getEndpointZones(s, svc)
+	primaryTargets := 0
+	for _, target := range targets {
+		if zones[targetAddress(target)] == primaryZone {
+			primaryTargets++
+		}
+	}
+	if primaryTargets == 0 {
+		log.Warn("no targets found in the primary zone, balancing across all zones")
+		return
+	}
+
+	for i := range targets {
+		if zones[targetAddress(targets[i])] == primaryZone {
+			continue
+		}
+		// targets excluded from load-balancing stay excluded
+		if targets[i].Weight == nil || *targets[i].Weight > failoverRemoteWeight {
+			targets[i].Weight = kong.Int(failoverRemoteWeight)
+		}
+	}
+}
+
+// failoverPrimaryZone provides the primary zone configured by the
+// KongUpstreamPolicy of a Service, if the policy configures failover.
+func failoverPrimaryZone(s store.Storer, svc *corev1.Service) (string, bool) {
+	policyName := annotations.ExtractUpstreamPolicyName(svc.Annotations)
+	if policyName == "" {
+		return "", false
+	}
+	policy, err := s.GetKongUpstreamPolicy(svc.Namespace, policyName)
+	if err != nil || policy.Spec.Failover == nil || policy.Spec.Failover.PrimaryZone == "" {
+		return "", false
+	}
+	return policy.Spec.Failover.PrimaryZone, true
+}
+
+// getEndpointZones maps the addresses of the endpoints of a Service to their
+// zones, as reported by the EndpointSlices of the Service.
+func getEndpointZones(s store.Storer, svc *corev1.Service) map[string]string {
+	zones := make(map[string]string)
+	endpointSlices, err := s.GetEndpointSlicesForService(svc.Namespace, svc.Name)
+	if err != nil {
+		return zones
+	}
+	for _, endpointSlice := range endpointSlices {
+		for _, endpoint := range endpointSlice.Endpoints {
+			if endpoint.Zone == nil {
+				continue
+			}
+			for _, address := range endpoint.Addresses {
+				zones[address] = *endpoint.Zone
+			}
+		}
+	}
+	return zones
+}
+
+// endpointTopology is the topology information of an endpoint.
+type endpointTopology struct {
+	zone      string
+	hinted    bool
+	hintZones []string
+}
+
+// filterTopologyTargets keeps the targets of a Service in the zone of the
+// data-plane. Topology hints are honored if all the endpoints of the targets
+// have one, as kube-proxy does, and the zones of the endpoints are used
+// otherwise. All the targets are kept if the topology of any of them is
+// unknown or if none of them is in the zone.
+func filterTopologyTargets(
+	log logrus.FieldLogger,
+	s store.Storer,
+	svc *corev1.Service,
+	targets []kongstate.Target,
+	zone string,
+) []kongstate.Target {
+	if zone == "" || len(targets) == 0 || isImportedService(svc) {
+		return targets
+	}
+	// Services with a primary zone fail over to the targets of other zones.
+	if _, ok := failoverPrimaryZone(s, svc); ok {
+		return targets
+	}
+
+	log = log.WithFields(logrus.Fields{
+		"service_name":      svc.Name,
+		"service_namespace": svc.Namespace,
+		"topology_zone":     zone,
+	})
+
+	topology := getEndpointTopology(s, svc)
+	hinted := true
+	for _, target := range targets {
+		endpoint, ok := topology[targetAddress(target)]
+		if !ok {
+			log.Debug("topology of targets unknown, balancing across all zones")
+			return targets
+		}
+		hinted = hinted && endpoint.hinted
+	}
+
+	filtered := make([]kongstate.Target, 0, len(targets))
+	for _, target := range targets {
+		endpoint := topology[targetAddress(target)]
+		inZone := endpoint.zone == zone
+		if hinted {
+			inZone = false
+			for _, hintZone := range endpoint.hintZones {
+				if hintZone == zone {
+					inZone = true
+					break
+				}
+			}
+		}
+		if inZone {
+			filtered = append(filtered, target)
+		}
+	}
+	if len(filtered) == 0 {
+		log.Warn("no targets found in the zone of the data-plane, balancing across all zones")
+		return targets
+	}
+	return filtered
+}
+
+// getEndpointTopology maps the addresses of the endpoints of a Service to
+// their topology, as reported by the EndpointSlices of the Service.
+func getEndpointTopology(s store.Storer, svc *corev1.Service) map[string]endpointTopology {
+	topology := make(map[string]endpointTopology)
+	endpointSlices, err := s.GetEndpointSlicesForService(svc.Namespace, svc.Name)
+	if err != nil {
+		return topology
+	}
+	for _, endpointSlice := range endpointSlices {
+		for _, endpoint := range endpointSlice.Endpoints {
+			var t endpointTopology
+			if endpoint.Zone != nil {
+				t.zone = *endpoint.Zone
+			}
+			if endpoint.Hints != nil {
+				t.hinted = true
+				for _, hint := range endpoint.Hints.ForZones {
+					t.hintZones = append(t.hintZones, hint.Name)
+				}
+			}
+			for _, address := range endpoint.Addresses {
+				topology[address] = t
+			}
+		}
+	}
+	return topology
+}
+
+// targetAddress provides the address of a target, without its port.
+func targetAddress(target kongstate.Target) string {
+	if target.Target.Target == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(*target.Target.Target)
+	if err != nil {
+		return *target.Target.Target
+	}
+	return host
+}
+
 // targetsForEndpoints generates kongstate.Target objects for each util.Endpoint provided.
 func targetsForEndpoints(endpoints []util.Endpoint) []kongstate.Target {
 	targets := []kongstate.Target{}
 	for _, endpoint := range endpoints {
 		target := kongstate.Target{
 			Target: kong.Target{
-				Target: kong.String(endpoint.Address + ":" + endpoint.Port),
+				Target: kong.String(net.JoinHostPort(endpoint.Address, endpoint.Port)),
 			},
 		}
 		targets = append(targets, target)
@@ -11,7 +11,9 @@ import (
 	"github.com/kong/go-kong/kong"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	networkingv1beta1 "k8s.io/api/networking/v1beta1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -25,6 +27,7 @@ import (
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
 	configurationv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+	configurationv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
 )
 
 type TLSPair struct {
@@ -964,6 +967,190 @@ func TestCACertificate(t *testing.T) {
 	})
 }
 
+func TestKongCACertificate(t *testing.T) {
+	kongCACert := func(name, secretName string) *configurationv1beta1.KongCACertificate {
+		return &configurationv1beta1.KongCACertificate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Annotations: map[string]string{
+					annotations.IngressClassKey: annotations.DefaultIngressClass,
+				},
+			},
+			Spec: configurationv1beta1.KongCACertificateSpec{
+				SecretRef: configurationv1beta1.KongCACertificateSecretRef{
+					Namespace: "default",
+					Name:      secretName,
+				},
+			},
+		}
+	}
+	store, err := store.NewFakeStore(store.FakeObjects{
+		Secrets: []*corev1.Secret{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "bundle", Namespace: "default"},
+				Data: map[string][]byte{
+					"ca.crt": []byte(caCert1 + "\n" + caCert2),
+				},
+			},
+		},
+		KongCACertificates: []*configurationv1beta1.KongCACertificate{
+			kongCACert("valid", "bundle"),
+			kongCACert("invalid", "missing"),
+		},
+	})
+	require.NoError(t, err)
+
+	p := NewParser(logrus.New(), store)
+	state, err := p.Build()
+	require.NoError(t, err)
+	require.Len(t, state.CACertificates, 2)
+	assert.Equal(t, caCert1+"\n", *state.CACertificates[0].Cert)
+	assert.Equal(t, caCert2+"\n", *state.CACertificates[1].Cert)
+
+	failures := p.PopTranslationFailures()
+	require.Len(t, failures, 1)
+	assert.Equal(t, "invalid", failures[0].Object.GetName())
+}
+
+func TestKongUpstreamPolicy(t *testing.T) {
+	store, err := store.NewFakeStore(store.FakeObjects{
+		IngressesV1: []*networkingv1.Ingress{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo",
+					Namespace: "default",
+					Annotations: map[string]string{
+						annotations.IngressClassKey: annotations.DefaultIngressClass,
+					},
+				},
+				Spec: networkingv1.IngressSpec{
+					Rules: []networkingv1.IngressRule{
+						{
+							Host: "example.com",
+							IngressRuleValue: networkingv1.IngressRuleValue{
+								HTTP: &networkingv1.HTTPIngressRuleValue{
+									Paths: []networkingv1.HTTPIngressPath{
+										{
+											Path: "/",
+											Backend: networkingv1.IngressBackend{
+												Service: &networkingv1.IngressServiceBackend{
+													Name: "foo-svc",
+													Port: networkingv1.ServiceBackendPort{Number: 80},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Services: []*corev1.Service{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo-svc",
+					Namespace: "default",
+					Annotations: map[string]string{
+						"konghq.com/upstream-policy": "sticky",
+					},
+				},
+			},
+		},
+		KongUpstreamPolicies: []*configurationv1beta1.KongUpstreamPolicy{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sticky",
+					Namespace: "default",
+				},
+				Spec: configurationv1beta1.KongUpstreamPolicySpec{
+					Algorithm: kong.String("consistent-hashing"),
+					Slots:     kong.Int(100),
+					HashOn: &configurationv1beta1.KongUpstreamHash{
+						Input: kong.String("ip"),
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	p := NewParser(logrus.New(), store)
+	state, err := p.Build()
+	require.NoError(t, err)
+	require.Len(t, state.Upstreams, 1)
+	assert.Equal(t, "consistent-hashing", *state.Upstreams[0].Algorithm)
+	assert.Equal(t, 100, *state.Upstreams[0].Slots)
+	assert.Equal(t, "ip", *state.Upstreams[0].HashOn)
+}
+
+func TestPluginProtocolMismatches(t *testing.T) {
+	store, err := store.NewFakeStore(store.FakeObjects{
+		TCPIngresses: []*configurationv1beta1.TCPIngress{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo",
+					Namespace: "default",
+					Annotations: map[string]string{
+						annotations.IngressClassKey: annotations.DefaultIngressClass,
+						"konghq.com/plugins":        "http-only,any-protocol",
+					},
+				},
+				Spec: configurationv1beta1.TCPIngressSpec{
+					Rules: []configurationv1beta1.IngressRule{
+						{
+							Port: 9000,
+							Backend: configurationv1beta1.IngressBackend{
+								ServiceName: "foo-svc",
+								ServicePort: 80,
+							},
+						},
+					},
+				},
+			},
+		},
+		Services: []*corev1.Service{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo-svc",
+					Namespace: "default",
+				},
+			},
+		},
+		KongPlugins: []*configurationv1.KongPlugin{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "http-only",
+					Namespace: "default",
+				},
+				PluginName: "ip-restriction",
+				Protocols:  []configurationv1.KongProtocol{"http", "https"},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "any-protocol",
+					Namespace: "default",
+				},
+				PluginName: "ip-restriction",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	p := NewParser(logrus.New(), store)
+	state, err := p.Build()
+	require.NoError(t, err)
+	require.Len(t, state.Plugins, 1, "only the plugin whose protocols the route uses is attached")
+	assert.Equal(t, "any-protocol", state.Plugins[0].K8sName)
+
+	failures := p.PopTranslationFailures()
+	require.Len(t, failures, 1)
+	assert.Equal(t, "http-only", failures[0].Object.GetName())
+	assert.Equal(t, "plugin ip-restriction not attached to route default.foo.0: protocols http,https aren't used by the route",
+		failures[0].Message)
+}
+
 func TestServiceClientCertificate(t *testing.T) {
 	assert := assert.New(t)
 	t.Run("valid client-cert annotation", func(t *testing.T) {
@@ -3507,6 +3694,93 @@ func TestParserHostAliases(t *testing.T) {
 	})
 }
 
+func TestParserExternalUpstream(t *testing.T) {
+	pathType := networkingv1.PathTypePrefix
+	ingress := func(name string, anns map[string]string) *networkingv1.Ingress {
+		anns[annotations.IngressClassKey] = annotations.DefaultIngressClass
+		return &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "default",
+				Annotations: anns,
+			},
+			Spec: networkingv1.IngressSpec{
+				Rules: []networkingv1.IngressRule{
+					{
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{
+									{
+										Path:     "/" + name,
+										PathType: &pathType,
+										Backend: networkingv1.IngressBackend{
+											Service: &networkingv1.IngressServiceBackend{
+												Name: "foo-svc",
+												Port: networkingv1.ServiceBackendPort{Number: 80},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	store, err := store.NewFakeStore(store.FakeObjects{
+		IngressesV1: []*networkingv1.Ingress{
+			ingress("internal", map[string]string{}),
+			ingress("legacy", map[string]string{
+				annotations.AnnotationPrefix + annotations.UpstreamNameKey: "legacy-backend",
+			}),
+		},
+		Services: []*corev1.Service{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo-svc", Namespace: "default"},
+				Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 80}}},
+			},
+		},
+		Endpoints: []*corev1.Endpoints{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo-svc", Namespace: "default"},
+				Subsets: []corev1.EndpointSubset{
+					{
+						Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}},
+						Ports:     []corev1.EndpointPort{{Port: 80}},
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	state, err := NewParser(logrus.New(), store).Build()
+	require.NoError(t, err)
+
+	services := make(map[string]kongstate.Service)
+	for _, service := range state.Services {
+		services[*service.Name] = service
+	}
+	require.Len(t, services, 2)
+
+	t.Log("verifying that the routes of annotated Ingresses proxy to the external upstream")
+	external, ok := services["default.upstream_legacy-backend"]
+	require.True(t, ok)
+	assert.Equal(t, "legacy-backend", *external.Host)
+	require.Len(t, external.Routes, 1)
+	assert.Equal(t, "default.legacy.00", *external.Routes[0].Name)
+
+	t.Log("verifying that the routes of other Ingresses still proxy to their backends")
+	internal, ok := services["default.foo-svc.pnum-80"]
+	require.True(t, ok)
+	require.Len(t, internal.Routes, 1)
+	assert.Equal(t, "default.internal.00", *internal.Routes[0].Name)
+
+	t.Log("verifying that no upstream is generated for the external upstream")
+	require.Len(t, state.Upstreams, 1)
+	assert.Equal(t, "foo-svc.default.80.svc", *state.Upstreams[0].Name)
+}
+
 func TestPluginAnnotations(t *testing.T) {
 	assert := assert.New(t)
 	t.Run("simple association", func(t *testing.T) {
@@ -3558,7 +3832,7 @@ func TestPluginAnnotations(t *testing.T) {
 					Namespace: "default",
 				},
 				PluginName: "key-auth",
-				Protocols:  []configurationv1.KongProtocol{"grpc"},
+				Protocols:  []configurationv1.KongProtocol{"http"},
 				Config: apiextensionsv1.JSON{
 					Raw: []byte(`{
 					"foo": "bar",
@@ -3589,7 +3863,7 @@ func TestPluginAnnotations(t *testing.T) {
 		pl.Route = nil
 		assert.Equal(pl, kong.Plugin{
 			Name:      kong.String("key-auth"),
-			Protocols: kong.StringSlice("grpc"),
+			Protocols: kong.StringSlice("http"),
 			Config: kong.Configuration{
 				"foo": "bar",
 				"add": map[string]interface{}{
@@ -3650,7 +3924,7 @@ func TestPluginAnnotations(t *testing.T) {
 					Namespace: "default",
 				},
 				PluginName: "basic-auth",
-				Protocols:  []configurationv1.KongProtocol{"grpc"},
+				Protocols:  []configurationv1.KongProtocol{"http"},
 				Config: apiextensionsv1.JSON{
 					Raw: []byte(`{"foo": "bar"}`),
 				},
@@ -3663,7 +3937,7 @@ func TestPluginAnnotations(t *testing.T) {
 					Namespace: "default",
 				},
 				PluginName: "key-auth",
-				Protocols:  []configurationv1.KongProtocol{"grpc"},
+				Protocols:  []configurationv1.KongProtocol{"http"},
 				Config: apiextensionsv1.JSON{
 					Raw: []byte(`{"foo": "bar"}`),
 				},
@@ -3684,7 +3958,7 @@ func TestPluginAnnotations(t *testing.T) {
 		assert.Equal(1, len(state.Plugins),
 			"expected no plugins to be rendered with missing plugin")
 		assert.Equal("key-auth", *state.Plugins[0].Name)
-		assert.Equal("grpc", *state.Plugins[0].Protocols[0])
+		assert.Equal("http", *state.Plugins[0].Protocols[0])
 	})
 	t.Run("KongClusterPlugin association", func(t *testing.T) {
 		services := []*corev1.Service{
@@ -3735,7 +4009,7 @@ func TestPluginAnnotations(t *testing.T) {
 					Namespace: "default",
 				},
 				PluginName: "basic-auth",
-				Protocols:  []configurationv1.KongProtocol{"grpc"},
+				Protocols:  []configurationv1.KongProtocol{"http"},
 				Config: apiextensionsv1.JSON{
 					Raw: []byte(`{"foo": "bar"}`),
 				},
@@ -3755,7 +4029,7 @@ func TestPluginAnnotations(t *testing.T) {
 		assert.Equal(1, len(state.Plugins),
 			"expected no plugins to be rendered with missing plugin")
 		assert.Equal("basic-auth", *state.Plugins[0].Name)
-		assert.Equal("grpc", *state.Plugins[0].Protocols[0])
+		assert.Equal("http", *state.Plugins[0].Protocols[0])
 	})
 	t.Run("missing plugin", func(t *testing.T) {
 		ingresses := []*networkingv1beta1.Ingress{
@@ -4779,3 +5053,434 @@ func TestCertificate(t *testing.T) {
 		assert.Equal(state.Certificates[0], fooCertificate)
 	})
 }
+
+func Test_setZonalFailoverWeights(t *testing.T) {
+	zoneA, zoneB := "zone-a", "zone-b"
+	endpointSlices := []*discoveryv1.EndpointSlice{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-1",
+				Namespace: "default",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "foo"},
+			},
+			Endpoints: []discoveryv1.Endpoint{
+				{Addresses: []string{"10.0.0.1"}, Zone: &zoneA},
+				{Addresses: []string{"10.0.0.2"}, Zone: &zoneB},
+				{Addresses: []string{"10.0.0.3"}},
+			},
+		},
+	}
+	newTargets := func(weight *int) []kongstate.Target {
+		var targets []kongstate.Target
+		for _, address := range []string{"10.0.0.1:80", "10.0.0.2:80", "10.0.0.3:80"} {
+			targets = append(targets, kongstate.Target{
+				Target: kong.Target{Target: kong.String(address), Weight: weight},
+			})
+		}
+		return targets
+	}
+	weights := func(targets []kongstate.Target) []*int {
+		var res []*int
+		for _, target := range targets {
+			res = append(res, target.Weight)
+		}
+		return res
+	}
+
+	policies := []*configurationv1beta1.KongUpstreamPolicy{
+		{ObjectMeta: metav1.ObjectMeta{Name: "no-failover", Namespace: "default"}},
+	}
+	for _, zone := range []string{zoneA, zoneB, "zone-c"} {
+		policies = append(policies, &configurationv1beta1.KongUpstreamPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: zone, Namespace: "default"},
+			Spec: configurationv1beta1.KongUpstreamPolicySpec{
+				Failover: &configurationv1beta1.KongUpstreamFailover{PrimaryZone: zone},
+			},
+		})
+	}
+	s, err := store.NewFakeStore(store.FakeObjects{
+		EndpointSlices:       endpointSlices,
+		KongUpstreamPolicies: policies,
+	})
+	require.NoError(t, err)
+	svc := func(policy string) *corev1.Service {
+		svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"}}
+		if policy != "" {
+			svc.Annotations = map[string]string{annotations.AnnotationPrefix + annotations.UpstreamPolicyKey: policy}
+		}
+		return svc
+	}
+
+	t.Run("services without a policy are left untouched", func(t *testing.T) {
+		targets := newTargets(nil)
+		setZonalFailoverWeights(logrus.New(), s, svc(""), targets)
+		assert.Equal(t, newTargets(nil), targets)
+	})
+
+	t.Run("services whose policy doesn't configure failover are left untouched", func(t *testing.T) {
+		targets := newTargets(nil)
+		setZonalFailoverWeights(logrus.New(), s, svc("no-failover"), targets)
+		assert.Equal(t, newTargets(nil), targets)
+	})
+
+	t.Run("services whose policy doesn't exist are left untouched", func(t *testing.T) {
+		targets := newTargets(nil)
+		setZonalFailoverWeights(logrus.New(), s, svc("missing"), targets)
+		assert.Equal(t, newTargets(nil), targets)
+	})
+
+	t.Run("targets outside the primary zone get the lowest weight", func(t *testing.T) {
+		targets := newTargets(nil)
+		setZonalFailoverWeights(logrus.New(), s, svc(zoneA), targets)
+		assert.Equal(t, []*int{nil, kong.Int(1), kong.Int(1)}, weights(targets),
+			"targets outside the primary zone can still receive traffic when healthchecks mark the primary zone unhealthy")
+	})
+
+	t.Run("explicit weights are kept for the primary zone", func(t *testing.T) {
+		targets := newTargets(kong.Int(25))
+		setZonalFailoverWeights(logrus.New(), s, svc(zoneB), targets)
+		assert.Equal(t, []*int{kong.Int(1), kong.Int(25), kong.Int(1)}, weights(targets))
+	})
+
+	t.Run("targets excluded from load-balancing stay excluded", func(t *testing.T) {
+		targets := newTargets(kong.Int(0))
+		setZonalFailoverWeights(logrus.New(), s, svc(zoneA), targets)
+		assert.Equal(t, []*int{kong.Int(0), kong.Int(0), kong.Int(0)}, weights(targets))
+	})
+
+	t.Run("all zones are balanced when no target is in the primary zone", func(t *testing.T) {
+		targets := newTargets(nil)
+		setZonalFailoverWeights(logrus.New(), s, svc("zone-c"), targets)
+		assert.Equal(t, []*int{nil, nil, nil}, weights(targets))
+	})
+}
+
+func Test_targetAddress(t *testing.T) {
+	for target, address := range map[string]string{
+		"10.0.0.1:80":  "10.0.0.1",
+		"[fd00::1]:80": "fd00::1",
+		"example.com":  "example.com",
+		"fd00::1":      "fd00::1",
+		"":             "",
+	} {
+		assert.Equal(t, address, targetAddress(kongstate.Target{Target: kong.Target{Target: kong.String(target)}}), target)
+	}
+	assert.Empty(t, targetAddress(kongstate.Target{}))
+}
+
+func Test_filterTopologyTargets(t *testing.T) {
+	zoneA, zoneB := "zone-a", "zone-b"
+	hints := func(zones ...string) *discoveryv1.EndpointHints {
+		h := &discoveryv1.EndpointHints{}
+		for _, zone := range zones {
+			h.ForZones = append(h.ForZones, discoveryv1.ForZone{Name: zone})
+		}
+		return h
+	}
+	s, err := store.NewFakeStore(store.FakeObjects{
+		KongUpstreamPolicies: []*configurationv1beta1.KongUpstreamPolicy{{
+			ObjectMeta: metav1.ObjectMeta{Name: "failover", Namespace: "default"},
+			Spec: configurationv1beta1.KongUpstreamPolicySpec{
+				Failover: &configurationv1beta1.KongUpstreamFailover{PrimaryZone: zoneA},
+			},
+		}},
+		EndpointSlices: []*discoveryv1.EndpointSlice{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "zoned-1",
+					Namespace: "default",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "zoned"},
+				},
+				Endpoints: []discoveryv1.Endpoint{
+					{Addresses: []string{"10.0.0.1"}, Zone: &zoneA},
+					{Addresses: []string{"10.0.0.2"}, Zone: &zoneB},
+					{Addresses: []string{"10.0.0.3"}, Zone: &zoneB},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "hinted-1",
+					Namespace: "default",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "hinted"},
+				},
+				Endpoints: []discoveryv1.Endpoint{
+					{Addresses: []string{"10.0.1.1"}, Zone: &zoneA, Hints: hints(zoneA)},
+					{Addresses: []string{"10.0.1.2"}, Zone: &zoneB, Hints: hints(zoneA)},
+					{Addresses: []string{"10.0.1.3"}, Zone: &zoneB, Hints: hints(zoneB)},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	newTargets := func(addresses ...string) []kongstate.Target {
+		var targets []kongstate.Target
+		for _, address := range addresses {
+			targets = append(targets, kongstate.Target{Target: kong.Target{Target: kong.String(address)}})
+		}
+		return targets
+	}
+	svc := func(name string) *corev1.Service {
+		return &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+	}
+	zoned := newTargets("10.0.0.1:80", "10.0.0.2:80", "10.0.0.3:80")
+	hinted := newTargets("10.0.1.1:80", "10.0.1.2:80", "10.0.1.3:80")
+
+	t.Run("targets are left untouched without a zone", func(t *testing.T) {
+		assert.Equal(t, zoned, filterTopologyTargets(logrus.New(), s, svc("zoned"), zoned, ""))
+	})
+
+	t.Run("targets are limited to the zone", func(t *testing.T) {
+		assert.Equal(t, newTargets("10.0.0.2:80", "10.0.0.3:80"), filterTopologyTargets(logrus.New(), s, svc("zoned"), zoned, zoneB))
+	})
+
+	t.Run("topology hints take precedence over zones", func(t *testing.T) {
+		assert.Equal(t, newTargets("10.0.1.1:80", "10.0.1.2:80"), filterTopologyTargets(logrus.New(), s, svc("hinted"), hinted, zoneA))
+	})
+
+	t.Run("all targets are kept when none is in the zone", func(t *testing.T) {
+		assert.Equal(t, zoned, filterTopologyTargets(logrus.New(), s, svc("zoned"), zoned, "zone-c"))
+	})
+
+	t.Run("all targets are kept when the topology of any is unknown", func(t *testing.T) {
+		targets := newTargets("10.0.0.1:80", "10.96.0.10:80")
+		assert.Equal(t, targets, filterTopologyTargets(logrus.New(), s, svc("zoned"), targets, zoneA))
+	})
+
+	t.Run("services failing over from a primary zone keep the targets of all zones", func(t *testing.T) {
+		primary := svc("zoned")
+		primary.Annotations = map[string]string{annotations.AnnotationPrefix + annotations.UpstreamPolicyKey: "failover"}
+		assert.Equal(t, zoned, filterTopologyTargets(logrus.New(), s, primary, zoned, zoneB))
+	})
+}
+
+func Test_getEndpointSliceEndpoints(t *testing.T) {
+	ready, notReady := true, false
+	terminating := func(serving bool) discoveryv1.EndpointConditions {
+		return discoveryv1.EndpointConditions{Ready: &notReady, Serving: &serving, Terminating: &ready}
+	}
+	tcp, udp := corev1.ProtocolTCP, corev1.ProtocolUDP
+	httpPort, dnsPort := int32(8080), int32(5353)
+	endpointSlices := []*discoveryv1.EndpointSlice{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "db-1",
+				Namespace: "default",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "db"},
+			},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Ports: []discoveryv1.EndpointPort{
+				{Name: kong.String("http"), Protocol: &tcp, Port: &httpPort},
+				{Name: kong.String("dns"), Protocol: &udp, Port: &dnsPort},
+			},
+			Endpoints: []discoveryv1.Endpoint{
+				{Addresses: []string{"10.0.0.1"}, Conditions: discoveryv1.EndpointConditions{Ready: &ready}},
+				{Addresses: []string{"10.0.0.2"}},
+				{Addresses: []string{"10.0.0.3"}, Conditions: discoveryv1.EndpointConditions{Ready: &notReady}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "portless-1",
+				Namespace: "default",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "portless"},
+			},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Endpoints: []discoveryv1.Endpoint{
+				{Addresses: []string{"10.0.1.1"}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "web-1",
+				Namespace: "default",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "web"},
+			},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Ports:       []discoveryv1.EndpointPort{{Protocol: &tcp, Port: &httpPort}},
+			Endpoints: []discoveryv1.Endpoint{
+				{Addresses: []string{"10.0.2.1"}, Conditions: discoveryv1.EndpointConditions{Ready: &ready, Serving: &ready}},
+				{Addresses: []string{"10.0.2.2"}, Conditions: terminating(true)},
+				{Addresses: []string{"10.0.2.3"}, Conditions: terminating(false)},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "draining-1",
+				Namespace: "default",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "draining"},
+			},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Ports:       []discoveryv1.EndpointPort{{Protocol: &tcp, Port: &httpPort}},
+			Endpoints: []discoveryv1.Endpoint{
+				{Addresses: []string{"10.0.3.1"}, Conditions: terminating(true)},
+				{Addresses: []string{"10.0.3.2"}, Conditions: terminating(false)},
+			},
+		},
+	}
+	s, err := store.NewFakeStore(store.FakeObjects{EndpointSlices: endpointSlices})
+	require.NoError(t, err)
+
+	headless := func(name string, ports ...corev1.ServicePort) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       corev1.ServiceSpec{ClusterIP: corev1.ClusterIPNone, Ports: ports},
+		}
+	}
+
+	t.Run("ready endpoints of the matching port are targeted", func(t *testing.T) {
+		svc := headless("db", corev1.ServicePort{Name: "http", Port: 80, TargetPort: intstr.FromString("http")})
+		endpoints := getEndpointSliceEndpoints(logrus.New(), svc, &svc.Spec.Ports[0], s.GetEndpointSlicesForService)
+		assert.Equal(t, []util.Endpoint{
+			{Address: "10.0.0.1", Port: "8080"},
+			{Address: "10.0.0.2", Port: "8080"},
+		}, endpoints)
+	})
+
+	t.Run("not ready endpoints are targeted if the service publishes them", func(t *testing.T) {
+		svc := headless("db", corev1.ServicePort{Name: "dns", Protocol: udp, Port: 53})
+		svc.Spec.PublishNotReadyAddresses = true
+		endpoints := getEndpointSliceEndpoints(logrus.New(), svc, &svc.Spec.Ports[0], s.GetEndpointSlicesForService)
+		assert.Equal(t, []util.Endpoint{
+			{Address: "10.0.0.1", Port: "5353"},
+			{Address: "10.0.0.2", Port: "5353"},
+			{Address: "10.0.0.3", Port: "5353"},
+		}, endpoints)
+	})
+
+	t.Run("services without ports are targeted on the requested port", func(t *testing.T) {
+		svc := headless("portless")
+		port, err := findPort(svc, kongstate.PortDef{Mode: kongstate.PortModeByNumber, Number: 9000})
+		require.NoError(t, err)
+		endpoints := getEndpointSliceEndpoints(logrus.New(), svc, port, s.GetEndpointSlicesForService)
+		assert.Equal(t, []util.Endpoint{{Address: "10.0.1.1", Port: "9000"}}, endpoints)
+	})
+
+	t.Run("services without endpoint slices have no endpoints", func(t *testing.T) {
+		svc := headless("missing", corev1.ServicePort{Port: 80})
+		endpoints := getEndpointSliceEndpoints(logrus.New(), svc, &svc.Spec.Ports[0], s.GetEndpointSlicesForService)
+		assert.Empty(t, endpoints)
+	})
+
+	for _, tt := range []struct {
+		name        string
+		service     string
+		drainPolicy string
+		want        []util.Endpoint
+	}{
+		{
+			name:    "terminating endpoints are dropped by default",
+			service: "web",
+			want:    []util.Endpoint{{Address: "10.0.2.1", Port: "8080"}},
+		},
+		{
+			name:        "terminating endpoints are dropped on an invalid drain policy",
+			service:     "web",
+			drainPolicy: "linger",
+			want:        []util.Endpoint{{Address: "10.0.2.1", Port: "8080"}},
+		},
+		{
+			name:        "serving terminating endpoints are targeted when draining",
+			service:     "web",
+			drainPolicy: "drain",
+			want: []util.Endpoint{
+				{Address: "10.0.2.1", Port: "8080"},
+				{Address: "10.0.2.2", Port: "8080"},
+			},
+		},
+		{
+			name:        "serving terminating endpoints are not targeted as fallback while others are ready",
+			service:     "web",
+			drainPolicy: "fallback",
+			want:        []util.Endpoint{{Address: "10.0.2.1", Port: "8080"}},
+		},
+		{
+			name:        "serving terminating endpoints are targeted as fallback when none are ready",
+			service:     "draining",
+			drainPolicy: "fallback",
+			want:        []util.Endpoint{{Address: "10.0.3.1", Port: "8080"}},
+		},
+		{
+			name:    "terminating endpoints are dropped by default when none are ready",
+			service: "draining",
+			want:    []util.Endpoint{},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := headless(tt.service, corev1.ServicePort{Port: 80})
+			if tt.drainPolicy != "" {
+				svc.Annotations = map[string]string{"konghq.com/drain-policy": tt.drainPolicy}
+			}
+			endpoints := getEndpointSliceEndpoints(logrus.New(), svc, &svc.Spec.Ports[0], s.GetEndpointSlicesForService)
+			assert.Equal(t, tt.want, endpoints)
+		})
+	}
+}
+
+func Test_getServiceEndpoints_endpointSliceTargets(t *testing.T) {
+	ready, terminating := true, true
+	port := int32(8080)
+	s, err := store.NewFakeStore(store.FakeObjects{
+		Endpoints: []*corev1.Endpoints{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+				Subsets: []corev1.EndpointSubset{
+					{
+						Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}},
+						Ports:     []corev1.EndpointPort{{Port: port, Protocol: corev1.ProtocolTCP}},
+					},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "legacy", Namespace: "default"},
+				Subsets: []corev1.EndpointSubset{
+					{
+						Addresses: []corev1.EndpointAddress{{IP: "10.0.1.1"}},
+						Ports:     []corev1.EndpointPort{{Port: port, Protocol: corev1.ProtocolTCP}},
+					},
+				},
+			},
+		},
+		EndpointSlices: []*discoveryv1.EndpointSlice{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "web-1",
+					Namespace: "default",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "web"},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Ports:       []discoveryv1.EndpointPort{{Port: &port}},
+				Endpoints: []discoveryv1.Endpoint{
+					{Addresses: []string{"10.0.0.1"}},
+					{Addresses: []string{"10.0.0.2"}, Conditions: discoveryv1.EndpointConditions{Ready: &ready, Serving: &ready, Terminating: &terminating}},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "web",
+			Namespace:   "default",
+			Annotations: map[string]string{"konghq.com/drain-policy": "drain"},
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: "10.96.0.10",
+			Ports:     []corev1.ServicePort{{Port: 80, TargetPort: intstr.FromInt(8080)}},
+		},
+	}
+	targets := func(endpointSliceTargets bool) []string {
+		var addresses []string
+		for _, target := range getServiceEndpoints(logrus.New(), s, svc, &svc.Spec.Ports[0], endpointSliceTargets) {
+			addresses = append(addresses, *target.Target.Target)
+		}
+		return addresses
+	}
+
+	assert.Equal(t, []string{"10.0.0.1:8080"}, targets(false), "targets are generated from Endpoints by default")
+	assert.Equal(t, []string{"10.0.0.1:8080", "10.0.0.2:8080"}, targets(true), "targets are generated from EndpointSlices when enabled")
+
+	svc.Name = "legacy"
+	assert.Equal(t, []string{"10.0.1.1:8080"}, targets(true), "services without EndpointSlices fall back to their Endpoints")
+}
@@ -0,0 +1,102 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	mcsv1alpha1 "github.com/kong/kubernetes-ingress-controller/v2/internal/apis/multicluster/v1alpha1"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+)
+
+// -----------------------------------------------------------------------------
+// Parser - Multi-Cluster Services
+// -----------------------------------------------------------------------------
+
+const serviceImportKind = "ServiceImport"
+
+// isServiceImportRef indicates whether a Gateway API backendRef refers to a
+// multi-cluster ServiceImport rather than to a Service.
+func isServiceImportRef(group *gatewayv1alpha2.Group, kind *gatewayv1alpha2.Kind) bool {
+	return group != nil && string(*group) == mcsv1alpha1.GroupVersion.Group &&
+		kind != nil && string(*kind) == serviceImportKind
+}
+
+// getServiceForServiceImport provides a Service standing for the ServiceImport
+// in namespace, so that imported services are translated like local ones.
+// The Service is owned by the ServiceImport, which marks it as imported.
+func getServiceForServiceImport(s store.Storer, namespace, name string) (*corev1.Service, error) {
+	serviceImport, err := s.GetServiceImport(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        serviceImport.Name,
+			Namespace:   serviceImport.Namespace,
+			Annotations: serviceImport.Annotations,
+			Labels:      serviceImport.Labels,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: mcsv1alpha1.GroupVersion.String(),
+				Kind:       serviceImportKind,
+				Name:       serviceImport.Name,
+				UID:        serviceImport.UID,
+			}},
+		},
+	}
+
+	switch serviceImport.Spec.Type {
+	case mcsv1alpha1.Headless:
+		svc.Spec.ClusterIP = corev1.ClusterIPNone
+	default:
+		if len(serviceImport.Spec.IPs) == 0 {
+			return nil, fmt.Errorf("ServiceImport %s/%s has no ClusterSet IP", namespace, name)
+		}
+		svc.Spec.ClusterIP = serviceImport.Spec.IPs[0]
+	}
+
+	for _, port := range serviceImport.Spec.Ports {
+		svc.Spec.Ports = append(svc.Spec.Ports, corev1.ServicePort{
+			Name:        port.Name,
+			Protocol:    port.Protocol,
+			AppProtocol: port.AppProtocol,
+			Port:        port.Port,
+			TargetPort:  intstr.FromInt(int(port.Port)),
+		})
+	}
+	return svc, nil
+}
+
+// isImportedService indicates whether a Service stands for a ServiceImport.
+func isImportedService(svc *corev1.Service) bool {
+	for _, owner := range svc.OwnerReferences {
+		if owner.APIVersion == mcsv1alpha1.GroupVersion.String() && owner.Kind == serviceImportKind {
+			return true
+		}
+	}
+	return false
+}
+
+// getImportedServiceEndpoints returns a list of <endpoint ip>:<port> for a given
+// imported service/port combination: the ClusterSet IP of the ServiceImport or,
+// for headless ones, the endpoints imported from the clusters of the ClusterSet.
+func getImportedServiceEndpoints(
+	log logrus.FieldLogger,
+	s store.Storer,
+	svc *corev1.Service,
+	servicePort *corev1.ServicePort,
+) []util.Endpoint {
+	if isHeadlessService(svc) {
+		return getEndpointSliceEndpoints(log, svc, servicePort, s.GetEndpointSlicesForServiceImport)
+	}
+	return []util.Endpoint{{
+		Address: svc.Spec.ClusterIP,
+		Port:    fmt.Sprint(servicePort.Port),
+	}}
+}
@@ -0,0 +1,121 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	mcsv1alpha1 "github.com/kong/kubernetes-ingress-controller/v2/internal/apis/multicluster/v1alpha1"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
+)
+
+func TestIsServiceImportRef(t *testing.T) {
+	group := func(s string) *gatewayv1alpha2.Group { g := gatewayv1alpha2.Group(s); return &g }
+	kind := func(s string) *gatewayv1alpha2.Kind { k := gatewayv1alpha2.Kind(s); return &k }
+
+	assert.True(t, isServiceImportRef(group("multicluster.x-k8s.io"), kind("ServiceImport")))
+	assert.False(t, isServiceImportRef(group(""), kind("Service")))
+	assert.False(t, isServiceImportRef(nil, kind("ServiceImport")))
+	assert.False(t, isServiceImportRef(group("multicluster.x-k8s.io"), nil))
+}
+
+func TestServiceImportEndpoints(t *testing.T) {
+	port := int32(8080)
+	s, err := store.NewFakeStore(store.FakeObjects{
+		ServiceImports: []*mcsv1alpha1.ServiceImport{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+				Spec: mcsv1alpha1.ServiceImportSpec{
+					Type:  mcsv1alpha1.ClusterSetIP,
+					IPs:   []string{"10.112.0.10"},
+					Ports: []mcsv1alpha1.ServicePort{{Port: 80, Protocol: corev1.ProtocolTCP}},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+				Spec: mcsv1alpha1.ServiceImportSpec{
+					Type:  mcsv1alpha1.Headless,
+					Ports: []mcsv1alpha1.ServicePort{{Port: 8080, Protocol: corev1.ProtocolTCP}},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "no-ip", Namespace: "default"},
+				Spec:       mcsv1alpha1.ServiceImportSpec{Type: mcsv1alpha1.ClusterSetIP},
+			},
+		},
+		// a local Service (and its endpoints) with the same name as an imported
+		// one must never be targeted for the ServiceImport
+		Endpoints: []*corev1.Endpoints{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+				Subsets: []corev1.EndpointSubset{
+					{
+						Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}},
+						Ports:     []corev1.EndpointPort{{Port: 80, Protocol: corev1.ProtocolTCP}},
+					},
+				},
+			},
+		},
+		EndpointSlices: []*discoveryv1.EndpointSlice{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "imported-db-cluster-a",
+					Namespace: "default",
+					Labels:    map[string]string{mcsv1alpha1.LabelServiceName: "db"},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Ports:       []discoveryv1.EndpointPort{{Port: &port}},
+				Endpoints:   []discoveryv1.Endpoint{{Addresses: []string{"10.1.0.1"}}},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "imported-db-cluster-b",
+					Namespace: "default",
+					Labels:    map[string]string{mcsv1alpha1.LabelServiceName: "db"},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Ports:       []discoveryv1.EndpointPort{{Port: &port}},
+				Endpoints:   []discoveryv1.Endpoint{{Addresses: []string{"10.2.0.1"}}},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	targets := func(svc *corev1.Service) []string {
+		var addresses []string
+		for _, target := range getServiceEndpoints(logrus.New(), s, svc, &svc.Spec.Ports[0], false) {
+			addresses = append(addresses, *target.Target.Target)
+		}
+		return addresses
+	}
+
+	t.Run("ClusterSetIP imports are targeted through their ClusterSet IP", func(t *testing.T) {
+		svc, err := getServiceForServiceImport(s, "default", "web")
+		require.NoError(t, err)
+		assert.True(t, isImportedService(svc))
+		assert.Equal(t, []string{"10.112.0.10:80"}, targets(svc))
+	})
+
+	t.Run("headless imports are targeted through their imported endpoints", func(t *testing.T) {
+		svc, err := getServiceForServiceImport(s, "default", "db")
+		require.NoError(t, err)
+		assert.True(t, isImportedService(svc))
+		assert.Equal(t, []string{"10.1.0.1:8080", "10.2.0.1:8080"}, targets(svc))
+	})
+
+	t.Run("ClusterSetIP imports without IPs are rejected", func(t *testing.T) {
+		_, err := getServiceForServiceImport(s, "default", "no-ip")
+		require.Error(t, err)
+	})
+
+	t.Run("missing imports are rejected", func(t *testing.T) {
+		_, err := getServiceForServiceImport(s, "default", "missing")
+		require.Error(t, err)
+	})
+}
@@ -2,12 +2,15 @@ package parser
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
+	"github.com/blang/semver/v4"
 	"github.com/kong/go-kong/kong"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
 )
 
@@ -42,6 +45,111 @@ func pathsFromK8s(path string, pathType networkingv1.PathType) ([]*string, error
 	return nil, fmt.Errorf("unknown pathType %v", pathType)
 }
 
+// MinRegexPathPrefixKongVersion is the minimum Kong version whose router
+// requires regex paths to be prefixed with kongPathRegexPrefix.
+var MinRegexPathPrefixKongVersion = semver.MustParse("3.0.0")
+
+const (
+	// kongPathRegexPrefix is the prefix Kong 3.0+ uses to determine if it should
+	// parse a path as a regex.
+	kongPathRegexPrefix = "~"
+
+	// defaultRegexPrefix is the prefix users can put in front of Ingress paths
+	// to mark them as regex paths, unless overridden by the regex-prefix annotation.
+	defaultRegexPrefix = "/~"
+)
+
+// legacyRegexPathChars matches the characters that made Kong versions prior
+// to 3.0 treat a path as a regex.
+var legacyRegexPathChars = regexp.MustCompile(`[^a-zA-Z0-9._~/%-]`)
+
+// prefixRegexPaths adds the regex prefix Kong 3.0+ requires to the regex paths
+// of all routes. Paths starting with the regex prefix of the object the route was
+// translated from have it replaced by the Kong one. Other paths are considered
+// regex paths if they end with "$" (as the ones generated for Exact and Prefix
+// path types do) or, if the object asks for legacy behavior, if Kong versions
+// prior to 3.0 would have considered them regex paths.
+func prefixRegexPaths(state *kongstate.KongState) {
+	for i := range state.Services {
+		for j := range state.Services[i].Routes {
+			route := &state.Services[i].Routes[j]
+			regexPrefix := defaultRegexPrefix
+			if prefix, ok := annotations.ExtractRegexPrefix(route.Ingress.Annotations); ok && prefix != "" {
+				regexPrefix = prefix
+			}
+			legacy := annotations.ExtractLegacyRegexPath(route.Ingress.Annotations) == "true"
+			for k, path := range route.Paths {
+				if path == nil {
+					continue
+				}
+				route.Paths[k] = kong.String(maybePrefixRegexPath(*path, regexPrefix, legacy))
+			}
+		}
+	}
+}
+
+// maybePrefixRegexPath provides the path as Kong 3.0+ expects it, prefixed
+// with kongPathRegexPrefix if it's a regex path.
+func maybePrefixRegexPath(path, regexPrefix string, legacy bool) string {
+	switch {
+	case strings.HasPrefix(path, kongPathRegexPrefix):
+		return path
+	case hasPathSegmentsPrefix(path, regexPrefix):
+		return kongPathRegexPrefix + "/" + strings.TrimLeft(strings.TrimPrefix(path, regexPrefix), "/")
+	case legacy:
+		if !strings.HasPrefix(path, "/") || legacyRegexPathChars.MatchString(path) {
+			return kongPathRegexPrefix + path
+		}
+	case strings.HasSuffix(path, "$"):
+		return kongPathRegexPrefix + path
+	}
+	return path
+}
+
+// hasPathSegmentsPrefix reports whether prefix is made of whole segments of
+// path, e.g. "/re" is one of "/re/foo" but not of "/rest".
+func hasPathSegmentsPrefix(path, prefix string) bool {
+	if !strings.HasPrefix(path, prefix) {
+		return false
+	}
+	return len(path) == len(prefix) || strings.HasSuffix(prefix, "/") || path[len(prefix)] == '/'
+}
+
+// KongRegexPaths provides the regexes Kong matches requests against among the
+// paths an Ingress rule path translates to, without the Kong regex prefix.
+// Kong versions prior to 3.0 consider paths regexes based on their characters,
+// later ones based on their prefix.
+func KongRegexPaths(
+	path string,
+	pathType networkingv1.PathType,
+	ingressAnnotations map[string]string,
+	kongVersion semver.Version,
+) ([]string, error) {
+	paths, err := pathsFromK8s(path, pathType)
+	if err != nil {
+		return nil, err
+	}
+	regexPrefix := defaultRegexPrefix
+	if prefix, ok := annotations.ExtractRegexPrefix(ingressAnnotations); ok && prefix != "" {
+		regexPrefix = prefix
+	}
+	legacy := annotations.ExtractLegacyRegexPath(ingressAnnotations) == "true"
+
+	var regexes []string
+	for _, p := range paths {
+		if kongVersion.LT(MinRegexPathPrefixKongVersion) {
+			if !strings.HasPrefix(*p, "/") || legacyRegexPathChars.MatchString(*p) {
+				regexes = append(regexes, *p)
+			}
+			continue
+		}
+		if prefixed := maybePrefixRegexPath(*p, regexPrefix, legacy); strings.HasPrefix(prefixed, kongPathRegexPrefix) {
+			regexes = append(regexes, strings.TrimPrefix(prefixed, kongPathRegexPrefix))
+		}
+	}
+	return regexes, nil
+}
+
 var priorityForPath = map[networkingv1.PathType]int{
 	networkingv1.PathTypeExact:                  300,
 	networkingv1.PathTypePrefix:                 200,
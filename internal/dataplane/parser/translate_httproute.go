@@ -2,12 +2,16 @@ package parser
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/kong/go-kong/kong"
 	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+	configurationv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
 )
 
 // -----------------------------------------------------------------------------
@@ -90,6 +94,7 @@ func (p *Parser) ingressRulesFromHTTPRoute(result *ingressRules, httproute *gate
 		result.ServiceNameToServices[*service.Service.Name] = service
 	}
 
+	result.addTLSFromCertManager(httproute)
 	return nil
 }
 
@@ -120,6 +125,11 @@ func generateKongRoutesFromHTTPRouteRule(httproute *gatewayv1alpha2.HTTPRoute, r
 	objectInfo := util.FromK8sObject(httproute)
 	hostnames := getHTTPRouteHostnamesAsSliceOfStringPointers(httproute)
 
+	// attach the KongPlugins referenced by the rule's filters to its routes
+	if err := addExtensionRefPlugins(&objectInfo, rule.Filters); err != nil {
+		return nil, err
+	}
+
 	// the HTTPRoute specification upstream specifically defines matches as
 	// independent (e.g. each match is an OR with other matches, not an AND).
 	// Therefore we treat each match rule as a separate Kong Route, so we iterate through
@@ -185,6 +195,11 @@ func generateKongRoutesFromHTTPRouteRule(httproute *gatewayv1alpha2.HTTPRoute, r
 				r.Route.Headers = headers
 			}
 
+			// translate the rule's filters for the route
+			if err := applyHTTPRouteFilters(&r, rule.Filters, match.Path); err != nil {
+				return nil, err
+			}
+
 			// add the route to the list of routes for the service(s)
 			routes = append(routes, r)
 		}
@@ -210,8 +225,193 @@ func generateKongRoutesFromHTTPRouteRule(httproute *gatewayv1alpha2.HTTPRoute, r
 
 		// otherwise apply the hostnames to the route
 		r.Hosts = append(r.Hosts, hostnames...)
+
+		// translate the rule's filters for the route
+		if err := applyHTTPRouteFilters(&r, rule.Filters, nil); err != nil {
+			return nil, err
+		}
 		routes = append(routes, r)
 	}
 
 	return routes, nil
 }
+
+// addExtensionRefPlugins adds the KongPlugins referenced by ExtensionRef filters
+// to the plugins annotation of the routes' object information. Plugins are then
+// attached to the routes generated for the rule in the same way as the plugins
+// listed in the HTTPRoute's own konghq.com/plugins annotation.
+func addExtensionRefPlugins(objectInfo *util.K8sObjectInfo, filters []gatewayv1alpha2.HTTPRouteFilter) error {
+	pluginNames := annotations.ExtractKongPluginsFromAnnotations(objectInfo.Annotations)
+	seen := make(map[string]struct{}, len(pluginNames))
+	for _, name := range pluginNames {
+		seen[name] = struct{}{}
+	}
+
+	added := false
+	for _, filter := range filters {
+		if filter.Type != gatewayv1alpha2.HTTPRouteFilterExtensionRef || filter.ExtensionRef == nil {
+			continue
+		}
+		ref := filter.ExtensionRef
+		if string(ref.Group) != configurationv1.GroupVersion.Group || string(ref.Kind) != "KongPlugin" {
+			return fmt.Errorf("unsupported extensionRef filter %s/%s %s: only %s/KongPlugin is supported",
+				ref.Group, ref.Kind, ref.Name, configurationv1.GroupVersion.Group)
+		}
+		if _, ok := seen[string(ref.Name)]; ok {
+			continue
+		}
+		seen[string(ref.Name)] = struct{}{}
+		pluginNames = append(pluginNames, string(ref.Name))
+		added = true
+	}
+
+	if added {
+		if objectInfo.Annotations == nil {
+			objectInfo.Annotations = make(map[string]string)
+		}
+		objectInfo.Annotations[annotations.AnnotationPrefix+annotations.PluginsKey] = strings.Join(pluginNames, ",")
+	}
+	return nil
+}
+
+// requestTransformerPluginName is the name of the Kong plugin HTTPRoute
+// filters modifying requests are translated into.
+const requestTransformerPluginName = "request-transformer"
+
+// applyHTTPRouteFilters translates the standard filters of an HTTPRoute rule
+// into the configuration of one of the rule's routes, the path match of which
+// is given (nil if the route doesn't match on paths):
+//
+//   - RequestHeaderModifier and URLRewrite filters are translated into the
+//     request-transformer plugin.
+//   - RequestRedirect filters redirecting requests to https are translated into
+//     the route's https redirect, other redirects aren't supported by Kong.
+//   - RequestMirror filters aren't supported by Kong.
+//
+// ExtensionRef filters are handled by addExtensionRefPlugins.
+func applyHTTPRouteFilters(r *kongstate.Route, filters []gatewayv1alpha2.HTTPRouteFilter, pathMatch *gatewayv1alpha2.HTTPPathMatch) error {
+	transformer := make(map[string]map[string]interface{})
+	transform := func(action, key string, value interface{}) {
+		if transformer[action] == nil {
+			transformer[action] = make(map[string]interface{})
+		}
+		if headers, ok := value.([]string); ok {
+			existing, _ := transformer[action][key].([]string)
+			value = append(existing, headers...)
+		}
+		transformer[action][key] = value
+	}
+
+	for _, filter := range filters {
+		switch filter.Type {
+		case gatewayv1alpha2.HTTPRouteFilterRequestHeaderModifier:
+			if filter.RequestHeaderModifier == nil {
+				continue
+			}
+			modifier := filter.RequestHeaderModifier
+			if len(modifier.Set) > 0 {
+				// the plugin only replaces headers which are present and only
+				// adds those which aren't, doing both sets them either way.
+				transform("replace", "headers", kongHeaders(modifier.Set))
+				transform("add", "headers", kongHeaders(modifier.Set))
+			}
+			if len(modifier.Add) > 0 {
+				transform("append", "headers", kongHeaders(modifier.Add))
+			}
+			if len(modifier.Remove) > 0 {
+				transform("remove", "headers", append([]string(nil), modifier.Remove...))
+			}
+
+		case gatewayv1alpha2.HTTPRouteFilterURLRewrite:
+			if filter.URLRewrite == nil {
+				continue
+			}
+			rewrite := filter.URLRewrite
+			if rewrite.Hostname != nil {
+				// the plugin sets the upstream host when replacing the Host header
+				transform("replace", "headers", []string{"host:" + string(*rewrite.Hostname)})
+			}
+			if rewrite.Path != nil {
+				uri, err := rewriteHTTPRoutePath(r, *rewrite.Path, pathMatch)
+				if err != nil {
+					return err
+				}
+				transform("replace", "uri", uri)
+			}
+
+		case gatewayv1alpha2.HTTPRouteFilterRequestRedirect:
+			if filter.RequestRedirect == nil {
+				continue
+			}
+			redirect := filter.RequestRedirect
+			if redirect.Scheme == nil || *redirect.Scheme != "https" ||
+				redirect.Hostname != nil || redirect.Path != nil || redirect.Port != nil {
+				return fmt.Errorf("unsupported RequestRedirect filter: only redirects to https are supported")
+			}
+			statusCode := 302
+			if redirect.StatusCode != nil {
+				statusCode = *redirect.StatusCode
+			}
+			r.Protocols = kong.StringSlice("https")
+			r.HTTPSRedirectStatusCode = kong.Int(statusCode)
+
+		case gatewayv1alpha2.HTTPRouteFilterRequestMirror:
+			return fmt.Errorf("unsupported RequestMirror filter: requests can't be mirrored")
+		}
+	}
+
+	if len(transformer) > 0 {
+		config := make(kong.Configuration, len(transformer))
+		for action, fields := range transformer {
+			config[action] = fields
+		}
+		r.Plugins = append(r.Plugins, kong.Plugin{
+			Name:   kong.String(requestTransformerPluginName),
+			Config: config,
+		})
+	}
+	return nil
+}
+
+// kongHeaders formats HTTPRoute headers as the name:value pairs the
+// request-transformer plugin is configured with.
+func kongHeaders(headers []gatewayv1alpha2.HTTPHeader) []string {
+	result := make([]string, 0, len(headers))
+	for _, header := range headers {
+		result = append(result, fmt.Sprintf("%s:%s", header.Name, header.Value))
+	}
+	return result
+}
+
+// rewriteHTTPRoutePath provides the URI template the request-transformer plugin
+// proxies the requests matching the route with to apply a URLRewrite path. To
+// replace the matched prefix, the prefix path of the route is turned into a
+// regex capturing the rest of the path. The regex ends with "$" for
+// prefixRegexPaths to consider it one on Kong 3.0+.
+func rewriteHTTPRoutePath(r *kongstate.Route, path gatewayv1alpha2.HTTPPathModifier, pathMatch *gatewayv1alpha2.HTTPPathMatch) (string, error) {
+	// any $ would be evaluated by the template engine of the plugin
+	if strings.Contains(path.Substitution, "$") {
+		return "", fmt.Errorf("unsupported URLRewrite path %q: paths can't contain $", path.Substitution)
+	}
+	switch path.Type {
+	case gatewayv1alpha2.AbsoluteHTTPPathModifier:
+		return path.Substitution, nil
+	case gatewayv1alpha2.PrefixMatchHTTPPathModifier:
+		prefix := "/"
+		if pathMatch != nil && pathMatch.Value != nil {
+			if pathMatch.Type == nil || *pathMatch.Type != gatewayv1alpha2.PathMatchPathPrefix {
+				return "", fmt.Errorf("URLRewrite path prefix replacements require a PathPrefix match")
+			}
+			prefix = *pathMatch.Value
+		}
+		prefix = strings.TrimSuffix(prefix, "/")
+		regex := "/(.*)$"
+		if prefix != "" {
+			regex = regexp.QuoteMeta(prefix) + "/?(.*)$"
+		}
+		r.Paths = kong.StringSlice(regex)
+		return strings.TrimSuffix(path.Substitution, "/") + "/$(uri_captures[1])", nil
+	default:
+		return "", fmt.Errorf("unsupported URLRewrite path type %q", path.Type)
+	}
+}
@@ -7,14 +7,17 @@ import (
 	"github.com/kong/go-kong/kong"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+	configurationv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
 )
 
 // httprouteGVK is the GVK for HTTPRoutes, needed in unit tests because
@@ -116,6 +119,7 @@ func Test_ingressRulesFromHTTPRoutes(t *testing.T) {
 								Name:        "basic-httproute",
 								Namespace:   corev1.NamespaceDefault,
 								Annotations: make(map[string]string),
+								Labels:      make(map[string]string),
 								GroupVersionKind: schema.GroupVersionKind{
 									Group:   "gateway.networking.k8s.io",
 									Version: "v1alpha2",
@@ -267,6 +271,7 @@ func Test_ingressRulesFromHTTPRoutes(t *testing.T) {
 								Name:        "basic-httproute",
 								Namespace:   corev1.NamespaceDefault,
 								Annotations: make(map[string]string),
+								Labels:      make(map[string]string),
 								GroupVersionKind: schema.GroupVersionKind{
 									Group:   "gateway.networking.k8s.io",
 									Version: "v1alpha2",
@@ -450,6 +455,7 @@ func Test_ingressRulesFromHTTPRoutes(t *testing.T) {
 								Name:        "basic-httproute",
 								Namespace:   corev1.NamespaceDefault,
 								Annotations: make(map[string]string),
+								Labels:      make(map[string]string),
 								GroupVersionKind: schema.GroupVersionKind{
 									Group:   "gateway.networking.k8s.io",
 									Version: "v1alpha2",
@@ -570,6 +576,7 @@ func Test_ingressRulesFromHTTPRoutes(t *testing.T) {
 								Name:        "basic-httproute",
 								Namespace:   corev1.NamespaceDefault,
 								Annotations: make(map[string]string),
+								Labels:      make(map[string]string),
 								GroupVersionKind: schema.GroupVersionKind{
 									Group:   "gateway.networking.k8s.io",
 									Version: "v1alpha2",
@@ -694,3 +701,363 @@ func Test_getHTTPRouteHostnamesAsSliceOfStringPointers(t *testing.T) {
 		})
 	}
 }
+
+func Test_addExtensionRefPlugins(t *testing.T) {
+	pluginsKey := annotations.AnnotationPrefix + annotations.PluginsKey
+	kongPluginFilter := func(name string) gatewayv1alpha2.HTTPRouteFilter {
+		return gatewayv1alpha2.HTTPRouteFilter{
+			Type: gatewayv1alpha2.HTTPRouteFilterExtensionRef,
+			ExtensionRef: &gatewayv1alpha2.LocalObjectReference{
+				Group: gatewayv1alpha2.Group(configurationv1.GroupVersion.Group),
+				Kind:  gatewayv1alpha2.Kind("KongPlugin"),
+				Name:  gatewayv1alpha2.ObjectName(name),
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		msg         string
+		annotations map[string]string
+		filters     []gatewayv1alpha2.HTTPRouteFilter
+		expected    map[string]string
+		expectedErr bool
+	}{
+		{
+			msg:         "a rule without filters leaves the annotations untouched",
+			annotations: map[string]string{pluginsKey: "auth"},
+			expected:    map[string]string{pluginsKey: "auth"},
+		},
+		{
+			msg: "KongPlugins referenced by filters are added to the plugins annotation",
+			filters: []gatewayv1alpha2.HTTPRouteFilter{
+				kongPluginFilter("rate-limit"),
+				{Type: gatewayv1alpha2.HTTPRouteFilterRequestHeaderModifier},
+				kongPluginFilter("cors"),
+			},
+			expected: map[string]string{pluginsKey: "rate-limit,cors"},
+		},
+		{
+			msg:         "KongPlugins referenced by filters are merged with the HTTPRoute's plugins annotation",
+			annotations: map[string]string{pluginsKey: "auth, cors"},
+			filters: []gatewayv1alpha2.HTTPRouteFilter{
+				kongPluginFilter("cors"),
+				kongPluginFilter("rate-limit"),
+			},
+			expected: map[string]string{pluginsKey: "auth,cors,rate-limit"},
+		},
+		{
+			msg: "extensionRef filters referencing other kinds are rejected",
+			filters: []gatewayv1alpha2.HTTPRouteFilter{{
+				Type: gatewayv1alpha2.HTTPRouteFilterExtensionRef,
+				ExtensionRef: &gatewayv1alpha2.LocalObjectReference{
+					Group: gatewayv1alpha2.Group("example.net"),
+					Kind:  gatewayv1alpha2.Kind("MyFilter"),
+					Name:  gatewayv1alpha2.ObjectName("my-filter"),
+				},
+			}},
+			expectedErr: true,
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			objectInfo := util.K8sObjectInfo{Annotations: tt.annotations}
+			err := addExtensionRefPlugins(&objectInfo, tt.filters)
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, objectInfo.Annotations)
+		})
+	}
+}
+
+func TestHTTPRouteExtensionRefPlugins(t *testing.T) {
+	httpPort := gatewayv1alpha2.PortNumber(80)
+	httproute := &gatewayv1alpha2.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "basic-httproute",
+			Namespace: corev1.NamespaceDefault,
+		},
+		Spec: gatewayv1alpha2.HTTPRouteSpec{
+			Hostnames: []gatewayv1alpha2.Hostname{"konghq.com"},
+			Rules: []gatewayv1alpha2.HTTPRouteRule{{
+				Filters: []gatewayv1alpha2.HTTPRouteFilter{{
+					Type: gatewayv1alpha2.HTTPRouteFilterExtensionRef,
+					ExtensionRef: &gatewayv1alpha2.LocalObjectReference{
+						Group: gatewayv1alpha2.Group(configurationv1.GroupVersion.Group),
+						Kind:  gatewayv1alpha2.Kind("KongPlugin"),
+						Name:  gatewayv1alpha2.ObjectName("key-auth"),
+					},
+				}},
+				BackendRefs: []gatewayv1alpha2.HTTPBackendRef{{
+					BackendRef: gatewayv1alpha2.BackendRef{
+						BackendObjectReference: gatewayv1alpha2.BackendObjectReference{
+							Name: gatewayv1alpha2.ObjectName("fake-service"),
+							Port: &httpPort,
+						},
+					},
+				}},
+			}},
+		},
+	}
+	httproute.SetGroupVersionKind(httprouteGVK)
+
+	fakestore, err := store.NewFakeStore(store.FakeObjects{
+		HTTPRoutes: []*gatewayv1alpha2.HTTPRoute{httproute},
+		KongPlugins: []*configurationv1.KongPlugin{{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "key-auth",
+				Namespace: corev1.NamespaceDefault,
+			},
+			PluginName: "key-auth",
+		}},
+	})
+	require.NoError(t, err)
+	p := NewParser(logrus.New(), fakestore)
+
+	state, err := p.Build()
+	require.NoError(t, err)
+	require.Len(t, state.Plugins, 1)
+	assert.Equal(t, "key-auth", *state.Plugins[0].Name)
+	require.NotNil(t, state.Plugins[0].Route)
+	assert.Equal(t, "httproute.default.basic-httproute.0.0", *state.Plugins[0].Route.ID)
+}
+
+func Test_applyHTTPRouteFilters(t *testing.T) {
+	pathPrefix := gatewayv1alpha2.PathMatchPathPrefix
+	pathExact := gatewayv1alpha2.PathMatchExact
+	https := "https"
+	http := "http"
+	hostname := gatewayv1alpha2.PreciseHostname("example.com")
+	rewriteHostname := gatewayv1alpha2.Hostname("internal.example.com")
+	permanent := 301
+
+	for _, tt := range []struct {
+		msg             string
+		filters         []gatewayv1alpha2.HTTPRouteFilter
+		pathMatch       *gatewayv1alpha2.HTTPPathMatch
+		expectedPlugins []kong.Plugin
+		expectedRoute   kong.Route
+		expectedErr     bool
+	}{
+		{
+			msg:           "a rule without filters leaves the route untouched",
+			expectedRoute: kong.Route{Paths: kong.StringSlice("/api")},
+		},
+		{
+			msg: "header modifiers are translated into the request-transformer plugin",
+			filters: []gatewayv1alpha2.HTTPRouteFilter{{
+				Type: gatewayv1alpha2.HTTPRouteFilterRequestHeaderModifier,
+				RequestHeaderModifier: &gatewayv1alpha2.HTTPRequestHeaderFilter{
+					Set:    []gatewayv1alpha2.HTTPHeader{{Name: "x-env", Value: "prod"}},
+					Add:    []gatewayv1alpha2.HTTPHeader{{Name: "x-tag", Value: "a"}, {Name: "x-tag", Value: "b"}},
+					Remove: []string{"x-debug"},
+				},
+			}},
+			expectedPlugins: []kong.Plugin{{
+				Name: kong.String("request-transformer"),
+				Config: kong.Configuration{
+					"replace": map[string]interface{}{"headers": []string{"x-env:prod"}},
+					"add":     map[string]interface{}{"headers": []string{"x-env:prod"}},
+					"append":  map[string]interface{}{"headers": []string{"x-tag:a", "x-tag:b"}},
+					"remove":  map[string]interface{}{"headers": []string{"x-debug"}},
+				},
+			}},
+			expectedRoute: kong.Route{Paths: kong.StringSlice("/api")},
+		},
+		{
+			msg: "URL rewrites are merged into the same request-transformer plugin",
+			filters: []gatewayv1alpha2.HTTPRouteFilter{
+				{
+					Type: gatewayv1alpha2.HTTPRouteFilterRequestHeaderModifier,
+					RequestHeaderModifier: &gatewayv1alpha2.HTTPRequestHeaderFilter{
+						Set: []gatewayv1alpha2.HTTPHeader{{Name: "x-env", Value: "prod"}},
+					},
+				},
+				{
+					Type: gatewayv1alpha2.HTTPRouteFilterURLRewrite,
+					URLRewrite: &gatewayv1alpha2.HTTPURLRewriteFilter{
+						Hostname: &rewriteHostname,
+						Path: &gatewayv1alpha2.HTTPPathModifier{
+							Type:         gatewayv1alpha2.AbsoluteHTTPPathModifier,
+							Substitution: "/v2/api",
+						},
+					},
+				},
+			},
+			expectedPlugins: []kong.Plugin{{
+				Name: kong.String("request-transformer"),
+				Config: kong.Configuration{
+					"replace": map[string]interface{}{
+						"headers": []string{"x-env:prod", "host:internal.example.com"},
+						"uri":     "/v2/api",
+					},
+					"add": map[string]interface{}{"headers": []string{"x-env:prod"}},
+				},
+			}},
+			expectedRoute: kong.Route{Paths: kong.StringSlice("/api")},
+		},
+		{
+			msg: "prefix replacements turn the prefix path into a regex capturing the rest of the path",
+			filters: []gatewayv1alpha2.HTTPRouteFilter{{
+				Type: gatewayv1alpha2.HTTPRouteFilterURLRewrite,
+				URLRewrite: &gatewayv1alpha2.HTTPURLRewriteFilter{
+					Path: &gatewayv1alpha2.HTTPPathModifier{
+						Type:         gatewayv1alpha2.PrefixMatchHTTPPathModifier,
+						Substitution: "/v2/",
+					},
+				},
+			}},
+			pathMatch: &gatewayv1alpha2.HTTPPathMatch{Type: &pathPrefix, Value: kong.String("/api/")},
+			expectedPlugins: []kong.Plugin{{
+				Name: kong.String("request-transformer"),
+				Config: kong.Configuration{
+					"replace": map[string]interface{}{"uri": "/v2/$(uri_captures[1])"},
+				},
+			}},
+			expectedRoute: kong.Route{Paths: kong.StringSlice("/api/?(.*)$")},
+		},
+		{
+			msg: "prefix replacements without a path match replace the / prefix",
+			filters: []gatewayv1alpha2.HTTPRouteFilter{{
+				Type: gatewayv1alpha2.HTTPRouteFilterURLRewrite,
+				URLRewrite: &gatewayv1alpha2.HTTPURLRewriteFilter{
+					Path: &gatewayv1alpha2.HTTPPathModifier{
+						Type:         gatewayv1alpha2.PrefixMatchHTTPPathModifier,
+						Substitution: "/v2",
+					},
+				},
+			}},
+			expectedPlugins: []kong.Plugin{{
+				Name: kong.String("request-transformer"),
+				Config: kong.Configuration{
+					"replace": map[string]interface{}{"uri": "/v2/$(uri_captures[1])"},
+				},
+			}},
+			expectedRoute: kong.Route{Paths: kong.StringSlice("/(.*)$")},
+		},
+		{
+			msg: "prefix replacements are rejected for exact path matches",
+			filters: []gatewayv1alpha2.HTTPRouteFilter{{
+				Type: gatewayv1alpha2.HTTPRouteFilterURLRewrite,
+				URLRewrite: &gatewayv1alpha2.HTTPURLRewriteFilter{
+					Path: &gatewayv1alpha2.HTTPPathModifier{
+						Type:         gatewayv1alpha2.PrefixMatchHTTPPathModifier,
+						Substitution: "/v2",
+					},
+				},
+			}},
+			pathMatch:   &gatewayv1alpha2.HTTPPathMatch{Type: &pathExact, Value: kong.String("/api")},
+			expectedErr: true,
+		},
+		{
+			msg: "redirects to https are translated into the route's https redirect",
+			filters: []gatewayv1alpha2.HTTPRouteFilter{{
+				Type: gatewayv1alpha2.HTTPRouteFilterRequestRedirect,
+				RequestRedirect: &gatewayv1alpha2.HTTPRequestRedirectFilter{
+					Scheme:     &https,
+					StatusCode: &permanent,
+				},
+			}},
+			expectedRoute: kong.Route{
+				Paths:                   kong.StringSlice("/api"),
+				Protocols:               kong.StringSlice("https"),
+				HTTPSRedirectStatusCode: kong.Int(301),
+			},
+		},
+		{
+			msg: "redirects to other hosts are rejected",
+			filters: []gatewayv1alpha2.HTTPRouteFilter{{
+				Type: gatewayv1alpha2.HTTPRouteFilterRequestRedirect,
+				RequestRedirect: &gatewayv1alpha2.HTTPRequestRedirectFilter{
+					Scheme:   &https,
+					Hostname: &hostname,
+				},
+			}},
+			expectedErr: true,
+		},
+		{
+			msg: "redirects to http are rejected",
+			filters: []gatewayv1alpha2.HTTPRouteFilter{{
+				Type:            gatewayv1alpha2.HTTPRouteFilterRequestRedirect,
+				RequestRedirect: &gatewayv1alpha2.HTTPRequestRedirectFilter{Scheme: &http},
+			}},
+			expectedErr: true,
+		},
+		{
+			msg: "mirrors are rejected",
+			filters: []gatewayv1alpha2.HTTPRouteFilter{{
+				Type: gatewayv1alpha2.HTTPRouteFilterRequestMirror,
+				RequestMirror: &gatewayv1alpha2.HTTPRequestMirrorFilter{
+					BackendRef: gatewayv1alpha2.BackendObjectReference{Name: "mirror"},
+				},
+			}},
+			expectedErr: true,
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			r := kongstate.Route{Route: kong.Route{Paths: kong.StringSlice("/api")}}
+			err := applyHTTPRouteFilters(&r, tt.filters, tt.pathMatch)
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedPlugins, r.Plugins)
+			assert.Equal(t, tt.expectedRoute, r.Route)
+		})
+	}
+}
+
+func TestHTTPRouteURLRewritePrefixRegexPath(t *testing.T) {
+	pathPrefix := gatewayv1alpha2.PathMatchPathPrefix
+	port := gatewayv1alpha2.PortNumber(80)
+	s, err := store.NewFakeStore(store.FakeObjects{
+		HTTPRoutes: []*gatewayv1alpha2.HTTPRoute{{
+			TypeMeta: metav1.TypeMeta{Kind: httprouteGVK.Kind, APIVersion: httprouteGVK.GroupVersion().String()},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rewrite",
+				Namespace: corev1.NamespaceDefault,
+			},
+			Spec: gatewayv1alpha2.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1alpha2.CommonRouteSpec{
+					ParentRefs: []gatewayv1alpha2.ParentReference{{Name: "fake-gateway"}},
+				},
+				Rules: []gatewayv1alpha2.HTTPRouteRule{{
+					Matches: []gatewayv1alpha2.HTTPRouteMatch{{
+						Path: &gatewayv1alpha2.HTTPPathMatch{Type: &pathPrefix, Value: kong.String("/api")},
+					}},
+					Filters: []gatewayv1alpha2.HTTPRouteFilter{{
+						Type: gatewayv1alpha2.HTTPRouteFilterURLRewrite,
+						URLRewrite: &gatewayv1alpha2.HTTPURLRewriteFilter{
+							Path: &gatewayv1alpha2.HTTPPathModifier{
+								Type:         gatewayv1alpha2.PrefixMatchHTTPPathModifier,
+								Substitution: "/v2",
+							},
+						},
+					}},
+					BackendRefs: []gatewayv1alpha2.HTTPBackendRef{{
+						BackendRef: gatewayv1alpha2.BackendRef{
+							BackendObjectReference: gatewayv1alpha2.BackendObjectReference{
+								Name: "fake-service",
+								Port: &port,
+							},
+						},
+					}},
+				}},
+			},
+		}},
+	})
+	require.NoError(t, err)
+
+	p := NewParser(logrus.New(), s)
+	p.EnableRegexPathPrefix()
+	state, err := p.Build()
+	require.NoError(t, err)
+	require.Len(t, state.Services, 1)
+	require.Len(t, state.Services[0].Routes, 1)
+	route := state.Services[0].Routes[0]
+	assert.Equal(t, kong.StringSlice("~/api/?(.*)$"), route.Paths, "the regex capturing the rest of the path is prefixed as one")
+	require.Len(t, route.Plugins, 1)
+	assert.Equal(t, map[string]interface{}{"uri": "/v2/$(uri_captures[1])"}, route.Plugins[0].Config["replace"])
+}
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -9,10 +10,14 @@ import (
 	"github.com/sirupsen/logrus"
 	networkingv1 "k8s.io/api/networking/v1"
 	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser/translators"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+	configurationv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
 )
 
 func (p *Parser) ingressRulesFromIngressV1beta1() ingressRules {
@@ -37,7 +42,8 @@ func (p *Parser) ingressRulesFromIngressV1beta1() ingressRules {
 			allDefaultBackends = append(allDefaultBackends, *ingress)
 		}
 
-		result.SecretNameToSNIs.addFromIngressV1beta1TLS(ingressSpec.TLS, ingress.Namespace)
+		result.addTLSFromIngressV1beta1(ingressSpec.TLS, ingress)
+		result.addDefaultCertificateCandidatesFromIngressV1beta1TLS(ingressSpec.TLS, ingress.ObjectMeta)
 
 		var objectSuccessfullyParsed bool
 		for i, rule := range ingressSpec.Rules {
@@ -50,6 +56,7 @@ func (p *Parser) ingressRulesFromIngressV1beta1() ingressRules {
 
 				if strings.Contains(path, "//") {
 					log.Errorf("rule skipped: invalid path: '%v'", path)
+					p.registerTranslationFailure(ingress, fmt.Sprintf("rule skipped: invalid path: '%v'", path))
 					continue
 				}
 				if path == "" {
@@ -158,6 +165,10 @@ func (p *Parser) ingressRulesFromIngressV1beta1() ingressRules {
 		}
 		service.Routes = append(service.Routes, r)
 		result.ServiceNameToServices[serviceName] = service
+		for i := range allDefaultBackends[1:] {
+			p.registerTranslationFailure(&allDefaultBackends[i+1], fmt.Sprintf(
+				"default backend ignored: the older Ingress %s/%s sets the default backend", ingress.Namespace, ingress.Name))
+		}
 	}
 
 	return result
@@ -167,30 +178,39 @@ func (p *Parser) ingressRulesFromIngressV1() ingressRules {
 	result := newIngressRules()
 
 	ingressList := p.storer.ListIngressesV1()
+	classParameters, err := p.storer.GetIngressClassParametersV1beta1()
+	if err != nil {
+		if !errors.As(err, &store.ErrNotFound{}) {
+			p.logger.WithError(err).Error("failed to get the IngressClassParameters of the ingress class")
+		}
+	}
 
-	var allDefaultBackends []networkingv1.Ingress
+	var allDefaultBackends []*networkingv1.Ingress
 	sort.SliceStable(ingressList, func(i, j int) bool {
 		return ingressList[i].CreationTimestamp.Before(
 			&ingressList[j].CreationTimestamp)
 	})
 
-	for _, ingress := range ingressList {
+	for _, original := range ingressList {
+		ingress := applyIngressClassParameters(original, classParameters)
 		ingressSpec := ingress.Spec
 		log := p.logger.WithFields(logrus.Fields{
 			"ingress_namespace": ingress.Namespace,
 			"ingress_name":      ingress.Name,
 		})
 
-		if ingressSpec.DefaultBackend != nil {
-			allDefaultBackends = append(allDefaultBackends, *ingress)
+		if ingressSpec.DefaultBackend != nil && ingressSpec.DefaultBackend.Service != nil {
+			allDefaultBackends = append(allDefaultBackends, original)
 		}
 
-		result.SecretNameToSNIs.addFromIngressV1TLS(ingressSpec.TLS, ingress.Namespace)
+		result.addTLSFromIngressV1(ingressSpec.TLS, original)
+		result.addTLSFromCertManager(original)
+		result.addDefaultCertificateCandidatesFromIngressV1TLS(ingressSpec.TLS, ingress.ObjectMeta)
 
 		var objectSuccessfullyParsed bool
 
 		if p.featureEnabledCombinedServiceRoutes {
-			for _, kongStateService := range translators.TranslateIngress(ingress) {
+			for _, kongStateService := range p.translateIngress(ingress) {
 				result.ServiceNameToServices[*kongStateService.Service.Name] = *kongStateService
 			}
 			objectSuccessfullyParsed = true
@@ -202,6 +222,7 @@ func (p *Parser) ingressRulesFromIngressV1() ingressRules {
 				for j, rulePath := range rule.HTTP.Paths {
 					if strings.Contains(rulePath.Path, "//") {
 						log.Errorf("rule skipped: invalid path: '%v'", rulePath.Path)
+						p.registerTranslationFailure(original, fmt.Sprintf("rule skipped: invalid path: '%v'", rulePath.Path))
 						continue
 					}
 
@@ -213,6 +234,7 @@ func (p *Parser) ingressRulesFromIngressV1() ingressRules {
 					paths, err := pathsFromK8s(rulePath.Path, pathType)
 					if err != nil {
 						log.WithError(err).Error("rule skipped: pathsFromK8s")
+						p.registerTranslationFailure(original, fmt.Sprintf("rule skipped: %v", err))
 						continue
 					}
 
@@ -266,7 +288,8 @@ func (p *Parser) ingressRulesFromIngressV1() ingressRules {
 		}
 
 		if objectSuccessfullyParsed {
-			p.ReportKubernetesObjectUpdate(ingress)
+			result.addCatchAllRoutesFromIngressV1(ingress)
+			p.ReportKubernetesObjectUpdate(original)
 		}
 	}
 
@@ -274,38 +297,133 @@ func (p *Parser) ingressRulesFromIngressV1() ingressRules {
 		return allDefaultBackends[i].CreationTimestamp.Before(&allDefaultBackends[j].CreationTimestamp)
 	})
 
-	// Process the default backend
-	if len(allDefaultBackends) > 0 {
-		ingress := allDefaultBackends[0]
-		defaultBackend := allDefaultBackends[0].Spec.DefaultBackend
-		port := PortDefFromServiceBackendPort(&defaultBackend.Service.Port)
-		serviceName := fmt.Sprintf("%s.%s.%s", allDefaultBackends[0].Namespace, defaultBackend.Service.Name,
-			port.CanonicalString())
-		service, ok := result.ServiceNameToServices[serviceName]
-		if !ok {
-			service = kongstate.Service{
-				Service: kong.Service{
-					Name: kong.String(serviceName),
-					Host: kong.String(fmt.Sprintf("%s.%s.%d.svc", defaultBackend.Service.Name, ingress.Namespace,
-						defaultBackend.Service.Port.Number)),
-					Port:           kong.Int(DefaultHTTPPort),
-					Protocol:       kong.String("http"),
-					ConnectTimeout: kong.Int(DefaultServiceTimeout),
-					ReadTimeout:    kong.Int(DefaultServiceTimeout),
-					WriteTimeout:   kong.Int(DefaultServiceTimeout),
-					Retries:        kong.Int(DefaultRetries),
-				},
-				Namespace: ingress.Namespace,
-				Backends: []kongstate.ServiceBackend{{
-					Name:    defaultBackend.Service.Name,
-					PortDef: PortDefFromServiceBackendPort(&defaultBackend.Service.Port),
-				}},
+	// Process the default backend: the one of the class takes precedence over
+	// the ones of its Ingresses, of which only the oldest one is used
+	switch {
+	case classParameters != nil && classParameters.Spec.DefaultBackend != nil:
+		result.addDefaultBackendV1(classParameters, classParameters.Spec.DefaultBackend,
+			fmt.Sprintf("%s.%s.default-backend", classParameters.Namespace, classParameters.Name))
+		for _, ingress := range allDefaultBackends {
+			p.registerTranslationFailure(ingress, fmt.Sprintf(
+				"default backend ignored: the IngressClassParameters %s/%s of the class set the default backend",
+				classParameters.Namespace, classParameters.Name))
+		}
+	case len(allDefaultBackends) > 0:
+		ingress := applyIngressClassParameters(allDefaultBackends[0], classParameters)
+		result.addDefaultBackendV1(ingress, ingress.Spec.DefaultBackend.Service, ingress.Namespace+"."+ingress.Name)
+		for _, ignored := range allDefaultBackends[1:] {
+			p.registerTranslationFailure(ignored, fmt.Sprintf(
+				"default backend ignored: the older Ingress %s/%s sets the default backend", ingress.Namespace, ingress.Name))
+		}
+	}
+
+	return result
+}
+
+// addDefaultBackendV1 adds a route matching all the requests no other route
+// matches, generated from obj, to the service of the given default backend in
+// the namespace of obj.
+func (ir ingressRules) addDefaultBackendV1(obj client.Object, defaultBackend *networkingv1.IngressServiceBackend, routeName string) {
+	namespace := obj.GetNamespace()
+	port := PortDefFromServiceBackendPort(&defaultBackend.Port)
+	serviceName := fmt.Sprintf("%s.%s.%s", namespace, defaultBackend.Name, port.CanonicalString())
+	service, ok := ir.ServiceNameToServices[serviceName]
+	if !ok {
+		service = kongstate.Service{
+			Service: kong.Service{
+				Name: kong.String(serviceName),
+				Host: kong.String(fmt.Sprintf("%s.%s.%d.svc", defaultBackend.Name, namespace,
+					defaultBackend.Port.Number)),
+				Port:           kong.Int(DefaultHTTPPort),
+				Protocol:       kong.String("http"),
+				ConnectTimeout: kong.Int(DefaultServiceTimeout),
+				ReadTimeout:    kong.Int(DefaultServiceTimeout),
+				WriteTimeout:   kong.Int(DefaultServiceTimeout),
+				Retries:        kong.Int(DefaultRetries),
+			},
+			Namespace: namespace,
+			Backends: []kongstate.ServiceBackend{{
+				Name:    defaultBackend.Name,
+				PortDef: port,
+			}},
+		}
+	}
+	r := kongstate.Route{
+		Ingress: util.FromK8sObject(obj),
+		Route: kong.Route{
+			Name:              kong.String(routeName),
+			Paths:             kong.StringSlice("/"),
+			StripPath:         kong.Bool(false),
+			PreserveHost:      kong.Bool(true),
+			Protocols:         kong.StringSlice("http", "https"),
+			RegexPriority:     kong.Int(0),
+			RequestBuffering:  kong.Bool(true),
+			ResponseBuffering: kong.Bool(true),
+		},
+	}
+	service.Routes = append(service.Routes, r)
+	ir.ServiceNameToServices[serviceName] = service
+}
+
+// addCatchAllRoutesFromIngressV1 adds a route matching all the paths of each
+// host of an Ingress annotated with konghq.com/catch-all-plugins, to which the
+// KongPlugins it lists are attached. As Kong matches the longest paths first,
+// these routes only match the requests no other path of their host matches,
+// so that plugins such as request-termination can serve custom error pages
+// instead of the Kong 404 response. Each route belongs to a service of its
+// host, which receives the requests the plugins don't terminate. Hosts for
+// which the Ingress already matches all paths are skipped.
+func (ir ingressRules) addCatchAllRoutesFromIngressV1(ingress *networkingv1.Ingress) {
+	plugins := annotations.ExtractCatchAllPlugins(ingress.Annotations)
+	if plugins == "" {
+		return
+	}
+
+	var hosts []string
+	matchesAllPaths := make(map[string]bool)
+	for _, rule := range ingress.Spec.Rules {
+		if rule.Host == "" || rule.HTTP == nil {
+			continue
+		}
+		if _, ok := matchesAllPaths[rule.Host]; !ok {
+			matchesAllPaths[rule.Host] = false
+			hosts = append(hosts, rule.Host)
+		}
+		for _, rulePath := range rule.HTTP.Paths {
+			if strings.Trim(rulePath.Path, "/") == "" &&
+				(rulePath.PathType == nil || *rulePath.PathType != networkingv1.PathTypeExact) {
+				matchesAllPaths[rule.Host] = true
 			}
 		}
-		r := kongstate.Route{
-			Ingress: util.FromK8sObject(&ingress),
+	}
+
+	// the routes only inherit the annotations of the Ingress which determine
+	// the requests they match and where they're proxied, their plugins being
+	// the catch-all ones
+	info := util.FromK8sObject(ingress)
+	info.Annotations = map[string]string{
+		annotations.AnnotationPrefix + annotations.PluginsKey: plugins,
+	}
+	for _, key := range []string{annotations.ProtocolsKey, annotations.HostAliasesKey, annotations.UpstreamNameKey} {
+		if value, ok := ingress.Annotations[annotations.AnnotationPrefix+key]; ok {
+			info.Annotations[annotations.AnnotationPrefix+key] = value
+		}
+	}
+
+	for i, host := range hosts {
+		if matchesAllPaths[host] {
+			continue
+		}
+		serviceName, ok := ir.serviceNameForIngressHost(ingress, host)
+		if !ok {
+			continue
+		}
+		service := ir.ServiceNameToServices[serviceName]
+		service.Routes = append(service.Routes, kongstate.Route{
+			Ingress: info,
 			Route: kong.Route{
-				Name:              kong.String(ingress.Namespace + "." + ingress.Name),
+				Name:              kong.String(fmt.Sprintf("%s.%s.catch-all.%d", ingress.Namespace, ingress.Name, i)),
+				Hosts:             kong.StringSlice(host),
 				Paths:             kong.StringSlice("/"),
 				StripPath:         kong.Bool(false),
 				PreserveHost:      kong.Bool(true),
@@ -314,10 +432,78 @@ func (p *Parser) ingressRulesFromIngressV1() ingressRules {
 				RequestBuffering:  kong.Bool(true),
 				ResponseBuffering: kong.Bool(true),
 			},
+		})
+		ir.ServiceNameToServices[serviceName] = service
+	}
+}
+
+// serviceNameForIngressHost provides the name of the first service, in name
+// order, with a route translated from a rule of an Ingress for a host.
+func (ir ingressRules) serviceNameForIngressHost(ingress *networkingv1.Ingress, host string) (string, bool) {
+	var names []string
+	for name, service := range ir.ServiceNameToServices {
+		for _, route := range service.Routes {
+			if route.Ingress.Namespace != ingress.Namespace || route.Ingress.Name != ingress.Name {
+				continue
+			}
+			if kind := route.Ingress.GroupVersionKind.Kind; kind != "" && kind != "Ingress" {
+				continue
+			}
+			for _, routeHost := range route.Hosts {
+				if routeHost != nil && *routeHost == host {
+					names = append(names, name)
+				}
+			}
 		}
-		service.Routes = append(service.Routes, r)
-		result.ServiceNameToServices[serviceName] = service
 	}
+	if len(names) == 0 {
+		return "", false
+	}
+	sort.Strings(names)
+	return names[0], true
+}
 
-	return result
+// translateIngress translates an Ingress into Kong Services and Routes on its
+// own, reusing its cached translation if it hasn't changed since.
+func (p *Parser) translateIngress(ingress *networkingv1.Ingress) []*kongstate.Service {
+	if p.translationCache == nil {
+		return translators.TranslateIngress(ingress)
+	}
+	return p.translationCache.translate(ingress, func() []*kongstate.Service {
+		return translators.TranslateIngress(ingress)
+	})
+}
+
+// applyIngressClassParameters provides the Ingress as translated with the
+// IngressClassParameters of the ingress class: the annotations the parameters
+// default are set on a copy of the Ingress, whose resourceVersion also tracks
+// the one of the parameters so that cached translations are invalidated when
+// the parameters change.
+func applyIngressClassParameters(ingress *networkingv1.Ingress, params *configurationv1beta1.IngressClassParameters) *networkingv1.Ingress {
+	if params == nil {
+		return ingress
+	}
+	ingress = ingress.DeepCopy()
+	if ingress.Annotations == nil {
+		ingress.Annotations = map[string]string{}
+	}
+	if params.Spec.EnableLegacyRegexDetection && annotations.ExtractLegacyRegexPath(ingress.Annotations) == "" {
+		ingress.Annotations[annotations.AnnotationPrefix+annotations.LegacyRegexPathKey] = "true"
+	}
+	if len(params.Spec.Plugins) > 0 {
+		plugins := append([]string{}, params.Spec.Plugins...)
+		seen := make(map[string]bool, len(plugins))
+		for _, plugin := range plugins {
+			seen[plugin] = true
+		}
+		for _, plugin := range annotations.ExtractKongPluginsFromAnnotations(ingress.Annotations) {
+			if !seen[plugin] {
+				seen[plugin] = true
+				plugins = append(plugins, plugin)
+			}
+		}
+		ingress.Annotations[annotations.AnnotationPrefix+annotations.PluginsKey] = strings.Join(plugins, ",")
+	}
+	ingress.ResourceVersion += "." + params.ResourceVersion
+	return ingress
 }
@@ -1,18 +1,24 @@
 package parser
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/kong/go-kong/kong"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	networkingv1 "k8s.io/api/networking/v1"
 	networkingv1beta1 "k8s.io/api/networking/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
+	configurationv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
 )
 
 func TestFromIngressV1beta1(t *testing.T) {
@@ -861,3 +867,255 @@ func TestFromIngressV1(t *testing.T) {
 		assert.True(ok)
 	})
 }
+
+func TestCatchAllRoutesFromIngressV1(t *testing.T) {
+	prefix := networkingv1.PathTypePrefix
+	backend := func(name string) networkingv1.IngressBackend {
+		return networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+			Name: name,
+			Port: networkingv1.ServiceBackendPort{Number: 80},
+		}}
+	}
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: "default",
+			Annotations: map[string]string{
+				annotations.IngressClassKey:                                   annotations.DefaultIngressClass,
+				annotations.AnnotationPrefix + annotations.CatchAllPluginsKey: "not-found-page",
+				annotations.AnnotationPrefix + annotations.PluginsKey:         "rate-limiting",
+				annotations.AnnotationPrefix + annotations.ProtocolsKey:       "https",
+				annotations.AnnotationPrefix + annotations.HostAliasesKey:     "alias.example.com",
+				annotations.AnnotationPrefix + annotations.StripPathKey:       "true",
+			},
+		},
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: "a.example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{
+							{Path: "/api", PathType: &prefix, Backend: backend("b-svc")},
+							{Path: "/web", PathType: &prefix, Backend: backend("a-svc")},
+						},
+					}},
+				},
+				{
+					Host: "b.example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{
+							{Path: "/", PathType: &prefix, Backend: backend("b-svc")},
+						},
+					}},
+				},
+			},
+		},
+	}
+
+	for _, combinedServiceRoutes := range []bool{false, true} {
+		t.Run(fmt.Sprintf("combined service routes: %t", combinedServiceRoutes), func(t *testing.T) {
+			store, err := store.NewFakeStore(store.FakeObjects{IngressesV1: []*networkingv1.Ingress{ingress}})
+			require.NoError(t, err)
+			p := NewParser(logrus.New(), store)
+			if combinedServiceRoutes {
+				p.EnableCombinedServiceRoutes()
+			}
+
+			var catchAllRoutes []kongstate.Route
+			var catchAllServices []string
+			for name, service := range p.ingressRulesFromIngressV1().ServiceNameToServices {
+				for _, route := range service.Routes {
+					if strings.Contains(*route.Name, "catch-all") {
+						catchAllRoutes = append(catchAllRoutes, route)
+						catchAllServices = append(catchAllServices, name)
+					}
+				}
+			}
+
+			require.Len(t, catchAllRoutes, 1, "the host matching all paths has no catch-all route")
+			route := catchAllRoutes[0]
+			assert.Equal(t, "default.foo.catch-all.0", *route.Name)
+			assert.Equal(t, kong.StringSlice("a.example.com"), route.Hosts)
+			assert.Equal(t, kong.StringSlice("/"), route.Paths)
+			assert.Contains(t, catchAllServices[0], "a-svc", "the route belongs to the first service of the host in name order")
+			assert.Equal(t, map[string]string{
+				"konghq.com/plugins":      "not-found-page",
+				"konghq.com/protocols":    "https",
+				"konghq.com/host-aliases": "alias.example.com",
+			}, route.Ingress.Annotations, "the route only inherits the annotations selecting requests")
+		})
+	}
+}
+
+func TestIngressClassParametersFromIngressV1(t *testing.T) {
+	prefix := networkingv1.PathTypePrefix
+	group := configurationv1beta1.SchemeGroupVersion.Group
+	namespaceScope := networkingv1.IngressClassParametersReferenceScopeNamespace
+	namespace := "kong"
+	class := &networkingv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{Name: annotations.DefaultIngressClass},
+		Spec: networkingv1.IngressClassSpec{
+			Controller: store.IngressClassKongController,
+			Parameters: &networkingv1.IngressClassParametersReference{
+				APIGroup:  &group,
+				Kind:      configurationv1beta1.IngressClassParametersKind,
+				Name:      "params",
+				Scope:     &namespaceScope,
+				Namespace: &namespace,
+			},
+		},
+	}
+	params := &configurationv1beta1.IngressClassParameters{
+		ObjectMeta: metav1.ObjectMeta{Name: "params", Namespace: namespace, ResourceVersion: "2"},
+		Spec: configurationv1beta1.IngressClassParametersSpec{
+			EnableLegacyRegexDetection: true,
+			Plugins:                    []string{"auth", "rate-limiting"},
+		},
+	}
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "foo",
+			Namespace:       "default",
+			ResourceVersion: "1",
+			Annotations: map[string]string{
+				annotations.AnnotationPrefix + annotations.PluginsKey: "cors, rate-limiting",
+			},
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: &class.Name,
+			Rules: []networkingv1.IngressRule{{
+				Host: "example.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{{
+						Path:     "/api",
+						PathType: &prefix,
+						Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+							Name: "svc",
+							Port: networkingv1.ServiceBackendPort{Number: 80},
+						}},
+					}},
+				}},
+			}},
+		},
+	}
+
+	for _, combinedServiceRoutes := range []bool{false, true} {
+		t.Run(fmt.Sprintf("combined service routes: %t", combinedServiceRoutes), func(t *testing.T) {
+			store, err := store.NewFakeStore(store.FakeObjects{
+				IngressClassesV1:              []*networkingv1.IngressClass{class},
+				IngressClassParametersV1beta1: []*configurationv1beta1.IngressClassParameters{params},
+				IngressesV1:                   []*networkingv1.Ingress{ingress},
+			})
+			require.NoError(t, err)
+			p := NewParser(logrus.New(), store)
+			p.EnableKubernetesObjectReports()
+			if combinedServiceRoutes {
+				p.EnableCombinedServiceRoutes()
+			}
+
+			services := p.ingressRulesFromIngressV1().ServiceNameToServices
+			require.Len(t, services, 1)
+			for _, service := range services {
+				require.Len(t, service.Routes, 1)
+				route := service.Routes[0]
+				assert.Equal(t, "true", route.Ingress.Annotations["konghq.com/legacy-regex-path"])
+				assert.Equal(t, "auth,rate-limiting,cors", route.Ingress.Annotations["konghq.com/plugins"],
+					"the plugins of the parameters come first, without duplicates")
+			}
+			assert.Equal(t, "cors, rate-limiting", ingress.Annotations["konghq.com/plugins"], "the Ingress is not modified")
+			assert.Equal(t, []client.Object{ingress}, p.GenerateKubernetesObjectReport(), "the Ingress itself is reported")
+		})
+	}
+}
+
+func TestDefaultBackendsFromIngressV1(t *testing.T) {
+	group := configurationv1beta1.SchemeGroupVersion.Group
+	namespaceScope := networkingv1.IngressClassParametersReferenceScopeNamespace
+	namespace := "kong"
+	class := &networkingv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{Name: annotations.DefaultIngressClass},
+		Spec: networkingv1.IngressClassSpec{
+			Controller: store.IngressClassKongController,
+			Parameters: &networkingv1.IngressClassParametersReference{
+				APIGroup:  &group,
+				Kind:      configurationv1beta1.IngressClassParametersKind,
+				Name:      "params",
+				Scope:     &namespaceScope,
+				Namespace: &namespace,
+			},
+		},
+	}
+	ingress := func(name string, created int64, service string) *networkingv1.Ingress {
+		return &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				CreationTimestamp: metav1.Unix(created, 0),
+			},
+			Spec: networkingv1.IngressSpec{
+				IngressClassName: &class.Name,
+				DefaultBackend: &networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+					Name: service,
+					Port: networkingv1.ServiceBackendPort{Number: 80},
+				}},
+			},
+		}
+	}
+	ingresses := []*networkingv1.Ingress{ingress("newer", 2, "newer-svc"), ingress("older", 1, "older-svc")}
+
+	t.Run("the oldest Ingress sets the default backend", func(t *testing.T) {
+		store, err := store.NewFakeStore(store.FakeObjects{
+			IngressClassesV1: []*networkingv1.IngressClass{class},
+			IngressesV1:      ingresses,
+		})
+		require.NoError(t, err)
+		p := NewParser(logrus.New(), store)
+
+		services := p.ingressRulesFromIngressV1().ServiceNameToServices
+		require.Len(t, services, 1)
+		service, ok := services["default.older-svc.80"]
+		require.True(t, ok)
+		require.Len(t, service.Routes, 1)
+		assert.Equal(t, "default.older", *service.Routes[0].Name)
+
+		failures := p.PopTranslationFailures()
+		require.Len(t, failures, 1)
+		assert.Equal(t, "newer", failures[0].Object.GetName())
+		assert.Equal(t, "default backend ignored: the older Ingress default/older sets the default backend", failures[0].Message)
+	})
+
+	t.Run("the IngressClassParameters take precedence", func(t *testing.T) {
+		params := &configurationv1beta1.IngressClassParameters{
+			ObjectMeta: metav1.ObjectMeta{Name: "params", Namespace: namespace},
+			Spec: configurationv1beta1.IngressClassParametersSpec{
+				DefaultBackend: &networkingv1.IngressServiceBackend{
+					Name: "fallback",
+					Port: networkingv1.ServiceBackendPort{Number: 8080},
+				},
+			},
+		}
+		store, err := store.NewFakeStore(store.FakeObjects{
+			IngressClassesV1:              []*networkingv1.IngressClass{class},
+			IngressClassParametersV1beta1: []*configurationv1beta1.IngressClassParameters{params},
+			IngressesV1:                   ingresses,
+		})
+		require.NoError(t, err)
+		p := NewParser(logrus.New(), store)
+
+		services := p.ingressRulesFromIngressV1().ServiceNameToServices
+		require.Len(t, services, 1)
+		service, ok := services["kong.fallback.8080"]
+		require.True(t, ok)
+		assert.Equal(t, "kong", service.Namespace)
+		require.Len(t, service.Routes, 1)
+		assert.Equal(t, "kong.params.default-backend", *service.Routes[0].Name)
+		assert.Equal(t, []*string{kong.String("/")}, service.Routes[0].Paths)
+
+		ignored := make(map[string]string)
+		for _, failure := range p.PopTranslationFailures() {
+			ignored[failure.Object.GetName()] = failure.Message
+		}
+		message := "default backend ignored: the IngressClassParameters kong/params of the class set the default backend"
+		assert.Equal(t, map[string]string{"newer": message, "older": message}, ignored)
+	})
+}
@@ -3,6 +3,7 @@ package parser
 import (
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/kong/go-kong/kong"
 	knative "knative.dev/networking/pkg/apis/networking/v1alpha1"
@@ -26,12 +27,11 @@ func (p *Parser) ingressRulesFromKnativeIngress() ingressRules {
 	})
 
 	services := map[string]kongstate.Service{}
-	secretToSNIs := newSecretNameToSNIs()
 
 	for _, ingress := range ingressList {
 		ingressSpec := ingress.Spec
 
-		secretToSNIs.addFromIngressV1beta1TLS(knativeIngressToNetworkingTLS(ingress.Spec.TLS), ingress.Namespace)
+		result.addTLSFromIngressV1beta1(knativeIngressToNetworkingTLS(ingress.Spec.TLS), ingress)
 
 		var objectSuccessfullyParsed bool
 		for i, rule := range ingressSpec.Rules {
@@ -48,7 +48,7 @@ func (p *Parser) ingressRulesFromKnativeIngress() ingressRules {
 				r := kongstate.Route{
 					Ingress: util.FromK8sObject(ingress),
 					Route: kong.Route{
-						Name:              kong.String(fmt.Sprintf("%s.%s.%d%d", ingress.Namespace, ingress.Name, i, j)),
+						Name:              kong.String(knativeRouteName(ingress, i, j)),
 						Paths:             kong.StringSlice(path),
 						StripPath:         kong.Bool(false),
 						PreserveHost:      kong.Bool(true),
@@ -59,6 +59,14 @@ func (p *Parser) ingressRulesFromKnativeIngress() ingressRules {
 					},
 				}
 				r.Hosts = kong.StringSlice(hosts...)
+				if len(rule.Headers) > 0 {
+					headers, err := knativeHeaderMatches(rule.Headers)
+					if err != nil {
+						p.registerTranslationFailure(ingress, fmt.Sprintf("path %q of rule %d ignored: %s", path, i, err))
+						continue
+					}
+					r.Headers = headers
+				}
 
 				knativeBackend := knativeSelectSplit(rule.Splits)
 				serviceName := fmt.Sprintf("%s.%s.%s", knativeBackend.ServiceNamespace, knativeBackend.ServiceName,
@@ -117,10 +125,48 @@ func (p *Parser) ingressRulesFromKnativeIngress() ingressRules {
 	}
 
 	result.ServiceNameToServices = services
-	result.SecretNameToSNIs = secretToSNIs
 	return result
 }
 
+// knativeHeaderMatches converts the header matches of a Knative Ingress path,
+// such as the Knative-Serving-Tag header of tag-based routing, into the header
+// matches of a Kong route.
+func knativeHeaderMatches(matches map[string]knative.HeaderMatch) (map[string][]string, error) {
+	headers := make(map[string][]string, len(matches))
+	for name, match := range matches {
+		if strings.EqualFold(name, "host") {
+			// Kong matches the Host header with the hosts of routes only
+			return nil, fmt.Errorf("the Host header can't be matched, the hosts of the rule must be used instead")
+		}
+		headers[name] = []string{match.Exact}
+	}
+	return headers, nil
+}
+
+// knativeRouteName provides the name of the route translated from the path j
+// of the rule i of a Knative Ingress.
+func knativeRouteName(ingress *knative.Ingress, i, j int) string {
+	return fmt.Sprintf("%s.%s.%d%d", ingress.Namespace, ingress.Name, i, j)
+}
+
+// KnativeIngressRouteNames provides the names of the Kong routes a Knative
+// Ingress translates into, so that whether they are live in Kong can be probed.
+func KnativeIngressRouteNames(ingress *knative.Ingress) []string {
+	var names []string
+	for i, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for j, path := range rule.HTTP.Paths {
+			if _, err := knativeHeaderMatches(path.Headers); err != nil {
+				continue // not translated
+			}
+			names = append(names, knativeRouteName(ingress, i, j))
+		}
+	}
+	return names
+}
+
 func knativeSelectSplit(splits []knative.IngressBackendSplit) knative.IngressBackendSplit {
 	if len(splits) == 0 {
 		return knative.IngressBackendSplit{}
@@ -306,4 +306,42 @@ func TestFromKnativeIngress(t *testing.T) {
 
 		assert.Equal(newSecretNameToSNIs(), parsedInfo.SecretNameToSNIs)
 	})
+	t.Run("header matches of tag-based routing are translated", func(t *testing.T) {
+		ingress := ingressList[1].DeepCopy()
+		paths := &ingress.Spec.Rules[0].HTTP.Paths
+		taggedPath := (*paths)[0]
+		taggedPath.Headers = map[string]knative.HeaderMatch{"Knative-Serving-Tag": {Exact: "v1"}}
+		hostPath := (*paths)[0]
+		hostPath.Headers = map[string]knative.HeaderMatch{"Host": {Exact: "other.example.com"}}
+		*paths = append(*paths, taggedPath, hostPath)
+		store, err := store.NewFakeStore(store.FakeObjects{
+			KnativeIngresses: []*knative.Ingress{ingress},
+		})
+		assert.NoError(err)
+		p := NewParser(logrus.New(), store)
+
+		parsedInfo := p.ingressRulesFromKnativeIngress()
+		routes := parsedInfo.ServiceNameToServices["foo-ns.foo-svc.42"].Routes
+		assert.Len(routes, 2)
+		assert.Empty(routes[0].Headers)
+		assert.Equal("foo-namespace.foo.01", *routes[1].Name)
+		assert.Equal(map[string][]string{"Knative-Serving-Tag": {"v1"}}, routes[1].Headers)
+
+		failures := p.PopTranslationFailures()
+		assert.Len(failures, 1, "the Host header can't be matched")
+		assert.Equal([]string{"foo-namespace.foo.00", "foo-namespace.foo.01"}, KnativeIngressRouteNames(ingress))
+	})
+}
+
+func TestKnativeIngressRouteNames(t *testing.T) {
+	ingress := &knative.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "foo-namespace"},
+		Spec: knative.IngressSpec{
+			Rules: []knative.IngressRule{
+				{},
+				{HTTP: &knative.HTTPIngressRuleValue{Paths: []knative.HTTPIngressPath{{Path: "/"}, {Path: "/bar"}}}},
+			},
+		},
+	}
+	assert.Equal(t, []string{"foo-namespace.foo.10", "foo-namespace.foo.11"}, KnativeIngressRouteNames(ingress))
 }
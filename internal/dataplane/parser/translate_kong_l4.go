@@ -4,12 +4,14 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/kong/go-kong/kong"
 	"github.com/sirupsen/logrus"
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+	configurationv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
 )
 
 func (p *Parser) ingressRulesFromTCPIngressV1beta1() ingressRules {
@@ -21,10 +23,11 @@ func (p *Parser) ingressRulesFromTCPIngressV1beta1() ingressRules {
 		return result
 	}
 
+	// the oldest TCPIngress is routed the listeners several of them claim
 	sort.SliceStable(ingressList, func(i, j int) bool {
-		return ingressList[i].CreationTimestamp.Before(
-			&ingressList[j].CreationTimestamp)
+		return objectPrecedes(ingressList[i], ingressList[j])
 	})
+	claimed := make(map[TCPListener]*configurationv1beta1.TCPIngress)
 
 	for _, ingress := range ingressList {
 		ingressSpec := ingress.Spec
@@ -34,14 +37,27 @@ func (p *Parser) ingressRulesFromTCPIngressV1beta1() ingressRules {
 			"tcpingress_name":      ingress.Name,
 		})
 
-		result.SecretNameToSNIs.addFromIngressV1beta1TLS(tcpIngressToNetworkingTLS(ingressSpec.TLS), ingress.Namespace)
+		// the certificates of the rules are bound to their hosts first, so that
+		// the TLS section only binds the hosts they leave
+		result.addTLSFromIngressV1beta1(tcpIngressRulesToNetworkingTLS(ingressSpec.Rules), ingress)
+		result.addTLSFromIngressV1beta1(tcpIngressToNetworkingTLS(ingressSpec.TLS), ingress)
 
 		var objectSuccessfullyParsed bool
 		for i, rule := range ingressSpec.Rules {
-			if !util.IsValidPort(rule.Port) {
-				log.Errorf("invalid TCPIngress: invalid port: %v", rule.Port)
+			if err := ValidateTCPIngressRule(rule); err != nil {
+				log.Errorf("invalid TCPIngress: %s", err)
+				p.registerTranslationFailure(ingress, "invalid TCPIngress: "+err.Error())
 				continue
 			}
+			listener := TCPListenerOf(rule)
+			if owner, ok := claimed[listener]; ok && owner != ingress {
+				message := fmt.Sprintf("rule %d ignored: %s is already claimed by TCPIngress %s/%s, which takes precedence",
+					i, listener, owner.Namespace, owner.Name)
+				log.Error(message)
+				p.registerPortConflict(ingress, owner, message)
+				continue
+			}
+			claimed[listener] = ingress
 			r := kongstate.Route{
 				Ingress: util.FromK8sObject(ingress),
 				Route: kong.Route{
@@ -58,14 +74,6 @@ func (p *Parser) ingressRulesFromTCPIngressV1beta1() ingressRules {
 			if host != "" {
 				r.SNIs = kong.StringSlice(host)
 			}
-			if rule.Backend.ServiceName == "" {
-				log.Errorf("invalid TCPIngress: empty serviceName")
-				continue
-			}
-			if !util.IsValidPort(rule.Backend.ServicePort) {
-				log.Errorf("invalid TCPIngress: invalid servicePort: %v", rule.Backend.ServicePort)
-				continue
-			}
 
 			serviceName := fmt.Sprintf("%s.%s.%d", ingress.Namespace, rule.Backend.ServiceName, rule.Backend.ServicePort)
 			service, ok := result.ServiceNameToServices[serviceName]
@@ -111,9 +119,11 @@ func (p *Parser) ingressRulesFromUDPIngressV1beta1() ingressRules {
 		return result
 	}
 
+	// the oldest UDPIngress is routed the ports several of them claim
 	sort.SliceStable(ingressList, func(i, j int) bool {
-		return ingressList[i].CreationTimestamp.Before(&ingressList[j].CreationTimestamp)
+		return objectPrecedes(ingressList[i], ingressList[j])
 	})
+	claimed := make(map[int]*configurationv1beta1.UDPIngress)
 
 	for _, ingress := range ingressList {
 		ingressSpec := ingress.Spec
@@ -126,50 +136,63 @@ func (p *Parser) ingressRulesFromUDPIngressV1beta1() ingressRules {
 		var objectSuccessfullyParsed bool
 		for i, rule := range ingressSpec.Rules {
 			// validate the ports and servicenames for the rule
-			if !util.IsValidPort(rule.Port) {
-				log.Errorf("invalid UDPIngress: invalid port: %d", rule.Port)
-				continue
-			}
-			if rule.Backend.ServiceName == "" {
-				log.Errorf("invalid UDPIngress: empty serviceName")
+			if err := ValidateUDPIngressRule(rule); err != nil {
+				log.Errorf("invalid UDPIngress: %s", err)
+				p.registerTranslationFailure(ingress, "invalid UDPIngress: "+err.Error())
 				continue
 			}
-			if !util.IsValidPort(rule.Backend.ServicePort) {
-				log.Errorf("invalid UDPIngress: invalid servicePort: %d", rule.Backend.ServicePort)
+
+			// a range of ports is routed to the range of service ports at the same offsets
+			endPort := UDPIngressRuleEndPort(rule)
+			if owner, port, ok := claimedUDPPort(claimed, ingress, rule.Port, endPort); ok {
+				message := fmt.Sprintf("rule %d ignored: port %d is already claimed by UDPIngress %s/%s, which takes precedence",
+					i, port, owner.Namespace, owner.Name)
+				log.Error(message)
+				p.registerPortConflict(ingress, owner, message)
 				continue
 			}
-
-			// generate the kong Route based on the listen port
-			route := kongstate.Route{
-				Ingress: util.FromK8sObject(ingress),
-				Route: kong.Route{
-					Name:         kong.String(ingress.Namespace + "." + ingress.Name + "." + strconv.Itoa(i) + ".udp"),
-					Protocols:    kong.StringSlice("udp"),
-					Destinations: []*kong.CIDRPort{{Port: kong.Int(rule.Port)}},
-				},
+			for port := rule.Port; port <= endPort; port++ {
+				claimed[port] = ingress
 			}
+			for port := rule.Port; port <= endPort; port++ {
+				servicePort := rule.Backend.ServicePort + port - rule.Port
 
-			// generate the kong Service backend for the UDPIngress rules
-			host := fmt.Sprintf("%s.%s.%d.svc", rule.Backend.ServiceName, ingress.Namespace, rule.Backend.ServicePort)
-			serviceName := fmt.Sprintf("%s.%s.%d.udp", ingress.Namespace, rule.Backend.ServiceName, rule.Backend.ServicePort)
-			service, ok := result.ServiceNameToServices[serviceName]
-			if !ok {
-				service = kongstate.Service{
-					Namespace: ingress.Namespace,
-					Service: kong.Service{
-						Name:     kong.String(serviceName),
-						Protocol: kong.String("udp"),
-						Host:     kong.String(host),
-						Port:     kong.Int(rule.Backend.ServicePort),
+				// generate the kong Route based on the listen port
+				routeName := ingress.Namespace + "." + ingress.Name + "." + strconv.Itoa(i) + ".udp"
+				if endPort != rule.Port {
+					routeName = ingress.Namespace + "." + ingress.Name + "." + strconv.Itoa(i) + "." + strconv.Itoa(port) + ".udp"
+				}
+				route := kongstate.Route{
+					Ingress: util.FromK8sObject(ingress),
+					Route: kong.Route{
+						Name:         kong.String(routeName),
+						Protocols:    kong.StringSlice("udp"),
+						Destinations: []*kong.CIDRPort{{Port: kong.Int(port)}},
 					},
-					Backends: []kongstate.ServiceBackend{{
-						Name:    rule.Backend.ServiceName,
-						PortDef: kongstate.PortDef{Mode: kongstate.PortModeByNumber, Number: int32(rule.Backend.ServicePort)},
-					}},
 				}
+
+				// generate the kong Service backend for the UDPIngress rules
+				host := fmt.Sprintf("%s.%s.%d.svc", rule.Backend.ServiceName, ingress.Namespace, servicePort)
+				serviceName := fmt.Sprintf("%s.%s.%d.udp", ingress.Namespace, rule.Backend.ServiceName, servicePort)
+				service, ok := result.ServiceNameToServices[serviceName]
+				if !ok {
+					service = kongstate.Service{
+						Namespace: ingress.Namespace,
+						Service: kong.Service{
+							Name:     kong.String(serviceName),
+							Protocol: kong.String("udp"),
+							Host:     kong.String(host),
+							Port:     kong.Int(servicePort),
+						},
+						Backends: []kongstate.ServiceBackend{{
+							Name:    rule.Backend.ServiceName,
+							PortDef: kongstate.PortDef{Mode: kongstate.PortModeByNumber, Number: int32(servicePort)},
+						}},
+					}
+				}
+				service.Routes = append(service.Routes, route)
+				result.ServiceNameToServices[serviceName] = service
 			}
-			service.Routes = append(service.Routes, route)
-			result.ServiceNameToServices[serviceName] = service
 			objectSuccessfullyParsed = true
 		}
 
@@ -180,3 +203,93 @@ func (p *Parser) ingressRulesFromUDPIngressV1beta1() ingressRules {
 
 	return result
 }
+
+// TCPListener is what a TCPIngress rule claims: a port, along with the SNI
+// the connections must match, empty for any. Only one TCPIngress rule of a
+// class can claim a listener.
+type TCPListener struct {
+	Port int
+	Host string
+}
+
+// TCPListenerOf provides the listener a TCPIngress rule claims.
+func TCPListenerOf(rule configurationv1beta1.IngressRule) TCPListener {
+	return TCPListener{Port: rule.Port, Host: strings.ToLower(rule.Host)}
+}
+
+func (l TCPListener) String() string {
+	if l.Host == "" {
+		return fmt.Sprintf("port %d", l.Port)
+	}
+	return fmt.Sprintf("port %d with host %q", l.Port, l.Host)
+}
+
+// claimedUDPPort provides the first port of the range from port to endPort
+// which another UDPIngress than ingress claims, along with that UDPIngress.
+func claimedUDPPort(
+	claimed map[int]*configurationv1beta1.UDPIngress,
+	ingress *configurationv1beta1.UDPIngress,
+	port, endPort int,
+) (*configurationv1beta1.UDPIngress, int, bool) {
+	for ; port <= endPort; port++ {
+		if owner, ok := claimed[port]; ok && owner != ingress {
+			return owner, port, true
+		}
+	}
+	return nil, 0, false
+}
+
+// ValidateTCPIngressRule checks that a TCPIngress rule can be translated,
+// returning why it can't otherwise.
+func ValidateTCPIngressRule(rule configurationv1beta1.IngressRule) error {
+	if !util.IsValidPort(rule.Port) {
+		return fmt.Errorf("invalid port: %v", rule.Port)
+	}
+	if rule.TLSSecretName != "" && rule.Host == "" {
+		return fmt.Errorf("tlsSecretName requires a host")
+	}
+	if rule.Backend.ServiceName == "" {
+		return fmt.Errorf("empty serviceName")
+	}
+	if !util.IsValidPort(rule.Backend.ServicePort) {
+		return fmt.Errorf("invalid servicePort: %v", rule.Backend.ServicePort)
+	}
+	return nil
+}
+
+// MaxUDPIngressRulePorts is the number of ports a UDPIngress rule may listen
+// on at most, as each port of its range is translated to its own route and
+// service.
+const MaxUDPIngressRulePorts = 1000
+
+// ValidateUDPIngressRule checks that a UDPIngress rule can be translated,
+// returning why it can't otherwise.
+func ValidateUDPIngressRule(rule configurationv1beta1.UDPIngressRule) error {
+	if !util.IsValidPort(rule.Port) {
+		return fmt.Errorf("invalid port: %d", rule.Port)
+	}
+	if rule.Backend.ServiceName == "" {
+		return fmt.Errorf("empty serviceName")
+	}
+	if !util.IsValidPort(rule.Backend.ServicePort) {
+		return fmt.Errorf("invalid servicePort: %d", rule.Backend.ServicePort)
+	}
+	endPort := UDPIngressRuleEndPort(rule)
+	if endPort < rule.Port || !util.IsValidPort(rule.Backend.ServicePort+endPort-rule.Port) {
+		return fmt.Errorf("invalid endPort: %d", rule.EndPort)
+	}
+	if ports := endPort - rule.Port + 1; ports > MaxUDPIngressRulePorts {
+		return fmt.Errorf("the range of ports from %d to %d exceeds the maximum of %d ports", rule.Port, endPort, MaxUDPIngressRulePorts)
+	}
+	return nil
+}
+
+// UDPIngressRuleEndPort provides the last port of the range a UDPIngress rule
+// listens on, which is routed to the range of service ports at the same
+// offsets.
+func UDPIngressRuleEndPort(rule configurationv1beta1.UDPIngressRule) int {
+	if rule.EndPort != 0 {
+		return rule.EndPort
+	}
+	return rule.Port
+}
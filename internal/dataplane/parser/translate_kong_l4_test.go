@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/kong/go-kong/kong"
@@ -161,6 +162,77 @@ func TestFromTCPIngressV1beta1(t *testing.T) {
 				},
 			},
 		},
+		// 7
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+				Annotations: map[string]string{
+					annotations.IngressClassKey: annotations.DefaultIngressClass,
+				},
+			},
+			Spec: configurationv1beta1.TCPIngressSpec{
+				Rules: []configurationv1beta1.IngressRule{
+					{
+						Host:          "tenant-a.example.com",
+						Port:          9443,
+						TLSSecretName: "tenant-a",
+						Backend: configurationv1beta1.IngressBackend{
+							ServiceName: "tenant-a-svc",
+							ServicePort: 80,
+						},
+					},
+					{
+						Host:          "tenant-b.example.com",
+						Port:          9443,
+						TLSSecretName: "tenant-b",
+						Backend: configurationv1beta1.IngressBackend{
+							ServiceName: "tenant-b-svc",
+							ServicePort: 80,
+						},
+					},
+					{
+						Host: "tenant-c.example.com",
+						Port: 9443,
+						Backend: configurationv1beta1.IngressBackend{
+							ServiceName: "tenant-c-svc",
+							ServicePort: 80,
+						},
+					},
+				},
+				TLS: []configurationv1beta1.IngressTLS{
+					{
+						Hosts: []string{
+							"tenant-a.example.com",
+							"tenant-c.example.com",
+						},
+						SecretName: "shared",
+					},
+				},
+			},
+		},
+		// 8
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+				Annotations: map[string]string{
+					annotations.IngressClassKey: annotations.DefaultIngressClass,
+				},
+			},
+			Spec: configurationv1beta1.TCPIngressSpec{
+				Rules: []configurationv1beta1.IngressRule{
+					{
+						Port:          9443,
+						TLSSecretName: "tenant-a",
+						Backend: configurationv1beta1.IngressBackend{
+							ServiceName: "tenant-a-svc",
+							ServicePort: 80,
+						},
+					},
+				},
+			},
+		},
 	}
 	t.Run("no TCPIngress returns empty info", func(t *testing.T) {
 		store, err := store.NewFakeStore(store.FakeObjects{
@@ -275,6 +347,12 @@ func TestFromTCPIngressV1beta1(t *testing.T) {
 			ServiceNameToServices: make(map[string]kongstate.Service),
 			SecretNameToSNIs:      make(map[string][]string),
 		}, parsedInfo)
+
+		failures := p.PopTranslationFailures()
+		assert.Len(failures, 1)
+		assert.Equal("invalid TCPIngress: empty serviceName", failures[0].Message)
+		assert.Equal(tcpIngressList[4], failures[0].Object)
+		assert.Empty(p.PopTranslationFailures())
 	})
 	t.Run("TCPIngress with invalid port returns empty info", func(t *testing.T) {
 		store, err := store.NewFakeStore(store.FakeObjects{
@@ -290,6 +368,12 @@ func TestFromTCPIngressV1beta1(t *testing.T) {
 			ServiceNameToServices: make(map[string]kongstate.Service),
 			SecretNameToSNIs:      make(map[string][]string),
 		}, parsedInfo)
+
+		failures := p.PopTranslationFailures()
+		assert.Len(failures, 1)
+		assert.Equal("invalid TCPIngress: invalid port: 0", failures[0].Message)
+		assert.Equal(tcpIngressList[5], failures[0].Object)
+		assert.Empty(p.PopTranslationFailures())
 	})
 	t.Run("empty TCPIngress with invalid service port returns empty info", func(t *testing.T) {
 		store, err := store.NewFakeStore(store.FakeObjects{
@@ -305,5 +389,222 @@ func TestFromTCPIngressV1beta1(t *testing.T) {
 			ServiceNameToServices: make(map[string]kongstate.Service),
 			SecretNameToSNIs:      make(map[string][]string),
 		}, parsedInfo)
+
+		failures := p.PopTranslationFailures()
+		assert.Len(failures, 1)
+		assert.Equal("invalid TCPIngress: invalid servicePort: 0", failures[0].Message)
+		assert.Equal(tcpIngressList[6], failures[0].Object)
+	})
+	t.Run("TCPIngress rules with TLS secrets", func(t *testing.T) {
+		store, err := store.NewFakeStore(store.FakeObjects{
+			TCPIngresses: []*configurationv1beta1.TCPIngress{
+				tcpIngressList[7],
+			},
+		})
+		assert.NoError(err)
+		p := NewParser(logrus.New(), store)
+
+		parsedInfo := p.ingressRulesFromTCPIngressV1beta1()
+		assert.Equal(SecretNameToSNIs{
+			"default/tenant-a": {"tenant-a.example.com"},
+			"default/tenant-b": {"tenant-b.example.com"},
+			"default/shared":   {"tenant-c.example.com"},
+		}, parsedInfo.SecretNameToSNIs, "the secrets of the rules take precedence over the TLS section")
+		assert.Len(parsedInfo.ServiceNameToServices, 3)
+		assert.Empty(p.PopTranslationFailures())
+	})
+	t.Run("TCPIngress rule with TLS secret without host", func(t *testing.T) {
+		store, err := store.NewFakeStore(store.FakeObjects{
+			TCPIngresses: []*configurationv1beta1.TCPIngress{
+				tcpIngressList[8],
+			},
+		})
+		assert.NoError(err)
+		p := NewParser(logrus.New(), store)
+
+		parsedInfo := p.ingressRulesFromTCPIngressV1beta1()
+		assert.Empty(parsedInfo.ServiceNameToServices)
+		assert.Empty(parsedInfo.SecretNameToSNIs)
+
+		failures := p.PopTranslationFailures()
+		assert.Len(failures, 1)
+		assert.Equal("invalid TCPIngress: tlsSecretName requires a host", failures[0].Message)
+		assert.Equal(tcpIngressList[8], failures[0].Object)
+	})
+}
+
+func TestFromUDPIngressV1beta1(t *testing.T) {
+	ingress := &configurationv1beta1.UDPIngress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: "default",
+			Annotations: map[string]string{
+				annotations.IngressClassKey: annotations.DefaultIngressClass,
+			},
+		},
+		Spec: configurationv1beta1.UDPIngressSpec{
+			Rules: []configurationv1beta1.UDPIngressRule{
+				{
+					Port:    53,
+					Backend: configurationv1beta1.IngressBackend{ServiceName: "dns", ServicePort: 53},
+				},
+				{
+					Port:    7000,
+					EndPort: 7002,
+					Backend: configurationv1beta1.IngressBackend{ServiceName: "game", ServicePort: 17000},
+				},
+				{
+					Port:    8000,
+					EndPort: 7999,
+					Backend: configurationv1beta1.IngressBackend{ServiceName: "game", ServicePort: 18000},
+				},
+				{
+					Port:    1,
+					EndPort: 65535,
+					Backend: configurationv1beta1.IngressBackend{ServiceName: "all", ServicePort: 1},
+				},
+			},
+		},
+	}
+	store, err := store.NewFakeStore(store.FakeObjects{
+		UDPIngresses: []*configurationv1beta1.UDPIngress{ingress},
+	})
+	assert.NoError(t, err)
+	p := NewParser(logrus.New(), store)
+
+	parsedInfo := p.ingressRulesFromUDPIngressV1beta1()
+	assert.Len(t, parsedInfo.ServiceNameToServices, 4)
+
+	t.Log("verifying that a single port is routed to the service port")
+	svc := parsedInfo.ServiceNameToServices["default.dns.53.udp"]
+	assert.Equal(t, 53, *svc.Port)
+	assert.Len(t, svc.Routes, 1)
+	assert.Equal(t, "default.foo.0.udp", *svc.Routes[0].Name)
+	assert.Equal(t, []*kong.CIDRPort{{Port: kong.Int(53)}}, svc.Routes[0].Destinations)
+
+	t.Log("verifying that each port of a range is routed to the service port at the same offset")
+	for offset := 0; offset <= 2; offset++ {
+		svc := parsedInfo.ServiceNameToServices[fmt.Sprintf("default.game.%d.udp", 17000+offset)]
+		assert.Equal(t, 17000+offset, *svc.Port)
+		assert.Len(t, svc.Routes, 1)
+		assert.Equal(t, fmt.Sprintf("default.foo.1.%d.udp", 7000+offset), *svc.Routes[0].Name)
+		assert.Equal(t, []*kong.CIDRPort{{Port: kong.Int(7000 + offset)}}, svc.Routes[0].Destinations)
+	}
+
+	t.Log("verifying that a range ending before its start and a range exceeding the maximum number of ports are reported")
+	failures := p.PopTranslationFailures()
+	assert.Len(t, failures, 2)
+	assert.Equal(t, "invalid UDPIngress: invalid endPort: 7999", failures[0].Message)
+	assert.Equal(t, "invalid UDPIngress: the range of ports from 1 to 65535 exceeds the maximum of 1000 ports", failures[1].Message)
+}
+
+func TestL4IngressPortConflicts(t *testing.T) {
+	older, newer := metav1.Unix(1000, 0), metav1.Unix(2000, 0)
+	tcpIngress := func(name string, created metav1.Time, rules ...configurationv1beta1.IngressRule) *configurationv1beta1.TCPIngress {
+		return &configurationv1beta1.TCPIngress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				CreationTimestamp: created,
+				Annotations: map[string]string{
+					annotations.IngressClassKey: annotations.DefaultIngressClass,
+				},
+			},
+			Spec: configurationv1beta1.TCPIngressSpec{Rules: rules},
+		}
+	}
+	tcpRule := func(host string, port int) configurationv1beta1.IngressRule {
+		return configurationv1beta1.IngressRule{
+			Host:    host,
+			Port:    port,
+			Backend: configurationv1beta1.IngressBackend{ServiceName: "svc", ServicePort: 80},
+		}
+	}
+
+	t.Run("the oldest TCPIngress claiming a listener is routed it", func(t *testing.T) {
+		winner := tcpIngress("b", older, tcpRule("", 9000))
+		loser := tcpIngress("a", newer, tcpRule("", 9000), tcpRule("", 9001), tcpRule("example.com", 9000))
+		store, err := store.NewFakeStore(store.FakeObjects{
+			TCPIngresses: []*configurationv1beta1.TCPIngress{loser, winner},
+		})
+		assert.NoError(t, err)
+		p := NewParser(logrus.New(), store)
+
+		parsedInfo := p.ingressRulesFromTCPIngressV1beta1()
+		routes := parsedInfo.ServiceNameToServices["default.svc.80"].Routes
+		var routeNames []string
+		for _, route := range routes {
+			routeNames = append(routeNames, *route.Name)
+		}
+		assert.Equal(t, []string{"default.b.0", "default.a.1", "default.a.2"}, routeNames)
+
+		conflicts := p.PopPortConflicts()
+		assert.Len(t, conflicts, 1)
+		assert.Equal(t, loser, conflicts[0].Object)
+		assert.Equal(t, winner, conflicts[0].Winner)
+		assert.Equal(t, "rule 0 ignored: port 9000 is already claimed by TCPIngress default/b, which takes precedence",
+			conflicts[0].Message)
+		failures := p.PopTranslationFailures()
+		assert.Len(t, failures, 1)
+		assert.Equal(t, loser, failures[0].Object)
+		assert.Equal(t, conflicts[0].Message, failures[0].Message)
+	})
+
+	t.Run("TCPIngresses created at the same time are ordered by name", func(t *testing.T) {
+		store, err := store.NewFakeStore(store.FakeObjects{
+			TCPIngresses: []*configurationv1beta1.TCPIngress{
+				tcpIngress("b", older, tcpRule("Example.com", 9000)),
+				tcpIngress("a", older, tcpRule("example.com", 9000)),
+			},
+		})
+		assert.NoError(t, err)
+		p := NewParser(logrus.New(), store)
+
+		p.ingressRulesFromTCPIngressV1beta1()
+		conflicts := p.PopPortConflicts()
+		assert.Len(t, conflicts, 1)
+		assert.Equal(t, "b", conflicts[0].Object.GetName())
+		assert.Equal(t, `rule 0 ignored: port 9000 with host "example.com" is already claimed by TCPIngress default/a, `+
+			"which takes precedence", conflicts[0].Message)
+	})
+
+	t.Run("UDPIngresses whose port ranges overlap", func(t *testing.T) {
+		udpIngress := func(name string, created metav1.Time, port, endPort int) *configurationv1beta1.UDPIngress {
+			return &configurationv1beta1.UDPIngress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              name,
+					Namespace:         "default",
+					CreationTimestamp: created,
+					Annotations: map[string]string{
+						annotations.IngressClassKey: annotations.DefaultIngressClass,
+					},
+				},
+				Spec: configurationv1beta1.UDPIngressSpec{
+					Rules: []configurationv1beta1.UDPIngressRule{{
+						Port:    port,
+						EndPort: endPort,
+						Backend: configurationv1beta1.IngressBackend{ServiceName: name, ServicePort: 53},
+					}},
+				},
+			}
+		}
+		winner := udpIngress("game", older, 7000, 7002)
+		loser := udpIngress("dns", newer, 7002, 0)
+		store, err := store.NewFakeStore(store.FakeObjects{
+			UDPIngresses: []*configurationv1beta1.UDPIngress{loser, winner},
+		})
+		assert.NoError(t, err)
+		p := NewParser(logrus.New(), store)
+
+		parsedInfo := p.ingressRulesFromUDPIngressV1beta1()
+		assert.Len(t, parsedInfo.ServiceNameToServices, 3)
+		assert.NotContains(t, parsedInfo.ServiceNameToServices, "default.dns.53.udp")
+
+		conflicts := p.PopPortConflicts()
+		assert.Len(t, conflicts, 1)
+		assert.Equal(t, loser, conflicts[0].Object)
+		assert.Equal(t, winner, conflicts[0].Winner)
+		assert.Equal(t, "rule 0 ignored: port 7002 is already claimed by UDPIngress default/game, which takes precedence",
+			conflicts[0].Message)
 	})
 }
@@ -3,9 +3,13 @@ package parser
 import (
 	"testing"
 
+	"github.com/blang/semver/v4"
 	"github.com/kong/go-kong/kong"
 	"github.com/stretchr/testify/require"
 	networkingv1 "k8s.io/api/networking/v1"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
 )
 
 func TestPathsFromK8s(t *testing.T) {
@@ -77,3 +81,158 @@ func TestPathsFromK8s(t *testing.T) {
 		})
 	}
 }
+
+func TestMaybePrefixRegexPath(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		path        string
+		regexPrefix string
+		legacy      bool
+		want        string
+	}{
+		{
+			name:        "plain path",
+			path:        "/foo",
+			regexPrefix: defaultRegexPrefix,
+			want:        "/foo",
+		},
+		{
+			name:        "generated regex path",
+			path:        "/foo$",
+			regexPrefix: defaultRegexPrefix,
+			want:        "~/foo$",
+		},
+		{
+			name:        "already prefixed",
+			path:        "~/foo/[0-9]+",
+			regexPrefix: defaultRegexPrefix,
+			want:        "~/foo/[0-9]+",
+		},
+		{
+			name:        "default regex prefix",
+			path:        "/~/foo/[0-9]+",
+			regexPrefix: defaultRegexPrefix,
+			want:        "~/foo/[0-9]+",
+		},
+		{
+			name:        "custom regex prefix",
+			path:        "/regex/foo/[0-9]+",
+			regexPrefix: "/regex",
+			want:        "~/foo/[0-9]+",
+		},
+		{
+			name:        "custom regex prefix only matches whole path segments",
+			path:        "/rest/api",
+			regexPrefix: "/re",
+			want:        "/rest/api",
+		},
+		{
+			name:        "regex without prefix is a plain path",
+			path:        "/foo/[0-9]+",
+			regexPrefix: defaultRegexPrefix,
+			want:        "/foo/[0-9]+",
+		},
+		{
+			name:        "legacy regex detection",
+			path:        "/foo/[0-9]+",
+			regexPrefix: defaultRegexPrefix,
+			legacy:      true,
+			want:        "~/foo/[0-9]+",
+		},
+		{
+			name:        "legacy regex detection of plain path",
+			path:        "/foo/bar-baz_1.2",
+			regexPrefix: defaultRegexPrefix,
+			legacy:      true,
+			want:        "/foo/bar-baz_1.2",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, maybePrefixRegexPath(tt.path, tt.regexPrefix, tt.legacy))
+		})
+	}
+}
+
+func TestPrefixRegexPaths(t *testing.T) {
+	state := &kongstate.KongState{
+		Services: []kongstate.Service{{
+			Routes: []kongstate.Route{
+				{
+					Route: kong.Route{Paths: kong.StringSlice("/foo$", "/foo/", "/~/bar/[a-z]+")},
+				},
+				{
+					Ingress: util.K8sObjectInfo{Annotations: map[string]string{
+						"konghq.com/regex-prefix":      "/re",
+						"konghq.com/legacy-regex-path": "true",
+					}},
+					Route: kong.Route{Paths: kong.StringSlice("/re/baz/.*", "/qux/[0-9]+", "/quux")},
+				},
+			},
+		}},
+	}
+
+	prefixRegexPaths(state)
+
+	require.Equal(t, kong.StringSlice("~/foo$", "/foo/", "~/bar/[a-z]+"), state.Services[0].Routes[0].Paths)
+	require.Equal(t, kong.StringSlice("~/baz/.*", "~/qux/[0-9]+", "/quux"), state.Services[0].Routes[1].Paths)
+}
+
+func TestKongRegexPaths(t *testing.T) {
+	legacyVersion := semver.MustParse("2.8.0")
+	for _, tt := range []struct {
+		name        string
+		path        string
+		pathType    networkingv1.PathType
+		annotations map[string]string
+		kongVersion semver.Version
+		want        []string
+	}{
+		{
+			name:        "prefix paths translate to a regex and a plain path",
+			path:        "/foo/",
+			pathType:    networkingv1.PathTypePrefix,
+			kongVersion: MinRegexPathPrefixKongVersion,
+			want:        []string{"/foo$"},
+		},
+		{
+			name:        "implementation specific paths are regexes with the regex prefix",
+			path:        "/~/foo/[a-z]+",
+			pathType:    networkingv1.PathTypeImplementationSpecific,
+			kongVersion: MinRegexPathPrefixKongVersion,
+			want:        []string{"/foo/[a-z]+"},
+		},
+		{
+			name:        "implementation specific paths are plain paths without the regex prefix",
+			path:        "/foo/[a-z]+",
+			pathType:    networkingv1.PathTypeImplementationSpecific,
+			kongVersion: MinRegexPathPrefixKongVersion,
+		},
+		{
+			name:        "the regex prefix can be overridden",
+			path:        "/re/foo/[a-z]+",
+			pathType:    networkingv1.PathTypeImplementationSpecific,
+			annotations: map[string]string{"konghq.com/regex-prefix": "/re"},
+			kongVersion: MinRegexPathPrefixKongVersion,
+			want:        []string{"/foo/[a-z]+"},
+		},
+		{
+			name:        "paths with regex characters are regexes before Kong 3.0",
+			path:        "/foo/[a-z]+",
+			pathType:    networkingv1.PathTypeImplementationSpecific,
+			kongVersion: legacyVersion,
+			want:        []string{"/foo/[a-z]+"},
+		},
+		{
+			name:        "paths without regex characters are plain paths before Kong 3.0",
+			path:        "/foo",
+			pathType:    networkingv1.PathTypeImplementationSpecific,
+			kongVersion: legacyVersion,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := KongRegexPaths(tt.path, tt.pathType, tt.annotations, tt.kongVersion)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
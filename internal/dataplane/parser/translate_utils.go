@@ -137,7 +137,8 @@ func (p *Parser) generateKongServiceFromBackendRef(
 					Mode:   kongstate.PortModeByNumber,
 					Number: int32(*backendRef.Port),
 				},
-				Weight: backendRef.Weight,
+				Weight:        backendRef.Weight,
+				ServiceImport: isServiceImportRef(backendRef.Group, backendRef.Kind),
 			}
 			if backendRef.Namespace != nil {
 				backend.Namespace = string(*backendRef.Namespace)
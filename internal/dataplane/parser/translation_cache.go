@@ -0,0 +1,102 @@
+package parser
+
+import (
+	"sync"
+
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+)
+
+// -----------------------------------------------------------------------------
+// Parser - Translation Cache
+// -----------------------------------------------------------------------------
+
+// TranslationCache holds the Kong Services and Routes translated from Kubernetes
+// objects, keyed by the UID and resourceVersion of the objects, so that objects
+// which haven't changed since a previous translation aren't translated again.
+//
+// Only the translation of the objects themselves is cached: the Kubernetes
+// Services, Secrets and plugins they refer to are resolved on every translation,
+// so that changes to them are always taken into account.
+type TranslationCache struct {
+	lock       sync.Mutex
+	entries    map[k8stypes.UID]*translationCacheEntry
+	generation uint64
+}
+
+// translationCacheEntry is the cached translation of a single object.
+type translationCacheEntry struct {
+	resourceVersion string
+	services        []*kongstate.Service
+
+	// generation is the last translation the entry was used in.
+	generation uint64
+}
+
+// NewTranslationCache provides an empty TranslationCache.
+func NewTranslationCache() *TranslationCache {
+	return &TranslationCache{entries: make(map[k8stypes.UID]*translationCacheEntry)}
+}
+
+// Len provides the number of objects whose translation is cached.
+func (c *TranslationCache) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return len(c.entries)
+}
+
+// begin marks the start of a translation, returning its generation.
+func (c *TranslationCache) begin() uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.generation++
+	return c.generation
+}
+
+// end marks the end of the translation of the given generation, evicting the
+// entries of the objects which weren't part of it (e.g. because they were
+// deleted). Nothing is evicted if a later translation has begun since, as it
+// may not have used the entries it needs yet.
+func (c *TranslationCache) end(generation uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if generation != c.generation {
+		return
+	}
+	for uid, entry := range c.entries {
+		if entry.generation != c.generation {
+			delete(c.entries, uid)
+		}
+	}
+}
+
+// translate provides the Services translated from obj, calling translateFn only
+// if obj isn't cached yet or has changed since it was. The Services returned are
+// copies which the caller is free to modify.
+func (c *TranslationCache) translate(obj client.Object, translateFn func() []*kongstate.Service) []*kongstate.Service {
+	uid, resourceVersion := obj.GetUID(), obj.GetResourceVersion()
+	// objects which weren't persisted by the API server can't be told apart
+	if uid == "" || resourceVersion == "" {
+		return translateFn()
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	entry, ok := c.entries[uid]
+	if !ok || entry.resourceVersion != resourceVersion {
+		entry = &translationCacheEntry{
+			resourceVersion: resourceVersion,
+			services:        translateFn(),
+		}
+		c.entries[uid] = entry
+	}
+	entry.generation = c.generation
+
+	services := make([]*kongstate.Service, 0, len(entry.services))
+	for _, service := range entry.services {
+		services = append(services, service.DeepCopy())
+	}
+	return services
+}
@@ -0,0 +1,136 @@
+package parser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
+)
+
+func TestTranslationCache(t *testing.T) {
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", UID: "1", ResourceVersion: "1"},
+	}
+	translations := 0
+	translate := func() []*kongstate.Service {
+		translations++
+		return []*kongstate.Service{{
+			Service: kong.Service{Name: kong.String("default.foo.80")},
+			Routes:  []kongstate.Route{{Route: kong.Route{Paths: kong.StringSlice("/foo")}}},
+		}}
+	}
+
+	cache := NewTranslationCache()
+	generation := cache.begin()
+	services := cache.translate(ingress, translate)
+	cache.end(generation)
+	assert.Equal(t, 1, translations)
+
+	t.Log("modifying the translation doesn't affect the cache")
+	services[0].Routes[0].Paths[0] = kong.String("~/foo")
+
+	t.Log("unchanged objects aren't translated again")
+	generation = cache.begin()
+	services = cache.translate(ingress, translate)
+	cache.end(generation)
+	assert.Equal(t, 1, translations)
+	assert.Equal(t, "/foo", *services[0].Routes[0].Paths[0])
+
+	t.Log("changed objects are translated again")
+	ingress.ResourceVersion = "2"
+	generation = cache.begin()
+	cache.translate(ingress, translate)
+	cache.end(generation)
+	assert.Equal(t, 2, translations)
+
+	t.Log("objects which aren't part of a translation are evicted")
+	generation = cache.begin()
+	cache.end(generation)
+	assert.Equal(t, 0, cache.Len())
+
+	t.Log("objects without a UID aren't cached")
+	generation = cache.begin()
+	cache.translate(&networkingv1.Ingress{}, translate)
+	cache.end(generation)
+	assert.Equal(t, 3, translations)
+	assert.Equal(t, 0, cache.Len())
+
+	t.Log("translations ending after a later one began don't evict its objects")
+	earlier := cache.begin()
+	generation = cache.begin()
+	cache.translate(ingress, translate)
+	cache.end(earlier)
+	assert.Equal(t, 1, cache.Len())
+	cache.end(generation)
+	assert.Equal(t, 1, cache.Len())
+}
+
+func TestTranslationCacheBuild(t *testing.T) {
+	pathType := networkingv1.PathTypePrefix
+	s, err := store.NewFakeStore(store.FakeObjects{
+		IngressesV1: []*networkingv1.Ingress{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "foo",
+					Namespace:       "default",
+					UID:             "1",
+					ResourceVersion: "1",
+					Annotations:     map[string]string{"kubernetes.io/ingress.class": "kong"},
+				},
+				Spec: networkingv1.IngressSpec{
+					Rules: []networkingv1.IngressRule{{
+						Host: "example.com",
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{{
+									Path:     "/foo",
+									PathType: &pathType,
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: "foo",
+											Port: networkingv1.ServiceBackendPort{Number: 80},
+										},
+									},
+								}},
+							},
+						},
+					}},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	cache := NewTranslationCache()
+	build := func() *kongstate.KongState {
+		p := NewParser(logrus.New(), s)
+		p.EnableCombinedServiceRoutes()
+		p.EnableRegexPathPrefix()
+		p.SetTranslationCache(cache)
+		state, err := p.Build()
+		require.NoError(t, err)
+		return state
+	}
+
+	first := build()
+	require.Len(t, first.Services, 1)
+	assert.Equal(t, 1, cache.Len())
+	assert.Equal(t, first.Services, build().Services, "cached translations produce the same configuration")
+
+	t.Log("cancelled translations stop and don't evict cached objects")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	p := NewParser(logrus.New(), s)
+	p.SetTranslationCache(cache)
+	_, err = p.BuildWithContext(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, cache.Len())
+}
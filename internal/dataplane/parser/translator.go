@@ -0,0 +1,153 @@
+package parser
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	networkingv1 "k8s.io/api/networking/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
+)
+
+// -----------------------------------------------------------------------------
+// Parser - Translators
+// -----------------------------------------------------------------------------
+
+// Translator translates the Kubernetes objects of a kind (e.g. Ingresses or
+// HTTPRoutes) into the Kong services and routes serving them. The parser
+// runs the built-in translators first, then the translators registered with
+// RegisterTranslator in the order they were registered, and merges what they
+// translated before filling in the rest of the configuration (e.g. plugins,
+// upstreams and certificates) the same way for all of them.
+type Translator interface {
+	// Name identifies the translator, e.g. in logs.
+	Name() string
+
+	// Translate translates the objects of the kind the translator handles,
+	// which it retrieves from the store of ctx.
+	Translate(ctx TranslationContext) TranslationResult
+}
+
+// TranslationContext provides translators with the Kubernetes objects to
+// translate and the means to report on them.
+type TranslationContext struct {
+	// Logger logs the problems which don't prevent the translation.
+	Logger logrus.FieldLogger
+
+	// Storer provides the Kubernetes objects to translate.
+	Storer store.Storer
+
+	parser *Parser
+}
+
+// RegisterTranslationFailure records a problem which prevented obj, or part
+// of it, from being translated, which is reported to users (e.g. through
+// Events).
+func (ctx TranslationContext) RegisterTranslationFailure(obj client.Object, message string) {
+	ctx.parser.registerTranslationFailure(obj, message)
+}
+
+// ReportKubernetesObjectUpdate reports that obj was translated, so that its
+// status can reflect whether it's configured in the data-plane.
+func (ctx TranslationContext) ReportKubernetesObjectUpdate(obj client.Object) {
+	ctx.parser.ReportKubernetesObjectUpdate(obj)
+}
+
+// TranslationResult is what a Translator translated. The zero value is not
+// usable, use NewTranslationResult.
+type TranslationResult struct {
+	rules ingressRules
+}
+
+// NewTranslationResult provides an empty TranslationResult.
+func NewTranslationResult() TranslationResult {
+	return TranslationResult{rules: newIngressRules()}
+}
+
+// AddService adds a Kong service along with its routes, which should refer to
+// the objects they're translated from. The routes of services of the same
+// name added to a TranslationResult are merged.
+func (r *TranslationResult) AddService(service kongstate.Service) {
+	if existing, ok := r.rules.ServiceNameToServices[*service.Name]; ok {
+		existing.Routes = append(existing.Routes, service.Routes...)
+		service = existing
+	}
+	r.rules.ServiceNameToServices[*service.Name] = service
+}
+
+// AddTLS serves the certificate of the Secret of the given name in the
+// namespace of owner for the given hosts, which owner requests. When several
+// objects request different Secrets for a host, the oldest object wins.
+func (r *TranslationResult) AddTLS(owner client.Object, secretName string, hosts ...string) {
+	r.rules.addTLSFromIngressV1([]networkingv1.IngressTLS{{Hosts: hosts, SecretName: secretName}}, owner)
+}
+
+// builtinTranslator is a translator of this package, which has access to all
+// the features of the parser.
+type builtinTranslator struct {
+	name      string
+	translate func(*Parser) ingressRules
+}
+
+func (t builtinTranslator) Name() string {
+	return t.name
+}
+
+func (t builtinTranslator) Translate(ctx TranslationContext) TranslationResult {
+	return TranslationResult{rules: t.translate(ctx.parser)}
+}
+
+// builtinTranslators are the translators of the kinds this controller
+// supports, in the order they run.
+var builtinTranslators = []Translator{
+	builtinTranslator{name: "IngressV1beta1", translate: func(p *Parser) ingressRules {
+		return p.ingressRulesFromIngressV1beta1().routeToExternalUpstreams()
+	}},
+	builtinTranslator{name: "IngressV1", translate: func(p *Parser) ingressRules {
+		return p.ingressRulesFromIngressV1().routeToExternalUpstreams()
+	}},
+	builtinTranslator{name: "TCPIngressV1beta1", translate: (*Parser).ingressRulesFromTCPIngressV1beta1},
+	builtinTranslator{name: "UDPIngressV1beta1", translate: (*Parser).ingressRulesFromUDPIngressV1beta1},
+	builtinTranslator{name: "KnativeIngress", translate: (*Parser).ingressRulesFromKnativeIngress},
+	builtinTranslator{name: "HTTPRoute", translate: (*Parser).ingressRulesFromHTTPRoutes},
+	builtinTranslator{name: "UDPRoute", translate: (*Parser).ingressRulesFromUDPRoutes},
+	builtinTranslator{name: "TCPRoute", translate: (*Parser).ingressRulesFromTCPRoutes},
+	builtinTranslator{name: "TLSRoute", translate: (*Parser).ingressRulesFromTLSRoutes},
+}
+
+var (
+	registeredTranslatorsLock sync.RWMutex
+	registeredTranslators     []Translator
+)
+
+// RegisterTranslator adds a translator to the ones all parsers run, allowing
+// builds of the controller to translate additional kinds of Kubernetes
+// objects, typically from the init function of the package implementing it.
+// It panics if a translator of the same name is already registered.
+func RegisterTranslator(translator Translator) {
+	registeredTranslatorsLock.Lock()
+	defer registeredTranslatorsLock.Unlock()
+	for _, existing := range listTranslators() {
+		if existing.Name() == translator.Name() {
+			panic(fmt.Sprintf("translator %q is already registered", translator.Name()))
+		}
+	}
+	registeredTranslators = append(registeredTranslators, translator)
+}
+
+// allTranslators provides the translators parsers run, in order.
+func allTranslators() []Translator {
+	registeredTranslatorsLock.RLock()
+	defer registeredTranslatorsLock.RUnlock()
+	return listTranslators()
+}
+
+// listTranslators provides the translators parsers run, in order. The caller
+// must hold registeredTranslatorsLock.
+func listTranslators() []Translator {
+	all := make([]Translator, 0, len(builtinTranslators)+len(registeredTranslators))
+	return append(append(all, builtinTranslators...), registeredTranslators...)
+}
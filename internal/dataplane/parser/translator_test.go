@@ -0,0 +1,149 @@
+package parser
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+)
+
+const exposePathAnnotation = "example.com/expose-path"
+
+// exposeServicesTranslator exposes the given Services which are annotated
+// with the path to route to them, as an out-of-tree translator would.
+type exposeServicesTranslator struct {
+	services []types.NamespacedName
+}
+
+func (exposeServicesTranslator) Name() string {
+	return "ExposedService"
+}
+
+func (t exposeServicesTranslator) Translate(ctx TranslationContext) TranslationResult {
+	result := NewTranslationResult()
+	for _, nn := range t.services {
+		svc, err := ctx.Storer.GetService(nn.Namespace, nn.Name)
+		if err != nil {
+			ctx.Logger.Errorf("failed to get service %s: %v", nn, err)
+			continue
+		}
+		path, ok := svc.Annotations[exposePathAnnotation]
+		if !ok {
+			continue
+		}
+		if len(svc.Spec.Ports) == 0 {
+			ctx.RegisterTranslationFailure(svc, "service has no port to expose")
+			continue
+		}
+		name := svc.Namespace + ".exposed." + svc.Name
+		port := svc.Spec.Ports[0].Port
+		result.AddService(kongstate.Service{
+			Service: kong.Service{
+				Name:     kong.String(name),
+				Host:     kong.String(fmt.Sprintf("%s.%s.%d.svc", svc.Name, svc.Namespace, port)),
+				Port:     kong.Int(int(port)),
+				Protocol: kong.String("http"),
+			},
+			Namespace: svc.Namespace,
+			Backends: []kongstate.ServiceBackend{{
+				Name:    svc.Name,
+				PortDef: kongstate.PortDef{Mode: kongstate.PortModeByNumber, Number: port},
+			}},
+			Routes: []kongstate.Route{{
+				Route: kong.Route{
+					Name:  kong.String(name),
+					Paths: kong.StringSlice(path),
+				},
+				Ingress: util.FromK8sObject(svc),
+			}},
+		})
+		ctx.ReportKubernetesObjectUpdate(svc)
+	}
+	return result
+}
+
+func registerTestTranslator(t *testing.T, translator Translator) {
+	RegisterTranslator(translator)
+	t.Cleanup(func() {
+		registeredTranslatorsLock.Lock()
+		defer registeredTranslatorsLock.Unlock()
+		registeredTranslators = nil
+	})
+}
+
+func TestRegisteredTranslators(t *testing.T) {
+	registerTestTranslator(t, exposeServicesTranslator{services: []types.NamespacedName{
+		{Namespace: "default", Name: "exposed"},
+		{Namespace: "default", Name: "portless"},
+		{Namespace: "default", Name: "hidden"},
+		{Namespace: "default", Name: "missing"},
+	}})
+
+	storer, err := store.NewFakeStore(store.FakeObjects{
+		Services: []*corev1.Service{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "exposed",
+					Namespace:   "default",
+					Annotations: map[string]string{exposePathAnnotation: "/exposed"},
+				},
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{{Port: 8080}},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "portless",
+					Namespace:   "default",
+					Annotations: map[string]string{exposePathAnnotation: "/portless"},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "hidden",
+					Namespace: "default",
+				},
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{{Port: 8080}},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	p := NewParser(logrus.New(), storer)
+	p.EnableKubernetesObjectReports()
+	state, err := p.Build()
+	require.NoError(t, err)
+
+	require.Len(t, state.Services, 1)
+	service := state.Services[0]
+	assert.Equal(t, "default.exposed.exposed", *service.Name)
+	assert.Contains(t, service.K8sServices, "exposed")
+	require.Len(t, service.Routes, 1)
+	assert.Equal(t, []*string{kong.String("/exposed")}, service.Routes[0].Paths)
+	require.Len(t, state.Upstreams, 1)
+	assert.Equal(t, "exposed.default.8080.svc", *state.Upstreams[0].Name)
+
+	failures := p.PopTranslationFailures()
+	require.Len(t, failures, 1)
+	assert.Equal(t, "portless", failures[0].Object.GetName())
+	assert.Len(t, p.GenerateKubernetesObjectReport(), 1)
+}
+
+func TestRegisterTranslatorDuplicateName(t *testing.T) {
+	registerTestTranslator(t, exposeServicesTranslator{})
+
+	assert.Panics(t, func() { RegisterTranslator(exposeServicesTranslator{}) })
+	assert.Panics(t, func() { RegisterTranslator(builtinTranslators[0]) })
+}
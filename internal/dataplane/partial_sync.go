@@ -0,0 +1,68 @@
+package dataplane
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/metrics"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+	configurationv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+)
+
+// -----------------------------------------------------------------------------
+// Dataplane Client - Kong - Partial Sync
+// -----------------------------------------------------------------------------
+
+// translationFailedObjects groups the translation failures by the Kubernetes
+// objects they were encountered on.
+func translationFailedObjects(failures []parser.TranslationFailure) brokenObjects {
+	failed := brokenObjects{}
+	for _, failure := range failures {
+		failed.addErrors(translationFailureObjectInfo(failure.Object), failure.Message)
+	}
+	return failed
+}
+
+// translationFailureObjectInfo provides the object info of an object with
+// translation failures the way the configuration translated from it refers
+// to it, so that the configuration can be excluded.
+func translationFailureObjectInfo(obj client.Object) util.K8sObjectInfo {
+	switch obj := obj.(type) {
+	case *corev1.Service:
+		return serviceObjectInfo(obj)
+	case *configurationv1.KongConsumer:
+		return consumerObjectInfo(obj)
+	}
+	return util.FromK8sObject(obj)
+}
+
+// reportTranslationFailures exposes the Kubernetes objects which couldn't be
+// translated in the last update through metrics and, if enabled, the
+// diagnostics server.
+func (c *KongClient) reportTranslationFailures(failed brokenObjects, excluded bool) {
+	c.prometheusMetrics.TranslationFailedObjects.Reset()
+	reports := make([]util.TranslationFailureReport, 0, len(failed))
+	for _, obj := range failed.sorted() {
+		c.prometheusMetrics.TranslationFailedObjects.With(prometheus.Labels{
+			metrics.KindKey: obj.GroupVersionKind.Kind,
+		}).Inc()
+		reports = append(reports, util.TranslationFailureReport{
+			Kind:      obj.GroupVersionKind.Kind,
+			Namespace: obj.Namespace,
+			Name:      obj.Name,
+			Errors:    obj.errors,
+			Excluded:  excluded,
+		})
+	}
+
+	if c.diagnostic.TranslationFailures == nil {
+		return
+	}
+	select {
+	case c.diagnostic.TranslationFailures <- reports:
+	default:
+		c.logger.Error("translation failure buffer full, dropping translation failure report")
+	}
+}
@@ -0,0 +1,84 @@
+package dataplane
+
+import (
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/metrics"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+)
+
+func TestPartialSync(t *testing.T) {
+	goodIngress := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "good", Namespace: "default"}}
+	goodIngress.SetGroupVersionKind(networkingv1.SchemeGroupVersion.WithKind("Ingress"))
+	badIngress := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "bad", Namespace: "default"}}
+	badIngress.SetGroupVersionKind(networkingv1.SchemeGroupVersion.WithKind("Ingress"))
+	badService := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "default"}}
+
+	state := &kongstate.KongState{
+		Services: []kongstate.Service{
+			{
+				Service: kong.Service{Name: kong.String("default.foo.80"), Host: kong.String("foo.default.80.svc")},
+				Routes: []kongstate.Route{
+					{Route: kong.Route{Name: kong.String("default.good.00")}, Ingress: util.FromK8sObject(goodIngress)},
+					{Route: kong.Route{Name: kong.String("default.bad.00")}, Ingress: util.FromK8sObject(badIngress)},
+				},
+			},
+			{
+				Service:     kong.Service{Name: kong.String("default.bar.80"), Host: kong.String("bar.default.80.svc")},
+				K8sServices: map[string]*corev1.Service{"default/bar": badService},
+			},
+		},
+	}
+
+	t.Log("verifying that translation failures are grouped by object")
+	failed := translationFailedObjects([]parser.TranslationFailure{
+		{Object: badIngress, Message: "invalid path"},
+		{Object: badIngress, Message: "invalid host"},
+		{Object: badService, Message: "invalid port"},
+	})
+	require.Len(t, failed, 2)
+	assert.Equal(t, []string{"invalid path", "invalid host"}, failed[util.FromK8sObject(badIngress).Key()].errors)
+
+	t.Log("verifying that the configuration translated from the failed objects is excluded")
+	partial := excludeBrokenObjects(state, failed)
+	require.Len(t, partial.Services, 1)
+	require.Len(t, partial.Services[0].Routes, 1)
+	assert.Equal(t, "default.good.00", *partial.Services[0].Routes[0].Name)
+
+	t.Log("verifying that the failed objects are reported")
+	reports := make(chan []util.TranslationFailureReport, 1)
+	c := &KongClient{
+		logger:            logrus.New(),
+		prometheusMetrics: metrics.NewCtrlFuncMetrics(),
+		diagnostic:        util.ConfigDumpDiagnostic{TranslationFailures: reports},
+	}
+	c.reportTranslationFailures(failed, true)
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.prometheusMetrics.TranslationFailedObjects.With(prometheus.Labels{
+		metrics.KindKey: "Ingress",
+	})))
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.prometheusMetrics.TranslationFailedObjects.With(prometheus.Labels{
+		metrics.KindKey: "Service",
+	})))
+	require.Len(t, reports, 1)
+	assert.Equal(t, []util.TranslationFailureReport{
+		{Kind: "Service", Namespace: "default", Name: "bar", Errors: []string{"invalid port"}, Excluded: true},
+		{Kind: "Ingress", Namespace: "default", Name: "bad", Errors: []string{"invalid path", "invalid host"}, Excluded: true},
+	}, <-reports)
+
+	t.Log("verifying that objects which were fixed aren't reported anymore")
+	c.reportTranslationFailures(brokenObjects{}, false)
+	assert.Equal(t, 0, testutil.CollectAndCount(c.prometheusMetrics.TranslationFailedObjects))
+	assert.Empty(t, <-reports)
+}
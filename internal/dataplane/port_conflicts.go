@@ -0,0 +1,59 @@
+package dataplane
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/metrics"
+	configurationv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
+)
+
+// -----------------------------------------------------------------------------
+// Dataplane Client - TCPIngress and UDPIngress Port Conflicts
+// -----------------------------------------------------------------------------
+
+// reportPortConflicts exposes the number of rules of TCPIngresses and
+// UDPIngresses ignored in the last translation because of port conflicts
+// through metrics.
+func (c *KongClient) reportPortConflicts(conflicts []parser.PortConflict) {
+	c.prometheusMetrics.PortConflicts.Reset()
+	for _, conflict := range conflicts {
+		c.prometheusMetrics.PortConflicts.With(prometheus.Labels{
+			metrics.KindKey: l4IngressKind(conflict.Object),
+		}).Inc()
+	}
+}
+
+// portConflictMessages groups the messages of the port conflicts by the
+// objects whose rules are ignored.
+func portConflictMessages(conflicts []parser.PortConflict) map[types.UID][]string {
+	messages := make(map[types.UID][]string)
+	for _, conflict := range conflicts {
+		uid := conflict.Object.GetUID()
+		messages[uid] = append(messages[uid], conflict.Message)
+	}
+	return messages
+}
+
+// portConflictCondition computes the PortConflict condition of a TCPIngress
+// or UDPIngress given the messages of the port conflicts by object.
+func portConflictCondition(ingress client.Object, messages map[types.UID][]string) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               configurationv1beta1.IngressConditionPortConflict,
+		Status:             metav1.ConditionFalse,
+		Reason:             configurationv1beta1.IngressReasonNoConflict,
+		Message:            "no object taking precedence claims the ports of the rules",
+		ObservedGeneration: ingress.GetGeneration(),
+	}
+	if ingressMessages := messages[ingress.GetUID()]; len(ingressMessages) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = configurationv1beta1.IngressReasonPortsClaimed
+		condition.Message = strings.Join(ingressMessages, "; ")
+	}
+	return condition
+}
@@ -0,0 +1,47 @@
+package dataplane
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/metrics"
+	configurationv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
+)
+
+func TestPortConflicts(t *testing.T) {
+	winner := &configurationv1beta1.TCPIngress{ObjectMeta: metav1.ObjectMeta{Name: "winner", UID: "uid-winner", Generation: 2}}
+	loser := &configurationv1beta1.TCPIngress{ObjectMeta: metav1.ObjectMeta{Name: "loser", UID: "uid-loser", Generation: 2}}
+	conflicts := []parser.PortConflict{
+		{Object: loser, Winner: winner, Message: "rule 0 ignored"},
+		{Object: loser, Winner: winner, Message: "rule 1 ignored"},
+	}
+
+	t.Log("verifying that the ignored rules are counted by kind")
+	c := &KongClient{
+		logger:            logrus.New(),
+		prometheusMetrics: metrics.NewCtrlFuncMetrics(),
+	}
+	c.reportPortConflicts(conflicts)
+	assert.Equal(t, 2.0, testutil.ToFloat64(c.prometheusMetrics.PortConflicts.With(prometheus.Labels{
+		metrics.KindKey: "TCPIngress",
+	})))
+	c.reportPortConflicts(nil)
+	assert.Equal(t, 0, testutil.CollectAndCount(c.prometheusMetrics.PortConflicts))
+
+	t.Log("verifying that the conditions report the conflicts of each object")
+	messages := portConflictMessages(conflicts)
+	condition := portConflictCondition(loser, messages)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, configurationv1beta1.IngressReasonPortsClaimed, condition.Reason)
+	assert.Equal(t, "rule 0 ignored; rule 1 ignored", condition.Message)
+	assert.Equal(t, int64(2), condition.ObservedGeneration)
+	condition = portConflictCondition(winner, messages)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, configurationv1beta1.IngressReasonNoConflict, condition.Reason)
+}
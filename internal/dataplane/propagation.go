@@ -0,0 +1,93 @@
+package dataplane
+
+import (
+	"reflect"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// observedChange is a version of a Kubernetes object, and when the client
+// observed it.
+type observedChange struct {
+	obj        client.Object
+	observedAt time.Time
+}
+
+// kind provides the kind of the object, which objects from the cache may
+// lack the type information of.
+func (c observedChange) kind() string {
+	if kind := c.obj.GetObjectKind().GroupVersionKind().Kind; kind != "" {
+		return kind
+	}
+	return reflect.Indirect(reflect.ValueOf(c.obj)).Type().Name()
+}
+
+// propagationTracker records when the changes to Kubernetes objects were
+// observed, so that the time they took to propagate to the data-plane can be
+// measured once an update containing them succeeds. The zero value is ready
+// to use.
+type propagationTracker struct {
+	lock sync.Mutex
+	// changes are the latest observed change of each object by UID.
+	changes map[types.UID]observedChange
+	// pending are the objects whose latest change hasn't propagated yet.
+	pending map[types.UID]bool
+}
+
+// observe records the change of obj observed at the given time, unless its
+// version was already observed (e.g. when the object is resynced).
+func (t *propagationTracker) observe(obj client.Object, at time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.changes == nil {
+		t.changes = make(map[types.UID]observedChange)
+		t.pending = make(map[types.UID]bool)
+	}
+	uid := obj.GetUID()
+	if previous, ok := t.changes[uid]; ok && previous.obj.GetResourceVersion() == obj.GetResourceVersion() {
+		return
+	}
+	t.changes[uid] = observedChange{obj: obj, observedAt: at}
+	t.pending[uid] = true
+}
+
+// forget stops tracking the changes of a deleted object.
+func (t *propagationTracker) forget(obj client.Object) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	delete(t.changes, obj.GetUID())
+	delete(t.pending, obj.GetUID())
+}
+
+// pendingChanges provides the changes which haven't propagated yet, all of
+// which the next translation includes.
+func (t *propagationTracker) pendingChanges() []observedChange {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	changes := make([]observedChange, 0, len(t.pending))
+	for uid := range t.pending {
+		changes = append(changes, t.changes[uid])
+	}
+	return changes
+}
+
+// propagated marks the given changes as propagated and provides those which
+// are still the latest change of their object, i.e. which didn't change again
+// meanwhile and weren't deleted.
+func (t *propagationTracker) propagated(changes []observedChange) []observedChange {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	var latest []observedChange
+	for _, change := range changes {
+		uid := change.obj.GetUID()
+		if current, ok := t.changes[uid]; !ok || current.obj.GetResourceVersion() != change.obj.GetResourceVersion() {
+			continue
+		}
+		delete(t.pending, uid)
+		latest = append(latest, change)
+	}
+	return latest
+}
@@ -0,0 +1,71 @@
+package dataplane
+
+import (
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
+)
+
+// isTLSSecretRotation determines whether secret is a new Secret, or a version
+// of a cached Secret with different contents, which the TLS section of an
+// Ingress or TCPIngress references or which holds the default certificate,
+// e.g. a certificate renewed by cert-manager.
+func (c *KongClient) isTLSSecretRotation(secret *corev1.Secret) bool {
+	previous, exists, err := c.cache.Get(secret)
+	if err != nil {
+		return false
+	}
+	if previousSecret, ok := previous.(*corev1.Secret); exists && ok && reflect.DeepEqual(previousSecret.Data, secret.Data) {
+		return false
+	}
+	if secret.Namespace+"/"+secret.Name == c.DefaultCertificate() {
+		return true
+	}
+	storer := store.New(*c.cache, c.ingressClass, false, false, false, c.logger)
+	return isTLSSecretReferenced(storer, secret.Namespace, secret.Name)
+}
+
+// isTLSSecretReferenced determines whether the TLS section of an Ingress or
+// TCPIngress references the given Secret.
+func isTLSSecretReferenced(storer store.Storer, namespace, name string) bool {
+	for _, ingress := range storer.ListIngressesV1() {
+		for _, tls := range ingress.Spec.TLS {
+			if ingress.Namespace == namespace && tls.SecretName == name {
+				return true
+			}
+		}
+	}
+	for _, ingress := range storer.ListIngressesV1beta1() {
+		for _, tls := range ingress.Spec.TLS {
+			if ingress.Namespace == namespace && tls.SecretName == name {
+				return true
+			}
+		}
+	}
+	tcpIngresses, err := storer.ListTCPIngresses()
+	if err != nil {
+		return false
+	}
+	for _, ingress := range tcpIngresses {
+		for _, tls := range ingress.Spec.TLS {
+			if ingress.Namespace == namespace && tls.SecretName == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// triggerUpdate makes the next update happen right away, if an update trigger
+// has been set.
+func (c *KongClient) triggerUpdate() {
+	c.additionalFeaturesLock.RLock()
+	trigger := c.updateTrigger
+	c.additionalFeaturesLock.RUnlock()
+	if trigger != nil {
+		c.logger.Debug("TLS Secret in use changed, updating the data-plane right away")
+		trigger()
+	}
+}
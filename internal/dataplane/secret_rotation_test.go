@@ -0,0 +1,68 @@
+package dataplane
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
+	kongv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
+)
+
+func TestUpdateObjectTriggersUpdateOnTLSSecretRotation(t *testing.T) {
+	cache := store.NewCacheStores()
+	var triggered int
+	c := &KongClient{
+		logger:       logrus.New(),
+		ingressClass: "kong",
+		cache:        &cache,
+	}
+	c.SetUpdateTrigger(func() { triggered++ })
+
+	ingressClass := map[string]string{annotations.IngressClassKey: "kong"}
+	require.NoError(t, c.UpdateObject(&networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "ingress", Namespace: "default", Annotations: ingressClass},
+		Spec:       networkingv1.IngressSpec{TLS: []networkingv1.IngressTLS{{SecretName: "ingress-cert"}}},
+	}))
+	require.NoError(t, c.UpdateObject(&kongv1beta1.TCPIngress{
+		ObjectMeta: metav1.ObjectMeta{Name: "tcpingress", Namespace: "default", Annotations: ingressClass},
+		Spec:       kongv1beta1.TCPIngressSpec{TLS: []kongv1beta1.IngressTLS{{SecretName: "tcpingress-cert"}}},
+	}))
+	secret := func(namespace, name, cert string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Data:       map[string][]byte{corev1.TLSCertKey: []byte(cert)},
+		}
+	}
+
+	t.Log("verifying that TLS Secrets in use trigger an update when they're created")
+	require.NoError(t, c.UpdateObject(secret("default", "ingress-cert", "v1")))
+	require.NoError(t, c.UpdateObject(secret("default", "tcpingress-cert", "v1")))
+	assert.Equal(t, 2, triggered)
+
+	t.Log("verifying that TLS Secrets in use trigger an update when they're renewed")
+	require.NoError(t, c.UpdateObject(secret("default", "ingress-cert", "v2")))
+	require.NoError(t, c.UpdateObject(secret("default", "tcpingress-cert", "v2")))
+	assert.Equal(t, 4, triggered)
+
+	t.Log("verifying that resyncs of unchanged TLS Secrets don't trigger an update")
+	require.NoError(t, c.UpdateObject(secret("default", "ingress-cert", "v2")))
+	assert.Equal(t, 4, triggered)
+
+	t.Log("verifying that Secrets which aren't in use don't trigger an update")
+	require.NoError(t, c.UpdateObject(secret("default", "unused", "v1")))
+	require.NoError(t, c.UpdateObject(secret("other", "ingress-cert", "v1")))
+	assert.Equal(t, 4, triggered)
+
+	t.Log("verifying that the Secret of the default certificate triggers an update when it's renewed")
+	c.SetDefaultCertificate("kong/default-cert")
+	require.NoError(t, c.UpdateObject(secret("kong", "default-cert", "v1")))
+	require.NoError(t, c.UpdateObject(secret("kong", "default-cert", "v2")))
+	assert.Equal(t, 6, triggered)
+}
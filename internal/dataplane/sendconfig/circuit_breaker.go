@@ -0,0 +1,109 @@
+package sendconfig
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCircuitBreakerThreshold is the number of consecutive transient
+	// failures of an Admin API endpoint after which pushes to it are stopped.
+	DefaultCircuitBreakerThreshold = 3
+
+	// DefaultCircuitBreakerMinBackoff is how long pushes to an Admin API
+	// endpoint are stopped once the threshold is reached, doubling with each
+	// further failure.
+	DefaultCircuitBreakerMinBackoff = 5 * time.Second
+
+	// DefaultCircuitBreakerMaxBackoff bounds how long pushes to an Admin API
+	// endpoint are stopped.
+	DefaultCircuitBreakerMaxBackoff = 2 * time.Minute
+)
+
+// CircuitOpenError is returned instead of pushing a configuration to an Admin
+// API endpoint which keeps failing, until its backoff expires.
+type CircuitOpenError struct {
+	// URL is the URL of the Admin API endpoint.
+	URL string
+	// Failures is the number of consecutive failures of the endpoint.
+	Failures int
+	// RetryAt is when pushes to the endpoint are attempted again.
+	RetryAt time.Time
+}
+
+func (e CircuitOpenError) Error() string {
+	return fmt.Sprintf("not pushing configuration to Kong Admin API %s after %d consecutive failures until %s",
+		e.URL, e.Failures, e.RetryAt.Format(time.RFC3339))
+}
+
+// CircuitBreaker stops pushing configuration to an Admin API endpoint which
+// keeps failing for transient reasons (e.g. a crash-looping Kong), so that
+// updates fail fast rather than each waiting for the endpoint to time out.
+// Once the threshold of consecutive failures is reached, pushes are stopped
+// for an exponentially increasing backoff, after which a single push probes
+// the endpoint again. A successful push, or a response of the endpoint, closes
+// the circuit. A nil CircuitBreaker never stops pushes.
+type CircuitBreaker struct {
+	threshold  int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	lock     sync.Mutex
+	failures int
+	retryAt  time.Time
+	now      func() time.Time
+}
+
+// NewCircuitBreaker provides a CircuitBreaker opening after threshold
+// consecutive failures, for a backoff doubling from minBackoff up to
+// maxBackoff.
+func NewCircuitBreaker(threshold int, minBackoff, maxBackoff time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		threshold:  threshold,
+		minBackoff: minBackoff,
+		maxBackoff: maxBackoff,
+		now:        time.Now,
+	}
+}
+
+// allow returns a CircuitOpenError if pushes to the endpoint at url are
+// stopped.
+func (b *CircuitBreaker) allow(url string) error {
+	if b == nil {
+		return nil
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.now().Before(b.retryAt) {
+		return CircuitOpenError{URL: url, Failures: b.failures, RetryAt: b.retryAt}
+	}
+	return nil
+}
+
+// record records the outcome of a request to the endpoint: only transient
+// errors are failures of the endpoint, as others are responses of it.
+func (b *CircuitBreaker) record(err error) {
+	if b == nil {
+		return
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if err == nil || ClassifyError(err) != ErrorClassTransient {
+		b.failures = 0
+		b.retryAt = time.Time{}
+		return
+	}
+	b.failures++
+	if b.failures < b.threshold {
+		return
+	}
+	backoff := b.minBackoff
+	for i := b.threshold; i < b.failures && backoff < b.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > b.maxBackoff {
+		backoff = b.maxBackoff
+	}
+	b.retryAt = b.now().Add(backoff)
+}
@@ -0,0 +1,102 @@
+package sendconfig
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kong/deck/file"
+	"github.com/kong/go-kong/kong"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/deckgen"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/metrics"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+	b := NewCircuitBreaker(2, time.Second, 3*time.Second)
+	b.now = func() time.Time { return now }
+	transient := errors.New("connection refused")
+	const url = "http://kong:8001"
+
+	t.Log("verifying that the circuit only opens after the threshold of transient failures")
+	b.record(transient)
+	assert.NoError(t, b.allow(url))
+	b.record(kong.NewAPIError(http.StatusBadRequest, "invalid configuration"))
+	b.record(transient)
+	assert.NoError(t, b.allow(url), "responses of the endpoint aren't failures")
+	b.record(transient)
+	var circuitErr CircuitOpenError
+	require.ErrorAs(t, b.allow(url), &circuitErr)
+	assert.Equal(t, CircuitOpenError{URL: url, Failures: 2, RetryAt: now.Add(time.Second)}, circuitErr)
+	assert.Equal(t, ErrorClassTransient, ClassifyError(circuitErr))
+
+	t.Log("verifying that the backoff doubles with each failed probe, up to the maximum")
+	for _, backoff := range []time.Duration{2 * time.Second, 3 * time.Second, 3 * time.Second} {
+		now = circuitErr.RetryAt
+		require.NoError(t, b.allow(url))
+		b.record(transient)
+		require.ErrorAs(t, b.allow(url), &circuitErr)
+		assert.Equal(t, now.Add(backoff), circuitErr.RetryAt)
+	}
+
+	t.Log("verifying that a successful probe closes the circuit")
+	now = circuitErr.RetryAt
+	b.record(nil)
+	b.record(transient)
+	assert.NoError(t, b.allow(url))
+
+	t.Log("verifying that nil circuit breakers never open")
+	var disabled *CircuitBreaker
+	disabled.record(transient)
+	assert.NoError(t, disabled.allow(url))
+}
+
+func TestPerformUpdateCircuitBreaker(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	client, err := kong.NewClient(kong.String(server.URL), server.Client())
+	require.NoError(t, err)
+	kongConfig := &Kong{
+		URL:            server.URL,
+		Client:         client,
+		CircuitBreaker: NewCircuitBreaker(2, time.Minute, time.Minute),
+	}
+	promMetrics := metrics.NewCtrlFuncMetrics()
+	update := func(oldSHA []byte, content *file.Content) ([]byte, error) {
+		return PerformUpdate(context.Background(), logrus.New(), kongConfig, true, false, false,
+			content, nil, nil, nil, oldSHA, promMetrics)
+	}
+	content := &file.Content{FormatVersion: "1.1"}
+
+	var circuitErr CircuitOpenError
+	for i := 0; i < 2; i++ {
+		_, err := update(nil, content)
+		require.Error(t, err)
+		assert.False(t, errors.As(err, &circuitErr))
+	}
+	assert.Equal(t, 2, requests)
+
+	t.Log("verifying that changed configurations aren't pushed while the circuit is open")
+	_, err = update(nil, content)
+	assert.ErrorAs(t, err, &circuitErr)
+	assert.Equal(t, 2, requests)
+
+	t.Log("verifying that unchanged configurations are skipped while the circuit is open")
+	oldSHA, err := deckgen.GenerateSHA(content, nil, nil)
+	require.NoError(t, err)
+	sha, err := update(oldSHA, content)
+	require.NoError(t, err)
+	assert.Equal(t, oldSHA, sha)
+	assert.Equal(t, 2, requests)
+}
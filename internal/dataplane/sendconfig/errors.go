@@ -0,0 +1,144 @@
+package sendconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	deckutils "github.com/kong/deck/utils"
+	"github.com/kong/go-kong/kong"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/metrics"
+)
+
+// -----------------------------------------------------------------------------
+// Sendconfig - Errors
+// -----------------------------------------------------------------------------
+
+// EntityError describes a single Kong entity which was rejected by the
+// data-plane, along with the reasons it was rejected.
+type EntityError struct {
+	// Type is the Kong entity type (e.g. "service", "route").
+	Type string
+	// Name is the name of the Kong entity, if it has one.
+	Name string
+	// Errors lists the validation errors reported for the entity.
+	Errors []string
+}
+
+// ConfigRejectedError is returned when the data-plane rejected the whole
+// configuration because of problems with individual entities. It wraps the
+// underlying *kong.APIError.
+type ConfigRejectedError struct {
+	apiErr *kong.APIError
+
+	// EntityErrors lists the rejected entities. It's only populated for
+	// data-planes which support reporting flattened errors (Kong 3.0+).
+	EntityErrors []EntityError
+}
+
+func (e ConfigRejectedError) Error() string {
+	return e.apiErr.Error()
+}
+
+func (e ConfigRejectedError) Unwrap() error {
+	return e.apiErr
+}
+
+// flattenedErrorsResponse is the body of an error response from the DB-less
+// /config endpoint when the flatten_errors query parameter is set.
+type flattenedErrorsResponse struct {
+	Message         string `json:"message"`
+	FlattenedErrors []struct {
+		EntityName string `json:"entity_name"`
+		EntityType string `json:"entity_type"`
+		Errors     []struct {
+			Field   string `json:"field"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	} `json:"flattened_errors"`
+}
+
+// parseConfigError converts an error response from the /config endpoint into
+// an error. If the response contains flattened errors a ConfigRejectedError
+// is returned, otherwise a plain *kong.APIError.
+func parseConfigError(statusCode int, body []byte) error {
+	var resp flattenedErrorsResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return kong.NewAPIError(statusCode, fmt.Sprintf("<failed to parse response body: %v>", err))
+	}
+	apiErr := kong.NewAPIError(statusCode, resp.Message)
+	if len(resp.FlattenedErrors) == 0 {
+		return apiErr
+	}
+
+	entityErrors := make([]EntityError, 0, len(resp.FlattenedErrors))
+	for _, flattened := range resp.FlattenedErrors {
+		entityErr := EntityError{
+			Type: flattened.EntityType,
+			Name: flattened.EntityName,
+		}
+		for _, e := range flattened.Errors {
+			if e.Field != "" {
+				entityErr.Errors = append(entityErr.Errors, fmt.Sprintf("%s: %s", e.Field, e.Message))
+			} else {
+				entityErr.Errors = append(entityErr.Errors, e.Message)
+			}
+		}
+		entityErrors = append(entityErrors, entityErr)
+	}
+	return ConfigRejectedError{apiErr: apiErr, EntityErrors: entityErrors}
+}
+
+// ErrorClass describes why a configuration couldn't be applied, which
+// determines how soon applying it again can succeed.
+type ErrorClass string
+
+const (
+	// ErrorClassValidation indicates that the data-plane rejected the
+	// configuration: applying it again fails until it changes.
+	ErrorClassValidation ErrorClass = ErrorClass(metrics.ErrorClassValidation)
+	// ErrorClassAuth indicates that the data-plane refused the credentials of
+	// the controller: applying the configuration fails until they're fixed.
+	ErrorClassAuth ErrorClass = ErrorClass(metrics.ErrorClassAuth)
+	// ErrorClassTransient indicates that the data-plane couldn't be reached or
+	// failed to process the configuration, which is likely to succeed again
+	// shortly.
+	ErrorClassTransient ErrorClass = ErrorClass(metrics.ErrorClassTransient)
+)
+
+// ClassifyError determines the class of an error returned when applying a
+// configuration. Errors which aren't responses of the data-plane (e.g. network
+// errors) are transient. For the errors of individual entities of a DB-backed
+// data-plane, the class is that of the most persistent one.
+func ClassifyError(err error) ErrorClass {
+	var errArray deckutils.ErrArray
+	if errors.As(err, &errArray) && len(errArray.Errors) > 0 {
+		class := ErrorClassValidation
+		for _, entityErr := range errArray.Errors {
+			switch ClassifyError(entityErr) {
+			case ErrorClassAuth:
+				return ErrorClassAuth
+			case ErrorClassTransient:
+				class = ErrorClassTransient
+			}
+		}
+		return class
+	}
+
+	var apiErr *kong.APIError
+	if !errors.As(err, &apiErr) {
+		return ErrorClassTransient
+	}
+	switch code := apiErr.Code(); {
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		return ErrorClassAuth
+	case code == http.StatusTooManyRequests || code >= http.StatusInternalServerError:
+		return ErrorClassTransient
+	case code >= http.StatusBadRequest:
+		return ErrorClassValidation
+	default:
+		return ErrorClassTransient
+	}
+}
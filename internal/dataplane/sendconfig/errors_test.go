@@ -0,0 +1,132 @@
+package sendconfig
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	deckutils "github.com/kong/deck/utils"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConfigError(t *testing.T) {
+	t.Log("verifying that errors with flattened entity errors are parsed")
+	body := []byte(`{
+		"code": 14,
+		"name": "invalid declarative configuration",
+		"message": "declarative config is invalid: {}",
+		"flattened_errors": [
+			{
+				"entity_name": "default.ingress.foo.00",
+				"entity_type": "route",
+				"errors": [
+					{"type": "field", "field": "methods", "message": "invalid value: get"},
+					{"type": "entity", "message": "must set one of 'methods', 'hosts', 'headers', 'paths'"}
+				]
+			}
+		]
+	}`)
+	err := parseConfigError(http.StatusBadRequest, body)
+	var rejectedErr ConfigRejectedError
+	require.True(t, errors.As(err, &rejectedErr))
+	assert.Equal(t, []EntityError{
+		{
+			Type: "route",
+			Name: "default.ingress.foo.00",
+			Errors: []string{
+				"methods: invalid value: get",
+				"must set one of 'methods', 'hosts', 'headers', 'paths'",
+			},
+		},
+	}, rejectedErr.EntityErrors)
+
+	var apiErr *kong.APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusBadRequest, apiErr.Code())
+
+	t.Log("verifying that errors without flattened entity errors are plain API errors")
+	err = parseConfigError(http.StatusBadRequest, []byte(`{"message": "declarative config is invalid"}`))
+	assert.False(t, errors.As(err, &rejectedErr))
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, `HTTP status 400 (message: "declarative config is invalid")`, apiErr.Error())
+
+	t.Log("verifying that unparseable error responses are still reported")
+	err = parseConfigError(http.StatusInternalServerError, []byte("oops"))
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusInternalServerError, apiErr.Code())
+}
+
+func TestClassifyError(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		err  error
+		want ErrorClass
+	}{
+		{
+			name: "rejected configuration",
+			err:  fmt.Errorf("posting new config to /config: %w", ConfigRejectedError{apiErr: kong.NewAPIError(http.StatusBadRequest, "invalid")}),
+			want: ErrorClassValidation,
+		},
+		{
+			name: "invalid entity",
+			err:  kong.NewAPIError(http.StatusBadRequest, "schema violation"),
+			want: ErrorClassValidation,
+		},
+		{
+			name: "unauthorized",
+			err:  fmt.Errorf("loading configuration from kong: %w", kong.NewAPIError(http.StatusUnauthorized, "invalid credentials")),
+			want: ErrorClassAuth,
+		},
+		{
+			name: "forbidden",
+			err:  kong.NewAPIError(http.StatusForbidden, "no RBAC permission"),
+			want: ErrorClassAuth,
+		},
+		{
+			name: "server error",
+			err:  kong.NewAPIError(http.StatusServiceUnavailable, "unavailable"),
+			want: ErrorClassTransient,
+		},
+		{
+			name: "rate limited",
+			err:  kong.NewAPIError(http.StatusTooManyRequests, "slow down"),
+			want: ErrorClassTransient,
+		},
+		{
+			name: "network error",
+			err:  errors.New("dial tcp 10.0.0.1:8444: connect: connection refused"),
+			want: ErrorClassTransient,
+		},
+		{
+			name: "invalid entities",
+			err: deckutils.ErrArray{Errors: []error{
+				kong.NewAPIError(http.StatusBadRequest, "schema violation"),
+				kong.NewAPIError(http.StatusConflict, "unique constraint violation"),
+			}},
+			want: ErrorClassValidation,
+		},
+		{
+			name: "invalid and unreachable entities",
+			err: deckutils.ErrArray{Errors: []error{
+				kong.NewAPIError(http.StatusBadRequest, "schema violation"),
+				errors.New("connection reset by peer"),
+			}},
+			want: ErrorClassTransient,
+		},
+		{
+			name: "forbidden entities",
+			err: deckutils.ErrArray{Errors: []error{
+				errors.New("connection reset by peer"),
+				fmt.Errorf("create route foo failed: %w", kong.NewAPIError(http.StatusForbidden, "no RBAC permission")),
+			}},
+			want: ErrorClassAuth,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ClassifyError(tt.err))
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package sendconfig
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kong/deck/file"
+	"github.com/kong/go-kong/kong"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+)
+
+// CollectOrphanedEntities deletes, from the Kong database, the routes,
+// services, upstreams and consumers which the sync scoped to the filter tags
+// of kongConfig leaves behind: those carrying all the previousFilterTags the
+// controller used to manage its entities with, but not all its current filter
+// tags. They are deleted if they aren't part of targetContent, the
+// configuration which was just applied, and were translated from a Kubernetes
+// object which isn't cached anymore: their k8s-uid tag identifies an object
+// whose UID isCached reports as unknown. It returns the number of deleted
+// entities.
+//
+// Entities without a k8s-uid tag are never deleted, as they may have been
+// created by other means, and neither are those carrying all the current
+// filter tags, which the sync already deletes. Failures to delete single
+// entities (e.g. a service still referenced by a route which isn't managed by
+// the controller) are logged rather than returned. Nothing is deleted when no
+// previous or current filter tags are configured: without current filter
+// tags, the sync deletes any entity which isn't configured.
+func CollectOrphanedEntities(
+	ctx context.Context,
+	log logrus.FieldLogger,
+	kongConfig *Kong,
+	previousFilterTags []string,
+	targetContent *file.Content,
+	isCached func(types.UID) bool,
+) (int, error) {
+	if len(previousFilterTags) == 0 || len(kongConfig.FilterTags) == 0 {
+		return 0, nil
+	}
+
+	target := map[string]map[string]bool{
+		"route":    {},
+		"service":  {},
+		"upstream": {},
+		"consumer": {},
+	}
+	for _, s := range targetContent.Services {
+		addEntityName(target["service"], s.Name)
+		for _, r := range s.Routes {
+			addEntityName(target["route"], r.Name)
+		}
+	}
+	for _, r := range targetContent.Routes {
+		addEntityName(target["route"], r.Name)
+	}
+	for _, u := range targetContent.Upstreams {
+		addEntityName(target["upstream"], u.Name)
+	}
+	for _, c := range targetContent.Consumers {
+		addEntityName(target["consumer"], c.Username)
+	}
+	isOrphaned := func(kind string, name *string, tags []*string) bool {
+		if name != nil && target[kind][*name] {
+			return false
+		}
+		if hasAllTags(tags, kongConfig.FilterTags) {
+			return false
+		}
+		uid, ok := sourceUID(tags)
+		return ok && !isCached(uid)
+	}
+
+	type orphan struct {
+		kind, name string
+		delete     func(context.Context, *string) error
+	}
+	var orphans []orphan
+	client := kongConfig.Client
+	opt := &kong.ListOpt{Size: 1000, Tags: kong.StringSlice(previousFilterTags...), MatchAllTags: true}
+
+	// routes go first, so that the services they reference can be deleted
+	routes, err := listAll(ctx, opt, client.Routes.List)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list routes: %w", err)
+	}
+	for _, r := range routes {
+		if isOrphaned("route", r.Name, r.Tags) {
+			orphans = append(orphans, orphan{"route", entityName(r.Name, r.ID), client.Routes.Delete})
+		}
+	}
+	services, err := listAll(ctx, opt, client.Services.List)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list services: %w", err)
+	}
+	for _, s := range services {
+		if isOrphaned("service", s.Name, s.Tags) {
+			orphans = append(orphans, orphan{"service", entityName(s.Name, s.ID), client.Services.Delete})
+		}
+	}
+	upstreams, err := listAll(ctx, opt, client.Upstreams.List)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list upstreams: %w", err)
+	}
+	for _, u := range upstreams {
+		if isOrphaned("upstream", u.Name, u.Tags) {
+			orphans = append(orphans, orphan{"upstream", entityName(u.Name, u.ID), client.Upstreams.Delete})
+		}
+	}
+	consumers, err := listAll(ctx, opt, client.Consumers.List)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list consumers: %w", err)
+	}
+	for _, c := range consumers {
+		if isOrphaned("consumer", c.Username, c.Tags) {
+			orphans = append(orphans, orphan{"consumer", entityName(c.Username, c.ID), client.Consumers.Delete})
+		}
+	}
+
+	var deleted int
+	for _, o := range orphans {
+		// the entities are deleted by ID, names may be missing
+		if err := o.delete(ctx, kong.String(o.name)); err != nil && !kong.IsNotFoundErr(err) {
+			log.WithError(err).Errorf("failed to delete orphaned %s %s", o.kind, o.name)
+			continue
+		}
+		log.Debugf("deleted orphaned %s %s", o.kind, o.name)
+		deleted++
+	}
+	return deleted, nil
+}
+
+// listAll lists all the pages of entities of list matching opt.
+func listAll[T any](
+	ctx context.Context,
+	opt *kong.ListOpt,
+	list func(context.Context, *kong.ListOpt) ([]T, *kong.ListOpt, error),
+) ([]T, error) {
+	var all []T
+	for opt := *opt; ; {
+		items, next, err := list(ctx, &opt)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+		if next == nil {
+			return all, nil
+		}
+		opt = *next
+	}
+}
+
+// sourceUID returns the UID of the Kubernetes object which an entity with the
+// given tags was translated from, if it's tagged with it.
+func sourceUID(tags []*string) (types.UID, bool) {
+	for _, tag := range tags {
+		if tag != nil && strings.HasPrefix(*tag, util.K8sUIDTagPrefix) {
+			return types.UID(strings.TrimPrefix(*tag, util.K8sUIDTagPrefix)), true
+		}
+	}
+	return "", false
+}
+
+// hasAllTags determines whether tags include all the wanted tags.
+func hasAllTags(tags []*string, wanted []string) bool {
+	have := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		if tag != nil {
+			have[*tag] = true
+		}
+	}
+	for _, tag := range wanted {
+		if !have[tag] {
+			return false
+		}
+	}
+	return true
+}
+
+func addEntityName(names map[string]bool, name *string) {
+	if name != nil {
+		names[*name] = true
+	}
+}
+
+// entityName identifies an entity in the Admin API, preferring its ID.
+func entityName(name, id *string) string {
+	if id != nil {
+		return *id
+	}
+	return *name
+}
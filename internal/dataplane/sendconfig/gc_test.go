@@ -0,0 +1,109 @@
+package sendconfig
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/kong/deck/file"
+	"github.com/kong/go-kong/kong"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestCollectOrphanedEntities(t *testing.T) {
+	// the controller managed its entities with the team-a tag, it now manages
+	// them with the team-b one: the sync scoped to team-b never deletes the
+	// entities carrying team-a only
+	const managed, teamA, teamB = "managed-by-ingress-controller", "team-a", "team-b"
+	entities := map[string][]map[string]interface{}{
+		"routes": {
+			{"id": "route-current", "name": "default.echo.00", "tags": []string{managed, teamA, "k8s-uid:gone"}},
+			{"id": "route-cached", "name": "default.old.00", "tags": []string{managed, teamA, "k8s-uid:ingress"}},
+			{"id": "route-gone", "name": "default.gone.00", "tags": []string{managed, teamA, "k8s-uid:gone"}},
+			{"id": "route-synced", "name": "default.synced.00", "tags": []string{managed, teamA, teamB, "k8s-uid:gone"}},
+			{"id": "route-other", "name": "default.other.00", "tags": []string{managed, "k8s-uid:gone"}},
+		},
+		"services": {
+			{"id": "service-current", "name": "default.echo.80", "tags": []string{managed, teamA}},
+			{"id": "service-untagged", "name": "default.old.80", "tags": []string{managed, teamA}},
+		},
+		"upstreams": {
+			{"id": "upstream-cached", "name": "old.default.80.svc", "tags": []string{managed, teamA, "k8s-uid:service"}},
+		},
+		"consumers": {
+			{"id": "consumer-current", "username": "alice", "tags": []string{managed, teamA}},
+			{"id": "consumer-gone", "username": "bob", "tags": []string{managed, teamA, "k8s-uid:gone"}},
+			{"id": "consumer-team-b", "username": "carol", "tags": []string{managed, teamB, "k8s-uid:gone"}},
+		},
+	}
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		collection := strings.Split(strings.Trim(r.URL.Path, "/"), "/")[0]
+		switch r.Method {
+		case http.MethodGet:
+			// only the entities carrying all the previous filter tags are listed
+			filter := r.URL.Query().Get("tags")
+			assert.Equal(t, managed+","+teamA, filter)
+			var listed []map[string]interface{}
+			for _, entity := range entities[collection] {
+				tags := strings.Join(entity["tags"].([]string), ",")
+				if strings.HasPrefix(tags, managed+","+teamA) {
+					listed = append(listed, entity)
+				}
+			}
+			require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{"data": listed}))
+		case http.MethodDelete:
+			deleted = append(deleted, strings.TrimPrefix(r.URL.Path, "/"))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer server.Close()
+	client, err := kong.NewClient(kong.String(server.URL), server.Client())
+	require.NoError(t, err)
+	kongConfig := &Kong{
+		URL:        server.URL,
+		Client:     client,
+		FilterTags: []string{managed, teamB},
+	}
+	target := &file.Content{
+		Services: []file.FService{{
+			Service: kong.Service{Name: kong.String("default.echo.80")},
+			Routes:  []*file.FRoute{{Route: kong.Route{Name: kong.String("default.echo.00")}}},
+		}},
+		Consumers: []file.FConsumer{{Consumer: kong.Consumer{Username: kong.String("alice")}}},
+	}
+	cached := map[types.UID]bool{"ingress": true, "service": true}
+	isCached := func(uid types.UID) bool { return cached[uid] }
+
+	previousFilterTags := []string{managed, teamA}
+
+	t.Log("verifying that only the entities the sync leaves behind which aren't configured and were translated from objects which aren't cached are deleted")
+	n, err := CollectOrphanedEntities(context.Background(), logrus.New(), kongConfig, previousFilterTags, target, isCached)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	sort.Strings(deleted)
+	assert.Equal(t, []string{"consumers/consumer-gone", "routes/route-gone"}, deleted)
+
+	t.Log("verifying that nothing is deleted without previous filter tags")
+	deleted = nil
+	n, err = CollectOrphanedEntities(context.Background(), logrus.New(), kongConfig, nil, target, isCached)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	assert.Empty(t, deleted)
+
+	t.Log("verifying that nothing is deleted without filter tags, as the sync deletes any entity which isn't configured")
+	kongConfig.FilterTags = nil
+	n, err = CollectOrphanedEntities(context.Background(), logrus.New(), kongConfig, previousFilterTags, target, isCached)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	assert.Empty(t, deleted)
+}
@@ -29,4 +29,8 @@ type Kong struct {
 	Version semver.Version
 
 	Concurrency int
+
+	// CircuitBreaker, if set, stops pushing configuration to the Admin API
+	// while it keeps failing.
+	CircuitBreaker *CircuitBreaker
 }
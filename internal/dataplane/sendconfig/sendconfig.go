@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"reflect"
 	"sync"
@@ -16,11 +17,13 @@ import (
 	"github.com/kong/deck/file"
 	"github.com/kong/deck/state"
 	deckutils "github.com/kong/deck/utils"
+	"github.com/kong/go-kong/kong"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/deckgen"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/metrics"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/tracing"
 )
 
 const initialHash = "00000000000000000000000000000000"
@@ -29,7 +32,8 @@ const initialHash = "00000000000000000000000000000000"
 // Sendconfig - Public Functions
 // -----------------------------------------------------------------------------
 
-// PerformUpdate writes `targetContent` and `customEntities` to Kong Admin API specified by `kongConfig`.
+// PerformUpdate writes `targetContent`, `customEntities` and `pluginOrderings` to Kong Admin API specified by
+// `kongConfig`.
 func PerformUpdate(ctx context.Context,
 	log logrus.FieldLogger,
 	kongConfig *Kong,
@@ -39,12 +43,23 @@ func PerformUpdate(ctx context.Context,
 	targetContent *file.Content,
 	selectorTags []string,
 	customEntities []byte,
+	pluginOrderings deckgen.PluginOrderings,
 	oldSHA []byte,
-	promMetrics *metrics.CtrlFuncMetrics) ([]byte, error) {
-	newSHA, err := deckgen.GenerateSHA(targetContent, customEntities)
+	promMetrics *metrics.CtrlFuncMetrics) (_ []byte, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "sendconfig.PerformUpdate")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	metricsProtocol := metrics.ProtocolDeck
+	if inMemory {
+		metricsProtocol = metrics.ProtocolDBLess
+	}
+
+	newSHA, err := deckgen.GenerateSHA(targetContent, customEntities, pluginOrderings)
 	if err != nil {
 		return oldSHA, err
 	}
+	// pushes to an Admin API which keeps failing are stopped for a while
+	circuitErr := kongConfig.CircuitBreaker.allow(kongConfig.URL)
 	// disable optimization if reverse sync is enabled
 	if !reverseSync {
 		// use the previous SHA to determine whether or not to perform an update
@@ -55,32 +70,53 @@ func PerformUpdate(ctx context.Context,
 			// we assume ready as not all Kong versions provide their configuration hash, and their readiness state
 			// is always unknown
 			ready := true
+			if circuitErr != nil {
+				log.WithError(circuitErr).Debug("configuration state unknown, skipping sync to kong")
+				promMetrics.ConfigPushSkippedCount.With(prometheus.Labels{
+					metrics.ProtocolKey:   metricsProtocol,
+					metrics.SkipReasonKey: metrics.SkipReasonCircuitOpen,
+				}).Inc()
+				return oldSHA, nil
+			}
 			status, err := kongConfig.Client.Status(ctx)
+			kongConfig.CircuitBreaker.record(err)
 			if err != nil {
 				log.WithError(err).Error("checking config status failed")
 				log.Debug("configuration state unknown, skipping sync to kong")
+				promMetrics.ConfigPushSkippedCount.With(prometheus.Labels{
+					metrics.ProtocolKey:   metricsProtocol,
+					metrics.SkipReasonKey: metrics.SkipReasonStatusUnknown,
+				}).Inc()
 				return oldSHA, nil
 			}
 			if status.ConfigurationHash == initialHash {
 				ready = false
 			}
 			if ready {
+				span.SetAttributes(tracing.ChangedKey.Bool(false))
 				log.Debug("no configuration change, skipping sync to kong")
+				promMetrics.ConfigPushSkippedCount.With(prometheus.Labels{
+					metrics.ProtocolKey:   metricsProtocol,
+					metrics.SkipReasonKey: metrics.SkipReasonUnchanged,
+				}).Inc()
 				return oldSHA, nil
 			}
 		}
 	}
 
-	var metricsProtocol string
+	if circuitErr != nil {
+		return nil, circuitErr
+	}
+
 	timeStart := time.Now()
 	if inMemory {
-		metricsProtocol = metrics.ProtocolDBLess
-		err = onUpdateInMemoryMode(ctx, log, targetContent, customEntities, kongConfig)
+		err = onUpdateInMemoryMode(ctx, log, targetContent, customEntities, pluginOrderings, kongConfig)
 	} else {
-		metricsProtocol = metrics.ProtocolDeck
 		err = onUpdateDBMode(ctx, targetContent, kongConfig, selectorTags, skipCACertificates)
 	}
 	timeEnd := time.Now()
+	span.SetAttributes(tracing.ProtocolKey.String(metricsProtocol), tracing.ChangedKey.Bool(true))
+	kongConfig.CircuitBreaker.record(err)
 
 	if err != nil {
 		promMetrics.ConfigPushCount.With(prometheus.Labels{
@@ -91,6 +127,10 @@ func PerformUpdate(ctx context.Context,
 			metrics.SuccessKey:  metrics.SuccessFalse,
 			metrics.ProtocolKey: metricsProtocol,
 		}).Observe(float64(timeEnd.Sub(timeStart).Milliseconds()))
+		promMetrics.ConfigPushErrorCount.With(prometheus.Labels{
+			metrics.ErrorClassKey: string(ClassifyError(err)),
+			metrics.ProtocolKey:   metricsProtocol,
+		}).Inc()
 		return nil, err
 	}
 
@@ -111,7 +151,7 @@ func PerformUpdate(ctx context.Context,
 // -----------------------------------------------------------------------------
 
 func renderConfigWithCustomEntities(log logrus.FieldLogger, state *file.Content,
-	customEntitiesJSONBytes []byte) ([]byte, error) {
+	customEntitiesJSONBytes []byte, pluginOrderings deckgen.PluginOrderings) ([]byte, error) {
 
 	var kongCoreConfig []byte
 	var err error
@@ -122,7 +162,7 @@ func renderConfigWithCustomEntities(log logrus.FieldLogger, state *file.Content,
 	}
 
 	// fast path
-	if len(customEntitiesJSONBytes) == 0 {
+	if len(customEntitiesJSONBytes) == 0 && len(pluginOrderings) == 0 {
 		return kongCoreConfig, nil
 	}
 
@@ -138,18 +178,23 @@ func renderConfigWithCustomEntities(log logrus.FieldLogger, state *file.Content,
 	}
 
 	// unmarshal custom entities config into the merge map
-	err = json.Unmarshal(customEntitiesJSONBytes, &customEntities)
-	if err != nil {
-		// do not error out when custom entities are messed up
-		log.WithError(err).Error("failed to unmarshal custom entities from secret data")
-	} else {
-		for k, v := range customEntities {
-			if _, exists := mergeMap[k]; !exists {
-				mergeMap[k] = v
+	if len(customEntitiesJSONBytes) > 0 {
+		err = json.Unmarshal(customEntitiesJSONBytes, &customEntities)
+		if err != nil {
+			// do not error out when custom entities are messed up
+			log.WithError(err).Error("failed to unmarshal custom entities from secret data")
+		} else {
+			for k, v := range customEntities {
+				if _, exists := mergeMap[k]; !exists {
+					mergeMap[k] = v
+				}
 			}
 		}
 	}
 
+	// set the ordering of plugins, which decK doesn't support
+	pluginOrderings.Apply(mergeMap)
+
 	// construct the final configuration
 	result, err = json.Marshal(mergeMap)
 	if err != nil {
@@ -164,18 +209,54 @@ func onUpdateInMemoryMode(ctx context.Context,
 	log logrus.FieldLogger,
 	state *file.Content,
 	customEntities []byte,
+	pluginOrderings deckgen.PluginOrderings,
 	kongConfig *Kong,
 ) error {
+	config, err := RenderInMemoryConfig(log, state, customEntities, pluginOrderings)
+	if err != nil {
+		return err
+	}
+	return postInMemoryConfig(ctx, kongConfig, config)
+}
+
+// RenderInMemoryConfig renders the configuration posted to the /config
+// endpoint of a DB-less Kong.
+func RenderInMemoryConfig(log logrus.FieldLogger,
+	state *file.Content,
+	customEntities []byte,
+	pluginOrderings deckgen.PluginOrderings,
+) ([]byte, error) {
 	// Kong will error out if this is set
 	state.Info = nil
 	// Kong errors out if `null`s are present in `config` of plugins
 	deckgen.CleanUpNullsInPluginConfigs(state)
 
-	config, err := renderConfigWithCustomEntities(log, state, customEntities)
+	config, err := renderConfigWithCustomEntities(log, state, customEntities, pluginOrderings)
 	if err != nil {
-		return fmt.Errorf("constructing kong configuration: %w", err)
+		return nil, fmt.Errorf("constructing kong configuration: %w", err)
 	}
+	return config, nil
+}
 
+// SeedInMemoryConfig posts a rendered configuration to a DB-less Kong which
+// still serves the empty configuration it starts with, reporting whether it
+// did. Kong versions which don't report their configuration hash are assumed
+// to be configured already.
+func SeedInMemoryConfig(ctx context.Context, kongConfig *Kong, config []byte) (bool, error) {
+	status, err := kongConfig.Client.Status(ctx)
+	if err != nil {
+		return false, fmt.Errorf("checking config status: %w", err)
+	}
+	if status.ConfigurationHash != initialHash {
+		return false, nil
+	}
+	if err := postInMemoryConfig(ctx, kongConfig, config); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func postInMemoryConfig(ctx context.Context, kongConfig *Kong, config []byte) error {
 	req, err := http.NewRequest("POST", kongConfig.URL+"/config",
 		bytes.NewReader(config))
 	if err != nil {
@@ -185,15 +266,26 @@ func onUpdateInMemoryMode(ctx context.Context,
 
 	queryString := req.URL.Query()
 	queryString.Add("check_hash", "1")
+	// ask Kong to report exactly which entities were rejected, if supported.
+	queryString.Add("flatten_errors", "1")
 
 	req.URL.RawQuery = queryString.Encode()
 
-	_, err = kongConfig.Client.Do(ctx, req, nil)
+	resp, err := kongConfig.Client.DoRAW(ctx, req)
 	if err != nil {
 		return fmt.Errorf("posting new config to /config: %w", err)
 	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("reading /config error response: %w", err)
+		}
+		return fmt.Errorf("posting new config to /config: %w", parseConfigError(resp.StatusCode, body))
+	}
 
-	return err
+	return nil
 }
 
 func onUpdateDBMode(ctx context.Context,
@@ -204,28 +296,47 @@ func onUpdateDBMode(ctx context.Context,
 ) error {
 	dumpConfig := dump.Config{SelectorTags: selectorTags, SkipCACerts: skipCACertificates}
 	// read the current state
-	rawState, err := dump.Get(ctx, kongConfig.Client, dumpConfig)
+	currentRawState, err := dump.Get(ctx, kongConfig.Client, dumpConfig)
 	if err != nil {
 		return fmt.Errorf("loading configuration from kong: %w", err)
 	}
-	currentState, err := state.Get(rawState)
+	currentState, err := state.Get(currentRawState)
 	if err != nil {
 		return err
 	}
 
 	// read the target state
-	rawState, err = file.Get(ctx, targetContent, file.RenderConfig{
+	targetRawState, err := file.Get(ctx, targetContent, file.RenderConfig{
 		CurrentState: currentState,
 		KongVersion:  kongConfig.Version,
 	}, dumpConfig, kongConfig.Client)
 	if err != nil {
 		return err
 	}
-	targetState, err := state.Get(rawState)
+	targetState, err := state.Get(targetRawState)
+	if err != nil {
+		return err
+	}
+
+	// create and update the certificates, consumers and credentials before anything else, so
+	// that they are in place before the routes and plugins which require them are changed,
+	// and that none of these are changed if they can't be applied. Nothing is deleted in this
+	// pass: deletions happen in the full sync below, which removes routes and plugins before
+	// the entities they reference.
+	priorityState, err := state.Get(withPriorityEntities(currentRawState, targetRawState))
 	if err != nil {
 		return err
 	}
+	if err := solve(ctx, kongConfig, currentState, priorityState); err != nil {
+		return err
+	}
+
+	// the syncer keeps currentState up to date with the changes it applied
+	return solve(ctx, kongConfig, currentState, targetState)
+}
 
+// solve applies the changes needed to get Kong from currentState to targetState.
+func solve(ctx context.Context, kongConfig *Kong, currentState, targetState *state.KongState) error {
 	syncer, err := diff.NewSyncer(diff.SyncerOpts{
 		CurrentState:    currentState,
 		TargetState:     targetState,
@@ -242,6 +353,57 @@ func onUpdateDBMode(ctx context.Context,
 	return nil
 }
 
+// withPriorityEntities provides the current state with the certificates, CA certificates, SNIs,
+// consumers and credentials of the target state added to it, replacing the current ones with
+// the same IDs. Current entities missing from the target state are kept.
+func withPriorityEntities(current, target *deckutils.KongRawState) *deckutils.KongRawState {
+	merged := *current
+	merged.Certificates = mergeByID(current.Certificates, target.Certificates,
+		func(e *kong.Certificate) *string { return e.ID })
+	merged.CACertificates = mergeByID(current.CACertificates, target.CACertificates,
+		func(e *kong.CACertificate) *string { return e.ID })
+	merged.SNIs = mergeByID(current.SNIs, target.SNIs,
+		func(e *kong.SNI) *string { return e.ID })
+	merged.Consumers = mergeByID(current.Consumers, target.Consumers,
+		func(e *kong.Consumer) *string { return e.ID })
+	merged.KeyAuths = mergeByID(current.KeyAuths, target.KeyAuths,
+		func(e *kong.KeyAuth) *string { return e.ID })
+	merged.HMACAuths = mergeByID(current.HMACAuths, target.HMACAuths,
+		func(e *kong.HMACAuth) *string { return e.ID })
+	merged.JWTAuths = mergeByID(current.JWTAuths, target.JWTAuths,
+		func(e *kong.JWTAuth) *string { return e.ID })
+	merged.BasicAuths = mergeByID(current.BasicAuths, target.BasicAuths,
+		func(e *kong.BasicAuth) *string { return e.ID })
+	merged.ACLGroups = mergeByID(current.ACLGroups, target.ACLGroups,
+		func(e *kong.ACLGroup) *string { return e.ID })
+	merged.Oauth2Creds = mergeByID(current.Oauth2Creds, target.Oauth2Creds,
+		func(e *kong.Oauth2Credential) *string { return e.ID })
+	merged.MTLSAuths = mergeByID(current.MTLSAuths, target.MTLSAuths,
+		func(e *kong.MTLSAuth) *string { return e.ID })
+	return &merged
+}
+
+// mergeByID provides the target entities followed by the current ones whose IDs aren't in target.
+func mergeByID[T any](current, target []T, id func(T) *string) []T {
+	merged := make([]T, 0, len(current)+len(target))
+	ids := make(map[string]struct{}, len(target))
+	for _, e := range target {
+		if id(e) != nil {
+			ids[*id(e)] = struct{}{}
+		}
+		merged = append(merged, e)
+	}
+	for _, e := range current {
+		if id(e) != nil {
+			if _, ok := ids[*id(e)]; ok {
+				continue
+			}
+		}
+		merged = append(merged, e)
+	}
+	return merged
+}
+
 func equalSHA(a, b []byte) bool {
 	return reflect.DeepEqual(a, b)
 }
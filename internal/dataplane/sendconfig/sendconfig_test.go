@@ -5,15 +5,23 @@ import (
 	"testing"
 
 	"github.com/kong/deck/file"
+	"github.com/kong/deck/state"
+	deckutils "github.com/kong/deck/utils"
 	"github.com/kong/go-kong/kong"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/deckgen"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+	configurationv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
 )
 
 func Test_renderConfigWithCustomEntities(t *testing.T) {
 	type args struct {
 		state                   *file.Content
 		customEntitiesJSONBytes []byte
+		pluginOrderings         deckgen.PluginOrderings
 	}
 	tests := []struct {
 		name    string
@@ -102,10 +110,48 @@ func Test_renderConfigWithCustomEntities(t *testing.T) {
 				`,"services":[{"host":"example.com","name":"foo"}]}`),
 			wantErr: false,
 		},
+		{
+			name: "plugin orderings are set",
+			args: args{
+				state: &file.Content{
+					FormatVersion: "1.1",
+					Plugins: []file.FPlugin{
+						{
+							Plugin: kong.Plugin{
+								Name:    kong.String("rate-limiting"),
+								Service: &kong.Service{ID: kong.String("foo")},
+							},
+						},
+						{
+							Plugin: kong.Plugin{
+								Name: kong.String("rate-limiting"),
+							},
+						},
+					},
+				},
+				pluginOrderings: deckgen.ToPluginOrderings(&kongstate.KongState{
+					Plugins: []kongstate.Plugin{
+						{
+							Plugin: kong.Plugin{
+								Name:    kong.String("rate-limiting"),
+								Service: &kong.Service{ID: kong.String("foo")},
+							},
+							Ordering: &configurationv1.PluginOrdering{
+								Before: configurationv1.PluginOrderingPhase{"access": {"key-auth"}},
+							},
+						},
+					},
+				}),
+			},
+			want: []byte(`{"_format_version":"1.1","plugins":[` +
+				`{"name":"rate-limiting","ordering":{"before":{"access":["key-auth"]}},"service":"foo"},` +
+				`{"name":"rate-limiting"}]}`),
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := renderConfigWithCustomEntities(logrus.New(), tt.args.state, tt.args.customEntitiesJSONBytes)
+			got, err := renderConfigWithCustomEntities(logrus.New(), tt.args.state, tt.args.customEntitiesJSONBytes, tt.args.pluginOrderings)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("renderConfigWithCustomEntities() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -128,3 +174,39 @@ func Test_updateReportingUtilities(t *testing.T) {
 	assert.True(t, hasSHAUpdateAlreadyBeenReported([]byte("yet-another-fake-sha")))
 	assert.True(t, hasSHAUpdateAlreadyBeenReported([]byte("yet-another-fake-sha")))
 }
+
+func Test_withPriorityEntities(t *testing.T) {
+	current := &deckutils.KongRawState{
+		Services: []*kong.Service{{ID: kong.String("svc"), Name: kong.String("svc"), Host: kong.String("example.com")}},
+		Routes: []*kong.Route{{
+			ID: kong.String("route"), Name: kong.String("route"), Paths: kong.StringSlice("/"),
+			Service: &kong.Service{ID: kong.String("svc")},
+		}},
+		Consumers: []*kong.Consumer{
+			{ID: kong.String("kept"), Username: kong.String("kept")},
+			{ID: kong.String("updated"), Username: kong.String("old")},
+		},
+		KeyAuths: []*kong.KeyAuth{{ID: kong.String("key"), Key: kong.String("old"), Consumer: &kong.Consumer{ID: kong.String("kept")}}},
+	}
+	target := &deckutils.KongRawState{
+		Consumers: []*kong.Consumer{
+			{ID: kong.String("updated"), Username: kong.String("new")},
+			{ID: kong.String("created"), Username: kong.String("created")},
+		},
+		KeyAuths: []*kong.KeyAuth{{ID: kong.String("key"), Key: kong.String("new"), Consumer: &kong.Consumer{ID: kong.String("created")}}},
+	}
+
+	merged := withPriorityEntities(current, target)
+	assert.Equal(t, current.Services, merged.Services, "services are left as they are")
+	assert.Equal(t, current.Routes, merged.Routes, "routes are left as they are")
+	assert.Equal(t, []*kong.Consumer{
+		{ID: kong.String("updated"), Username: kong.String("new")},
+		{ID: kong.String("created"), Username: kong.String("created")},
+		{ID: kong.String("kept"), Username: kong.String("kept")},
+	}, merged.Consumers)
+	assert.Equal(t, target.KeyAuths, merged.KeyAuths)
+	assert.Len(t, current.Consumers, 2, "the current state is not modified")
+
+	_, err := state.Get(merged)
+	require.NoError(t, err)
+}
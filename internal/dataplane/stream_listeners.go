@@ -0,0 +1,105 @@
+package dataplane
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kong/go-kong/kong"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
+	configurationv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
+)
+
+// -----------------------------------------------------------------------------
+// Dataplane Client - TCPIngress and UDPIngress Stream Listeners
+// -----------------------------------------------------------------------------
+
+// streamListenersCondition computes the StreamListenersReady condition of a
+// TCPIngress or UDPIngress given the stream listeners of the data-plane.
+// Connections to ports the Kong proxy doesn't listen on never reach the
+// routes of the rules, which is otherwise hard to tell from the rules being
+// translated fine.
+func streamListenersCondition(ingress client.Object, listeners []kong.StreamListener) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               configurationv1beta1.IngressConditionStreamListenersReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             configurationv1beta1.IngressReasonListening,
+		Message:            "the Kong proxy listens on the ports of the rules",
+		ObservedGeneration: ingress.GetGeneration(),
+	}
+	udp := l4IngressKind(ingress) == "UDPIngress"
+	if missing := missingStreamListenerPorts(ingress, listeners, udp); len(missing) > 0 {
+		protocol, flag := "TCP", ""
+		if udp {
+			protocol, flag = "UDP", " with the udp flag"
+		}
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = configurationv1beta1.IngressReasonListenersMissing
+		condition.Message = fmt.Sprintf("the Kong proxy has no %s stream listener on ports %s: add them to its "+
+			"stream_listen configuration%s and expose them through its Service", protocol, formatPorts(missing), flag)
+	}
+	return condition
+}
+
+// missingStreamListenerPorts provides the ports the valid rules of a
+// TCPIngress or UDPIngress claim which none of the given stream listeners of
+// the same protocol listens on, in ascending order.
+func missingStreamListenerPorts(ingress client.Object, listeners []kong.StreamListener, udp bool) []int {
+	listening := make(map[int]bool)
+	for _, listener := range listeners {
+		if listener.UDP == udp {
+			listening[listener.Port] = true
+		}
+	}
+
+	claimed := make(map[int]bool)
+	switch ingress := ingress.(type) {
+	case *configurationv1beta1.TCPIngress:
+		for _, rule := range ingress.Spec.Rules {
+			if parser.ValidateTCPIngressRule(rule) == nil {
+				claimed[rule.Port] = true
+			}
+		}
+	case *configurationv1beta1.UDPIngress:
+		for _, rule := range ingress.Spec.Rules {
+			if parser.ValidateUDPIngressRule(rule) != nil {
+				continue
+			}
+			for port := rule.Port; port <= parser.UDPIngressRuleEndPort(rule); port++ {
+				claimed[port] = true
+			}
+		}
+	}
+
+	var missing []int
+	for port := range claimed {
+		if !listening[port] {
+			missing = append(missing, port)
+		}
+	}
+	sort.Ints(missing)
+	return missing
+}
+
+// formatPorts formats ports given in ascending order, collapsing consecutive
+// ports into ranges, e.g. "53, 7000-7002".
+func formatPorts(ports []int) string {
+	var formatted []string
+	for i := 0; i < len(ports); {
+		j := i
+		for j+1 < len(ports) && ports[j+1] == ports[j]+1 {
+			j++
+		}
+		if i == j {
+			formatted = append(formatted, strconv.Itoa(ports[i]))
+		} else {
+			formatted = append(formatted, fmt.Sprintf("%d-%d", ports[i], ports[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(formatted, ", ")
+}
@@ -0,0 +1,64 @@
+package dataplane
+
+import (
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configurationv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
+)
+
+func TestStreamListenersCondition(t *testing.T) {
+	listeners := []kong.StreamListener{
+		{Port: 9000},
+		{Port: 9443, SSL: true},
+		{Port: 53, UDP: true},
+		{Port: 7001, UDP: true},
+	}
+	backend := configurationv1beta1.IngressBackend{ServiceName: "svc", ServicePort: 80}
+
+	t.Run("TCPIngresses whose ports are all listened on", func(t *testing.T) {
+		ingress := &configurationv1beta1.TCPIngress{
+			ObjectMeta: metav1.ObjectMeta{Generation: 3},
+			Spec: configurationv1beta1.TCPIngressSpec{Rules: []configurationv1beta1.IngressRule{
+				{Port: 9000, Backend: backend},
+				{Port: 9443, Host: "example.com", Backend: backend},
+				{Port: 9001}, // invalid rules aren't translated
+			}},
+		}
+		condition := streamListenersCondition(ingress, listeners)
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+		assert.Equal(t, configurationv1beta1.IngressReasonListening, condition.Reason)
+		assert.Equal(t, int64(3), condition.ObservedGeneration)
+	})
+
+	t.Run("TCPIngresses whose ports are only listened on for UDP", func(t *testing.T) {
+		ingress := &configurationv1beta1.TCPIngress{
+			Spec: configurationv1beta1.TCPIngressSpec{Rules: []configurationv1beta1.IngressRule{
+				{Port: 53, Backend: backend},
+				{Port: 9000, Backend: backend},
+			}},
+		}
+		condition := streamListenersCondition(ingress, listeners)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, configurationv1beta1.IngressReasonListenersMissing, condition.Reason)
+		assert.Equal(t, "the Kong proxy has no TCP stream listener on ports 53: add them to its stream_listen "+
+			"configuration and expose them through its Service", condition.Message)
+	})
+
+	t.Run("UDPIngresses whose port ranges are partially listened on", func(t *testing.T) {
+		ingress := &configurationv1beta1.UDPIngress{
+			Spec: configurationv1beta1.UDPIngressSpec{Rules: []configurationv1beta1.UDPIngressRule{
+				{Port: 53, Backend: backend},
+				{Port: 7000, EndPort: 7004, Backend: backend},
+				{Port: 9000, Backend: backend},
+			}},
+		}
+		condition := streamListenersCondition(ingress, listeners)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, "the Kong proxy has no UDP stream listener on ports 7000, 7002-7004, 9000: add them to its "+
+			"stream_listen configuration with the udp flag and expose them through its Service", condition.Message)
+	})
+}
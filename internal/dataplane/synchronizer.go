@@ -9,6 +9,8 @@ import (
 	"github.com/bombsimon/logrusr/v2"
 	"github.com/go-logr/logr"
 	"github.com/sirupsen/logrus"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/sendconfig"
 )
 
 // -----------------------------------------------------------------------------
@@ -28,6 +30,18 @@ const (
 	DefaultSyncSeconds float32 = 3.0
 )
 
+const (
+	// maxValidationErrorBackoff bounds the delay before applying again a
+	// configuration Kong rejected. Retrying an unchanged configuration is
+	// pointless, but a fixed one should be applied soon.
+	maxValidationErrorBackoff = 30 * time.Second
+
+	// maxAuthErrorBackoff bounds the delay before applying a configuration
+	// again after Kong refused the credentials of the controller, which are
+	// unlikely to be fixed soon.
+	maxAuthErrorBackoff = 5 * time.Minute
+)
+
 // -----------------------------------------------------------------------------
 // Synchronizer - Public Types
 // -----------------------------------------------------------------------------
@@ -43,8 +57,18 @@ type Synchronizer struct {
 	// server configuration, flow control, channels and utility attributes
 	stagger         time.Duration
 	syncTicker      *time.Ticker
+	updateTrigger   chan struct{}
 	configApplied   bool
 	isServerRunning bool
+	stopped         chan struct{}
+
+	// retryAt is the earliest time the next update is performed after a
+	// failed one, and failures the number of consecutive failed updates
+	// with errors of class failureClass. These are only accessed by the
+	// update server.
+	retryAt      time.Time
+	failures     int
+	failureClass sendconfig.ErrorClass
 
 	lock sync.RWMutex
 }
@@ -69,6 +93,7 @@ func NewSynchronizerWithStagger(logger logrus.FieldLogger, dataplaneClient Clien
 		logger:          logrusr.New(logger),
 		dataplaneClient: dataplaneClient,
 		stagger:         stagger,
+		updateTrigger:   make(chan struct{}, 1),
 		configApplied:   false,
 	}
 
@@ -97,12 +122,23 @@ func (p *Synchronizer) Start(ctx context.Context) error {
 	}
 
 	p.syncTicker = time.NewTicker(p.stagger)
-	go p.startUpdateServer(ctx)
+	p.stopped = make(chan struct{})
+	go p.startUpdateServer(ctx, p.stopped)
 	p.isServerRunning = true
 
 	return nil
 }
 
+// TriggerUpdate makes the synchronization server perform an Update() right
+// away instead of waiting for the next tick. Triggers received while an update
+// is already pending are merged into it.
+func (p *Synchronizer) TriggerUpdate() {
+	select {
+	case p.updateTrigger <- struct{}{}:
+	default:
+	}
+}
+
 // IsRunning informs the caller whether the synchronization server is running.
 func (p *Synchronizer) IsRunning() bool {
 	p.lock.RLock()
@@ -112,20 +148,34 @@ func (p *Synchronizer) IsRunning() bool {
 
 // IsReady indicates whether the synchronizer is actively able to synchronize
 // configuration to the dataplane. It's similar to IsRunning() but reports
-// on whether configuration can actually be successful and is also used as part
-// of a controller-runtime Runnable interface to wait for readiness before
-// starting controllers.
+// on whether configuration was successfully applied at least once since the
+// server started, and is also used as part of a controller-runtime Runnable
+// interface to wait for readiness before starting controllers.
 func (p *Synchronizer) IsReady() bool {
-	// If the proxy is has no database, it is only ready after a successful sync
-	// Otherwise, it has no configuration loaded
-	if p.dataplaneClient.DBMode() == "off" {
-		p.lock.RLock()
-		defer p.lock.RUnlock()
-		return p.configApplied
+	// The proxy is only ready after a successful sync, regardless of whether
+	// it has a database: until then, it may have no configuration loaded, or
+	// configuration which doesn't reflect the cluster.
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.configApplied
+}
+
+// WaitUntilStopped blocks until the synchronization server has stopped after
+// its context was done, which it does once the update in progress, if any,
+// completes. It returns right away if the server was never started.
+func (p *Synchronizer) WaitUntilStopped(ctx context.Context) error {
+	p.lock.RLock()
+	stopped := p.stopped
+	p.lock.RUnlock()
+	if stopped == nil {
+		return nil
+	}
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
-	// If the proxy has a database, it is ready immediately
-	// It will load existing configuration from the database
-	return true
 }
 
 // NeedLeaderElection implements the controller-runtime Runnable interface to
@@ -141,7 +191,12 @@ func (p *Synchronizer) NeedLeaderElection() bool {
 
 // startUpdateServer runs a server in a background goroutine that is responsible for
 // updating the kong proxy backend at regular intervals.
-func (p *Synchronizer) startUpdateServer(ctx context.Context) {
+func (p *Synchronizer) startUpdateServer(ctx context.Context, stopped chan struct{}) {
+	defer close(stopped)
+	// updates don't use ctx, so that the update in progress when the server
+	// is shut down isn't interrupted, leaving a partially applied configuration
+	// in a DB-backed Kong. The Kong API timeout bounds them regardless.
+	updateCtx := context.Background()
 	var initialConfig sync.Once
 	for {
 		select {
@@ -159,10 +214,26 @@ func (p *Synchronizer) startUpdateServer(ctx context.Context) {
 
 			return
 		case <-p.syncTicker.C:
-			if err := p.dataplaneClient.Update(ctx); err != nil {
-				p.logger.Error(err, "could not update kong admin")
+			if time.Now().Before(p.retryAt) {
+				break
+			}
+			if err := p.dataplaneClient.Update(updateCtx); err != nil {
+				p.backOff(err)
 				break
 			}
+			p.resetBackoff()
+			initialConfig.Do(p.markConfigApplied)
+		case <-p.updateTrigger:
+			// triggered updates may apply a fixed configuration, but not
+			// fixed credentials
+			if p.failureClass == sendconfig.ErrorClassAuth && time.Now().Before(p.retryAt) {
+				break
+			}
+			if err := p.dataplaneClient.Update(updateCtx); err != nil {
+				p.backOff(err)
+				break
+			}
+			p.resetBackoff()
 			initialConfig.Do(p.markConfigApplied)
 		}
 	}
@@ -172,6 +243,52 @@ func (p *Synchronizer) startUpdateServer(ctx context.Context) {
 // Synchronizer - Private Methods - Helper
 // -----------------------------------------------------------------------------
 
+// backOff delays the next update after a failed one depending on the class of
+// its error: updates failing for transient reasons are retried on the next
+// tick, while those rejected by Kong or refused because of the credentials of
+// the controller are retried with an exponential backoff.
+func (p *Synchronizer) backOff(err error) {
+	class := sendconfig.ClassifyError(err)
+	if class != p.failureClass {
+		p.failures = 0
+	}
+	p.failureClass = class
+	p.failures++
+
+	var delay time.Duration
+	switch class {
+	case sendconfig.ErrorClassValidation:
+		delay = exponentialBackoff(p.stagger, p.failures, maxValidationErrorBackoff)
+	case sendconfig.ErrorClassAuth:
+		delay = exponentialBackoff(p.stagger, p.failures, maxAuthErrorBackoff)
+	}
+	p.retryAt = time.Now().Add(delay)
+	p.logger.Error(err, "could not update kong admin", "error_class", string(class), "retry_in", delay.String())
+}
+
+// resetBackoff clears the backoff once an update succeeded.
+func (p *Synchronizer) resetBackoff() {
+	p.retryAt = time.Time{}
+	p.failures = 0
+	p.failureClass = ""
+}
+
+// exponentialBackoff provides the delay after a number of consecutive failures,
+// doubling from base up to max. The first failure is retried on the next tick.
+func exponentialBackoff(base time.Duration, failures int, max time.Duration) time.Duration {
+	if failures <= 1 {
+		return 0
+	}
+	delay := base
+	for i := 1; i < failures && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		return max
+	}
+	return delay
+}
+
 // markConfigApplied marks that config has been applied
 func (p *Synchronizer) markConfigApplied() {
 	p.lock.Lock()
@@ -1,13 +1,19 @@
 package dataplane
 
 import (
+	"errors"
+	"net/http"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/kong/go-kong/kong"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/net/context"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/sendconfig"
 )
 
 func TestSynchronizer(t *testing.T) {
@@ -27,11 +33,8 @@ func TestSynchronizer(t *testing.T) {
 	t.Log("verifying that a non-started dataplane synchronizer reports as not running")
 	assert.False(t, sync.IsRunning())
 
-	t.Log("verifying that postgres dp makes the synchronizer immediately ready")
-	c.dbmode = "postgres"
-	assert.True(t, sync.IsReady())
-
-	t.Log("verifying dbless mode means the synchronizer wont be ready until a config has been applied")
+	t.Log("verifying that the synchronizer won't be ready until a config has been applied, whatever the db mode")
+	assert.False(t, sync.IsReady())
 	c.dbmode = "off"
 	assert.False(t, sync.IsReady())
 
@@ -45,7 +48,7 @@ func TestSynchronizer(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, err.Error(), "server is already running")
 
-	t.Log("verifying that eventually the synchronizer reports as ready for a dbless dataplane")
+	t.Log("verifying that eventually the synchronizer reports as ready")
 	assert.Eventually(t, func() bool { return sync.IsReady() }, stagger*2, time.Millisecond*200)
 
 	t.Log("verifying that the dataplane eventually receieves several successful updates from the synchronizer")
@@ -75,6 +78,97 @@ func TestSynchronizer(t *testing.T) {
 	assert.Eventually(t, func() bool { return !sync.IsReady() }, time.Second, time.Millisecond*200)
 }
 
+func TestSynchronizerTriggerUpdate(t *testing.T) {
+	c := &fakeDataplaneClient{dbmode: "off"}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	t.Log("starting an update server which won't tick during the test")
+	sync, err := NewSynchronizerWithStagger(logrus.New(), c, time.Hour)
+	assert.NoError(t, err)
+	sync.syncTicker = time.NewTicker(time.Hour)
+	go sync.startUpdateServer(ctx, make(chan struct{}))
+
+	t.Log("verifying that a trigger results in an update right away")
+	sync.TriggerUpdate()
+	assert.Eventually(t, func() bool { return c.totalUpdates() == 1 }, time.Second, time.Millisecond*10)
+	assert.Eventually(t, func() bool { return sync.IsReady() }, time.Second, time.Millisecond*10)
+	assert.Never(t, func() bool { return c.totalUpdates() > 1 }, time.Millisecond*100, time.Millisecond*10)
+
+	t.Log("verifying that a later trigger results in another update")
+	sync.TriggerUpdate()
+	assert.Eventually(t, func() bool { return c.totalUpdates() == 2 }, time.Second, time.Millisecond*10)
+}
+
+func TestSynchronizerWaitUntilStopped(t *testing.T) {
+	c := &slowDataplaneClient{
+		fakeDataplaneClient: fakeDataplaneClient{dbmode: "off"},
+		started:             make(chan struct{}),
+		release:             make(chan struct{}),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sync, err := NewSynchronizerWithStagger(logrus.New(), c, time.Hour)
+	assert.NoError(t, err)
+	assert.NoError(t, sync.WaitUntilStopped(ctx), "a synchronizer which was never started is stopped")
+	sync.syncTicker = time.NewTicker(time.Hour)
+	sync.stopped = make(chan struct{})
+	go sync.startUpdateServer(ctx, sync.stopped)
+
+	t.Log("shutting the server down while an update is in progress")
+	sync.TriggerUpdate()
+	<-c.started
+	cancel()
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer waitCancel()
+	assert.ErrorIs(t, sync.WaitUntilStopped(waitCtx), context.DeadlineExceeded)
+
+	t.Log("verifying that the server stops once the update completes without being interrupted")
+	close(c.release)
+	assert.NoError(t, sync.WaitUntilStopped(context.Background()))
+	assert.Equal(t, 1, c.totalUpdates())
+	assert.NoError(t, c.updateCtxErr)
+}
+
+func TestSynchronizerBackOff(t *testing.T) {
+	sync, err := NewSynchronizerWithStagger(logrus.New(), &fakeDataplaneClient{}, time.Second)
+	require.NoError(t, err)
+
+	t.Log("verifying that transient errors are retried on the next tick")
+	sync.backOff(errors.New("connection refused"))
+	sync.backOff(errors.New("connection refused"))
+	assert.False(t, time.Now().Before(sync.retryAt))
+
+	t.Log("verifying that rejected configurations are retried with a backoff")
+	rejected := kong.NewAPIError(http.StatusBadRequest, "invalid")
+	sync.backOff(rejected)
+	assert.Equal(t, 1, sync.failures, "a change of error class resets the backoff")
+	sync.backOff(rejected)
+	assert.WithinDuration(t, time.Now().Add(2*time.Second), sync.retryAt, time.Second/2)
+
+	t.Log("verifying that refused credentials are retried with a backoff")
+	refused := kong.NewAPIError(http.StatusUnauthorized, "invalid credentials")
+	for i := 0; i < 20; i++ {
+		sync.backOff(refused)
+	}
+	assert.Equal(t, sendconfig.ErrorClassAuth, sync.failureClass)
+	assert.WithinDuration(t, time.Now().Add(maxAuthErrorBackoff), sync.retryAt, time.Second/2)
+
+	t.Log("verifying that a successful update resets the backoff")
+	sync.resetBackoff()
+	assert.True(t, sync.retryAt.IsZero())
+	assert.Zero(t, sync.failures)
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	assert.Equal(t, time.Duration(0), exponentialBackoff(time.Second, 1, time.Minute))
+	assert.Equal(t, 2*time.Second, exponentialBackoff(time.Second, 2, time.Minute))
+	assert.Equal(t, 4*time.Second, exponentialBackoff(time.Second, 3, time.Minute))
+	assert.Equal(t, time.Minute, exponentialBackoff(time.Second, 10, time.Minute))
+	assert.Equal(t, time.Minute, exponentialBackoff(time.Second, 1000, time.Minute))
+}
+
 // fakeDataplaneClient fakes the dataplane.Client interface so that we can
 // unit test the dataplane.Synchronizer.
 type fakeDataplaneClient struct {
@@ -101,3 +195,19 @@ func (c *fakeDataplaneClient) totalUpdates() int {
 	defer c.lock.RUnlock()
 	return c.updateCount
 }
+
+// slowDataplaneClient is a fakeDataplaneClient whose update blocks until
+// released, recording whether its context was canceled in the meantime.
+type slowDataplaneClient struct {
+	fakeDataplaneClient
+	started      chan struct{}
+	release      chan struct{}
+	updateCtxErr error
+}
+
+func (c *slowDataplaneClient) Update(ctx context.Context) error {
+	close(c.started)
+	<-c.release
+	c.updateCtxErr = ctx.Err()
+	return c.fakeDataplaneClient.Update(ctx)
+}
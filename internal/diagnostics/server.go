@@ -11,7 +11,9 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/kong/deck/file"
+	"sigs.k8s.io/yaml"
 
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/deckgen"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
 )
 
@@ -21,10 +23,17 @@ type Server struct {
 	ProfilingEnabled bool
 	ConfigDumps      util.ConfigDumpDiagnostic
 	ConfigLock       *sync.RWMutex
+
+	// FeatureReports receives the translation features in effect, served
+	// alongside the other diagnostics, if set.
+	FeatureReports chan util.FeatureReport
 }
 
 var successfulConfigDump file.Content
+var successfulConfigHash string
 var failedConfigDump file.Content
+var featureReport *util.FeatureReport
+var translationFailures []util.TranslationFailureReport
 
 // Listen starts up the HTTP server and blocks until ctx expires.
 func (s *Server) Listen(ctx context.Context, port int) error {
@@ -36,6 +45,9 @@ func (s *Server) Listen(ctx context.Context, port int) error {
 	if s.ProfilingEnabled {
 		installProfilingHandlers(mux)
 	}
+	if s.FeatureReports != nil {
+		mux.HandleFunc("/debug/features", s.features)
+	}
 
 	httpServer := &http.Server{Addr: fmt.Sprintf(":%d", port), Handler: mux}
 	errChan := make(chan error)
@@ -65,7 +77,7 @@ func (s *Server) Listen(ctx context.Context, port int) error {
 	}
 }
 
-// receiveConfig watches the config update channel
+// receiveConfig watches the config update and feature report channels
 func (s *Server) receiveConfig(ctx context.Context) {
 	for {
 		select {
@@ -75,8 +87,17 @@ func (s *Server) receiveConfig(ctx context.Context) {
 				failedConfigDump = dump.Config
 			} else {
 				successfulConfigDump = dump.Config
+				successfulConfigHash = dump.Hash
 			}
 			s.ConfigLock.Unlock()
+		case failures := <-s.ConfigDumps.TranslationFailures:
+			s.ConfigLock.Lock()
+			translationFailures = failures
+			s.ConfigLock.Unlock()
+		case report := <-s.FeatureReports:
+			s.ConfigLock.Lock()
+			featureReport = &report
+			s.ConfigLock.Unlock()
 		case <-ctx.Done():
 			if err := ctx.Err(); err != nil {
 				s.Logger.Error(err, "shutting down diagnostic config collection: context completed with error")
@@ -106,6 +127,10 @@ func installProfilingHandlers(mux *http.ServeMux) {
 func (s *Server) installDumpHandlers(mux *http.ServeMux) {
 	mux.HandleFunc("/debug/config/successful", s.lastConfig(&successfulConfigDump))
 	mux.HandleFunc("/debug/config/failed", s.lastConfig(&failedConfigDump))
+	mux.HandleFunc("/debug/config/deck", s.deckConfig)
+	mux.HandleFunc("/debug/config/diff", s.configDiff)
+	mux.HandleFunc("/debug/config/hash", s.configHash)
+	mux.HandleFunc("/debug/config/translation-failures", s.translationFailures)
 }
 
 // redirectTo redirects request to a certain destination.
@@ -125,3 +150,86 @@ func (s *Server) lastConfig(config *file.Content) func(rw http.ResponseWriter, r
 		s.ConfigLock.RUnlock()
 	}
 }
+
+// deckConfig serves the last successful configuration in decK YAML format, so
+// that it can be snapshotted or applied with decK as is.
+func (s *Server) deckConfig(rw http.ResponseWriter, req *http.Request) {
+	s.ConfigLock.RLock()
+	defer s.ConfigLock.RUnlock()
+	// configurations generated by the controller always have a format version
+	if successfulConfigDump.FormatVersion == "" {
+		http.Error(rw, "no configuration has been successfully applied yet", http.StatusNotFound)
+		return
+	}
+	config, err := yaml.Marshal(successfulConfigDump)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/yaml")
+	_, _ = rw.Write(config)
+}
+
+// configDiff serves the entities which differ between the last successful
+// configuration and the last failed one.
+func (s *Server) configDiff(rw http.ResponseWriter, req *http.Request) {
+	s.ConfigLock.RLock()
+	defer s.ConfigLock.RUnlock()
+	// configurations generated by the controller always have a format version
+	if successfulConfigDump.FormatVersion == "" || failedConfigDump.FormatVersion == "" {
+		http.Error(rw, "both a successful and a failed configuration are needed for a diff", http.StatusNotFound)
+		return
+	}
+	diff := deckgen.DiffConfigs(&successfulConfigDump, &failedConfigDump)
+	if diff == nil {
+		diff = []deckgen.EntityDiff{}
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(diff); err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// configHash serves the checksum of the last successful configuration, which
+// only changes when the configuration does.
+func (s *Server) configHash(rw http.ResponseWriter, req *http.Request) {
+	s.ConfigLock.RLock()
+	defer s.ConfigLock.RUnlock()
+	if successfulConfigHash == "" {
+		http.Error(rw, "no configuration has been successfully applied yet", http.StatusNotFound)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(map[string]string{"hash": successfulConfigHash}); err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// features serves the translation features in effect, as of the last update.
+func (s *Server) features(rw http.ResponseWriter, req *http.Request) {
+	s.ConfigLock.RLock()
+	defer s.ConfigLock.RUnlock()
+	if featureReport == nil {
+		http.Error(rw, "no configuration has been built yet", http.StatusNotFound)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(featureReport); err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// translationFailures serves the Kubernetes objects which couldn't be
+// translated, as of the last update.
+func (s *Server) translationFailures(rw http.ResponseWriter, req *http.Request) {
+	s.ConfigLock.RLock()
+	defer s.ConfigLock.RUnlock()
+	failures := translationFailures
+	if failures == nil {
+		failures = []util.TranslationFailureReport{}
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(failures); err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,233 @@
+// Package introspection serves the model of the configuration the controller
+// last applied to Kong: its services, routes and plugins along with the
+// Kubernetes objects they were translated from, so that external tools can
+// discover the routing of the edge without reading the Kong Admin API.
+package introspection
+
+import (
+	"sort"
+
+	"github.com/kong/go-kong/kong"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+	configurationv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+)
+
+// Model is the translated model of the Kubernetes objects applied to Kong.
+type Model struct {
+	Services []Service `json:"services"`
+	// Plugins are the plugins which aren't attached to a service or a route,
+	// which are global or apply to a consumer.
+	Plugins []Plugin `json:"plugins"`
+}
+
+// Source identifies a Kubernetes object entities were translated from.
+type Source struct {
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+// Service is a Kong service and the entities attached to it.
+type Service struct {
+	Name     string   `json:"name"`
+	Protocol string   `json:"protocol,omitempty"`
+	Host     string   `json:"host,omitempty"`
+	Port     int      `json:"port,omitempty"`
+	Path     string   `json:"path,omitempty"`
+	Sources  []Source `json:"sources,omitempty"`
+	Routes   []Route  `json:"routes"`
+	Plugins  []Plugin `json:"plugins"`
+}
+
+// Route is a Kong route and the plugins attached to it.
+type Route struct {
+	Name      string   `json:"name"`
+	Service   string   `json:"service"`
+	Protocols []string `json:"protocols,omitempty"`
+	Hosts     []string `json:"hosts,omitempty"`
+	Paths     []string `json:"paths,omitempty"`
+	Methods   []string `json:"methods,omitempty"`
+	SNIs      []string `json:"snis,omitempty"`
+	Source    *Source  `json:"source,omitempty"`
+	Plugins   []Plugin `json:"plugins"`
+}
+
+// Plugin is a Kong plugin. Its configuration isn't included, as it may
+// contain credentials.
+type Plugin struct {
+	Name     string `json:"name"`
+	Service  string `json:"service,omitempty"`
+	Route    string `json:"route,omitempty"`
+	Consumer string `json:"consumer,omitempty"`
+	// Source is the KongPlugin or KongClusterPlugin the plugin was translated
+	// from, unset for the plugins generated from annotations.
+	Source *Source `json:"source,omitempty"`
+}
+
+// NewModel provides the model of a translated configuration.
+func NewModel(state *kongstate.KongState) *Model {
+	model := &Model{Services: []Service{}, Plugins: []Plugin{}}
+	services := make(map[string]int, len(state.Services))
+	routes := make(map[string][2]int)
+	for i, s := range state.Services {
+		service := Service{
+			Name:     stringOrEmpty(s.Name),
+			Protocol: stringOrEmpty(s.Protocol),
+			Host:     stringOrEmpty(s.Host),
+			Port:     intOrZero(s.Port),
+			Path:     stringOrEmpty(s.Path),
+			Sources:  serviceSources(&state.Services[i]),
+			Routes:   []Route{},
+			Plugins:  generatedPlugins(s.Plugins, stringOrEmpty(s.Name), ""),
+		}
+		for _, r := range s.Routes {
+			route := Route{
+				Name:      stringOrEmpty(r.Name),
+				Service:   service.Name,
+				Protocols: stringValues(r.Protocols),
+				Hosts:     stringValues(r.Hosts),
+				Paths:     stringValues(r.Paths),
+				Methods:   stringValues(r.Methods),
+				SNIs:      stringValues(r.SNIs),
+				Plugins:   generatedPlugins(r.Plugins, "", stringOrEmpty(r.Name)),
+			}
+			if r.Ingress.Name != "" {
+				source := objectSource(r.Ingress)
+				route.Source = &source
+			}
+			routes[route.Name] = [2]int{len(model.Services), len(service.Routes)}
+			service.Routes = append(service.Routes, route)
+		}
+		services[service.Name] = len(model.Services)
+		model.Services = append(model.Services, service)
+	}
+
+	// the plugins reference the services and routes they're attached to by name
+	for _, p := range state.Plugins {
+		plugin := Plugin{
+			Name:   stringOrEmpty(p.Name),
+			Source: pluginSource(p),
+		}
+		if p.Service != nil {
+			plugin.Service = stringOrEmpty(p.Service.ID)
+		}
+		if p.Route != nil {
+			plugin.Route = stringOrEmpty(p.Route.ID)
+		}
+		if p.Consumer != nil {
+			plugin.Consumer = stringOrEmpty(p.Consumer.ID)
+		}
+		if i, ok := routes[plugin.Route]; ok {
+			route := &model.Services[i[0]].Routes[i[1]]
+			route.Plugins = append(route.Plugins, plugin)
+		} else if i, ok := services[plugin.Service]; ok {
+			model.Services[i].Plugins = append(model.Services[i].Plugins, plugin)
+		} else {
+			model.Plugins = append(model.Plugins, plugin)
+		}
+	}
+	return model
+}
+
+// Routes provides the routes of all the services.
+func (m *Model) Routes() []Route {
+	routes := []Route{}
+	for _, service := range m.Services {
+		routes = append(routes, service.Routes...)
+	}
+	return routes
+}
+
+// AllPlugins provides all the plugins, whether they're attached to a service,
+// to a route or to neither.
+func (m *Model) AllPlugins() []Plugin {
+	plugins := []Plugin{}
+	for _, service := range m.Services {
+		plugins = append(plugins, service.Plugins...)
+		for _, route := range service.Routes {
+			plugins = append(plugins, route.Plugins...)
+		}
+	}
+	return append(plugins, m.Plugins...)
+}
+
+// serviceSources provides the Kubernetes Services a Kong service proxies to,
+// and the object which configured it (e.g. an HTTPRoute) if any.
+func serviceSources(s *kongstate.Service) []Source {
+	var sources []Source
+	for _, k8sService := range s.K8sServices {
+		sources = append(sources, Source{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "Service",
+			Namespace:  k8sService.Namespace,
+			Name:       k8sService.Name,
+		})
+	}
+	// K8sServices is a map, sort its Services for a stable output
+	sort.Slice(sources, func(i, j int) bool {
+		if sources[i].Namespace != sources[j].Namespace {
+			return sources[i].Namespace < sources[j].Namespace
+		}
+		return sources[i].Name < sources[j].Name
+	})
+	if s.Parent != nil {
+		sources = append(sources, objectSource(util.FromK8sObject(s.Parent)))
+	}
+	return sources
+}
+
+func objectSource(info util.K8sObjectInfo) Source {
+	return Source{
+		APIVersion: info.GroupVersionKind.GroupVersion().String(),
+		Kind:       info.GroupVersionKind.Kind,
+		Namespace:  info.Namespace,
+		Name:       info.Name,
+	}
+}
+
+func pluginSource(p kongstate.Plugin) *Source {
+	if p.K8sName == "" {
+		return nil
+	}
+	apiVersion := configurationv1.SchemeGroupVersion.String()
+	if p.ClusterPlugin {
+		return &Source{APIVersion: apiVersion, Kind: "KongClusterPlugin", Name: p.K8sName}
+	}
+	return &Source{APIVersion: apiVersion, Kind: "KongPlugin", Namespace: p.K8sNamespace, Name: p.K8sName}
+}
+
+// generatedPlugins provides the plugins the controller generated for a service
+// or a route from its annotations.
+func generatedPlugins(plugins []kong.Plugin, service, route string) []Plugin {
+	res := []Plugin{}
+	for _, p := range plugins {
+		res = append(res, Plugin{Name: stringOrEmpty(p.Name), Service: service, Route: route})
+	}
+	return res
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func intOrZero(i *int) int {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
+
+func stringValues(ss []*string) []string {
+	var res []string
+	for _, s := range ss {
+		res = append(res, stringOrEmpty(s))
+	}
+	return res
+}
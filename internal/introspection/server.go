@@ -0,0 +1,156 @@
+package introspection
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// shutdownTimeout is how long the server waits for the requests in progress
+// to complete when it stops.
+const shutdownTimeout = 5 * time.Second
+
+// ServerConfig configures the introspection API server.
+type ServerConfig struct {
+	// ListenAddr is the address the server listens on, "off" to disable it.
+	ListenAddr string
+	// TokenPath is the path of the file holding the token clients must send
+	// as a bearer token.
+	TokenPath string
+	// CertPath and KeyPath are the paths of the PEM certificate and private
+	// key the server uses, which serves plain HTTP if they're unset.
+	CertPath string
+	KeyPath  string
+}
+
+// ModelProvider provides the model of the configuration last applied to Kong,
+// nil if none was applied yet.
+type ModelProvider interface {
+	IntrospectionModel() *Model
+}
+
+// Server serves the model of the configuration last applied to Kong to the
+// clients which authenticate with its token. It implements the Runnable
+// interface of controller-runtime managers.
+type Server struct {
+	config   ServerConfig
+	token    string
+	provider ModelProvider
+	logger   logr.Logger
+}
+
+// NewServer provides a server for the model of provider, reading its token
+// from the token file.
+func NewServer(config ServerConfig, provider ModelProvider, logger logr.Logger) (*Server, error) {
+	if config.TokenPath == "" {
+		return nil, errors.New("a token file is required")
+	}
+	if (config.CertPath == "") != (config.KeyPath == "") {
+		return nil, errors.New("both or neither of the certificate and key files must be provided")
+	}
+	token, err := os.ReadFile(config.TokenPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the token file: %w", err)
+	}
+	s := &Server{
+		config:   config,
+		token:    strings.TrimSpace(string(token)),
+		provider: provider,
+		logger:   logger,
+	}
+	if s.token == "" {
+		return nil, fmt.Errorf("token file %s is empty", config.TokenPath)
+	}
+	return s, nil
+}
+
+// Handler provides the HTTP handler of the API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/model", s.serve(func(m *Model) interface{} { return m }))
+	mux.HandleFunc("/v1/services", s.serve(func(m *Model) interface{} { return m.Services }))
+	mux.HandleFunc("/v1/routes", s.serve(func(m *Model) interface{} { return m.Routes() }))
+	mux.HandleFunc("/v1/plugins", s.serve(func(m *Model) interface{} { return m.AllPlugins() }))
+	return s.authenticate(mux)
+}
+
+// Start serves the API until ctx is done.
+func (s *Server) Start(ctx context.Context) error {
+	httpServer := &http.Server{
+		Addr:              s.config.ListenAddr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errChan := make(chan error, 1)
+	go func() {
+		var err error
+		if s.config.CertPath != "" {
+			err = httpServer.ListenAndServeTLS(s.config.CertPath, s.config.KeyPath)
+		} else {
+			err = httpServer.ListenAndServe()
+		}
+		if !errors.Is(err, http.ErrServerClosed) {
+			errChan <- err
+		}
+	}()
+	s.logger.Info("introspection API server is starting to listen", "addr", s.config.ListenAddr)
+
+	select {
+	case <-ctx.Done():
+		s.logger.Info("shutting down introspection API server")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return httpServer.Shutdown(shutdownCtx)
+	case err := <-errChan:
+		return fmt.Errorf("introspection API server stopped: %w", err)
+	}
+}
+
+// NeedLeaderElection indicates that the API is served by all the instances of
+// the controller, so that probing it doesn't depend on which one leads. Only
+// the leader applies the configuration to Kong though: standby instances
+// respond with 503 Service Unavailable until they're elected.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// authenticate only passes the requests bearing the token of the server to next.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			rw.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(rw, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(rw, req)
+	})
+}
+
+// serve provides a handler serving the part of the model view selects as JSON.
+func (s *Server) serve(view func(*Model) interface{}) func(http.ResponseWriter, *http.Request) {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			rw.Header().Set("Allow", http.MethodGet)
+			http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		model := s.provider.IntrospectionModel()
+		if model == nil {
+			http.Error(rw, "no configuration was applied to Kong yet", http.StatusServiceUnavailable)
+			return
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(rw).Encode(view(model)); err != nil {
+			s.logger.Error(err, "failed to encode the introspection model")
+		}
+	}
+}
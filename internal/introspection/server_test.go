@@ -0,0 +1,182 @@
+package introspection
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+)
+
+type staticModel struct {
+	model *Model
+}
+
+func (s staticModel) IntrospectionModel() *Model {
+	return s.model
+}
+
+func testState() *kongstate.KongState {
+	return &kongstate.KongState{
+		Services: []kongstate.Service{{
+			Service: kong.Service{
+				Name:     kong.String("default.echo.80"),
+				Host:     kong.String("echo.default.80.svc"),
+				Port:     kong.Int(80),
+				Protocol: kong.String("http"),
+			},
+			K8sServices: map[string]*corev1.Service{
+				"default/echo": {ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "echo"}},
+			},
+			Routes: []kongstate.Route{{
+				Route: kong.Route{
+					Name:  kong.String("default.echo.00"),
+					Hosts: kong.StringSlice("echo.example.com"),
+					Paths: kong.StringSlice("/echo"),
+				},
+				Ingress: util.K8sObjectInfo{
+					Namespace:        "default",
+					Name:             "echo",
+					GroupVersionKind: netv1.SchemeGroupVersion.WithKind("Ingress"),
+				},
+				Plugins: []kong.Plugin{{Name: kong.String("request-termination")}},
+			}},
+		}},
+		Plugins: []kongstate.Plugin{
+			{
+				Plugin: kong.Plugin{
+					Name:   kong.String("key-auth"),
+					Route:  &kong.Route{ID: kong.String("default.echo.00")},
+					Config: kong.Configuration{"key_names": []string{"apikey"}},
+				},
+				K8sNamespace: "default",
+				K8sName:      "auth",
+			},
+			{
+				Plugin:        kong.Plugin{Name: kong.String("prometheus")},
+				K8sName:       "metrics",
+				ClusterPlugin: true,
+			},
+		},
+	}
+}
+
+func TestNewModel(t *testing.T) {
+	model := NewModel(testState())
+
+	require.Len(t, model.Services, 1)
+	service := model.Services[0]
+	assert.Equal(t, "default.echo.80", service.Name)
+	assert.Equal(t, 80, service.Port)
+	assert.Equal(t, []Source{{APIVersion: "v1", Kind: "Service", Namespace: "default", Name: "echo"}}, service.Sources)
+
+	require.Len(t, service.Routes, 1)
+	route := service.Routes[0]
+	assert.Equal(t, "default.echo.80", route.Service)
+	assert.Equal(t, []string{"echo.example.com"}, route.Hosts)
+	assert.Equal(t, []string{"/echo"}, route.Paths)
+	assert.Equal(t, &Source{APIVersion: "networking.k8s.io/v1", Kind: "Ingress", Namespace: "default", Name: "echo"}, route.Source)
+	assert.Equal(t, []Plugin{
+		{Name: "request-termination", Route: "default.echo.00"},
+		{
+			Name:   "key-auth",
+			Route:  "default.echo.00",
+			Source: &Source{APIVersion: "configuration.konghq.com/v1", Kind: "KongPlugin", Namespace: "default", Name: "auth"},
+		},
+	}, route.Plugins)
+
+	assert.Equal(t, []Plugin{{
+		Name:   "prometheus",
+		Source: &Source{APIVersion: "configuration.konghq.com/v1", Kind: "KongClusterPlugin", Name: "metrics"},
+	}}, model.Plugins)
+	assert.Len(t, model.AllPlugins(), 3)
+	assert.Equal(t, service.Routes, model.Routes())
+}
+
+func TestServer(t *testing.T) {
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenPath, []byte("secret\n"), 0o600))
+
+	provider := &staticModel{}
+	server, err := NewServer(ServerConfig{ListenAddr: ":0", TokenPath: tokenPath}, provider, logr.Discard())
+	require.NoError(t, err)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	get := func(path, token string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, ts.URL+path, nil)
+		require.NoError(t, err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("requests without the token are rejected", func(t *testing.T) {
+		for _, token := range []string{"", "wrong"} {
+			resp := get("/v1/services", token)
+			resp.Body.Close()
+			assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		}
+	})
+
+	t.Run("the API is unavailable until a configuration is applied", func(t *testing.T) {
+		resp := get("/v1/services", "secret")
+		resp.Body.Close()
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	})
+
+	provider.model = NewModel(testState())
+
+	t.Run("routes are served", func(t *testing.T) {
+		resp := get("/v1/routes", "secret")
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var routes []Route
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&routes))
+		assert.Equal(t, provider.model.Routes(), routes)
+	})
+
+	t.Run("plugin configurations are not served", func(t *testing.T) {
+		resp := get("/v1/plugins", "secret")
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var plugins []map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&plugins))
+		require.Len(t, plugins, 3)
+		for _, plugin := range plugins {
+			assert.NotContains(t, plugin, "config")
+		}
+	})
+}
+
+func TestNewServerInvalidConfig(t *testing.T) {
+	emptyTokenPath := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(emptyTokenPath, []byte("\n"), 0o600))
+
+	for name, config := range map[string]ServerConfig{
+		"no token file":      {ListenAddr: ":0"},
+		"missing token file": {ListenAddr: ":0", TokenPath: filepath.Join(t.TempDir(), "missing")},
+		"empty token file":   {ListenAddr: ":0", TokenPath: emptyTokenPath},
+		"certificate alone":  {ListenAddr: ":0", TokenPath: emptyTokenPath, CertPath: "tls.crt"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewServer(config, &staticModel{}, logr.Discard())
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/adminapi"
+)
+
+// -----------------------------------------------------------------------------
+// Controller Manager - Kong Admin API mTLS
+// -----------------------------------------------------------------------------
+
+// loadKongAdminTLSSecret loads the TLS credentials used with the Kong Admin
+// API from the Secret given with --kong-admin-tls-secret, and keeps them up to
+// date as the Secret rotates until ctx is done. The Secret holds a client
+// certificate and key (tls.crt and tls.key) and/or CA certificates (ca.crt).
+func loadKongAdminTLSSecret(ctx context.Context, logger logr.Logger, kubeconfig *rest.Config, c *Config) error {
+	namespace, name, ok := strings.Cut(c.KongAdminTLSSecret, "/")
+	if !ok || namespace == "" || name == "" {
+		return fmt.Errorf("%q must be a Secret given as <namespace>/<name>", c.KongAdminTLSSecret)
+	}
+
+	clientset, err := kubernetes.NewForConfig(kubeconfig)
+	if err != nil {
+		return err
+	}
+	secret, err := clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	credentials := &adminapi.TLSCredentials{}
+	if err := updateTLSCredentials(credentials, secret); err != nil {
+		return fmt.Errorf("invalid Secret %s: %w", c.KongAdminTLSSecret, err)
+	}
+	c.KongAdminAPIConfig.TLSCredentials = credentials
+
+	factory := informers.NewSharedInformerFactoryWithOptions(clientset, 0,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+		}),
+	)
+	reload := func(obj interface{}) {
+		secret, ok := obj.(*corev1.Secret)
+		if !ok {
+			return
+		}
+		if err := updateTLSCredentials(credentials, secret); err != nil {
+			logger.Error(err, "invalid Kong Admin API TLS Secret, keeping the current credentials", "secret", c.KongAdminTLSSecret)
+			return
+		}
+		logger.Info("loaded the Kong Admin API TLS credentials", "secret", c.KongAdminTLSSecret, "resourceVersion", secret.ResourceVersion)
+	}
+	factory.Core().V1().Secrets().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    reload,
+		UpdateFunc: func(_, obj interface{}) { reload(obj) },
+	})
+	factory.Start(ctx.Done())
+	return nil
+}
+
+// updateTLSCredentials replaces the credentials with those of the Secret.
+func updateTLSCredentials(credentials *adminapi.TLSCredentials, secret *corev1.Secret) error {
+	return credentials.Update(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey], secret.Data[corev1.ServiceAccountRootCAKey])
+}
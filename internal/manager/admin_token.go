@@ -0,0 +1,84 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/go-logr/logr"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/adminapi"
+)
+
+// -----------------------------------------------------------------------------
+// Controller Manager - Kong Enterprise RBAC Token Rotation
+// -----------------------------------------------------------------------------
+
+// watchKongAdminTokenFile reads the Kong Enterprise RBAC token from the file
+// given with --kong-admin-token-file, e.g. a key of a mounted Secret, and
+// re-reads it whenever the file changes until ctx is done, so that the token
+// can be rotated without restarting the controller. The directory of the file
+// is watched rather than the file itself since Kubernetes updates mounted
+// Secrets by swapping a symlink.
+func watchKongAdminTokenFile(ctx context.Context, logger logr.Logger, c *Config) error {
+	if c.KongAdminToken != "" {
+		return fmt.Errorf("both --kong-admin-token and --kong-admin-token-file are set; please remove one or the other")
+	}
+	path := c.KongAdminTokenPath
+	token, err := readKongAdminToken(path)
+	if err != nil {
+		return err
+	}
+	adminToken := &adminapi.AdminToken{}
+	adminToken.Set(token)
+	c.KongAdminAPIConfig.AdminToken = adminToken
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return fmt.Errorf("failed to watch %s: %w", path, err)
+	}
+
+	go func() {
+		defer watcher.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err := <-watcher.Errors:
+				logger.Error(err, "failed to watch the Kong Admin API token file", "path", path)
+			case <-watcher.Events:
+				token, err := readKongAdminToken(path)
+				if err != nil {
+					logger.Error(err, "failed to re-read the Kong Admin API token, keeping the current one", "path", path)
+					continue
+				}
+				if token != adminToken.Get() {
+					adminToken.Set(token)
+					logger.Info("reloaded the Kong Admin API token", "path", path)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// readKongAdminToken reads the token from the given file, ignoring the
+// surrounding whitespace.
+func readKongAdminToken(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the Kong Admin API token from %s: %w", path, err)
+	}
+	token := strings.TrimSpace(string(b))
+	if token == "" {
+		return "", fmt.Errorf("the Kong Admin API token file %s is empty", path)
+	}
+	return token, nil
+}
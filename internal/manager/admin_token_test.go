@@ -0,0 +1,53 @@
+package manager
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchKongAdminTokenFile(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// lay the token out the way Kubernetes mounts Secrets, behind a symlink to
+	// the directory of the current version which updates swap.
+	dir := t.TempDir()
+	writeVersion := func(version, token string) {
+		require.NoError(t, os.Mkdir(filepath.Join(dir, version), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, version, "token"), []byte(token+"\n"), 0o600))
+		require.NoError(t, os.Symlink(version, filepath.Join(dir, "..data_tmp")))
+		require.NoError(t, os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")))
+	}
+	writeVersion("v1", "initial-token")
+	path := filepath.Join(dir, "token")
+	require.NoError(t, os.Symlink(filepath.Join("..data", "token"), path))
+
+	t.Log("verifying that the token is read on startup")
+	c := &Config{KongAdminTokenPath: path}
+	require.NoError(t, watchKongAdminTokenFile(ctx, logr.Discard(), c))
+	require.NotNil(t, c.KongAdminAPIConfig.AdminToken)
+	require.Equal(t, "initial-token", c.KongAdminAPIConfig.AdminToken.Get())
+
+	t.Log("verifying that the token is re-read when the Secret rotates")
+	writeVersion("v2", "rotated-token")
+	require.Eventually(t, func() bool {
+		return c.KongAdminAPIConfig.AdminToken.Get() == "rotated-token"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	t.Log("verifying that the token is kept when the file becomes invalid")
+	writeVersion("v3", "")
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, "rotated-token", c.KongAdminAPIConfig.AdminToken.Get())
+
+	t.Log("verifying that the token file can't be combined with a token")
+	require.Error(t, watchKongAdminTokenFile(ctx, logr.Discard(), &Config{KongAdminToken: "token", KongAdminTokenPath: path}))
+
+	t.Log("verifying that a missing token file is an error")
+	require.Error(t, watchKongAdminTokenFile(ctx, logr.Discard(), &Config{KongAdminTokenPath: filepath.Join(dir, "missing")}))
+}
@@ -0,0 +1,133 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane"
+)
+
+// benchmarkNamespace is the namespace of the synthetic objects of the
+// benchmark, which doesn't need to exist.
+const benchmarkNamespace = "kic-benchmark"
+
+// benchmarkClient is the part of the dataplane client the benchmark uses.
+type benchmarkClient interface {
+	UpdateObject(obj client.Object) error
+	Update(ctx context.Context) error
+	LastUpdateTimings() dataplane.UpdateTimings
+}
+
+// benchmark adds synthetic Ingresses to the configuration cache of the
+// dataplane client, then applies the configuration and logs how long
+// translating and applying it took, so that the scaling limits of the
+// controller can be measured without creating as many objects in a cluster.
+// The synthetic objects stay in the cache, so the following updates (which
+// can be profiled with --profiling) include them too. It runs once, when the
+// controller starts (or becomes the leader).
+type benchmark struct {
+	logger       logr.Logger
+	client       benchmarkClient
+	ingressClass string
+	ingresses    int
+}
+
+// Start adds the synthetic objects and runs the benchmark.
+func (b *benchmark) Start(ctx context.Context) error {
+	for i := 0; i < b.ingresses; i++ {
+		for _, obj := range benchmarkObjects(i, b.ingressClass) {
+			if err := b.client.UpdateObject(obj); err != nil {
+				return fmt.Errorf("failed to add synthetic object to the configuration: %w", err)
+			}
+		}
+	}
+	b.logger.Info("added synthetic objects to the configuration", "ingresses", b.ingresses, "namespace", benchmarkNamespace)
+
+	start := time.Now()
+	if err := b.client.Update(ctx); err != nil {
+		b.logger.Error(err, "failed to apply the configuration including the synthetic objects")
+		return nil
+	}
+	timings := b.client.LastUpdateTimings()
+	b.logger.Info("benchmark complete",
+		"ingresses", b.ingresses,
+		"total", time.Since(start).String(),
+		"translation", timings.Translation.String(),
+		"sync", timings.Sync.String(),
+	)
+	return nil
+}
+
+// benchmarkObjects provides the i-th synthetic Ingress, along with the
+// Service it routes to and the Endpoints and EndpointSlice of this Service.
+func benchmarkObjects(i int, ingressClass string) []client.Object {
+	name := fmt.Sprintf("benchmark-%d", i)
+	meta := func(kind string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Namespace:         benchmarkNamespace,
+			Name:              name,
+			UID:               types.UID(fmt.Sprintf("%s-%s-%s", kind, benchmarkNamespace, name)),
+			CreationTimestamp: metav1.Now(),
+		}
+	}
+	// each Service has a single endpoint, with a distinct address
+	n := i + 1
+	address := fmt.Sprintf("10.%d.%d.%d", (n>>16)&0xff, (n>>8)&0xff, n&0xff)
+	port := int32(80)
+
+	pathType := networkingv1.PathTypePrefix
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: meta("ingress"),
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: &ingressClass,
+			Rules: []networkingv1.IngressRule{{
+				Host: name + ".benchmark.example",
+				IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{{
+						Path:     "/",
+						PathType: &pathType,
+						Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+							Name: name,
+							Port: networkingv1.ServiceBackendPort{Number: port},
+						}},
+					}},
+				}},
+			}},
+		},
+	}
+	service := &corev1.Service{
+		ObjectMeta: meta("service"),
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{
+				Protocol:   corev1.ProtocolTCP,
+				Port:       port,
+				TargetPort: intstr.FromInt(int(port)),
+			}},
+		},
+	}
+	endpoints := &corev1.Endpoints{
+		ObjectMeta: meta("endpoints"),
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{{IP: address}},
+			Ports:     []corev1.EndpointPort{{Protocol: corev1.ProtocolTCP, Port: port}},
+		}},
+	}
+	endpointSlice := &discoveryv1.EndpointSlice{
+		ObjectMeta:  meta("endpointslice"),
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Endpoints:   []discoveryv1.Endpoint{{Addresses: []string{address}}},
+		Ports:       []discoveryv1.EndpointPort{{Port: &port}},
+	}
+	endpointSlice.Labels = map[string]string{discoveryv1.LabelServiceName: name}
+	return []client.Object{ingress, service, endpoints, endpointSlice}
+}
@@ -0,0 +1,72 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
+)
+
+type fakeBenchmarkClient struct {
+	objects []client.Object
+	updates int
+}
+
+func (c *fakeBenchmarkClient) UpdateObject(obj client.Object) error {
+	c.objects = append(c.objects, obj)
+	return nil
+}
+
+func (c *fakeBenchmarkClient) Update(context.Context) error {
+	c.updates++
+	return nil
+}
+
+func (c *fakeBenchmarkClient) LastUpdateTimings() dataplane.UpdateTimings {
+	return dataplane.UpdateTimings{Translation: time.Millisecond, Sync: time.Second}
+}
+
+func TestBenchmark(t *testing.T) {
+	c := &fakeBenchmarkClient{}
+	b := &benchmark{
+		logger:       logr.Discard(),
+		client:       c,
+		ingressClass: "kong",
+		ingresses:    3,
+	}
+	require.NoError(t, b.Start(context.Background()))
+	assert.Len(t, c.objects, 12, "each Ingress comes with a Service, Endpoints and an EndpointSlice")
+	assert.Equal(t, 1, c.updates)
+
+	t.Log("verifying that the synthetic objects translate into routes to their endpoints")
+	cache := store.NewCacheStores()
+	for _, obj := range c.objects {
+		require.NoError(t, cache.Add(obj))
+	}
+	for _, endpointSliceTargets := range []bool{false, true} {
+		p := parser.NewParser(logrus.New(), store.New(cache, "kong", false, false, false, logrus.New()))
+		if endpointSliceTargets {
+			p.EnableEndpointSliceTargets()
+		}
+		state, err := p.Build()
+		require.NoError(t, err)
+		assert.Len(t, state.Services, 3)
+		require.Len(t, state.Upstreams, 3)
+		targets := map[string]bool{}
+		for _, upstream := range state.Upstreams {
+			require.Len(t, upstream.Targets, 1)
+			targets[*upstream.Targets[0].Target.Target] = true
+		}
+		assert.Equal(t, map[string]bool{"10.0.0.1:80": true, "10.0.0.2:80": true, "10.0.0.3:80": true}, targets)
+		assert.Empty(t, p.PopTranslationFailures())
+	}
+}
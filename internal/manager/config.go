@@ -16,6 +16,8 @@ import (
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/admission"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/introspection"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
 )
 
 // -----------------------------------------------------------------------------
@@ -36,59 +38,105 @@ type Config struct {
 	KongAdminInitializationRetries    uint
 	KongAdminInitializationRetryDelay time.Duration
 	KongAdminToken                    string
+	KongAdminTokenPath                string
 	KongWorkspace                     string
 	AnonymousReports                  bool
 	EnableReverseSync                 bool
 	SyncPeriod                        time.Duration
+	SyncPeriodStagger                 time.Duration
 	SkipCACertificates                bool
 
 	// Kong Proxy configurations
-	APIServerHost            string
-	APIServerQPS             int
-	APIServerBurst           int
-	MetricsAddr              string
-	ProbeAddr                string
-	KongAdminURL             string
-	ProxySyncSeconds         float32
-	ProxyTimeoutSeconds      float32
-	KongCustomEntitiesSecret string
+	APIServerHost             string
+	APIServerQPS              int
+	APIServerBurst            int
+	MetricsAddr               string
+	ProbeAddr                 string
+	KongAdminURL              string
+	KongAdminPortForward      string
+	KongAdminTLSSecret        string
+	ProxySyncSeconds          float32
+	ProxyTimeoutSeconds       float32
+	TranslationTimeout        time.Duration
+	TranslationFailuresBudget int
+	KongCustomEntitiesSecret  string
+	ProxyProtocolPorts        []int
+	ProxyTrustedCIDRs         []string
+	ProxyDeployment           string
+	ProxyContainer            string
+	AppliedConfigConfigMap    string
+	ConfigSnapshotSecret      string
+	ConfigExportConfigMap     string
+	ConfigExportInterval      time.Duration
+	NamespaceQuotas           util.NamespaceQuotas
+	TopologyZone              string
+	DefaultCertificate        string
+
+	ClusterPluginSecretNamespaces []string
 
 	// Kubernetes configurations
 	KubeconfigPath          string
 	IngressClassName        string
 	EnableLeaderElection    bool
+	LeaderElectionMode      string
 	LeaderElectionNamespace string
 	LeaderElectionID        string
 	Concurrency             int
 	FilterTags              []string
+	GCPreviousFilterTags    []string
 	WatchNamespaces         []string
+	WatchNamespaceSelector  string
+	StaleFinalizers         []string
+	ShutdownFinalizers      []string
 
 	// Ingress status
 	PublishService       string
 	PublishStatusAddress []string
 	UpdateStatus         bool
 
+	// Stream ports provisioning
+	ProvisionStreamPorts            bool
+	ProvisionStreamListenDeployment string
+	ProvisionStreamListenContainer  string
+
 	// Kubernetes API toggling
-	IngressExtV1beta1Enabled bool
-	IngressNetV1beta1Enabled bool
-	IngressNetV1Enabled      bool
-	IngressClassNetV1Enabled bool
-	UDPIngressEnabled        bool
-	TCPIngressEnabled        bool
-	KongIngressEnabled       bool
-	KnativeIngressEnabled    bool
-	KongClusterPluginEnabled bool
-	KongPluginEnabled        bool
-	KongConsumerEnabled      bool
-	ServiceEnabled           bool
+	IngressExtV1beta1Enabled      bool
+	IngressNetV1beta1Enabled      bool
+	IngressNetV1Enabled           bool
+	IngressClassNetV1Enabled      bool
+	IngressClassParametersEnabled bool
+	UDPIngressEnabled             bool
+	TCPIngressEnabled             bool
+	KongIngressEnabled            bool
+	KnativeIngressEnabled         bool
+	ServiceImportEnabled          bool
+	KongClusterPluginEnabled      bool
+	KongPluginEnabled             bool
+	KongConsumerEnabled           bool
+	KongCACertificateEnabled      bool
+	KongUpstreamPolicyEnabled     bool
+	KongPluginBundleEnabled       bool
+	KongVaultEnabled              bool
+	ServiceEnabled                bool
+	CertManagerEnabled            bool
 
 	// Admission Webhook server config
 	AdmissionServer admission.ServerConfig
 
+	// Introspection API server config
+	IntrospectionAPI introspection.ServerConfig
+
 	// Diagnostics and performance
 	EnableProfiling     bool
 	EnableConfigDumps   bool
 	DumpSensitiveConfig bool
+	PropagationEvents   bool
+	BenchmarkObjects    int
+
+	// Tracing
+	TracingOTLPEndpoint  string
+	TracingOTLPHeaders   map[string]string
+	TracingSamplingRatio float64
 
 	// Feature Gates
 	FeatureGates map[string]bool
@@ -125,16 +173,19 @@ func (c *Config) FlagSet() *pflag.FlagSet {
 	flagSet.UintVar(&c.KongAdminInitializationRetries, "kong-admin-init-retries", 60, "Number of attempts that will be made initially on controller startup to connect to the Kong Admin API")
 	flagSet.DurationVar(&c.KongAdminInitializationRetryDelay, "kong-admin-init-retry-delay", time.Second*1, "The time delay between every attempt (on controller startup) to connect to the Kong Admin API")
 	flagSet.StringVar(&c.KongAdminToken, "kong-admin-token", "", `The Kong Enterprise RBAC token used by the controller.`)
+	flagSet.StringVar(&c.KongAdminTokenPath, "kong-admin-token-file", "", `Path to a file containing the Kong Enterprise RBAC token used by the controller, e.g. a key of a mounted Secret. The token is re-read when the file changes.`)
 	flagSet.StringVar(&c.KongWorkspace, "kong-workspace", "", "Kong Enterprise workspace to configure. Leave this empty if not using Kong workspaces.")
 	flagSet.BoolVar(&c.AnonymousReports, "anonymous-reports", true, `Send anonymized usage data to help improve Kong`)
 	flagSet.BoolVar(&c.EnableReverseSync, "enable-reverse-sync", false, `Send configuration to Kong even if the configuration checksum has not changed since previous update.`)
-	flagSet.DurationVar(&c.SyncPeriod, "sync-period", time.Hour*48, `Relist and confirm cloud resources this often`) // 48 hours derived from controller-runtime defaults
+	flagSet.DurationVar(&c.SyncPeriod, "sync-period", time.Hour*48, `Relist and confirm cloud resources this often. Set to 0 to disable periodic resyncs.`) // 48 hours derived from controller-runtime defaults
+	flagSet.DurationVar(&c.SyncPeriodStagger, "sync-period-stagger", 0, `Delay the periodic resync of each resource type by a random amount of up to this duration, so that they don't all happen at once. Set to 0 to disable staggering.`)
 	flagSet.BoolVar(&c.SkipCACertificates, "skip-ca-certificates", false, `disable syncing CA certificate syncing (for use with multi-workspace environments)`)
 
 	flagSet.StringVar(&c.KongAdminAPIConfig.TLSClientCertPath, "kong-admin-tls-client-cert-file", "", "mTLS client certificate file for authentication.")
 	flagSet.StringVar(&c.KongAdminAPIConfig.TLSClientKeyPath, "kong-admin-tls-client-key-file", "", "mTLS client key file for authentication.")
 	flagSet.StringVar(&c.KongAdminAPIConfig.TLSClientCert, "kong-admin-tls-client-cert", "", "mTLS client certificate for authentication.")
 	flagSet.StringVar(&c.KongAdminAPIConfig.TLSClientKey, "kong-admin-tls-client-key", "", "mTLS client key for authentication.")
+	flagSet.StringVar(&c.KongAdminTLSSecret, "kong-admin-tls-secret", "", `A Secret containing the mTLS client certificate and key (tls.crt and tls.key) and/or the CA certificate (ca.crt) used with Kong's Admin endpoint, in "namespace/name" format. The credentials are reloaded when the Secret changes.`)
 
 	// Kong Proxy and Proxy Cache configurations
 	flagSet.StringVar(&c.APIServerHost, "apiserver-host", "", `The Kubernetes API server URL. If not set, the controller will use cluster config discovery.`)
@@ -142,26 +193,106 @@ func (c *Config) FlagSet() *pflag.FlagSet {
 	flagSet.IntVar(&c.APIServerBurst, "apiserver-burst", 300, "The Kubernetes API RateLimiter maximum burst queries per second")
 	flagSet.StringVar(&c.MetricsAddr, "metrics-bind-address", fmt.Sprintf(":%v", MetricsPort), "The address the metric endpoint binds to.")
 	flagSet.StringVar(&c.ProbeAddr, "health-probe-bind-address", fmt.Sprintf(":%v", HealthzPort), "The address the probe endpoint binds to.")
-	flagSet.StringVar(&c.KongAdminURL, "kong-admin-url", "http://localhost:8001", `The Kong Admin URL to connect to in the format "protocol://address:port".`)
+	flagSet.StringVar(&c.KongAdminURL, "kong-admin-url", "http://localhost:8001", `The Kong Admin URL to connect to in the format "protocol://address:port".
+		Kong must run with the "traditional" or "traditional_compatible" router flavor, the "expressions" one is not supported.`)
+	flagSet.StringVar(&c.KongAdminPortForward, "kong-admin-port-forward", "",
+		`Development mode: port-forward to the given Kong Admin service of the cluster, in the format "namespace/name:port", `+
+			`and connect to it with the scheme of --kong-admin-url, to run the controller outside of the cluster.`)
 	flagSet.Float32Var(&c.ProxySyncSeconds, "proxy-sync-seconds", dataplane.DefaultSyncSeconds,
 		"Define the rate (in seconds) in which configuration updates will be applied to the Kong Admin API.",
 	)
 	flagSet.Float32Var(&c.ProxyTimeoutSeconds, "proxy-timeout-seconds", dataplane.DefaultTimeoutSeconds,
 		"Sets the timeout (in seconds) for all requests to Kong's Admin API.",
 	)
+	flagSet.DurationVar(&c.TranslationTimeout, "translation-timeout", dataplane.DefaultTranslationTimeout,
+		"Sets the deadline for translating Kubernetes objects into Kong configuration. Translations exceeding it are discarded and the last applied configuration is kept. Set to 0 to disable.",
+	)
+	flagSet.IntVar(&c.TranslationFailuresBudget, "translation-failures-budget", 0,
+		"Sets the number of Kubernetes objects which may fail translation into Kong configuration. When more do, the configuration is not applied and the last applied configuration is kept. Set to 0 to disable.",
+	)
 	flagSet.StringVar(&c.KongCustomEntitiesSecret, "kong-custom-entities-secret", "", `A Secret containing custom entities for DB-less mode, in "namespace/name" format`)
+	flagSet.IntSliceVar(&c.ProxyProtocolPorts, "proxy-protocol-ports", nil, `Ports of the proxy and stream listens of Kong
+			(e.g. those of TCPIngresses) which the load-balancers in front of the Kong proxy pass client IPs to with the PROXY
+			protocol instead of the X-Forwarded-For header. On startup the controller verifies that these listens accept the
+			PROXY protocol and that Kong reads client IPs from it, and logs the environment variables to set on the proxy
+			container if not, unless --proxy-deployment is set. Kong's trusted_ips must include the load-balancers for it
+			to use the client IPs they pass, see --proxy-trusted-cidrs.`)
+	flagSet.StringSliceVar(&c.ProxyTrustedCIDRs, "proxy-trusted-cidrs", nil, `CIDRs (or IPs) of the load-balancers in front of
+			the Kong proxy. On startup the controller verifies that Kong trusts the client IPs these load-balancers pass in the
+			X-Forwarded-For header (or with the PROXY protocol, see --proxy-protocol-ports) and logs the environment variables
+			to set on the proxy container if it does not, unless --proxy-deployment is set.`)
+	flagSet.StringVar(&c.ProxyDeployment, "proxy-deployment", "", `The Deployment running the Kong proxy, in "namespace/name"
+			format. The environment variables --proxy-trusted-cidrs and --proxy-protocol-ports require are set on its
+			--proxy-container container on startup, which rolls out its pods. The controller's RBAC must allow it to get
+			and patch the Deployment.`)
+	flagSet.StringVar(&c.ProxyContainer, "proxy-container", "proxy", `The name of the Kong proxy container of --proxy-deployment.`)
+	flagSet.StringVar(&c.AppliedConfigConfigMap, "applied-config-configmap", "", `A ConfigMap in "namespace/name" format
+			to record the checksum of the configuration applied to Kong, the time it was applied at and the controller
+			version in, after each successful update. Unless RBAC is adjusted, it must be in the controller's namespace.`)
+	flagSet.StringVar(&c.DefaultCertificate, "default-certificate", "", `A TLS Secret in "namespace/name" format
+			holding the certificate Kong serves for the SNIs no TLS section of an Ingress covers and to clients which
+			don't send SNI, unless the TLS section of an Ingress sets the default certificate.`)
+	flagSet.StringVar(&c.ConfigSnapshotSecret, "config-snapshot-secret", "", `A Secret in "namespace/name" format to
+			store the configuration applied to DB-less Kong in after each successful update. On startup, it's pushed to
+			Kong if Kong has no configuration yet, so that a freshly started Kong doesn't serve an empty configuration
+			until the controller has synced. Without leader election, a single instance of the controller, holding a Lease
+			named after the Secret in its namespace, stores it. Configurations which don't fit in the Secret, even gzipped,
+			are not stored. Requires permission to get, create and update the Secret, which the default RBAC rules don't
+			grant, and the Lease, which they only grant in the namespace of the controller.`)
+
+	flagSet.StringVar(&c.ConfigExportConfigMap, "config-export-configmap", "", `A ConfigMap in "namespace/name" format
+			to export the configuration applied to Kong to, in decK YAML format with credentials redacted, after
+			successful updates which changed it. Requires permission to get, create and update the ConfigMap, which the
+			default RBAC rules only grant in the controller's namespace.`)
+	flagSet.DurationVar(&c.ConfigExportInterval, "config-export-interval", time.Minute, `Minimum interval between
+			exports of the configuration to the --config-export-configmap ConfigMap.`)
+
+	flagSet.IntVar(&c.NamespaceQuotas.MaxRoutes, "namespace-max-routes", 0, `Maximum number of Kong routes the
+			objects of any single namespace may produce. Ingresses, HTTPRoutes, TCPIngresses and UDPIngresses adding routes
+			beyond it are rejected by the admission webhook, and routes exceeding it are dropped. Set to 0 to disable.`)
+	flagSet.IntVar(&c.NamespaceQuotas.MaxPlugins, "namespace-max-plugins", 0, `Maximum number of Kong plugins the
+			objects of any single namespace may produce, also limiting the KongPlugins admitted in a namespace.
+			Plugins exceeding it are dropped. Set to 0 to disable.`)
+	flagSet.IntVar(&c.NamespaceQuotas.MaxConsumers, "namespace-max-consumers", 0, `Maximum number of KongConsumers in any
+			single namespace. Consumers exceeding it are rejected by the admission webhook and dropped. Set to 0 to disable.`)
+	flagSet.StringVar(&c.TopologyZone, "topology-zone", "", `Zone of the Kong proxy. When the targets of Services are
+			generated from their EndpointSlices, only the endpoints in this zone (or hinted for it) are targeted, unless there are none.
+			The same configuration is sent to every Kong proxy this controller configures, so all of them must run in this zone:
+			deploy one controller and set of proxies (and, in DB mode, one database) per zone. The externalTrafficPolicy and
+			internalTrafficPolicy of Services are not taken into account.`)
+	flagSet.StringSliceVar(&c.ClusterPluginSecretNamespaces, "kong-cluster-plugin-secret-namespace", nil, `Namespace(s) of the Secrets
+			which KongClusterPlugins may reference for their configuration. Defaults to any namespace. When watching specific
+			namespaces, these are watched too. To allow multiple namespaces, use a comma-separated list of namespaces.`)
 
 	// Kubernetes configurations
 	flagSet.StringVar(&c.KubeconfigPath, "kubeconfig", "", "Path to the kubeconfig file.")
 	flagSet.StringVar(&c.IngressClassName, "ingress-class", annotations.DefaultIngressClass, `Name of the ingress class to route through this controller.`)
-	flagSet.BoolVar(&c.EnableLeaderElection, "leader-elect", false, "DEPRECATED as of 2.1.0 leader election behavior is determined automatically and this flag has no effect")
+	flagSet.BoolVar(&c.EnableLeaderElection, "leader-elect", false, "DEPRECATED as of 2.1.0 leader election behavior is set with --election-mode and this flag has no effect")
+	flagSet.StringVar(&c.LeaderElectionMode, "election-mode", leaderElectionModeAuto, `Whether only the elected leader
+		among the instances of the controller applies the configuration to Kong: "enabled", "disabled" (e.g. for DB-less
+		Kong instances each configured by their own controller), or "auto" to enable it with a database only. Standby
+		instances keep building the configuration, to take over quickly.`)
 	flagSet.StringVar(&c.LeaderElectionID, "election-id", "5b374a9e.konghq.com", `Election id to use for status update.`)
 	flagSet.StringVar(&c.LeaderElectionNamespace, "election-namespace", "", `Leader election namespace to use when running outside a cluster`)
 	flagSet.StringSliceVar(&c.FilterTags, "kong-admin-filter-tag", []string{"managed-by-ingress-controller"}, "The tag used to manage and filter entities in Kong. This flag can be specified multiple times to specify multiple tags. This setting will be silently ignored if the Kong instance has no tags support.")
+	flagSet.StringSliceVar(&c.GCPreviousFilterTags, "kong-admin-gc-previous-filter-tag", nil, "A tag the entities were managed with in Kong before --kong-admin-filter-tag changed. In DB mode, the routes, services, upstreams and consumers carrying all these tags but not all the current filter tags, which the sync leaves behind, are deleted if they aren't part of the configuration and their k8s-uid tag identifies a Kubernetes object the controller doesn't know of anymore. Entities without a k8s-uid tag are never deleted. This flag can be specified multiple times to specify multiple tags.")
 	flagSet.IntVar(&c.Concurrency, "kong-admin-concurrency", 10, "Max number of concurrent requests sent to Kong's Admin API.")
 	flagSet.StringSliceVar(&c.WatchNamespaces, "watch-namespace", nil,
 		`Namespace(s) to watch for Kubernetes resources. Defaults to all namespaces. To watch multiple namespaces, use
 		a comma-separated list of namespaces.`)
+	flagSet.StringVar(&c.WatchNamespaceSelector, "watch-namespace-selector", "",
+		`Label selector of the namespaces to watch for Kubernetes resources, in addition to those set with
+		--watch-namespace. The matching namespaces are resolved on startup, and the controller restarts when they change.
+		Requires permission to list namespaces, which the default RBAC rules don't grant.`)
+	flagSet.StringSliceVar(&c.StaleFinalizers, "remove-stale-finalizers", nil,
+		`Finalizer(s) left by previous controller versions or instances to remove from the watched resources on
+		startup, e.g. when they block the deletion of namespaces. Only konghq.com finalizers can be removed. Requires
+		permission to patch the watched resources, which the default RBAC rules don't grant.`)
+	flagSet.StringSliceVar(&c.ShutdownFinalizers, "shutdown-remove-finalizers", nil,
+		`Finalizer(s) of the controller to remove from the watched resources when it shuts down, after the final update
+		of Kong, so that resources deleted while no controller runs (e.g. during rolling updates) aren't stranded. Only
+		konghq.com finalizers can be removed. Requires permission to patch the watched resources, which the default RBAC
+		rules don't grant.`)
 
 	// Ingress status
 	flagSet.StringVar(&c.PublishService, "publish-service", "", `Service fronting Ingress resources in "namespace/name"
@@ -172,19 +303,41 @@ func (c *Config) FlagSet() *pflag.FlagSet {
 	flagSet.BoolVar(&c.UpdateStatus, "update-status", true,
 		`Indicates if the ingress controller should update the status of resources (e.g. IP/Hostname for v1.Ingress, e.t.c.)`)
 
+	// Stream ports provisioning
+	flagSet.BoolVar(&c.ProvisionStreamPorts, "provision-stream-ports", false, `Open the ports the TCPIngresses and
+		UDPIngresses claim on the Service given with --publish-service, adding a port named kic-<protocol>-<port> to it
+		for each port it doesn't expose yet and removing those ports once they aren't claimed anymore. Requires
+		permission to patch the Service, which the default RBAC rules don't grant.`)
+	flagSet.StringVar(&c.ProvisionStreamListenDeployment, "provision-stream-listen-deployment", "", `Kong Deployment
+		in "namespace/name" format whose proxy container gets a stream listener added to its KONG_STREAM_LISTEN for each
+		port the TCPIngresses and UDPIngresses claim which it doesn't listen on yet, rolling the Deployment out. Listeners
+		are never removed. Requires --provision-stream-ports, and permission to get and patch the Deployment, which the
+		default RBAC rules don't grant.`)
+	flagSet.StringVar(&c.ProvisionStreamListenContainer, "provision-stream-listen-container", "proxy",
+		`Name of the Kong proxy container of the Deployment given with --provision-stream-listen-deployment.`)
+
 	// Kubernetes API toggling
 	flagSet.BoolVar(&c.IngressNetV1Enabled, "enable-controller-ingress-networkingv1", true, "Enable the networking.k8s.io/v1 Ingress controller.")
 	flagSet.BoolVar(&c.IngressClassNetV1Enabled, "enable-controller-ingress-class-networkingv1", true, "Enable the networking.k8s.io/v1 IngressClass controller.")
+	flagSet.BoolVar(&c.IngressClassParametersEnabled, "enable-controller-ingress-class-parameters", true, "Enable the IngressClassParameters controller.")
 	flagSet.BoolVar(&c.IngressNetV1beta1Enabled, "enable-controller-ingress-networkingv1beta1", true, "Enable the networking.k8s.io/v1beta1 Ingress controller.")
 	flagSet.BoolVar(&c.IngressExtV1beta1Enabled, "enable-controller-ingress-extensionsv1beta1", true, "Enable the extensions/v1beta1 Ingress controller.")
 	flagSet.BoolVar(&c.UDPIngressEnabled, "enable-controller-udpingress", true, "Enable the UDPIngress controller.")
 	flagSet.BoolVar(&c.TCPIngressEnabled, "enable-controller-tcpingress", true, "Enable the TCPIngress controller.")
 	flagSet.BoolVar(&c.KnativeIngressEnabled, "enable-controller-knativeingress", true, "Enable the KnativeIngress controller.")
+	flagSet.BoolVar(&c.ServiceImportEnabled, "enable-controller-serviceimport", true, "Enable the ServiceImport controller.")
 	flagSet.BoolVar(&c.KongIngressEnabled, "enable-controller-kongingress", true, "Enable the KongIngress controller.")
 	flagSet.BoolVar(&c.KongClusterPluginEnabled, "enable-controller-kongclusterplugin", true, "Enable the KongClusterPlugin controller.")
 	flagSet.BoolVar(&c.KongPluginEnabled, "enable-controller-kongplugin", true, "Enable the KongPlugin controller.")
 	flagSet.BoolVar(&c.KongConsumerEnabled, "enable-controller-kongconsumer", true, "Enable the KongConsumer controller. ")
+	flagSet.BoolVar(&c.KongCACertificateEnabled, "enable-controller-kongcacertificate", true, "Enable the KongCACertificate controller.")
+	flagSet.BoolVar(&c.KongUpstreamPolicyEnabled, "enable-controller-kongupstreampolicy", true, "Enable the KongUpstreamPolicy controller.")
+	flagSet.BoolVar(&c.KongPluginBundleEnabled, "enable-controller-kongpluginbundle", true, "Enable the KongPluginBundle controller.")
+	flagSet.BoolVar(&c.KongVaultEnabled, "enable-controller-kongvault", true, "Enable the KongVault controller.")
 	flagSet.BoolVar(&c.ServiceEnabled, "enable-controller-service", true, "Enable the Service controller.")
+	flagSet.BoolVar(&c.CertManagerEnabled, "enable-controller-cert-manager", false, `Enable the controllers creating
+		cert-manager Certificates for the hosts lacking TLS of the Ingresses and HTTPRoutes annotated with
+		konghq.com/cert-manager-issuer or konghq.com/cert-manager-cluster-issuer.`)
 
 	// Admission Webhook server config
 	flagSet.StringVar(&c.AdmissionServer.ListenAddr, "admission-webhook-listen", "off",
@@ -200,10 +353,37 @@ func (c *Config) FlagSet() *pflag.FlagSet {
 	flagSet.StringVar(&c.AdmissionServer.Key, "admission-webhook-key", "",
 		`admission server PEM private key value`)
 
+	// Introspection API server config
+	flagSet.StringVar(&c.IntrospectionAPI.ListenAddr, "introspection-api-listen", "off",
+		`The address to serve the introspection API on (ip:port), which exposes the services, routes and plugins `+
+			`of the applied configuration and the Kubernetes objects they were translated from. Standby instances respond with 503 `+
+			`until they're elected leader, as they don't apply the configuration. Setting it to 'off' disables it.`)
+	flagSet.StringVar(&c.IntrospectionAPI.TokenPath, "introspection-api-token-file", "",
+		`Path to a file holding the token clients of the introspection API must send as a bearer token.`)
+	flagSet.StringVar(&c.IntrospectionAPI.CertPath, "introspection-api-cert-file", "",
+		`introspection API server PEM certificate file path; the API is served over plain HTTP if unset.`)
+	flagSet.StringVar(&c.IntrospectionAPI.KeyPath, "introspection-api-key-file", "",
+		`introspection API server PEM private key file path.`)
+
 	// Diagnostics
 	flagSet.BoolVar(&c.EnableProfiling, "profiling", false, fmt.Sprintf("Enable profiling via web interface host:%v/debug/pprof/", DiagnosticsPort))
-	flagSet.BoolVar(&c.EnableConfigDumps, "dump-config", false, fmt.Sprintf("Enable config dumps via web interface host:%v/debug/config", DiagnosticsPort))
-	flagSet.BoolVar(&c.DumpSensitiveConfig, "dump-sensitive-config", false, "Include credentials and TLS secrets in configs exposed with --dump-config")
+	flagSet.BoolVar(&c.EnableConfigDumps, "dump-config", false, fmt.Sprintf("Enable config dumps via web interface host:%v/debug/config/{successful,failed,diff,deck}", DiagnosticsPort))
+	flagSet.BoolVar(&c.DumpSensitiveConfig, "dump-sensitive-config", false, "Include credentials and TLS secrets in configs exposed with --dump-config and in the per-entity diffs logged at debug level")
+	flagSet.BoolVar(&c.PropagationEvents, "propagation-events", false,
+		"Emit an Event for each change to a Kubernetes object once it is applied to Kong, reporting how long it took")
+	flagSet.IntVar(&c.BenchmarkObjects, "benchmark-objects", 0,
+		"Add this many synthetic Ingresses, along with their Services and Endpoints, to the configuration at startup, "+
+			"and log how long translating and applying it takes, to measure how the controller scales. "+
+			"The synthetic Ingresses are applied to Kong: only use with a test data-plane.")
+
+	// Tracing
+	flagSet.StringVar(&c.TracingOTLPEndpoint, "tracing-otlp-endpoint", "", `URL of the OTLP/HTTP traces endpoint of an
+		OpenTelemetry collector (e.g. http://otel-collector:4318/v1/traces) to export the spans of reconciliations,
+		translations and configuration updates to. Tracing is disabled if unset.`)
+	flagSet.StringToStringVar(&c.TracingOTLPHeaders, "tracing-otlp-headers", nil, `Headers to send with the spans
+		exported to the OTLP endpoint, in key=value format (e.g. to authenticate to the collector).`)
+	flagSet.Float64Var(&c.TracingSamplingRatio, "tracing-sampling-ratio", 1, `Ratio of traces to export to the OTLP
+		endpoint, between 0 and 1.`)
 
 	// Feature Gates (see FEATURE_GATES.md)
 	flagSet.Var(cliflag.NewMapStringBool(&c.FeatureGates), "feature-gates", "A set of key=value pairs that describe feature gates for alpha/beta/experimental features. "+
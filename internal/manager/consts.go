@@ -1,5 +1,7 @@
 package manager
 
+import "time"
+
 // -----------------------------------------------------------------------------
 // Controller Manager - Constants & Vars
 // -----------------------------------------------------------------------------
@@ -19,3 +21,18 @@ const MetricsPort = 10255
 
 // DiagnosticsPort is the default port of the manager's diagnostics service listens on.
 const DiagnosticsPort = 10256
+
+// KongClientEventRecorderComponentName is the name used by the data-plane client
+// as the source of the Events it emits for Kubernetes objects.
+const KongClientEventRecorderComponentName = "kong-client"
+
+// tracingShutdownTimeout is how long the manager waits for the remaining spans
+// to be exported when it exits.
+const tracingShutdownTimeout = 5 * time.Second
+
+// Values of the --election-mode flag.
+const (
+	leaderElectionModeAuto     = "auto"
+	leaderElectionModeEnabled  = "enabled"
+	leaderElectionModeDisabled = "disabled"
+)
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 
+	discoveryv1 "k8s.io/api/discovery/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	knativev1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -11,12 +12,15 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
+	mcsv1alpha1 "github.com/kong/kubernetes-ingress-controller/v2/internal/apis/multicluster/v1alpha1"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/controllers/configuration"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/controllers/gateway"
 	ctrlutils "github.com/kong/kubernetes-ingress-controller/v2/internal/controllers/utils"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util/kubernetes/object/status"
 	konghqcomv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+	konghqcomv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
 )
 
 // -----------------------------------------------------------------------------
@@ -154,6 +158,21 @@ func setupControllers(
 				DataplaneClient: dataplaneClient,
 			},
 		},
+		{
+			Enabled: c.ServiceEnabled,
+			AutoHandler: crdExistsChecker{
+				GVR: schema.GroupVersionResource{
+					Group:    discoveryv1.SchemeGroupVersion.Group,
+					Version:  discoveryv1.SchemeGroupVersion.Version,
+					Resource: "endpointslices",
+				}}.CRDExists,
+			Controller: &configuration.DiscoveryV1EndpointSliceReconciler{
+				Client:          mgr.GetClient(),
+				Log:             ctrl.Log.WithName("controllers").WithName("EndpointSlices"),
+				Scheme:          mgr.GetScheme(),
+				DataplaneClient: dataplaneClient,
+			},
+		},
 		{
 			Enabled: true,
 			Controller: &configuration.CoreV1SecretReconciler{
@@ -237,9 +256,109 @@ func setupControllers(
 				DisableIngressClassLookups: !c.IngressClassNetV1Enabled,
 			},
 		},
+		{
+			Enabled: c.KongCACertificateEnabled,
+			AutoHandler: crdExistsChecker{GVR: schema.GroupVersionResource{
+				Group:    konghqcomv1beta1.SchemeGroupVersion.Group,
+				Version:  konghqcomv1beta1.SchemeGroupVersion.Version,
+				Resource: "kongcacertificates",
+			}}.CRDExists,
+			Controller: &configuration.KongV1Beta1KongCACertificateReconciler{
+				Client:                     mgr.GetClient(),
+				Log:                        ctrl.Log.WithName("controllers").WithName("KongCACertificate"),
+				Scheme:                     mgr.GetScheme(),
+				DataplaneClient:            dataplaneClient,
+				IngressClassName:           c.IngressClassName,
+				DisableIngressClassLookups: !c.IngressClassNetV1Enabled,
+			},
+		},
+		{
+			Enabled: c.KongUpstreamPolicyEnabled,
+			AutoHandler: crdExistsChecker{GVR: schema.GroupVersionResource{
+				Group:    konghqcomv1beta1.SchemeGroupVersion.Group,
+				Version:  konghqcomv1beta1.SchemeGroupVersion.Version,
+				Resource: "kongupstreampolicies",
+			}}.CRDExists,
+			Controller: &configuration.KongV1Beta1KongUpstreamPolicyReconciler{
+				Client:          mgr.GetClient(),
+				Log:             ctrl.Log.WithName("controllers").WithName("KongUpstreamPolicy"),
+				Scheme:          mgr.GetScheme(),
+				DataplaneClient: dataplaneClient,
+			},
+		},
+		{
+			Enabled: c.IngressClassParametersEnabled,
+			AutoHandler: crdExistsChecker{GVR: schema.GroupVersionResource{
+				Group:    konghqcomv1beta1.SchemeGroupVersion.Group,
+				Version:  konghqcomv1beta1.SchemeGroupVersion.Version,
+				Resource: "ingressclassparameterses",
+			}}.CRDExists,
+			Controller: &configuration.KongV1Beta1IngressClassParametersReconciler{
+				Client:          mgr.GetClient(),
+				Log:             ctrl.Log.WithName("controllers").WithName("IngressClassParameters"),
+				Scheme:          mgr.GetScheme(),
+				DataplaneClient: dataplaneClient,
+			},
+		},
+		{
+			Enabled: c.KongPluginBundleEnabled,
+			AutoHandler: crdExistsChecker{GVR: schema.GroupVersionResource{
+				Group:    konghqcomv1beta1.SchemeGroupVersion.Group,
+				Version:  konghqcomv1beta1.SchemeGroupVersion.Version,
+				Resource: "kongpluginbundles",
+			}}.CRDExists,
+			Controller: &configuration.KongV1Beta1KongPluginBundleReconciler{
+				Client:          mgr.GetClient(),
+				Log:             ctrl.Log.WithName("controllers").WithName("KongPluginBundle"),
+				Scheme:          mgr.GetScheme(),
+				DataplaneClient: dataplaneClient,
+			},
+		},
+		{
+			Enabled: c.KongVaultEnabled,
+			AutoHandler: crdExistsChecker{GVR: schema.GroupVersionResource{
+				Group:    konghqcomv1beta1.SchemeGroupVersion.Group,
+				Version:  konghqcomv1beta1.SchemeGroupVersion.Version,
+				Resource: "kongvaults",
+			}}.CRDExists,
+			Controller: &configuration.KongV1Beta1KongVaultReconciler{
+				Client:                     mgr.GetClient(),
+				Log:                        ctrl.Log.WithName("controllers").WithName("KongVault"),
+				Scheme:                     mgr.GetScheme(),
+				DataplaneClient:            dataplaneClient,
+				IngressClassName:           c.IngressClassName,
+				DisableIngressClassLookups: !c.IngressClassNetV1Enabled,
+			},
+		},
 		// ---------------------------------------------------------------------------
 		// Other Controllers
 		// ---------------------------------------------------------------------------
+		{
+			// static --publish-status-address overrides never change, so the
+			// publish service only needs to be watched when it's the address source.
+			Enabled: c.UpdateStatus && c.PublishService != "" && len(c.PublishStatusAddress) == 0,
+			Controller: &configuration.PublishServiceStatusReconciler{
+				Client:                 mgr.GetClient(),
+				Log:                    ctrl.Log.WithName("controllers").WithName("PublishServiceStatus"),
+				Scheme:                 mgr.GetScheme(),
+				DataplaneClient:        dataplaneClient,
+				DataplaneAddressFinder: dataplaneAddressFinder,
+				PublishService:         c.PublishService,
+			},
+		},
+		{
+			Enabled: c.ProvisionStreamPorts,
+			Controller: &configuration.StreamPortsReconciler{
+				Client:           mgr.GetClient(),
+				Log:              ctrl.Log.WithName("controllers").WithName("StreamPorts"),
+				Scheme:           mgr.GetScheme(),
+				IngressClassName: c.IngressClassName,
+				PublishService:   c.PublishService,
+				Deployment:       c.ProvisionStreamListenDeployment,
+				Container:        c.ProvisionStreamListenContainer,
+				APIReader:        mgr.GetAPIReader(),
+			},
+		},
 		{
 			// knative is a special case because it existed before we added feature gates functionality
 			// for this controller (only) the existing --enable-controller-knativeingress flag overrides
@@ -261,6 +380,42 @@ func setupControllers(
 				DataplaneAddressFinder:     dataplaneAddressFinder,
 			},
 		},
+		{
+			Enabled:     c.CertManagerEnabled && c.IngressNetV1Enabled,
+			AutoHandler: crdExistsChecker{GVR: certManagerCertificatesGVR}.CRDExists,
+			Controller: &configuration.CertManagerIngressReconciler{
+				Client:           mgr.GetClient(),
+				Log:              ctrl.Log.WithName("controllers").WithName("CertManagerIngress"),
+				Scheme:           mgr.GetScheme(),
+				IngressClassName: c.IngressClassName,
+			},
+		},
+		{
+			Enabled:     c.CertManagerEnabled && featureGates[gatewayFeature],
+			AutoHandler: crdExistsChecker{GVR: certManagerCertificatesGVR}.CRDExists,
+			Controller: &configuration.CertManagerHTTPRouteReconciler{
+				Client: mgr.GetClient(),
+				Log:    ctrl.Log.WithName("controllers").WithName("CertManagerHTTPRoute"),
+				Scheme: mgr.GetScheme(),
+			},
+		},
+		// ---------------------------------------------------------------------------
+		// Multi-Cluster Services API Controllers
+		// ---------------------------------------------------------------------------
+		{
+			Enabled: c.ServiceImportEnabled,
+			AutoHandler: crdExistsChecker{GVR: schema.GroupVersionResource{
+				Group:    mcsv1alpha1.SchemeGroupVersion.Group,
+				Version:  mcsv1alpha1.SchemeGroupVersion.Version,
+				Resource: "serviceimports",
+			}}.CRDExists,
+			Controller: &configuration.MCSV1Alpha1ServiceImportReconciler{
+				Client:          mgr.GetClient(),
+				Log:             ctrl.Log.WithName("controllers").WithName("ServiceImport"),
+				Scheme:          mgr.GetScheme(),
+				DataplaneClient: dataplaneClient,
+			},
+		},
 		// ---------------------------------------------------------------------------
 		// GatewayAPI Controllers
 		// ---------------------------------------------------------------------------
@@ -361,6 +516,14 @@ func setupControllers(
 	return controllers, nil
 }
 
+// certManagerCertificatesGVR is the resource type of cert-manager Certificates,
+// whose controllers only run when cert-manager is installed.
+var certManagerCertificatesGVR = schema.GroupVersionResource{
+	Group:    util.CertManagerCertificateGVK.Group,
+	Version:  util.CertManagerCertificateGVK.Version,
+	Resource: "certificates",
+}
+
 // crdExistsChecker verifies whether the resource type defined by GVR is supported by the k8s apiserver.
 type crdExistsChecker struct {
 	GVR schema.GroupVersionResource
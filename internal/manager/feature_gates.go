@@ -22,6 +22,21 @@ const (
 	// objects like Ingress instead of creating a route per path.
 	combinedRoutesFeature = "CombinedRoutes"
 
+	// fallbackConfigurationFeature is the name of the feature-gate for excluding
+	// Kubernetes objects whose configuration is rejected by Kong and applying
+	// the remaining valid configuration instead of none at all.
+	fallbackConfigurationFeature = "FallbackConfiguration"
+
+	// endpointSliceTargetsFeature is the name of the feature-gate for generating
+	// the targets of all Services from their EndpointSlices, taking the ready,
+	// serving and terminating conditions of their endpoints into account.
+	endpointSliceTargetsFeature = "EndpointSliceTargets"
+
+	// partialSyncFeature is the name of the feature-gate for excluding the
+	// Kubernetes objects which can't be fully translated from the configuration
+	// instead of applying what could be translated of them.
+	partialSyncFeature = "PartialSync"
+
 	// featureGatesDocsURL provides a link to the documentation for feature gates in the KIC repository
 	featureGatesDocsURL = "https://github.com/Kong/kubernetes-ingress-controller/blob/main/FEATURE_GATES.md"
 )
@@ -51,8 +66,11 @@ func setupFeatureGates(setupLog logr.Logger, c *Config) (map[string]bool, error)
 // NOTE: if you're adding a new feature gate, it needs to be added here.
 func getFeatureGatesDefaults() map[string]bool {
 	return map[string]bool{
-		knativeFeature:        false,
-		gatewayFeature:        false,
-		combinedRoutesFeature: false,
+		knativeFeature:               false,
+		gatewayFeature:               false,
+		combinedRoutesFeature:        false,
+		fallbackConfigurationFeature: false,
+		endpointSliceTargetsFeature:  false,
+		partialSyncFeature:           false,
 	}
 }
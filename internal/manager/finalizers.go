@@ -0,0 +1,144 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	knativev1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	konghqcomv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+	configurationv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
+)
+
+// staleFinalizerKinds are the kinds of the objects the controller reconciles,
+// which finalizers can be left on by previous versions or instances of it.
+var staleFinalizerKinds = []schema.GroupVersionKind{
+	networkingv1.SchemeGroupVersion.WithKind("Ingress"),
+	corev1.SchemeGroupVersion.WithKind("Service"),
+	konghqcomv1.SchemeGroupVersion.WithKind("KongPlugin"),
+	konghqcomv1.SchemeGroupVersion.WithKind("KongClusterPlugin"),
+	konghqcomv1.SchemeGroupVersion.WithKind("KongConsumer"),
+	konghqcomv1.SchemeGroupVersion.WithKind("KongIngress"),
+	configurationv1beta1.SchemeGroupVersion.WithKind("TCPIngress"),
+	configurationv1beta1.SchemeGroupVersion.WithKind("UDPIngress"),
+	knativev1alpha1.SchemeGroupVersion.WithKind("Ingress"),
+	gatewayv1alpha2.SchemeGroupVersion.WithKind("Gateway"),
+	gatewayv1alpha2.SchemeGroupVersion.WithKind("HTTPRoute"),
+	gatewayv1alpha2.SchemeGroupVersion.WithKind("TCPRoute"),
+	gatewayv1alpha2.SchemeGroupVersion.WithKind("UDPRoute"),
+	gatewayv1alpha2.SchemeGroupVersion.WithKind("TLSRoute"),
+}
+
+// validateStaleFinalizers checks that the finalizers to remove are Kong
+// finalizers, so that the finalizers of other controllers can't be removed
+// by mistake.
+func validateStaleFinalizers(finalizers []string) error {
+	for _, finalizer := range finalizers {
+		if errs := validation.IsQualifiedName(finalizer); len(errs) > 0 {
+			return fmt.Errorf("invalid finalizer %q: %s", finalizer, strings.Join(errs, ", "))
+		}
+		domain := strings.SplitN(finalizer, "/", 2)[0]
+		if !strings.Contains(finalizer, "/") || (domain != "konghq.com" && !strings.HasSuffix(domain, ".konghq.com")) {
+			return fmt.Errorf("invalid finalizer %q: only konghq.com finalizers can be removed", finalizer)
+		}
+	}
+	return nil
+}
+
+// staleFinalizerCollector removes the finalizers left by previous versions or
+// instances of the controller from the objects it reconciles, which would
+// otherwise block the deletion of these objects (and of their namespaces)
+// once no controller handles the finalizers anymore. It runs once, when the
+// controller starts (or becomes the leader).
+type staleFinalizerCollector struct {
+	logger     logr.Logger
+	reader     client.Reader
+	client     client.Client
+	finalizers []string
+	namespaces []string
+	kinds      []schema.GroupVersionKind
+}
+
+// Start removes the stale finalizers. Failures are only logged, as the stale
+// finalizers don't prevent the controller from working.
+func (c *staleFinalizerCollector) Start(ctx context.Context) error {
+	namespaces := c.namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{corev1.NamespaceAll}
+	}
+	removed := 0
+	for _, gvk := range c.kinds {
+		for _, namespace := range namespaces {
+			n, err := c.collect(ctx, gvk, namespace)
+			removed += n
+			if meta.IsNoMatchError(err) {
+				c.logger.V(1).Info("skipping kind which isn't installed", "kind", gvk.String())
+				break
+			}
+			if err != nil {
+				c.logger.Error(err, "failed to remove stale finalizers", "kind", gvk.String(), "namespace", namespace)
+			}
+		}
+	}
+	c.logger.Info("removed stale finalizers", "objects", removed)
+	return nil
+}
+
+// collect removes the stale finalizers from the objects of a kind in a
+// namespace, returning the number of objects they were removed from.
+func (c *staleFinalizerCollector) collect(ctx context.Context, gvk schema.GroupVersionKind, namespace string) (int, error) {
+	list := &metav1.PartialObjectMetadataList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := c.reader.List(ctx, list, client.InNamespace(namespace)); err != nil {
+		return 0, err
+	}
+	removed := 0
+	for i := range list.Items {
+		obj := &list.Items[i]
+		obj.SetGroupVersionKind(gvk)
+		finalizers := withoutFinalizers(obj.GetFinalizers(), c.finalizers)
+		if len(finalizers) == len(obj.GetFinalizers()) {
+			continue
+		}
+		// the optimistic lock prevents overwriting the finalizers other
+		// controllers may have changed in the meantime
+		patch := client.MergeFromWithOptions(obj.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		obj.SetFinalizers(finalizers)
+		if err := c.client.Patch(ctx, obj, patch); err != nil {
+			c.logger.Error(err, "failed to remove stale finalizers",
+				"kind", gvk.String(), "namespace", obj.Namespace, "name", obj.Name)
+			continue
+		}
+		c.logger.V(1).Info("removed stale finalizers", "kind", gvk.String(), "namespace", obj.Namespace, "name", obj.Name)
+		removed++
+	}
+	return removed, nil
+}
+
+// withoutFinalizers provides the finalizers which aren't stale.
+func withoutFinalizers(finalizers, stale []string) []string {
+	var result []string
+	for _, finalizer := range finalizers {
+		isStale := false
+		for _, s := range stale {
+			if finalizer == s {
+				isStale = true
+				break
+			}
+		}
+		if !isStale {
+			result = append(result, finalizer)
+		}
+	}
+	return result
+}
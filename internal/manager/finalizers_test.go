@@ -0,0 +1,64 @@
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestValidateStaleFinalizers(t *testing.T) {
+	assert.NoError(t, validateStaleFinalizers([]string{"konghq.com/cleanup", "configuration.konghq.com/plugins"}))
+	assert.Error(t, validateStaleFinalizers([]string{"cleanup"}))
+	assert.Error(t, validateStaleFinalizers([]string{"kubernetes.io/pv-protection"}))
+	assert.Error(t, validateStaleFinalizers([]string{"notkonghq.com/cleanup"}))
+	assert.Error(t, validateStaleFinalizers([]string{"konghq.com/"}))
+}
+
+func TestStaleFinalizerCollector(t *testing.T) {
+	service := func(namespace, name string, finalizers ...string) *corev1.Service {
+		return &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Finalizers: finalizers}}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		service("default", "stale", "konghq.com/cleanup"),
+		service("default", "mixed", "example.com/other", "konghq.com/cleanup"),
+		service("default", "other", "example.com/other"),
+		service("unwatched", "stale", "konghq.com/cleanup"),
+	).Build()
+
+	collector := &staleFinalizerCollector{
+		logger:     logr.Discard(),
+		reader:     c,
+		client:     c,
+		finalizers: []string{"konghq.com/cleanup"},
+		namespaces: []string{"default"},
+		kinds: []schema.GroupVersionKind{
+			corev1.SchemeGroupVersion.WithKind("Service"),
+			// not installed, skipped
+			{Group: "example.com", Version: "v1", Kind: "Missing"},
+		},
+	}
+	require.NoError(t, collector.Start(context.Background()))
+
+	for _, tt := range []struct {
+		namespace, name string
+		finalizers      []string
+	}{
+		{"default", "stale", nil},
+		{"default", "mixed", []string{"example.com/other"}},
+		{"default", "other", []string{"example.com/other"}},
+		{"unwatched", "stale", []string{"konghq.com/cleanup"}},
+	} {
+		svc := &corev1.Service{}
+		require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: tt.namespace, Name: tt.name}, svc))
+		assert.Equal(t, tt.finalizers, svc.Finalizers, "finalizers of %s/%s", tt.namespace, tt.name)
+	}
+}
@@ -0,0 +1,107 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// -----------------------------------------------------------------------------
+// Controller Manager - Namespace Selector
+// -----------------------------------------------------------------------------
+
+// namespaceSelectorPollInterval is how often the namespaces matching the
+// --watch-namespace-selector label selector are checked for changes.
+const namespaceSelectorPollInterval = 30 * time.Second
+
+// selectNamespaces provides the sorted names of the namespaces matching a
+// label selector.
+func selectNamespaces(ctx context.Context, reader client.Reader, selector labels.Selector) ([]string, error) {
+	list := &corev1.NamespaceList{}
+	if err := reader.List(ctx, list, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+	namespaces := make([]string, 0, len(list.Items))
+	for _, ns := range list.Items {
+		namespaces = append(namespaces, ns.Name)
+	}
+	sort.Strings(namespaces)
+	return namespaces, nil
+}
+
+// withNamespaces adds namespaces to a list of namespaces, skipping those
+// already in it.
+func withNamespaces(namespaces []string, added ...string) []string {
+	seen := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		seen[ns] = true
+	}
+	for _, ns := range added {
+		if !seen[ns] {
+			seen[ns] = true
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
+
+// namespaceSelectorWatcher stops the controller when the namespaces matching
+// the --watch-namespace-selector label selector change, as the caches of the
+// manager are restricted to the namespaces matching it on startup. The
+// controller then restarts watching the new set of namespaces.
+type namespaceSelectorWatcher struct {
+	logger     logr.Logger
+	reader     client.Reader
+	selector   labels.Selector
+	namespaces []string
+	interval   time.Duration
+}
+
+// Start checks the namespaces matching the selector until they change, which
+// it reports with an error stopping the manager. Failures to list the
+// namespaces are only logged, as the watched namespaces are still valid.
+func (w *namespaceSelectorWatcher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		namespaces, err := selectNamespaces(ctx, w.reader, w.selector)
+		if err != nil {
+			w.logger.Error(err, "failed to list the namespaces matching the selector", "selector", w.selector.String())
+			continue
+		}
+		if !equalNamespaces(namespaces, w.namespaces) {
+			return fmt.Errorf("the namespaces matching --watch-namespace-selector changed from %v to %v, "+
+				"restarting to watch them", w.namespaces, namespaces)
+		}
+	}
+}
+
+// NeedLeaderElection makes standby instances restart too, so that they watch
+// the same namespaces as the leader.
+func (w *namespaceSelectorWatcher) NeedLeaderElection() bool {
+	return false
+}
+
+// equalNamespaces tells whether two sorted lists of namespaces are the same.
+func equalNamespaces(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSelectNamespaces(t *testing.T) {
+	namespace := func(name, team string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"team": team}}}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		namespace("b", "blue"),
+		namespace("a", "blue"),
+		namespace("c", "red"),
+	).Build()
+
+	selector, err := labels.Parse("team=blue")
+	require.NoError(t, err)
+	namespaces, err := selectNamespaces(context.Background(), c, selector)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, namespaces)
+}
+
+func TestWithNamespaces(t *testing.T) {
+	assert.Equal(t, []string{"a", "b", "c"}, withNamespaces([]string{"a", "b"}, "b", "c"))
+	assert.Equal(t, []string{"a"}, withNamespaces(nil, "a"))
+}
+
+func TestNamespaceSelectorWatcher(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "a", Labels: map[string]string{"team": "blue"}}},
+	).Build()
+	selector, err := labels.Parse("team=blue")
+	require.NoError(t, err)
+	w := &namespaceSelectorWatcher{
+		logger:     logr.Discard(),
+		reader:     c,
+		selector:   selector,
+		namespaces: []string{"a"},
+		interval:   10 * time.Millisecond,
+	}
+	assert.False(t, w.NeedLeaderElection())
+
+	errs := make(chan error)
+	go func() { errs <- w.Start(ctx) }()
+
+	select {
+	case err := <-errs:
+		t.Fatalf("the watcher stopped while the namespaces didn't change: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	require.NoError(t, c.Create(ctx, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "b", Labels: map[string]string{"team": "blue"}},
+	}))
+	select {
+	case err := <-errs:
+		assert.ErrorContains(t, err, "changed from [a] to [a b]")
+	case <-ctx.Done():
+		t.Fatal("the watcher didn't stop when the namespaces changed")
+	}
+}
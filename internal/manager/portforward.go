@@ -0,0 +1,169 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// -----------------------------------------------------------------------------
+// Controller Manager - Development Mode
+// -----------------------------------------------------------------------------
+
+// portForwardTarget is the port of a Kubernetes service port-forwarding
+// connects to, given as "<namespace>/<name>:<port>", the port being either
+// the number or the name of a port of the service.
+type portForwardTarget struct {
+	namespace string
+	name      string
+	port      string
+}
+
+func parsePortForwardTarget(s string) (portForwardTarget, error) {
+	namespacedName, port, ok := strings.Cut(s, ":")
+	namespace, name, hasNamespace := strings.Cut(namespacedName, "/")
+	if !ok || !hasNamespace || namespace == "" || name == "" || port == "" {
+		return portForwardTarget{}, fmt.Errorf("%q must be a service given as <namespace>/<name>:<port>", s)
+	}
+	return portForwardTarget{namespace: namespace, name: name, port: port}, nil
+}
+
+// portForwardKongAdmin forwards a local port to a pod backing the Kong Admin
+// service given with --kong-admin-port-forward and makes the Kong Admin URL
+// point to it, so that the controller can run outside of the cluster against
+// its Kong. The scheme of the Kong Admin URL is kept. The forwarding stops
+// along with ctx.
+func portForwardKongAdmin(ctx context.Context, logger logr.Logger, kubeconfig *rest.Config, c *Config) error {
+	target, err := parsePortForwardTarget(c.KongAdminPortForward)
+	if err != nil {
+		return err
+	}
+	adminURL, err := url.Parse(c.KongAdminURL)
+	if err != nil {
+		return fmt.Errorf("invalid Kong Admin URL %q: %w", c.KongAdminURL, err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(kubeconfig)
+	if err != nil {
+		return err
+	}
+	service, err := clientset.CoreV1().Services(target.namespace).Get(ctx, target.name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if len(service.Spec.Selector) == 0 {
+		return fmt.Errorf("service %s/%s has no selector to find its pods with", target.namespace, target.name)
+	}
+	pods, err := clientset.CoreV1().Pods(target.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(service.Spec.Selector).String(),
+	})
+	if err != nil {
+		return err
+	}
+	pod := readyPod(pods.Items)
+	if pod == nil {
+		return fmt.Errorf("no pod of service %s/%s is ready", target.namespace, target.name)
+	}
+	podPort, err := targetPodPort(service, pod, target.port)
+	if err != nil {
+		return err
+	}
+
+	transport, upgrader, err := spdy.RoundTripperFor(kubeconfig)
+	if err != nil {
+		return err
+	}
+	portForwardURL := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").Namespace(pod.Namespace).Name(pod.Name).SubResource("portforward").URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, portForwardURL)
+
+	stopCh, readyCh := make(chan struct{}), make(chan struct{})
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{"127.0.0.1"},
+		[]string{fmt.Sprintf("0:%d", podPort)}, stopCh, readyCh, io.Discard, io.Discard)
+	if err != nil {
+		return err
+	}
+	forwardErrs := make(chan error, 1)
+	go func() {
+		forwardErrs <- forwarder.ForwardPorts()
+	}()
+	select {
+	case err := <-forwardErrs:
+		return err
+	case <-ctx.Done():
+		close(stopCh)
+		return ctx.Err()
+	case <-readyCh:
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			close(stopCh)
+		case err := <-forwardErrs:
+			logger.Error(err, "port-forwarding to the Kong Admin API stopped", "pod", pod.Namespace+"/"+pod.Name)
+		}
+	}()
+
+	ports, err := forwarder.GetPorts()
+	if err != nil {
+		return err
+	}
+	adminURL.Host = fmt.Sprintf("127.0.0.1:%d", ports[0].Local)
+	c.KongAdminURL = adminURL.String()
+	logger.Info("port-forwarding to the Kong Admin API", "pod", pod.Namespace+"/"+pod.Name,
+		"port", podPort, "url", c.KongAdminURL)
+	return nil
+}
+
+// readyPod returns the first of the pods which is ready, if any.
+func readyPod(pods []corev1.Pod) *corev1.Pod {
+	for i := range pods {
+		if pods[i].DeletionTimestamp != nil || pods[i].Status.Phase != corev1.PodRunning {
+			continue
+		}
+		for _, condition := range pods[i].Status.Conditions {
+			if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+				return &pods[i]
+			}
+		}
+	}
+	return nil
+}
+
+// targetPodPort resolves the port of the pod the given port, by number or
+// name, of the service targets.
+func targetPodPort(service *corev1.Service, pod *corev1.Pod, port string) (int, error) {
+	for _, servicePort := range service.Spec.Ports {
+		if servicePort.Name != port && strconv.Itoa(int(servicePort.Port)) != port {
+			continue
+		}
+		if servicePort.TargetPort.IntValue() != 0 {
+			return servicePort.TargetPort.IntValue(), nil
+		}
+		if servicePort.TargetPort.StrVal == "" {
+			return int(servicePort.Port), nil
+		}
+		for _, container := range pod.Spec.Containers {
+			for _, containerPort := range container.Ports {
+				if containerPort.Name == servicePort.TargetPort.StrVal {
+					return int(containerPort.ContainerPort), nil
+				}
+			}
+		}
+		return 0, fmt.Errorf("pod %s/%s has no port named %q", pod.Namespace, pod.Name, servicePort.TargetPort.StrVal)
+	}
+	return 0, fmt.Errorf("service %s/%s has no port %q", service.Namespace, service.Name, port)
+}
@@ -0,0 +1,79 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestParsePortForwardTarget(t *testing.T) {
+	target, err := parsePortForwardTarget("kong/kong-admin:8444")
+	require.NoError(t, err)
+	assert.Equal(t, portForwardTarget{namespace: "kong", name: "kong-admin", port: "8444"}, target)
+
+	target, err = parsePortForwardTarget("kong/kong-admin:admin")
+	require.NoError(t, err)
+	assert.Equal(t, portForwardTarget{namespace: "kong", name: "kong-admin", port: "admin"}, target)
+
+	for _, invalid := range []string{"", "kong-admin:8444", "kong/kong-admin", "/kong-admin:8444", "kong/:8444", "kong/kong-admin:"} {
+		_, err := parsePortForwardTarget(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestReadyPod(t *testing.T) {
+	pod := func(name string, phase corev1.PodPhase, ready corev1.ConditionStatus) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.PodStatus{
+				Phase:      phase,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+			},
+		}
+	}
+	assert.Nil(t, readyPod(nil))
+	assert.Nil(t, readyPod([]corev1.Pod{pod("pending", corev1.PodPending, corev1.ConditionFalse)}))
+
+	got := readyPod([]corev1.Pod{
+		pod("pending", corev1.PodPending, corev1.ConditionFalse),
+		pod("unready", corev1.PodRunning, corev1.ConditionFalse),
+		pod("ready", corev1.PodRunning, corev1.ConditionTrue),
+	})
+	require.NotNil(t, got)
+	assert.Equal(t, "ready", got.Name)
+}
+
+func TestTargetPodPort(t *testing.T) {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kong", Name: "kong-admin"},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{Name: "admin", Port: 8444, TargetPort: intstr.FromInt(8445)},
+				{Name: "admin-named", Port: 443, TargetPort: intstr.FromString("admin-tls")},
+				{Name: "admin-http", Port: 8001},
+				{Name: "missing", Port: 9000, TargetPort: intstr.FromString("missing")},
+			},
+		},
+	}
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Ports: []corev1.ContainerPort{{Name: "admin-tls", ContainerPort: 8444}}},
+			},
+		},
+	}
+
+	for port, want := range map[string]int{"admin": 8445, "8444": 8445, "443": 8444, "admin-http": 8001} {
+		got, err := targetPodPort(service, pod, port)
+		require.NoError(t, err, port)
+		assert.Equal(t, want, got, port)
+	}
+	_, err := targetPodPort(service, pod, "missing")
+	assert.Error(t, err)
+	_, err = targetPodPort(service, pod, "8080")
+	assert.Error(t, err)
+}
@@ -0,0 +1,113 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mgrutils "github.com/kong/kubernetes-ingress-controller/v2/internal/manager/utils"
+)
+
+// -----------------------------------------------------------------------------
+// Controller Manager - Kong Proxy Environment
+// -----------------------------------------------------------------------------
+
+// configureProxyClientIPs verifies that the Kong proxy, whose configuration is
+// given, preserves the client IPs passed by the load-balancers of
+// --proxy-trusted-cidrs and --proxy-protocol-ports. If it doesn't, the
+// environment variables it requires are set on the proxy container of
+// --proxy-deployment, or logged if the flag isn't set.
+func configureProxyClientIPs(ctx context.Context, logger logr.Logger, kubeconfig *rest.Config, kongRootConfig map[string]interface{}, c *Config) error {
+	env := make(map[string]string)
+	if len(c.ProxyTrustedCIDRs) > 0 {
+		patch, err := mgrutils.RealIPConfigurationPatch(kongRootConfig, c.ProxyTrustedCIDRs)
+		if err != nil {
+			return fmt.Errorf("invalid --proxy-trusted-cidrs: %w", err)
+		}
+		for name, value := range patch {
+			env[name] = value
+		}
+	}
+	// the PROXY protocol takes precedence over the X-Forwarded-For header
+	for name, value := range mgrutils.ProxyProtocolConfigurationPatch(kongRootConfig, c.ProxyProtocolPorts) {
+		env[name] = value
+	}
+
+	if len(env) == 0 {
+		logger.Info("Kong is configured to preserve the client IPs passed by the load-balancers in front of it")
+		return nil
+	}
+	if c.ProxyDeployment == "" {
+		logger.Error(nil, "Kong is not configured to preserve the client IPs passed by the load-balancers in front of it, "+
+			"set the given environment variables on the proxy container", "env", env)
+		return nil
+	}
+
+	namespace, name, ok := strings.Cut(c.ProxyDeployment, "/")
+	if !ok || namespace == "" || name == "" {
+		return fmt.Errorf("--proxy-deployment was expected to be in format <namespace>/<name> but got %s", c.ProxyDeployment)
+	}
+	cl, err := client.New(kubeconfig, client.Options{})
+	if err != nil {
+		return fmt.Errorf("unable to create a client for the proxy Deployment: %w", err)
+	}
+	deployment := k8stypes.NamespacedName{Namespace: namespace, Name: name}
+	if err := patchProxyEnv(ctx, cl, deployment, c.ProxyContainer, env); err != nil {
+		return fmt.Errorf("unable to configure the client IPs of the proxy Deployment %s: %w", deployment, err)
+	}
+	logger.Info("configured the proxy Deployment to preserve client IPs, its pods are rolled out",
+		"deployment", c.ProxyDeployment, "env", env)
+	return nil
+}
+
+// patchProxyEnv sets the given environment variables on a container of the
+// Deployment running the Kong proxy, replacing any existing value. Kubernetes
+// rolls out the pods of the Deployment for the proxy to use them.
+func patchProxyEnv(ctx context.Context, c client.Client, deployment k8stypes.NamespacedName, container string, env map[string]string) error {
+	existing := &appsv1.Deployment{}
+	if err := c.Get(ctx, deployment, existing); err != nil {
+		return err
+	}
+	found := false
+	for _, ctr := range existing.Spec.Template.Spec.Containers {
+		if ctr.Name == container {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("Deployment %s has no container %q", deployment, container)
+	}
+
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	envPatch := make([]map[string]interface{}, 0, len(names))
+	for _, name := range names {
+		// valueFrom is cleared for the variables previously set from a reference
+		envPatch = append(envPatch, map[string]interface{}{"name": name, "value": env[name], "valueFrom": nil})
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []map[string]interface{}{{"name": container, "env": envPatch}},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return c.Patch(ctx, existing, client.RawPatch(k8stypes.StrategicMergePatchType, patch))
+}
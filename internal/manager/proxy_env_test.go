@@ -0,0 +1,62 @@
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPatchProxyEnv(t *testing.T) {
+	ctx := context.Background()
+	deployment := k8stypes.NamespacedName{Namespace: "kong", Name: "proxy-kong"}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: deployment.Namespace, Name: deployment.Name},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "ingress-controller", Image: "kong/kubernetes-ingress-controller"},
+						{
+							Name:  "proxy",
+							Image: "kong",
+							Env: []corev1.EnvVar{
+								{Name: "KONG_DATABASE", Value: "off"},
+								{Name: "KONG_TRUSTED_IPS", ValueFrom: &corev1.EnvVarSource{
+									ConfigMapKeyRef: &corev1.ConfigMapKeySelector{Key: "trusted-ips"},
+								}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}).Build()
+
+	require.NoError(t, patchProxyEnv(ctx, c, deployment, "proxy", map[string]string{
+		"KONG_TRUSTED_IPS":    "10.0.0.0/8",
+		"KONG_REAL_IP_HEADER": "X-Forwarded-For",
+	}))
+
+	patched := &appsv1.Deployment{}
+	require.NoError(t, c.Get(ctx, deployment, patched))
+	containers := patched.Spec.Template.Spec.Containers
+	require.Len(t, containers, 2)
+	assert.Empty(t, containers[0].Env, "other containers are left as they are")
+	assert.ElementsMatch(t, []corev1.EnvVar{
+		{Name: "KONG_DATABASE", Value: "off"},
+		{Name: "KONG_TRUSTED_IPS", Value: "10.0.0.0/8"},
+		{Name: "KONG_REAL_IP_HEADER", Value: "X-Forwarded-For"},
+	}, containers[1].Env)
+
+	assert.Error(t, patchProxyEnv(ctx, c, deployment, "kong", map[string]string{"KONG_TRUSTED_IPS": "10.0.0.0/8"}),
+		"missing containers aren't added")
+	assert.Error(t, patchProxyEnv(ctx, c, k8stypes.NamespacedName{Namespace: "kong", Name: "missing"}, "proxy", nil))
+}
@@ -0,0 +1,46 @@
+package manager
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/go-logr/logr"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane"
+)
+
+// forceResyncSignals are the signals which make the controller push its whole
+// configuration to the data-plane again. Sending a signal requires access to
+// the controller's process (e.g. through kubectl exec), so no further
+// authentication is needed.
+var forceResyncSignals = []os.Signal{syscall.SIGHUP}
+
+// handleForceResyncSignals forces a configuration push to the data-plane,
+// whether the configuration has changed or not, every time one of the
+// forceResyncSignals is received and until ctx is done. This is useful after
+// manual changes to a DB-backed Kong or when drift is suspected, as it doesn't
+// require restarting the controller.
+func handleForceResyncSignals(
+	ctx context.Context,
+	logger logr.Logger,
+	dataplaneClient *dataplane.KongClient,
+	synchronizer *dataplane.Synchronizer,
+) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, forceResyncSignals...)
+	go func() {
+		defer signal.Stop(signals)
+		for {
+			select {
+			case sig := <-signals:
+				logger.Info("signal received, forcing a configuration push to the data-plane", "signal", sig.String())
+				dataplaneClient.ForceNextUpdate()
+				synchronizer.TriggerUpdate()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,104 @@
+package manager
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// resyncCheckPeriod is how often the informers of a staggered cache check
+// whether any of their handlers is due for a resync. It bounds the precision
+// of the staggered resync periods.
+const resyncCheckPeriod = time.Minute
+
+// staggeredResyncCacheBuilder wraps a cache builder so that the periodic
+// resyncs of the different resource types don't all happen at once: the
+// resync period of each type is the sync period delayed by a random amount of
+// up to stagger, which also differs between controller instances.
+func staggeredResyncCacheBuilder(newCache cache.NewCacheFunc, syncPeriod, stagger time.Duration) cache.NewCacheFunc {
+	return func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+		checkPeriod := resyncCheckPeriod
+		if syncPeriod < checkPeriod {
+			checkPeriod = syncPeriod
+		}
+		opts.Resync = &checkPeriod
+		c, err := newCache(config, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &staggeredResyncCache{
+			Cache:         c,
+			scheme:        opts.Scheme,
+			syncPeriod:    syncPeriod,
+			stagger:       stagger,
+			resyncPeriods: make(map[schema.GroupVersionKind]time.Duration),
+		}, nil
+	}
+}
+
+// staggeredResyncCache is a cache which registers the event handlers of each
+// resource type with its own resync period.
+type staggeredResyncCache struct {
+	cache.Cache
+
+	scheme     *runtime.Scheme
+	syncPeriod time.Duration
+	stagger    time.Duration
+
+	lock          sync.Mutex
+	resyncPeriods map[schema.GroupVersionKind]time.Duration
+}
+
+func (c *staggeredResyncCache) GetInformer(ctx context.Context, obj client.Object) (cache.Informer, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return nil, err
+	}
+	informer, err := c.Cache.GetInformer(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	return &staggeredResyncInformer{Informer: informer, resyncPeriod: c.resyncPeriod(gvk)}, nil
+}
+
+func (c *staggeredResyncCache) GetInformerForKind(ctx context.Context, gvk schema.GroupVersionKind) (cache.Informer, error) {
+	informer, err := c.Cache.GetInformerForKind(ctx, gvk)
+	if err != nil {
+		return nil, err
+	}
+	return &staggeredResyncInformer{Informer: informer, resyncPeriod: c.resyncPeriod(gvk)}, nil
+}
+
+// resyncPeriod provides the resync period of a resource type, which is drawn
+// the first time the type is requested.
+func (c *staggeredResyncCache) resyncPeriod(gvk schema.GroupVersionKind) time.Duration {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	period, ok := c.resyncPeriods[gvk]
+	if !ok {
+		period = c.syncPeriod + time.Duration(rand.Int63n(int64(c.stagger))) //nolint:gosec
+		c.resyncPeriods[gvk] = period
+	}
+	return period
+}
+
+// staggeredResyncInformer is an informer which registers event handlers with
+// the resync period of its resource type.
+type staggeredResyncInformer struct {
+	cache.Informer
+
+	resyncPeriod time.Duration
+}
+
+func (i *staggeredResyncInformer) AddEventHandler(handler toolscache.ResourceEventHandler) {
+	i.Informer.AddEventHandlerWithResyncPeriod(handler, i.resyncPeriod)
+}
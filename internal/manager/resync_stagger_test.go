@@ -0,0 +1,91 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// resyncRecordingCache is a fake cache whose informers record the resync
+// periods event handlers are registered with.
+type resyncRecordingCache struct {
+	informertest.FakeInformers
+
+	informers map[schema.GroupVersionKind]*resyncRecordingInformer
+}
+
+func (c *resyncRecordingCache) GetInformerForKind(_ context.Context, gvk schema.GroupVersionKind) (cache.Informer, error) {
+	if c.informers[gvk] == nil {
+		c.informers[gvk] = &resyncRecordingInformer{}
+	}
+	return c.informers[gvk], nil
+}
+
+func (c *resyncRecordingCache) GetInformer(ctx context.Context, obj client.Object) (cache.Informer, error) {
+	gvks, _, err := scheme.Scheme.ObjectKinds(obj)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetInformerForKind(ctx, gvks[0])
+}
+
+type resyncRecordingInformer struct {
+	cache.Informer
+
+	resyncPeriods []time.Duration
+}
+
+func (i *resyncRecordingInformer) AddEventHandlerWithResyncPeriod(_ toolscache.ResourceEventHandler, resyncPeriod time.Duration) {
+	i.resyncPeriods = append(i.resyncPeriods, resyncPeriod)
+}
+
+func TestStaggeredResyncCache(t *testing.T) {
+	ctx := context.Background()
+	syncPeriod, stagger := 10*time.Hour, 2*time.Hour
+	fake := &resyncRecordingCache{informers: make(map[schema.GroupVersionKind]*resyncRecordingInformer)}
+	var resync time.Duration
+	newCache := staggeredResyncCacheBuilder(func(_ *rest.Config, opts cache.Options) (cache.Cache, error) {
+		resync = *opts.Resync
+		return fake, nil
+	}, syncPeriod, stagger)
+	c, err := newCache(&rest.Config{}, cache.Options{Scheme: scheme.Scheme})
+	require.NoError(t, err)
+	assert.Equal(t, resyncCheckPeriod, resync, "informers check for due resyncs every resyncCheckPeriod")
+
+	t.Log("verifying that event handlers are registered with a staggered resync period")
+	for i := 0; i < 2; i++ {
+		informer, err := c.GetInformer(ctx, &corev1.Service{})
+		require.NoError(t, err)
+		informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{})
+	}
+	informer, err := c.GetInformerForKind(ctx, corev1.SchemeGroupVersion.WithKind("Service"))
+	require.NoError(t, err)
+	informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{})
+
+	periods := fake.informers[corev1.SchemeGroupVersion.WithKind("Service")].resyncPeriods
+	require.Len(t, periods, 3)
+	assert.GreaterOrEqual(t, periods[0], syncPeriod)
+	assert.Less(t, periods[0], syncPeriod+stagger)
+	assert.Equal(t, periods[0], periods[1], "a resource type keeps its resync period")
+	assert.Equal(t, periods[0], periods[2], "a resource type keeps its resync period")
+
+	t.Log("verifying that short sync periods are checked for often enough")
+	newCache = staggeredResyncCacheBuilder(func(_ *rest.Config, opts cache.Options) (cache.Cache, error) {
+		resync = *opts.Resync
+		return fake, nil
+	}, 30*time.Second, time.Second)
+	_, err = newCache(&rest.Config{}, cache.Options{Scheme: scheme.Scheme})
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, resync)
+}
@@ -6,21 +6,28 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/avast/retry-go/v4"
 	"github.com/kong/go-kong/kong"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	knativev1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
+	mcsv1alpha1 "github.com/kong/kubernetes-ingress-controller/v2/internal/apis/multicluster/v1alpha1"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/introspection"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/manager/metadata"
 	mgrutils "github.com/kong/kubernetes-ingress-controller/v2/internal/manager/utils"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/tracing"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util/kubernetes/object/status"
 	konghqcomv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
@@ -31,8 +38,21 @@ import (
 // Controller Manager - Setup & Run
 // -----------------------------------------------------------------------------
 
-// Run starts the controller manager and blocks until it exits.
-func Run(ctx context.Context, c *Config, diagnostic util.ConfigDumpDiagnostic) error {
+// cacheSyncCheckTimeout bounds how long the readiness probe waits for the
+// caches of the manager to sync.
+const cacheSyncCheckTimeout = 100 * time.Millisecond
+
+// Run starts the controller manager and blocks until it exits. The controller
+// reports that it isn't ready anymore once terminating is closed, ahead of the
+// cancellation of ctx. The translation features in effect are sent to
+// featureReports, if set, whenever they change.
+func Run(
+	ctx context.Context,
+	c *Config,
+	diagnostic util.ConfigDumpDiagnostic,
+	featureReports chan<- util.FeatureReport,
+	terminating <-chan struct{},
+) error {
 	deprecatedLogger, _, err := setupLoggers(c)
 	if err != nil {
 		return err
@@ -47,9 +67,10 @@ func Run(ctx context.Context, c *Config, diagnostic util.ConfigDumpDiagnostic) e
 	utilruntime.Must(configurationv1beta1.AddToScheme(scheme))
 	utilruntime.Must(knativev1alpha1.AddToScheme(scheme))
 	utilruntime.Must(gatewayv1alpha2.AddToScheme(scheme))
+	utilruntime.Must(mcsv1alpha1.AddToScheme(scheme))
 
 	if c.EnableLeaderElection {
-		setupLog.V(0).Info("the --leader-elect flag is deprecated and no longer has any effect: leader election is set based on the Kong database setting")
+		setupLog.V(0).Info("the --leader-elect flag is deprecated and no longer has any effect: leader election is set with --election-mode")
 	}
 
 	setupLog.Info("getting enabled options and features")
@@ -58,12 +79,50 @@ func Run(ctx context.Context, c *Config, diagnostic util.ConfigDumpDiagnostic) e
 		return fmt.Errorf("failed to configure feature gates: %w", err)
 	}
 
+	if c.TracingOTLPEndpoint != "" {
+		setupLog.Info("exporting traces", "endpoint", c.TracingOTLPEndpoint, "sampling-ratio", c.TracingSamplingRatio)
+		shutdownTracing, err := tracing.Setup(ctrl.Log.WithName("tracing"), tracing.Config{
+			Endpoint:      c.TracingOTLPEndpoint,
+			Headers:       c.TracingOTLPHeaders,
+			SamplingRatio: c.TracingSamplingRatio,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to set up tracing: %w", err)
+		}
+		defer func() {
+			// flush the spans of the last updates
+			ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+			defer cancel()
+			if err := shutdownTracing(ctx); err != nil {
+				setupLog.Error(err, "failed to flush traces")
+			}
+		}()
+	}
+
 	setupLog.Info("getting the kubernetes client configuration")
 	kubeconfig, err := c.GetKubeconfig()
 	if err != nil {
 		return fmt.Errorf("get kubeconfig from file %q: %w", c.KubeconfigPath, err)
 	}
 
+	if c.KongAdminPortForward != "" {
+		if err := portForwardKongAdmin(ctx, ctrl.Log.WithName("port-forward"), kubeconfig, c); err != nil {
+			return fmt.Errorf("unable to port-forward to the Kong Admin API: %w", err)
+		}
+	}
+
+	if c.KongAdminTLSSecret != "" {
+		if err := loadKongAdminTLSSecret(ctx, ctrl.Log.WithName("admin-tls"), kubeconfig, c); err != nil {
+			return fmt.Errorf("unable to load the Kong Admin API TLS credentials: %w", err)
+		}
+	}
+
+	if c.KongAdminTokenPath != "" {
+		if err := watchKongAdminTokenFile(ctx, ctrl.Log.WithName("admin-token"), c); err != nil {
+			return fmt.Errorf("unable to load the Kong Admin API token: %w", err)
+		}
+	}
+
 	setupLog.Info("getting the kong admin api client configuration")
 	adminClient, err := c.GetKongClient(ctx)
 	if err != nil {
@@ -110,6 +169,61 @@ func Run(ctx context.Context, c *Config, diagnostic util.ConfigDumpDiagnostic) e
 	if dbmode == "off" && c.SkipCACertificates {
 		return fmt.Errorf("--skip-ca-certificates is not available for use with DB-less Kong instances")
 	}
+	// the expressions router ignores the paths, hosts and headers of routes,
+	// which are all this controller can currently translate Kubernetes objects to:
+	// translating them to route expressions and priorities isn't supported.
+	routerFlavor, _ := kongRootConfig["router_flavor"].(string)
+	if routerFlavor == "expressions" {
+		return fmt.Errorf("router_flavor %q is not supported, as Kubernetes objects can't be translated to route expressions: "+
+			"use \"traditional\" or \"traditional_compatible\"", routerFlavor)
+	}
+	if len(c.ProxyTrustedCIDRs) > 0 || len(c.ProxyProtocolPorts) > 0 {
+		if err := configureProxyClientIPs(ctx, setupLog, kubeconfig, kongRootConfig, c); err != nil {
+			return err
+		}
+	}
+
+	var namespaceWatcher *namespaceSelectorWatcher
+	if c.WatchNamespaceSelector != "" {
+		selector, err := labels.Parse(c.WatchNamespaceSelector)
+		if err != nil {
+			return fmt.Errorf("invalid --watch-namespace-selector: %w", err)
+		}
+		uncachedClient, err := client.New(kubeconfig, client.Options{Scheme: scheme})
+		if err != nil {
+			return fmt.Errorf("unable to create a client for the namespace selector: %w", err)
+		}
+		namespaces, err := selectNamespaces(ctx, uncachedClient, selector)
+		if err != nil {
+			return fmt.Errorf("unable to list the namespaces matching --watch-namespace-selector: %w", err)
+		}
+		// watching no namespace would mean watching them all
+		if len(namespaces) == 0 && len(c.WatchNamespaces) == 0 {
+			return fmt.Errorf("no namespace matches --watch-namespace-selector %q", c.WatchNamespaceSelector)
+		}
+		setupLog.Info("watching the namespaces matching the selector", "selector", selector.String(), "namespaces", namespaces)
+		c.WatchNamespaces = withNamespaces(c.WatchNamespaces, namespaces...)
+		namespaceWatcher = &namespaceSelectorWatcher{
+			logger:     ctrl.Log.WithName("namespace-selector"),
+			reader:     uncachedClient,
+			selector:   selector,
+			namespaces: namespaces,
+			interval:   namespaceSelectorPollInterval,
+		}
+	}
+
+	if c.ProvisionStreamPorts && c.PublishService == "" {
+		return fmt.Errorf("--provision-stream-ports requires --publish-service")
+	}
+	if c.ProvisionStreamListenDeployment != "" {
+		if !c.ProvisionStreamPorts {
+			return fmt.Errorf("--provision-stream-listen-deployment requires --provision-stream-ports")
+		}
+		if parts := strings.Split(c.ProvisionStreamListenDeployment, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("--provision-stream-listen-deployment was expected to be in format <namespace>/<name> but got %s",
+				c.ProvisionStreamListenDeployment)
+		}
+	}
 
 	setupLog.Info("configuring and building the controller manager")
 	controllerOpts, err := setupControllerOptions(setupLog, c, scheme, dbmode)
@@ -122,7 +236,7 @@ func Run(ctx context.Context, c *Config, diagnostic util.ConfigDumpDiagnostic) e
 	}
 
 	setupLog.Info("Starting Admission Server")
-	if err := setupAdmissionServer(ctx, c, mgr.GetClient()); err != nil {
+	if err := setupAdmissionServer(ctx, c, mgr.GetClient(), routerFlavor, featureGates); err != nil {
 		return err
 	}
 
@@ -131,22 +245,243 @@ func Run(ctx context.Context, c *Config, diagnostic util.ConfigDumpDiagnostic) e
 	if err != nil {
 		return fmt.Errorf("%f is not a valid number of seconds to the timeout config for the kong client: %w", c.ProxyTimeoutSeconds, err)
 	}
-	dataplaneClient, err := dataplane.NewKongClient(deprecatedLogger, timeoutDuration, c.IngressClassName, c.EnableReverseSync, c.SkipCACertificates, diagnostic, kongConfig)
+	dataplaneClient, err := dataplane.NewKongClient(deprecatedLogger, timeoutDuration, c.IngressClassName, c.EnableReverseSync, c.SkipCACertificates, diagnostic, kongConfig,
+		mgr.GetEventRecorderFor(KongClientEventRecorderComponentName))
 	if err != nil {
 		return fmt.Errorf("failed to initialize kong data-plane client: %w", err)
 	}
+	dataplaneClient.SetTranslationTimeout(c.TranslationTimeout)
+	dataplaneClient.SetNamespaceQuotas(c.NamespaceQuotas)
+	dataplaneClient.SetTopologyZone(c.TopologyZone)
+	dataplaneClient.SetClusterPluginSecretNamespaces(c.ClusterPluginSecretNamespaces)
+	if c.DefaultCertificate != "" {
+		if parts := strings.Split(c.DefaultCertificate, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("--default-certificate was expected to be in format <namespace>/<name> but got %s", c.DefaultCertificate)
+		}
+		dataplaneClient.SetDefaultCertificate(c.DefaultCertificate)
+	}
+	dataplaneClient.SetRouterFlavor(routerFlavor)
+	if featureReports != nil {
+		dataplaneClient.SetFeatureReports(featureReports)
+	}
+	if c.AppliedConfigConfigMap != "" {
+		parts := strings.Split(c.AppliedConfigConfigMap, "/")
+		if len(parts) != 2 {
+			return fmt.Errorf("--applied-config-configmap was expected to be in format <namespace>/<name> but got %s", c.AppliedConfigConfigMap)
+		}
+		uncachedClient, err := client.New(kubeconfig, client.Options{Scheme: scheme})
+		if err != nil {
+			return fmt.Errorf("unable to create a client for the applied configuration ConfigMap: %w", err)
+		}
+		dataplaneClient.SetAppliedConfigurationRecorder(&dataplane.ConfigMapAppliedConfigurationRecorder{
+			Client:            uncachedClient,
+			ConfigMap:         k8stypes.NamespacedName{Namespace: parts[0], Name: parts[1]},
+			ControllerVersion: metadata.Release,
+		})
+		setupLog.Info("recording the applied configuration", "configmap", c.AppliedConfigConfigMap)
+	}
+	if c.ConfigExportConfigMap != "" {
+		parts := strings.Split(c.ConfigExportConfigMap, "/")
+		if len(parts) != 2 {
+			return fmt.Errorf("--config-export-configmap was expected to be in format <namespace>/<name> but got %s", c.ConfigExportConfigMap)
+		}
+		if c.ConfigExportInterval < 0 {
+			return fmt.Errorf("--config-export-interval must not be negative")
+		}
+		uncachedClient, err := client.New(kubeconfig, client.Options{Scheme: scheme})
+		if err != nil {
+			return fmt.Errorf("unable to create a client for the configuration export ConfigMap: %w", err)
+		}
+		dataplaneClient.SetConfigExporter(&dataplane.ConfigMapConfigExporter{
+			Client:    uncachedClient,
+			ConfigMap: k8stypes.NamespacedName{Namespace: parts[0], Name: parts[1]},
+		}, c.ConfigExportInterval)
+		setupLog.Info("exporting the applied configuration", "configmap", c.ConfigExportConfigMap)
+	}
+	if c.ConfigSnapshotSecret != "" {
+		if dbmode != "off" {
+			return fmt.Errorf("--config-snapshot-secret is only available for use with DB-less Kong instances")
+		}
+		parts := strings.Split(c.ConfigSnapshotSecret, "/")
+		if len(parts) != 2 {
+			return fmt.Errorf("--config-snapshot-secret was expected to be in format <namespace>/<name> but got %s", c.ConfigSnapshotSecret)
+		}
+		uncachedClient, err := client.New(kubeconfig, client.Options{Scheme: scheme})
+		if err != nil {
+			return fmt.Errorf("unable to create a client for the configuration snapshot Secret: %w", err)
+		}
+		snapshots := &dataplane.SecretConfigSnapshotStore{
+			Client: uncachedClient,
+			Secret: k8stypes.NamespacedName{Namespace: parts[0], Name: parts[1]},
+		}
+		// with leader election only the leader applies, and stores, the configuration
+		if !controllerOpts.LeaderElection {
+			election, err := newConfigSnapshotWriterElection(ctrl.Log.WithName("config-snapshot"), kubeconfig, snapshots.Secret)
+			if err != nil {
+				return fmt.Errorf("unable to set up the election of the configuration snapshot writer: %w", err)
+			}
+			if err := mgr.Add(election); err != nil {
+				return fmt.Errorf("unable to add the election of the configuration snapshot writer: %w", err)
+			}
+			snapshots.IsWriter = election.IsWriter
+		}
+		dataplaneClient.SetConfigSnapshotStore(snapshots)
+		// seed Kong before the caches are synced, failing to do so only
+		// delays its configuration until the first update
+		seedCtx, cancel := context.WithTimeout(ctx, timeoutDuration)
+		seeded, err := dataplane.SeedConfigFromSnapshot(seedCtx, &kongConfig, snapshots)
+		cancel()
+		if err != nil {
+			setupLog.Error(err, "failed to seed Kong with the configuration snapshot")
+		} else if seeded {
+			setupLog.Info("seeded Kong with the configuration snapshot", "secret", c.ConfigSnapshotSecret)
+		}
+	}
+
+	if c.UpdateStatus {
+		dataplaneClient.SetKongClusterPluginStatusUpdater(&dataplane.ClientKongClusterPluginStatusUpdater{
+			Client: mgr.GetClient(),
+		})
+		dataplaneClient.SetL4IngressStatusUpdater(&dataplane.ClientL4IngressStatusUpdater{
+			Client: mgr.GetClient(),
+		})
+	}
+
+	// with leader election, standby instances run the controllers and build the
+	// configuration too, to be ready to take over, but only the leader applies it
+	var controllerMgr ctrl.Manager = mgr
+	var standbyMgr *standbyManager
+	if controllerOpts.LeaderElection {
+		standbyMgr = newStandbyManager(mgr, ctrl.Log.WithName("standby"))
+		controllerMgr = standbyMgr
+		dataplaneClient.EnableStandby(mgr.Elected())
+	}
 
 	setupLog.Info("Initializing Dataplane Synchronizer")
-	synchronizer, err := setupDataplaneSynchronizer(setupLog, deprecatedLogger, mgr, dataplaneClient, c)
+	synchronizer, err := setupDataplaneSynchronizer(setupLog, deprecatedLogger, controllerMgr, dataplaneClient, c)
 	if err != nil {
 		return fmt.Errorf("unable to initialize dataplane synchronizer: %w", err)
 	}
+	dataplaneClient.SetUpdateTrigger(synchronizer.TriggerUpdate)
+	if controllerOpts.LeaderElection {
+		go func() {
+			select {
+			case <-mgr.Elected():
+				setupLog.Info("elected leader, applying the configuration")
+				synchronizer.TriggerUpdate()
+				// the writes dropped while standing by are retried by
+				// reconciling all the objects again
+				standbyMgr.Replay()
+			case <-ctx.Done():
+			}
+		}()
+	}
+	handleForceResyncSignals(ctx, ctrl.Log.WithName("resync"), dataplaneClient, synchronizer)
+
+	if namespaceWatcher != nil {
+		if err := mgr.Add(namespaceWatcher); err != nil {
+			return fmt.Errorf("unable to add the namespace selector watcher: %w", err)
+		}
+	}
+
+	if len(c.StaleFinalizers) > 0 {
+		if err := validateStaleFinalizers(c.StaleFinalizers); err != nil {
+			return fmt.Errorf("invalid --remove-stale-finalizers: %w", err)
+		}
+		setupLog.Info("removing stale finalizers", "finalizers", c.StaleFinalizers)
+		if err := mgr.Add(&staleFinalizerCollector{
+			logger:     ctrl.Log.WithName("stale-finalizers"),
+			reader:     mgr.GetAPIReader(),
+			client:     mgr.GetClient(),
+			finalizers: c.StaleFinalizers,
+			namespaces: c.WatchNamespaces,
+			kinds:      staleFinalizerKinds,
+		}); err != nil {
+			return fmt.Errorf("unable to add the stale finalizer collector: %w", err)
+		}
+	}
+
+	if c.BenchmarkObjects < 0 {
+		return fmt.Errorf("invalid --benchmark-objects %d: must not be negative", c.BenchmarkObjects)
+	}
+	if c.BenchmarkObjects > 0 {
+		setupLog.Info("benchmark mode enabled, synthetic objects will be added to the configuration", "ingresses", c.BenchmarkObjects)
+		if err := mgr.Add(&benchmark{
+			logger:       ctrl.Log.WithName("benchmark"),
+			client:       dataplaneClient,
+			ingressClass: c.IngressClassName,
+			ingresses:    c.BenchmarkObjects,
+		}); err != nil {
+			return fmt.Errorf("unable to add the benchmark: %w", err)
+		}
+	}
+
+	shutdown := &gracefulShutdown{
+		logger:          ctrl.Log.WithName("shutdown"),
+		synchronizer:    synchronizer,
+		dataplaneClient: dataplaneClient,
+		timeout:         gracefulShutdownTimeout,
+	}
+	if len(c.ShutdownFinalizers) > 0 {
+		if err := validateStaleFinalizers(c.ShutdownFinalizers); err != nil {
+			return fmt.Errorf("invalid --shutdown-remove-finalizers: %w", err)
+		}
+		shutdown.finalizers = &staleFinalizerCollector{
+			logger:     ctrl.Log.WithName("shutdown-finalizers"),
+			reader:     mgr.GetAPIReader(),
+			client:     mgr.GetClient(),
+			finalizers: c.ShutdownFinalizers,
+			namespaces: c.WatchNamespaces,
+			kinds:      staleFinalizerKinds,
+		}
+	}
+	if err := mgr.Add(shutdown); err != nil {
+		return fmt.Errorf("unable to add the graceful shutdown: %w", err)
+	}
+
+	if c.IntrospectionAPI.ListenAddr != "off" {
+		introspectionServer, err := introspection.NewServer(c.IntrospectionAPI, dataplaneClient, ctrl.Log.WithName("introspection-api"))
+		if err != nil {
+			return fmt.Errorf("unable to set up the introspection API: %w", err)
+		}
+		if err := mgr.Add(introspectionServer); err != nil {
+			return fmt.Errorf("unable to add the introspection API server: %w", err)
+		}
+	} else {
+		setupLog.Info("introspection API disabled")
+	}
 
 	if enabled, ok := featureGates[combinedRoutesFeature]; ok && enabled {
 		dataplaneClient.EnableCombinedServiceRoutes()
 		setupLog.Info("combined routes mode has been enabled")
 	}
 
+	if enabled, ok := featureGates[fallbackConfigurationFeature]; ok && enabled {
+		dataplaneClient.EnableFallbackConfiguration()
+		setupLog.Info("fallback configuration has been enabled")
+	}
+
+	if enabled, ok := featureGates[partialSyncFeature]; ok && enabled {
+		dataplaneClient.EnablePartialSync()
+		setupLog.Info("partial sync has been enabled")
+	}
+	dataplaneClient.SetTranslationFailuresBudget(c.TranslationFailuresBudget)
+
+	if len(c.GCPreviousFilterTags) > 0 {
+		dataplaneClient.EnableOrphanedEntitiesGC(c.GCPreviousFilterTags)
+		setupLog.Info("garbage collection of orphaned entities has been enabled", "tags", c.GCPreviousFilterTags)
+	}
+
+	if c.PropagationEvents {
+		dataplaneClient.EnablePropagationEvents()
+		setupLog.Info("propagation events have been enabled")
+	}
+
+	if enabled, ok := featureGates[endpointSliceTargetsFeature]; ok && enabled {
+		dataplaneClient.EnableEndpointSliceTargets()
+		setupLog.Info("endpoint slice targets have been enabled")
+	}
+
 	var kubernetesStatusQueue *status.Queue
 	if c.UpdateStatus {
 		setupLog.Info("Starting Status Updater")
@@ -163,12 +498,12 @@ func Run(ctx context.Context, c *Config, diagnostic util.ConfigDumpDiagnostic) e
 	}
 
 	setupLog.Info("Starting Enabled Controllers")
-	controllers, err := setupControllers(mgr, dataplaneClient, dataplaneAddressFinder, kubernetesStatusQueue, c, featureGates)
+	controllers, err := setupControllers(controllerMgr, dataplaneClient, dataplaneAddressFinder, kubernetesStatusQueue, c, featureGates)
 	if err != nil {
 		return fmt.Errorf("unable to setup controller as expected %w", err)
 	}
 	for _, c := range controllers {
-		if err := c.MaybeSetupWithManager(mgr); err != nil {
+		if err := c.MaybeSetupWithManager(controllerMgr); err != nil {
 			return fmt.Errorf("unable to create controller %q: %w", c.Name(), err)
 		}
 	}
@@ -182,6 +517,11 @@ func Run(ctx context.Context, c *Config, diagnostic util.ConfigDumpDiagnostic) e
 		return fmt.Errorf("unable to setup healthz: %w", err)
 	}
 	if err := mgr.AddReadyzCheck("check", func(_ *http.Request) error {
+		select {
+		case <-terminating:
+			return errors.New("shutting down")
+		default:
+		}
 		if !synchronizer.IsReady() {
 			return errors.New("synchronizer not yet configured")
 		}
@@ -189,6 +529,18 @@ func Run(ctx context.Context, c *Config, diagnostic util.ConfigDumpDiagnostic) e
 	}); err != nil {
 		return fmt.Errorf("unable to setup readyz: %w", err)
 	}
+	if err := mgr.AddReadyzCheck("caches", func(req *http.Request) error {
+		// the cache is synced once its informers have listed their objects,
+		// which is checked without holding up the probe
+		ctx, cancel := context.WithTimeout(req.Context(), cacheSyncCheckTimeout)
+		defer cancel()
+		if !mgr.GetCache().WaitForCacheSync(ctx) {
+			return errors.New("caches not yet synced")
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("unable to setup readyz: %w", err)
+	}
 
 	if c.AnonymousReports {
 		setupLog.Info("Starting anonymous reports")
@@ -44,8 +44,9 @@ func setupLoggers(c *Config) (logrus.FieldLogger, logr.Logger, error) {
 	logger := logrusr.New(deprecatedLogger)
 	ctrl.SetLogger(logger)
 
-	if c.LogLevel != "trace" && c.LogLevel != "debug" {
-		// disable deck's per-change diff output
+	if (c.LogLevel != "trace" && c.LogLevel != "debug") || !c.DumpSensitiveConfig {
+		// disable deck's per-change diff output, which includes full entities
+		// with credentials and TLS keys, unless sensitive dumps are allowed
 		cprint.DisableOutput = true
 	}
 
@@ -70,13 +71,29 @@ func setupControllerOptions(logger logr.Logger, c *Config, scheme *runtime.Schem
 		requiredCacheNamespaces = append(requiredCacheNamespaces, publishServiceSplit[0])
 	}
 
+	// the Secrets KongClusterPlugins may reference must be cached, wherever
+	// they are.
+	requiredCacheNamespaces = append(requiredCacheNamespaces, c.ClusterPluginSecretNamespaces...)
+
 	var leaderElection bool
-	if dbmode == "off" {
-		logger.Info("DB-less mode detected, disabling leader election")
-		leaderElection = false
-	} else {
-		logger.Info("Database mode detected, enabling leader election")
+	switch c.LeaderElectionMode {
+	case leaderElectionModeEnabled:
+		logger.Info("enabling leader election")
 		leaderElection = true
+	case leaderElectionModeDisabled:
+		logger.Info("disabling leader election")
+		leaderElection = false
+	case leaderElectionModeAuto:
+		if dbmode == "off" {
+			logger.Info("DB-less mode detected, disabling leader election")
+			leaderElection = false
+		} else {
+			logger.Info("Database mode detected, enabling leader election")
+			leaderElection = true
+		}
+	default:
+		return ctrl.Options{}, fmt.Errorf("--election-mode must be one of %q, %q or %q, got %q",
+			leaderElectionModeAuto, leaderElectionModeEnabled, leaderElectionModeDisabled, c.LeaderElectionMode)
 	}
 
 	// configure the general controller options
@@ -87,7 +104,9 @@ func setupControllerOptions(logger logr.Logger, c *Config, scheme *runtime.Schem
 		HealthProbeBindAddress: c.ProbeAddr,
 		LeaderElection:         leaderElection,
 		LeaderElectionID:       c.LeaderElectionID,
-		SyncPeriod:             &c.SyncPeriod,
+		// let a standby instance take over right away when the leader stops
+		LeaderElectionReleaseOnCancel: true,
+		SyncPeriod:                    &c.SyncPeriod,
 	}
 
 	// configure the controller caching options
@@ -109,6 +128,21 @@ func setupControllerOptions(logger logr.Logger, c *Config, scheme *runtime.Schem
 		controllerOpts.LeaderElectionNamespace = c.LeaderElectionNamespace
 	}
 
+	// spread the periodic resyncs of the different resource types, if enabled
+	if c.SyncPeriod < 0 || c.SyncPeriodStagger < 0 {
+		return ctrl.Options{}, fmt.Errorf("--sync-period and --sync-period-stagger must not be negative")
+	}
+	if c.SyncPeriod > 0 && c.SyncPeriodStagger > 0 {
+		logger.Info("staggering periodic resyncs", "sync-period", c.SyncPeriod, "stagger", c.SyncPeriodStagger)
+		newCache := controllerOpts.NewCache
+		if newCache == nil {
+			newCache = cache.New
+		}
+		controllerOpts.NewCache = staggeredResyncCacheBuilder(newCache, c.SyncPeriod, c.SyncPeriodStagger)
+	} else if c.SyncPeriod == 0 {
+		logger.Info("periodic resyncs are disabled")
+	}
+
 	return controllerOpts, nil
 }
 
@@ -127,6 +161,11 @@ func setupKongConfig(ctx context.Context, kongClient *kong.Client, logger logr.L
 		Concurrency:       c.Concurrency,
 		Client:            kongClient,
 		PluginSchemaStore: util.NewPluginSchemaStore(kongClient),
+		CircuitBreaker: sendconfig.NewCircuitBreaker(
+			sendconfig.DefaultCircuitBreakerThreshold,
+			sendconfig.DefaultCircuitBreakerMinBackoff,
+			sendconfig.DefaultCircuitBreakerMaxBackoff,
+		),
 	}
 }
 
@@ -167,7 +206,13 @@ func setupDataplaneSynchronizer(
 	return dataplaneSynchronizer, nil
 }
 
-func setupAdmissionServer(ctx context.Context, managerConfig *Config, managerClient client.Client) error {
+func setupAdmissionServer(
+	ctx context.Context,
+	managerConfig *Config,
+	managerClient client.Client,
+	routerFlavor string,
+	featureGates map[string]bool,
+) error {
 	log, err := util.MakeLogger(managerConfig.LogLevel, managerConfig.LogFormat)
 	if err != nil {
 		return err
@@ -184,15 +229,20 @@ func setupAdmissionServer(ctx context.Context, managerConfig *Config, managerCli
 	if err != nil {
 		return err
 	}
+	validator := admission.NewKongHTTPValidator(
+		kongclient.Consumers,
+		kongclient.Plugins,
+		log,
+		managerClient,
+		managerConfig.IngressClassName,
+	)
+	validator.NamespaceQuotas = managerConfig.NamespaceQuotas
+	validator.CombinedRoutes = featureGates[combinedRoutesFeature]
+	validator.ClusterPluginSecretNamespaces = managerConfig.ClusterPluginSecretNamespaces
+	validator.RouterFlavor = routerFlavor
 	srv, err := admission.MakeTLSServer(ctx, &managerConfig.AdmissionServer, &admission.RequestHandler{
-		Validator: admission.NewKongHTTPValidator(
-			kongclient.Consumers,
-			kongclient.Plugins,
-			log,
-			managerClient,
-			managerConfig.IngressClassName,
-		),
-		Logger: logger,
+		Validator: validator,
+		Logger:    logger,
 	}, log)
 	if err != nil {
 		return err
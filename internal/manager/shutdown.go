@@ -0,0 +1,64 @@
+package manager
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane"
+)
+
+// -----------------------------------------------------------------------------
+// Controller Manager - Graceful Shutdown
+// -----------------------------------------------------------------------------
+
+// gracefulShutdownTimeout bounds the graceful shutdown, staying within the
+// grace period the controller manager waits for its runnables to stop in.
+const gracefulShutdownTimeout = 25 * time.Second
+
+// gracefulShutdown runs when the controller shuts down: it waits for the
+// update of Kong in progress, if any, to complete, then flushes the changes
+// to Kubernetes objects made since with a final update. If configured, it
+// then removes the controller's finalizers from the objects it reconciles, so
+// that objects deleted while no controller runs (e.g. during a rolling
+// update) aren't stranded.
+type gracefulShutdown struct {
+	logger          logr.Logger
+	synchronizer    *dataplane.Synchronizer
+	dataplaneClient dataplane.Client
+	timeout         time.Duration
+
+	// finalizers removes the controller's finalizers, if set.
+	finalizers *staleFinalizerCollector
+}
+
+// Start waits for ctx to be done and shuts down gracefully. Failures are only
+// logged, as the controller stops regardless.
+func (s *gracefulShutdown) Start(ctx context.Context) error {
+	<-ctx.Done()
+	s.logger.Info("shutting down gracefully", "timeout", s.timeout.String())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	if err := s.synchronizer.WaitUntilStopped(shutdownCtx); err != nil {
+		s.logger.Error(err, "the update of Kong in progress didn't complete")
+		return nil
+	}
+	if err := s.dataplaneClient.Update(shutdownCtx); err != nil {
+		s.logger.Error(err, "failed to flush the configuration to Kong")
+	} else {
+		s.logger.Info("flushed the configuration to Kong")
+	}
+
+	if s.finalizers != nil {
+		_ = s.finalizers.Start(shutdownCtx)
+	}
+	return nil
+}
+
+// NeedLeaderElection makes only the leader, which updates Kong, shut down
+// gracefully.
+func (s *gracefulShutdown) NeedLeaderElection() bool {
+	return true
+}
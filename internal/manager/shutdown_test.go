@@ -0,0 +1,71 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane"
+)
+
+func TestGracefulShutdown(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(&corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "svc", Finalizers: []string{"konghq.com/cleanup"}},
+	}).Build()
+	dataplaneClient := &countingDataplaneClient{}
+	synchronizer, err := dataplane.NewSynchronizer(logrus.New(), dataplaneClient)
+	require.NoError(t, err)
+
+	shutdown := &gracefulShutdown{
+		logger:          logr.Discard(),
+		synchronizer:    synchronizer,
+		dataplaneClient: dataplaneClient,
+		timeout:         time.Second,
+		finalizers: &staleFinalizerCollector{
+			logger:     logr.Discard(),
+			reader:     c,
+			client:     c,
+			finalizers: []string{"konghq.com/cleanup"},
+			kinds:      []schema.GroupVersionKind{corev1.SchemeGroupVersion.WithKind("Service")},
+		},
+	}
+	assert.True(t, shutdown.NeedLeaderElection())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- shutdown.Start(ctx) }()
+	assert.Never(t, func() bool { return dataplaneClient.updates > 0 }, time.Millisecond*100, time.Millisecond*10,
+		"nothing happens until the controller shuts down")
+
+	cancel()
+	require.NoError(t, <-done)
+	assert.Equal(t, 1, dataplaneClient.updates, "the configuration is flushed")
+	svc := &corev1.Service{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "svc"}, svc))
+	assert.Empty(t, svc.Finalizers)
+}
+
+// countingDataplaneClient counts the updates of the data-plane.
+type countingDataplaneClient struct {
+	updates int
+}
+
+func (c *countingDataplaneClient) DBMode() string {
+	return "off"
+}
+
+func (c *countingDataplaneClient) Update(_ context.Context) error {
+	c.updates++
+	return nil
+}
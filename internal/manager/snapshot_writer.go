@@ -0,0 +1,103 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-logr/logr"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// -----------------------------------------------------------------------------
+// Controller Manager - Configuration Snapshot Writer
+// -----------------------------------------------------------------------------
+
+const (
+	// configSnapshotWriterLeaseDuration, configSnapshotWriterRenewDeadline and
+	// configSnapshotWriterRetryPeriod are the timings of the election of the
+	// configuration snapshot writer, matching those of the manager.
+	configSnapshotWriterLeaseDuration = 15 * time.Second
+	configSnapshotWriterRenewDeadline = 10 * time.Second
+	configSnapshotWriterRetryPeriod   = 2 * time.Second
+)
+
+// configSnapshotWriterElection elects the instance of the controller which
+// stores the configuration snapshot when the instances don't elect a leader,
+// e.g. with DB-less Kong instances each configured by their own controller, as
+// they would otherwise all race to update the same Secret. The elected
+// instance holds a Lease named after the Secret, in its namespace.
+type configSnapshotWriterElection struct {
+	elector *leaderelection.LeaderElector
+}
+
+func newConfigSnapshotWriterElection(
+	logger logr.Logger,
+	kubeconfig *rest.Config,
+	secret k8stypes.NamespacedName,
+) (*configSnapshotWriterElection, error) {
+	clientset, err := kubernetes.NewForConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the hostname: %w", err)
+	}
+	lock, err := resourcelock.New(
+		resourcelock.LeasesResourceLock,
+		secret.Namespace,
+		secret.Name,
+		clientset.CoreV1(),
+		clientset.CoordinationV1(),
+		resourcelock.ResourceLockConfig{Identity: hostname + "_" + string(uuid.NewUUID())},
+	)
+	if err != nil {
+		return nil, err
+	}
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   configSnapshotWriterLeaseDuration,
+		RenewDeadline:   configSnapshotWriterRenewDeadline,
+		RetryPeriod:     configSnapshotWriterRetryPeriod,
+		ReleaseOnCancel: true,
+		Name:            "config-snapshot-writer",
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(context.Context) {
+				logger.Info("elected to store the configuration snapshot", "secret", secret.String())
+			},
+			OnStoppedLeading: func() {
+				// also called when the election stops without having been won
+				logger.V(1).Info("stopped running for storing the configuration snapshot", "secret", secret.String())
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &configSnapshotWriterElection{elector: elector}, nil
+}
+
+// Start runs for the Lease until ctx is done, again whenever it's lost.
+func (e *configSnapshotWriterElection) Start(ctx context.Context) error {
+	for ctx.Err() == nil {
+		e.elector.Run(ctx)
+	}
+	return nil
+}
+
+// NeedLeaderElection makes the election run on all the instances.
+func (e *configSnapshotWriterElection) NeedLeaderElection() bool {
+	return false
+}
+
+// IsWriter indicates whether this instance stores the configuration snapshot.
+func (e *configSnapshotWriterElection) IsWriter() bool {
+	return e.elector.IsLeader()
+}
@@ -0,0 +1,235 @@
+package manager
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+)
+
+// -----------------------------------------------------------------------------
+// Controller Manager - Standby Instances
+// -----------------------------------------------------------------------------
+
+// standbyManager runs the controllers added to it on all the instances of the
+// controller rather than only on the leader, so that standby instances keep
+// the cache of the data-plane client warm and can take over quickly. The
+// writes of its clients to Kubernetes are dropped until the instance is
+// elected, so that only the leader updates objects and their statuses. As the
+// reconcilers consider the dropped writes done, all the objects watched by its
+// controllers are reconciled again with Replay once the instance is elected.
+type standbyManager struct {
+	manager.Manager
+	client client.Client
+	cache  *replayCache
+}
+
+func newStandbyManager(mgr manager.Manager, logger logr.Logger) *standbyManager {
+	return &standbyManager{
+		Manager: mgr,
+		client: &leaderWritesClient{
+			Client:  mgr.GetClient(),
+			elected: mgr.Elected(),
+			logger:  logger,
+		},
+		cache: &replayCache{Cache: mgr.GetCache()},
+	}
+}
+
+// SetFields injects the dependencies of the controllers, making their sources
+// watch the informers of a cache which records their event handlers.
+func (m *standbyManager) SetFields(i interface{}) error {
+	if _, err := inject.CacheInto(m.cache, i); err != nil {
+		return err
+	}
+	return m.Manager.SetFields(i)
+}
+
+// Replay enqueues all the objects watched by the controllers of the manager.
+func (m *standbyManager) Replay() {
+	m.cache.replay()
+}
+
+// Add adds a runnable which runs on all the instances.
+func (m *standbyManager) Add(r manager.Runnable) error {
+	return m.Manager.Add(standbyRunnable{Runnable: r})
+}
+
+// GetClient provides a client whose writes are dropped until the instance is
+// elected.
+func (m *standbyManager) GetClient() client.Client {
+	return m.client
+}
+
+// standbyRunnable is a runnable which doesn't need leader election.
+type standbyRunnable struct {
+	manager.Runnable
+}
+
+func (standbyRunnable) NeedLeaderElection() bool {
+	return false
+}
+
+// leaderWritesClient is a client which drops writes until elected is closed.
+type leaderWritesClient struct {
+	client.Client
+	elected <-chan struct{}
+	logger  logr.Logger
+}
+
+func (c *leaderWritesClient) isLeader() bool {
+	select {
+	case <-c.elected:
+		return true
+	default:
+		return false
+	}
+}
+
+// dropped indicates that a write must be dropped, logging it if so.
+func (c *leaderWritesClient) dropped(verb string, obj client.Object) bool {
+	if c.isLeader() {
+		return false
+	}
+	c.logger.V(1).Info("not the leader, dropping write", "verb", verb,
+		"namespace", obj.GetNamespace(), "name", obj.GetName())
+	return true
+}
+
+func (c *leaderWritesClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if c.dropped("create", obj) {
+		return nil
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *leaderWritesClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	if c.dropped("delete", obj) {
+		return nil
+	}
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *leaderWritesClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if c.dropped("update", obj) {
+		return nil
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *leaderWritesClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if c.dropped("patch", obj) {
+		return nil
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *leaderWritesClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	if c.dropped("deletecollection", obj) {
+		return nil
+	}
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
+
+func (c *leaderWritesClient) Status() client.StatusWriter {
+	return &leaderStatusWriter{StatusWriter: c.Client.Status(), client: c}
+}
+
+// leaderStatusWriter is a status writer which drops writes until its client is
+// elected.
+type leaderStatusWriter struct {
+	client.StatusWriter
+	client *leaderWritesClient
+}
+
+func (w *leaderStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if w.client.dropped("update status", obj) {
+		return nil
+	}
+	return w.StatusWriter.Update(ctx, obj, opts...)
+}
+
+func (w *leaderStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if w.client.dropped("patch status", obj) {
+		return nil
+	}
+	return w.StatusWriter.Patch(ctx, obj, patch, opts...)
+}
+
+// replayCache is a cache whose informers record the event handlers added to
+// them, to replay the objects they hold to these handlers on demand.
+type replayCache struct {
+	cache.Cache
+
+	lock     sync.Mutex
+	handlers []recordedHandler
+}
+
+// recordedHandler is an event handler added to an informer.
+type recordedHandler struct {
+	informer cache.Informer
+	handler  toolscache.ResourceEventHandler
+}
+
+func (c *replayCache) GetInformer(ctx context.Context, obj client.Object) (cache.Informer, error) {
+	informer, err := c.Cache.GetInformer(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	return recordingInformer{Informer: informer, cache: c}, nil
+}
+
+func (c *replayCache) GetInformerForKind(ctx context.Context, gvk schema.GroupVersionKind) (cache.Informer, error) {
+	informer, err := c.Cache.GetInformerForKind(ctx, gvk)
+	if err != nil {
+		return nil, err
+	}
+	return recordingInformer{Informer: informer, cache: c}, nil
+}
+
+func (c *replayCache) record(informer cache.Informer, handler toolscache.ResourceEventHandler) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.handlers = append(c.handlers, recordedHandler{informer: informer, handler: handler})
+}
+
+// replay delivers the objects held by the informers to their recorded event
+// handlers as if they had just been created.
+func (c *replayCache) replay() {
+	c.lock.Lock()
+	handlers := c.handlers
+	c.lock.Unlock()
+	for _, h := range handlers {
+		store, ok := h.informer.(interface{ GetStore() toolscache.Store })
+		if !ok {
+			continue
+		}
+		for _, obj := range store.GetStore().List() {
+			h.handler.OnAdd(obj)
+		}
+	}
+}
+
+// recordingInformer is an informer which records the event handlers added to
+// it in its cache.
+type recordingInformer struct {
+	cache.Informer
+	cache *replayCache
+}
+
+func (i recordingInformer) AddEventHandler(handler toolscache.ResourceEventHandler) {
+	i.Informer.AddEventHandler(handler)
+	i.cache.record(i.Informer, handler)
+}
+
+func (i recordingInformer) AddEventHandlerWithResyncPeriod(handler toolscache.ResourceEventHandler, resyncPeriod time.Duration) {
+	i.Informer.AddEventHandlerWithResyncPeriod(handler, resyncPeriod)
+	i.cache.record(i.Informer, handler)
+}
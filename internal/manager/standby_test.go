@@ -0,0 +1,148 @@
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+func TestLeaderWritesClient(t *testing.T) {
+	ctx := context.Background()
+	elected := make(chan struct{})
+	c := &leaderWritesClient{
+		Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+			&corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "existing"}},
+		).Build(),
+		elected: elected,
+		logger:  logr.Discard(),
+	}
+	get := func(name string) (*corev1.Service, error) {
+		svc := &corev1.Service{}
+		return svc, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: name}, svc)
+	}
+
+	t.Run("writes are dropped until elected", func(t *testing.T) {
+		require.NoError(t, c.Create(ctx, &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "created"}}))
+		_, err := get("created")
+		assert.Error(t, err, "the Service is not created")
+
+		svc, err := get("existing")
+		require.NoError(t, err)
+		svc.Labels = map[string]string{"updated": "true"}
+		require.NoError(t, c.Update(ctx, svc))
+		svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}}
+		require.NoError(t, c.Status().Update(ctx, svc))
+		require.NoError(t, c.Delete(ctx, svc))
+
+		svc, err = get("existing")
+		require.NoError(t, err, "the Service is not deleted")
+		assert.Empty(t, svc.Labels, "the Service is not updated")
+		assert.Empty(t, svc.Status.LoadBalancer.Ingress, "the status of the Service is not updated")
+	})
+
+	close(elected)
+
+	t.Run("writes are applied once elected", func(t *testing.T) {
+		svc, err := get("existing")
+		require.NoError(t, err)
+		svc.Labels = map[string]string{"updated": "true"}
+		require.NoError(t, c.Update(ctx, svc))
+		svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}}
+		require.NoError(t, c.Status().Update(ctx, svc))
+
+		svc, err = get("existing")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"updated": "true"}, svc.Labels)
+		assert.Equal(t, []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}}, svc.Status.LoadBalancer.Ingress)
+
+		require.NoError(t, c.Delete(ctx, svc))
+		_, err = get("existing")
+		assert.Error(t, err, "the Service is deleted")
+	})
+}
+
+func TestStandbyRunnable(t *testing.T) {
+	var r manager.Runnable = standbyRunnable{Runnable: manager.RunnableFunc(func(context.Context) error { return nil })}
+	leaderElectionRunnable, ok := r.(manager.LeaderElectionRunnable)
+	require.True(t, ok)
+	assert.False(t, leaderElectionRunnable.NeedLeaderElection())
+}
+
+// storeInformer is an informer holding the objects of a store.
+type storeInformer struct {
+	cache.Informer
+	store    toolscache.Store
+	handlers []toolscache.ResourceEventHandler
+}
+
+func (i *storeInformer) AddEventHandler(handler toolscache.ResourceEventHandler) {
+	i.handlers = append(i.handlers, handler)
+}
+
+func (i *storeInformer) GetStore() toolscache.Store {
+	return i.store
+}
+
+// storeInformerCache is a cache providing the same informer for all objects.
+type storeInformerCache struct {
+	cache.Cache
+	informer *storeInformer
+}
+
+func (c storeInformerCache) GetInformer(context.Context, client.Object) (cache.Informer, error) {
+	return c.informer, nil
+}
+
+func (c storeInformerCache) WaitForCacheSync(context.Context) bool {
+	return true
+}
+
+func TestReplayCache(t *testing.T) {
+	informer := &storeInformer{store: toolscache.NewStore(toolscache.MetaNamespaceKeyFunc)}
+	for _, name := range []string{"foo", "bar"} {
+		require.NoError(t, informer.store.Add(&corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name}}))
+	}
+	c := &replayCache{Cache: storeInformerCache{informer: informer}}
+
+	ctx := context.Background()
+	src := &source.Kind{Type: &corev1.Service{}}
+	require.NoError(t, src.InjectCache(c))
+	queue := &recordingQueue{}
+	require.NoError(t, src.Start(ctx, &handler.EnqueueRequestForObject{}, queue))
+	require.NoError(t, src.WaitForSync(ctx))
+	require.Len(t, informer.handlers, 1, "the source watches the informer")
+	assert.Empty(t, queue.added)
+
+	c.replay()
+	assert.ElementsMatch(t, []interface{}{
+		reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "foo"}},
+		reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "bar"}},
+	}, queue.added, "the objects held by the informer are enqueued")
+	assert.Len(t, informer.handlers, 1, "replaying doesn't add event handlers")
+}
+
+// recordingQueue is a rate limiting queue recording the items added to it.
+type recordingQueue struct {
+	workqueue.RateLimitingInterface
+	added []interface{}
+}
+
+func (q *recordingQueue) Add(item interface{}) {
+	q.added = append(q.added, item)
+}
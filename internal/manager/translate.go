@@ -0,0 +1,147 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/go-logr/logr"
+	"github.com/kong/deck/file"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/deckgen"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
+	kongv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+	kongv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
+)
+
+// translateDefaultNamespace is the namespace of the namespaced objects of
+// manifests which don't specify one, as with kubectl.
+const translateDefaultNamespace = "default"
+
+// clusterScopedKinds are the kinds supported by the translation which aren't
+// namespaced.
+var clusterScopedKinds = map[schema.GroupKind]bool{
+	kongv1.SchemeGroupVersion.WithKind("KongClusterPlugin").GroupKind():      true,
+	kongv1beta1.SchemeGroupVersion.WithKind("KongCACertificate").GroupKind(): true,
+	kongv1beta1.SchemeGroupVersion.WithKind("KongPluginBundle").GroupKind():  true,
+	kongv1beta1.SchemeGroupVersion.WithKind("KongVault").GroupKind():         true,
+}
+
+// ErrTranslationFailures is returned by Translate when some objects couldn't
+// be translated, after writing the configuration of the others.
+var ErrTranslationFailures = errors.New("some objects couldn't be translated")
+
+// Translate reads Kubernetes objects from YAML or JSON manifests, translates
+// them into Kong configuration the way the controller configured by c would,
+// and writes the resulting declarative configuration to out in format,
+// without contacting a cluster or a Kong Admin API. Objects of kinds which
+// the controller doesn't translate are ignored. As no Admin API is available,
+// the configurations of plugins aren't filled with their defaults.
+func Translate(c *Config, logger logrus.FieldLogger, out io.Writer, format file.Format, manifests ...io.Reader) error {
+	featureGates, err := setupFeatureGates(logr.Discard(), c)
+	if err != nil {
+		return err
+	}
+	var objs []runtime.Object
+	for _, r := range manifests {
+		read, err := readManifests(logger, r)
+		if err != nil {
+			return err
+		}
+		objs = append(objs, read...)
+	}
+	cache, err := store.NewCacheStoresFromObjs(objs...)
+	if err != nil {
+		return fmt.Errorf("invalid object in manifests: %w", err)
+	}
+
+	p := parser.NewParser(logger, store.New(cache, c.IngressClassName, false, false, false, logger))
+	if featureGates[combinedRoutesFeature] {
+		p.EnableCombinedServiceRoutes()
+	}
+	state, err := p.Build()
+	if err != nil {
+		return fmt.Errorf("failed to translate the objects: %w", err)
+	}
+	failures := p.PopTranslationFailures()
+	for _, failure := range failures {
+		logger.WithField("object", fmt.Sprintf("%s %s/%s",
+			failure.Object.GetObjectKind().GroupVersionKind().Kind,
+			failure.Object.GetNamespace(), failure.Object.GetName(),
+		)).Error(failure.Message)
+	}
+
+	content := deckgen.ToDeckContent(context.Background(), logger, state, nil, c.FilterTags)
+	var b []byte
+	switch format {
+	case file.YAML:
+		b, err = yaml.Marshal(content)
+	case file.JSON:
+		b, err = json.MarshalIndent(content, "", "  ")
+	default:
+		return fmt.Errorf("unknown output format: %s", format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal the configuration: %w", err)
+	}
+	if _, err := out.Write(b); err != nil {
+		return fmt.Errorf("failed to write the configuration: %w", err)
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%w: %d translation failures", ErrTranslationFailures, len(failures))
+	}
+	return nil
+}
+
+// readManifests decodes the objects of a stream of YAML documents or JSON
+// objects, flattening lists and skipping the objects of kinds which aren't
+// translated.
+func readManifests(logger logrus.FieldLogger, manifests io.Reader) ([]runtime.Object, error) {
+	var objs []runtime.Object
+	add := func(obj *unstructured.Unstructured) {
+		gvk := obj.GroupVersionKind()
+		if !store.IsSupportedKind(gvk) {
+			logger.Debugf("ignoring %s %s, which isn't translated", gvk.Kind, obj.GetName())
+			return
+		}
+		if obj.GetNamespace() == "" && !clusterScopedKinds[gvk.GroupKind()] {
+			obj.SetNamespace(translateDefaultNamespace)
+		}
+		objs = append(objs, obj)
+	}
+
+	decoder := utilyaml.NewYAMLOrJSONDecoder(manifests, 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				return objs, nil
+			}
+			return nil, fmt.Errorf("failed to decode manifests: %w", err)
+		}
+		// empty documents
+		if len(obj.Object) == 0 {
+			continue
+		}
+		if !obj.IsList() {
+			add(obj)
+			continue
+		}
+		if err := obj.EachListItem(func(item runtime.Object) error {
+			add(item.(*unstructured.Unstructured))
+			return nil
+		}); err != nil {
+			return nil, fmt.Errorf("failed to decode manifests: %w", err)
+		}
+	}
+}
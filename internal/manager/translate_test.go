@@ -0,0 +1,96 @@
+package manager
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/kong/deck/file"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+const translateManifests = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: echo
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Service
+  metadata:
+    name: echo
+  spec:
+    ports:
+    - port: 80
+      protocol: TCP
+---
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: echo
+spec:
+  ingressClassName: kong
+  rules:
+  - host: echo.example
+    http:
+      paths:
+      - path: /
+        pathType: Prefix
+        backend:
+          service:
+            name: echo
+            port:
+              number: 80
+`
+
+const translateCACertificate = `{
+  "apiVersion": "configuration.konghq.com/v1beta1",
+  "kind": "KongCACertificate",
+  "metadata": {"name": "ca", "annotations": {"kubernetes.io/ingress.class": "kong"}},
+  "spec": {"secretRef": {"namespace": "default", "name": "missing", "key": "ca.crt"}}
+}`
+
+func TestTranslate(t *testing.T) {
+	c := &Config{IngressClassName: "kong", FilterTags: []string{"managed-by-ingress-controller"}}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	translate := func(format file.Format, manifests ...string) (*file.Content, error) {
+		var out bytes.Buffer
+		readers := make([]io.Reader, 0, len(manifests))
+		for _, m := range manifests {
+			readers = append(readers, strings.NewReader(m))
+		}
+		err := Translate(c, logger, &out, format, readers...)
+		content := &file.Content{}
+		require.NoError(t, yaml.Unmarshal(out.Bytes(), content))
+		return content, err
+	}
+
+	t.Log("verifying that the objects of the manifests are translated, in the default namespace")
+	for _, format := range []file.Format{file.YAML, file.JSON} {
+		content, err := translate(format, translateManifests)
+		require.NoError(t, err)
+		assert.Equal(t, "1.1", content.FormatVersion)
+		require.Len(t, content.Services, 1)
+		assert.Equal(t, "default.echo.pnum-80", *content.Services[0].Name)
+		require.Len(t, content.Services[0].Routes, 1)
+		assert.Equal(t, "default.echo.00", *content.Services[0].Routes[0].Name)
+	}
+
+	t.Log("verifying that translation failures are reported after writing the configuration")
+	content, err := translate(file.YAML, translateManifests, translateCACertificate)
+	assert.ErrorIs(t, err, ErrTranslationFailures)
+	assert.Len(t, content.Services, 1)
+
+	t.Log("verifying that invalid manifests are rejected")
+	_, err = translate(file.YAML, "kind: [")
+	assert.Error(t, err)
+}
@@ -0,0 +1,198 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+const (
+	// realIPHeaderForwardedFor is the header load-balancers use to pass client IPs to Kong.
+	realIPHeaderForwardedFor = "X-Forwarded-For"
+	// realIPHeaderProxyProtocol makes Kong read client IPs from the PROXY protocol header.
+	realIPHeaderProxyProtocol = "proxy_protocol"
+)
+
+// RealIPConfigurationPatch compares the real IP configuration of a Kong proxy
+// (from the "configuration" section of its Admin API root) with the CIDRs of the
+// load-balancers in front of it, and provides the environment variables which need
+// to be set on the proxy container for client IPs to survive the hop through the
+// load-balancers. No variables are returned if the proxy is already configured
+// appropriately. An error is returned if any of the CIDRs is invalid.
+func RealIPConfigurationPatch(kongRootConfig map[string]interface{}, trustedCIDRs []string) (map[string]string, error) {
+	desired := make([]*net.IPNet, 0, len(trustedCIDRs))
+	for _, cidr := range trustedCIDRs {
+		ipNet, err := parseCIDROrIP(cidr)
+		if err != nil {
+			return nil, err
+		}
+		desired = append(desired, ipNet)
+	}
+
+	current := make([]*net.IPNet, 0)
+	currentValues := stringsFromConfig(kongRootConfig["trusted_ips"])
+	for _, value := range currentValues {
+		// trusted_ips may include non-network values (e.g. "unix:"), which don't cover any CIDR
+		if ipNet, err := parseCIDROrIP(value); err == nil {
+			current = append(current, ipNet)
+		}
+	}
+
+	patch := make(map[string]string)
+
+	// the load-balancer CIDRs missing from trusted_ips are added to the existing ones
+	trusted := append([]string{}, currentValues...)
+	for i, ipNet := range desired {
+		if !isCoveredByAny(ipNet, current) {
+			trusted = append(trusted, trustedCIDRs[i])
+		}
+	}
+	if len(trusted) != len(currentValues) {
+		patch["KONG_TRUSTED_IPS"] = strings.Join(trusted, ",")
+	}
+
+	header, _ := kongRootConfig["real_ip_header"].(string)
+	switch {
+	case header == realIPHeaderProxyProtocol:
+		// client IPs are passed by the load-balancers through the PROXY protocol
+	case strings.EqualFold(header, realIPHeaderForwardedFor):
+		if recursive, _ := kongRootConfig["real_ip_recursive"].(string); recursive != "on" {
+			patch["KONG_REAL_IP_RECURSIVE"] = "on"
+		}
+	default:
+		patch["KONG_REAL_IP_HEADER"] = realIPHeaderForwardedFor
+		patch["KONG_REAL_IP_RECURSIVE"] = "on"
+	}
+
+	return patch, nil
+}
+
+// parseCIDROrIP parses a CIDR, or a single IP which is handled as a CIDR of its own.
+func parseCIDROrIP(value string) (*net.IPNet, error) {
+	if strings.Contains(value, "/") {
+		_, ipNet, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", value, err)
+		}
+		return ipNet, nil
+	}
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP %q", value)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
+
+// isCoveredByAny reports whether a network is fully contained in any of the given networks.
+func isCoveredByAny(inner *net.IPNet, networks []*net.IPNet) bool {
+	innerOnes, innerBits := inner.Mask.Size()
+	for _, outer := range networks {
+		outerOnes, outerBits := outer.Mask.Size()
+		if outerBits == innerBits && outerOnes <= innerOnes && outer.Contains(inner.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+// ProxyProtocolConfigurationPatch compares the configuration of a Kong proxy
+// (from the "configuration" section of its Admin API root) with the ports of
+// the listens which the load-balancers in front of it pass client IPs to with
+// the PROXY protocol, and provides the environment variables which need to be
+// set on the proxy container for these listens to accept the PROXY protocol
+// and for Kong to read client IPs from it. No variables are returned if the
+// proxy is already configured appropriately. Kong only uses the client IPs
+// passed by the load-balancers in its trusted_ips, which the controller can't
+// tell apart, so these aren't verified.
+func ProxyProtocolConfigurationPatch(kongRootConfig map[string]interface{}, proxyProtocolPorts []int) map[string]string {
+	patch := make(map[string]string)
+	if len(proxyProtocolPorts) == 0 {
+		return patch
+	}
+
+	// the listens the PROXY protocol is used with lack the parameter enabling it
+	if proxyListens, changed := proxyProtocolListens(kongRootConfig["proxy_listen"], proxyProtocolPorts); changed {
+		patch["KONG_PROXY_LISTEN"] = strings.Join(proxyListens, ", ")
+	}
+	if streamListens, changed := proxyProtocolListens(kongRootConfig["stream_listen"], proxyProtocolPorts); changed {
+		patch["KONG_STREAM_LISTEN"] = strings.Join(streamListens, ", ")
+	}
+	if header, _ := kongRootConfig["real_ip_header"].(string); header != realIPHeaderProxyProtocol {
+		patch["KONG_REAL_IP_HEADER"] = realIPHeaderProxyProtocol
+	}
+
+	return patch
+}
+
+// proxyProtocolListens provides the listens of a Kong configuration (e.g.
+// proxy_listen) with the proxy_protocol parameter added to those on the given
+// ports, and whether it was missing from any of them. UDP listens, which can't
+// use the PROXY protocol, are kept as they are.
+func proxyProtocolListens(value interface{}, ports []int) ([]string, bool) {
+	listens := stringsFromConfig(value)
+	changed := false
+	for i, listen := range listens {
+		fields := strings.Fields(listen)
+		if len(fields) == 0 || !containsPort(ports, listenPort(fields[0])) {
+			continue
+		}
+		if containsString(fields[1:], "udp") || containsString(fields[1:], realIPHeaderProxyProtocol) {
+			continue
+		}
+		listens[i] = listen + " " + realIPHeaderProxyProtocol
+		changed = true
+	}
+	return listens, changed
+}
+
+// listenPort provides the port of the address of a Kong listen, or 0 if it
+// has none (e.g. "off").
+func listenPort(address string) int {
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return 0
+	}
+	n, err := strconv.Atoi(port)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+func containsPort(ports []int, port int) bool {
+	for _, p := range ports {
+		if port != 0 && p == port {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// stringsFromConfig provides the values of a Kong configuration array, which
+// is absent (or not an array) when unset.
+func stringsFromConfig(value interface{}) []string {
+	values, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+	res := make([]string, 0, len(values))
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			res = append(res, s)
+		}
+	}
+	return res
+}
@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRealIPConfigurationPatch(t *testing.T) {
+	for _, tt := range []struct {
+		name         string
+		kongConfig   map[string]interface{}
+		trustedCIDRs []string
+		want         map[string]string
+		wantErr      bool
+	}{
+		{
+			name: "default Kong configuration",
+			kongConfig: map[string]interface{}{
+				"real_ip_header":    "X-Real-IP",
+				"real_ip_recursive": "off",
+			},
+			trustedCIDRs: []string{"10.0.0.0/8"},
+			want: map[string]string{
+				"KONG_TRUSTED_IPS":       "10.0.0.0/8",
+				"KONG_REAL_IP_HEADER":    "X-Forwarded-For",
+				"KONG_REAL_IP_RECURSIVE": "on",
+			},
+		},
+		{
+			name: "load-balancer CIDRs are added to the trusted ones",
+			kongConfig: map[string]interface{}{
+				"trusted_ips":       []interface{}{"unix:", "10.0.0.0/8"},
+				"real_ip_header":    "x-forwarded-for",
+				"real_ip_recursive": "on",
+			},
+			trustedCIDRs: []string{"10.1.0.0/16", "192.168.0.10", "2001:db8::/32"},
+			want: map[string]string{
+				"KONG_TRUSTED_IPS": "unix:,10.0.0.0/8,192.168.0.10,2001:db8::/32",
+			},
+		},
+		{
+			name: "trusted CIDRs covering the load-balancers",
+			kongConfig: map[string]interface{}{
+				"trusted_ips":       []interface{}{"0.0.0.0/0", "::/0"},
+				"real_ip_header":    "X-Forwarded-For",
+				"real_ip_recursive": "off",
+			},
+			trustedCIDRs: []string{"10.0.0.0/8", "2001:db8::1"},
+			want: map[string]string{
+				"KONG_REAL_IP_RECURSIVE": "on",
+			},
+		},
+		{
+			name: "PROXY protocol",
+			kongConfig: map[string]interface{}{
+				"trusted_ips":    []interface{}{"10.0.0.0/8"},
+				"real_ip_header": "proxy_protocol",
+			},
+			trustedCIDRs: []string{"10.0.0.1"},
+			want:         map[string]string{},
+		},
+		{
+			name:         "invalid CIDR",
+			kongConfig:   map[string]interface{}{},
+			trustedCIDRs: []string{"10.0.0.0/33"},
+			wantErr:      true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RealIPConfigurationPatch(tt.kongConfig, tt.trustedCIDRs)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestProxyProtocolConfigurationPatch(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		kongConfig map[string]interface{}
+		ports      []int
+		want       map[string]string
+	}{
+		{
+			name: "no PROXY protocol ports",
+			kongConfig: map[string]interface{}{
+				"real_ip_header": "X-Real-IP",
+				"proxy_listen":   []interface{}{"0.0.0.0:8000 reuseport backlog=16384"},
+			},
+			want: map[string]string{},
+		},
+		{
+			name: "PROXY protocol on the given listens",
+			kongConfig: map[string]interface{}{
+				"real_ip_header": "X-Real-IP",
+				"proxy_listen": []interface{}{
+					"0.0.0.0:8000 reuseport backlog=16384",
+					"0.0.0.0:8443 http2 ssl proxy_protocol reuseport backlog=16384",
+					"127.0.0.1:8100",
+				},
+				"stream_listen": []interface{}{"0.0.0.0:9000 reuseport", "0.0.0.0:9999 udp reuseport"},
+			},
+			ports: []int{8000, 8443, 9000, 9999},
+			want: map[string]string{
+				"KONG_PROXY_LISTEN": "0.0.0.0:8000 reuseport backlog=16384 proxy_protocol, " +
+					"0.0.0.0:8443 http2 ssl proxy_protocol reuseport backlog=16384, 127.0.0.1:8100",
+				"KONG_STREAM_LISTEN":  "0.0.0.0:9000 reuseport proxy_protocol, 0.0.0.0:9999 udp reuseport",
+				"KONG_REAL_IP_HEADER": "proxy_protocol",
+			},
+		},
+		{
+			name: "PROXY protocol already configured on the given listens",
+			kongConfig: map[string]interface{}{
+				"real_ip_header": "proxy_protocol",
+				"proxy_listen":   []interface{}{"0.0.0.0:8000 proxy_protocol"},
+				"stream_listen":  []interface{}{"off"},
+			},
+			ports: []int{8000},
+			want:  map[string]string{},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ProxyProtocolConfigurationPatch(tt.kongConfig, tt.ports))
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"errors"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
@@ -12,8 +14,41 @@ type CtrlFuncMetrics struct {
 	// TranslationCount is a Prometheus metric with semantics defined by its help string in NewCtrlFuncMetrics().
 	TranslationCount *prometheus.CounterVec
 
+	// ConfigPushErrorCount is a Prometheus metric with semantics defined by its help string in NewCtrlFuncMetrics().
+	ConfigPushErrorCount *prometheus.CounterVec
+
+	// ConfigPushSkippedCount is a Prometheus metric with semantics defined by its help string in NewCtrlFuncMetrics().
+	ConfigPushSkippedCount *prometheus.CounterVec
+
+	// ConfigHash is a Prometheus metric with semantics defined by its help string in NewCtrlFuncMetrics().
+	ConfigHash *prometheus.GaugeVec
+
 	// ConfigPushDuration is a Prometheus metric with semantics defined by its help string in NewCtrlFuncMetrics().
 	ConfigPushDuration *prometheus.HistogramVec
+
+	// TranslationDuration is a Prometheus metric with semantics defined by its help string in NewCtrlFuncMetrics().
+	TranslationDuration *prometheus.HistogramVec
+
+	// TranslationTimeoutCount is a Prometheus metric with semantics defined by its help string in NewCtrlFuncMetrics().
+	TranslationTimeoutCount prometheus.Counter
+
+	// ConfigEntityChangeCount is a Prometheus metric with semantics defined by its help string in NewCtrlFuncMetrics().
+	ConfigEntityChangeCount *prometheus.CounterVec
+
+	// FeatureEnabled is a Prometheus metric with semantics defined by its help string in NewCtrlFuncMetrics().
+	FeatureEnabled *prometheus.GaugeVec
+
+	// ConfigPropagationDuration is a Prometheus metric with semantics defined by its help string in NewCtrlFuncMetrics().
+	ConfigPropagationDuration *prometheus.HistogramVec
+
+	// TranslationFailedObjects is a Prometheus metric with semantics defined by its help string in NewCtrlFuncMetrics().
+	TranslationFailedObjects *prometheus.GaugeVec
+
+	// ConfigSnapshotTooLargeCount is a Prometheus metric with semantics defined by its help string in NewCtrlFuncMetrics().
+	ConfigSnapshotTooLargeCount prometheus.Counter
+
+	// PortConflicts is a Prometheus metric with semantics defined by its help string in NewCtrlFuncMetrics().
+	PortConflicts *prometheus.GaugeVec
 }
 
 const (
@@ -37,9 +72,76 @@ const (
 )
 
 const (
-	MetricNameConfigPushCount    = "ingress_controller_configuration_push_count"
-	MetricNameTranslationCount   = "ingress_controller_translation_count"
-	MetricNameConfigPushDuration = "ingress_controller_configuration_push_duration_milliseconds"
+	// ErrorClassValidation indicates that Kong rejected the configuration.
+	ErrorClassValidation string = "validation"
+	// ErrorClassAuth indicates that Kong refused the credentials of the controller.
+	ErrorClassAuth string = "auth"
+	// ErrorClassTransient indicates that Kong couldn't be reached or failed to process the configuration.
+	ErrorClassTransient string = "transient"
+
+	// ErrorClassKey defines the key of the metric label indicating the class of an error.
+	ErrorClassKey string = "error_class"
+)
+
+const (
+	// OperationCreated indicates that an entity was created.
+	OperationCreated string = "created"
+	// OperationUpdated indicates that an entity was updated.
+	OperationUpdated string = "updated"
+	// OperationDeleted indicates that an entity was deleted.
+	OperationDeleted string = "deleted"
+
+	// OperationKey defines the key of the metric label indicating which operation was performed on an entity.
+	OperationKey string = "operation"
+
+	// EntityTypeKey defines the key of the metric label indicating the type of a Kong entity.
+	EntityTypeKey string = "entity_type"
+)
+
+const (
+	// FeatureKey defines the key of the metric label indicating a translation feature.
+	FeatureKey string = "feature"
+)
+
+const (
+	// HashKey defines the key of the metric label indicating the checksum of a configuration.
+	HashKey string = "hash"
+)
+
+const (
+	// KindKey defines the key of the metric label indicating the kind of a Kubernetes object.
+	KindKey string = "kind"
+)
+
+const (
+	// SkipReasonUnchanged indicates that a configuration push was skipped because the configuration didn't change.
+	SkipReasonUnchanged string = "unchanged"
+	// SkipReasonStatusUnknown indicates that a configuration push was skipped because the status of Kong couldn't
+	// be checked.
+	SkipReasonStatusUnknown string = "status_unknown"
+	// SkipReasonCircuitOpen indicates that a configuration push was skipped because pushes to the Admin API are
+	// suspended after repeated failures.
+	SkipReasonCircuitOpen string = "circuit_open"
+
+	// SkipReasonKey defines the key of the metric label indicating why a configuration push was skipped.
+	SkipReasonKey string = "reason"
+)
+
+const (
+	MetricNameConfigPushCount          = "ingress_controller_configuration_push_count"
+	MetricNameConfigPushErrorCount     = "ingress_controller_configuration_push_error_count"
+	MetricNameConfigPushSkippedCount   = "ingress_controller_configuration_push_skipped_count"
+	MetricNameConfigHash               = "ingress_controller_configuration_hash_info"
+	MetricNameTranslationCount         = "ingress_controller_translation_count"
+	MetricNameConfigPushDuration       = "ingress_controller_configuration_push_duration_milliseconds"
+	MetricNameTranslationDuration      = "ingress_controller_translation_duration_milliseconds"
+	MetricNameTranslationTimeoutCount  = "ingress_controller_translation_timeout_count"
+	MetricNameConfigEntityChangeCount  = "ingress_controller_configuration_entity_change_count"
+	MetricNameFeatureEnabled           = "ingress_controller_translation_feature_enabled"
+	MetricNameConfigPropagationTime    = "ingress_controller_configuration_propagation_duration_milliseconds"
+	MetricNameTranslationFailedObjects = "ingress_controller_translation_failed_objects"
+	MetricNamePortConflicts            = "ingress_controller_translation_port_conflicts"
+	MetricNameConfigSnapshotTooLarge   = "ingress_controller_configuration_snapshot_too_large_count"
 )
 
 func NewCtrlFuncMetrics() *CtrlFuncMetrics {
@@ -58,6 +160,20 @@ func NewCtrlFuncMetrics() *CtrlFuncMetrics {
 			[]string{SuccessKey, ProtocolKey},
 		)
 
+	controllerMetrics.ConfigPushErrorCount =
+		prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: MetricNameConfigPushErrorCount,
+				Help: "Count of failed configuration pushes to Kong. `" +
+					ProtocolKey + "` describes the configuration protocol (" + ProtocolDBLess + " or " +
+					ProtocolDeck + ") in use. `" +
+					ErrorClassKey + "` describes whether Kong rejected the configuration (`" +
+					ErrorClassValidation + "`), refused the credentials of the controller (`" +
+					ErrorClassAuth + "`) or couldn't be reached or failed otherwise (`" + ErrorClassTransient + "`).",
+			},
+			[]string{ErrorClassKey, ProtocolKey},
+		)
+
 	controllerMetrics.TranslationCount =
 		prometheus.NewCounterVec(
 			prometheus.CounterOpts{
@@ -83,7 +199,145 @@ func NewCtrlFuncMetrics() *CtrlFuncMetrics {
 			[]string{SuccessKey, ProtocolKey},
 		)
 
-	metrics.Registry.MustRegister(controllerMetrics.ConfigPushCount, controllerMetrics.TranslationCount, controllerMetrics.ConfigPushDuration)
+	controllerMetrics.TranslationDuration =
+		prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name: MetricNameTranslationDuration,
+				Help: "How long it took to translate Kubernetes state to Kong state, in milliseconds. `" +
+					SuccessKey + "` describes whether there were unrecoverable errors (`" +
+					SuccessFalse + "`) or not (`" + SuccessTrue + "`).",
+				Buckets: prometheus.ExponentialBuckets(1, 1.5, 30),
+			},
+			[]string{SuccessKey},
+		)
+
+	controllerMetrics.TranslationTimeoutCount =
+		prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: MetricNameTranslationTimeoutCount,
+				Help: "Count of translations from Kubernetes state to Kong state which exceeded the " +
+					"translation timeout. The results of these translations are discarded and the " +
+					"last successfully applied configuration remains in place.",
+			},
+		)
+
+	controllerMetrics.ConfigEntityChangeCount =
+		prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: MetricNameConfigEntityChangeCount,
+				Help: "Count of Kong entities changed by successful configuration pushes to Kong. `" +
+					EntityTypeKey + "` describes the type of the Kong entity (e.g. service or route). `" +
+					OperationKey + "` describes whether the entity was `" + OperationCreated + "`, `" +
+					OperationUpdated + "` or `" + OperationDeleted + "`.",
+			},
+			[]string{EntityTypeKey, OperationKey},
+		)
+
+	controllerMetrics.FeatureEnabled =
+		prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricNameFeatureEnabled,
+				Help: "Whether a feature changing how Kubernetes state is translated to Kong state is " +
+					"in effect (1) or not (0). `" + FeatureKey + "` describes the feature.",
+			},
+			[]string{FeatureKey},
+		)
+
+	controllerMetrics.ConfigPropagationDuration =
+		prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name: MetricNameConfigPropagationTime,
+				Help: "How long it took for changes to Kubernetes objects to propagate to Kong, from when " +
+					"the controller observed them to the successful configuration push containing them, " +
+					"in milliseconds. `" + KindKey + "` describes the kind of the Kubernetes object.",
+				Buckets: prometheus.ExponentialBuckets(100, 1.33, 30),
+			},
+			[]string{KindKey},
+		)
+
+	controllerMetrics.TranslationFailedObjects =
+		prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricNameTranslationFailedObjects,
+				Help: "Number of Kubernetes objects which couldn't be fully translated to Kong state in the last " +
+					"translation. `" + KindKey + "` describes the kind of the Kubernetes objects.",
+			},
+			[]string{KindKey},
+		)
+
+	controllerMetrics.PortConflicts =
+		prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricNamePortConflicts,
+				Help: "Number of rules of TCPIngresses and UDPIngresses ignored in the last translation because an " +
+					"object taking precedence claims the same port. `" + KindKey + "` describes the kind of the " +
+					"Kubernetes objects whose rules are ignored.",
+			},
+			[]string{KindKey},
+		)
+
+	controllerMetrics.ConfigPushSkippedCount =
+		prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: MetricNameConfigPushSkippedCount,
+				Help: "Count of configuration pushes to Kong skipped while the configuration didn't change since the " +
+					"last successful push. `" + ProtocolKey + "` describes the configuration protocol (" +
+					ProtocolDBLess + " or " + ProtocolDeck + ") in use. `" + SkipReasonKey + "` describes why the " +
+					"push was skipped: " + SkipReasonUnchanged + " when Kong runs the configuration, " +
+					SkipReasonStatusUnknown + " when the status of Kong couldn't be checked, or " +
+					SkipReasonCircuitOpen + " when pushes are suspended after repeated failures.",
+			},
+			[]string{ProtocolKey, SkipReasonKey},
+		)
+
+	controllerMetrics.ConfigSnapshotTooLargeCount =
+		prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: MetricNameConfigSnapshotTooLarge,
+				Help: "Count of configurations applied to Kong which weren't stored in the configuration snapshot " +
+					"Secret as they exceed the size limit of Secrets, even gzipped. The previous snapshot is kept.",
+			},
+		)
+
+	controllerMetrics.ConfigHash =
+		prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricNameConfigHash,
+				Help: "Checksum of the configuration last successfully pushed to Kong, as the `" + HashKey +
+					"` label of the only series, which is always 1.",
+			},
+			[]string{HashKey},
+		)
+
+	// several clients can be created in a single process (e.g. by tests), in
+	// which case they share the collectors registered by the first one.
+	controllerMetrics.ConfigPushCount = register(controllerMetrics.ConfigPushCount).(*prometheus.CounterVec)
+	controllerMetrics.ConfigPushErrorCount = register(controllerMetrics.ConfigPushErrorCount).(*prometheus.CounterVec)
+	controllerMetrics.ConfigPushSkippedCount = register(controllerMetrics.ConfigPushSkippedCount).(*prometheus.CounterVec)
+	controllerMetrics.ConfigHash = register(controllerMetrics.ConfigHash).(*prometheus.GaugeVec)
+	controllerMetrics.TranslationCount = register(controllerMetrics.TranslationCount).(*prometheus.CounterVec)
+	controllerMetrics.ConfigPushDuration = register(controllerMetrics.ConfigPushDuration).(*prometheus.HistogramVec)
+	controllerMetrics.TranslationDuration = register(controllerMetrics.TranslationDuration).(*prometheus.HistogramVec)
+	controllerMetrics.TranslationTimeoutCount = register(controllerMetrics.TranslationTimeoutCount).(prometheus.Counter)
+	controllerMetrics.ConfigEntityChangeCount = register(controllerMetrics.ConfigEntityChangeCount).(*prometheus.CounterVec)
+	controllerMetrics.FeatureEnabled = register(controllerMetrics.FeatureEnabled).(*prometheus.GaugeVec)
+	controllerMetrics.ConfigPropagationDuration = register(controllerMetrics.ConfigPropagationDuration).(*prometheus.HistogramVec)
+	controllerMetrics.TranslationFailedObjects = register(controllerMetrics.TranslationFailedObjects).(*prometheus.GaugeVec)
+	controllerMetrics.PortConflicts = register(controllerMetrics.PortConflicts).(*prometheus.GaugeVec)
+	controllerMetrics.ConfigSnapshotTooLargeCount = register(controllerMetrics.ConfigSnapshotTooLargeCount).(prometheus.Counter)
 
 	return controllerMetrics
 }
+
+// register registers the collector with the controller-runtime registry and
+// returns it, or returns the identical collector which is already registered.
+func register(collector prometheus.Collector) prometheus.Collector {
+	if err := metrics.Registry.Register(collector); err != nil {
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+		if errors.As(err, &alreadyRegistered) {
+			return alreadyRegistered.ExistingCollector
+		}
+		panic(err)
+	}
+	return collector
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 	apiv1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	networkingv1beta1 "k8s.io/api/networking/v1beta1"
 	"k8s.io/client-go/tools/cache"
@@ -12,6 +13,7 @@ import (
 	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
+	mcsv1alpha1 "github.com/kong/kubernetes-ingress-controller/v2/internal/apis/multicluster/v1alpha1"
 	configurationv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
 	configurationv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
 )
@@ -30,26 +32,35 @@ func clusterResourceKeyFunc(obj interface{}) (string, error) {
 
 // FakeObjects can be used to populate a fake Store.
 type FakeObjects struct {
-	IngressesV1beta1   []*networkingv1beta1.Ingress
-	IngressesV1        []*networkingv1.Ingress
-	IngressClassesV1   []*networkingv1.IngressClass
-	HTTPRoutes         []*gatewayv1alpha2.HTTPRoute
-	UDPRoutes          []*gatewayv1alpha2.UDPRoute
-	TCPRoutes          []*gatewayv1alpha2.TCPRoute
-	TLSRoutes          []*gatewayv1alpha2.TLSRoute
-	ReferencePolicies  []*gatewayv1alpha2.ReferencePolicy
-	Gateways           []*gatewayv1alpha2.Gateway
-	TCPIngresses       []*configurationv1beta1.TCPIngress
-	UDPIngresses       []*configurationv1beta1.UDPIngress
-	Services           []*apiv1.Service
-	Endpoints          []*apiv1.Endpoints
-	Secrets            []*apiv1.Secret
-	KongPlugins        []*configurationv1.KongPlugin
-	KongClusterPlugins []*configurationv1.KongClusterPlugin
-	KongIngresses      []*configurationv1.KongIngress
-	KongConsumers      []*configurationv1.KongConsumer
+	IngressesV1beta1     []*networkingv1beta1.Ingress
+	IngressesV1          []*networkingv1.Ingress
+	IngressClassesV1     []*networkingv1.IngressClass
+	HTTPRoutes           []*gatewayv1alpha2.HTTPRoute
+	UDPRoutes            []*gatewayv1alpha2.UDPRoute
+	TCPRoutes            []*gatewayv1alpha2.TCPRoute
+	TLSRoutes            []*gatewayv1alpha2.TLSRoute
+	ReferencePolicies    []*gatewayv1alpha2.ReferencePolicy
+	Gateways             []*gatewayv1alpha2.Gateway
+	TCPIngresses         []*configurationv1beta1.TCPIngress
+	UDPIngresses         []*configurationv1beta1.UDPIngress
+	Services             []*apiv1.Service
+	Endpoints            []*apiv1.Endpoints
+	EndpointSlices       []*discoveryv1.EndpointSlice
+	Secrets              []*apiv1.Secret
+	KongPlugins          []*configurationv1.KongPlugin
+	KongClusterPlugins   []*configurationv1.KongClusterPlugin
+	KongIngresses        []*configurationv1.KongIngress
+	KongConsumers        []*configurationv1.KongConsumer
+	KongCACertificates   []*configurationv1beta1.KongCACertificate
+	KongUpstreamPolicies []*configurationv1beta1.KongUpstreamPolicy
+	KongPluginBundles    []*configurationv1beta1.KongPluginBundle
+	KongVaults           []*configurationv1beta1.KongVault
+
+	IngressClassParametersV1beta1 []*configurationv1beta1.IngressClassParameters
 
 	KnativeIngresses []*knative.Ingress
+
+	ServiceImports []*mcsv1alpha1.ServiceImport
 }
 
 // NewFakeStore creates a store backed by the objects passed in as arguments.
@@ -149,6 +160,13 @@ func NewFakeStore(
 			return nil, err
 		}
 	}
+	endpointSliceStore := cache.NewIndexer(keyFunc, endpointSliceIndexers)
+	for _, e := range objects.EndpointSlices {
+		err := endpointSliceStore.Add(e)
+		if err != nil {
+			return nil, err
+		}
+	}
 	kongIngressStore := cache.NewStore(keyFunc)
 	for _, k := range objects.KongIngresses {
 		err := kongIngressStore.Add(k)
@@ -177,6 +195,41 @@ func NewFakeStore(
 			return nil, err
 		}
 	}
+	kongCACertificateStore := cache.NewStore(clusterResourceKeyFunc)
+	for _, c := range objects.KongCACertificates {
+		err := kongCACertificateStore.Add(c)
+		if err != nil {
+			return nil, err
+		}
+	}
+	kongUpstreamPolicyStore := cache.NewStore(keyFunc)
+	for _, p := range objects.KongUpstreamPolicies {
+		err := kongUpstreamPolicyStore.Add(p)
+		if err != nil {
+			return nil, err
+		}
+	}
+	kongPluginBundleStore := cache.NewStore(clusterResourceKeyFunc)
+	for _, b := range objects.KongPluginBundles {
+		err := kongPluginBundleStore.Add(b)
+		if err != nil {
+			return nil, err
+		}
+	}
+	kongVaultStore := cache.NewStore(clusterResourceKeyFunc)
+	for _, v := range objects.KongVaults {
+		err := kongVaultStore.Add(v)
+		if err != nil {
+			return nil, err
+		}
+	}
+	ingressClassParametersV1beta1Store := cache.NewStore(keyFunc)
+	for _, p := range objects.IngressClassParametersV1beta1 {
+		err := ingressClassParametersV1beta1Store.Add(p)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	knativeIngressStore := cache.NewStore(keyFunc)
 	for _, ingress := range objects.KnativeIngresses {
@@ -185,6 +238,13 @@ func NewFakeStore(
 			return nil, err
 		}
 	}
+	serviceImportStore := cache.NewStore(keyFunc)
+	for _, serviceImport := range objects.ServiceImports {
+		err := serviceImportStore.Add(serviceImport)
+		if err != nil {
+			return nil, err
+		}
+	}
 	s = Store{
 		stores: CacheStores{
 			IngressV1beta1:  ingressV1beta1Store,
@@ -200,14 +260,23 @@ func NewFakeStore(
 			UDPIngress:      udpIngressStore,
 			Service:         serviceStore,
 			Endpoint:        endpointStore,
+			EndpointSlice:   endpointSliceStore,
 			Secret:          secretsStore,
 
-			Plugin:        kongPluginsStore,
-			ClusterPlugin: kongClusterPluginsStore,
-			Consumer:      consumerStore,
-			KongIngress:   kongIngressStore,
+			Plugin:             kongPluginsStore,
+			ClusterPlugin:      kongClusterPluginsStore,
+			Consumer:           consumerStore,
+			KongIngress:        kongIngressStore,
+			KongCACertificate:  kongCACertificateStore,
+			KongUpstreamPolicy: kongUpstreamPolicyStore,
+			KongPluginBundle:   kongPluginBundleStore,
+			KongVault:          kongVaultStore,
+
+			IngressClassParametersV1beta1: ingressClassParametersV1beta1Store,
 
 			KnativeIngress: knativeIngressStore,
+
+			ServiceImport: serviceImportStore,
 		},
 		ingressClass:                annotations.DefaultIngressClass,
 		isValidIngressClass:         annotations.IngressClassValidatorFuncFromObjectMeta(annotations.DefaultIngressClass),
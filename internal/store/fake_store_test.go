@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	apiv1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	networkingv1beta1 "k8s.io/api/networking/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -303,6 +304,86 @@ func TestFakeStoreIngressClassV1(t *testing.T) {
 	assert.Len(store.ListIngressClassesV1(), 2)
 }
 
+func TestFakeStoreIngressClassParametersV1beta1(t *testing.T) {
+	group := configurationv1beta1.SchemeGroupVersion.Group
+	namespaceScope := networkingv1.IngressClassParametersReferenceScopeNamespace
+	clusterScope := networkingv1.IngressClassParametersReferenceScopeCluster
+	params := &configurationv1beta1.IngressClassParameters{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "params",
+			Namespace: "default",
+		},
+		Spec: configurationv1beta1.IngressClassParametersSpec{
+			EnableLegacyRegexDetection: true,
+		},
+	}
+
+	for _, tt := range []struct {
+		name       string
+		parameters *networkingv1.IngressClassParametersReference
+		wantErr    bool
+		notFound   bool
+	}{
+		{
+			name:     "no parameters",
+			wantErr:  true,
+			notFound: true,
+		},
+		{
+			name:       "parameters of another kind",
+			parameters: &networkingv1.IngressClassParametersReference{APIGroup: &group, Kind: "KongIngress", Name: "params"},
+			wantErr:    true,
+			notFound:   true,
+		},
+		{
+			name: "cluster scoped parameters",
+			parameters: &networkingv1.IngressClassParametersReference{
+				APIGroup: &group, Kind: configurationv1beta1.IngressClassParametersKind, Name: "params", Scope: &clusterScope,
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing parameters",
+			parameters: &networkingv1.IngressClassParametersReference{
+				APIGroup: &group, Kind: configurationv1beta1.IngressClassParametersKind, Name: "missing",
+				Scope: &namespaceScope, Namespace: &params.Namespace,
+			},
+			wantErr:  true,
+			notFound: true,
+		},
+		{
+			name: "namespace scoped parameters",
+			parameters: &networkingv1.IngressClassParametersReference{
+				APIGroup: &group, Kind: configurationv1beta1.IngressClassParametersKind, Name: "params",
+				Scope: &namespaceScope, Namespace: &params.Namespace,
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := NewFakeStore(FakeObjects{
+				IngressClassesV1: []*networkingv1.IngressClass{{
+					ObjectMeta: metav1.ObjectMeta{Name: annotations.DefaultIngressClass},
+					Spec: networkingv1.IngressClassSpec{
+						Controller: IngressClassKongController,
+						Parameters: tt.parameters,
+					},
+				}},
+				IngressClassParametersV1beta1: []*configurationv1beta1.IngressClassParameters{params},
+			})
+			require.NoError(t, err)
+
+			got, err := store.GetIngressClassParametersV1beta1()
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Equal(t, tt.notFound, errors.As(err, &ErrNotFound{}))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, params, got)
+		})
+	}
+}
+
 func TestFakeStoreListTCPIngress(t *testing.T) {
 	assert := assert.New(t)
 	require := require.New(t)
@@ -498,6 +579,55 @@ func TestFakeStoreEndpiont(t *testing.T) {
 	assert.Nil(c)
 }
 
+func TestFakeStoreEndpointSlices(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	endpointSlices := []*discoveryv1.EndpointSlice{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-2",
+				Namespace: "default",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "foo"},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-1",
+				Namespace: "default",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "foo"},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-1",
+				Namespace: "other",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "foo"},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bar-1",
+				Namespace: "default",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "bar"},
+			},
+		},
+	}
+	store, err := NewFakeStore(FakeObjects{EndpointSlices: endpointSlices})
+	require.Nil(err)
+	require.NotNil(store)
+	c, err := store.GetEndpointSlicesForService("default", "foo")
+	assert.Nil(err)
+	require.Len(c, 2)
+	assert.Equal("foo-1", c[0].Name)
+	assert.Equal("foo-2", c[1].Name)
+
+	c, err = store.GetEndpointSlicesForService("default", "does-not-exist")
+	assert.NotNil(err)
+	assert.True(errors.As(err, &ErrNotFound{}))
+	assert.Nil(c)
+}
+
 func TestFakeStoreConsumer(t *testing.T) {
 	assert := assert.New(t)
 	require := require.New(t)
@@ -615,6 +745,9 @@ func TestFakeStoreClusterPlugins(t *testing.T) {
 	plugins, err = store.ListGlobalKongClusterPlugins()
 	assert.NoError(err)
 	assert.Len(plugins, 1)
+	plugins, err = store.ListKongClusterPlugins()
+	assert.NoError(err)
+	assert.Len(plugins, 1)
 
 	plugin, err := store.GetKongClusterPlugin("foo")
 	assert.NotNil(plugin)
@@ -626,6 +759,43 @@ func TestFakeStoreClusterPlugins(t *testing.T) {
 	assert.Nil(plugin)
 }
 
+func TestFakeStoreKongCACertificates(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	caCerts := []*configurationv1beta1.KongCACertificate{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo",
+				Annotations: map[string]string{
+					annotations.IngressClassKey: annotations.DefaultIngressClass,
+				},
+			},
+		},
+		{
+			// invalid due to lack of class, not loaded
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "bar",
+			},
+		},
+	}
+	store, err := NewFakeStore(FakeObjects{KongCACertificates: caCerts})
+	require.Nil(err)
+	require.NotNil(store)
+	caCerts, err = store.ListKongCACertificates()
+	assert.NoError(err)
+	assert.Len(caCerts, 1)
+
+	caCert, err := store.GetKongCACertificate("foo")
+	assert.NotNil(caCert)
+	assert.Nil(err)
+
+	caCert, err = store.GetKongCACertificate("does-not-exist")
+	assert.NotNil(err)
+	assert.True(errors.As(err, &ErrNotFound{}))
+	assert.Nil(caCert)
+}
+
 func TestFakeStoreSecret(t *testing.T) {
 	assert := assert.New(t)
 	require := require.New(t)
@@ -0,0 +1,158 @@
+package store
+
+import (
+	"errors"
+	"sync"
+
+	"k8s.io/client-go/tools/cache"
+)
+
+// ErrReadOnlySnapshot is returned when modifying the stores of a snapshot.
+var ErrReadOnlySnapshot = errors.New("store snapshots are read-only")
+
+// Snapshot provides read-only copies of the stores, which aren't affected by
+// later changes to them, so that translating the objects they hold works on a
+// consistent view of the cluster. The copies share the objects with the
+// stores, as the objects are replaced rather than modified when they change:
+// taking a snapshot only copies the index of the objects of the kinds which
+// changed since the previous snapshot, and reuses the copies of the others.
+// Stores which weren't created by NewCacheStores are shared as they are.
+func (c CacheStores) Snapshot() CacheStores {
+	c.l.RLock()
+	defer c.l.RUnlock()
+
+	return CacheStores{
+		IngressV1beta1: snapshotOf(c.IngressV1beta1),
+		IngressV1:      snapshotOf(c.IngressV1),
+		IngressClassV1: snapshotOf(c.IngressClassV1),
+		Service:        snapshotOf(c.Service),
+		Secret:         snapshotOf(c.Secret),
+		Endpoint:       snapshotOf(c.Endpoint),
+		EndpointSlice:  snapshotOf(c.EndpointSlice),
+
+		HTTPRoute:       snapshotOf(c.HTTPRoute),
+		UDPRoute:        snapshotOf(c.UDPRoute),
+		TCPRoute:        snapshotOf(c.TCPRoute),
+		TLSRoute:        snapshotOf(c.TLSRoute),
+		ReferencePolicy: snapshotOf(c.ReferencePolicy),
+		Gateway:         snapshotOf(c.Gateway),
+
+		Plugin:             snapshotOf(c.Plugin),
+		ClusterPlugin:      snapshotOf(c.ClusterPlugin),
+		Consumer:           snapshotOf(c.Consumer),
+		KongIngress:        snapshotOf(c.KongIngress),
+		TCPIngress:         snapshotOf(c.TCPIngress),
+		UDPIngress:         snapshotOf(c.UDPIngress),
+		KongCACertificate:  snapshotOf(c.KongCACertificate),
+		KongUpstreamPolicy: snapshotOf(c.KongUpstreamPolicy),
+		KongPluginBundle:   snapshotOf(c.KongPluginBundle),
+		KongVault:          snapshotOf(c.KongVault),
+
+		IngressClassParametersV1beta1: snapshotOf(c.IngressClassParametersV1beta1),
+
+		KnativeIngress: snapshotOf(c.KnativeIngress),
+		ServiceImport:  snapshotOf(c.ServiceImport),
+
+		l: &sync.RWMutex{},
+	}
+}
+
+func snapshotOf(s cache.Store) cache.Store {
+	if cow, ok := s.(*cowStore); ok {
+		return cow.snapshot()
+	}
+	return s
+}
+
+// cowStore is a cache.Indexer which provides read-only snapshots of its
+// objects, indexed the same way and reused until the store changes.
+type cowStore struct {
+	cache.Indexer
+	keyFunc  cache.KeyFunc
+	indexers cache.Indexers
+
+	lock    sync.Mutex
+	changed bool
+	last    cache.Store
+}
+
+func newCOWStore(keyFunc cache.KeyFunc) *cowStore {
+	return newCOWIndexer(keyFunc, cache.Indexers{})
+}
+
+func newCOWIndexer(keyFunc cache.KeyFunc, indexers cache.Indexers) *cowStore {
+	return &cowStore{Indexer: cache.NewIndexer(keyFunc, indexers), keyFunc: keyFunc, indexers: indexers}
+}
+
+func (s *cowStore) Add(obj interface{}) error {
+	defer s.markChanged()
+	return s.Indexer.Add(obj)
+}
+
+func (s *cowStore) Update(obj interface{}) error {
+	defer s.markChanged()
+	return s.Indexer.Update(obj)
+}
+
+func (s *cowStore) Delete(obj interface{}) error {
+	defer s.markChanged()
+	return s.Indexer.Delete(obj)
+}
+
+func (s *cowStore) Replace(list []interface{}, resourceVersion string) error {
+	defer s.markChanged()
+	return s.Indexer.Replace(list, resourceVersion)
+}
+
+// markChanged invalidates the last snapshot. It's called once the store has
+// been modified, so a snapshot taken concurrently is at worst taken again.
+func (s *cowStore) markChanged() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.changed = true
+}
+
+func (s *cowStore) snapshot() cache.Store {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.last != nil && !s.changed {
+		return s.last
+	}
+	s.changed = false
+	copied := cache.NewIndexer(s.keyFunc, s.indexers)
+	// the objects listed were all added with the same key and index
+	// functions, so none can fail to be added again
+	_ = copied.Replace(s.Indexer.List(), "")
+	s.last = readOnlyStore{copied}
+	return s.last
+}
+
+// readOnlyStore is a cache.Indexer which can't be modified, as it's shared by
+// the snapshots of a cowStore.
+type readOnlyStore struct {
+	cache.Indexer
+}
+
+func (readOnlyStore) Add(interface{}) error {
+	return ErrReadOnlySnapshot
+}
+
+func (readOnlyStore) Update(interface{}) error {
+	return ErrReadOnlySnapshot
+}
+
+func (readOnlyStore) Delete(interface{}) error {
+	return ErrReadOnlySnapshot
+}
+
+func (readOnlyStore) Replace([]interface{}, string) error {
+	return ErrReadOnlySnapshot
+}
+
+func (readOnlyStore) Resync() error {
+	return nil
+}
+
+func (readOnlyStore) AddIndexers(cache.Indexers) error {
+	return ErrReadOnlySnapshot
+}
@@ -0,0 +1,87 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCacheStoresSnapshot(t *testing.T) {
+	svc := func(name string) *corev1.Service {
+		return &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name}}
+	}
+	cs := NewCacheStores()
+	foo := svc("foo")
+	require.NoError(t, cs.Add(foo))
+	require.NoError(t, cs.Add(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"}}))
+
+	t.Log("verifying that snapshots share the objects of the stores")
+	snapshot := cs.Snapshot()
+	item, exists, err := snapshot.Get(svc("foo"))
+	require.NoError(t, err)
+	require.True(t, exists)
+	assert.Same(t, foo, item)
+	storer := New(snapshot, "kong", false, false, false, logrus.New())
+	found, err := storer.GetService("default", "foo")
+	require.NoError(t, err)
+	assert.Same(t, foo, found)
+
+	t.Log("verifying that snapshots aren't affected by later changes to the stores")
+	require.NoError(t, cs.Add(svc("bar")))
+	require.NoError(t, cs.Delete(foo))
+	assert.Len(t, snapshot.Service.List(), 1)
+	_, exists, err = snapshot.Get(svc("foo"))
+	require.NoError(t, err)
+	assert.True(t, exists)
+	_, exists, err = snapshot.Get(svc("bar"))
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	t.Log("verifying that the stores which didn't change aren't copied again")
+	next := cs.Snapshot()
+	assert.Same(t, snapshot.Secret.(readOnlyStore).Indexer, next.Secret.(readOnlyStore).Indexer)
+	assert.NotSame(t, snapshot.Service.(readOnlyStore).Indexer, next.Service.(readOnlyStore).Indexer)
+	assert.Len(t, next.Service.List(), 1)
+	_, exists, err = next.Get(svc("bar"))
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	t.Log("verifying that snapshots can't be modified")
+	assert.ErrorIs(t, next.Add(svc("baz")), ErrReadOnlySnapshot)
+	assert.ErrorIs(t, next.Delete(svc("bar")), ErrReadOnlySnapshot)
+	assert.Len(t, cs.Snapshot().Service.List(), 1)
+}
+
+func TestCacheStoresSnapshotEndpointSliceIndex(t *testing.T) {
+	endpointSlice := func(name, service string) *discoveryv1.EndpointSlice {
+		return &discoveryv1.EndpointSlice{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			Labels:    map[string]string{discoveryv1.LabelServiceName: service},
+		}}
+	}
+	cs := NewCacheStores()
+	require.NoError(t, cs.Add(endpointSlice("foo-1", "foo")))
+	require.NoError(t, cs.Add(endpointSlice("bar-1", "bar")))
+
+	t.Log("verifying that the EndpointSlices of snapshots are indexed by Service")
+	s := New(cs.Snapshot(), "kong", false, false, false, logrus.New())
+	endpointSlices, err := s.GetEndpointSlicesForService("default", "foo")
+	require.NoError(t, err)
+	require.Len(t, endpointSlices, 1)
+	assert.Equal(t, "foo-1", endpointSlices[0].Name)
+
+	t.Log("verifying that the index follows the changes to the EndpointSlices")
+	require.NoError(t, cs.Add(endpointSlice("foo-1", "bar")))
+	s = New(cs.Snapshot(), "kong", false, false, false, logrus.New())
+	_, err = s.GetEndpointSlicesForService("default", "foo")
+	assert.ErrorAs(t, err, &ErrNotFound{})
+	endpointSlices, err = s.GetEndpointSlicesForService("default", "bar")
+	require.NoError(t, err)
+	assert.Len(t, endpointSlices, 2)
+}
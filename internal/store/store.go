@@ -26,9 +26,11 @@ import (
 
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	extensions "k8s.io/api/extensions/v1beta1"
 	networkingv1 "k8s.io/api/networking/v1"
 	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured/unstructuredscheme"
 	"k8s.io/apimachinery/pkg/labels"
@@ -37,12 +39,14 @@ import (
 	serializer "k8s.io/apimachinery/pkg/runtime/serializer/json"
 	yamlserializer "k8s.io/apimachinery/pkg/runtime/serializer/yaml"
 	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
 	knative "knative.dev/networking/pkg/apis/networking/v1alpha1"
 	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	"sigs.k8s.io/yaml"
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
+	mcsv1alpha1 "github.com/kong/kubernetes-ingress-controller/v2/internal/apis/multicluster/v1alpha1"
 	ctrlutils "github.com/kong/kubernetes-ingress-controller/v2/internal/controllers/utils"
 	kongv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
 	kongv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
@@ -73,11 +77,17 @@ type Storer interface {
 	GetSecret(namespace, name string) (*corev1.Secret, error)
 	GetService(namespace, name string) (*corev1.Service, error)
 	GetEndpointsForService(namespace, name string) (*corev1.Endpoints, error)
+	GetEndpointSlicesForService(namespace, name string) ([]*discoveryv1.EndpointSlice, error)
+	GetServiceImport(namespace, name string) (*mcsv1alpha1.ServiceImport, error)
+	GetEndpointSlicesForServiceImport(namespace, name string) ([]*discoveryv1.EndpointSlice, error)
 	GetKongIngress(namespace, name string) (*kongv1.KongIngress, error)
 	GetKongPlugin(namespace, name string) (*kongv1.KongPlugin, error)
 	GetKongClusterPlugin(name string) (*kongv1.KongClusterPlugin, error)
 	GetKongConsumer(namespace, name string) (*kongv1.KongConsumer, error)
+	GetKongCACertificate(name string) (*kongv1beta1.KongCACertificate, error)
+	GetKongUpstreamPolicy(namespace, name string) (*kongv1beta1.KongUpstreamPolicy, error)
 	GetIngressClassV1(name string) (*networkingv1.IngressClass, error)
+	GetIngressClassParametersV1beta1() (*kongv1beta1.IngressClassParameters, error)
 
 	ListIngressesV1beta1() []*networkingv1beta1.Ingress
 	ListIngressesV1() []*networkingv1.Ingress
@@ -93,8 +103,12 @@ type Storer interface {
 	ListKnativeIngresses() ([]*knative.Ingress, error)
 	ListGlobalKongPlugins() ([]*kongv1.KongPlugin, error)
 	ListGlobalKongClusterPlugins() ([]*kongv1.KongClusterPlugin, error)
+	ListKongClusterPlugins() ([]*kongv1.KongClusterPlugin, error)
 	ListKongConsumers() []*kongv1.KongConsumer
 	ListCACerts() ([]*corev1.Secret, error)
+	ListKongCACertificates() ([]*kongv1beta1.KongCACertificate, error)
+	ListKongPluginBundles() ([]*kongv1beta1.KongPluginBundle, error)
+	ListKongVaults() ([]*kongv1beta1.KongVault, error)
 }
 
 // Store implements Storer and can be used to list Ingress, Services
@@ -126,6 +140,7 @@ type CacheStores struct {
 	Service        cache.Store
 	Secret         cache.Store
 	Endpoint       cache.Store
+	EndpointSlice  cache.Store
 
 	// Gateway API Stores
 	HTTPRoute       cache.Store
@@ -136,42 +151,60 @@ type CacheStores struct {
 	Gateway         cache.Store
 
 	// Kong Stores
-	Plugin        cache.Store
-	ClusterPlugin cache.Store
-	Consumer      cache.Store
-	KongIngress   cache.Store
-	TCPIngress    cache.Store
-	UDPIngress    cache.Store
+	Plugin             cache.Store
+	ClusterPlugin      cache.Store
+	Consumer           cache.Store
+	KongIngress        cache.Store
+	TCPIngress         cache.Store
+	UDPIngress         cache.Store
+	KongCACertificate  cache.Store
+	KongUpstreamPolicy cache.Store
+	KongPluginBundle   cache.Store
+	KongVault          cache.Store
+
+	IngressClassParametersV1beta1 cache.Store
 
 	// Knative Stores
 	KnativeIngress cache.Store
 
+	// Multi-Cluster Services Stores
+	ServiceImport cache.Store
+
 	l *sync.RWMutex
 }
 
 // NewCacheStores is a convenience function for CacheStores to initialize all attributes with new cache stores
 func NewCacheStores() CacheStores {
 	return CacheStores{
-		IngressV1beta1:  cache.NewStore(keyFunc),
-		IngressV1:       cache.NewStore(keyFunc),
-		IngressClassV1:  cache.NewStore(clusterResourceKeyFunc),
-		Service:         cache.NewStore(keyFunc),
-		Secret:          cache.NewStore(keyFunc),
-		Endpoint:        cache.NewStore(keyFunc),
-		HTTPRoute:       cache.NewStore(keyFunc),
-		UDPRoute:        cache.NewStore(keyFunc),
-		TCPRoute:        cache.NewStore(keyFunc),
-		TLSRoute:        cache.NewStore(keyFunc),
-		ReferencePolicy: cache.NewStore(keyFunc),
-		Gateway:         cache.NewStore(keyFunc),
-		Plugin:          cache.NewStore(keyFunc),
-		ClusterPlugin:   cache.NewStore(clusterResourceKeyFunc),
-		Consumer:        cache.NewStore(keyFunc),
-		KongIngress:     cache.NewStore(keyFunc),
-		TCPIngress:      cache.NewStore(keyFunc),
-		UDPIngress:      cache.NewStore(keyFunc),
-		KnativeIngress:  cache.NewStore(keyFunc),
-		l:               &sync.RWMutex{},
+		IngressV1beta1:     newCOWStore(keyFunc),
+		IngressV1:          newCOWStore(keyFunc),
+		IngressClassV1:     newCOWStore(clusterResourceKeyFunc),
+		Service:            newCOWStore(keyFunc),
+		Secret:             newCOWStore(keyFunc),
+		Endpoint:           newCOWStore(keyFunc),
+		EndpointSlice:      newCOWIndexer(keyFunc, endpointSliceIndexers),
+		HTTPRoute:          newCOWStore(keyFunc),
+		UDPRoute:           newCOWStore(keyFunc),
+		TCPRoute:           newCOWStore(keyFunc),
+		TLSRoute:           newCOWStore(keyFunc),
+		ReferencePolicy:    newCOWStore(keyFunc),
+		Gateway:            newCOWStore(keyFunc),
+		Plugin:             newCOWStore(keyFunc),
+		ClusterPlugin:      newCOWStore(clusterResourceKeyFunc),
+		Consumer:           newCOWStore(keyFunc),
+		KongIngress:        newCOWStore(keyFunc),
+		TCPIngress:         newCOWStore(keyFunc),
+		UDPIngress:         newCOWStore(keyFunc),
+		KongCACertificate:  newCOWStore(clusterResourceKeyFunc),
+		KongUpstreamPolicy: newCOWStore(keyFunc),
+		KongPluginBundle:   newCOWStore(clusterResourceKeyFunc),
+		KongVault:          newCOWStore(clusterResourceKeyFunc),
+		KnativeIngress:     newCOWStore(keyFunc),
+		ServiceImport:      newCOWStore(keyFunc),
+
+		IngressClassParametersV1beta1: newCOWStore(keyFunc),
+
+		l: &sync.RWMutex{},
 	}
 }
 
@@ -217,6 +250,13 @@ func NewCacheStoresFromObjs(objs ...runtime.Object) (CacheStores, error) {
 	return c, nil
 }
 
+// IsSupportedKind indicates whether objects of the provided kind can be added
+// to CacheStores.
+func IsSupportedKind(gvk schema.GroupVersionKind) bool {
+	_, err := mkObjFromGVK(gvk)
+	return err == nil
+}
+
 // Get checks whether or not there's already some version of the provided object present in the cache.
 func (c CacheStores) Get(obj runtime.Object) (item interface{}, exists bool, err error) {
 	c.l.RLock()
@@ -240,6 +280,8 @@ func (c CacheStores) Get(obj runtime.Object) (item interface{}, exists bool, err
 		return c.Secret.Get(obj)
 	case *corev1.Endpoints:
 		return c.Endpoint.Get(obj)
+	case *discoveryv1.EndpointSlice:
+		return c.EndpointSlice.Get(obj)
 	// ----------------------------------------------------------------------------
 	// Kubernetes Gateway API Support
 	// ----------------------------------------------------------------------------
@@ -270,11 +312,23 @@ func (c CacheStores) Get(obj runtime.Object) (item interface{}, exists bool, err
 		return c.TCPIngress.Get(obj)
 	case *kongv1beta1.UDPIngress:
 		return c.UDPIngress.Get(obj)
+	case *kongv1beta1.KongCACertificate:
+		return c.KongCACertificate.Get(obj)
+	case *kongv1beta1.KongUpstreamPolicy:
+		return c.KongUpstreamPolicy.Get(obj)
+	case *kongv1beta1.KongPluginBundle:
+		return c.KongPluginBundle.Get(obj)
+	case *kongv1beta1.KongVault:
+		return c.KongVault.Get(obj)
+	case *kongv1beta1.IngressClassParameters:
+		return c.IngressClassParametersV1beta1.Get(obj)
 	// ----------------------------------------------------------------------------
 	// 3rd Party API Support
 	// ----------------------------------------------------------------------------
 	case *knative.Ingress:
 		return c.KnativeIngress.Get(obj)
+	case *mcsv1alpha1.ServiceImport:
+		return c.ServiceImport.Get(obj)
 	}
 	return nil, false, fmt.Errorf("%T is not a supported cache object type", obj)
 }
@@ -303,6 +357,8 @@ func (c CacheStores) Add(obj runtime.Object) error {
 		return c.Secret.Add(obj)
 	case *corev1.Endpoints:
 		return c.Endpoint.Add(obj)
+	case *discoveryv1.EndpointSlice:
+		return c.EndpointSlice.Add(obj)
 	// ----------------------------------------------------------------------------
 	// Kubernetes Gateway API Support
 	// ----------------------------------------------------------------------------
@@ -333,11 +389,23 @@ func (c CacheStores) Add(obj runtime.Object) error {
 		return c.TCPIngress.Add(obj)
 	case *kongv1beta1.UDPIngress:
 		return c.UDPIngress.Add(obj)
+	case *kongv1beta1.KongCACertificate:
+		return c.KongCACertificate.Add(obj)
+	case *kongv1beta1.KongUpstreamPolicy:
+		return c.KongUpstreamPolicy.Add(obj)
+	case *kongv1beta1.KongPluginBundle:
+		return c.KongPluginBundle.Add(obj)
+	case *kongv1beta1.KongVault:
+		return c.KongVault.Add(obj)
+	case *kongv1beta1.IngressClassParameters:
+		return c.IngressClassParametersV1beta1.Add(obj)
 	// ----------------------------------------------------------------------------
 	// 3rd Party API Support
 	// ----------------------------------------------------------------------------
 	case *knative.Ingress:
 		return c.KnativeIngress.Add(obj)
+	case *mcsv1alpha1.ServiceImport:
+		return c.ServiceImport.Add(obj)
 	default:
 		return fmt.Errorf("cannot add unsupported kind %q to the store", obj.GetObjectKind().GroupVersionKind())
 	}
@@ -367,6 +435,8 @@ func (c CacheStores) Delete(obj runtime.Object) error {
 		return c.Secret.Delete(obj)
 	case *corev1.Endpoints:
 		return c.Endpoint.Delete(obj)
+	case *discoveryv1.EndpointSlice:
+		return c.EndpointSlice.Delete(obj)
 	// ----------------------------------------------------------------------------
 	// Kubernetes Gateway API Support
 	// ----------------------------------------------------------------------------
@@ -397,16 +467,84 @@ func (c CacheStores) Delete(obj runtime.Object) error {
 		return c.TCPIngress.Delete(obj)
 	case *kongv1beta1.UDPIngress:
 		return c.UDPIngress.Delete(obj)
+	case *kongv1beta1.KongCACertificate:
+		return c.KongCACertificate.Delete(obj)
+	case *kongv1beta1.KongUpstreamPolicy:
+		return c.KongUpstreamPolicy.Delete(obj)
+	case *kongv1beta1.KongPluginBundle:
+		return c.KongPluginBundle.Delete(obj)
+	case *kongv1beta1.KongVault:
+		return c.KongVault.Delete(obj)
+	case *kongv1beta1.IngressClassParameters:
+		return c.IngressClassParametersV1beta1.Delete(obj)
 	// ----------------------------------------------------------------------------
 	// 3rd Party API Support
 	// ----------------------------------------------------------------------------
 	case *knative.Ingress:
 		return c.KnativeIngress.Delete(obj)
+	case *mcsv1alpha1.ServiceImport:
+		return c.ServiceImport.Delete(obj)
 	default:
 		return fmt.Errorf("cannot delete unsupported kind %q from the store", obj.GetObjectKind().GroupVersionKind())
 	}
 }
 
+// Names of the indexes of the EndpointSlices by the namespace/name of the
+// Service and the ServiceImport they belong to.
+const (
+	endpointSliceServiceIndex       = "service"
+	endpointSliceServiceImportIndex = "serviceImport"
+)
+
+// endpointSliceIndexers index EndpointSlices by the Service and ServiceImport
+// they belong to, so that the EndpointSlices of a Service are looked up without
+// going through all of them.
+var endpointSliceIndexers = cache.Indexers{
+	endpointSliceServiceIndex:       endpointSliceLabelIndexFunc(discoveryv1.LabelServiceName),
+	endpointSliceServiceImportIndex: endpointSliceLabelIndexFunc(mcsv1alpha1.LabelServiceName),
+}
+
+func endpointSliceLabelIndexFunc(label string) cache.IndexFunc {
+	return func(obj interface{}) ([]string, error) {
+		endpointSlice, ok := obj.(*discoveryv1.EndpointSlice)
+		if !ok {
+			return nil, nil
+		}
+		value, ok := endpointSlice.Labels[label]
+		if !ok {
+			return nil, nil
+		}
+		return []string{endpointSlice.Namespace + "/" + value}, nil
+	}
+}
+
+// UIDs returns the UIDs of all the objects in the cache.
+func (c CacheStores) UIDs() map[types.UID]struct{} {
+	c.l.RLock()
+	defer c.l.RUnlock()
+
+	uids := make(map[types.UID]struct{})
+	for _, s := range []cache.Store{
+		c.IngressV1beta1, c.IngressV1, c.IngressClassV1, c.Service, c.Secret, c.Endpoint, c.EndpointSlice,
+		c.HTTPRoute, c.UDPRoute, c.TCPRoute, c.TLSRoute, c.ReferencePolicy, c.Gateway,
+		c.Plugin, c.ClusterPlugin, c.Consumer, c.KongIngress, c.TCPIngress, c.UDPIngress,
+		c.KongCACertificate, c.KongUpstreamPolicy, c.KongPluginBundle, c.KongVault,
+		c.IngressClassParametersV1beta1, c.KnativeIngress, c.ServiceImport,
+	} {
+		if s == nil {
+			continue
+		}
+		for _, item := range s.List() {
+			obj, err := meta.Accessor(item)
+			if err != nil {
+				continue
+			}
+			uids[obj.GetUID()] = struct{}{}
+		}
+	}
+	return uids
+}
+
 // New creates a new object store to be used in the ingress controller
 func New(cs CacheStores, ingressClass string, processClasslessIngressV1Beta1 bool, processClasslessIngressV1 bool,
 	processClasslessKongConsumer bool, logger logrus.FieldLogger) Storer {
@@ -731,6 +869,64 @@ func (s Store) GetEndpointsForService(namespace, name string) (*corev1.Endpoints
 	return eps.(*corev1.Endpoints), nil
 }
 
+// GetEndpointSlicesForService returns the EndpointSlices of service
+// 'namespace/name' inside k8s.
+func (s Store) GetEndpointSlicesForService(namespace, name string) ([]*discoveryv1.EndpointSlice, error) {
+	endpointSlices := s.listEndpointSlicesByLabel(endpointSliceServiceIndex, discoveryv1.LabelServiceName, namespace, name)
+	if len(endpointSlices) == 0 {
+		return nil, ErrNotFound{fmt.Sprintf("EndpointSlices for service %v/%v not found", namespace, name)}
+	}
+	return endpointSlices, nil
+}
+
+// GetServiceImport returns the 'name' ServiceImport resource in namespace.
+func (s Store) GetServiceImport(namespace, name string) (*mcsv1alpha1.ServiceImport, error) {
+	key := fmt.Sprintf("%v/%v", namespace, name)
+	serviceImport, exists, err := s.stores.ServiceImport.GetByKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrNotFound{fmt.Sprintf("ServiceImport %v not found", key)}
+	}
+	return serviceImport.(*mcsv1alpha1.ServiceImport), nil
+}
+
+// GetEndpointSlicesForServiceImport returns the EndpointSlices imported for
+// the ServiceImport in namespace, sorted by name.
+func (s Store) GetEndpointSlicesForServiceImport(namespace, name string) ([]*discoveryv1.EndpointSlice, error) {
+	endpointSlices := s.listEndpointSlicesByLabel(endpointSliceServiceImportIndex, mcsv1alpha1.LabelServiceName, namespace, name)
+	if len(endpointSlices) == 0 {
+		return nil, ErrNotFound{fmt.Sprintf("EndpointSlices for service import %v/%v not found", namespace, name)}
+	}
+	return endpointSlices, nil
+}
+
+// listEndpointSlicesByLabel lists the EndpointSlices in namespace whose label
+// is set to value, sorted by name, looking them up in the index of the label
+// unless the store isn't indexed (e.g. fake stores).
+func (s Store) listEndpointSlicesByLabel(index, label, namespace, value string) []*discoveryv1.EndpointSlice {
+	objs := s.stores.EndpointSlice.List
+	if indexer, ok := s.stores.EndpointSlice.(cache.Indexer); ok {
+		if indexed, err := indexer.ByIndex(index, namespace+"/"+value); err == nil {
+			objs = func() []interface{} { return indexed }
+		}
+	}
+
+	var endpointSlices []*discoveryv1.EndpointSlice
+	for _, obj := range objs() {
+		endpointSlice, ok := obj.(*discoveryv1.EndpointSlice)
+		if !ok || endpointSlice.Namespace != namespace || endpointSlice.Labels[label] != value {
+			continue
+		}
+		endpointSlices = append(endpointSlices, endpointSlice)
+	}
+	sort.SliceStable(endpointSlices, func(i, j int) bool {
+		return endpointSlices[i].Name < endpointSlices[j].Name
+	})
+	return endpointSlices
+}
+
 // GetKongPlugin returns the 'name' KongPlugin resource in namespace.
 func (s Store) GetKongPlugin(namespace, name string) (*kongv1.KongPlugin, error) {
 	key := fmt.Sprintf("%v/%v", namespace, name)
@@ -756,6 +952,31 @@ func (s Store) GetKongClusterPlugin(name string) (*kongv1.KongClusterPlugin, err
 	return p.(*kongv1.KongClusterPlugin), nil
 }
 
+// GetKongCACertificate returns the 'name' KongCACertificate resource.
+func (s Store) GetKongCACertificate(name string) (*kongv1beta1.KongCACertificate, error) {
+	c, exists, err := s.stores.KongCACertificate.GetByKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrNotFound{fmt.Sprintf("KongCACertificate %v not found", name)}
+	}
+	return c.(*kongv1beta1.KongCACertificate), nil
+}
+
+// GetKongUpstreamPolicy returns the 'name' KongUpstreamPolicy resource in namespace.
+func (s Store) GetKongUpstreamPolicy(namespace, name string) (*kongv1beta1.KongUpstreamPolicy, error) {
+	key := fmt.Sprintf("%v/%v", namespace, name)
+	p, exists, err := s.stores.KongUpstreamPolicy.GetByKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrNotFound{fmt.Sprintf("KongUpstreamPolicy %v not found", name)}
+	}
+	return p.(*kongv1beta1.KongUpstreamPolicy), nil
+}
+
 // GetKongIngress returns the 'name' KongIngress resource in namespace.
 func (s Store) GetKongIngress(namespace, name string) (*kongv1.KongIngress, error) {
 	key := fmt.Sprintf("%v/%v", namespace, name)
@@ -794,6 +1015,35 @@ func (s Store) GetIngressClassV1(name string) (*networkingv1.IngressClass, error
 	return p.(*networkingv1.IngressClass), nil
 }
 
+// GetIngressClassParametersV1beta1 returns the IngressClassParameters the
+// parameters of the controller's IngressClass reference. It returns ErrNotFound
+// if the IngressClass doesn't exist or doesn't reference IngressClassParameters.
+func (s Store) GetIngressClassParametersV1beta1() (*kongv1beta1.IngressClassParameters, error) {
+	ingressClass, err := s.GetIngressClassV1(s.ingressClass)
+	if err != nil {
+		return nil, err
+	}
+	ref := ingressClass.Spec.Parameters
+	if ref == nil || ref.APIGroup == nil || *ref.APIGroup != kongv1beta1.SchemeGroupVersion.Group ||
+		ref.Kind != kongv1beta1.IngressClassParametersKind {
+		return nil, ErrNotFound{fmt.Sprintf("IngressClass %v doesn't reference IngressClassParameters", ingressClass.Name)}
+	}
+	// IngressClassParameters are namespaced, so they can only be referenced
+	// with the namespace scope
+	if ref.Scope == nil || *ref.Scope != networkingv1.IngressClassParametersReferenceScopeNamespace || ref.Namespace == nil {
+		return nil, fmt.Errorf("IngressClass %v must reference IngressClassParameters with the %s scope and a namespace",
+			ingressClass.Name, networkingv1.IngressClassParametersReferenceScopeNamespace)
+	}
+	p, exists, err := s.stores.IngressClassParametersV1beta1.GetByKey(*ref.Namespace + "/" + ref.Name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrNotFound{fmt.Sprintf("IngressClassParameters %v/%v not found", *ref.Namespace, ref.Name)}
+	}
+	return p.(*kongv1beta1.IngressClassParameters), nil
+}
+
 // ListKongConsumers returns all KongConsumers filtered by the ingress.class
 // annotation.
 func (s Store) ListKongConsumers() []*kongv1.KongConsumer {
@@ -859,6 +1109,23 @@ func (s Store) ListGlobalKongClusterPlugins() ([]*kongv1.KongClusterPlugin, erro
 	return plugins, nil
 }
 
+// ListKongClusterPlugins returns all KongClusterPlugin resources
+// filtered by the ingress.class annotation.
+func (s Store) ListKongClusterPlugins() ([]*kongv1.KongClusterPlugin, error) {
+	var plugins []*kongv1.KongClusterPlugin
+	err := cache.ListAll(s.stores.ClusterPlugin, labels.NewSelector(),
+		func(ob interface{}) {
+			p, ok := ob.(*kongv1.KongClusterPlugin)
+			if ok && s.isValidIngressClass(&p.ObjectMeta, annotations.IngressClassKey, s.getIngressClassHandling()) {
+				plugins = append(plugins, p)
+			}
+		})
+	if err != nil {
+		return nil, err
+	}
+	return plugins, nil
+}
+
 // ListCACerts returns all Secrets containing the label
 // "konghq.com/ca-cert"="true".
 func (s Store) ListCACerts() ([]*corev1.Secret, error) {
@@ -882,6 +1149,77 @@ func (s Store) ListCACerts() ([]*corev1.Secret, error) {
 	return secrets, nil
 }
 
+// ListKongCACertificates returns all KongCACertificate resources filtered by
+// the ingress.class annotation.
+func (s Store) ListKongCACertificates() ([]*kongv1beta1.KongCACertificate, error) {
+	var certs []*kongv1beta1.KongCACertificate
+	err := cache.ListAll(s.stores.KongCACertificate, labels.NewSelector(),
+		func(ob interface{}) {
+			c, ok := ob.(*kongv1beta1.KongCACertificate)
+			if ok && s.isValidIngressClass(&c.ObjectMeta, annotations.IngressClassKey, s.getIngressClassHandling()) {
+				certs = append(certs, c)
+			}
+		})
+	if err != nil {
+		return nil, err
+	}
+	return certs, nil
+}
+
+// ListKongPluginBundles returns all KongPluginBundles which target the ingress
+// class of the controller, sorted by name.
+func (s Store) ListKongPluginBundles() ([]*kongv1beta1.KongPluginBundle, error) {
+	var bundles []*kongv1beta1.KongPluginBundle
+	err := cache.ListAll(s.stores.KongPluginBundle, labels.NewSelector(),
+		func(ob interface{}) {
+			b, ok := ob.(*kongv1beta1.KongPluginBundle)
+			if ok && s.isBundleForIngressClass(b) {
+				bundles = append(bundles, b)
+			}
+		})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(bundles, func(i, j int) bool {
+		return bundles[i].Name < bundles[j].Name
+	})
+	return bundles, nil
+}
+
+// isBundleForIngressClass indicates whether a KongPluginBundle targets the
+// ingress class of the controller.
+func (s Store) isBundleForIngressClass(bundle *kongv1beta1.KongPluginBundle) bool {
+	if len(bundle.Spec.IngressClasses) == 0 {
+		return true
+	}
+	for _, class := range bundle.Spec.IngressClasses {
+		if class == s.ingressClass {
+			return true
+		}
+	}
+	return false
+}
+
+// ListKongVaults returns all KongVault resources filtered by the ingress.class
+// annotation, sorted by name.
+func (s Store) ListKongVaults() ([]*kongv1beta1.KongVault, error) {
+	var vaults []*kongv1beta1.KongVault
+	err := cache.ListAll(s.stores.KongVault, labels.NewSelector(),
+		func(ob interface{}) {
+			v, ok := ob.(*kongv1beta1.KongVault)
+			if ok && s.isValidIngressClass(&v.ObjectMeta, annotations.IngressClassKey, s.getIngressClassHandling()) {
+				vaults = append(vaults, v)
+			}
+		})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(vaults, func(i, j int) bool {
+		return vaults[i].Name < vaults[j].Name
+	})
+	return vaults, nil
+}
+
 func (s Store) networkingIngressV1Beta1(obj interface{}) *networkingv1beta1.Ingress {
 	switch obj := obj.(type) {
 	case *networkingv1beta1.Ingress:
@@ -965,6 +1303,8 @@ func mkObjFromGVK(gvk schema.GroupVersionKind) (runtime.Object, error) {
 		return &corev1.Secret{}, nil
 	case corev1.SchemeGroupVersion.WithKind("Endpoints"):
 		return &corev1.Endpoints{}, nil
+	case discoveryv1.SchemeGroupVersion.WithKind("EndpointSlice"):
+		return &discoveryv1.EndpointSlice{}, nil
 	// ----------------------------------------------------------------------------
 	// Kubernetes Gateway APIs
 	// ----------------------------------------------------------------------------
@@ -977,6 +1317,16 @@ func mkObjFromGVK(gvk schema.GroupVersionKind) (runtime.Object, error) {
 		return &kongv1.KongIngress{}, nil
 	case kongv1beta1.SchemeGroupVersion.WithKind("UDPIngress"):
 		return &kongv1beta1.UDPIngress{}, nil
+	case kongv1beta1.SchemeGroupVersion.WithKind("KongCACertificate"):
+		return &kongv1beta1.KongCACertificate{}, nil
+	case kongv1beta1.SchemeGroupVersion.WithKind("KongUpstreamPolicy"):
+		return &kongv1beta1.KongUpstreamPolicy{}, nil
+	case kongv1beta1.SchemeGroupVersion.WithKind("KongPluginBundle"):
+		return &kongv1beta1.KongPluginBundle{}, nil
+	case kongv1beta1.SchemeGroupVersion.WithKind("KongVault"):
+		return &kongv1beta1.KongVault{}, nil
+	case kongv1beta1.SchemeGroupVersion.WithKind(kongv1beta1.IngressClassParametersKind):
+		return &kongv1beta1.IngressClassParameters{}, nil
 	case kongv1.SchemeGroupVersion.WithKind("KongPlugin"):
 		return &kongv1.KongPlugin{}, nil
 	case kongv1.SchemeGroupVersion.WithKind("KongClusterPlugin"):
@@ -988,6 +1338,8 @@ func mkObjFromGVK(gvk schema.GroupVersionKind) (runtime.Object, error) {
 	// ----------------------------------------------------------------------------
 	case knative.SchemeGroupVersion.WithKind("Ingress"):
 		return &knative.Ingress{}, nil
+	case mcsv1alpha1.SchemeGroupVersion.WithKind("ServiceImport"):
+		return &mcsv1alpha1.ServiceImport{}, nil
 	default:
 		return nil, fmt.Errorf("%s is not a supported runtime.Object", gvk)
 	}
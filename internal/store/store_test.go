@@ -14,6 +14,7 @@ import (
 	netv1 "k8s.io/api/networking/v1"
 	networking "k8s.io/api/networking/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
@@ -204,6 +205,14 @@ spec:
 	assert.True(t, exists)
 }
 
+func TestCacheStoresUIDs(t *testing.T) {
+	cs := NewCacheStores()
+	require.NoError(t, cs.Add(&corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "echo", UID: "service"}}))
+	require.NoError(t, cs.Add(&netv1.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "echo", UID: "ingress"}}))
+	assert.Equal(t, map[types.UID]struct{}{"service": {}, "ingress": {}}, cs.UIDs())
+	assert.Equal(t, cs.UIDs(), cs.Snapshot().UIDs())
+}
+
 func Test_getIngressClassHandling(t *testing.T) {
 	tests := []struct {
 		name string
@@ -0,0 +1,179 @@
+// Package tracing instruments the pipeline from a change to a Kubernetes
+// object to Kong accepting the configuration it translates to with
+// OpenTelemetry spans.
+//
+// Spans are only recorded once Setup has configured an exporter: until then
+// the global OpenTelemetry tracer provider, which doesn't record anything, is
+// used.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/manager/metadata"
+)
+
+const (
+	// TracerName is the name of the tracer which records the spans of the
+	// controller.
+	TracerName = "github.com/kong/kubernetes-ingress-controller"
+
+	// ServiceName is the name the controller reports its spans under.
+	ServiceName = "kong-ingress-controller"
+
+	// maxChangeLinks is the maximum number of changes a configuration update
+	// is linked to, matching the default limit of links per span.
+	maxChangeLinks = 128
+
+	// otlpExportTimeout is the timeout of a request exporting spans.
+	otlpExportTimeout = 10 * time.Second
+)
+
+// Attribute keys of the spans of the controller.
+const (
+	KindKey      = attribute.Key("k8s.object.kind")
+	NamespaceKey = attribute.Key("k8s.namespace.name")
+	NameKey      = attribute.Key("k8s.object.name")
+	ProtocolKey  = attribute.Key("kong.config.protocol")
+	ChangedKey   = attribute.Key("kong.config.changed")
+)
+
+// Config configures the export of spans.
+type Config struct {
+	// Endpoint is the URL of the OTLP/HTTP traces endpoint of a collector,
+	// e.g. http://otel-collector:4318/v1/traces.
+	Endpoint string
+	// Headers are the headers sent with each export request, e.g. to
+	// authenticate to the collector.
+	Headers map[string]string
+	// SamplingRatio is the ratio of traces which are recorded, between 0 and 1.
+	SamplingRatio float64
+}
+
+// Tracer provides the tracer which records the spans of the controller.
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
+
+// Setup configures the global tracer provider to export spans to an OTLP
+// collector, returning a function which flushes the remaining spans and stops
+// exporting them. Export failures are logged to logger.
+func Setup(logger logr.Logger, cfg Config) (func(context.Context) error, error) {
+	if cfg.SamplingRatio < 0 || cfg.SamplingRatio > 1 {
+		return nil, fmt.Errorf("sampling ratio must be between 0 and 1, got %g", cfg.SamplingRatio)
+	}
+	exporter, err := newOTLPExporter(cfg.Endpoint, cfg.Headers)
+	if err != nil {
+		return nil, err
+	}
+	resource, err := sdkresource.Merge(sdkresource.Default(), sdkresource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceNameKey.String(ServiceName),
+		semconv.ServiceVersionKey.String(metadata.Release),
+	))
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SamplingRatio))),
+	)
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.Error(err, "failed to export spans")
+	}))
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// newOTLPExporter provides an exporter sending spans to an OTLP/HTTP traces
+// endpoint, with the given headers.
+func newOTLPExporter(endpoint string, headers map[string]string) (*otlptrace.Exporter, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q: %w", endpoint, err)
+	}
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(u.Host),
+		otlptracehttp.WithHeaders(headers),
+		otlptracehttp.WithTimeout(otlpExportTimeout),
+	}
+	switch u.Scheme {
+	case "http":
+		opts = append(opts, otlptracehttp.WithInsecure())
+	case "https":
+	default:
+		return nil, fmt.Errorf("invalid OTLP endpoint %q: scheme must be http or https", endpoint)
+	}
+	if u.Path != "" {
+		opts = append(opts, otlptracehttp.WithURLPath(u.Path))
+	}
+	// the client only connects once spans are exported
+	return otlptracehttp.New(context.Background(), opts...)
+}
+
+// ObjectAttributes provides the attributes identifying a Kubernetes object.
+func ObjectAttributes(kind string, nn k8stypes.NamespacedName) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		KindKey.String(kind),
+		NamespaceKey.String(nn.Namespace),
+		NameKey.String(nn.Name),
+	}
+}
+
+// EndSpan ends a span, marking it as failed if err isn't nil.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// ChangeLinks collects the spans of the changes to Kubernetes objects which
+// the next configuration update applies, so that the span of the update can be
+// linked to the spans of the changes which caused it. The zero value is ready
+// to use.
+type ChangeLinks struct {
+	lock  sync.Mutex
+	links []trace.Link
+}
+
+// Add records the span of ctx, if any, as a change applied by the next update.
+// Only the oldest changes are recorded when there are too many of them, as
+// they are the ones the update took the longest to apply.
+func (l *ChangeLinks) Add(ctx context.Context) {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if len(l.links) < maxChangeLinks {
+		l.links = append(l.links, trace.Link{SpanContext: spanContext})
+	}
+}
+
+// Pop provides the recorded changes, and forgets them.
+func (l *ChangeLinks) Pop() []trace.Link {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	links := l.links
+	l.links = nil
+	return links
+}
@@ -0,0 +1,90 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+func TestSetup(t *testing.T) {
+	var (
+		path    string
+		headers http.Header
+		body    []byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, headers = r.URL.Path, r.Header
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	shutdown, err := Setup(logr.Discard(), Config{
+		Endpoint:      server.URL + "/v1/traces",
+		Headers:       map[string]string{"Authorization": "Bearer token"},
+		SamplingRatio: 1,
+	})
+	require.NoError(t, err)
+	_, span := Tracer().Start(context.Background(), "KongClient.Update")
+	span.End()
+	require.NoError(t, shutdown(context.Background()), "the remaining spans are flushed on shutdown")
+
+	assert.Equal(t, "/v1/traces", path)
+	assert.Equal(t, "application/x-protobuf", headers.Get("Content-Type"))
+	assert.Equal(t, "Bearer token", headers.Get("Authorization"))
+	assert.Contains(t, string(body), "KongClient.Update")
+}
+
+func TestSetupInvalidConfig(t *testing.T) {
+	for _, cfg := range []Config{
+		{Endpoint: "otel-collector:4318"},
+		{Endpoint: "grpc://otel-collector:4317"},
+		{Endpoint: "http://otel-collector:4318/v1/traces", SamplingRatio: 2},
+	} {
+		_, err := Setup(logr.Discard(), cfg)
+		assert.Error(t, err, cfg.Endpoint)
+	}
+}
+
+func TestSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer(TracerName)
+
+	ctx, reconcile := tracer.Start(context.Background(), "Reconcile NetV1Ingress",
+		trace.WithAttributes(ObjectAttributes("Ingress", k8stypes.NamespacedName{Namespace: "default", Name: "echo"})...))
+	reconcile.End()
+	links := &ChangeLinks{}
+	links.Add(ctx)
+	links.Add(context.Background())
+	ctx, update := tracer.Start(context.Background(), "KongClient.Update", trace.WithLinks(links.Pop()...))
+	_, build := tracer.Start(ctx, "parser.Build")
+	EndSpan(build, errors.New("translation failed"))
+	EndSpan(update, nil)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	assert.Equal(t, ObjectAttributes("Ingress", k8stypes.NamespacedName{Namespace: "default", Name: "echo"}), spans[0].Attributes())
+
+	assert.Equal(t, "parser.Build", spans[1].Name())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "translation failed", spans[1].Status().Description)
+	require.Len(t, spans[1].Events(), 1, "the error is recorded as an event")
+
+	assert.Equal(t, "KongClient.Update", spans[2].Name())
+	assert.Equal(t, codes.Unset, spans[2].Status().Code)
+	require.Len(t, spans[2].Links(), 1, "the update is linked to the change it applies")
+	assert.Equal(t, reconcile.SpanContext(), spans[2].Links()[0].SpanContext)
+	assert.Empty(t, links.Pop(), "the changes are linked to a single update")
+}
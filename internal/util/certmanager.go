@@ -0,0 +1,91 @@
+package util
+
+import (
+	"sort"
+	"strings"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
+)
+
+// CertManagerCertificateGVK is the kind of the cert-manager Certificates
+// issuing certificates for the hosts of Ingresses and HTTPRoutes.
+var CertManagerCertificateGVK = schema.GroupVersionKind{
+	Group:   "cert-manager.io",
+	Version: "v1",
+	Kind:    "Certificate",
+}
+
+// CertManagerIssuer references the cert-manager Issuer or ClusterIssuer an
+// object requests certificates from.
+type CertManagerIssuer struct {
+	Name string
+	Kind string
+}
+
+// CertManagerIssuerFromAnnotations provides the issuer requested by the
+// konghq.com/cert-manager-issuer or konghq.com/cert-manager-cluster-issuer
+// annotation, the former taking precedence, and whether one is requested.
+func CertManagerIssuerFromAnnotations(anns map[string]string) (CertManagerIssuer, bool) {
+	if name := annotations.ExtractCertManagerIssuer(anns); name != "" {
+		return CertManagerIssuer{Name: name, Kind: "Issuer"}, true
+	}
+	if name := annotations.ExtractCertManagerClusterIssuer(anns); name != "" {
+		return CertManagerIssuer{Name: name, Kind: "ClusterIssuer"}, true
+	}
+	return CertManagerIssuer{}, false
+}
+
+// CertManagerSecretName provides the name of both the cert-manager Certificate
+// issued for the hosts of an object and the Secret it stores the certificate
+// in. The kind is part of it so that an Ingress and an HTTPRoute sharing a
+// name don't share a certificate.
+func CertManagerSecretName(obj client.Object) string {
+	var kind string
+	switch obj.(type) {
+	case *networkingv1.Ingress:
+		kind = "ingress"
+	case *gatewayv1alpha2.HTTPRoute:
+		kind = "httproute"
+	default:
+		kind = strings.ToLower(obj.GetObjectKind().GroupVersionKind().Kind)
+	}
+	return "kong-" + kind + "-" + obj.GetName()
+}
+
+// CertManagerHosts provides the sorted hosts of an object lacking TLS, which
+// the certificate issued for it covers: the rule hosts of an Ingress that none
+// of its TLS sections cover, or the hostnames of an HTTPRoute.
+func CertManagerHosts(obj client.Object) []string {
+	seen := make(map[string]bool)
+	var hosts []string
+	add := func(host string) {
+		if host != "" && !seen[host] {
+			seen[host] = true
+			hosts = append(hosts, host)
+		}
+	}
+
+	switch obj := obj.(type) {
+	case *networkingv1.Ingress:
+		for _, tls := range obj.Spec.TLS {
+			for _, host := range tls.Hosts {
+				seen[host] = true
+			}
+		}
+		for _, rule := range obj.Spec.Rules {
+			add(rule.Host)
+		}
+	case *gatewayv1alpha2.HTTPRoute:
+		for _, hostname := range obj.Spec.Hostnames {
+			add(string(hostname))
+		}
+	}
+
+	sort.Strings(hosts)
+	return hosts
+}
@@ -0,0 +1,51 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+func TestCertManagerIssuerFromAnnotations(t *testing.T) {
+	issuer, ok := CertManagerIssuerFromAnnotations(map[string]string{
+		"konghq.com/cert-manager-issuer":         "namespaced",
+		"konghq.com/cert-manager-cluster-issuer": "cluster",
+	})
+	assert.True(t, ok)
+	assert.Equal(t, CertManagerIssuer{Name: "namespaced", Kind: "Issuer"}, issuer)
+
+	issuer, ok = CertManagerIssuerFromAnnotations(map[string]string{"konghq.com/cert-manager-cluster-issuer": "cluster"})
+	assert.True(t, ok)
+	assert.Equal(t, CertManagerIssuer{Name: "cluster", Kind: "ClusterIssuer"}, issuer)
+
+	_, ok = CertManagerIssuerFromAnnotations(nil)
+	assert.False(t, ok)
+}
+
+func TestCertManagerHosts(t *testing.T) {
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+		Spec: networkingv1.IngressSpec{
+			TLS: []networkingv1.IngressTLS{{Hosts: []string{"secure.example.com"}, SecretName: "secure"}},
+			Rules: []networkingv1.IngressRule{
+				{Host: "secure.example.com"},
+				{Host: "b.example.com"},
+				{},
+				{Host: "a.example.com"},
+				{Host: "b.example.com"},
+			},
+		},
+	}
+	assert.Equal(t, []string{"a.example.com", "b.example.com"}, CertManagerHosts(ingress))
+	assert.Equal(t, "kong-ingress-foo", CertManagerSecretName(ingress))
+
+	httproute := &gatewayv1alpha2.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+		Spec:       gatewayv1alpha2.HTTPRouteSpec{Hostnames: []gatewayv1alpha2.Hostname{"b.example.com", "a.example.com"}},
+	}
+	assert.Equal(t, []string{"a.example.com", "b.example.com"}, CertManagerHosts(httproute))
+	assert.Equal(t, "kong-httproute-foo", CertManagerSecretName(httproute))
+}
@@ -6,10 +6,28 @@ import "github.com/kong/deck/file"
 type ConfigDump struct {
 	Config file.Content
 	Failed bool
+
+	// Hash is the hex-encoded checksum of the config, only set for configs
+	// which were successfully applied.
+	Hash string
 }
 
 // ConfigDumpDiagnostic contains settings and channels for receiving diagnostic configuration dumps
 type ConfigDumpDiagnostic struct {
 	DumpsIncludeSensitive bool
 	Configs               chan ConfigDump
+	TranslationFailures   chan []TranslationFailureReport
+}
+
+// TranslationFailureReport describes a Kubernetes object which couldn't be
+// translated into Kong configuration, as of the last update.
+type TranslationFailureReport struct {
+	Kind      string   `json:"kind"`
+	Namespace string   `json:"namespace,omitempty"`
+	Name      string   `json:"name"`
+	Errors    []string `json:"errors"`
+
+	// Excluded indicates that all the configuration translated from the
+	// object was excluded from the data-plane.
+	Excluded bool `json:"excluded"`
 }
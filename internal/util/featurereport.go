@@ -0,0 +1,29 @@
+package util
+
+// FeatureReport describes which translation features are in effect, i.e. how
+// the controller translates Kubernetes objects into Kong configuration.
+type FeatureReport struct {
+	CombinedServiceRoutes   bool `json:"combinedServiceRoutes"`
+	RegexPathPrefix         bool `json:"regexPathPrefix"`
+	EndpointSliceTargets    bool `json:"endpointSliceTargets"`
+	FallbackConfiguration   bool `json:"fallbackConfiguration"`
+	PartialSync             bool `json:"partialSync"`
+	KubernetesObjectReports bool `json:"kubernetesObjectReports"`
+
+	// RouterFlavor is the router_flavor of Kong, empty for versions prior to 3.0.
+	RouterFlavor string `json:"routerFlavor,omitempty"`
+	DBMode       string `json:"dbMode"`
+}
+
+// Features provides whether each of the features of the report which can be
+// toggled is enabled, by name.
+func (r FeatureReport) Features() map[string]bool {
+	return map[string]bool{
+		"combined_service_routes":   r.CombinedServiceRoutes,
+		"regex_path_prefix":         r.RegexPathPrefix,
+		"endpoint_slice_targets":    r.EndpointSliceTargets,
+		"fallback_configuration":    r.FallbackConfiguration,
+		"partial_sync":              r.PartialSync,
+		"kubernetes_object_reports": r.KubernetesObjectReports,
+	}
+}
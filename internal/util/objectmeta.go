@@ -1,7 +1,11 @@
 package util
 
 import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -9,7 +13,9 @@ import (
 type K8sObjectInfo struct {
 	Name             string
 	Namespace        string
+	UID              types.UID
 	Annotations      map[string]string
+	Labels           map[string]string
 	GroupVersionKind schema.GroupVersionKind
 }
 
@@ -25,10 +31,32 @@ func FromK8sObject(obj client.Object) K8sObjectInfo {
 	ret := K8sObjectInfo{
 		Name:        obj.GetName(),
 		Namespace:   obj.GetNamespace(),
+		UID:         obj.GetUID(),
 		Annotations: deepCopy(obj.GetAnnotations()),
+		Labels:      deepCopy(obj.GetLabels()),
 	}
 	if gvk := obj.GetObjectKind().GroupVersionKind(); gvk.String() != "" {
 		ret.GroupVersionKind = gvk
 	}
 	return ret
 }
+
+// Key provides a key identifying the described object among objects of all
+// kinds.
+func (info K8sObjectInfo) Key() string {
+	return fmt.Sprintf("%s/%s/%s", info.GroupVersionKind.String(), info.Namespace, info.Name)
+}
+
+// ToK8sObject provides a minimal client.Object for the described object, which
+// includes all the information needed to reference it (e.g. in Events).
+func (info K8sObjectInfo) ToK8sObject() client.Object {
+	obj := &metav1.PartialObjectMetadata{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      info.Name,
+			Namespace: info.Namespace,
+			UID:       info.UID,
+		},
+	}
+	obj.SetGroupVersionKind(info.GroupVersionKind)
+	return obj
+}
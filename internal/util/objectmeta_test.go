@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	networkingv1beta1 "k8s.io/api/networking/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -27,6 +28,7 @@ func TestFromK8sObject(t *testing.T) {
 				Name:        "name",
 				Namespace:   "namespace",
 				Annotations: map[string]string{},
+				Labels:      map[string]string{},
 			},
 		},
 		{
@@ -42,6 +44,23 @@ func TestFromK8sObject(t *testing.T) {
 				Name:        "name",
 				Namespace:   "namespace",
 				Annotations: map[string]string{"a": "1", "b": "2"},
+				Labels:      map[string]string{},
+			},
+		},
+		{
+			name: "has labels",
+			in: &networkingv1beta1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "name",
+					Namespace: "namespace",
+					Labels:    map[string]string{"tier": "public"},
+				},
+			},
+			want: K8sObjectInfo{
+				Name:        "name",
+				Namespace:   "namespace",
+				Annotations: map[string]string{},
+				Labels:      map[string]string{"tier": "public"},
 			},
 		},
 	} {
@@ -51,3 +70,16 @@ func TestFromK8sObject(t *testing.T) {
 		})
 	}
 }
+
+func TestK8sObjectInfoKey(t *testing.T) {
+	ingress := K8sObjectInfo{
+		Name:             "name",
+		Namespace:        "namespace",
+		GroupVersionKind: networkingv1beta1.SchemeGroupVersion.WithKind("Ingress"),
+	}
+	assert.Equal(t, "networking.k8s.io/v1beta1, Kind=Ingress/namespace/name", ingress.Key())
+
+	service := ingress
+	service.GroupVersionKind = schema.GroupVersionKind{Version: "v1", Kind: "Service"}
+	assert.NotEqual(t, ingress.Key(), service.Key(), "objects of different kinds have different keys")
+}
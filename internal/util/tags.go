@@ -0,0 +1,44 @@
+package util
+
+import (
+	"github.com/kong/go-kong/kong"
+)
+
+// Prefixes of the tags identifying the Kubernetes object a Kong entity was
+// translated from, e.g. "k8s-name:echo".
+const (
+	K8sNameTagPrefix      = "k8s-name:"
+	K8sNamespaceTagPrefix = "k8s-namespace:"
+	K8sKindTagPrefix      = "k8s-kind:"
+	K8sGroupTagPrefix     = "k8s-group:"
+	K8sVersionTagPrefix   = "k8s-version:"
+	K8sUIDTagPrefix       = "k8s-uid:"
+)
+
+// GenerateTagsForObject provides the tags identifying the described object,
+// so that the Kong entities translated from it can be traced back to it (e.g.
+// when filtering them in Kong Manager). The tags of the kind, group and version
+// are only provided if the kind is known, and those of the namespace and UID
+// if they're set.
+func GenerateTagsForObject(info K8sObjectInfo) []*string {
+	if info.Name == "" {
+		return nil
+	}
+	tags := []*string{kong.String(K8sNameTagPrefix + info.Name)}
+	if info.Namespace != "" {
+		tags = append(tags, kong.String(K8sNamespaceTagPrefix+info.Namespace))
+	}
+	if gvk := info.GroupVersionKind; gvk.Kind != "" {
+		tags = append(tags, kong.String(K8sKindTagPrefix+gvk.Kind))
+		if gvk.Group != "" {
+			tags = append(tags, kong.String(K8sGroupTagPrefix+gvk.Group))
+		}
+		if gvk.Version != "" {
+			tags = append(tags, kong.String(K8sVersionTagPrefix+gvk.Version))
+		}
+	}
+	if info.UID != "" {
+		tags = append(tags, kong.String(K8sUIDTagPrefix+string(info.UID)))
+	}
+	return tags
+}
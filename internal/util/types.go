@@ -54,3 +54,15 @@ func ParseConfigDumpMode(in string) (ConfigDumpMode, error) {
 		return ConfigDumpModeOff, fmt.Errorf("unrecognized config dump mode: %s", in)
 	}
 }
+
+// NamespaceQuotas limits the amount of Kong configuration which the Kubernetes
+// objects of any single namespace may produce, so that one tenant can't bloat
+// the configuration shared by all of them. Zero values mean no limit.
+type NamespaceQuotas struct {
+	// MaxRoutes is the maximum number of Kong routes per namespace.
+	MaxRoutes int
+	// MaxPlugins is the maximum number of Kong plugins per namespace.
+	MaxPlugins int
+	// MaxConsumers is the maximum number of Kong consumers per namespace.
+	MaxConsumers int
+}
@@ -59,6 +59,13 @@ func ValidateCredentials(secret *corev1.Secret) error {
 		return fmt.Errorf("some fields were invalid due to missing data: %s", strings.Join(missingDataFields, ", "))
 	}
 
+	// verify the values of the fields which Kong constrains further
+	if validateValues, ok := credTypeValueValidators[credentialType]; ok {
+		if err := validateValues(secret.Data); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -1,10 +1,16 @@
 package credentials
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 )
 
 func TestUniqueConstraintsValidation(t *testing.T) {
@@ -59,3 +65,63 @@ func TestUniqueConstraintsValidation(t *testing.T) {
 	t.Log("verifying that unconstrained keys for types with constraints don't flag as violated")
 	assert.False(t, IsKeyUniqueConstrained("basic-auth", "unconstrained-key"))
 }
+
+func TestValidateCredentialsValues(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	publicKeyDER, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+	publicKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyDER}))
+
+	jwt := func(algorithm, rsaPublicKey string) map[string]string {
+		return map[string]string{
+			"kongCredType":   "jwt",
+			"algorithm":      algorithm,
+			"key":            "key",
+			"rsa_public_key": rsaPublicKey,
+			"secret":         "secret",
+		}
+	}
+	oauth2 := func(redirectURIs, hashSecret string) map[string]string {
+		return map[string]string{
+			"kongCredType":  "oauth2",
+			"name":          "name",
+			"client_id":     "client-id",
+			"client_secret": "client-secret",
+			"redirect_uris": redirectURIs,
+			"hash_secret":   hashSecret,
+		}
+	}
+
+	for _, tt := range []struct {
+		name    string
+		data    map[string]string
+		wantErr string
+	}{
+		{name: "jwt with a symmetric algorithm", data: jwt("HS256", "unused")},
+		{name: "jwt with an asymmetric algorithm", data: jwt("ES256", publicKey)},
+		{name: "jwt with a public key stored in a vault", data: jwt("RS256", "{vault://env/jwt-public-key}")},
+		{name: "jwt with an unsupported algorithm", data: jwt("none", publicKey), wantErr: "invalid jwt algorithm none"},
+		{name: "jwt with an invalid public key", data: jwt("RS256", "unused"), wantErr: "invalid rsa_public_key: not PEM-encoded"},
+		{name: "oauth2", data: oauth2("https://example.com/callback,https://example.net/callback", "true")},
+		{
+			name:    "oauth2 with a relative redirect URI",
+			data:    oauth2("https://example.com/callback,/callback", "true"),
+			wantErr: "invalid redirect_uris: /callback is not an absolute URL",
+		},
+		{name: "oauth2 with an invalid hash_secret", data: oauth2("https://example.com/callback", "yes"), wantErr: "invalid hash_secret"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := &corev1.Secret{Data: make(map[string][]byte, len(tt.data))}
+			for k, v := range tt.data {
+				secret.Data[k] = []byte(v)
+			}
+			err := ValidateCredentials(secret)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
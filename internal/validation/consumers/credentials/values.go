@@ -0,0 +1,73 @@
+package credentials
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// -----------------------------------------------------------------------------
+// Validation - Values
+// -----------------------------------------------------------------------------
+
+// credTypeValueValidators validates the values of the fields of the credentials
+// of a given type, beyond their presence. Like the unique key constraints, the
+// rules are derived from the schemas of the types in the backend Kong Admin API.
+var credTypeValueValidators = map[string]func(data map[string][]byte) error{
+	"jwt":    validateJWTValues,
+	"oauth2": validateOAuth2Values,
+}
+
+// JWTAlgorithms are the algorithms Kong supports for JWT credentials.
+var JWTAlgorithms = sets.NewString(
+	"HS256", "HS384", "HS512",
+	"RS256", "RS384", "RS512",
+	"ES256", "ES384",
+	"PS256", "PS384", "PS512",
+)
+
+func validateJWTValues(data map[string][]byte) error {
+	algorithm := string(data["algorithm"])
+	if !JWTAlgorithms.Has(algorithm) {
+		return fmt.Errorf("invalid jwt algorithm %s", algorithm)
+	}
+
+	// asymmetric algorithms verify tokens with the public key of the credential
+	if strings.HasPrefix(algorithm, "HS") || isVaultReference(data["rsa_public_key"]) {
+		return nil
+	}
+	block, _ := pem.Decode(data["rsa_public_key"])
+	if block == nil {
+		return fmt.Errorf("invalid rsa_public_key: not PEM-encoded")
+	}
+	if _, err := x509.ParsePKIXPublicKey(block.Bytes); err != nil {
+		return fmt.Errorf("invalid rsa_public_key: %w", err)
+	}
+	return nil
+}
+
+func validateOAuth2Values(data map[string][]byte) error {
+	for _, redirectURI := range strings.Split(string(data["redirect_uris"]), ",") {
+		u, err := url.Parse(redirectURI)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid redirect_uris: %s is not an absolute URL", redirectURI)
+		}
+	}
+	if hashSecret, ok := data["hash_secret"]; ok {
+		if _, err := strconv.ParseBool(string(hashSecret)); err != nil {
+			return fmt.Errorf("invalid hash_secret: %w", err)
+		}
+	}
+	return nil
+}
+
+// isVaultReference indicates whether a value references a secret stored in a
+// vault, which is only resolved by Kong.
+func isVaultReference(value []byte) bool {
+	return strings.HasPrefix(string(value), "{vault://")
+}
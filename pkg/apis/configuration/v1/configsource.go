@@ -1,5 +1,9 @@
 package v1
 
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
 // ConfigSource is a wrapper around SecretValueFromSource
 //+kubebuilder:object:generate=true
 type ConfigSource struct {
@@ -36,3 +40,32 @@ type NamespacedSecretValueFromSource struct {
 	//+kubebuilder:validation:Required
 	Key string `json:"key,omitempty"`
 }
+
+// ConfigPatch sets a field of the configuration of a plugin, e.g. to inject a
+// value from a Secret into a nested field without moving the whole
+// configuration to the Secret.
+//+kubebuilder:object:generate=true
+type ConfigPatch struct {
+	// Path is the JSON pointer (RFC 6901) to the field, e.g. /auth/token.
+	// Missing parent objects are created.
+	//+kubebuilder:validation:Required
+	Path string `json:"path"`
+	// Value is the JSON value of the field.
+	Value *apiextensionsv1.JSON `json:"value,omitempty"`
+	// ValueFrom references a Secret key holding the JSON value of the field.
+	ValueFrom *ConfigSource `json:"valueFrom,omitempty"`
+}
+
+// NamespacedConfigPatch is a ConfigPatch whose value may come from a Secret
+// in any namespace.
+//+kubebuilder:object:generate=true
+type NamespacedConfigPatch struct {
+	// Path is the JSON pointer (RFC 6901) to the field, e.g. /auth/token.
+	// Missing parent objects are created.
+	//+kubebuilder:validation:Required
+	Path string `json:"path"`
+	// Value is the JSON value of the field.
+	Value *apiextensionsv1.JSON `json:"value,omitempty"`
+	// ValueFrom references a Secret key holding the JSON value of the field.
+	ValueFrom *NamespacedConfigSource `json:"valueFrom,omitempty"`
+}
@@ -33,6 +33,7 @@ import (
 //+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,description="Age"
 //+kubebuilder:printcolumn:name="Disabled",type=boolean,JSONPath=`.disabled`,description="Indicates if the plugin is disabled",priority=1
 //+kubebuilder:printcolumn:name="Config",type=string,JSONPath=`.config`,description="Configuration of the plugin",priority=1
+//+kubebuilder:printcolumn:name="Applied",type=string,JSONPath=`.status.conditions[?(@.type=="Applied")].status`,description="Indicates if the plugin is applied",priority=1
 
 // KongClusterPlugin is the Schema for the kongclusterplugins API
 type KongClusterPlugin struct {
@@ -52,6 +53,10 @@ type KongClusterPlugin struct {
 	// ConfigFrom references a secret containing the plugin configuration.
 	ConfigFrom *NamespacedConfigSource `json:"configFrom,omitempty"`
 
+	// ConfigPatches set fields of the configuration given by Config, in
+	// order, e.g. to inject values from Secrets into nested fields.
+	ConfigPatches []NamespacedConfigPatch `json:"configPatches,omitempty"`
+
 	// PluginName is the name of the plugin to which to apply the config
 	//+kubebuilder:validation:Required
 	PluginName string `json:"plugin,omitempty"`
@@ -64,8 +69,66 @@ type KongClusterPlugin struct {
 	// Protocols configures plugin to run on requests received on specific
 	// protocols.
 	Protocols []KongProtocol `json:"protocols,omitempty"`
+
+	// Ordering overrides the order in which plugins run, which is otherwise
+	// given by their static priorities. It is only supported by Kong
+	// Enterprise 3.0 and above, in DB-less mode.
+	Ordering *PluginOrdering `json:"ordering,omitempty"`
+
+	// RouteSelector attaches the plugin to the routes translated from all
+	// objects (e.g. Ingresses or HTTPRoutes) whose labels match it, in
+	// addition to the objects listing the plugin in their plugins annotation.
+	RouteSelector *metav1.LabelSelector `json:"routeSelector,omitempty"`
+
+	// ServiceSelector attaches the plugin to the services translated from all
+	// Kubernetes Services whose labels match it, in addition to the Services
+	// listing the plugin in their plugins annotation.
+	ServiceSelector *metav1.LabelSelector `json:"serviceSelector,omitempty"`
+
+	// Status reports how the plugin is applied to the data-plane.
+	Status KongClusterPluginStatus `json:"status,omitempty"`
 }
 
+// KongClusterPluginStatus reports how a KongClusterPlugin is applied to the
+// data-plane.
+type KongClusterPluginStatus struct {
+	// Conditions describe the current state of the KongClusterPlugin.
+	//+listType=map
+	//+listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Global indicates whether the plugin is applied globally.
+	Global bool `json:"global,omitempty"`
+
+	// Services is the number of services the plugin is attached to.
+	Services int32 `json:"services,omitempty"`
+
+	// Routes is the number of routes the plugin is attached to.
+	Routes int32 `json:"routes,omitempty"`
+
+	// Consumers is the number of consumers the plugin is attached to.
+	Consumers int32 `json:"consumers,omitempty"`
+}
+
+const (
+	// KongClusterPluginConditionApplied indicates whether the plugin is
+	// applied to the data-plane.
+	KongClusterPluginConditionApplied = "Applied"
+
+	// KongClusterPluginReasonApplied is used when the plugin is applied
+	// globally or attached to services, routes or consumers.
+	KongClusterPluginReasonApplied = "Applied"
+	// KongClusterPluginReasonNotAttached is used when the plugin is neither
+	// global nor attached to any service, route or consumer.
+	KongClusterPluginReasonNotAttached = "NotAttached"
+	// KongClusterPluginReasonInvalid is used when the plugin can't be
+	// translated or its configuration violates the schema of the plugin.
+	KongClusterPluginReasonInvalid = "Invalid"
+	// KongClusterPluginReasonConfigurationRejected is used when the data-plane
+	// rejected the configuration the plugin is part of.
+	KongClusterPluginReasonConfigurationRejected = "ConfigurationRejected"
+)
+
 //+kubebuilder:object:root=true
 
 // KongClusterPluginList contains a list of KongClusterPlugin
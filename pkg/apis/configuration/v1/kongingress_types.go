@@ -86,6 +86,18 @@ type KongIngressService struct {
 	// for transmitting a request to the upstream server.
 	//+kubebuilder:validation:Minimum=0
 	WriteTimeout *int `json:"write_timeout,omitempty" yaml:"write_timeout,omitempty"`
+
+	// Whether to verify the TLS certificate of the upstream server.
+	TLSVerify *bool `json:"tls_verify,omitempty" yaml:"tls_verify,omitempty"`
+
+	// The maximum depth of the certificate chain allowed when verifying
+	// the TLS certificate of the upstream server.
+	//+kubebuilder:validation:Minimum=0
+	TLSVerifyDepth *int `json:"tls_verify_depth,omitempty" yaml:"tls_verify_depth,omitempty"`
+
+	// The IDs of the CA certificates used to verify the TLS certificate
+	// of the upstream server.
+	CACertificates []string `json:"ca_certificates,omitempty" yaml:"ca_certificates,omitempty"`
 }
 
 // KongIngressRoute contains KongIngress route configuration
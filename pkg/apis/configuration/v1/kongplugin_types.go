@@ -51,6 +51,10 @@ type KongPlugin struct {
 	// ConfigFrom references a secret containing the plugin configuration.
 	ConfigFrom *ConfigSource `json:"configFrom,omitempty"`
 
+	// ConfigPatches set fields of the configuration given by Config, in
+	// order, e.g. to inject values from Secrets into nested fields.
+	ConfigPatches []ConfigPatch `json:"configPatches,omitempty"`
+
 	// PluginName is the name of the plugin to which to apply the config
 	//+kubebuilder:validation:Required
 	PluginName string `json:"plugin,omitempty"`
@@ -63,8 +67,28 @@ type KongPlugin struct {
 	// Protocols configures plugin to run on requests received on specific
 	// protocols.
 	Protocols []KongProtocol `json:"protocols,omitempty"`
+
+	// Ordering overrides the order in which plugins run, which is otherwise
+	// given by their static priorities. It is only supported by Kong
+	// Enterprise 3.0 and above, in DB-less mode.
+	Ordering *PluginOrdering `json:"ordering,omitempty"`
+}
+
+// PluginOrdering configures the plugins a plugin runs before or after.
+type PluginOrdering struct {
+	// Before lists the plugins the plugin runs before, by phase of the
+	// request processing (e.g. access).
+	Before PluginOrderingPhase `json:"before,omitempty"`
+
+	// After lists the plugins the plugin runs after, by phase of the request
+	// processing (e.g. access).
+	After PluginOrderingPhase `json:"after,omitempty"`
 }
 
+// PluginOrderingPhase lists the names of plugins by phase of the request
+// processing.
+type PluginOrderingPhase map[string][]string
+
 //+kubebuilder:object:root=true
 
 // KongPluginList contains a list of KongPlugin
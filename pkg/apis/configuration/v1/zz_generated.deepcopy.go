@@ -23,9 +23,36 @@ package v1
 
 import (
 	"github.com/kong/go-kong/kong"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigPatch) DeepCopyInto(out *ConfigPatch) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(ConfigSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigPatch.
+func (in *ConfigPatch) DeepCopy() *ConfigPatch {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigPatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigSource) DeepCopyInto(out *ConfigSource) {
 	*out = *in
@@ -53,11 +80,34 @@ func (in *KongClusterPlugin) DeepCopyInto(out *KongClusterPlugin) {
 		*out = new(NamespacedConfigSource)
 		**out = **in
 	}
+	if in.ConfigPatches != nil {
+		in, out := &in.ConfigPatches, &out.ConfigPatches
+		*out = make([]NamespacedConfigPatch, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Protocols != nil {
 		in, out := &in.Protocols, &out.Protocols
 		*out = make([]KongProtocol, len(*in))
 		copy(*out, *in)
 	}
+	if in.Ordering != nil {
+		in, out := &in.Ordering, &out.Ordering
+		*out = new(PluginOrdering)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RouteSelector != nil {
+		in, out := &in.RouteSelector, &out.RouteSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceSelector != nil {
+		in, out := &in.ServiceSelector, &out.ServiceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KongClusterPlugin.
@@ -110,6 +160,28 @@ func (in *KongClusterPluginList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KongClusterPluginStatus) DeepCopyInto(out *KongClusterPluginStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KongClusterPluginStatus.
+func (in *KongClusterPluginStatus) DeepCopy() *KongClusterPluginStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KongClusterPluginStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KongConsumer) DeepCopyInto(out *KongConsumer) {
 	*out = *in
@@ -375,6 +447,21 @@ func (in *KongIngressService) DeepCopyInto(out *KongIngressService) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.TLSVerify != nil {
+		in, out := &in.TLSVerify, &out.TLSVerify
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TLSVerifyDepth != nil {
+		in, out := &in.TLSVerifyDepth, &out.TLSVerifyDepth
+		*out = new(int)
+		**out = **in
+	}
+	if in.CACertificates != nil {
+		in, out := &in.CACertificates, &out.CACertificates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KongIngressService.
@@ -463,11 +550,23 @@ func (in *KongPlugin) DeepCopyInto(out *KongPlugin) {
 		*out = new(ConfigSource)
 		**out = **in
 	}
+	if in.ConfigPatches != nil {
+		in, out := &in.ConfigPatches, &out.ConfigPatches
+		*out = make([]ConfigPatch, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Protocols != nil {
 		in, out := &in.Protocols, &out.Protocols
 		*out = make([]KongProtocol, len(*in))
 		copy(*out, *in)
 	}
+	if in.Ordering != nil {
+		in, out := &in.Ordering, &out.Ordering
+		*out = new(PluginOrdering)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KongPlugin.
@@ -520,6 +619,31 @@ func (in *KongPluginList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacedConfigPatch) DeepCopyInto(out *NamespacedConfigPatch) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(NamespacedConfigSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedConfigPatch.
+func (in *NamespacedConfigPatch) DeepCopy() *NamespacedConfigPatch {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacedConfigPatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NamespacedConfigSource) DeepCopyInto(out *NamespacedConfigSource) {
 	*out = *in
@@ -551,6 +675,80 @@ func (in *NamespacedSecretValueFromSource) DeepCopy() *NamespacedSecretValueFrom
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PluginOrdering) DeepCopyInto(out *PluginOrdering) {
+	*out = *in
+	if in.Before != nil {
+		in, out := &in.Before, &out.Before
+		*out = make(PluginOrderingPhase, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.After != nil {
+		in, out := &in.After, &out.After
+		*out = make(PluginOrderingPhase, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginOrdering.
+func (in *PluginOrdering) DeepCopy() *PluginOrdering {
+	if in == nil {
+		return nil
+	}
+	out := new(PluginOrdering)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in PluginOrderingPhase) DeepCopyInto(out *PluginOrderingPhase) {
+	{
+		in := &in
+		*out = make(PluginOrderingPhase, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginOrderingPhase.
+func (in PluginOrderingPhase) DeepCopy() PluginOrderingPhase {
+	if in == nil {
+		return nil
+	}
+	out := new(PluginOrderingPhase)
+	in.DeepCopyInto(out)
+	return *out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretValueFromSource) DeepCopyInto(out *SecretValueFromSource) {
 	*out = *in
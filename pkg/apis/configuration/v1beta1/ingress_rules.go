@@ -11,6 +11,17 @@ type UDPIngressRule struct {
 	// +kubebuilder:validation:Required
 	Port int `json:"port"`
 
+	// EndPort makes the rule accept incoming traffic on the range of ports
+	// from Port to EndPort, inclusive, each of which is routed to the port of
+	// the service Backend at the same offset from its ServicePort, e.g. for
+	// game servers and RTP workloads listening on ranges of ports. The proxy
+	// must listen on all the ports of the range, which may include 1000 ports
+	// at most.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	EndPort int `json:"endPort,omitempty"`
+
 	// Backend defines the Kubernetes service which accepts traffic from the
 	// listening Port defined above.
 	// +kubebuilder:validation:Required
@@ -42,6 +53,12 @@ type IngressRule struct {
 	// will be forwarded to.
 	// +kubebuilder:validation:Required
 	Backend IngressBackend `json:"backend"`
+
+	// TLSSecretName is the name of the secret holding the certificate used to
+	// terminate TLS sessions for the Host of this rule. It takes precedence
+	// over the `tls` section of the TCPIngress, and requires a Host.
+	// +optional
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
 }
 
 //+kubebuilder:validation:Optional
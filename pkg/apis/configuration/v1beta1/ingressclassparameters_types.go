@@ -0,0 +1,77 @@
+/*
+Copyright 2022 Kong, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IngressClassParametersKind is the kind IngressClasses reference in their
+// parameters to be configured by an IngressClassParameters.
+const IngressClassParametersKind = "IngressClassParameters"
+
+func init() {
+	SchemeBuilder.Register(&IngressClassParameters{}, &IngressClassParametersList{})
+}
+
+//+kubebuilder:object:root=true
+
+// IngressClassParametersList contains a list of IngressClassParameters
+type IngressClassParametersList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IngressClassParameters `json:"items"`
+}
+
+//+genclient
+//+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=icp,categories=kong-ingress-controller
+//+kubebuilder:storageversion
+//+kubebuilder:validation:Optional
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,description="Age"
+
+// IngressClassParameters is the Schema for the ingressclassparameterses API.
+// It configures how the controller translates the Ingresses of the
+// IngressClass whose parameters reference it.
+type IngressClassParameters struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec IngressClassParametersSpec `json:"spec,omitempty"`
+}
+
+// IngressClassParametersSpec defines the desired state of IngressClassParameters
+type IngressClassParametersSpec struct {
+	// EnableLegacyRegexDetection makes Kong 3.0+ consider the paths of the
+	// Ingresses of the class regexes as Kong versions prior to 3.0 did, as if
+	// they were annotated with konghq.com/legacy-regex-path. The annotation
+	// of an Ingress takes precedence.
+	EnableLegacyRegexDetection bool `json:"enableLegacyRegexDetection,omitempty"`
+
+	// Plugins are the names of the KongPlugins (in the namespace of each
+	// Ingress) or KongClusterPlugins attached to the routes of all the
+	// Ingresses of the class, in addition to the ones they're annotated with.
+	Plugins []string `json:"plugins,omitempty"`
+
+	// DefaultBackend is the Service, in the namespace of the
+	// IngressClassParameters, which receives the requests no route of the
+	// Ingresses of the class matches. It takes precedence over the default
+	// backends of the Ingresses of the class, which are then ignored.
+	DefaultBackend *networkingv1.IngressServiceBackend `json:"defaultBackend,omitempty"`
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2022 Kong, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func init() {
+	SchemeBuilder.Register(&KongCACertificate{}, &KongCACertificateList{})
+}
+
+//+kubebuilder:object:root=true
+
+// KongCACertificateList contains a list of KongCACertificate
+type KongCACertificateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KongCACertificate `json:"items"`
+}
+
+//+genclient
+//+genclient:nonNamespaced
+//+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster,shortName=kcac,categories=kong-ingress-controller
+//+kubebuilder:subresource:status
+//+kubebuilder:storageversion
+//+kubebuilder:validation:Optional
+//+kubebuilder:printcolumn:name="Secret-Namespace",type=string,JSONPath=`.spec.secretRef.namespace`,description="Namespace of the Secret containing the CA bundle"
+//+kubebuilder:printcolumn:name="Secret-Name",type=string,JSONPath=`.spec.secretRef.name`,description="Name of the Secret containing the CA bundle"
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,description="Age"
+
+// KongCACertificate is the Schema for the kongcacertificates API
+type KongCACertificate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec KongCACertificateSpec `json:"spec,omitempty"`
+}
+
+// KongCACertificateSpec defines the desired state of KongCACertificate
+type KongCACertificateSpec struct {
+	// SecretRef references the Secret containing the PEM encoded CA bundle.
+	//+kubebuilder:validation:Required
+	SecretRef KongCACertificateSecretRef `json:"secretRef"`
+}
+
+// KongCACertificateSecretRef references a key of a Secret.
+type KongCACertificateSecretRef struct {
+	// Namespace of the Secret.
+	//+kubebuilder:validation:Required
+	Namespace string `json:"namespace"`
+
+	// Name of the Secret.
+	//+kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Key of the Secret data containing the CA bundle. Defaults to "ca.crt".
+	Key string `json:"key,omitempty"`
+}
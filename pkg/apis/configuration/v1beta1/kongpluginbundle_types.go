@@ -0,0 +1,77 @@
+/*
+Copyright 2022 Kong, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func init() {
+	SchemeBuilder.Register(&KongPluginBundle{}, &KongPluginBundleList{})
+}
+
+//+kubebuilder:object:root=true
+
+// KongPluginBundleList contains a list of KongPluginBundle
+type KongPluginBundleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KongPluginBundle `json:"items"`
+}
+
+//+genclient
+//+genclient:nonNamespaced
+//+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster,shortName=kpb,categories=kong-ingress-controller
+//+kubebuilder:storageversion
+//+kubebuilder:validation:Optional
+//+kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.spec.version`,description="Version of the bundle"
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,description="Age"
+//+kubebuilder:printcolumn:name="Plugins",type=string,JSONPath=`.spec.plugins`,description="KongClusterPlugins in the bundle",priority=1
+
+// KongPluginBundle is the Schema for the kongpluginbundles API. It groups
+// KongClusterPlugins which are applied together to all the routes of the
+// namespaces and ingress classes it targets, so that they can be shipped and
+// rolled back as a single versioned object.
+type KongPluginBundle struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec KongPluginBundleSpec `json:"spec,omitempty"`
+}
+
+// KongPluginBundleSpec defines the desired state of KongPluginBundle
+type KongPluginBundleSpec struct {
+	// Version identifies the revision of the bundle.
+	//+kubebuilder:validation:Required
+	//+kubebuilder:validation:MinLength=1
+	Version string `json:"version"`
+
+	// Plugins are the names of the KongClusterPlugins in the bundle.
+	//+kubebuilder:validation:Required
+	//+kubebuilder:validation:MinItems=1
+	Plugins []string `json:"plugins"`
+
+	// Namespaces are the namespaces whose routes the bundle applies to.
+	// The bundle applies to the routes of all namespaces if empty.
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// IngressClasses are the ingress classes of the controllers which apply
+	// the bundle. The bundle is applied by all controllers if empty.
+	IngressClasses []string `json:"ingressClasses,omitempty"`
+}
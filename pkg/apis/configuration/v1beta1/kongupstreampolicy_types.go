@@ -0,0 +1,148 @@
+/*
+Copyright 2022 Kong, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"github.com/kong/go-kong/kong"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func init() {
+	SchemeBuilder.Register(&KongUpstreamPolicy{}, &KongUpstreamPolicyList{})
+}
+
+//+kubebuilder:object:root=true
+
+// KongUpstreamPolicyList contains a list of KongUpstreamPolicy
+type KongUpstreamPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KongUpstreamPolicy `json:"items"`
+}
+
+//+genclient
+//+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=kup,categories=kong-ingress-controller
+//+kubebuilder:storageversion
+//+kubebuilder:validation:Optional
+//+kubebuilder:printcolumn:name="Algorithm",type=string,JSONPath=`.spec.algorithm`,description="Load balancing algorithm"
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,description="Age"
+
+// KongUpstreamPolicy is the Schema for the kongupstreampolicies API. It
+// configures the load balancing of the Kong Upstreams generated for the
+// Services annotated with konghq.com/upstream-policy.
+type KongUpstreamPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec KongUpstreamPolicySpec `json:"spec,omitempty"`
+}
+
+// KongUpstreamPolicySpec defines the desired state of KongUpstreamPolicy
+type KongUpstreamPolicySpec struct {
+	// Algorithm is the load balancing algorithm to use.
+	//+kubebuilder:validation:Enum=round-robin;consistent-hashing;least-connections
+	Algorithm *string `json:"algorithm,omitempty"`
+
+	// Slots is the number of slots in the load balancer algorithm.
+	//+kubebuilder:validation:Minimum=10
+	//+kubebuilder:validation:Maximum=65536
+	Slots *int `json:"slots,omitempty"`
+
+	// HashOn defines what to use as hashing input when the algorithm is
+	// "consistent-hashing".
+	HashOn *KongUpstreamHash `json:"hashOn,omitempty"`
+
+	// HashOnFallback defines what to use as hashing input if the primary
+	// HashOn does not return a hash.
+	HashOnFallback *KongUpstreamHash `json:"hashOnFallback,omitempty"`
+
+	// Healthchecks defines the health check configurations in Kong.
+	Healthchecks *kong.Healthcheck `json:"healthchecks,omitempty"`
+
+	// Connections defines the settings of the connections to the upstream,
+	// applied to the Kong Services of the Services using the policy.
+	Connections *KongUpstreamConnections `json:"connections,omitempty"`
+
+	// Failover sends most of the traffic to the endpoints of a primary zone,
+	// failing over to the endpoints of the other zones when it has no ready or
+	// healthy ones.
+	Failover *KongUpstreamFailover `json:"failover,omitempty"`
+}
+
+// KongUpstreamFailover defines active/passive load balancing across the
+// zones of the endpoints of a Service, based on the zones EndpointSlices
+// report. The targets of the other zones keep a weight of 1, so that traffic
+// fails over to them when healthchecks mark the targets of the primary zone
+// unhealthy. They receive a small share of the traffic otherwise.
+type KongUpstreamFailover struct {
+	// PrimaryZone is the zone whose endpoints receive most of the traffic as
+	// long as any of them is ready. The endpoints of the other zones get a
+	// weight of 1, and all the traffic when the primary zone has no ready or
+	// healthy endpoints.
+	//+kubebuilder:validation:Required
+	//+kubebuilder:validation:MinLength=1
+	PrimaryZone string `json:"primaryZone"`
+}
+
+// KongUpstreamConnections defines the settings of the connections Kong opens
+// to an upstream. Kong only supports them per service: the size of the
+// keepalive pools and the lifetime of their connections are global Kong
+// settings (upstream_keepalive_pool_size, upstream_keepalive_max_requests and
+// upstream_keepalive_idle_timeout).
+type KongUpstreamConnections struct {
+	// ConnectTimeout is the timeout in milliseconds for establishing a
+	// connection to the upstream.
+	//+kubebuilder:validation:Minimum=1
+	ConnectTimeout *int `json:"connectTimeout,omitempty"`
+
+	// ReadTimeout is the timeout in milliseconds between two successive read
+	// operations on a connection to the upstream.
+	//+kubebuilder:validation:Minimum=1
+	ReadTimeout *int `json:"readTimeout,omitempty"`
+
+	// WriteTimeout is the timeout in milliseconds between two successive write
+	// operations on a connection to the upstream.
+	//+kubebuilder:validation:Minimum=1
+	WriteTimeout *int `json:"writeTimeout,omitempty"`
+
+	// Retries is the number of retries to perform when proxying to the
+	// upstream fails.
+	//+kubebuilder:validation:Minimum=0
+	//+kubebuilder:validation:Maximum=32767
+	Retries *int `json:"retries,omitempty"`
+}
+
+// KongUpstreamHash defines the input of the hash of requests.
+type KongUpstreamHash struct {
+	// Input is the kind of hashing input.
+	//+kubebuilder:validation:Enum=none;consumer;ip;header;cookie
+	Input *string `json:"input,omitempty"`
+
+	// Header is the header name to take the value from as hash input.
+	// Only required when Input is "header".
+	Header *string `json:"header,omitempty"`
+
+	// Cookie is the cookie name to take the value from as hash input.
+	// Only required when Input is "cookie".
+	Cookie *string `json:"cookie,omitempty"`
+
+	// CookiePath is the cookie path to set in the response headers.
+	// Only used when Input is "cookie".
+	CookiePath *string `json:"cookiePath,omitempty"`
+}
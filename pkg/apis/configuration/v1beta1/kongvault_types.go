@@ -0,0 +1,77 @@
+/*
+Copyright 2022 Kong, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func init() {
+	SchemeBuilder.Register(&KongVault{}, &KongVaultList{})
+}
+
+//+kubebuilder:object:root=true
+
+// KongVaultList contains a list of KongVault
+type KongVaultList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KongVault `json:"items"`
+}
+
+//+genclient
+//+genclient:nonNamespaced
+//+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster,shortName=kv,categories=kong-ingress-controller
+//+kubebuilder:storageversion
+//+kubebuilder:validation:Optional
+//+kubebuilder:printcolumn:name="Backend",type=string,JSONPath=`.spec.backend`,description="Name of the vault backend"
+//+kubebuilder:printcolumn:name="Prefix",type=string,JSONPath=`.spec.prefix`,description="Prefix of the vault references"
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,description="Age"
+
+// KongVault is the Schema for the kongvaults API. It configures a Kong vault,
+// which resolves the references to secrets ({vault://<prefix>/<secret>}) used
+// in place of sensitive values, such as the keys of consumer credentials, so
+// that those values don't need to be stored in Kubernetes.
+type KongVault struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec KongVaultSpec `json:"spec,omitempty"`
+}
+
+// KongVaultSpec defines the desired state of KongVault
+type KongVaultSpec struct {
+	// Backend is the name of the Kong vault backend (e.g. env, aws, gcp or hcv).
+	//+kubebuilder:validation:Required
+	//+kubebuilder:validation:MinLength=1
+	Backend string `json:"backend"`
+
+	// Prefix is the prefix of the references resolved through the vault.
+	//+kubebuilder:validation:Required
+	//+kubebuilder:validation:Pattern=`^[a-z][a-z0-9-]*$`
+	Prefix string `json:"prefix"`
+
+	// Description is the description of the vault.
+	Description string `json:"description,omitempty"`
+
+	// Config is the configuration of the vault backend.
+	//+kubebuilder:validation:Type=object
+	Config apiextensionsv1.JSON `json:"config,omitempty"`
+}
@@ -81,8 +81,40 @@ type TCPIngressStatus struct {
 	// LoadBalancer contains the current status of the load-balancer.
 	// +optional
 	LoadBalancer corev1.LoadBalancerStatus `json:"loadBalancer,omitempty"`
+
+	// Conditions describe the current state of the TCPIngress.
+	//+listType=map
+	//+listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+const (
+	// IngressConditionPortConflict indicates whether rules of a TCPIngress or
+	// UDPIngress are ignored because an object taking precedence claims the
+	// same ports.
+	IngressConditionPortConflict = "PortConflict"
+
+	// IngressReasonPortsClaimed is used when another object taking precedence
+	// claims ports of the rules.
+	IngressReasonPortsClaimed = "PortsClaimed"
+	// IngressReasonNoConflict is used when no object taking precedence claims
+	// ports of the rules.
+	IngressReasonNoConflict = "NoConflict"
+
+	// IngressConditionStreamListenersReady indicates whether the Kong proxy
+	// listens on all the ports the rules of a TCPIngress or UDPIngress claim.
+	IngressConditionStreamListenersReady = "StreamListenersReady"
+
+	// IngressReasonListening is used when the Kong proxy listens on all the
+	// ports of the rules.
+	IngressReasonListening = "Listening"
+	// IngressReasonListenersMissing is used when the Kong proxy doesn't listen
+	// on ports of the rules, which its configuration and Service must be
+	// updated to expose.
+	IngressReasonListenersMissing = "ListenersMissing"
+)
+
 func init() {
 	SchemeBuilder.Register(&TCPIngress{}, &TCPIngressList{})
 }
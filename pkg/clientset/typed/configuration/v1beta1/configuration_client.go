@@ -26,6 +26,11 @@ import (
 
 type ConfigurationV1beta1Interface interface {
 	RESTClient() rest.Interface
+	IngressClassParametersesGetter
+	KongCACertificatesGetter
+	KongPluginBundlesGetter
+	KongUpstreamPoliciesGetter
+	KongVaultsGetter
 	TCPIngressesGetter
 	UDPIngressesGetter
 }
@@ -35,6 +40,26 @@ type ConfigurationV1beta1Client struct {
 	restClient rest.Interface
 }
 
+func (c *ConfigurationV1beta1Client) IngressClassParameterses(namespace string) IngressClassParametersInterface {
+	return newIngressClassParameterses(c, namespace)
+}
+
+func (c *ConfigurationV1beta1Client) KongCACertificates() KongCACertificateInterface {
+	return newKongCACertificates(c)
+}
+
+func (c *ConfigurationV1beta1Client) KongPluginBundles() KongPluginBundleInterface {
+	return newKongPluginBundles(c)
+}
+
+func (c *ConfigurationV1beta1Client) KongUpstreamPolicies(namespace string) KongUpstreamPolicyInterface {
+	return newKongUpstreamPolicies(c, namespace)
+}
+
+func (c *ConfigurationV1beta1Client) KongVaults() KongVaultInterface {
+	return newKongVaults(c)
+}
+
 func (c *ConfigurationV1beta1Client) TCPIngresses(namespace string) TCPIngressInterface {
 	return newTCPIngresses(c, namespace)
 }
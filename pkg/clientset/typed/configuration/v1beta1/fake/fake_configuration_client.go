@@ -28,6 +28,26 @@ type FakeConfigurationV1beta1 struct {
 	*testing.Fake
 }
 
+func (c *FakeConfigurationV1beta1) IngressClassParameterses(namespace string) v1beta1.IngressClassParametersInterface {
+	return &FakeIngressClassParameterses{c, namespace}
+}
+
+func (c *FakeConfigurationV1beta1) KongCACertificates() v1beta1.KongCACertificateInterface {
+	return &FakeKongCACertificates{c}
+}
+
+func (c *FakeConfigurationV1beta1) KongPluginBundles() v1beta1.KongPluginBundleInterface {
+	return &FakeKongPluginBundles{c}
+}
+
+func (c *FakeConfigurationV1beta1) KongUpstreamPolicies(namespace string) v1beta1.KongUpstreamPolicyInterface {
+	return &FakeKongUpstreamPolicies{c, namespace}
+}
+
+func (c *FakeConfigurationV1beta1) KongVaults() v1beta1.KongVaultInterface {
+	return &FakeKongVaults{c}
+}
+
 func (c *FakeConfigurationV1beta1) TCPIngresses(namespace string) v1beta1.TCPIngressInterface {
 	return &FakeTCPIngresses{c, namespace}
 }
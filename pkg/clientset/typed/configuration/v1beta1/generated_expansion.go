@@ -18,6 +18,16 @@ limitations under the License.
 
 package v1beta1
 
+type IngressClassParametersExpansion interface{}
+
+type KongCACertificateExpansion interface{}
+
+type KongPluginBundleExpansion interface{}
+
+type KongUpstreamPolicyExpansion interface{}
+
+type KongVaultExpansion interface{}
+
 type TCPIngressExpansion interface{}
 
 type UDPIngressExpansion interface{}
@@ -0,0 +1,302 @@
+// Package translationtest runs Kubernetes objects through the same translation
+// and configuration pipeline as the controller, against a mock Kong Admin API,
+// so that the Kong configuration produced by a set of manifests can be
+// verified in unit tests (e.g. to test platform policies in CI) without a
+// Kubernetes cluster or a Kong instance.
+package translationtest
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blang/semver/v4"
+	"github.com/kong/deck/file"
+	"github.com/kong/go-kong/kong"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	knativev1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
+	mcsv1alpha1 "github.com/kong/kubernetes-ingress-controller/v2/internal/apis/multicluster/v1alpha1"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/sendconfig"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+	configurationv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+	configurationv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
+)
+
+// DefaultKongVersion is the version of Kong configuration is generated for
+// when none is configured.
+const DefaultKongVersion = "2.8.0"
+
+// Options configure a translation.
+type Options struct {
+	// IngressClass is the class of the objects to translate. Defaults to the
+	// default class of the controller.
+	IngressClass string
+
+	// KongVersion is the version of Kong the configuration is generated for.
+	// The version is global to a process in the controller, so only the
+	// first version used by a process applies. Defaults to DefaultKongVersion.
+	KongVersion string
+
+	// EnableCombinedServiceRoutes translates Ingresses as with the
+	// CombinedRoutes feature gate.
+	EnableCombinedServiceRoutes bool
+
+	// EnableEndpointSliceTargets generates targets from EndpointSlices as with
+	// the EndpointSliceTargets feature gate.
+	EnableEndpointSliceTargets bool
+
+	// PluginSchemas are the JSON schemas of the configuration of plugins, as
+	// served by the /plugins/schema/<name> endpoint of the Kong Admin API.
+	// The defaults they define are filled in the configuration of the
+	// plugins, the configuration of other plugins is kept as is.
+	PluginSchemas map[string]string
+}
+
+// Result is the outcome of a translation.
+type Result struct {
+	// Config is the declarative configuration sent to Kong.
+	Config *file.Content
+
+	// RawConfig is the body of the request sending the configuration to the
+	// /config endpoint of Kong, including custom entities (e.g. vaults).
+	RawConfig []byte
+
+	// Events are the Events emitted for the objects which couldn't be fully
+	// translated.
+	Events []Event
+}
+
+// Event is an Event emitted by the controller for a Kubernetes object.
+type Event struct {
+	Object  runtime.Object
+	Type    string
+	Reason  string
+	Message string
+}
+
+// Scheme provides a scheme with all the kinds of objects which the controller
+// translates.
+func Scheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(configurationv1.AddToScheme(scheme))
+	utilruntime.Must(configurationv1beta1.AddToScheme(scheme))
+	utilruntime.Must(knativev1alpha1.AddToScheme(scheme))
+	utilruntime.Must(gatewayv1alpha2.AddToScheme(scheme))
+	utilruntime.Must(mcsv1alpha1.AddToScheme(scheme))
+	return scheme
+}
+
+// TranslateManifests translates the objects of YAML (or JSON) manifests, which
+// may contain several documents. Objects which the controller doesn't
+// translate (e.g. Deployments) are ignored.
+func TranslateManifests(ctx context.Context, manifests []byte, opts Options) (*Result, error) {
+	decoder := serializer.NewCodecFactory(Scheme()).UniversalDeserializer()
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(manifests)))
+	var objs []client.Object
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read manifests: %w", err)
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+		obj, gvk, err := decoder.Decode(doc, nil, nil)
+		if err != nil {
+			if runtime.IsNotRegisteredError(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to decode manifest: %w", err)
+		}
+		if !store.IsSupportedKind(*gvk) {
+			continue
+		}
+		clientObj, ok := obj.(client.Object)
+		if !ok {
+			continue
+		}
+		objs = append(objs, clientObj)
+	}
+	return Translate(ctx, objs, opts)
+}
+
+// Translate loads the objects into the cache stores of the controller,
+// translates them into Kong configuration and sends the configuration to a
+// mock Kong Admin API (in DB-less mode), returning the configuration it got.
+func Translate(ctx context.Context, objs []client.Object, opts Options) (*Result, error) {
+	if opts.IngressClass == "" {
+		opts.IngressClass = annotations.DefaultIngressClass
+	}
+	if opts.KongVersion == "" {
+		opts.KongVersion = DefaultKongVersion
+	}
+	version, err := semver.Parse(opts.KongVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Kong version %q: %w", opts.KongVersion, err)
+	}
+	util.SetKongVersion(version)
+
+	admin := newMockAdminAPI(opts)
+	server := httptest.NewServer(admin)
+	defer server.Close()
+	kongClient, err := kong.NewClient(kong.String(server.URL), server.Client())
+	if err != nil {
+		return nil, err
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	recorder := &eventRecorder{}
+	dataplaneClient, err := dataplane.NewKongClient(logger, 10*time.Second, opts.IngressClass, false, false,
+		util.ConfigDumpDiagnostic{},
+		sendconfig.Kong{
+			URL:               server.URL,
+			Client:            kongClient,
+			PluginSchemaStore: util.NewPluginSchemaStore(kongClient),
+			Concurrency:       1,
+		},
+		recorder,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if opts.EnableCombinedServiceRoutes {
+		dataplaneClient.EnableCombinedServiceRoutes()
+	}
+	if opts.EnableEndpointSliceTargets {
+		dataplaneClient.EnableEndpointSliceTargets()
+	}
+
+	for _, obj := range objs {
+		if err := dataplaneClient.UpdateObject(obj); err != nil {
+			return nil, fmt.Errorf("failed to load %s %s: %w",
+				obj.GetObjectKind().GroupVersionKind().Kind, client.ObjectKeyFromObject(obj), err)
+		}
+	}
+	if err := dataplaneClient.Update(ctx); err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		RawConfig: admin.config(),
+		Events:    recorder.events,
+	}
+	if result.RawConfig != nil {
+		result.Config = &file.Content{}
+		if err := json.Unmarshal(result.RawConfig, result.Config); err != nil {
+			return nil, fmt.Errorf("failed to parse the configuration: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// mockAdminAPI serves the parts of the Kong Admin API used to configure a
+// DB-less Kong.
+type mockAdminAPI struct {
+	version       string
+	pluginSchemas map[string]string
+
+	lock       sync.Mutex
+	lastConfig []byte
+}
+
+func newMockAdminAPI(opts Options) *mockAdminAPI {
+	return &mockAdminAPI{
+		version:       opts.KongVersion,
+		pluginSchemas: opts.PluginSchemas,
+	}
+}
+
+func (a *mockAdminAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/":
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"version":       a.version,
+			"configuration": map[string]interface{}{"database": "off"},
+		})
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/plugins/schema/"):
+		schema, ok := a.pluginSchemas[strings.TrimPrefix(r.URL.Path, "/plugins/schema/")]
+		if !ok {
+			schema = `{"fields":[]}`
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(schema))
+	case r.Method == http.MethodPost && r.URL.Path == "/config":
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]interface{}{"message": err.Error()})
+			return
+		}
+		a.lock.Lock()
+		a.lastConfig = body
+		a.lock.Unlock()
+		writeJSON(w, http.StatusCreated, map[string]interface{}{})
+	case r.Method == http.MethodGet && r.URL.Path == "/status":
+		writeJSON(w, http.StatusOK, map[string]interface{}{})
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{"message": "Not found"})
+	}
+}
+
+// config provides the configuration most recently sent to the mock.
+func (a *mockAdminAPI) config() []byte {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return a.lastConfig
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// eventRecorder collects the Events emitted by the controller.
+type eventRecorder struct {
+	lock   sync.Mutex
+	events []Event
+}
+
+func (r *eventRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.events = append(r.events, Event{Object: object, Type: eventtype, Reason: reason, Message: message})
+}
+
+func (r *eventRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *eventRecorder) AnnotatedEventf(
+	object runtime.Object,
+	_ map[string]string,
+	eventtype, reason, messageFmt string,
+	args ...interface{},
+) {
+	r.Eventf(object, eventtype, reason, messageFmt, args...)
+}
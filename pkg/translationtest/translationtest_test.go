@@ -0,0 +1,129 @@
+package translationtest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const manifests = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: echo
+  namespace: default
+spec:
+  selector:
+    matchLabels:
+      app: echo
+  template:
+    metadata:
+      labels:
+        app: echo
+    spec:
+      containers:
+      - name: echo
+        image: kong/go-echo
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: echo
+  namespace: default
+  annotations:
+    konghq.com/plugins: rate-limit
+spec:
+  ports:
+  - port: 80
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: echo
+  namespace: default
+  annotations:
+    kubernetes.io/ingress.class: kong
+spec:
+  rules:
+  - http:
+      paths:
+      - path: /echo
+        pathType: Prefix
+        backend:
+          service:
+            name: echo
+            port:
+              number: 80
+---
+apiVersion: configuration.konghq.com/v1beta1
+kind: TCPIngress
+metadata:
+  name: echo
+  namespace: default
+  annotations:
+    kubernetes.io/ingress.class: kong
+spec:
+  rules:
+  - port: 0
+    backend:
+      serviceName: echo
+      servicePort: 80
+---
+apiVersion: configuration.konghq.com/v1
+kind: KongPlugin
+metadata:
+  name: rate-limit
+  namespace: default
+plugin: rate-limiting
+config:
+  minute: 5
+---
+apiVersion: configuration.konghq.com/v1beta1
+kind: KongVault
+metadata:
+  name: secrets
+  annotations:
+    kubernetes.io/ingress.class: kong
+spec:
+  backend: env
+  prefix: secrets
+`
+
+func TestTranslateManifests(t *testing.T) {
+	result, err := TranslateManifests(context.Background(), []byte(manifests), Options{
+		PluginSchemas: map[string]string{
+			"rate-limiting": `{"fields":[{"minute":{"type":"number"}},{"policy":{"type":"string","default":"local"}}]}`,
+		},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result.Config)
+
+	require.Len(t, result.Config.Services, 1)
+	service := result.Config.Services[0]
+	assert.Equal(t, "default.echo.pnum-80", *service.Name)
+	require.Len(t, service.Routes, 1)
+	assert.Equal(t, "default.echo.00", *service.Routes[0].Name)
+
+	require.Len(t, result.Config.Plugins, 1)
+	plugin := result.Config.Plugins[0]
+	assert.Equal(t, "rate-limiting", *plugin.Name)
+	assert.Equal(t, "default.echo.pnum-80", *plugin.Service.ID)
+	assert.EqualValues(t, 5, plugin.Config["minute"])
+	assert.Equal(t, "local", plugin.Config["policy"], "defaults of the plugin schema are filled")
+
+	assert.Contains(t, string(result.RawConfig), `"vaults":[{"name":"env","prefix":"secrets"}]`)
+
+	require.Len(t, result.Events, 1)
+	assert.Equal(t, corev1.EventTypeWarning, result.Events[0].Type)
+	assert.Equal(t, "invalid TCPIngress: invalid port: 0", result.Events[0].Message)
+}
+
+func TestTranslateManifestsOtherIngressClass(t *testing.T) {
+	result, err := TranslateManifests(context.Background(), []byte(manifests), Options{IngressClass: "other"})
+	require.NoError(t, err)
+	require.NotNil(t, result.Config)
+	assert.Empty(t, result.Config.Services, "objects of other classes aren't translated")
+}